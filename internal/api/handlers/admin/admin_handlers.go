@@ -4,7 +4,6 @@ import (
 	"github.com/rail-service/rail_service/internal/api/handlers/common"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -25,9 +24,17 @@ import (
 
 // AdminHandlers handles admin-related operations
 type AdminHandlers struct {
-	db     *sql.DB
-	cfg    *config.Config
-	logger *zap.Logger
+	db      *sql.DB
+	cfg     *config.Config
+	logger  *zap.Logger
+	baskets BasketCatalog
+}
+
+// BasketCatalog manages curated baskets and their versioned compositions
+type BasketCatalog interface {
+	CreateBasket(ctx context.Context, adminID uuid.UUID, req *entities.CuratedBasketRequest) (*entities.Basket, error)
+	UpdateBasket(ctx context.Context, adminID, basketID uuid.UUID, req *entities.CuratedBasketRequest) (*entities.BasketVersionChange, error)
+	GetVersions(ctx context.Context, basketID uuid.UUID) ([]*entities.BasketVersion, error)
 }
 
 // NewAdminHandlers creates a new AdminHandlers instance
@@ -39,6 +46,11 @@ func NewAdminHandlers(db *sql.DB, cfg *config.Config, logger *zap.Logger) *Admin
 	}
 }
 
+// SetBasketCatalog sets the catalog curated baskets are saved through
+func (h *AdminHandlers) SetBasketCatalog(baskets BasketCatalog) {
+	h.baskets = baskets
+}
+
 // CreateAdmin handles POST /api/v1/admin/create
 func (h *AdminHandlers) CreateAdmin(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
@@ -332,7 +344,14 @@ func (h *AdminHandlers) GetSystemAnalytics(c *gin.Context) {
 
 // CreateCuratedBasket handles POST /api/v1/admin/baskets
 func (h *AdminHandlers) CreateCuratedBasket(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.SendUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	// Each symbol is checked with the broker, so allow more time than other admin writes
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
 	var req entities.CuratedBasketRequest
@@ -346,8 +365,17 @@ func (h *AdminHandlers) CreateCuratedBasket(c *gin.Context) {
 		return
 	}
 
-	basket, err := h.createBasket(ctx, &req)
+	if h.baskets == nil {
+		common.SendInternalError(c, common.ErrCodeCreateFailed, "Curated baskets are not available")
+		return
+	}
+
+	basket, err := h.baskets.CreateBasket(ctx, adminID, &req)
 	if err != nil {
+		if isCompositionError(err) {
+			common.SendBadRequest(c, common.ErrCodeValidationError, err.Error())
+			return
+		}
 		h.logger.Error("failed to create basket", zap.Error(err))
 		common.SendInternalError(c, common.ErrCodeCreateFailed, "Failed to create curated basket")
 		return
@@ -357,7 +385,14 @@ func (h *AdminHandlers) CreateCuratedBasket(c *gin.Context) {
 }
 
 // UpdateCuratedBasket handles PUT /api/v1/admin/baskets/:id
+// A changed composition becomes a new version, applied at effectiveAt or immediately.
 func (h *AdminHandlers) UpdateCuratedBasket(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.SendUnauthorized(c, "User not authenticated")
+		return
+	}
+
 	basketID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		common.SendBadRequest(c, common.ErrCodeInvalidID, "Invalid basket ID")
@@ -375,21 +410,56 @@ func (h *AdminHandlers) UpdateCuratedBasket(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	if h.baskets == nil {
+		common.SendInternalError(c, common.ErrCodeUpdateFailed, "Curated baskets are not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	basket, err := h.updateBasket(ctx, basketID, &req)
+	change, err := h.baskets.UpdateBasket(ctx, adminID, basketID, &req)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) || err.Error() == "basket not found" {
 			common.SendNotFound(c, common.ErrCodeNotFound, "Basket not found")
 			return
 		}
+		if isCompositionError(err) {
+			common.SendBadRequest(c, common.ErrCodeValidationError, err.Error())
+			return
+		}
 		h.logger.Error("failed to update basket", zap.Error(err), zap.String("basket_id", basketID.String()))
 		common.SendInternalError(c, common.ErrCodeUpdateFailed, "Failed to update curated basket")
 		return
 	}
 
-	common.SendSuccess(c, basket)
+	common.SendSuccess(c, change)
+}
+
+// ListCuratedBasketVersions handles GET /api/v1/admin/baskets/:id/versions
+func (h *AdminHandlers) ListCuratedBasketVersions(c *gin.Context) {
+	basketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.SendBadRequest(c, common.ErrCodeInvalidID, "Invalid basket ID")
+		return
+	}
+
+	if h.baskets == nil {
+		common.SendInternalError(c, common.ErrCodeInternalError, "Curated baskets are not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	versions, err := h.baskets.GetVersions(ctx, basketID)
+	if err != nil {
+		h.logger.Error("failed to list basket versions", zap.Error(err), zap.String("basket_id", basketID.String()))
+		common.SendInternalError(c, common.ErrCodeInternalError, "Failed to list basket versions")
+		return
+	}
+
+	common.SendSuccess(c, gin.H{"versions": versions})
 }
 
 // Helper methods
@@ -776,99 +846,6 @@ func (h *AdminHandlers) getSystemAnalytics(ctx context.Context) (*entities.Syste
 	return &analytics, nil
 }
 
-func (h *AdminHandlers) createBasket(ctx context.Context, req *entities.CuratedBasketRequest) (*entities.Basket, error) {
-	payload, err := json.Marshal(req.Composition)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal composition: %w", err)
-	}
-
-	now := time.Now().UTC()
-	basketID := uuid.New()
-
-	query := `
-		INSERT INTO baskets (id, name, description, risk_level, composition_json, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, name, description, risk_level, composition_json, created_at, updated_at`
-
-	var basket entities.Basket
-	var compositionRaw []byte
-
-	err = h.db.QueryRowContext(ctx, query,
-		basketID,
-		req.Name,
-		req.Description,
-		req.RiskLevel,
-		payload,
-		now,
-		now,
-	).Scan(
-		&basket.ID,
-		&basket.Name,
-		&basket.Description,
-		&basket.RiskLevel,
-		&compositionRaw,
-		&basket.CreatedAt,
-		&basket.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal(compositionRaw, &basket.Composition); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal composition: %w", err)
-	}
-
-	return &basket, nil
-}
-
-func (h *AdminHandlers) updateBasket(ctx context.Context, basketID uuid.UUID, req *entities.CuratedBasketRequest) (*entities.Basket, error) {
-	payload, err := json.Marshal(req.Composition)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal composition: %w", err)
-	}
-
-	query := `
-		UPDATE baskets
-		SET name = $1,
-		    description = $2,
-		    risk_level = $3,
-		    composition_json = $4,
-		    updated_at = $5
-		WHERE id = $6
-		RETURNING id, name, description, risk_level, composition_json, created_at, updated_at`
-
-	var basket entities.Basket
-	var compositionRaw []byte
-
-	err = h.db.QueryRowContext(ctx, query,
-		req.Name,
-		req.Description,
-		req.RiskLevel,
-		payload,
-		time.Now().UTC(),
-		basketID,
-	).Scan(
-		&basket.ID,
-		&basket.Name,
-		&basket.Description,
-		&basket.RiskLevel,
-		&compositionRaw,
-		&basket.CreatedAt,
-		&basket.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal(compositionRaw, &basket.Composition); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal composition: %w", err)
-	}
-
-	return &basket, nil
-}
-
 // validateBasketRequest validates basket creation/update requests
 func validateBasketRequest(req *entities.CuratedBasketRequest) error {
 	if len(req.Composition) == 0 {
@@ -900,6 +877,12 @@ func validateBasketRequest(req *entities.CuratedBasketRequest) error {
 	return nil
 }
 
+// isCompositionError reports whether the basket catalog rejected the composition itself
+func isCompositionError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "asset ") || strings.HasPrefix(msg, "duplicate symbol")
+}
+
 // AdminWalletHandlers handles admin wallet operations
 type AdminWalletHandlers struct {
 	db  *sql.DB
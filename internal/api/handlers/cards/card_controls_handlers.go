@@ -0,0 +1,121 @@
+package cards
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+)
+
+// GetCardControls retrieves the spending controls for a card
+// GET /api/v1/cards/:id/controls
+func (h *CardHandlers) GetCardControls(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid card ID"})
+		return
+	}
+
+	controls, err := h.service.GetCardControls(c.Request.Context(), userID, cardID)
+	if err != nil {
+		if errors.Is(err, card.ErrCardNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
+			return
+		}
+		h.logger.Error("Failed to get card controls", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to retrieve card controls"})
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// UpdateCardControls updates limits, merchant restrictions and channel toggles for a card
+// PATCH /api/v1/cards/:id/controls
+func (h *CardHandlers) UpdateCardControls(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid card ID"})
+		return
+	}
+
+	var req entities.UpdateCardControlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	controls, err := h.service.UpdateCardControls(c.Request.Context(), userID, cardID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrCardNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
+		case errors.Is(err, card.ErrCardCancelled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_CANCELLED", "message": "Cannot change controls on a cancelled card"})
+		case errors.Is(err, card.ErrSingleUseCard):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SINGLE_USE_CARD", "message": err.Error()})
+		case errors.Is(err, card.ErrInvalidCardControls):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_CONTROLS", "message": err.Error()})
+		default:
+			h.logger.Error("Failed to update card controls", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to update card controls"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// CreateSingleUseCard issues a disposable virtual card
+// POST /api/v1/cards/single-use
+func (h *CardHandlers) CreateSingleUseCard(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	var req entities.CreateSingleUseCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	cardData, err := h.service.CreateSingleUseCard(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrInvalidCardControls):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_CONTROLS", "message": err.Error()})
+		case errors.Is(err, card.ErrCustomerNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CUSTOMER_NOT_FOUND", "message": "Complete onboarding before creating a card"})
+		case errors.Is(err, card.ErrWalletNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "WALLET_NOT_FOUND", "message": "Wallet required for card creation"})
+		default:
+			h.logger.Error("Failed to create single-use card", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to create card"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, entities.CreateCardResponse{
+		Card:    cardData,
+		Message: "Single-use card created. It will be cancelled after its first purchase.",
+	})
+}
@@ -165,6 +165,8 @@ func (h *CardHandlers) FreezeCard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
 		case card.ErrCardCancelled:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_CANCELLED", "message": "Cannot freeze a cancelled card"})
+		case card.ErrCardNotActivated:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_NOT_ACTIVATED", "message": "Card must be activated before it can be frozen"})
 		default:
 			h.logger.Error("Failed to freeze card", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to freeze card"})
@@ -207,6 +209,8 @@ func (h *CardHandlers) UnfreezeCard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
 		case card.ErrCardCancelled:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_CANCELLED", "message": "Cannot unfreeze a cancelled card"})
+		case card.ErrCardNotActivated:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_NOT_ACTIVATED", "message": "Card must be activated before it can be unfrozen"})
 		default:
 			h.logger.Error("Failed to unfreeze card", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to unfreeze card"})
@@ -295,6 +299,44 @@ func (h *CardHandlers) GetAllTransactions(c *gin.Context) {
 	})
 }
 
+// RecategorizeTransaction changes a transaction's category and optionally learns it for the merchant
+// PATCH /api/v1/cards/transactions/:txId/category
+func (h *CardHandlers) RecategorizeTransaction(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	txID, err := uuid.Parse(c.Param("txId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid transaction ID"})
+		return
+	}
+
+	var req entities.RecategorizeTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	tx, err := h.service.RecategorizeTransaction(c.Request.Context(), userID, txID, &req)
+	if err != nil {
+		switch err {
+		case card.ErrInvalidCategory:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_CATEGORY", "message": "Unknown spending category"})
+		case card.ErrTransactionNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Transaction not found"})
+		default:
+			h.logger.Error("Failed to recategorize transaction", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to update transaction category"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tx)
+}
+
 func derefBridgeTransactions(txs []*entities.BridgeCardTransaction) []entities.BridgeCardTransaction {
 	result := make([]entities.BridgeCardTransaction, len(txs))
 	for i, tx := range txs {
@@ -0,0 +1,214 @@
+package cards
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+)
+
+// OpenDispute opens a dispute on a card transaction
+// POST /api/v1/cards/disputes
+func (h *CardHandlers) OpenDispute(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	var req entities.OpenCardDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	result, err := h.service.OpenDispute(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.writeDisputeError(c, err, "Failed to open dispute")
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// ListDisputes lists the user's card disputes
+// GET /api/v1/cards/disputes
+func (h *CardHandlers) ListDisputes(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 100 {
+		limit = 100
+	}
+
+	disputes, err := h.service.ListDisputes(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list disputes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to retrieve disputes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"disputes": disputes, "total": len(disputes), "has_more": len(disputes) == limit})
+}
+
+// GetDispute retrieves a dispute with its evidence
+// GET /api/v1/cards/disputes/:disputeId
+func (h *CardHandlers) GetDispute(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	disputeID, err := uuid.Parse(c.Param("disputeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid dispute ID"})
+		return
+	}
+
+	result, err := h.service.GetDispute(c.Request.Context(), userID, disputeID)
+	if err != nil {
+		h.writeDisputeError(c, err, "Failed to retrieve dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AddDisputeEvidence attaches evidence to an open dispute
+// POST /api/v1/cards/disputes/:disputeId/evidence
+func (h *CardHandlers) AddDisputeEvidence(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	disputeID, err := uuid.Parse(c.Param("disputeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid dispute ID"})
+		return
+	}
+
+	var req entities.AddDisputeEvidenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	result, err := h.service.AddDisputeEvidence(c.Request.Context(), userID, disputeID, &req)
+	if err != nil {
+		h.writeDisputeError(c, err, "Failed to add dispute evidence")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AdminListDisputes lists disputes by status for operators
+// GET /api/v1/admin/cards/disputes?status=submitted
+func (h *CardHandlers) AdminListDisputes(c *gin.Context) {
+	status := entities.CardDisputeStatus(c.DefaultQuery("status", string(entities.CardDisputeSubmitted)))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 100 {
+		limit = 100
+	}
+
+	disputes, err := h.service.ListDisputesByStatus(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list disputes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to retrieve disputes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"disputes": disputes, "total": len(disputes), "has_more": len(disputes) == limit})
+}
+
+// AdminReviewDispute moves a dispute into review
+// POST /api/v1/admin/cards/disputes/:disputeId/review
+func (h *CardHandlers) AdminReviewDispute(c *gin.Context) {
+	adminID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	disputeID, err := uuid.Parse(c.Param("disputeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid dispute ID"})
+		return
+	}
+
+	dispute, err := h.service.MarkDisputeUnderReview(c.Request.Context(), disputeID, adminID)
+	if err != nil {
+		h.writeDisputeError(c, err, "Failed to update dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// AdminResolveDispute records the outcome of a dispute
+// POST /api/v1/admin/cards/disputes/:disputeId/resolve
+func (h *CardHandlers) AdminResolveDispute(c *gin.Context) {
+	adminID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	disputeID, err := uuid.Parse(c.Param("disputeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid dispute ID"})
+		return
+	}
+
+	var req entities.ResolveCardDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	dispute, err := h.service.ResolveDispute(c.Request.Context(), disputeID, adminID, &req)
+	if err != nil {
+		h.writeDisputeError(c, err, "Failed to resolve dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// writeDisputeError maps dispute service errors to HTTP responses
+func (h *CardHandlers) writeDisputeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, card.ErrTransactionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Transaction not found"})
+	case errors.Is(err, card.ErrDisputeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Dispute not found"})
+	case errors.Is(err, card.ErrTransactionNotDisputable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "NOT_DISPUTABLE", "message": "Only settled card transactions can be disputed"})
+	case errors.Is(err, card.ErrDisputeWindowExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "DISPUTE_WINDOW_EXPIRED", "message": "This transaction is too old to dispute"})
+	case errors.Is(err, card.ErrDisputeExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "DISPUTE_EXISTS", "message": "This transaction has already been disputed"})
+	case errors.Is(err, card.ErrDisputeResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "DISPUTE_RESOLVED", "message": "Dispute is already resolved"})
+	case errors.Is(err, card.ErrInvalidDisputeTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "INVALID_TRANSITION", "message": err.Error()})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": message})
+	}
+}
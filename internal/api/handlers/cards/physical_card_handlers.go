@@ -0,0 +1,177 @@
+package cards
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+)
+
+// OrderPhysicalCard orders a physical card for the user
+// POST /api/v1/cards/physical
+func (h *CardHandlers) OrderPhysicalCard(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	var req entities.OrderPhysicalCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	result, err := h.service.OrderPhysicalCard(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrInvalidShippingAddress):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ADDRESS", "message": err.Error()})
+		case errors.Is(err, card.ErrPhysicalCardExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "CARD_EXISTS", "message": "User already has a physical card"})
+		case errors.Is(err, card.ErrCustomerNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CUSTOMER_NOT_FOUND", "message": "Complete onboarding before ordering a card"})
+		case errors.Is(err, card.ErrWalletNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "WALLET_NOT_FOUND", "message": "Wallet required for card creation"})
+		default:
+			h.logger.Error("Failed to order physical card", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to order physical card"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetCardShipment retrieves shipment tracking for a physical card
+// GET /api/v1/cards/:id/shipment
+func (h *CardHandlers) GetCardShipment(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid card ID"})
+		return
+	}
+
+	shipment, err := h.service.GetCardShipment(c.Request.Context(), userID, cardID)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrCardNotFound), errors.Is(err, card.ErrShipmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Shipment not found"})
+		case errors.Is(err, card.ErrNotPhysicalCard):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "NOT_PHYSICAL_CARD", "message": "Only physical cards are shipped"})
+		default:
+			h.logger.Error("Failed to get card shipment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to retrieve shipment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// ActivateCard activates a received physical card
+// POST /api/v1/cards/:id/activate
+func (h *CardHandlers) ActivateCard(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid card ID"})
+		return
+	}
+
+	var req entities.ActivateCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	cardData, err := h.service.ActivateCard(c.Request.Context(), userID, cardID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrCardNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
+		case errors.Is(err, card.ErrNotPhysicalCard):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "NOT_PHYSICAL_CARD", "message": "Only physical cards require activation"})
+		case errors.Is(err, card.ErrCardAlreadyActive):
+			c.JSON(http.StatusConflict, gin.H{"error": "CARD_ALREADY_ACTIVE", "message": "Card is already active"})
+		case errors.Is(err, card.ErrCardCancelled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_CANCELLED", "message": "Cannot activate a cancelled card"})
+		case errors.Is(err, card.ErrCardNotShipped):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_NOT_SHIPPED", "message": "Card has not shipped yet"})
+		case errors.Is(err, card.ErrActivationVerificationFailed):
+			c.JSON(http.StatusForbidden, gin.H{"error": "VERIFICATION_FAILED", "message": "Card details or passcode are incorrect"})
+		default:
+			h.logger.Error("Failed to activate card", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to activate card"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Card activated successfully",
+		"card": entities.CardDetailsResponse{
+			ID:       cardData.ID,
+			Type:     cardData.Type,
+			Status:   cardData.Status,
+			Last4:    cardData.Last4,
+			Expiry:   cardData.Expiry,
+			Currency: cardData.Currency,
+		},
+	})
+}
+
+// ReplaceCard cancels a lost, stolen or damaged card and issues a replacement
+// POST /api/v1/cards/:id/replace
+func (h *CardHandlers) ReplaceCard(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "Invalid user ID"})
+		return
+	}
+
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ID", "message": "Invalid card ID"})
+		return
+	}
+
+	var req entities.ReplaceCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	result, err := h.service.ReplaceCard(c.Request.Context(), userID, cardID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, card.ErrCardNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "NOT_FOUND", "message": "Card not found"})
+		case errors.Is(err, card.ErrCardCancelled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CARD_CANCELLED", "message": "Card is already cancelled"})
+		case errors.Is(err, card.ErrInvalidShippingAddress):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "INVALID_ADDRESS", "message": err.Error()})
+		default:
+			h.logger.Error("Failed to replace card", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to replace card"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
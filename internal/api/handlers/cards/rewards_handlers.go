@@ -0,0 +1,188 @@
+package cards
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/rewards"
+)
+
+// RewardsHandlers handles cashback rewards API endpoints
+type RewardsHandlers struct {
+	service *rewards.Service
+	logger  *zap.Logger
+}
+
+// NewRewardsHandlers creates new rewards handlers
+func NewRewardsHandlers(service *rewards.Service, logger *zap.Logger) *RewardsHandlers {
+	return &RewardsHandlers{service: service, logger: logger}
+}
+
+// GetSummary handles GET /api/v1/rewards/summary
+func (h *RewardsHandlers) GetSummary(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get cashback summary", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetRewards handles GET /api/v1/rewards/cashback
+func (h *RewardsHandlers) GetRewards(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	items, err := h.service.GetRewards(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get cashback rewards", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get rewards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rewards": items, "limit": limit, "offset": offset})
+}
+
+// GetOffers handles GET /api/v1/rewards/offers
+func (h *RewardsHandlers) GetOffers(c *gin.Context) {
+	offers, err := h.service.ListOffers(c.Request.Context(), true)
+	if err != nil {
+		h.logger.Error("Failed to list reward offers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offers": offers})
+}
+
+// AdminListOffers handles GET /api/v1/admin/rewards/offers
+func (h *RewardsHandlers) AdminListOffers(c *gin.Context) {
+	offers, err := h.service.ListOffers(c.Request.Context(), c.Query("active") == "true")
+	if err != nil {
+		h.logger.Error("Failed to list reward offers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offers": offers})
+}
+
+// AdminCreateOffer handles POST /api/v1/admin/rewards/offers
+func (h *RewardsHandlers) AdminCreateOffer(c *gin.Context) {
+	var req entities.CreateRewardOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	offer, err := h.service.CreateOffer(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, rewards.ErrInvalidOffer) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to create reward offer", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create offer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
+// AdminUpdateOffer handles PATCH /api/v1/admin/rewards/offers/:id
+func (h *RewardsHandlers) AdminUpdateOffer(c *gin.Context) {
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offer id"})
+		return
+	}
+
+	var req entities.UpdateRewardOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	offer, err := h.service.UpdateOffer(c.Request.Context(), offerID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, rewards.ErrOfferNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+		case errors.Is(err, rewards.ErrInvalidOffer):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to update reward offer", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update offer"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// AdminGetFunding handles GET /api/v1/admin/rewards/funding
+func (h *RewardsHandlers) AdminGetFunding(c *gin.Context) {
+	balance, err := h.service.GetFundingBalance(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get rewards funding balance", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get funding balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"balance": balance})
+}
+
+// AdminFundPool handles POST /api/v1/admin/rewards/funding, moving treasury cash into the
+// rewards funding pool
+func (h *RewardsHandlers) AdminFundPool(c *gin.Context) {
+	var req struct {
+		Amount    decimal.Decimal `json:"amount" binding:"required"`
+		Reference string          `json:"reference" binding:"required"` // Treasury transfer ID, keys retries
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	ledgerTx, err := h.service.FundPool(c.Request.Context(), req.Amount, req.Reference)
+	if err != nil {
+		if errors.Is(err, rewards.ErrInvalidFundingAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to fund rewards pool", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fund rewards pool"})
+		return
+	}
+
+	balance, err := h.service.GetFundingBalance(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get rewards funding balance", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get funding balance"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"ledger_transaction_id": ledgerTx.ID, "balance": balance})
+}
@@ -50,6 +50,12 @@ type UpdateSettingsRequest struct {
 	AutoInvestEnabled  *bool   `json:"auto_invest_enabled,omitempty"`
 	AutoInvestBasketID *string `json:"auto_invest_basket_id,omitempty"`
 	AutoInvestSymbol   *string `json:"auto_invest_symbol,omitempty"`
+	WeeklyCap          *string `json:"weekly_cap,omitempty"`         // "0" removes the cap
+	MonthlyCap         *string `json:"monthly_cap,omitempty"`        // "0" removes the cap
+	BoostMultiplier    *string `json:"boost_multiplier,omitempty"`   // Stacks on the multiplier, e.g. "2.0"
+	BoostDays          *int    `json:"boost_days,omitempty"`         // Boost duration; 0 ends the boost
+	LowBalanceFloor    *string `json:"low_balance_floor,omitempty"`  // Multipliers drop to 1x below this spend balance
+	SkipBelowBalance   *string `json:"skip_below_balance,omitempty"` // Skip round-ups that would leave less than this
 }
 
 // UpdateSettings handles PUT /api/v1/roundups/settings
@@ -95,6 +101,28 @@ func (h *RoundupHandlers) UpdateSettings(c *gin.Context) {
 			svcReq.AutoInvestBasketID = &id
 		}
 	}
+	svcReq.BoostDays = req.BoostDays
+	for _, field := range []struct {
+		name  string
+		value *string
+		dest  **decimal.Decimal
+	}{
+		{"weekly_cap", req.WeeklyCap, &svcReq.WeeklyCap},
+		{"monthly_cap", req.MonthlyCap, &svcReq.MonthlyCap},
+		{"boost_multiplier", req.BoostMultiplier, &svcReq.BoostMultiplier},
+		{"low_balance_floor", req.LowBalanceFloor, &svcReq.LowBalanceFloor},
+		{"skip_below_balance", req.SkipBelowBalance, &svcReq.SkipBelowBalance},
+	} {
+		if field.value == nil {
+			continue
+		}
+		d, err := decimal.NewFromString(*field.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + field.name + " format"})
+			return
+		}
+		*field.dest = &d
+	}
 
 	settings, err := h.service.UpdateSettings(c.Request.Context(), userID, svcReq)
 	if err != nil {
@@ -228,14 +256,12 @@ func (h *RoundupHandlers) CalculatePreview(c *gin.Context) {
 		return
 	}
 
-	settings, err := h.service.GetSettings(c.Request.Context(), userID)
-	rounded, spareChange, multiplied := entities.CalculateRoundup(amount, settings.Multiplier)
-
-	c.JSON(http.StatusOK, gin.H{
-		"original_amount":   amount.String(),
-		"rounded_amount":    rounded.String(),
-		"spare_change":      spareChange.String(),
-		"multiplied_amount": multiplied.String(),
-		"multiplier":        settings.Multiplier.String(),
-	})
+	preview, err := h.service.PreviewRoundup(c.Request.Context(), userID, amount)
+	if err != nil {
+		h.logger.Error("Failed to preview round-up", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to preview round-up"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
 }
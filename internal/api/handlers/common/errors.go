@@ -74,6 +74,7 @@ const (
 	// Funding & Wallet errors
 	ErrCodeInsufficientFunds   = "INSUFFICIENT_FUNDS"
 	ErrCodeInsufficientPosition = "INSUFFICIENT_POSITION"
+	ErrCodeRiskAcknowledgmentRequired = "RISK_ACKNOWLEDGMENT_REQUIRED"
 	ErrCodeWalletCreationFailed = "WALLET_CREATION_FAILED"
 	ErrCodeDepositFailed       = "DEPOSIT_FAILED"
 	ErrCodeWithdrawalFailed    = "WITHDRAWAL_FAILED"
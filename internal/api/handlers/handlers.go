@@ -44,6 +44,10 @@ type (
 	MarketHandlers            = investing.MarketHandlers
 	AIChatHandlers            = investing.AIChatHandlers
 	AICfoHandler              = investing.AICfoHandler
+	StrategyHandlers          = investing.StrategyHandlers
+	RiskProfileHandlers       = investing.RiskProfileHandlers
+	TaxHandlers               = investing.TaxHandlers
+	DividendHandlers          = investing.DividendHandlers
 
 	// Trading
 	CopyTradingHandlers         = trading.CopyTradingHandlers
@@ -53,6 +57,7 @@ type (
 	// Cards
 	CardHandlers    = cards.CardHandlers
 	RoundupHandlers = cards.RoundupHandlers
+	RewardsHandlers = cards.RewardsHandlers
 
 	// Admin
 	AdminHandlers            = admin.AdminHandlers
@@ -117,6 +122,10 @@ var (
 	NewMarketHandlers            = investing.NewMarketHandlers
 	NewAIChatHandlers            = investing.NewAIChatHandlers
 	NewAICfoHandler              = investing.NewAICfoHandler
+	NewStrategyHandlers          = investing.NewStrategyHandlers
+	NewRiskProfileHandlers       = investing.NewRiskProfileHandlers
+	NewTaxHandlers               = investing.NewTaxHandlers
+	NewDividendHandlers          = investing.NewDividendHandlers
 )
 
 // Trading constructors
@@ -130,6 +139,7 @@ var (
 var (
 	NewCardHandlers    = cards.NewCardHandlers
 	NewRoundupHandlers = cards.NewRoundupHandlers
+	NewRewardsHandlers = cards.NewRewardsHandlers
 )
 
 // Admin constructors
@@ -144,6 +154,7 @@ var (
 var (
 	NewWebhookHandlers        = webhooks.NewWebhookHandlers
 	NewBridgeWebhookHandler   = webhooks.NewBridgeWebhookHandler
+	NewBridgeWebhookService   = webhooks.NewBridgeWebhookService
 	NewCircleWebhookHandler   = webhooks.NewCircleWebhookHandler
 	NewAlpacaWebhookHandlers  = webhooks.NewAlpacaWebhookHandlers
 	NewBridgeKYCHandlers      = webhooks.NewBridgeKYCHandlers
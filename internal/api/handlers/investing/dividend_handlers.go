@@ -0,0 +1,107 @@
+package investing
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/dividend"
+	"github.com/rail-service/rail_service/pkg/logger"
+)
+
+// DividendHandlers handles dividend history and reinvestment settings
+type DividendHandlers struct {
+	service *dividend.Service
+	logger  *logger.Logger
+}
+
+// NewDividendHandlers creates a new dividend handlers instance
+func NewDividendHandlers(service *dividend.Service, logger *logger.Logger) *DividendHandlers {
+	return &DividendHandlers{service: service, logger: logger}
+}
+
+// GetDividends returns the user's dividends and withholding for a tax year, totalled by asset
+// GET /api/v1/dividends?year=2025
+func (h *DividendHandlers) GetDividends(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	year := time.Now().Year()
+	if y := c.Query("year"); y != "" {
+		if year, err = strconv.Atoi(y); err != nil {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+	}
+
+	report, err := h.service.GetDividendReport(c.Request.Context(), userID, year)
+	if err != nil {
+		if err.Error() == "invalid tax year" {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+		h.logger.Error("Failed to get dividends", "error", err)
+		common.RespondInternalError(c, "Failed to get dividends")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetSettings returns what happens to the user's dividends
+// GET /api/v1/dividends/settings
+func (h *DividendHandlers) GetSettings(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get dividend settings", "error", err)
+		common.RespondInternalError(c, "Failed to get dividend settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings keeps future dividends as cash or reinvests them into the paying asset or the
+// user's strategy allocation
+// PUT /api/v1/dividends/settings
+func (h *DividendHandlers) UpdateSettings(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		ReinvestMode entities.DividendReinvestMode `json:"reinvest_mode" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), userID, req.ReinvestMode)
+	if err != nil {
+		if err.Error() == "invalid reinvest mode" {
+			common.RespondBadRequest(c, "Reinvest mode must be one of off, same_asset or strategy")
+			return
+		}
+		h.logger.Error("Failed to update dividend settings", "error", err)
+		common.RespondInternalError(c, "Failed to update dividend settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -185,6 +185,12 @@ func (h *InvestingHandlers) handleOrderError(c *gin.Context, err error, userUUID
 		common.SendForbidden(c, common.ErrCodeInsufficientFunds)
 	case investing.ErrInsufficientPosition:
 		common.SendBadRequest(c, common.ErrCodeInsufficientPosition, "Insufficient position for sell order")
+	case entities.ErrRiskAcknowledgmentRequired:
+		common.SendConflict(c, common.ErrCodeRiskAcknowledgmentRequired, "This basket is riskier than your risk profile; resubmit with acknowledgeRisk to proceed")
+	case investing.ErrBrokerageAccountNotFound:
+		common.SendBadRequest(c, "BROKERAGE_ACCOUNT_REQUIRED", "Open a brokerage account before investing")
+	case investing.ErrBasketOrderFailed:
+		common.SendInternalError(c, "ORDER_FAILED", "Basket orders could not be placed; reserved funds were released")
 	default:
 		h.logger.Error("Failed to create order",
 			"error", err,
@@ -0,0 +1,209 @@
+package investing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/riskprofile"
+	"github.com/rail-service/rail_service/pkg/logger"
+)
+
+// RiskProfileHandlers handles the risk questionnaire, users' risk profiles and risk acknowledgments
+type RiskProfileHandlers struct {
+	service *riskprofile.Service
+	logger  *logger.Logger
+}
+
+// NewRiskProfileHandlers creates a new risk profile handlers instance
+func NewRiskProfileHandlers(service *riskprofile.Service, logger *logger.Logger) *RiskProfileHandlers {
+	return &RiskProfileHandlers{service: service, logger: logger}
+}
+
+// GetQuestionnaire returns the active risk questionnaire
+// GET /api/v1/risk-profile/questionnaire
+func (h *RiskProfileHandlers) GetQuestionnaire(c *gin.Context) {
+	questionnaire, err := h.service.GetActiveQuestionnaire(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get risk questionnaire", "error", err)
+		if err.Error() == "risk questionnaire not configured" {
+			common.RespondNotFound(c, "Risk questionnaire not available")
+			return
+		}
+		common.RespondInternalError(c, "Failed to get risk questionnaire")
+		return
+	}
+
+	c.JSON(http.StatusOK, questionnaire)
+}
+
+// SubmitAssessment scores the user's questionnaire answers
+// POST /api/v1/risk-profile/assessments
+func (h *RiskProfileHandlers) SubmitAssessment(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.SubmitRiskAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	assessment, err := h.service.SubmitAssessment(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error("Failed to submit risk assessment", "error", err)
+		switch {
+		case err.Error() == "risk questionnaire not configured":
+			common.RespondNotFound(c, "Risk questionnaire not available")
+		case strings.HasPrefix(err.Error(), "missing answer"),
+			strings.HasPrefix(err.Error(), "invalid answer"),
+			err.Error() == "answers include unknown questions":
+			common.RespondError(c, http.StatusBadRequest, "INVALID_ANSWERS", err.Error(), nil)
+		default:
+			common.RespondInternalError(c, "Failed to submit risk assessment")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, assessment)
+}
+
+// GetMyProfile returns the user's current risk profile and whether it needs re-assessment
+// GET /api/v1/risk-profile
+func (h *RiskProfileHandlers) GetMyProfile(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+	h.respondProfile(c, userID)
+}
+
+// GetUserProfile returns a user's current risk profile (admin only)
+// GET /api/v1/admin/risk-profile/users/:id
+func (h *RiskProfileHandlers) GetUserProfile(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid user ID")
+		return
+	}
+	h.respondProfile(c, userID)
+}
+
+func (h *RiskProfileHandlers) respondProfile(c *gin.Context, userID uuid.UUID) {
+	profile, err := h.service.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get risk profile", "error", err)
+		common.RespondInternalError(c, "Failed to get risk profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetMyAssessments returns the user's assessment history
+// GET /api/v1/risk-profile/assessments
+func (h *RiskProfileHandlers) GetMyAssessments(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	assessments, err := h.service.GetAssessmentHistory(c.Request.Context(), userID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get risk assessments", "error", err)
+		common.RespondInternalError(c, "Failed to get risk assessments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assessments": assessments,
+	})
+}
+
+// AcknowledgeRisk records the user accepting auto-investment strategies above their risk band
+// POST /api/v1/risk-profile/acknowledgments
+func (h *RiskProfileHandlers) AcknowledgeRisk(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.AcknowledgeRiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ack, err := h.service.AcknowledgeRisk(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error("Failed to acknowledge risk", "error", err)
+		if err.Error() == "risk assessment required" {
+			common.RespondError(c, http.StatusBadRequest, "ASSESSMENT_REQUIRED", "Complete the risk questionnaire first", nil)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid risk band") || strings.HasSuffix(err.Error(), "within your risk profile") {
+			common.RespondBadRequest(c, err.Error())
+			return
+		}
+		common.RespondInternalError(c, "Failed to acknowledge risk")
+		return
+	}
+
+	c.JSON(http.StatusCreated, ack)
+}
+
+// ListQuestionnaires returns every questionnaire version (admin only)
+// GET /api/v1/admin/risk-profile/questionnaires
+func (h *RiskProfileHandlers) ListQuestionnaires(c *gin.Context) {
+	questionnaires, err := h.service.ListQuestionnaires(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list risk questionnaires", "error", err)
+		common.RespondInternalError(c, "Failed to list risk questionnaires")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"questionnaires": questionnaires,
+	})
+}
+
+// PublishQuestionnaire publishes a new questionnaire version (admin only)
+// POST /api/v1/admin/risk-profile/questionnaires
+func (h *RiskProfileHandlers) PublishQuestionnaire(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.CreateRiskQuestionnaireRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	questionnaire, err := h.service.PublishQuestionnaire(c.Request.Context(), adminID, &req)
+	if err != nil {
+		h.logger.Error("Failed to publish risk questionnaire", "error", err)
+		if strings.HasPrefix(err.Error(), "failed to") {
+			common.RespondInternalError(c, "Failed to publish risk questionnaire")
+			return
+		}
+		common.RespondError(c, http.StatusBadRequest, "PUBLISH_FAILED", err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, questionnaire)
+}
@@ -0,0 +1,196 @@
+package investing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/strategy"
+	"github.com/rail-service/rail_service/pkg/logger"
+)
+
+// StrategyHandlers handles model portfolio administration and strategy assignment history
+type StrategyHandlers struct {
+	engine *strategy.Engine
+	logger *logger.Logger
+}
+
+// NewStrategyHandlers creates a new strategy handlers instance
+func NewStrategyHandlers(engine *strategy.Engine, logger *logger.Logger) *StrategyHandlers {
+	return &StrategyHandlers{engine: engine, logger: logger}
+}
+
+// GetMyAssignments returns the strategies the user's auto-investments used
+// GET /api/v1/strategy/assignments
+func (h *StrategyHandlers) GetMyAssignments(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+	h.respondAssignments(c, userID)
+}
+
+// GetUserAssignments returns the strategies a user's auto-investments used (admin only)
+// GET /api/v1/admin/strategy/users/:id/assignments
+func (h *StrategyHandlers) GetUserAssignments(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid user ID")
+		return
+	}
+	h.respondAssignments(c, userID)
+}
+
+func (h *StrategyHandlers) respondAssignments(c *gin.Context, userID uuid.UUID) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	assignments, err := h.engine.GetUserAssignments(c.Request.Context(), userID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get strategy assignments", "error", err)
+		common.RespondInternalError(c, "Failed to get strategy assignments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignments": assignments,
+	})
+}
+
+// ListModelPortfolios returns every model portfolio with its current version (admin only)
+// GET /api/v1/admin/strategy/portfolios
+func (h *StrategyHandlers) ListModelPortfolios(c *gin.Context) {
+	portfolios, err := h.engine.ListModelPortfolios(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list model portfolios", "error", err)
+		common.RespondInternalError(c, "Failed to list model portfolios")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"portfolios": portfolios,
+	})
+}
+
+// CreateModelPortfolio creates a model portfolio with its first version (admin only)
+// POST /api/v1/admin/strategy/portfolios
+func (h *StrategyHandlers) CreateModelPortfolio(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.CreateModelPortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	portfolio, err := h.engine.CreateModelPortfolio(c.Request.Context(), adminID, &req)
+	if err != nil {
+		h.logger.Error("Failed to create model portfolio", "error", err)
+		if strings.Contains(err.Error(), "duplicate key") {
+			common.RespondConflict(c, "A model portfolio with this slug already exists")
+			return
+		}
+		common.RespondError(c, http.StatusBadRequest, "CREATE_FAILED", err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, portfolio)
+}
+
+// GetModelPortfolioVersions returns a model portfolio's version history (admin only)
+// GET /api/v1/admin/strategy/portfolios/:id/versions
+func (h *StrategyHandlers) GetModelPortfolioVersions(c *gin.Context) {
+	portfolioID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid model portfolio ID")
+		return
+	}
+
+	versions, err := h.engine.GetModelPortfolioVersions(c.Request.Context(), portfolioID)
+	if err != nil {
+		h.logger.Error("Failed to get model portfolio versions", "error", err)
+		if err.Error() == "model portfolio not found" {
+			common.RespondNotFound(c, "Model portfolio not found")
+			return
+		}
+		common.RespondInternalError(c, "Failed to get model portfolio versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+	})
+}
+
+// PublishModelPortfolioVersion adds a version to a model portfolio (admin only)
+// POST /api/v1/admin/strategy/portfolios/:id/versions
+func (h *StrategyHandlers) PublishModelPortfolioVersion(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	portfolioID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid model portfolio ID")
+		return
+	}
+
+	var req entities.CreateModelPortfolioVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	version, err := h.engine.PublishModelPortfolioVersion(c.Request.Context(), adminID, portfolioID, &req)
+	if err != nil {
+		h.logger.Error("Failed to publish model portfolio version", "error", err)
+		if err.Error() == "model portfolio not found" {
+			common.RespondNotFound(c, "Model portfolio not found")
+			return
+		}
+		common.RespondError(c, http.StatusBadRequest, "PUBLISH_FAILED", err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, version)
+}
+
+// UpdateModelPortfolioStatus retires or reactivates a model portfolio (admin only)
+// PUT /api/v1/admin/strategy/portfolios/:id/status
+func (h *StrategyHandlers) UpdateModelPortfolioStatus(c *gin.Context) {
+	portfolioID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid model portfolio ID")
+		return
+	}
+
+	var req entities.UpdateModelPortfolioStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	portfolio, err := h.engine.UpdateModelPortfolioStatus(c.Request.Context(), portfolioID, req.Status)
+	if err != nil {
+		h.logger.Error("Failed to update model portfolio", "error", err)
+		if err.Error() == "model portfolio not found" {
+			common.RespondNotFound(c, "Model portfolio not found")
+			return
+		}
+		common.RespondInternalError(c, "Failed to update model portfolio")
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
@@ -0,0 +1,215 @@
+package investing
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/tax"
+	"github.com/rail-service/rail_service/pkg/logger"
+)
+
+// TaxHandlers handles tax lots, lot relief settings, realized gains and tax-loss harvest reports
+type TaxHandlers struct {
+	service    *tax.Service
+	harvesting *tax.HarvestingService
+	logger     *logger.Logger
+}
+
+// NewTaxHandlers creates a new tax handlers instance; harvesting may be nil
+func NewTaxHandlers(service *tax.Service, harvesting *tax.HarvestingService, logger *logger.Logger) *TaxHandlers {
+	return &TaxHandlers{service: service, harvesting: harvesting, logger: logger}
+}
+
+// GetLots returns the user's open tax lots
+// GET /api/v1/tax/lots
+func (h *TaxHandlers) GetLots(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	lots, err := h.service.GetOpenLots(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get tax lots", "error", err)
+		common.RespondInternalError(c, "Failed to get tax lots")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lots": lots})
+}
+
+// GetSettings returns the user's lot relief method and harvesting opt-in
+// GET /api/v1/tax/settings
+func (h *TaxHandlers) GetSettings(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get tax lot settings", "error", err)
+		common.RespondInternalError(c, "Failed to get tax lot settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings changes the lot relief method for the user's future sales and opts in or out
+// of tax-loss harvesting
+// PUT /api/v1/tax/settings
+func (h *TaxHandlers) UpdateSettings(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.UpdateTaxLotSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Method == nil && req.HarvestingEnabled == nil {
+		common.RespondBadRequest(c, "method or harvesting_enabled is required")
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "invalid tax lot method" {
+			common.RespondBadRequest(c, "Method must be one of fifo, specific_id or hifo")
+			return
+		}
+		h.logger.Error("Failed to update tax lot settings", "error", err)
+		common.RespondInternalError(c, "Failed to update tax lot settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SelectLots chooses the lots a sell order relieves under the specific-id method
+// POST /api/v1/tax/lot-selections
+func (h *TaxHandlers) SelectLots(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.SelectTaxLotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	selections, err := h.service.SelectLots(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "tax lot not found":
+			common.RespondNotFound(c, "Tax lot not found")
+		case "invalid lot quantity", "duplicate tax lot in selection", "selected quantity exceeds lot remaining quantity":
+			common.RespondBadRequest(c, err.Error())
+		default:
+			h.logger.Error("Failed to select tax lots", "error", err)
+			common.RespondInternalError(c, "Failed to select tax lots")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order_id": req.OrderID, "lots": selections})
+}
+
+// GetRealizedGains returns the user's realized gains report for a tax year as JSON or CSV
+// GET /api/v1/tax/realized-gains?year=2025&format=csv
+func (h *TaxHandlers) GetRealizedGains(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	year := time.Now().Year()
+	if y := c.Query("year"); y != "" {
+		if year, err = strconv.Atoi(y); err != nil {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+	}
+	format := entities.TaxReportFormat(strings.ToLower(c.DefaultQuery("format", string(entities.TaxReportFormatJSON))))
+	if format != entities.TaxReportFormatJSON && format != entities.TaxReportFormatCSV {
+		common.RespondBadRequest(c, "Format must be json or csv")
+		return
+	}
+
+	report, err := h.service.GenerateRealizedGainsReport(c.Request.Context(), userID, year)
+	if err != nil {
+		if err.Error() == "invalid tax year" {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+		h.logger.Error("Failed to generate realized gains report", "error", err)
+		common.RespondInternalError(c, "Failed to generate realized gains report")
+		return
+	}
+
+	if format == entities.TaxReportFormatJSON {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	data, err := h.service.ExportRealizedGainsReport(report, format)
+	if err != nil {
+		h.logger.Error("Failed to export realized gains report", "error", err)
+		common.RespondInternalError(c, "Failed to export realized gains report")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=realized-gains-%d.csv", year))
+	c.Data(http.StatusOK, "text/csv", data)
+}
+
+// GetHarvests returns the losses harvested for the user in a tax year
+// GET /api/v1/tax/harvests?year=2025
+func (h *TaxHandlers) GetHarvests(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+	if h.harvesting == nil {
+		common.RespondError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Tax-loss harvesting is not available", nil)
+		return
+	}
+
+	year := time.Now().Year()
+	if y := c.Query("year"); y != "" {
+		if year, err = strconv.Atoi(y); err != nil {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+	}
+
+	report, err := h.harvesting.GetHarvestReport(c.Request.Context(), userID, year)
+	if err != nil {
+		if err.Error() == "invalid tax year" {
+			common.RespondBadRequest(c, "Invalid year")
+			return
+		}
+		h.logger.Error("Failed to get tax-loss harvests", "error", err)
+		common.RespondInternalError(c, "Failed to get tax-loss harvests")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -171,14 +171,7 @@ func (h *SpendingStashHandlers) buildResponse(
 
 		var pending decimal.Decimal
 		for _, tx := range txnsToShow {
-			merchantName := ""
-			category := "Other"
-			if tx.MerchantName != nil {
-				merchantName = *tx.MerchantName
-			}
-			if tx.MerchantCategory != nil {
-				category = *tx.MerchantCategory
-			}
+			merchantName, category := cardTxMerchantAndCategory(tx)
 
 			if tx.Status == "pending" || tx.Type == "authorization" {
 				resp.PendingAuthorizations = append(resp.PendingAuthorizations, PendingAuthorization{
@@ -264,10 +257,7 @@ func (h *SpendingStashHandlers) calculateSpendingMetrics(txns []*entities.Bridge
 			continue
 		}
 		amount := tx.Amount.Abs()
-		category := "Other"
-		if tx.MerchantCategory != nil {
-			category = *tx.MerchantCategory
-		}
+		_, category := cardTxMerchantAndCategory(tx)
 
 		if !tx.CreatedAt.Before(thisMonthStart) {
 			thisMonthTotal = thisMonthTotal.Add(amount)
@@ -327,6 +317,22 @@ func (h *SpendingStashHandlers) calculateSpendingMetrics(txns []*entities.Bridge
 	return summary, categories
 }
 
+// cardTxMerchantAndCategory returns the enriched merchant name and category label of a card
+// transaction, falling back to the raw merchant descriptor
+func cardTxMerchantAndCategory(tx *entities.BridgeCardTransaction) (string, string) {
+	merchantName := ""
+	if tx.MerchantDisplayName != nil {
+		merchantName = *tx.MerchantDisplayName
+	} else if tx.MerchantName != nil {
+		merchantName = *tx.MerchantName
+	}
+	category := entities.SpendingCategoryOther.Label()
+	if tx.Category != nil {
+		category = tx.Category.Label()
+	}
+	return merchantName, category
+}
+
 func categoryIcon(category string) string {
 	icons := map[string]string{
 		"Food & Drink":    "🍔",
@@ -337,6 +343,11 @@ func categoryIcon(category string) string {
 		"Health":          "💊",
 		"Utilities":       "💡",
 		"Groceries":       "🛒",
+		"Subscriptions":   "🔁",
+		"Bills":           "🧾",
+		"Cash":            "💵",
+		"Transfers":       "💸",
+		"Fees":            "🏦",
 	}
 	if icon, ok := icons[category]; ok {
 		return icon
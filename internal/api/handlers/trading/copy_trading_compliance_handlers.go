@@ -0,0 +1,163 @@
+package trading
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Copy Trading Compliance Handlers ===
+
+// GetComplianceReport returns the conductor surveillance report (admin only)
+// GET /api/v1/admin/copy/compliance/report?days=7
+func (h *CopyTradingHandlers) GetComplianceReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 365 {
+		common.RespondBadRequest(c, "days must be between 1 and 365")
+		return
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	report, err := h.service.GetComplianceReport(c.Request.Context(), since)
+	if err != nil {
+		h.logger.Error("Failed to get compliance report", "error", err)
+		common.RespondInternalError(c, "Failed to get compliance report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetConductorComplianceEvents returns a conductor's compliance events (admin only)
+// GET /api/v1/admin/copy/conductors/:id/compliance-events
+func (h *CopyTradingHandlers) GetConductorComplianceEvents(c *gin.Context) {
+	conductorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid conductor ID")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	events, err := h.service.GetConductorComplianceEvents(c.Request.Context(), conductorID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get compliance events", "error", err)
+		common.RespondInternalError(c, "Failed to get compliance events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
+
+// SuspendConductor stops a conductor's trades from being copied (admin only)
+// POST /api/v1/admin/copy/conductors/:id/suspend
+func (h *CopyTradingHandlers) SuspendConductor(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	conductorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid conductor ID")
+		return
+	}
+
+	var req entities.SuspendConductorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	conductor, err := h.service.SuspendConductor(c.Request.Context(), adminID, conductorID, req.Reason)
+	if err != nil {
+		h.logger.Error("Failed to suspend conductor", "error", err)
+		if err.Error() == "conductor not found" {
+			common.RespondNotFound(c, "Conductor not found")
+			return
+		}
+		common.RespondError(c, http.StatusBadRequest, "SUSPEND_FAILED", err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Conductor suspended successfully",
+		"conductor": conductor,
+	})
+}
+
+// ListBlackoutWindows returns active and upcoming blackout windows (admin only)
+// GET /api/v1/admin/copy/blackouts
+func (h *CopyTradingHandlers) ListBlackoutWindows(c *gin.Context) {
+	windows, err := h.service.GetBlackoutWindows(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list blackout windows", "error", err)
+		common.RespondInternalError(c, "Failed to list blackout windows")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blackouts": windows,
+	})
+}
+
+// CreateBlackoutWindow schedules a blackout window (admin only)
+// POST /api/v1/admin/copy/blackouts
+func (h *CopyTradingHandlers) CreateBlackoutWindow(c *gin.Context) {
+	adminID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.CreateBlackoutWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	window, err := h.service.CreateBlackoutWindow(c.Request.Context(), adminID, &req)
+	if err != nil {
+		h.logger.Error("Failed to create blackout window", "error", err)
+		if err.Error() == "conductor not found" {
+			common.RespondNotFound(c, "Conductor not found")
+			return
+		}
+		common.RespondError(c, http.StatusBadRequest, "CREATE_FAILED", err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+// DeleteBlackoutWindow removes a blackout window (admin only)
+// DELETE /api/v1/admin/copy/blackouts/:id
+func (h *CopyTradingHandlers) DeleteBlackoutWindow(c *gin.Context) {
+	windowID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid blackout window ID")
+		return
+	}
+
+	if err := h.service.DeleteBlackoutWindow(c.Request.Context(), windowID); err != nil {
+		h.logger.Error("Failed to delete blackout window", "error", err)
+		if err.Error() == "blackout window not found" {
+			common.RespondNotFound(c, "Blackout window not found")
+			return
+		}
+		common.RespondInternalError(c, "Failed to delete blackout window")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Blackout window deleted successfully",
+	})
+}
@@ -0,0 +1,104 @@
+package trading
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/api/handlers/common"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Copy Trading Fee Handlers ===
+
+// GetDraftFees returns fee statements for a draft
+// GET /api/v1/copy/drafts/:id/fees
+func (h *CopyTradingHandlers) GetDraftFees(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid draft ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "24"))
+
+	statements, err := h.service.GetDraftFeeStatements(c.Request.Context(), userID, draftID, limit)
+	if err != nil {
+		if err.Error() == "draft not found" || err.Error() == "unauthorized" {
+			common.RespondNotFound(c, "Draft not found")
+			return
+		}
+		h.logger.Error("Failed to get draft fee statements", "error", err)
+		common.RespondInternalError(c, "Failed to get fee statements")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statements": statements,
+		"count":      len(statements),
+	})
+}
+
+// GetMyEarnings returns the current conductor's fee earnings and payouts
+// GET /api/v1/copy/conductors/me/earnings
+func (h *CopyTradingHandlers) GetMyEarnings(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	earnings, err := h.service.GetConductorEarnings(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user is not a conductor" {
+			common.RespondNotFound(c, "Conductor profile not found")
+			return
+		}
+		h.logger.Error("Failed to get conductor earnings", "error", err)
+		common.RespondInternalError(c, "Failed to get earnings")
+		return
+	}
+
+	c.JSON(http.StatusOK, earnings)
+}
+
+// UpdateMyFees changes the current conductor's fee schedule
+// PUT /api/v1/copy/conductors/me/fees
+func (h *CopyTradingHandlers) UpdateMyFees(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req entities.UpdateConductorFeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	conductor, err := h.service.UpdateConductorFees(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case err.Error() == "user is not a conductor":
+			common.RespondNotFound(c, "Conductor profile not found")
+		case strings.Contains(err.Error(), "fee rate must be between"):
+			common.RespondError(c, http.StatusBadRequest, "INVALID_FEE_RATE", err.Error(), nil)
+		default:
+			h.logger.Error("Failed to update conductor fees", "error", err)
+			common.RespondInternalError(c, "Failed to update fees")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, conductor)
+}
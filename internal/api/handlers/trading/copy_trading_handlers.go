@@ -4,6 +4,7 @@ import (
 	"github.com/rail-service/rail_service/internal/api/handlers/common"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,7 +33,7 @@ func NewCopyTradingHandlers(service *copytrading.Service, logger *logger.Logger)
 func (h *CopyTradingHandlers) ListConductors(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	sortBy := c.DefaultQuery("sort_by", "followers") // followers, return, aum, win_rate
+	sortBy := c.DefaultQuery("sort_by", "followers") // followers, return, aum, win_rate, sharpe, drawdown, trades
 
 	result, err := h.service.ListConductors(c.Request.Context(), page, pageSize, sortBy)
 	if err != nil {
@@ -91,6 +92,34 @@ func (h *CopyTradingHandlers) GetConductorSignals(c *gin.Context) {
 	})
 }
 
+// GetConductorPerformance returns a conductor's daily performance series and headline metrics
+// GET /api/v1/copy/conductors/:id/performance
+func (h *CopyTradingHandlers) GetConductorPerformance(c *gin.Context) {
+	conductorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid conductor ID")
+		return
+	}
+
+	period := c.DefaultQuery("period", "3m") // 1m, 3m, 6m, 1y, all
+
+	chart, err := h.service.GetConductorPerformance(c.Request.Context(), conductorID, period)
+	if err != nil {
+		switch err.Error() {
+		case "invalid period":
+			common.RespondBadRequest(c, "Invalid period")
+		case "conductor not found":
+			common.RespondNotFound(c, "Conductor not found")
+		default:
+			h.logger.Error("Failed to get conductor performance", "error", err)
+			common.RespondInternalError(c, "Failed to get performance")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, chart)
+}
+
 // CreateDraft creates a new copy relationship (follow a conductor)
 // POST /api/v1/copy/drafts
 func (h *CopyTradingHandlers) CreateDraft(c *gin.Context) {
@@ -125,6 +154,12 @@ func (h *CopyTradingHandlers) CreateDraft(c *gin.Context) {
 			common.RespondError(c, http.StatusBadRequest, "SELF_COPY", "You cannot copy your own trades", nil)
 		case "already following this conductor":
 			common.RespondError(c, http.StatusConflict, "ALREADY_FOLLOWING", "You are already following this conductor", nil)
+		case "invalid mirror mode", "track mirroring requires portfolio mode":
+			common.RespondError(c, http.StatusBadRequest, "INVALID_MIRROR_MODE", err.Error(), nil)
+		case "track not found":
+			common.RespondNotFound(c, "Track not found")
+		case entities.ErrRiskAcknowledgmentRequired.Error():
+			common.RespondError(c, http.StatusConflict, "RISK_ACKNOWLEDGMENT_REQUIRED", "This conductor is riskier than your risk profile; resubmit with acknowledge_risk to proceed", nil)
 		default:
 			if len(err.Error()) > 20 && err.Error()[:20] == "minimum allocation is" {
 				common.RespondError(c, http.StatusBadRequest, "MIN_ALLOCATION", err.Error(), nil)
@@ -348,6 +383,79 @@ func (h *CopyTradingHandlers) GetDraftHistory(c *gin.Context) {
 	})
 }
 
+// SyncDraft places catch-up orders to bring a portfolio-mode draft to its target weights
+// POST /api/v1/copy/drafts/:id/sync
+func (h *CopyTradingHandlers) SyncDraft(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid draft ID")
+		return
+	}
+
+	orders, err := h.service.SyncUserDraft(c.Request.Context(), userID, draftID)
+	if err != nil {
+		switch err.Error() {
+		case "draft not found", "unauthorized":
+			common.RespondNotFound(c, "Draft not found")
+		case "draft is not in portfolio mode", "draft is not active":
+			common.RespondError(c, http.StatusBadRequest, "SYNC_NOT_ALLOWED", err.Error(), nil)
+		default:
+			h.logger.Error("Failed to sync draft", "error", err)
+			common.RespondInternalError(c, "Failed to sync draft")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"count":  len(orders),
+	})
+}
+
+// UpdateDraftRisk sets a draft's max drawdown, trailing stop, concentration cap and max trade size
+// PUT /api/v1/copy/drafts/:id/risk
+func (h *CopyTradingHandlers) UpdateDraftRisk(c *gin.Context) {
+	userID, err := common.GetUserID(c)
+	if err != nil {
+		common.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid draft ID")
+		return
+	}
+
+	var req entities.UpdateDraftRiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	draft, err := h.service.UpdateDraftRisk(c.Request.Context(), userID, draftID, &req)
+	if err != nil {
+		switch {
+		case err.Error() == "draft not found", err.Error() == "unauthorized":
+			common.RespondNotFound(c, "Draft not found")
+		case err.Error() == "draft already unlinked", strings.HasPrefix(err.Error(), "invalid risk settings"):
+			common.RespondError(c, http.StatusBadRequest, "INVALID_RISK_SETTINGS", err.Error(), nil)
+		default:
+			h.logger.Error("Failed to update draft risk settings", "error", err)
+			common.RespondInternalError(c, "Failed to update draft risk settings")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
 // === Conductor Application Handlers ===
 
 // ApplyAsConductor submits an application to become a conductor
@@ -563,6 +671,34 @@ func (h *CopyTradingHandlers) GetTrack(c *gin.Context) {
 	c.JSON(http.StatusOK, track)
 }
 
+// GetTrackVersions returns a track's allocation history with each version's changes
+// GET /api/v1/copy/tracks/:id/versions
+func (h *CopyTradingHandlers) GetTrackVersions(c *gin.Context) {
+	trackID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondBadRequest(c, "Invalid track ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	versions, err := h.service.GetTrackVersions(c.Request.Context(), trackID, limit)
+	if err != nil {
+		if err.Error() == "track not found" {
+			common.RespondNotFound(c, "Track not found")
+			return
+		}
+		h.logger.Error("Failed to get track versions", "error", err)
+		common.RespondInternalError(c, "Failed to get track versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
 // GetConductorTracks returns all tracks for a conductor
 // GET /api/v1/copy/conductors/:id/tracks
 func (h *CopyTradingHandlers) GetConductorTracks(c *gin.Context) {
@@ -181,8 +181,8 @@ func (h *RebalancingHandlers) DeleteRebalancingConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
 }
 
-// GenerateRebalancingPlan generates a rebalancing plan
-// GET /api/v1/rebalancing/configs/:id/plan
+// GenerateRebalancingPlan generates a rebalancing plan, optionally investing new cash first
+// GET /api/v1/rebalancing/configs/:id/plan?cash=250.00
 func (h *RebalancingHandlers) GenerateRebalancingPlan(c *gin.Context) {
 	userID, err := common.GetUserID(c)
 	if err != nil {
@@ -196,7 +196,15 @@ func (h *RebalancingHandlers) GenerateRebalancingPlan(c *gin.Context) {
 		return
 	}
 
-	plan, err := h.service.GenerateRebalancingPlan(c.Request.Context(), userID, configID)
+	cashFlow := decimal.Zero
+	if cash := c.Query("cash"); cash != "" {
+		if cashFlow, err = decimal.NewFromString(cash); err != nil {
+			common.RespondBadRequest(c, "Invalid cash format")
+			return
+		}
+	}
+
+	plan, err := h.service.GenerateRebalancingPlanWithCashFlow(c.Request.Context(), userID, configID, cashFlow)
 	if err != nil {
 		h.logger.Error("Failed to generate rebalancing plan", "error", err)
 		common.RespondBadRequest(c, err.Error())
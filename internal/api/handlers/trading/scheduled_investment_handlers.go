@@ -39,6 +39,10 @@ func (h *ScheduledInvestmentHandlers) CreateScheduledInvestment(c *gin.Context)
 		Frequency  string  `json:"frequency" binding:"required"`
 		DayOfWeek  *int    `json:"day_of_week"`
 		DayOfMonth *int    `json:"day_of_month"`
+		// How missed runs are handled: skip, run_once (default) or run_all
+		CatchUpPolicy *string `json:"catch_up_policy"`
+		// How runs on market holidays and early closes are handled: defer (default) or skip
+		HolidayPolicy *string `json:"holiday_policy"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.RespondBadRequest(c, "Invalid request: "+err.Error())
@@ -52,13 +56,15 @@ func (h *ScheduledInvestmentHandlers) CreateScheduledInvestment(c *gin.Context)
 	}
 
 	createReq := &investing.CreateScheduledInvestmentRequest{
-		UserID:     userID,
-		Name:       req.Name,
-		Symbol:     req.Symbol,
-		Amount:     amount,
-		Frequency:  req.Frequency,
-		DayOfWeek:  req.DayOfWeek,
-		DayOfMonth: req.DayOfMonth,
+		UserID:        userID,
+		Name:          req.Name,
+		Symbol:        req.Symbol,
+		Amount:        amount,
+		Frequency:     req.Frequency,
+		DayOfWeek:     req.DayOfWeek,
+		DayOfMonth:    req.DayOfMonth,
+		CatchUpPolicy: req.CatchUpPolicy,
+		HolidayPolicy: req.HolidayPolicy,
 	}
 
 	if req.BasketID != nil {
@@ -161,8 +167,10 @@ func (h *ScheduledInvestmentHandlers) UpdateScheduledInvestment(c *gin.Context)
 	}
 
 	var req struct {
-		Amount    *string `json:"amount"`    // String for precise decimal (e.g., "100.00")
-		Frequency *string `json:"frequency"`
+		Amount        *string `json:"amount"` // String for precise decimal (e.g., "100.00")
+		Frequency     *string `json:"frequency"`
+		CatchUpPolicy *string `json:"catch_up_policy"`
+		HolidayPolicy *string `json:"holiday_policy"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.RespondBadRequest(c, "Invalid request")
@@ -179,6 +187,13 @@ func (h *ScheduledInvestmentHandlers) UpdateScheduledInvestment(c *gin.Context)
 		amount = &a
 	}
 
+	if req.CatchUpPolicy != nil || req.HolidayPolicy != nil {
+		if err := h.service.UpdateSchedulePolicies(c.Request.Context(), id, req.CatchUpPolicy, req.HolidayPolicy); err != nil {
+			common.RespondBadRequest(c, err.Error())
+			return
+		}
+	}
+
 	if err := h.service.UpdateScheduledInvestment(c.Request.Context(), id, amount, req.Frequency); err != nil {
 		h.logger.Error("Failed to update scheduled investment", "error", err)
 		common.RespondBadRequest(c, err.Error())
@@ -1217,6 +1217,12 @@ func (h *WalletFundingHandlers) CreateOrder(c *gin.Context) {
 				Message: "Insufficient position for sell order",
 			})
 			return
+		case entities.ErrRiskAcknowledgmentRequired:
+			c.JSON(http.StatusConflict, entities.ErrorResponse{
+				Code:    "RISK_ACKNOWLEDGMENT_REQUIRED",
+				Message: "This basket is riskier than your risk profile; resubmit with acknowledgeRisk to proceed",
+			})
+			return
 		default:
 			h.logger.Error("Failed to create order", "error", err, "user_id", userUUID)
 			c.JSON(http.StatusInternalServerError, entities.ErrorResponse{
@@ -214,7 +214,19 @@ func (h *AlpacaWebhookHandlers) HandleNonTradeActivity(c *gin.Context) {
 		h.logger.Error("Failed to store event", "error", err)
 	}
 
-	h.logger.Info("NTA webhook received", "body", string(body))
+	var activity entities.AlpacaNonTradeActivityEvent
+	if err := json.Unmarshal(body, &activity); err != nil {
+		h.logger.Error("Failed to parse NTA webhook", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+
+	// Process dividends immediately so buying power reflects them
+	if err := h.eventProcessor.ProcessNonTradeActivity(c.Request.Context(), &activity); err != nil {
+		h.logger.Error("Failed to process non-trade activity", "error", err, "activity_id", activity.ID)
+	}
+
+	h.logger.Info("NTA webhook received", "activity_id", activity.ID, "activity_type", activity.Type())
 	c.JSON(http.StatusOK, gin.H{"status": "received"})
 }
 
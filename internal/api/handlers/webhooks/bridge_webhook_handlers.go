@@ -1,6 +1,7 @@
 package webhooks
 
 import (
+	"context"
 	"crypto"
 	"crypto/hmac"
 	"crypto/rsa"
@@ -21,6 +22,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
 )
 
 // BridgeWebhookService defines operations for processing Bridge events
@@ -29,10 +33,11 @@ type BridgeWebhookService interface {
 	ProcessTransferCompleted(ctx *gin.Context, transferID string, amount decimal.Decimal) error
 	ProcessCustomerStatusChanged(ctx *gin.Context, customerID string, status string) error
 	// Card transaction methods
-	ProcessCardAuthorization(ctx *gin.Context, cardID string, amount decimal.Decimal, merchantName, merchantCategory string) error
-	ProcessCardTransaction(ctx *gin.Context, cardID, transID string, amount decimal.Decimal, merchantName, merchantCategory, status string) error
+	ProcessCardAuthorization(ctx *gin.Context, event *BridgeCardAuthorizationEvent) (*entities.CardAuthorizationDecision, error)
+	ProcessCardTransaction(ctx *gin.Context, cardID, transID, txType string, amount decimal.Decimal, merchantName, merchantCategory, status string) error
 	ProcessCardTransactionDeclined(ctx *gin.Context, cardID, transID, declineReason string) error
 	ProcessCardStatusChanged(ctx *gin.Context, cardID, status string) error
+	ProcessCardShipmentUpdate(ctx *gin.Context, cardID, status, carrier, trackingNumber, trackingURL string) error
 }
 
 // BridgeWebhookHandler handles Bridge API webhook notifications
@@ -76,6 +81,18 @@ type BridgeDepositEvent struct {
 	Status           string `json:"status"`
 }
 
+// BridgeCardAuthorizationEvent represents a real-time card authorization from Bridge
+type BridgeCardAuthorizationEvent struct {
+	CardAccountID    string          `json:"card_account_id"`
+	TransactionID    string          `json:"transaction_id"`
+	Amount           decimal.Decimal `json:"amount"`
+	Currency         string          `json:"currency"`
+	MerchantName     string          `json:"merchant_name"`
+	MerchantCategory string          `json:"merchant_category"`
+	MerchantCountry  string          `json:"merchant_country"`
+	CardNotPresent   bool            `json:"card_not_present"`
+}
+
 // BridgeTransferEvent represents a transfer event from Bridge
 type BridgeTransferEvent struct {
 	ID          string `json:"id"`
@@ -94,6 +111,12 @@ type BridgeCustomerEvent struct {
 	Email  string `json:"email"`
 }
 
+// SetService sets the service that processes Bridge events. The handler is created before the
+// domain services it routes to, so the service is wired once they exist.
+func (h *BridgeWebhookHandler) SetService(service BridgeWebhookService) {
+	h.service = service
+}
+
 // HandleWebhook handles all Bridge webhook events
 // POST /webhooks/bridge
 func (h *BridgeWebhookHandler) HandleWebhook(c *gin.Context) {
@@ -271,6 +294,19 @@ func (h *BridgeWebhookHandler) handleCardAccountEvent(c *gin.Context, payload Br
 		h.logger.Error("Failed to process card account event", zap.Error(err))
 	}
 
+	// Physical cards carry shipping progress on the card account object
+	if shipping, ok := payload.EventObject["shipping"].(map[string]interface{}); ok {
+		shippingStatus := getStringField(shipping, "status")
+		if shippingStatus != "" {
+			if err := h.service.ProcessCardShipmentUpdate(c, cardAccountID, shippingStatus,
+				getStringField(shipping, "carrier"),
+				getStringField(shipping, "tracking_number"),
+				getStringField(shipping, "tracking_url")); err != nil {
+				h.logger.Error("Failed to process card shipment update", zap.Error(err))
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "success"})
 }
 
@@ -301,11 +337,12 @@ func (h *BridgeWebhookHandler) handleCardTransactionEvent(c *gin.Context, payloa
 			h.logger.Error("Failed to process declined transaction", zap.Error(err))
 		}
 	case "pending":
-		if err := h.service.ProcessCardAuthorization(c, cardAccountID, amount, merchantName, merchantCategory); err != nil {
-			h.logger.Error("Failed to process card authorization", zap.Error(err))
-		}
+		event := newCardAuthorizationEvent(payload.EventObject, cardAccountID, transactionID, amount)
+		decision, err := h.service.ProcessCardAuthorization(c, event)
+		h.respondCardAuthorization(c, decision, err)
+		return
 	default:
-		if err := h.service.ProcessCardTransaction(c, cardAccountID, transactionID, amount, merchantName, merchantCategory, status); err != nil {
+		if err := h.service.ProcessCardTransaction(c, cardAccountID, transactionID, cardTransactionType(payload.EventObject), amount, merchantName, merchantCategory, status); err != nil {
 			h.logger.Error("Failed to process card transaction", zap.Error(err))
 		}
 	}
@@ -313,7 +350,7 @@ func (h *BridgeWebhookHandler) handleCardTransactionEvent(c *gin.Context, payloa
 	c.JSON(http.StatusOK, gin.H{"status": "success"})
 }
 
-// handlePostedCardTransaction processes posted_card_account_transaction events
+// handlePostedCardTransaction processes posted_card_account_transaction events, which Bridge sends when a purchase settles
 func (h *BridgeWebhookHandler) handlePostedCardTransaction(c *gin.Context, payload BridgeWebhookPayload) {
 	transactionID := payload.EventObjectID
 	cardAccountID := getStringField(payload.EventObject, "card_account_id")
@@ -331,7 +368,7 @@ func (h *BridgeWebhookHandler) handlePostedCardTransaction(c *gin.Context, paylo
 		zap.String("card_account_id", cardAccountID),
 		zap.String("amount", amount.String()))
 
-	if err := h.service.ProcessCardTransaction(c, cardAccountID, transactionID, amount, merchantName, merchantCategory, "posted"); err != nil {
+	if err := h.service.ProcessCardTransaction(c, cardAccountID, transactionID, cardTransactionType(payload.EventObject), amount, merchantName, merchantCategory, string(entities.CardTxStatusCompleted)); err != nil {
 		h.logger.Error("Failed to process posted card transaction", zap.Error(err))
 	}
 
@@ -479,15 +516,37 @@ func (h *BridgeWebhookHandler) handleCardAuthorization(c *gin.Context, payload B
 	h.logger.Info("Card authorization request",
 		zap.String("card_id", cardID),
 		zap.String("amount", amount.String()),
-		zap.String("merchant", merchantName))
+		zap.String("merchant", merchantName),
+		zap.String("merchant_category", merchantCategory))
 
+	var decision *entities.CardAuthorizationDecision
+	var err error
 	if h.service != nil {
-		if err := h.service.ProcessCardAuthorization(c, cardID, amount, merchantName, merchantCategory); err != nil {
-			h.logger.Error("Failed to process card authorization", zap.Error(err))
-		}
+		event := newCardAuthorizationEvent(payload.EventObject, cardID, getStringField(payload.EventObject, "transaction_id"), amount)
+		decision, err = h.service.ProcessCardAuthorization(c, event)
 	}
+	h.respondCardAuthorization(c, decision, err)
+}
 
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+// respondCardAuthorization returns the authorization decision to Bridge, which approves or declines
+// the transaction from the response. Without a decision the transaction is declined.
+func (h *BridgeWebhookHandler) respondCardAuthorization(c *gin.Context, decision *entities.CardAuthorizationDecision, err error) {
+	if decision == nil {
+		decision = &entities.CardAuthorizationDecision{DeclineCode: entities.CardDeclineProcessingError}
+	}
+	if err != nil && decision.DeclineCode == entities.CardDeclineProcessingError {
+		h.logger.Error("Failed to process card authorization", zap.Error(err))
+	}
+
+	if decision.Approved {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "approved": true})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "declined",
+		"approved":     false,
+		"decline_code": decision.DeclineCode,
+	})
 }
 
 func (h *BridgeWebhookHandler) handleCardTransaction(c *gin.Context, payload BridgeWebhookPayload) {
@@ -527,7 +586,7 @@ func (h *BridgeWebhookHandler) handleCardTransaction(c *gin.Context, payload Bri
 		zap.String("amount", amount.String()))
 
 	if h.service != nil {
-		if err := h.service.ProcessCardTransaction(c, cardID, transID, amount, merchantName, merchantCategory, "completed"); err != nil {
+		if err := h.service.ProcessCardTransaction(c, cardID, transID, cardTransactionType(payload.EventObject), amount, merchantName, merchantCategory, "completed"); err != nil {
 			h.logger.Error("Failed to process card transaction", zap.Error(err))
 		}
 	}
@@ -712,10 +771,11 @@ type BridgeCustomerProcessor interface {
 
 // BridgeCardProcessor processes card events
 type BridgeCardProcessor interface {
-	ProcessAuthorization(ctx *gin.Context, cardID string, amount decimal.Decimal, merchantName, merchantCategory string) error
-	RecordTransaction(ctx *gin.Context, cardID, transactionID string, amount decimal.Decimal, merchantName, merchantCategory, status string) error
-	RecordDeclinedTransaction(ctx *gin.Context, cardID, transactionID, declineReason string) error
-	SyncCardStatus(ctx *gin.Context, cardID, status string) error
+	ProcessAuthorization(ctx context.Context, req *card.AuthorizationRequest) (*entities.CardAuthorizationDecision, error)
+	RecordTransaction(ctx context.Context, bridgeCardID, bridgeTransID, txType string, amount decimal.Decimal, merchantName, merchantCategory, status string, declineReason *string) error
+	RecordDeclinedTransaction(ctx context.Context, cardID, transactionID, declineReason string) error
+	SyncCardStatus(ctx context.Context, bridgeCardID string) error
+	UpdateCardShipment(ctx context.Context, bridgeCardID, status, carrier, trackingNumber, trackingURL string) error
 }
 
 // BridgeWebhookNotifier sends notifications for Bridge events
@@ -742,6 +802,11 @@ func NewBridgeWebhookService(
 }
 
 func (s *BridgeWebhookServiceImpl) ProcessFiatDeposit(ctx *gin.Context, event *BridgeDepositEvent) error {
+	if s.virtualAccountService == nil {
+		s.logger.Warn("Virtual account service not configured, skipping deposit processing",
+			zap.String("virtual_account_id", event.VirtualAccountID))
+		return nil
+	}
 	return s.virtualAccountService.ProcessFiatDeposit(ctx, event)
 }
 
@@ -759,22 +824,31 @@ func (s *BridgeWebhookServiceImpl) ProcessCustomerStatusChanged(ctx *gin.Context
 
 // Card processing methods - wired to CardService
 
-func (s *BridgeWebhookServiceImpl) ProcessCardAuthorization(ctx *gin.Context, cardID string, amount decimal.Decimal, merchantName, merchantCategory string) error {
+func (s *BridgeWebhookServiceImpl) ProcessCardAuthorization(ctx *gin.Context, event *BridgeCardAuthorizationEvent) (*entities.CardAuthorizationDecision, error) {
 	if s.cardService == nil {
-		s.logger.Warn("Card service not configured, skipping authorization processing",
-			zap.String("card_id", cardID))
-		return nil
-	}
-	return s.cardService.ProcessAuthorization(ctx, cardID, amount, merchantName, merchantCategory)
-}
-
-func (s *BridgeWebhookServiceImpl) ProcessCardTransaction(ctx *gin.Context, cardID, transID string, amount decimal.Decimal, merchantName, merchantCategory, status string) error {
+		s.logger.Warn("Card service not configured, declining authorization",
+			zap.String("card_id", event.CardAccountID))
+		return &entities.CardAuthorizationDecision{DeclineCode: entities.CardDeclineProcessingError}, nil
+	}
+	return s.cardService.ProcessAuthorization(ctx, &card.AuthorizationRequest{
+		BridgeCardID:     event.CardAccountID,
+		BridgeTransID:    event.TransactionID,
+		Amount:           event.Amount,
+		Currency:         event.Currency,
+		MerchantName:     event.MerchantName,
+		MerchantCategory: event.MerchantCategory,
+		MerchantCountry:  event.MerchantCountry,
+		CardNotPresent:   event.CardNotPresent,
+	})
+}
+
+func (s *BridgeWebhookServiceImpl) ProcessCardTransaction(ctx *gin.Context, cardID, transID, txType string, amount decimal.Decimal, merchantName, merchantCategory, status string) error {
 	if s.cardService == nil {
 		s.logger.Warn("Card service not configured, skipping transaction processing",
 			zap.String("card_id", cardID))
 		return nil
 	}
-	return s.cardService.RecordTransaction(ctx, cardID, transID, amount, merchantName, merchantCategory, status)
+	return s.cardService.RecordTransaction(ctx, cardID, transID, txType, amount, merchantName, merchantCategory, status, nil)
 }
 
 func (s *BridgeWebhookServiceImpl) ProcessCardTransactionDeclined(ctx *gin.Context, cardID, transID, declineReason string) error {
@@ -792,12 +866,59 @@ func (s *BridgeWebhookServiceImpl) ProcessCardStatusChanged(ctx *gin.Context, ca
 			zap.String("card_id", cardID))
 		return nil
 	}
-	return s.cardService.SyncCardStatus(ctx, cardID, status)
+	return s.cardService.SyncCardStatus(ctx, cardID)
+}
+
+func (s *BridgeWebhookServiceImpl) ProcessCardShipmentUpdate(ctx *gin.Context, cardID, status, carrier, trackingNumber, trackingURL string) error {
+	if s.cardService == nil {
+		s.logger.Warn("Card service not configured, skipping shipment update",
+			zap.String("card_id", cardID))
+		return nil
+	}
+	return s.cardService.UpdateCardShipment(ctx, cardID, status, carrier, trackingNumber, trackingURL)
 }
 
 
 // Helper functions for extracting fields from event objects
 
+// cardTransactionType maps the Bridge transaction type onto the types card_transactions stores,
+// treating untyped transactions and purchases as captures
+func cardTransactionType(obj map[string]interface{}) string {
+	switch txType := strings.ToLower(getStringField(obj, "type")); txType {
+	case "refund", "return":
+		return "refund"
+	case "reversal", "authorization":
+		return txType
+	}
+	return "capture"
+}
+
+// newCardAuthorizationEvent reads the merchant, currency and channel details of a card authorization
+func newCardAuthorizationEvent(obj map[string]interface{}, cardAccountID, transactionID string, amount decimal.Decimal) *BridgeCardAuthorizationEvent {
+	return &BridgeCardAuthorizationEvent{
+		CardAccountID:    cardAccountID,
+		TransactionID:    transactionID,
+		Amount:           amount,
+		Currency:         strings.ToUpper(getStringField(obj, "currency")),
+		MerchantName:     getStringField(obj, "merchant_name"),
+		MerchantCategory: getStringField(obj, "merchant_category"),
+		MerchantCountry:  strings.ToUpper(getStringField(obj, "merchant_country")),
+		CardNotPresent:   isCardNotPresent(obj),
+	}
+}
+
+// isCardNotPresent reports whether Bridge marked the authorization as online or otherwise card-not-present
+func isCardNotPresent(obj map[string]interface{}) bool {
+	if present, ok := obj["card_present"].(bool); ok {
+		return !present
+	}
+	switch strings.ToLower(getStringField(obj, "entry_mode")) {
+	case "ecommerce", "online", "card_not_present", "manual":
+		return true
+	}
+	return false
+}
+
 // getStringField safely extracts a string field from a map
 func getStringField(obj map[string]interface{}, key string) string {
 	if val, ok := obj[key]; ok {
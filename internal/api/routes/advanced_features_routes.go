@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"database/sql"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rail-service/rail_service/internal/api/handlers"
 	"github.com/rail-service/rail_service/internal/api/middleware"
@@ -102,3 +104,36 @@ func RegisterRoundupRoutes(
 		roundups.POST("/collect", roundupHandlers.CollectRoundups)
 	}
 }
+
+// RegisterRewardsRoutes registers cashback rewards routes
+func RegisterRewardsRoutes(
+	router *gin.RouterGroup,
+	rewardsHandlers *handlers.RewardsHandlers,
+	cfg *config.Config,
+	log *logger.Logger,
+	sessionValidator middleware.SessionValidator,
+	db *sql.DB,
+) {
+	if rewardsHandlers == nil {
+		return
+	}
+
+	rewards := router.Group("/rewards")
+	rewards.Use(middleware.Authentication(cfg, log, sessionValidator))
+	{
+		rewards.GET("/summary", rewardsHandlers.GetSummary)
+		rewards.GET("/cashback", rewardsHandlers.GetRewards)
+		rewards.GET("/offers", rewardsHandlers.GetOffers)
+	}
+
+	adminRewards := router.Group("/admin/rewards")
+	adminRewards.Use(middleware.Authentication(cfg, log, sessionValidator))
+	adminRewards.Use(middleware.AdminAuth(db, log))
+	{
+		adminRewards.GET("/offers", rewardsHandlers.AdminListOffers)
+		adminRewards.POST("/offers", rewardsHandlers.AdminCreateOffer)
+		adminRewards.PATCH("/offers/:id", rewardsHandlers.AdminUpdateOffer)
+		adminRewards.GET("/funding", rewardsHandlers.AdminGetFunding)
+		adminRewards.POST("/funding", rewardsHandlers.AdminFundPool)
+	}
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"database/sql"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rail-service/rail_service/internal/api/handlers"
 	"github.com/rail-service/rail_service/internal/api/middleware"
@@ -15,6 +17,7 @@ func RegisterCardRoutes(
 	cfg *config.Config,
 	log *logger.Logger,
 	sessionValidator middleware.SessionValidator,
+	db *sql.DB,
 ) {
 	if cardHandlers == nil {
 		log.Warn("Card handlers not initialized, skipping card routes")
@@ -31,8 +34,21 @@ func RegisterCardRoutes(
 		// Create a new card (virtual)
 		cards.POST("", cardHandlers.CreateCard)
 		
+		// Order a physical card
+		cards.POST("/physical", cardHandlers.OrderPhysicalCard)
+		
+		// Create a single-use virtual card
+		cards.POST("/single-use", cardHandlers.CreateSingleUseCard)
+		
 		// Get all card transactions for user
 		cards.GET("/transactions", cardHandlers.GetAllTransactions)
+		cards.PATCH("/transactions/:txId/category", cardHandlers.RecategorizeTransaction)
+		
+		// Transaction disputes
+		cards.POST("/disputes", cardHandlers.OpenDispute)
+		cards.GET("/disputes", cardHandlers.ListDisputes)
+		cards.GET("/disputes/:disputeId", cardHandlers.GetDispute)
+		cards.POST("/disputes/:disputeId/evidence", cardHandlers.AddDisputeEvidence)
 		
 		// Get specific card
 		cards.GET("/:id", cardHandlers.GetCard)
@@ -45,5 +61,28 @@ func RegisterCardRoutes(
 		
 		// Get card transactions
 		cards.GET("/:id/transactions", cardHandlers.GetCardTransactions)
+		
+		// Card controls (limits, merchant restrictions, channel toggles)
+		cards.GET("/:id/controls", cardHandlers.GetCardControls)
+		cards.PATCH("/:id/controls", cardHandlers.UpdateCardControls)
+		
+		// Physical card shipment tracking
+		cards.GET("/:id/shipment", cardHandlers.GetCardShipment)
+		
+		// Activate a received physical card
+		cards.POST("/:id/activate", cardHandlers.ActivateCard)
+		
+		// Replace a lost, stolen or damaged card
+		cards.POST("/:id/replace", cardHandlers.ReplaceCard)
+	}
+
+	// Admin dispute operations
+	adminCards := v1.Group("/admin/cards")
+	adminCards.Use(middleware.Authentication(cfg, log, sessionValidator))
+	adminCards.Use(middleware.AdminAuth(db, log))
+	{
+		adminCards.GET("/disputes", cardHandlers.AdminListDisputes)
+		adminCards.POST("/disputes/:disputeId/review", cardHandlers.AdminReviewDispute)
+		adminCards.POST("/disputes/:disputeId/resolve", cardHandlers.AdminResolveDispute)
 	}
 }
@@ -16,8 +16,23 @@ func SetupCopyTradingRoutes(rg *gin.RouterGroup, copyTradingHandlers *handlers.C
 		conductors := copy.Group("/conductors")
 		{
 			conductors.GET("", copyTradingHandlers.ListConductors)
+			conductors.GET("/me/earnings", copyTradingHandlers.GetMyEarnings)
+			conductors.PUT("/me/fees", copyTradingHandlers.UpdateMyFees)
 			conductors.GET("/:id", copyTradingHandlers.GetConductor)
 			conductors.GET("/:id/signals", copyTradingHandlers.GetConductorSignals)
+			conductors.GET("/:id/performance", copyTradingHandlers.GetConductorPerformance)
+			conductors.GET("/:id/tracks", copyTradingHandlers.GetConductorTracks)
+		}
+
+		// Track routes (curated portfolios followers mirror)
+		tracks := copy.Group("/tracks")
+		{
+			tracks.GET("", copyTradingHandlers.ListTracks)
+			tracks.POST("", copyTradingHandlers.CreateTrack)
+			tracks.GET("/:id", copyTradingHandlers.GetTrack)
+			tracks.PUT("/:id", copyTradingHandlers.UpdateTrack)
+			tracks.DELETE("/:id", copyTradingHandlers.DeleteTrack)
+			tracks.GET("/:id/versions", copyTradingHandlers.GetTrackVersions)
 		}
 
 		// Draft routes (user's copy relationships)
@@ -31,6 +46,28 @@ func SetupCopyTradingRoutes(rg *gin.RouterGroup, copyTradingHandlers *handlers.C
 			drafts.POST("/:id/resume", copyTradingHandlers.ResumeDraft)
 			drafts.PUT("/:id/resize", copyTradingHandlers.ResizeDraft)
 			drafts.GET("/:id/history", copyTradingHandlers.GetDraftHistory)
+			drafts.GET("/:id/fees", copyTradingHandlers.GetDraftFees)
+			drafts.POST("/:id/sync", copyTradingHandlers.SyncDraft)
+			drafts.PUT("/:id/risk", copyTradingHandlers.UpdateDraftRisk)
 		}
 	}
 }
+
+// SetupCopyTradingAdminRoutes configures copy trading admin routes on an admin-authenticated group
+func SetupCopyTradingAdminRoutes(admin *gin.RouterGroup, copyTradingHandlers *handlers.CopyTradingHandlers) {
+	copy := admin.Group("/copy")
+	{
+		copy.GET("/applications", copyTradingHandlers.ListPendingApplications)
+		copy.POST("/applications/:id/review", copyTradingHandlers.ReviewApplication)
+
+		// Compliance surveillance
+		copy.GET("/compliance/report", copyTradingHandlers.GetComplianceReport)
+		copy.GET("/conductors/:id/compliance-events", copyTradingHandlers.GetConductorComplianceEvents)
+		copy.POST("/conductors/:id/suspend", copyTradingHandlers.SuspendConductor)
+
+		// Blackout windows
+		copy.GET("/blackouts", copyTradingHandlers.ListBlackoutWindows)
+		copy.POST("/blackouts", copyTradingHandlers.CreateBlackoutWindow)
+		copy.DELETE("/blackouts/:id", copyTradingHandlers.DeleteBlackoutWindow)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rail-service/rail_service/internal/api/handlers"
+)
+
+// SetupDividendRoutes configures dividend history and reinvestment settings routes for users
+func SetupDividendRoutes(rg *gin.RouterGroup, dividendHandlers *handlers.DividendHandlers, authMiddleware gin.HandlerFunc) {
+	dividends := rg.Group("/dividends")
+	dividends.Use(authMiddleware)
+	{
+		dividends.GET("", dividendHandlers.GetDividends)
+		dividends.GET("/settings", dividendHandlers.GetSettings)
+		dividends.PUT("/settings", dividendHandlers.UpdateSettings)
+	}
+}
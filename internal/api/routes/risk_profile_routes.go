@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rail-service/rail_service/internal/api/handlers"
+)
+
+// SetupRiskProfileRoutes configures risk questionnaire and risk profile routes for users
+func SetupRiskProfileRoutes(rg *gin.RouterGroup, riskProfileHandlers *handlers.RiskProfileHandlers, authMiddleware gin.HandlerFunc) {
+	riskProfile := rg.Group("/risk-profile")
+	riskProfile.Use(authMiddleware)
+	{
+		riskProfile.GET("", riskProfileHandlers.GetMyProfile)
+		riskProfile.GET("/questionnaire", riskProfileHandlers.GetQuestionnaire)
+		riskProfile.GET("/assessments", riskProfileHandlers.GetMyAssessments)
+		riskProfile.POST("/assessments", riskProfileHandlers.SubmitAssessment)
+		riskProfile.POST("/acknowledgments", riskProfileHandlers.AcknowledgeRisk)
+	}
+}
+
+// SetupRiskProfileAdminRoutes configures risk questionnaire admin routes on an admin-authenticated group
+func SetupRiskProfileAdminRoutes(admin *gin.RouterGroup, riskProfileHandlers *handlers.RiskProfileHandlers) {
+	riskProfile := admin.Group("/risk-profile")
+	{
+		riskProfile.GET("/questionnaires", riskProfileHandlers.ListQuestionnaires)
+		riskProfile.POST("/questionnaires", riskProfileHandlers.PublishQuestionnaire)
+		riskProfile.GET("/users/:id", riskProfileHandlers.GetUserProfile)
+	}
+}
@@ -435,7 +435,9 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 							return
 						}
 						var req struct {
-							Amount string `json:"amount" binding:"required"`
+							Amount          string `json:"amount" binding:"required"`
+							AcknowledgeRisk bool   `json:"acknowledge_risk"`
+							IdempotencyKey  string `json:"idempotency_key"`
 						}
 						if err := c.ShouldBindJSON(&req); err != nil {
 							c.JSON(400, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
@@ -448,17 +450,46 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 						}
 						// Create order request
 						orderReq := &entities.OrderCreateRequest{
-							BasketID: basketID,
-							Side:     entities.OrderSideBuy,
-							Amount:   amount.String(),
+							BasketID:        basketID,
+							Side:            entities.OrderSideBuy,
+							Amount:          amount.String(),
+							AcknowledgeRisk: req.AcknowledgeRisk,
+						}
+						// Retries with the same key resume the original basket execution
+						if req.IdempotencyKey != "" {
+							orderReq.IdempotencyKey = &req.IdempotencyKey
 						}
 						order, err := investingService.CreateOrder(ctx, userID, orderReq)
+						if err == entities.ErrRiskAcknowledgmentRequired {
+							c.JSON(409, gin.H{"error": "RISK_ACKNOWLEDGMENT_REQUIRED", "message": err.Error()})
+							return
+						}
 						if err != nil {
 							c.JSON(500, gin.H{"error": "INVESTMENT_FAILED", "message": err.Error()})
 							return
 						}
 						c.JSON(201, gin.H{"order": order})
 					})
+					baskets.GET("/executions/:executionId", func(c *gin.Context) {
+						// Get basket execution report
+						ctx := c.Request.Context()
+						userID, _ := uuid.Parse(c.GetString("user_id"))
+						executionID, err := uuid.Parse(c.Param("executionId"))
+						if err != nil {
+							c.JSON(400, gin.H{"error": "INVALID_ID", "message": "Invalid execution ID"})
+							return
+						}
+						execution, err := basketExecutor.GetExecution(ctx, userID, executionID)
+						if err != nil {
+							c.JSON(500, gin.H{"error": "INTERNAL_ERROR", "message": "Failed to get basket execution"})
+							return
+						}
+						if execution == nil {
+							c.JSON(404, gin.H{"error": "NOT_FOUND", "message": "Basket execution not found"})
+							return
+						}
+						c.JSON(200, execution)
+					})
 				}
 			}
 
@@ -592,6 +623,27 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 				adminSecurity.POST("/blocked-countries", adminMFAHandlers.BlockCountry)
 				adminSecurity.DELETE("/blocked-countries/:country_code", adminMFAHandlers.UnblockCountry)
 			}
+
+			// Copy trading admin routes (conductor applications and compliance)
+			if copyTradingHandlers := container.GetCopyTradingHandlers(); copyTradingHandlers != nil {
+				SetupCopyTradingAdminRoutes(admin, copyTradingHandlers)
+			}
+
+			// Model portfolio admin routes
+			if strategyHandlers := container.GetStrategyHandlers(); strategyHandlers != nil {
+				SetupStrategyAdminRoutes(admin, strategyHandlers)
+			}
+
+			// Risk questionnaire admin routes
+			if riskProfileHandlers := container.GetRiskProfileHandlers(); riskProfileHandlers != nil {
+				SetupRiskProfileAdminRoutes(admin, riskProfileHandlers)
+			}
+
+			// Curated basket admin routes (versioned compositions)
+			adminHandlers := container.GetAdminHandlers()
+			admin.POST("/baskets", adminHandlers.CreateCuratedBasket)
+			admin.PUT("/baskets/:id", adminHandlers.UpdateCuratedBasket)
+			admin.GET("/baskets/:id/versions", adminHandlers.ListCuratedBasketVersions)
 		}
 
 		// Webhooks (external systems) - OpenAPI spec compliant
@@ -662,6 +714,16 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 			sessionValidator,
 		)
 
+		// Register cashback rewards routes
+		RegisterRewardsRoutes(
+			v1,
+			container.GetRewardsHandlers(),
+			container.Config,
+			container.Logger,
+			sessionValidator,
+			container.DB,
+		)
+
 		// Register copy trading routes
 		if container.GetCopyTradingHandlers() != nil {
 			copyTradingHandlers := container.GetCopyTradingHandlers()
@@ -669,6 +731,26 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 			SetupCopyTradingRoutes(v1, copyTradingHandlers, authMiddleware)
 		}
 
+		// Register auto-investment strategy routes
+		if strategyHandlers := container.GetStrategyHandlers(); strategyHandlers != nil {
+			SetupStrategyRoutes(v1, strategyHandlers, middleware.Authentication(container.Config, container.Logger, sessionValidator))
+		}
+
+		// Register risk profile routes
+		if riskProfileHandlers := container.GetRiskProfileHandlers(); riskProfileHandlers != nil {
+			SetupRiskProfileRoutes(v1, riskProfileHandlers, middleware.Authentication(container.Config, container.Logger, sessionValidator))
+		}
+
+		// Register tax lot and realized gains routes
+		if taxHandlers := container.GetTaxHandlers(); taxHandlers != nil {
+			SetupTaxRoutes(v1, taxHandlers, middleware.Authentication(container.Config, container.Logger, sessionValidator))
+		}
+
+		// Register dividend history and reinvestment routes
+		if dividendHandlers := container.GetDividendHandlers(); dividendHandlers != nil {
+			SetupDividendRoutes(v1, dividendHandlers, middleware.Authentication(container.Config, container.Logger, sessionValidator))
+		}
+
 		// Register card routes
 		RegisterCardRoutes(
 			v1,
@@ -676,6 +758,7 @@ func SetupRoutes(container *di.Container) *gin.Engine {
 			container.Config,
 			container.Logger,
 			sessionValidator,
+			container.DB,
 		)
 	}
 
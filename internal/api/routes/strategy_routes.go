@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rail-service/rail_service/internal/api/handlers"
+)
+
+// SetupStrategyRoutes configures auto-investment strategy routes for users
+func SetupStrategyRoutes(rg *gin.RouterGroup, strategyHandlers *handlers.StrategyHandlers, authMiddleware gin.HandlerFunc) {
+	strategy := rg.Group("/strategy")
+	strategy.Use(authMiddleware)
+	{
+		strategy.GET("/assignments", strategyHandlers.GetMyAssignments)
+	}
+}
+
+// SetupStrategyAdminRoutes configures model portfolio admin routes on an admin-authenticated group
+func SetupStrategyAdminRoutes(admin *gin.RouterGroup, strategyHandlers *handlers.StrategyHandlers) {
+	strategy := admin.Group("/strategy")
+	{
+		strategy.GET("/portfolios", strategyHandlers.ListModelPortfolios)
+		strategy.POST("/portfolios", strategyHandlers.CreateModelPortfolio)
+		strategy.GET("/portfolios/:id/versions", strategyHandlers.GetModelPortfolioVersions)
+		strategy.POST("/portfolios/:id/versions", strategyHandlers.PublishModelPortfolioVersion)
+		strategy.PUT("/portfolios/:id/status", strategyHandlers.UpdateModelPortfolioStatus)
+		strategy.GET("/users/:id/assignments", strategyHandlers.GetUserAssignments)
+	}
+}
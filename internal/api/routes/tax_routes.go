@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rail-service/rail_service/internal/api/handlers"
+)
+
+// SetupTaxRoutes configures tax lot, realized gains and harvest routes for users
+func SetupTaxRoutes(rg *gin.RouterGroup, taxHandlers *handlers.TaxHandlers, authMiddleware gin.HandlerFunc) {
+	tax := rg.Group("/tax")
+	tax.Use(authMiddleware)
+	{
+		tax.GET("/lots", taxHandlers.GetLots)
+		tax.POST("/lot-selections", taxHandlers.SelectLots)
+		tax.GET("/settings", taxHandlers.GetSettings)
+		tax.PUT("/settings", taxHandlers.UpdateSettings)
+		tax.GET("/realized-gains", taxHandlers.GetRealizedGains)
+		tax.GET("/harvests", taxHandlers.GetHarvests)
+	}
+}
@@ -16,9 +16,18 @@ import (
 	"github.com/rail-service/rail_service/internal/infrastructure/config"
 	"github.com/rail-service/rail_service/internal/infrastructure/database"
 	"github.com/rail-service/rail_service/internal/infrastructure/di"
+	auto_invest_worker "github.com/rail-service/rail_service/internal/workers/auto_invest_worker"
+	basket_version_worker "github.com/rail-service/rail_service/internal/workers/basket_version_worker"
+	cashback_worker "github.com/rail-service/rail_service/internal/workers/cashback_worker"
+	conductor_performance_worker "github.com/rail-service/rail_service/internal/workers/conductor_performance_worker"
+	copy_trading_fee_worker "github.com/rail-service/rail_service/internal/workers/copy_trading_fee_worker"
+	dividend_reinvest_worker "github.com/rail-service/rail_service/internal/workers/dividend_reinvest_worker"
+	draft_risk_worker "github.com/rail-service/rail_service/internal/workers/draft_risk_worker"
 	"github.com/rail-service/rail_service/internal/workers/funding_webhook"
 	portfolio_snapshot_worker "github.com/rail-service/rail_service/internal/workers/portfolio_snapshot_worker"
+	risk_reassessment_worker "github.com/rail-service/rail_service/internal/workers/risk_reassessment_worker"
 	scheduled_investment_worker "github.com/rail-service/rail_service/internal/workers/scheduled_investment_worker"
+	tax_harvest_worker "github.com/rail-service/rail_service/internal/workers/tax_harvest_worker"
 	walletprovisioning "github.com/rail-service/rail_service/internal/workers/wallet_provisioning"
 	"github.com/rail-service/rail_service/pkg/logger"
 	"github.com/rail-service/rail_service/pkg/metrics"
@@ -33,10 +42,19 @@ type Application struct {
 	container *di.Container
 
 	// Workers
-	scheduler                 *walletprovisioning.Scheduler
-	webhookManager            *funding_webhook.Manager
-	scheduledInvestmentWorker *scheduled_investment_worker.Worker
-	portfolioSnapshotWorker   *portfolio_snapshot_worker.Worker
+	scheduler                  *walletprovisioning.Scheduler
+	webhookManager             *funding_webhook.Manager
+	scheduledInvestmentWorker  *scheduled_investment_worker.Worker
+	portfolioSnapshotWorker    *portfolio_snapshot_worker.Worker
+	cashbackWorker             *cashback_worker.Worker
+	copyTradingFeeWorker       *copy_trading_fee_worker.Worker
+	conductorPerformanceWorker *conductor_performance_worker.Worker
+	draftRiskWorker            *draft_risk_worker.Worker
+	riskReassessmentWorker     *risk_reassessment_worker.Worker
+	basketVersionWorker        *basket_version_worker.Worker
+	taxHarvestWorker           *tax_harvest_worker.Worker
+	dividendReinvestWorker     *dividend_reinvest_worker.Worker
+	autoInvestWorker           *auto_invest_worker.Worker
 
 	// Tracing
 	tracingShutdown func(context.Context) error
@@ -140,6 +158,7 @@ func (app *Application) initializeWorkers() error {
 		app.scheduledInvestmentWorker = scheduled_investment_worker.NewWorker(
 			app.container.GetScheduledInvestmentService(),
 			app.container.GetMarketDataService(),
+			app.container.GetMarketCalendar(),
 			app.log.Zap(),
 		)
 		go app.scheduledInvestmentWorker.Start(context.Background())
@@ -151,12 +170,120 @@ func (app *Application) initializeWorkers() error {
 		app.portfolioSnapshotWorker = portfolio_snapshot_worker.NewWorker(
 			app.container.GetPortfolioAnalyticsService(),
 			app.container,
+			app.container.GetMarketCalendar(),
 			app.log.Zap(),
 		)
 		go app.portfolioSnapshotWorker.Start(context.Background())
 		app.log.Info("Portfolio snapshot worker started")
 	}
 
+	// Cashback payout worker
+	if app.container.GetRewardsService() != nil {
+		app.cashbackWorker = cashback_worker.NewWorker(
+			app.container.GetRewardsService(),
+			cashback_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.cashbackWorker.Start(context.Background())
+		app.log.Info("Cashback payout worker started")
+	}
+
+	// Copy trading fee worker
+	if app.container.GetCopyTradingService() != nil {
+		app.copyTradingFeeWorker = copy_trading_fee_worker.NewWorker(
+			app.container.GetCopyTradingService(),
+			copy_trading_fee_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.copyTradingFeeWorker.Start(context.Background())
+		app.log.Info("Copy trading fee worker started")
+	}
+
+	// Conductor performance worker
+	if app.container.GetCopyTradingService() != nil {
+		app.conductorPerformanceWorker = conductor_performance_worker.NewWorker(
+			app.container.GetCopyTradingService(),
+			app.container.GetMarketCalendar(),
+			conductor_performance_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.conductorPerformanceWorker.Start(context.Background())
+		app.log.Info("Conductor performance worker started")
+	}
+
+	// Draft risk worker
+	if app.container.GetCopyTradingService() != nil {
+		app.draftRiskWorker = draft_risk_worker.NewWorker(
+			app.container.GetCopyTradingService(),
+			app.container.GetMarketCalendar(),
+			draft_risk_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.draftRiskWorker.Start(context.Background())
+		app.log.Info("Draft risk worker started")
+	}
+
+	// Risk reassessment worker
+	if app.container.GetRiskProfileService() != nil {
+		app.riskReassessmentWorker = risk_reassessment_worker.NewWorker(
+			app.container.GetRiskProfileService(),
+			risk_reassessment_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.riskReassessmentWorker.Start(context.Background())
+		app.log.Info("Risk reassessment worker started")
+	}
+
+	// Basket version worker
+	if app.container.GetBasketCatalogService() != nil {
+		app.basketVersionWorker = basket_version_worker.NewWorker(
+			app.container.GetBasketCatalogService(),
+			app.container.GetMarketCalendar(),
+			basket_version_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.basketVersionWorker.Start(context.Background())
+		app.log.Info("Basket version worker started")
+	}
+
+	// Tax-loss harvest worker
+	if app.cfg.TaxHarvesting.Enabled && app.container.GetTaxHarvestingService() != nil {
+		harvestConfig := tax_harvest_worker.DefaultConfig()
+		if app.cfg.TaxHarvesting.IntervalMinutes > 0 {
+			harvestConfig.Interval = time.Duration(app.cfg.TaxHarvesting.IntervalMinutes) * time.Minute
+		}
+		app.taxHarvestWorker = tax_harvest_worker.NewWorker(
+			app.container.GetTaxHarvestingService(),
+			app.container.GetMarketCalendar(),
+			harvestConfig,
+			app.log.Zap(),
+		)
+		go app.taxHarvestWorker.Start(context.Background())
+		app.log.Info("Tax harvest worker started")
+	}
+
+	// Dividend reinvestment worker
+	if app.container.GetDividendService() != nil {
+		app.dividendReinvestWorker = dividend_reinvest_worker.NewWorker(
+			app.container.GetDividendService(),
+			dividend_reinvest_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.dividendReinvestWorker.Start(context.Background())
+		app.log.Info("Dividend reinvestment worker started")
+	}
+
+	// Deferred auto-investment worker
+	if app.container.GetAutoInvestService() != nil {
+		app.autoInvestWorker = auto_invest_worker.NewWorker(
+			app.container.GetAutoInvestService(),
+			auto_invest_worker.DefaultConfig(),
+			app.log.Zap(),
+		)
+		go app.autoInvestWorker.Start(context.Background())
+		app.log.Info("Deferred auto-investment worker started")
+	}
+
 	return nil
 }
 
@@ -363,6 +490,60 @@ func (app *Application) stopWorkers() {
 		app.log.Info("Stopping portfolio snapshot worker...")
 		app.portfolioSnapshotWorker.Stop()
 	}
+
+	// Stop cashback payout worker
+	if app.cashbackWorker != nil {
+		app.log.Info("Stopping cashback payout worker...")
+		app.cashbackWorker.Stop()
+	}
+
+	// Stop copy trading fee worker
+	if app.copyTradingFeeWorker != nil {
+		app.log.Info("Stopping copy trading fee worker...")
+		app.copyTradingFeeWorker.Stop()
+	}
+
+	// Stop conductor performance worker
+	if app.conductorPerformanceWorker != nil {
+		app.log.Info("Stopping conductor performance worker...")
+		app.conductorPerformanceWorker.Stop()
+	}
+
+	// Stop draft risk worker
+	if app.draftRiskWorker != nil {
+		app.log.Info("Stopping draft risk worker...")
+		app.draftRiskWorker.Stop()
+	}
+
+	// Stop risk reassessment worker
+	if app.riskReassessmentWorker != nil {
+		app.log.Info("Stopping risk reassessment worker...")
+		app.riskReassessmentWorker.Stop()
+	}
+
+	// Stop basket version worker
+	if app.basketVersionWorker != nil {
+		app.log.Info("Stopping basket version worker...")
+		app.basketVersionWorker.Stop()
+	}
+
+	// Stop tax harvest worker
+	if app.taxHarvestWorker != nil {
+		app.log.Info("Stopping tax harvest worker...")
+		app.taxHarvestWorker.Stop()
+	}
+
+	// Stop dividend reinvestment worker
+	if app.dividendReinvestWorker != nil {
+		app.log.Info("Stopping dividend reinvestment worker...")
+		app.dividendReinvestWorker.Stop()
+	}
+
+	// Stop deferred auto-investment worker
+	if app.autoInvestWorker != nil {
+		app.log.Info("Stopping deferred auto-investment worker...")
+		app.autoInvestWorker.Stop()
+	}
 }
 
 // WaitForShutdown waits for interrupt signal
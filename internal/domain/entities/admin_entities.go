@@ -127,4 +127,6 @@ type CuratedBasketRequest struct {
 	Description string            `json:"description" binding:"required"`
 	RiskLevel   RiskLevel         `json:"riskLevel" binding:"required"`
 	Composition []BasketComponent `json:"composition" binding:"required,dive"`
+	// EffectiveAt schedules a composition change; changes without it apply immediately
+	EffectiveAt *time.Time `json:"effectiveAt,omitempty"`
 }
@@ -15,6 +15,7 @@ const (
 	ContributionTypeRoundup  ContributionType = "roundup"
 	ContributionTypeCashback ContributionType = "cashback"
 	ContributionTypeReferral ContributionType = "referral"
+	ContributionTypeDividend ContributionType = "dividend"
 )
 
 // UserContribution represents a user's financial contribution
@@ -141,6 +141,45 @@ type AlpacaPositionEvent struct {
 	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
+// AlpacaNonTradeActivityEvent represents a non-trade activity (dividend, fee, interest, etc.).
+// Webhooks name the activity type entry_type; the activities API names it activity_type.
+type AlpacaNonTradeActivityEvent struct {
+	ID             string          `json:"id"`
+	AccountID      string          `json:"account_id"`
+	ActivityType   string          `json:"activity_type,omitempty"`
+	EntryType      string          `json:"entry_type,omitempty"`
+	Symbol         string          `json:"symbol,omitempty"`
+	NetAmount      decimal.Decimal `json:"net_amount"`
+	Qty            decimal.Decimal `json:"qty,omitempty"`
+	PerShareAmount decimal.Decimal `json:"per_share_amount,omitempty"`
+	Date           string          `json:"date,omitempty"`
+	SettleDate     string          `json:"settle_date,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	Status         string          `json:"status,omitempty"`
+}
+
+// Type returns the activity type, e.g. DIV or DIVNRA
+func (e *AlpacaNonTradeActivityEvent) Type() string {
+	if e.ActivityType != "" {
+		return e.ActivityType
+	}
+	return e.EntryType
+}
+
+// PayDate returns the day the activity was paid, preferring the settle date
+func (e *AlpacaNonTradeActivityEvent) PayDate() time.Time {
+	for _, value := range []string{e.SettleDate, e.Date} {
+		if value == "" {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", value[:min(len(value), 10)]); err == nil {
+			return date
+		}
+	}
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 // AlpacaReconciliationReport represents portfolio reconciliation results
 type AlpacaReconciliationReport struct {
 	UserID            uuid.UUID                  `json:"user_id"`
@@ -266,6 +266,17 @@ type AlpacaAssetResponse struct {
 	PriceIncrement    *decimal.Decimal  `json:"price_increment,omitempty"`
 }
 
+// Alpaca Calendar Entities
+
+// AlpacaCalendarDay is one trading day from the exchange calendar, with times in exchange time
+type AlpacaCalendarDay struct {
+	Date         string `json:"date"`          // 2006-01-02
+	Open         string `json:"open"`          // 15:04
+	Close        string `json:"close"`         // 15:04
+	SessionOpen  string `json:"session_open"`  // 1504, start of pre-market
+	SessionClose string `json:"session_close"` // 1504, end of after-hours
+}
+
 // Alpaca Position Entities
 
 // AlpacaPositionResponse represents a position in a portfolio
@@ -378,15 +389,17 @@ type AlpacaInstantFundingLimitsResponse struct {
 
 // AlpacaJournalRequest represents a request to create a journal entry
 type AlpacaJournalRequest struct {
-	FromAccount                     string          `json:"from_account"`
-	ToAccount                       string          `json:"to_account"`
-	EntryType                       string          `json:"entry_type"` // JNLC (cash), JNLS (securities)
-	Amount                          decimal.Decimal `json:"amount"`
-	Description                     string          `json:"description,omitempty"`
-	TransmitterName                 string          `json:"transmitter_name,omitempty"`
-	TransmitterAccountNumber        string          `json:"transmitter_account_number,omitempty"`
-	TransmitterAddress              string          `json:"transmitter_address,omitempty"`
-	TransmitterFinancialInstitution string          `json:"transmitter_financial_institution,omitempty"`
+	FromAccount                     string           `json:"from_account"`
+	ToAccount                       string           `json:"to_account"`
+	EntryType                       string           `json:"entry_type"` // JNLC (cash), JNLS (securities)
+	Amount                          decimal.Decimal  `json:"amount"`
+	Symbol                          string           `json:"symbol,omitempty"` // JNLS only
+	Qty                             *decimal.Decimal `json:"qty,omitempty"`    // JNLS only
+	Description                     string           `json:"description,omitempty"`
+	TransmitterName                 string           `json:"transmitter_name,omitempty"`
+	TransmitterAccountNumber        string           `json:"transmitter_account_number,omitempty"`
+	TransmitterAddress              string           `json:"transmitter_address,omitempty"`
+	TransmitterFinancialInstitution string           `json:"transmitter_financial_institution,omitempty"`
 }
 
 // AlpacaJournalResponse represents the response for a journal entry
@@ -33,6 +33,8 @@ const (
 	AuditActionMFADisable       AuditAction = "mfa_disable"
 	AuditActionPermissionChange AuditAction = "permission_change"
 	AuditActionAdminAction      AuditAction = "admin_action"
+	AuditActionRiskAssessment   AuditAction = "risk_assessment"
+	AuditActionRiskAcknowledge  AuditAction = "risk_acknowledgment"
 )
 
 type AuditLog struct {
@@ -37,3 +37,13 @@ type AutoInvestEvent struct {
 	Error     *string          `json:"error,omitempty" db:"error"`
 	CreatedAt time.Time        `json:"created_at" db:"created_at"`
 }
+
+// DeferredAutoInvestment is an auto-investment held in the stash while the market is closed,
+// triggered again once it opens
+type DeferredAutoInvestment struct {
+	CorrelationID string    `json:"correlation_id" db:"correlation_id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	StashID       uuid.UUID `json:"stash_id" db:"stash_id"`
+	NotBefore     time.Time `json:"not_before" db:"not_before"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BasketExecutionStatus represents the progress of a basket investment across its legs
+type BasketExecutionStatus string
+
+const (
+	BasketExecutionStatusPending            BasketExecutionStatus = "pending"
+	BasketExecutionStatusExecuting          BasketExecutionStatus = "executing"
+	BasketExecutionStatusCompleted          BasketExecutionStatus = "completed"           // Every leg submitted
+	BasketExecutionStatusPartiallyCompleted BasketExecutionStatus = "partially_completed" // Some legs failed and were released
+	BasketExecutionStatusFailed             BasketExecutionStatus = "failed"              // Every leg failed and was released
+)
+
+// ValidBasketExecutionTransitions defines allowed execution status transitions
+var ValidBasketExecutionTransitions = map[BasketExecutionStatus][]BasketExecutionStatus{
+	BasketExecutionStatusPending:            {BasketExecutionStatusExecuting},
+	BasketExecutionStatusExecuting:          {BasketExecutionStatusCompleted, BasketExecutionStatusPartiallyCompleted, BasketExecutionStatusFailed},
+	BasketExecutionStatusCompleted:          {}, // Terminal
+	BasketExecutionStatusPartiallyCompleted: {}, // Terminal
+	BasketExecutionStatusFailed:             {}, // Terminal
+}
+
+// CanTransitionTo checks if transition to new status is allowed
+func (s BasketExecutionStatus) CanTransitionTo(newStatus BasketExecutionStatus) bool {
+	for _, status := range ValidBasketExecutionTransitions[s] {
+		if status == newStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal returns true once every leg has been submitted or released
+func (s BasketExecutionStatus) IsTerminal() bool {
+	return s == BasketExecutionStatusCompleted || s == BasketExecutionStatusPartiallyCompleted || s == BasketExecutionStatusFailed
+}
+
+// ValidateTransition validates and returns error if transition is invalid
+func (s BasketExecutionStatus) ValidateTransition(newStatus BasketExecutionStatus) error {
+	if !s.CanTransitionTo(newStatus) {
+		return fmt.Errorf("invalid basket execution transition from %s to %s", s, newStatus)
+	}
+	return nil
+}
+
+// BasketExecutionLegStatus represents the state of a single order within a basket execution
+type BasketExecutionLegStatus string
+
+const (
+	BasketExecutionLegStatusPending   BasketExecutionLegStatus = "pending"   // Not yet accepted by the broker; may be retrying
+	BasketExecutionLegStatusSubmitted BasketExecutionLegStatus = "submitted" // Order accepted by the broker
+	BasketExecutionLegStatusFailed    BasketExecutionLegStatus = "failed"    // Gave up; funds still reserved
+	BasketExecutionLegStatusReleased  BasketExecutionLegStatus = "released"  // Failed and funds returned to the user's balance
+)
+
+// ValidBasketExecutionLegTransitions defines allowed leg status transitions
+var ValidBasketExecutionLegTransitions = map[BasketExecutionLegStatus][]BasketExecutionLegStatus{
+	BasketExecutionLegStatusPending:   {BasketExecutionLegStatusSubmitted, BasketExecutionLegStatusFailed},
+	BasketExecutionLegStatusSubmitted: {}, // Terminal
+	BasketExecutionLegStatusFailed:    {BasketExecutionLegStatusReleased},
+	BasketExecutionLegStatusReleased:  {}, // Terminal
+}
+
+// CanTransitionTo checks if transition to new status is allowed
+func (s BasketExecutionLegStatus) CanTransitionTo(newStatus BasketExecutionLegStatus) bool {
+	for _, status := range ValidBasketExecutionLegTransitions[s] {
+		if status == newStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition validates and returns error if transition is invalid
+func (s BasketExecutionLegStatus) ValidateTransition(newStatus BasketExecutionLegStatus) error {
+	if !s.CanTransitionTo(newStatus) {
+		return fmt.Errorf("invalid basket execution leg transition from %s to %s", s, newStatus)
+	}
+	return nil
+}
+
+// BasketExecution is one basket investment split into per-symbol legs. Once it reaches a
+// terminal status it is the execution report: what was submitted and what was released.
+type BasketExecution struct {
+	ID              uuid.UUID             `json:"id" db:"id"`
+	UserID          uuid.UUID             `json:"user_id" db:"user_id"`
+	BasketID        *uuid.UUID            `json:"basket_id,omitempty" db:"basket_id"`
+	AlpacaAccountID string                `json:"alpaca_account_id" db:"alpaca_account_id"`
+	IdempotencyKey  string                `json:"idempotency_key" db:"idempotency_key"`
+	TotalAmount     decimal.Decimal       `json:"total_amount" db:"total_amount"`
+	SubmittedAmount decimal.Decimal       `json:"submitted_amount" db:"submitted_amount"`
+	ReleasedAmount  decimal.Decimal       `json:"released_amount" db:"released_amount"`
+	Status          BasketExecutionStatus `json:"status" db:"status"`
+	Legs            []*BasketExecutionLeg `json:"legs" db:"-"`
+	CreatedAt       time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at" db:"updated_at"`
+	CompletedAt     *time.Time            `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// BasketExecutionLeg is the order for one symbol of a basket execution. ClientOrderID is derived
+// from the execution and symbol so a retried submission can never create a second order.
+type BasketExecutionLeg struct {
+	ID            uuid.UUID                `json:"id" db:"id"`
+	ExecutionID   uuid.UUID                `json:"execution_id" db:"execution_id"`
+	Symbol        string                   `json:"symbol" db:"symbol"`
+	Percentage    decimal.Decimal          `json:"percentage" db:"percentage"`
+	Amount        decimal.Decimal          `json:"amount" db:"amount"`
+	ClientOrderID string                   `json:"client_order_id" db:"client_order_id"`
+	AlpacaOrderID *string                  `json:"alpaca_order_id,omitempty" db:"alpaca_order_id"`
+	Status        BasketExecutionLegStatus `json:"status" db:"status"`
+	Attempts      int                      `json:"attempts" db:"attempts"`
+	LastError     *string                  `json:"last_error,omitempty" db:"last_error"`
+	SubmittedAt   *time.Time               `json:"submitted_at,omitempty" db:"submitted_at"`
+	ReleasedAt    *time.Time               `json:"released_at,omitempty" db:"released_at"`
+	UpdatedAt     time.Time                `json:"updated_at" db:"updated_at"`
+}
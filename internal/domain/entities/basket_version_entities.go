@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BasketVersionStatus represents where a basket composition version is in its lifecycle
+type BasketVersionStatus string
+
+const (
+	BasketVersionStatusScheduled  BasketVersionStatus = "scheduled"  // Takes effect at EffectiveAt
+	BasketVersionStatusActive     BasketVersionStatus = "active"     // The composition new investments use
+	BasketVersionStatusSuperseded BasketVersionStatus = "superseded" // Replaced by a later version
+	BasketVersionStatusCancelled  BasketVersionStatus = "cancelled"  // Replaced before it took effect
+)
+
+// BasketVersion is one composition of a curated basket. Changing a basket's composition adds a
+// version rather than editing it in place, so holders can be migrated from the old one.
+type BasketVersion struct {
+	ID          uuid.UUID           `json:"id" db:"id"`
+	BasketID    uuid.UUID           `json:"basket_id" db:"basket_id"`
+	Version     int                 `json:"version" db:"version"`
+	Composition []BasketComponent   `json:"composition" db:"-"`
+	Status      BasketVersionStatus `json:"status" db:"status"`
+	EffectiveAt time.Time           `json:"effective_at" db:"effective_at"`
+	ActivatedAt *time.Time          `json:"activated_at,omitempty" db:"activated_at"`
+	CreatedBy   *uuid.UUID          `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+}
+
+// BasketHolderMigrationStatus represents the progress of moving a holder to a new composition
+type BasketHolderMigrationStatus string
+
+const (
+	BasketHolderMigrationStatusPending   BasketHolderMigrationStatus = "pending"
+	BasketHolderMigrationStatusCompleted BasketHolderMigrationStatus = "completed"
+	BasketHolderMigrationStatusFailed    BasketHolderMigrationStatus = "failed"
+)
+
+// MaxBasketMigrationAttempts is how many worker passes a holder migration gets to place its
+// remaining trades before it is marked failed
+const MaxBasketMigrationAttempts = 10
+
+// BasketMigrationTrade is one leg of a holder migration and, once placed, its order
+type BasketMigrationTrade struct {
+	RebalanceTradeOrder
+	OrderID  *uuid.UUID `json:"order_id,omitempty"`
+	PlacedAt *time.Time `json:"placed_at,omitempty"`
+}
+
+// BasketHolderMigration records the trades that move one holder's basket position from the
+// composition they bought into the basket's newly activated composition. The trades are planned
+// once and each leg records its order, so a pass that stops partway resumes with the legs left.
+type BasketHolderMigration struct {
+	ID          uuid.UUID                   `json:"id" db:"id"`
+	BasketID    uuid.UUID                   `json:"basket_id" db:"basket_id"`
+	UserID      uuid.UUID                   `json:"user_id" db:"user_id"`
+	FromVersion int                         `json:"from_version" db:"from_version"`
+	ToVersion   int                         `json:"to_version" db:"to_version"`
+	Status      BasketHolderMigrationStatus `json:"status" db:"status"`
+	Trades      []BasketMigrationTrade      `json:"trades" db:"-"`
+	Attempts    int                         `json:"attempts" db:"attempts"` // Passes that stopped on a failed trade
+	Error       *string                     `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time                   `json:"created_at" db:"created_at"`
+	CompletedAt *time.Time                  `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// BasketVersionChange is the result of saving a curated basket: the basket and, when its
+// composition changed, the new version that is active or scheduled
+type BasketVersionChange struct {
+	Basket  *Basket        `json:"basket"`
+	Version *BasketVersion `json:"version,omitempty"`
+}
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -104,10 +105,112 @@ type BridgeCard struct {
 	Currency         string     `json:"currency" db:"currency"`
 	Chain            string     `json:"chain" db:"chain"`
 	WalletAddress    string     `json:"wallet_address" db:"wallet_address"`
+	ActivatedAt      *time.Time `json:"activated_at,omitempty" db:"activated_at"`
+	ReplacesCardID   *uuid.UUID `json:"replaces_card_id,omitempty" db:"replaces_card_id"`
+	CancelReason     *string    `json:"cancel_reason,omitempty" db:"cancel_reason"`
 	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+
+	Controls *CardControls `json:"controls,omitempty" db:"-"` // Loaded separately from card_controls
+}
+
+// CardControls are user-managed spending controls applied to a single card
+type CardControls struct {
+	CardID               uuid.UUID        `json:"card_id"`
+	PerTransactionLimit  *decimal.Decimal `json:"per_transaction_limit,omitempty"`
+	DailyLimit           *decimal.Decimal `json:"daily_limit,omitempty"`
+	MonthlyLimit         *decimal.Decimal `json:"monthly_limit,omitempty"`
+	AllowedMCCs          []string         `json:"allowed_mccs"` // Empty allows all categories
+	BlockedMCCs          []string         `json:"blocked_mccs"`
+	MerchantLocked       bool             `json:"merchant_locked"`           // Only the first merchant used may charge the card
+	LockedMerchant       *string          `json:"locked_merchant,omitempty"` // Normalized merchant name captured on first use
+	SingleUse            bool             `json:"single_use"`                // Card is cancelled after its first capture
+	OnlineEnabled        bool             `json:"online_enabled"`
+	ATMEnabled           bool             `json:"atm_enabled"`
+	InternationalEnabled bool             `json:"international_enabled"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+}
+
+// DefaultCardControls returns the controls applied to a card that has never been customized
+func DefaultCardControls(cardID uuid.UUID) *CardControls {
+	return &CardControls{
+		CardID:               cardID,
+		AllowedMCCs:          []string{},
+		BlockedMCCs:          []string{},
+		OnlineEnabled:        true,
+		ATMEnabled:           true,
+		InternationalEnabled: true,
+	}
+}
+
+// CardShipmentStatus represents the delivery status of a physical card
+type CardShipmentStatus string
+
+const (
+	CardShipmentOrdered    CardShipmentStatus = "ordered"
+	CardShipmentProcessing CardShipmentStatus = "processing"
+	CardShipmentShipped    CardShipmentStatus = "shipped"
+	CardShipmentDelivered  CardShipmentStatus = "delivered"
+	CardShipmentReturned   CardShipmentStatus = "returned"
+	CardShipmentFailed     CardShipmentStatus = "failed"
+)
+
+// IsValid checks if the shipment status is valid
+func (s CardShipmentStatus) IsValid() bool {
+	switch s {
+	case CardShipmentOrdered, CardShipmentProcessing, CardShipmentShipped,
+		CardShipmentDelivered, CardShipmentReturned, CardShipmentFailed:
+		return true
+	}
+	return false
+}
+
+// Stage orders shipment statuses by how far the card has progressed; delivered, returned and
+// failed are final
+func (s CardShipmentStatus) Stage() int {
+	switch s {
+	case CardShipmentOrdered:
+		return 0
+	case CardShipmentProcessing:
+		return 1
+	case CardShipmentShipped:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// CardShipment tracks the order and delivery of a physical card
+type CardShipment struct {
+	ID             uuid.UUID          `json:"id" db:"id"`
+	CardID         uuid.UUID          `json:"card_id" db:"card_id"`
+	UserID         uuid.UUID          `json:"user_id" db:"user_id"`
+	StreetLine1    string             `json:"street_line_1" db:"street_line_1"`
+	StreetLine2    *string            `json:"street_line_2,omitempty" db:"street_line_2"`
+	City           string             `json:"city" db:"city"`
+	State          *string            `json:"state,omitempty" db:"state"`
+	PostalCode     string             `json:"postal_code" db:"postal_code"`
+	Country        string             `json:"country" db:"country"`
+	Status         CardShipmentStatus `json:"status" db:"status"`
+	Carrier        *string            `json:"carrier,omitempty" db:"carrier"`
+	TrackingNumber *string            `json:"tracking_number,omitempty" db:"tracking_number"`
+	TrackingURL    *string            `json:"tracking_url,omitempty" db:"tracking_url"`
+	ShippedAt      *time.Time         `json:"shipped_at,omitempty" db:"shipped_at"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at" db:"updated_at"`
 }
 
+// CardReplacementReason represents why a card is being replaced
+type CardReplacementReason string
+
+const (
+	CardReplacementLost    CardReplacementReason = "lost"
+	CardReplacementStolen  CardReplacementReason = "stolen"
+	CardReplacementDamaged CardReplacementReason = "damaged"
+)
+
 // BridgeCardTransaction represents a card transaction via Bridge
 type BridgeCardTransaction struct {
 	ID               uuid.UUID       `json:"id" db:"id"`
@@ -121,8 +224,223 @@ type BridgeCardTransaction struct {
 	MerchantCategory *string         `json:"merchant_category,omitempty" db:"merchant_category"`
 	Status           string          `json:"status" db:"status"` // pending, completed, declined, reversed
 	DeclineReason    *string         `json:"decline_reason,omitempty" db:"decline_reason"`
+	DeclineCode      *string         `json:"decline_code,omitempty" db:"decline_code"`
+	RiskScore        *float64        `json:"risk_score,omitempty" db:"risk_score"`
 	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Enrichment
+	MerchantDisplayName *string           `json:"merchant_display_name,omitempty" db:"merchant_display_name"`
+	MerchantKey         *string           `json:"-" db:"merchant_key"` // Normalized merchant used to match rules and recurring charges
+	Category            *SpendingCategory `json:"category,omitempty" db:"spending_category"`
+	CategorySource      *CategorySource   `json:"category_source,omitempty" db:"category_source"`
+	IsRecurring         bool              `json:"is_recurring" db:"is_recurring"`
+}
+
+// CardAuthorizationHoldWindow is how long an uncaptured authorization holds funds before it lapses
+const CardAuthorizationHoldWindow = 7 * 24 * time.Hour
+
+// CardSpend totals what a card's transactions count toward its spending limits: completed purchases
+// and captures, plus authorizations still on hold at now. Refunds, reversals and declines never
+// count, and an authorization captured under a separate transaction counts once, through the
+// capture at the same merchant for the same amount that followed it.
+func CardSpend(txs []*BridgeCardTransaction, now time.Time) decimal.Decimal {
+	sorted := make([]*BridgeCardTransaction, len(txs))
+	copy(sorted, txs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	holdCutoff := now.Add(-CardAuthorizationHoldWindow)
+	total := decimal.Zero
+	var held []*BridgeCardTransaction
+	for _, tx := range sorted {
+		if !tx.Amount.IsPositive() || (tx.Type != "authorization" && tx.Type != "capture") {
+			continue
+		}
+		switch CardTransactionStatus(tx.Status) {
+		case CardTxStatusPending:
+			if tx.Type == "authorization" && !tx.CreatedAt.Before(holdCutoff) {
+				held = append(held, tx)
+			}
+		case CardTxStatusCompleted:
+			total = total.Add(tx.Amount)
+			if tx.Type == "capture" {
+				held = releaseCapturedHold(held, tx)
+			}
+		}
+	}
+	for _, tx := range held {
+		total = total.Add(tx.Amount)
+	}
+	return total
+}
+
+// releaseCapturedHold drops the earliest held authorization a capture settles
+func releaseCapturedHold(held []*BridgeCardTransaction, capture *BridgeCardTransaction) []*BridgeCardTransaction {
+	for i, auth := range held {
+		if auth.Amount.Equal(capture.Amount) && sameMerchant(auth, capture) {
+			return append(held[:i], held[i+1:]...)
+		}
+	}
+	return held
+}
+
+func sameMerchant(a, b *BridgeCardTransaction) bool {
+	if a.MerchantKey != nil && b.MerchantKey != nil {
+		return *a.MerchantKey == *b.MerchantKey
+	}
+	return a.MerchantName != nil && b.MerchantName != nil && *a.MerchantName == *b.MerchantName
+}
+
+// RefundShare prorates an amount earned on a card purchase to the part of the purchase that was
+// refunded, rounded to cents and never more than the amount itself
+func RefundShare(amount, purchase, refund decimal.Decimal) decimal.Decimal {
+	if !purchase.IsPositive() || !refund.LessThan(purchase) {
+		return amount
+	}
+	return decimal.Min(amount.Mul(refund).Div(purchase).Round(2), amount)
+}
+
+// SpendingCategory is Rail's category taxonomy for card spend
+type SpendingCategory string
+
+const (
+	SpendingCategoryFoodAndDrink   SpendingCategory = "food_and_drink"
+	SpendingCategoryGroceries      SpendingCategory = "groceries"
+	SpendingCategoryShopping       SpendingCategory = "shopping"
+	SpendingCategoryTransportation SpendingCategory = "transportation"
+	SpendingCategoryTravel         SpendingCategory = "travel"
+	SpendingCategoryEntertainment  SpendingCategory = "entertainment"
+	SpendingCategorySubscriptions  SpendingCategory = "subscriptions"
+	SpendingCategoryHealth         SpendingCategory = "health"
+	SpendingCategoryUtilities      SpendingCategory = "utilities"
+	SpendingCategoryBills          SpendingCategory = "bills"
+	SpendingCategoryCash           SpendingCategory = "cash"
+	SpendingCategoryTransfers      SpendingCategory = "transfers"
+	SpendingCategoryFees           SpendingCategory = "fees"
+	SpendingCategoryOther          SpendingCategory = "other"
+)
+
+// spendingCategoryLabels maps categories to their display labels
+var spendingCategoryLabels = map[SpendingCategory]string{
+	SpendingCategoryFoodAndDrink:   "Food & Drink",
+	SpendingCategoryGroceries:      "Groceries",
+	SpendingCategoryShopping:       "Shopping",
+	SpendingCategoryTransportation: "Transportation",
+	SpendingCategoryTravel:         "Travel",
+	SpendingCategoryEntertainment:  "Entertainment",
+	SpendingCategorySubscriptions:  "Subscriptions",
+	SpendingCategoryHealth:         "Health",
+	SpendingCategoryUtilities:      "Utilities",
+	SpendingCategoryBills:          "Bills",
+	SpendingCategoryCash:           "Cash",
+	SpendingCategoryTransfers:      "Transfers",
+	SpendingCategoryFees:           "Fees",
+	SpendingCategoryOther:          "Other",
+}
+
+// IsValid checks if the category is part of the taxonomy
+func (c SpendingCategory) IsValid() bool {
+	_, ok := spendingCategoryLabels[c]
+	return ok
+}
+
+// Label returns the display label for the category
+func (c SpendingCategory) Label() string {
+	if label, ok := spendingCategoryLabels[c]; ok {
+		return label
+	}
+	return spendingCategoryLabels[SpendingCategoryOther]
+}
+
+// CategorySource records how a transaction's category was assigned
+type CategorySource string
+
+const (
+	CategorySourceMCC      CategorySource = "mcc"      // Derived from the merchant category code
+	CategorySourceMerchant CategorySource = "merchant" // Known merchant directory
+	CategorySourceRule     CategorySource = "rule"     // User's learned re-categorization rule
+	CategorySourceUser     CategorySource = "user"     // Set by the user on this transaction
+)
+
+// CardCategoryRule is a user's learned category override for a merchant
+type CardCategoryRule struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	UserID      uuid.UUID        `json:"user_id" db:"user_id"`
+	MerchantKey string           `json:"merchant_key" db:"merchant_key"`
+	Category    SpendingCategory `json:"category" db:"category"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// RecategorizeTransactionRequest represents a user's category correction
+type RecategorizeTransactionRequest struct {
+	Category        SpendingCategory `json:"category" binding:"required"`
+	ApplyToMerchant *bool            `json:"apply_to_merchant,omitempty"` // Defaults to true: remember for future and past charges
+}
+
+// CardDeclineCode is a structured reason returned when a card authorization is declined
+type CardDeclineCode string
+
+const (
+	CardDeclineCardNotFound         CardDeclineCode = "card_not_found"
+	CardDeclineCardFrozen           CardDeclineCode = "card_frozen"
+	CardDeclineCardCancelled        CardDeclineCode = "card_cancelled"
+	CardDeclineCardInactive         CardDeclineCode = "card_inactive"
+	CardDeclineInsufficientFunds    CardDeclineCode = "insufficient_funds"
+	CardDeclineBalanceCheckFailed   CardDeclineCode = "balance_check_failed"
+	CardDeclineMerchantBlocked      CardDeclineCode = "merchant_category_blocked"
+	CardDeclineTransactionLimit     CardDeclineCode = "transaction_limit_exceeded"
+	CardDeclineDailyLimit           CardDeclineCode = "daily_limit_exceeded"
+	CardDeclineMonthlyLimit         CardDeclineCode = "monthly_limit_exceeded"
+	CardDeclineMerchantLocked       CardDeclineCode = "merchant_locked"
+	CardDeclineOnlineDisabled       CardDeclineCode = "online_disabled"
+	CardDeclineATMDisabled          CardDeclineCode = "atm_disabled"
+	CardDeclineInternationalBlocked CardDeclineCode = "international_disabled"
+	CardDeclineCountryRestricted    CardDeclineCode = "country_restricted"
+	CardDeclineCurrencyRestricted   CardDeclineCode = "currency_restricted"
+	CardDeclineVelocityExceeded     CardDeclineCode = "velocity_exceeded"
+	CardDeclineSuspectedFraud       CardDeclineCode = "suspected_fraud"
+	CardDeclineAuthorizationTimeout CardDeclineCode = "authorization_timeout"
+	CardDeclineProcessingError      CardDeclineCode = "processing_error"
+)
+
+// validCardDeclineCodes contains all decline codes produced by the authorization pipeline
+var validCardDeclineCodes = map[CardDeclineCode]bool{
+	CardDeclineCardNotFound:         true,
+	CardDeclineCardFrozen:           true,
+	CardDeclineCardCancelled:        true,
+	CardDeclineCardInactive:         true,
+	CardDeclineInsufficientFunds:    true,
+	CardDeclineBalanceCheckFailed:   true,
+	CardDeclineMerchantBlocked:      true,
+	CardDeclineTransactionLimit:     true,
+	CardDeclineDailyLimit:           true,
+	CardDeclineMonthlyLimit:         true,
+	CardDeclineMerchantLocked:       true,
+	CardDeclineOnlineDisabled:       true,
+	CardDeclineATMDisabled:          true,
+	CardDeclineInternationalBlocked: true,
+	CardDeclineCountryRestricted:    true,
+	CardDeclineCurrencyRestricted:   true,
+	CardDeclineVelocityExceeded:     true,
+	CardDeclineSuspectedFraud:       true,
+	CardDeclineAuthorizationTimeout: true,
+	CardDeclineProcessingError:      true,
+}
+
+// IsValid checks if the decline code is a known code
+func (c CardDeclineCode) IsValid() bool {
+	return validCardDeclineCodes[c]
+}
+
+// CardAuthorizationDecision is the outcome of the card authorization pipeline
+type CardAuthorizationDecision struct {
+	Approved    bool            `json:"approved"`
+	DeclineCode CardDeclineCode `json:"decline_code,omitempty"`
+	DeclinedBy  string          `json:"declined_by,omitempty"` // Name of the rule that declined
+	RiskScore   float64         `json:"risk_score"`
+	Fallback    bool            `json:"fallback"` // Decision made by the stand-in fallback after the latency budget expired
+	LatencyMs   int64           `json:"latency_ms"`
 }
 
 // CreateCardRequest represents a request to create a card
@@ -174,9 +492,165 @@ type ShippingAddress struct {
 	Country     string `json:"country" binding:"required"`
 }
 
+// PhysicalCardResponse represents a physical card with its shipment
+type PhysicalCardResponse struct {
+	Card     *BridgeCard   `json:"card"`
+	Shipment *CardShipment `json:"shipment,omitempty"`
+}
+
+// ActivateCardRequest represents a request to activate a physical card
+type ActivateCardRequest struct {
+	Last4    string `json:"last_4" binding:"required,len=4,numeric"`
+	Passcode string `json:"passcode" binding:"required"`
+}
+
+// ReplaceCardRequest represents a request to replace a lost, stolen or damaged card
+type ReplaceCardRequest struct {
+	Reason          CardReplacementReason `json:"reason" binding:"required,oneof=lost stolen damaged"`
+	ShippingAddress *ShippingAddress      `json:"shipping_address,omitempty"`
+}
+
+// UpdateCardControlsRequest represents a partial update of card controls.
+// Omitted fields are left unchanged; a zero limit removes that limit.
+type UpdateCardControlsRequest struct {
+	PerTransactionLimit  *decimal.Decimal `json:"per_transaction_limit,omitempty"`
+	DailyLimit           *decimal.Decimal `json:"daily_limit,omitempty"`
+	MonthlyLimit         *decimal.Decimal `json:"monthly_limit,omitempty"`
+	AllowedMCCs          *[]string        `json:"allowed_mccs,omitempty"`
+	BlockedMCCs          *[]string        `json:"blocked_mccs,omitempty"`
+	MerchantLocked       *bool            `json:"merchant_locked,omitempty"`
+	OnlineEnabled        *bool            `json:"online_enabled,omitempty"`
+	ATMEnabled           *bool            `json:"atm_enabled,omitempty"`
+	InternationalEnabled *bool            `json:"international_enabled,omitempty"`
+}
+
+// CreateSingleUseCardRequest represents a request for a single-use virtual card
+type CreateSingleUseCardRequest struct {
+	Limit          *decimal.Decimal `json:"limit,omitempty"`
+	MerchantLocked bool             `json:"merchant_locked,omitempty"`
+}
+
 // CardTransactionListResponse represents a list of card transactions
 type CardTransactionListResponse struct {
 	Transactions []BridgeCardTransaction `json:"transactions"`
 	Total        int                     `json:"total"`
 	HasMore      bool                    `json:"has_more"`
 }
+
+// CardDisputeStatus represents the lifecycle state of a card transaction dispute
+type CardDisputeStatus string
+
+const (
+	CardDisputeSubmitted   CardDisputeStatus = "submitted"
+	CardDisputeUnderReview CardDisputeStatus = "under_review"
+	CardDisputeWon         CardDisputeStatus = "won"  // Terminal: chargeback succeeded, credit is final
+	CardDisputeLost        CardDisputeStatus = "lost" // Terminal: merchant prevailed, credit clawed back
+)
+
+// validCardDisputeTransitions defines the allowed dispute state transitions
+var validCardDisputeTransitions = map[CardDisputeStatus][]CardDisputeStatus{
+	CardDisputeSubmitted:   {CardDisputeUnderReview, CardDisputeWon, CardDisputeLost},
+	CardDisputeUnderReview: {CardDisputeWon, CardDisputeLost},
+}
+
+// CanTransitionTo checks if the dispute can move to the target status
+func (s CardDisputeStatus) CanTransitionTo(target CardDisputeStatus) bool {
+	for _, allowed := range validCardDisputeTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal returns true if the dispute has been resolved
+func (s CardDisputeStatus) IsTerminal() bool {
+	return s == CardDisputeWon || s == CardDisputeLost
+}
+
+// CardDisputeReason represents why a user disputes a card transaction
+type CardDisputeReason string
+
+const (
+	CardDisputeReasonUnauthorized     CardDisputeReason = "unauthorized"
+	CardDisputeReasonDuplicate        CardDisputeReason = "duplicate"
+	CardDisputeReasonNotReceived      CardDisputeReason = "not_received"
+	CardDisputeReasonNotAsDescribed   CardDisputeReason = "not_as_described"
+	CardDisputeReasonIncorrectAmount  CardDisputeReason = "incorrect_amount"
+	CardDisputeReasonCancelledService CardDisputeReason = "cancelled_service"
+	CardDisputeReasonOther            CardDisputeReason = "other"
+)
+
+// ProvisionalCreditStatus tracks the provisional credit posted while a dispute is open
+type ProvisionalCreditStatus string
+
+const (
+	ProvisionalCreditNone           ProvisionalCreditStatus = "none"
+	ProvisionalCreditPosted         ProvisionalCreditStatus = "posted"
+	ProvisionalCreditFinalized      ProvisionalCreditStatus = "finalized"
+	ProvisionalCreditReversed       ProvisionalCreditStatus = "reversed"
+	ProvisionalCreditReversalFailed ProvisionalCreditStatus = "reversal_failed" // Clawback needs manual follow-up
+)
+
+// CardDispute represents a user dispute of a card transaction
+type CardDispute struct {
+	ID                      uuid.UUID               `json:"id" db:"id"`
+	UserID                  uuid.UUID               `json:"user_id" db:"user_id"`
+	CardID                  uuid.UUID               `json:"card_id" db:"card_id"`
+	TransactionID           uuid.UUID               `json:"transaction_id" db:"transaction_id"`
+	Reason                  CardDisputeReason       `json:"reason" db:"reason"`
+	Description             string                  `json:"description" db:"description"`
+	Amount                  decimal.Decimal         `json:"amount" db:"amount"`
+	Currency                string                  `json:"currency" db:"currency"`
+	Status                  CardDisputeStatus       `json:"status" db:"status"`
+	ProvisionalCreditStatus ProvisionalCreditStatus `json:"provisional_credit_status" db:"provisional_credit_status"`
+	ProvisionalCreditTxID   *uuid.UUID              `json:"-" db:"provisional_credit_tx_id"`
+	ClawbackTxID            *uuid.UUID              `json:"-" db:"clawback_tx_id"`
+	ResolutionNote          *string                 `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedBy              *uuid.UUID              `json:"-" db:"resolved_by"`
+	ResolvedAt              *time.Time              `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt               time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time               `json:"updated_at" db:"updated_at"`
+}
+
+// CardDisputeEvidence is a supporting document attached to a dispute
+type CardDisputeEvidence struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	DisputeID   uuid.UUID `json:"dispute_id" db:"dispute_id"`
+	Kind        string    `json:"kind" db:"kind"` // receipt, correspondence, photo, other
+	Description string    `json:"description" db:"description"`
+	URL         *string   `json:"url,omitempty" db:"url"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// DisputeEvidenceInput represents evidence submitted with a dispute
+type DisputeEvidenceInput struct {
+	Kind        string `json:"kind" binding:"required,oneof=receipt correspondence photo other"`
+	Description string `json:"description" binding:"required,max=1000"`
+	URL         string `json:"url,omitempty" binding:"omitempty,url"`
+}
+
+// OpenCardDisputeRequest represents a request to dispute a card transaction
+type OpenCardDisputeRequest struct {
+	TransactionID uuid.UUID              `json:"transaction_id" binding:"required"`
+	Reason        CardDisputeReason      `json:"reason" binding:"required,oneof=unauthorized duplicate not_received not_as_described incorrect_amount cancelled_service other"`
+	Description   string                 `json:"description" binding:"required,max=2000"`
+	Evidence      []DisputeEvidenceInput `json:"evidence,omitempty" binding:"omitempty,max=10,dive"`
+}
+
+// AddDisputeEvidenceRequest represents a request to attach more evidence to an open dispute
+type AddDisputeEvidenceRequest struct {
+	Evidence []DisputeEvidenceInput `json:"evidence" binding:"required,min=1,max=10,dive"`
+}
+
+// ResolveCardDisputeRequest represents an operator decision on a dispute
+type ResolveCardDisputeRequest struct {
+	Outcome CardDisputeStatus `json:"outcome" binding:"required,oneof=won lost"`
+	Note    string            `json:"note,omitempty" binding:"max=2000"`
+}
+
+// CardDisputeResponse represents a dispute with its evidence
+type CardDisputeResponse struct {
+	Dispute  *CardDispute           `json:"dispute"`
+	Evidence []*CardDisputeEvidence `json:"evidence"`
+}
@@ -54,6 +54,32 @@ const (
 	ExecutionStatusSkippedTooSmall   ExecutionStatus = "skipped_too_small"
 	ExecutionStatusInsufficientFunds ExecutionStatus = "insufficient_funds"
 	ExecutionStatusFailed            ExecutionStatus = "failed"
+	ExecutionStatusSkippedRiskLimit  ExecutionStatus = "skipped_risk_limit"
+)
+
+// ExecutionType distinguishes copied signals from portfolio catch-up and risk liquidation orders
+type ExecutionType string
+
+const (
+	ExecutionTypeSignal      ExecutionType = "signal"
+	ExecutionTypeCatchUp     ExecutionType = "catch_up"
+	ExecutionTypeLiquidation ExecutionType = "liquidation"
+)
+
+// DraftRiskAction is what happens to a draft when it breaches one of its risk limits
+type DraftRiskAction string
+
+const (
+	DraftRiskActionPause  DraftRiskAction = "pause"
+	DraftRiskActionUnlink DraftRiskAction = "unlink" // Liquidate holdings and return the draft's value to the balance
+)
+
+// DraftMirrorMode controls how a draft follows its conductor
+type DraftMirrorMode string
+
+const (
+	DraftMirrorModeSignals   DraftMirrorMode = "signals"   // Scale each conductor trade into the draft
+	DraftMirrorModePortfolio DraftMirrorMode = "portfolio" // Also keep the draft at the conductor's portfolio weights
 )
 
 // Conductor represents a professional investor whose trades can be copied
@@ -64,12 +90,13 @@ type Conductor struct {
 	Bio            string          `json:"bio,omitempty" db:"bio"`
 	AvatarURL      string          `json:"avatar_url,omitempty" db:"avatar_url"`
 	Status         ConductorStatus `json:"status" db:"status"`
-	FeeRate        decimal.Decimal `json:"fee_rate" db:"fee_rate"`
+	FeeRate        decimal.Decimal `json:"fee_rate" db:"fee_rate"`                       // Performance fee on gains above the high-water mark
+	ManagementFee  decimal.Decimal `json:"management_fee_rate" db:"management_fee_rate"` // Annual fee on draft value, accrued daily
 	SourceAUM      decimal.Decimal `json:"source_aum" db:"source_aum"`
-	TotalReturn    decimal.Decimal `json:"total_return" db:"total_return"`
-	WinRate        decimal.Decimal `json:"win_rate" db:"win_rate"`
-	MaxDrawdown    decimal.Decimal `json:"max_drawdown" db:"max_drawdown"`
-	SharpeRatio    decimal.Decimal `json:"sharpe_ratio" db:"sharpe_ratio"`
+	TotalReturn    decimal.Decimal `json:"total_return" db:"total_return"` // Time-weighted, as a fraction (0.12 = 12%)
+	WinRate        decimal.Decimal `json:"win_rate" db:"win_rate"`         // Share of closed round-trips that were profitable
+	MaxDrawdown    decimal.Decimal `json:"max_drawdown" db:"max_drawdown"` // Largest peak-to-trough decline, as a positive fraction
+	SharpeRatio    decimal.Decimal `json:"sharpe_ratio" db:"sharpe_ratio"` // Annualized from daily returns
 	TotalTrades    int             `json:"total_trades" db:"total_trades"`
 	FollowersCount int             `json:"followers_count" db:"followers_count"`
 	MinDraftAmount decimal.Decimal `json:"min_draft_amount" db:"min_draft_amount"`
@@ -91,13 +118,24 @@ type Draft struct {
 	StartValue       decimal.Decimal `json:"start_value" db:"start_value"`
 	TotalProfitLoss  decimal.Decimal `json:"total_profit_loss" db:"total_profit_loss"`
 	TotalFeesPaid    decimal.Decimal `json:"total_fees_paid" db:"total_fees_paid"`
+	HighWaterMark    decimal.Decimal `json:"high_water_mark" db:"high_water_mark"` // Value above which performance fees are charged
+	AccruedFees      decimal.Decimal `json:"accrued_fees" db:"accrued_fees"`       // Accrued but not yet crystallized fees
+	LastFeeAccrualAt *time.Time      `json:"last_fee_accrual_at,omitempty" db:"last_fee_accrual_at"`
+	LastCrystallized *time.Time      `json:"last_crystallized_at,omitempty" db:"last_crystallized_at"`
 	CopyRatio        decimal.Decimal `json:"copy_ratio" db:"copy_ratio"`
 	AutoAdjust       bool            `json:"auto_adjust" db:"auto_adjust"`
+	MirrorMode       DraftMirrorMode `json:"mirror_mode" db:"mirror_mode"`
+	TrackID          *uuid.UUID      `json:"track_id,omitempty" db:"track_id"` // Mirror this track's allocations instead of the conductor's holdings
+	PeakValue        decimal.Decimal `json:"peak_value" db:"peak_value"`       // Highest value seen, for the trailing stop
+	RiskBreachReason string          `json:"risk_breach_reason,omitempty" db:"risk_breach_reason"`
 	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
 	PausedAt         *time.Time      `json:"paused_at,omitempty" db:"paused_at"`
 	UnlinkedAt       *time.Time      `json:"unlinked_at,omitempty" db:"unlinked_at"`
 
+	// Drafter's risk limits
+	DraftRiskSettings
+
 	// Joined fields for API responses
 	Conductor *Conductor `json:"conductor,omitempty" db:"-"`
 }
@@ -120,13 +158,20 @@ type Signal struct {
 	FailedCount         int             `json:"failed_count" db:"failed_count"`
 	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
 	CompletedAt         *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+
+	// Set on REBALANCE signals, which carry a track's new allocation version instead of a trade
+	TrackID      *uuid.UUID `json:"track_id,omitempty" db:"track_id"`
+	TrackVersion *int       `json:"track_version,omitempty" db:"track_version"`
 }
 
 // SignalExecutionLog tracks the execution of a copied trade for each drafter
 type SignalExecutionLog struct {
 	ID               uuid.UUID       `json:"id" db:"id"`
 	DraftID          uuid.UUID       `json:"draft_id" db:"draft_id"`
-	SignalID         uuid.UUID       `json:"signal_id" db:"signal_id"`
+	SignalID         *uuid.UUID      `json:"signal_id,omitempty" db:"signal_id"` // Nil for catch-up orders
+	ExecutionType    ExecutionType   `json:"execution_type" db:"execution_type"`
+	AssetTicker      string          `json:"asset_ticker" db:"asset_ticker"`
+	Side             string          `json:"side" db:"side"`
 	ExecutedQuantity decimal.Decimal `json:"executed_quantity" db:"executed_quantity"`
 	ExecutedPrice    decimal.Decimal `json:"executed_price" db:"executed_price"`
 	ExecutedValue    decimal.Decimal `json:"executed_value" db:"executed_value"`
@@ -134,6 +179,7 @@ type SignalExecutionLog struct {
 	FeeApplied       decimal.Decimal `json:"fee_applied" db:"fee_applied"`
 	ErrorMessage     string          `json:"error_message,omitempty" db:"error_message"`
 	OrderID          string          `json:"order_id,omitempty" db:"order_id"`
+	BlockOrderID     *uuid.UUID      `json:"block_order_id,omitempty" db:"block_order_id"` // Set when filled through an aggregated block order
 	IdempotencyKey   string          `json:"idempotency_key" db:"idempotency_key"`
 	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
 	ExecutedAt       *time.Time      `json:"executed_at,omitempty" db:"executed_at"`
@@ -160,6 +206,9 @@ type CreateDraftRequest struct {
 	AllocatedCapital decimal.Decimal `json:"allocated_capital" binding:"required"`
 	CopyRatio        decimal.Decimal `json:"copy_ratio"` // Optional, defaults to 1.0
 	AutoAdjust       bool            `json:"auto_adjust"`
+	MirrorMode       DraftMirrorMode `json:"mirror_mode"`        // Optional, defaults to signals
+	TrackID          *uuid.UUID      `json:"track_id,omitempty"` // Optional, portfolio mode only
+	AcknowledgeRisk  bool            `json:"acknowledge_risk"`   // Accepts a conductor riskier than the drafter's risk band
 }
 
 // ResizeDraftRequest represents a request to adjust allocated capital
@@ -205,6 +254,27 @@ type DraftSummary struct {
 // MinimumTradeValue is the minimum trade value in USD
 var MinimumTradeValue = decimal.NewFromFloat(1.00)
 
+// MirrorDriftTolerance is the share of draft value a position may drift from target before catch-up
+var MirrorDriftTolerance = decimal.NewFromFloat(0.01)
+
+// TradingDaysPerYear annualizes daily conductor returns
+const TradingDaysPerYear = 252
+
+// ConductorPerformancePeriods maps chart periods to how far back they reach; "all" has no limit
+var ConductorPerformancePeriods = map[string]time.Duration{
+	"1m":  30 * 24 * time.Hour,
+	"3m":  91 * 24 * time.Hour,
+	"6m":  182 * 24 * time.Hour,
+	"1y":  365 * 24 * time.Hour,
+	"all": 0,
+}
+
+// MinBlockOrderDrafts is the number of following drafts at which a signal is executed as one block order
+const MinBlockOrderDrafts = 2
+
+// BlockAllocationPrecision is the number of decimal places fractional shares are allocated in
+const BlockAllocationPrecision int32 = 8
+
 // ConductorApplicationStatus represents the status of a conductor application
 type ConductorApplicationStatus string
 
@@ -256,6 +326,7 @@ type Track struct {
 	IsActive       bool            `json:"is_active" db:"is_active"`
 	FollowersCount int             `json:"followers_count" db:"followers_count"`
 	TotalReturn    decimal.Decimal `json:"total_return" db:"total_return"`
+	Version        int             `json:"version" db:"version"` // Current allocation version
 	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
 
@@ -271,10 +342,32 @@ type TrackAllocation struct {
 	AssetTicker  string          `json:"asset_ticker" db:"asset_ticker"`
 	AssetName    string          `json:"asset_name" db:"asset_name"`
 	TargetWeight decimal.Decimal `json:"target_weight" db:"target_weight"` // Percentage (0-100)
+	Version      int             `json:"version" db:"version"`
 	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
 }
 
+// TrackVersion is one set of allocations a track has held. Every version after the first is
+// propagated to followers by a REBALANCE signal.
+type TrackVersion struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TrackID   uuid.UUID  `json:"track_id" db:"track_id"`
+	Version   int        `json:"version" db:"version"`
+	SignalID  *uuid.UUID `json:"signal_id,omitempty" db:"signal_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Allocations []TrackAllocation       `json:"allocations" db:"-"`
+	Changes     []TrackAllocationChange `json:"changes" db:"-"` // Against the previous version
+}
+
+// TrackAllocationChange is the change in one asset's target weight between track versions
+type TrackAllocationChange struct {
+	AssetTicker    string          `json:"asset_ticker"`
+	PreviousWeight decimal.Decimal `json:"previous_weight"`
+	NewWeight      decimal.Decimal `json:"new_weight"`
+}
+
 // CreateTrackRequest represents a request to create a new track
 type CreateTrackRequest struct {
 	Name        string                        `json:"name" binding:"required,min=2,max=100"`
@@ -310,3 +403,242 @@ type TrackSummary struct {
 	TotalReturn    decimal.Decimal `json:"total_return"`
 	IsActive       bool            `json:"is_active"`
 }
+
+// Fee limits conductors can charge
+var (
+	MaxPerformanceFeeRate = decimal.NewFromFloat(0.30) // 30% of gains above the high-water mark
+	MaxManagementFeeRate  = decimal.NewFromFloat(0.02) // 2% of draft value per year
+)
+
+// MinConductorPayout is the smallest payable balance paid out to a conductor
+var MinConductorPayout = decimal.NewFromFloat(1.00)
+
+// Copy trading fee schedule
+const (
+	FeeAccrualInterval              = 24 * time.Hour      // Management fees accrue daily
+	DefaultFeeCrystallizationPeriod = 30 * 24 * time.Hour // Accrued fees are charged monthly
+)
+
+// DraftFeeStatement records one fee crystallization for a draft
+type DraftFeeStatement struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	DraftID            uuid.UUID       `json:"draft_id" db:"draft_id"`
+	DrafterID          uuid.UUID       `json:"drafter_id" db:"drafter_id"`
+	ConductorID        uuid.UUID       `json:"conductor_id" db:"conductor_id"`
+	PeriodStart        time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd          time.Time       `json:"period_end" db:"period_end"`
+	DraftValue         decimal.Decimal `json:"draft_value" db:"draft_value"` // Cash plus holdings at current prices
+	OpeningHighWater   decimal.Decimal `json:"opening_high_water_mark" db:"opening_high_water_mark"`
+	ClosingHighWater   decimal.Decimal `json:"closing_high_water_mark" db:"closing_high_water_mark"`
+	PerformanceFeeRate decimal.Decimal `json:"performance_fee_rate" db:"performance_fee_rate"`
+	ManagementFee      decimal.Decimal `json:"management_fee" db:"management_fee"`
+	PerformanceFee     decimal.Decimal `json:"performance_fee" db:"performance_fee"`
+	TotalFee           decimal.Decimal `json:"total_fee" db:"total_fee"`
+	AmountCharged      decimal.Decimal `json:"amount_charged" db:"amount_charged"` // Less than TotalFee when draft cash is short; the rest carries over
+	LedgerTxID         *uuid.UUID      `json:"-" db:"ledger_transaction_id"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ConductorPayout records fees paid from a conductor's payable account to their balance
+type ConductorPayout struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	ConductorID uuid.UUID       `json:"conductor_id" db:"conductor_id"`
+	Amount      decimal.Decimal `json:"amount" db:"amount"`
+	LedgerTxID  *uuid.UUID      `json:"-" db:"ledger_transaction_id"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ConductorEarnings summarizes a conductor's fee income
+type ConductorEarnings struct {
+	ConductorID    uuid.UUID          `json:"conductor_id"`
+	PayableBalance decimal.Decimal    `json:"payable_balance"`
+	TotalEarned    decimal.Decimal    `json:"total_earned"`
+	TotalPaidOut   decimal.Decimal    `json:"total_paid_out"`
+	RecentPayouts  []*ConductorPayout `json:"recent_payouts"`
+}
+
+// UpdateConductorFeesRequest represents a conductor changing their fee schedule
+type UpdateConductorFeesRequest struct {
+	PerformanceFeeRate *decimal.Decimal `json:"performance_fee_rate,omitempty"`
+	ManagementFeeRate  *decimal.Decimal `json:"management_fee_rate,omitempty"`
+}
+
+// BlockOrderStatus represents the status of an aggregated block order
+type BlockOrderStatus string
+
+const (
+	BlockOrderStatusPending         BlockOrderStatus = "pending"
+	BlockOrderStatusFilled          BlockOrderStatus = "filled"
+	BlockOrderStatusPartiallyFilled BlockOrderStatus = "partially_filled"
+	BlockOrderStatusFailed          BlockOrderStatus = "failed"
+)
+
+// BlockOrder is a single firm-account order aggregating every draft's share of a signal.
+// Fills are allocated back to the drafts pro rata and moved to their accounts by journal.
+type BlockOrder struct {
+	ID                uuid.UUID        `json:"id" db:"id"`
+	SignalID          uuid.UUID        `json:"signal_id" db:"signal_id"`
+	AssetTicker       string           `json:"asset_ticker" db:"asset_ticker"`
+	Side              string           `json:"side" db:"side"`
+	RequestedQuantity decimal.Decimal  `json:"requested_quantity" db:"requested_quantity"`
+	FilledQuantity    decimal.Decimal  `json:"filled_quantity" db:"filled_quantity"`
+	AveragePrice      decimal.Decimal  `json:"average_price" db:"average_price"`
+	DraftCount        int              `json:"draft_count" db:"draft_count"`
+	OrderID           string           `json:"order_id,omitempty" db:"order_id"`
+	Status            BlockOrderStatus `json:"status" db:"status"`
+	ErrorMessage      string           `json:"error_message,omitempty" db:"error_message"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	CompletedAt       *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// BlockAllocation is one draft's share of a block order
+type BlockAllocation struct {
+	DraftID           uuid.UUID
+	RequestedQuantity decimal.Decimal
+	AllocatedQuantity decimal.Decimal
+}
+
+// ConductorPerformanceChart is a conductor's daily performance series with headline metrics
+type ConductorPerformanceChart struct {
+	ConductorID uuid.UUID                      `json:"conductor_id"`
+	Period      string                         `json:"period"`
+	TotalReturn decimal.Decimal                `json:"total_return"`
+	MaxDrawdown decimal.Decimal                `json:"max_drawdown"`
+	SharpeRatio decimal.Decimal                `json:"sharpe_ratio"`
+	WinRate     decimal.Decimal                `json:"win_rate"`
+	Points      []*ConductorPerformanceHistory `json:"points"`
+}
+
+// DraftRiskSettings are a drafter's limits on a draft. Unset limits are not enforced.
+type DraftRiskSettings struct {
+	MaxDrawdown      *decimal.Decimal `json:"risk_max_drawdown,omitempty" db:"risk_max_drawdown"`           // Fall below start value, as a fraction
+	TrailingStop     *decimal.Decimal `json:"risk_trailing_stop,omitempty" db:"risk_trailing_stop"`         // Fall below peak value, as a fraction
+	MaxConcentration *decimal.Decimal `json:"risk_max_concentration,omitempty" db:"risk_max_concentration"` // Largest share of draft value in one asset
+	MaxTradeValue    *decimal.Decimal `json:"risk_max_trade_value,omitempty" db:"risk_max_trade_value"`     // Largest copied buy in USD
+	BreachAction     DraftRiskAction  `json:"risk_breach_action" db:"risk_breach_action"`
+}
+
+// HasStopLimits reports whether the draft's value is monitored for drawdown or trailing stop breaches
+func (r DraftRiskSettings) HasStopLimits() bool {
+	return r.MaxDrawdown != nil || r.TrailingStop != nil
+}
+
+// UpdateDraftRiskRequest replaces a draft's risk settings; omitted limits are cleared
+type UpdateDraftRiskRequest struct {
+	MaxDrawdown      *decimal.Decimal `json:"max_drawdown,omitempty"`
+	TrailingStop     *decimal.Decimal `json:"trailing_stop,omitempty"`
+	MaxConcentration *decimal.Decimal `json:"max_concentration,omitempty"`
+	MaxTradeValue    *decimal.Decimal `json:"max_trade_value,omitempty"`
+	BreachAction     DraftRiskAction  `json:"breach_action,omitempty"` // Defaults to pause
+}
+
+// ComplianceEventType classifies a conductor trade blocked or flagged by compliance controls
+type ComplianceEventType string
+
+const (
+	ComplianceEventRestrictedAsset ComplianceEventType = "restricted_asset"
+	ComplianceEventBlackout        ComplianceEventType = "blackout"
+	ComplianceEventFrequencyLimit  ComplianceEventType = "frequency_limit"
+	ComplianceEventFrontRunning    ComplianceEventType = "front_running"
+	ComplianceEventSuspended       ComplianceEventType = "suspended"
+)
+
+// Conductor compliance limits
+const (
+	ComplianceMaxSignalsPerHour  = 10
+	ComplianceMaxSignalsPerDay   = 50
+	ComplianceFrontRunningWindow = 30 * time.Minute
+	ComplianceLiquidityLookback  = 20 // Trading days averaged for dollar volume
+	ComplianceSuspiciousBlocks   = 3  // Blocked trades in a report period that flag a conductor
+)
+
+// ComplianceMinDollarVolume is the average daily dollar volume an asset needs before conductors'
+// buys of it are copied, keeping followers out of thinly traded assets that are easy to pump
+var ComplianceMinDollarVolume = decimal.NewFromInt(10_000_000)
+
+// ComplianceExchanges are the listing venues whose assets conductors may trade; OTC assets are excluded
+var ComplianceExchanges = map[string]bool{
+	"NYSE":   true,
+	"NASDAQ": true,
+	"ARCA":   true,
+	"AMEX":   true,
+	"BATS":   true,
+}
+
+// AssetEligibility is the brokerage data compliance checks before a buy is copied
+type AssetEligibility struct {
+	Symbol              string          `json:"symbol"`
+	Tradable            bool            `json:"tradable"`
+	Exchange            string          `json:"exchange"`
+	AverageDollarVolume decimal.Decimal `json:"average_dollar_volume"`
+}
+
+// ConductorComplianceEvent records a conductor trade that compliance blocked from being copied or
+// flagged for review
+type ConductorComplianceEvent struct {
+	ID          uuid.UUID           `json:"id" db:"id"`
+	ConductorID uuid.UUID           `json:"conductor_id" db:"conductor_id"`
+	SignalID    *uuid.UUID          `json:"signal_id,omitempty" db:"signal_id"` // Nil for blocked trades
+	EventType   ComplianceEventType `json:"event_type" db:"event_type"`
+	AssetTicker string              `json:"asset_ticker,omitempty" db:"asset_ticker"`
+	Side        string              `json:"side,omitempty" db:"side"`
+	TradeValue  decimal.Decimal     `json:"trade_value" db:"trade_value"`
+	Blocked     bool                `json:"blocked" db:"blocked"`
+	Details     string              `json:"details" db:"details"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+}
+
+// ComplianceEventCount is the number of one type of compliance event for a conductor
+type ComplianceEventCount struct {
+	ConductorID uuid.UUID           `db:"conductor_id"`
+	EventType   ComplianceEventType `db:"event_type"`
+	Blocked     bool                `db:"blocked"`
+	Count       int                 `db:"count"`
+	LastEventAt time.Time           `db:"last_event_at"`
+}
+
+// SignalBlackoutWindow stops conductor trades from being copied for a period. A window without a
+// conductor applies to every conductor, and one without an asset covers every asset.
+type SignalBlackoutWindow struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	ConductorID *uuid.UUID `json:"conductor_id,omitempty" db:"conductor_id"`
+	AssetTicker string     `json:"asset_ticker,omitempty" db:"asset_ticker"`
+	StartsAt    time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at" db:"ends_at"`
+	Reason      string     `json:"reason" db:"reason"`
+	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateBlackoutWindowRequest represents an admin scheduling a blackout window
+type CreateBlackoutWindowRequest struct {
+	ConductorID *uuid.UUID `json:"conductor_id,omitempty"`
+	AssetTicker string     `json:"asset_ticker,omitempty"`
+	StartsAt    time.Time  `json:"starts_at" binding:"required"`
+	EndsAt      time.Time  `json:"ends_at" binding:"required"`
+	Reason      string     `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// ConductorSurveillance is one conductor's line in the compliance surveillance report
+type ConductorSurveillance struct {
+	ConductorID       uuid.UUID                   `json:"conductor_id"`
+	DisplayName       string                      `json:"display_name"`
+	Status            ConductorStatus             `json:"status"`
+	BlockedTrades     int                         `json:"blocked_trades"`
+	FrontRunningFlags int                         `json:"front_running_flags"`
+	EventCounts       map[ComplianceEventType]int `json:"event_counts"`
+	LastEventAt       time.Time                   `json:"last_event_at"`
+	Suspicious        bool                        `json:"suspicious"`
+}
+
+// ComplianceReport lists conductors with compliance events in a period, suspicious ones first
+type ComplianceReport struct {
+	Since       time.Time                `json:"since"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Conductors  []*ConductorSurveillance `json:"conductors"`
+}
+
+// SuspendConductorRequest represents an admin suspending a conductor
+type SuspendConductorRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
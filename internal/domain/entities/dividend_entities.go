@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Alpaca non-trade activity types for dividends
+const (
+	ActivityTypeDividend               = "DIV"    // Cash dividend
+	ActivityTypeDividendNRAWithholding = "DIVNRA" // Tax withheld from a dividend for non-resident aliens
+)
+
+// IsDividendActivity reports whether a non-trade activity type is a dividend or its withholding
+func IsDividendActivity(activityType string) bool {
+	return activityType == ActivityTypeDividend || activityType == ActivityTypeDividendNRAWithholding
+}
+
+// DividendReinvestMode selects what happens to dividend cash
+type DividendReinvestMode string
+
+const (
+	DividendReinvestOff       DividendReinvestMode = "off"        // Keep dividends as buying power
+	DividendReinvestSameAsset DividendReinvestMode = "same_asset" // Buy more of the paying asset
+	DividendReinvestStrategy  DividendReinvestMode = "strategy"   // Invest across the user's target allocation
+)
+
+// IsValid reports whether the mode is supported
+func (m DividendReinvestMode) IsValid() bool {
+	switch m {
+	case DividendReinvestOff, DividendReinvestSameAsset, DividendReinvestStrategy:
+		return true
+	}
+	return false
+}
+
+// Dividend reinvestment outcomes
+const (
+	DividendReinvestStatusNone     = "not_reinvested"
+	DividendReinvestStatusAwaiting = "awaiting_withholding" // Held until tax withheld from the payment is known
+	DividendReinvestStatusPlaced   = "reinvested"
+	DividendReinvestStatusSkipped  = "skipped"
+	DividendReinvestStatusFailed   = "failed"
+)
+
+// DividendWithholdingWait is how long a dividend waits for its DIVNRA withholding activity before
+// it is reinvested in full; Alpaca posts withholding alongside the dividend
+const DividendWithholdingWait = 24 * time.Hour
+
+// MinDividendReinvestment is the smallest dividend reinvested; Alpaca rejects smaller
+// notional orders
+var MinDividendReinvestment = decimal.NewFromInt(1)
+
+// Dividend is one dividend or withholding activity posted to a user's buying power. A DIV
+// activity carries the gross dividend; a DIVNRA activity carries tax withheld from it.
+type Dividend struct {
+	ID                  uuid.UUID       `json:"id" db:"id"`
+	UserID              uuid.UUID       `json:"user_id" db:"user_id"`
+	ActivityID          string          `json:"activity_id" db:"activity_id"`
+	ActivityType        string          `json:"activity_type" db:"activity_type"`
+	Symbol              string          `json:"symbol" db:"symbol"`
+	Quantity            decimal.Decimal `json:"quantity" db:"quantity"`
+	PerShareAmount      decimal.Decimal `json:"per_share_amount" db:"per_share_amount"`
+	GrossAmount         decimal.Decimal `json:"gross_amount" db:"gross_amount"`
+	WithholdingAmount   decimal.Decimal `json:"withholding_amount" db:"withholding_amount"`
+	NetAmount           decimal.Decimal `json:"net_amount" db:"net_amount"` // Negative for withholding
+	PayDate             time.Time       `json:"pay_date" db:"pay_date"`
+	LedgerTransactionID *uuid.UUID      `json:"ledger_transaction_id,omitempty" db:"ledger_transaction_id"`
+	ReinvestStatus      string          `json:"reinvest_status" db:"reinvest_status"`
+	ReinvestAmount      decimal.Decimal `json:"reinvest_amount" db:"reinvest_amount"`
+	ReinvestOrders      int             `json:"reinvest_orders" db:"reinvest_orders"` // Orders placed with the dividend
+	ReinvestError       *string         `json:"reinvest_error,omitempty" db:"reinvest_error"`
+	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// DividendSettings holds a user's dividend reinvestment choice
+type DividendSettings struct {
+	UserID       uuid.UUID            `json:"user_id" db:"user_id"`
+	ReinvestMode DividendReinvestMode `json:"reinvest_mode" db:"reinvest_mode"`
+	UpdatedAt    time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// DividendSymbolSummary totals a year's dividends from one asset
+type DividendSymbolSummary struct {
+	Symbol      string          `json:"symbol"`
+	Gross       decimal.Decimal `json:"gross"`
+	Withholding decimal.Decimal `json:"withholding"`
+	Net         decimal.Decimal `json:"net"`
+	Payments    int             `json:"payments"`
+}
+
+// DividendReport totals a user's dividends for a tax year
+type DividendReport struct {
+	UserID      uuid.UUID                `json:"user_id"`
+	Year        int                      `json:"year"`
+	Gross       decimal.Decimal          `json:"gross"`
+	Withholding decimal.Decimal          `json:"withholding"`
+	Net         decimal.Decimal          `json:"net"`
+	Reinvested  decimal.Decimal          `json:"reinvested"`
+	BySymbol    []*DividendSymbolSummary `json:"by_symbol"`
+	Dividends   []*Dividend              `json:"dividends"`
+}
@@ -21,10 +21,18 @@ const (
 	AccountTypeSpendingBalance AccountType = "spending_balance" // User's 70% spending balance (available for payments)
 	AccountTypeStashBalance    AccountType = "stash_balance"    // User's 30% stash balance (locked savings)
 
+	// Copy trading account types
+	AccountTypeConductorPayable AccountType = "conductor_payable" // Fees owed to a conductor, awaiting payout
+
+	// Receivable account types
+	AccountTypeWithholdingReceivable AccountType = "withholding_receivable" // Dividend tax withheld beyond the user's buying power; runs negative by the amount owed
+
 	// System account types
-	AccountTypeSystemBufferUSDC  AccountType = "system_buffer_usdc" // System on-chain USDC reserve
-	AccountTypeSystemBufferFiat  AccountType = "system_buffer_fiat" // System operational USD buffer
-	AccountTypeBrokerOperational AccountType = "broker_operational" // Pre-funded cash at Alpaca
+	AccountTypeSystemBufferUSDC     AccountType = "system_buffer_usdc"     // System on-chain USDC reserve
+	AccountTypeSystemBufferFiat     AccountType = "system_buffer_fiat"     // System operational USD buffer
+	AccountTypeBrokerOperational    AccountType = "broker_operational"     // Pre-funded cash at Alpaca
+	AccountTypeSystemRewardsFunding AccountType = "system_rewards_funding" // Platform-funded cashback pool
+	AccountTypeBrokerIncoming       AccountType = "broker_incoming"        // Clearing account for cash the broker pays in, e.g. dividends
 )
 
 // IsUserAccountType returns true if the account type belongs to a user
@@ -33,14 +41,24 @@ func (a AccountType) IsUserAccountType() bool {
 		a == AccountTypeFiatExposure ||
 		a == AccountTypePendingInvestment ||
 		a == AccountTypeSpendingBalance ||
-		a == AccountTypeStashBalance
+		a == AccountTypeStashBalance ||
+		a == AccountTypeConductorPayable ||
+		a == AccountTypeWithholdingReceivable
 }
 
 // IsSystemAccountType returns true if the account type is system-level
 func (a AccountType) IsSystemAccountType() bool {
 	return a == AccountTypeSystemBufferUSDC ||
 		a == AccountTypeSystemBufferFiat ||
-		a == AccountTypeBrokerOperational
+		a == AccountTypeBrokerOperational ||
+		a == AccountTypeSystemRewardsFunding ||
+		a == AccountTypeBrokerIncoming
+}
+
+// AllowsNegativeBalance returns true for clearing accounts, which run negative by the cash they
+// have brought into the ledger, and for receivables, which run negative by the amount owed
+func (a AccountType) AllowsNegativeBalance() bool {
+	return a == AccountTypeBrokerIncoming || a == AccountTypeWithholdingReceivable
 }
 
 // IsSystemAccount is an alias for IsSystemAccountType
@@ -57,8 +75,9 @@ func (a AccountType) IsValid() bool {
 func (a AccountType) Validate() error {
 	switch a {
 	case AccountTypeUSDCBalance, AccountTypeFiatExposure, AccountTypePendingInvestment,
-		AccountTypeSpendingBalance, AccountTypeStashBalance,
-		AccountTypeSystemBufferUSDC, AccountTypeSystemBufferFiat, AccountTypeBrokerOperational:
+		AccountTypeSpendingBalance, AccountTypeStashBalance, AccountTypeConductorPayable,
+		AccountTypeWithholdingReceivable, AccountTypeSystemBufferUSDC, AccountTypeSystemBufferFiat, AccountTypeBrokerOperational,
+		AccountTypeSystemRewardsFunding, AccountTypeBrokerIncoming:
 		return nil
 	default:
 		return fmt.Errorf("invalid account type: %s", a)
@@ -77,6 +96,10 @@ const (
 	TransactionTypeBufferReplenishment TransactionType = "buffer_replenishment"
 	TransactionTypeReversal            TransactionType = "reversal"
 	TransactionTypeCardPayment         TransactionType = "card_payment"
+	TransactionTypeCardDispute         TransactionType = "card_dispute"
+	TransactionTypeCashback            TransactionType = "cashback"
+	TransactionTypeCopyTradingFee      TransactionType = "copy_trading_fee"
+	TransactionTypeDividend            TransactionType = "dividend"
 )
 
 // Validate checks if the transaction type is valid
@@ -84,7 +107,9 @@ func (t TransactionType) Validate() error {
 	switch t {
 	case TransactionTypeDeposit, TransactionTypeWithdrawal, TransactionTypeInvestment,
 		TransactionTypeConversion, TransactionTypeInternalTransfer,
-		TransactionTypeBufferReplenishment, TransactionTypeReversal, TransactionTypeCardPayment:
+		TransactionTypeBufferReplenishment, TransactionTypeReversal, TransactionTypeCardPayment,
+		TransactionTypeCardDispute, TransactionTypeCashback, TransactionTypeCopyTradingFee,
+		TransactionTypeDividend:
 		return nil
 	default:
 		return fmt.Errorf("invalid transaction type: %s", t)
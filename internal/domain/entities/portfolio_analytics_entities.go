@@ -130,6 +130,9 @@ type ScheduledInvestment struct {
 	Status          string          `json:"status" db:"status"`
 	TotalInvested   decimal.Decimal `json:"total_invested" db:"total_invested"`
 	ExecutionCount  int             `json:"execution_count" db:"execution_count"`
+	CatchUpPolicy   string          `json:"catch_up_policy" db:"catch_up_policy"` // skip, run_once, run_all
+	HolidayPolicy   string          `json:"holiday_policy" db:"holiday_policy"`   // defer, skip
+	ReminderSentFor *time.Time      `json:"-" db:"reminder_sent_for"`             // NextExecutionAt the last reminder was sent for
 	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
 }
@@ -139,9 +142,12 @@ type ScheduledInvestmentExecution struct {
 	ID                    uuid.UUID       `json:"id" db:"id"`
 	ScheduledInvestmentID uuid.UUID       `json:"scheduled_investment_id" db:"scheduled_investment_id"`
 	OrderID               *uuid.UUID      `json:"order_id" db:"order_id"`
+	LedgerTransactionID   *uuid.UUID      `json:"ledger_transaction_id,omitempty" db:"ledger_transaction_id"`
 	Amount                decimal.Decimal `json:"amount" db:"amount"`
-	Status                string          `json:"status" db:"status"` // success, failed, skipped
+	Status                string          `json:"status" db:"status"` // success, failed, skipped, deferred, insufficient_funds
 	ErrorMessage          *string         `json:"error_message" db:"error_message"`
+	Reason                *string         `json:"reason,omitempty" db:"reason"` // Why a run was skipped or deferred
+	ScheduledFor          *time.Time      `json:"scheduled_for,omitempty" db:"scheduled_for"`
 	ExecutedAt            time.Time       `json:"executed_at" db:"executed_at"`
 }
 
@@ -164,10 +170,16 @@ type RebalancingPlan struct {
 	ConfigID         uuid.UUID                  `json:"config_id"`
 	CurrentAllocations map[string]decimal.Decimal `json:"current_allocations"`
 	TargetAllocations  map[string]decimal.Decimal `json:"target_allocations"`
+	ProjectedAllocations map[string]decimal.Decimal `json:"projected_allocations"` // Weights after the trades, including cash flow
 	Trades           []RebalanceTradeOrder      `json:"trades"`
+	Skipped          []RebalanceSkippedTrade    `json:"skipped,omitempty"`
+	CashFlow         decimal.Decimal            `json:"cash_flow"`       // New cash invested before anything is sold
+	UninvestedCash   decimal.Decimal            `json:"uninvested_cash"` // Cash left over by trade size and whole-share rules
 	TotalBuyAmount   decimal.Decimal            `json:"total_buy_amount"`
 	TotalSellAmount  decimal.Decimal            `json:"total_sell_amount"`
+	EstimatedRealizedGain decimal.Decimal       `json:"estimated_realized_gain"`
 	EstimatedCost    decimal.Decimal            `json:"estimated_cost"`
+	Summary          string                     `json:"summary"`
 }
 
 // RebalanceTradeOrder represents a single trade in rebalancing plan
@@ -176,11 +188,33 @@ type RebalanceTradeOrder struct {
 	Side         string          `json:"side"` // buy or sell
 	CurrentPct   decimal.Decimal `json:"current_pct"`
 	TargetPct    decimal.Decimal `json:"target_pct"`
+	ProjectedPct decimal.Decimal `json:"projected_pct"`
 	DriftPct     decimal.Decimal `json:"drift_pct"`
 	Amount       decimal.Decimal `json:"amount"`
 	EstimatedQty decimal.Decimal `json:"estimated_qty"`
+	WholeShares  bool            `json:"whole_shares"`             // Asset is not fractionable; the order is for EstimatedQty shares
+	CashFlowAmount decimal.Decimal `json:"cash_flow_amount"`       // Part of a buy funded by new cash rather than sales
+	EstimatedGain decimal.Decimal `json:"estimated_gain,omitempty"` // Realized gain (negative for a loss) a sell is expected to book
+	Reason       string          `json:"reason"`
 }
 
+// RebalanceSkippedTrade is a trade left out of a plan by minimum size or whole-share rules
+type RebalanceSkippedTrade struct {
+	Symbol string          `json:"symbol"`
+	Side   string          `json:"side"`
+	Amount decimal.Decimal `json:"amount"`
+	Reason string          `json:"reason"`
+}
+
+// CashFlowSource identifies where new cash being invested came from
+type CashFlowSource string
+
+const (
+	CashFlowSourceDeposit  CashFlowSource = "deposit"
+	CashFlowSourceRoundup  CashFlowSource = "roundup"
+	CashFlowSourceDividend CashFlowSource = "dividend"
+)
+
 // MarketAlert represents a price/volume alert
 type MarketAlert struct {
 	ID               uuid.UUID        `json:"id" db:"id"`
@@ -197,6 +231,16 @@ type MarketAlert struct {
 	UpdatedAt        time.Time        `json:"updated_at" db:"updated_at"`
 }
 
+// MarketSession is one trading day on the exchange calendar
+type MarketSession struct {
+	Date          time.Time `json:"date"` // Midnight exchange time
+	Open          time.Time `json:"open"`
+	Close         time.Time `json:"close"`
+	ExtendedOpen  time.Time `json:"extended_open"`  // Start of pre-market trading
+	ExtendedClose time.Time `json:"extended_close"` // End of after-hours trading
+	EarlyClose    bool      `json:"early_close"`
+}
+
 // MarketQuote represents real-time market data
 type MarketQuote struct {
 	Symbol        string          `json:"symbol"`
@@ -237,4 +281,20 @@ const (
 	ScheduleStatusActive    = "active"
 	ScheduleStatusPaused    = "paused"
 	ScheduleStatusCancelled = "cancelled"
+
+	// Missed runs, e.g. while the service was down: skip runs more than a day late, run once
+	// however many were missed, or run every missed run
+	CatchUpPolicySkip    = "skip"
+	CatchUpPolicyRunOnce = "run_once"
+	CatchUpPolicyRunAll  = "run_all"
+
+	// Runs falling on a market holiday or early close: move to the next full trading day, or skip
+	HolidayPolicyDefer = "defer"
+	HolidayPolicySkip  = "skip"
+
+	ScheduledRunSuccess           = "success"
+	ScheduledRunFailed            = "failed"
+	ScheduledRunSkipped           = "skipped"
+	ScheduledRunDeferred          = "deferred"
+	ScheduledRunInsufficientFunds = "insufficient_funds"
 )
@@ -184,13 +184,14 @@ type Balance struct {
 
 // Basket represents a curated investment basket
 type Basket struct {
-	ID          uuid.UUID         `json:"id" db:"id"`
-	Name        string            `json:"name" db:"name"`
-	Description string            `json:"description" db:"description"`
-	RiskLevel   RiskLevel         `json:"risk_level" db:"risk_level"`
-	Composition []BasketComponent `json:"composition"` // Stored as JSON in DB
-	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+	ID             uuid.UUID         `json:"id" db:"id"`
+	Name           string            `json:"name" db:"name"`
+	Description    string            `json:"description" db:"description"`
+	RiskLevel      RiskLevel         `json:"risk_level" db:"risk_level"`
+	Composition    []BasketComponent `json:"composition"`                          // Stored as JSON in DB
+	CurrentVersion int               `json:"current_version" db:"current_version"` // Active composition version
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 // BasketComponent represents a component within a basket
@@ -285,6 +286,8 @@ type OrderCreateRequest struct {
 	Side           OrderSide `json:"side" validate:"required"`
 	Amount         string    `json:"amount" validate:"required"`
 	IdempotencyKey *string   `json:"idempotencyKey,omitempty"`
+	// AcknowledgeRisk accepts a basket riskier than the user's assessed risk band
+	AcknowledgeRisk bool `json:"acknowledgeRisk,omitempty"`
 }
 
 // Portfolio represents a user's complete portfolio
@@ -0,0 +1,142 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxCashbackRate is the highest cashback rate an offer may pay (25%)
+var MaxCashbackRate = decimal.NewFromFloat(0.25)
+
+// DefaultCashbackClearingPeriod is how long accrued cashback stays pending before it can be paid out.
+// It covers the window in which most refunds and chargebacks arrive.
+const DefaultCashbackClearingPeriod = 14 * 24 * time.Hour
+
+// CashbackStatus represents the lifecycle of an accrued cashback reward
+type CashbackStatus string
+
+const (
+	CashbackStatusPending   CashbackStatus = "pending"   // Accrued, waiting out the clearing period
+	CashbackStatusAvailable CashbackStatus = "available" // Cleared, waiting for payout
+	CashbackStatusPaid      CashbackStatus = "paid"      // Paid into the stash balance
+	CashbackStatusReversed  CashbackStatus = "reversed"  // Purchase was refunded or reversed
+)
+
+// RewardOffer is a cashback offer on a merchant or a spending category
+type RewardOffer struct {
+	ID                uuid.UUID         `json:"id" db:"id"`
+	Name              string            `json:"name" db:"name"`
+	Description       *string           `json:"description,omitempty" db:"description"`
+	MerchantKey       *string           `json:"merchant_key,omitempty" db:"merchant_key"` // Matches the card transaction merchant key, e.g. "STARBUCKS"
+	Category          *SpendingCategory `json:"category,omitempty" db:"category"`
+	Rate              decimal.Decimal   `json:"rate" db:"rate"`                                         // Fraction of the purchase, e.g. 0.05 = 5%
+	PerTransactionCap *decimal.Decimal  `json:"per_transaction_cap,omitempty" db:"per_transaction_cap"` // Max cashback per purchase
+	MonthlyCap        *decimal.Decimal  `json:"monthly_cap,omitempty" db:"monthly_cap"`                 // Max cashback per user per calendar month
+	StartsAt          time.Time         `json:"starts_at" db:"starts_at"`
+	EndsAt            *time.Time        `json:"ends_at,omitempty" db:"ends_at"`
+	Active            bool              `json:"active" db:"active"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Validate validates a reward offer
+func (o *RewardOffer) Validate() error {
+	if o.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if (o.MerchantKey == nil) == (o.Category == nil) {
+		return fmt.Errorf("offer must target exactly one of a merchant or a category")
+	}
+	if o.MerchantKey != nil && *o.MerchantKey == "" {
+		return fmt.Errorf("merchant cannot be empty")
+	}
+	if o.Category != nil && !o.Category.IsValid() {
+		return fmt.Errorf("invalid category: %s", *o.Category)
+	}
+	if !o.Rate.IsPositive() || o.Rate.GreaterThan(MaxCashbackRate) {
+		return fmt.Errorf("rate must be greater than 0 and at most %s", MaxCashbackRate.String())
+	}
+	for name, limit := range map[string]*decimal.Decimal{
+		"per-transaction cap": o.PerTransactionCap,
+		"monthly cap":         o.MonthlyCap,
+	} {
+		if limit != nil && !limit.IsPositive() {
+			return fmt.Errorf("%s must be positive", name)
+		}
+	}
+	if o.EndsAt != nil && !o.EndsAt.After(o.StartsAt) {
+		return fmt.Errorf("offer must end after it starts")
+	}
+	return nil
+}
+
+// AppliesTo reports whether the offer covers a card purchase made at the given time
+func (o *RewardOffer) AppliesTo(tx *BridgeCardTransaction, at time.Time) bool {
+	if !o.Active || at.Before(o.StartsAt) || (o.EndsAt != nil && !at.Before(*o.EndsAt)) {
+		return false
+	}
+	if o.MerchantKey != nil {
+		return tx.MerchantKey != nil && *tx.MerchantKey == *o.MerchantKey
+	}
+	return tx.Category != nil && *tx.Category == *o.Category
+}
+
+// CashbackReward is cashback accrued on a single settled card purchase
+type CashbackReward struct {
+	ID                 uuid.UUID         `json:"id" db:"id"`
+	UserID             uuid.UUID         `json:"user_id" db:"user_id"`
+	OfferID            uuid.UUID         `json:"offer_id" db:"offer_id"`
+	OfferName          string            `json:"offer_name" db:"offer_name"`
+	CardTransactionID  uuid.UUID         `json:"card_transaction_id" db:"card_transaction_id"`
+	MerchantName       *string           `json:"merchant_name,omitempty" db:"merchant_name"`
+	MerchantKey        *string           `json:"-" db:"merchant_key"`
+	Category           *SpendingCategory `json:"category,omitempty" db:"category"`
+	PurchaseAmount     decimal.Decimal   `json:"purchase_amount" db:"purchase_amount"`
+	Rate               decimal.Decimal   `json:"rate" db:"rate"`
+	Amount             decimal.Decimal   `json:"amount" db:"amount"`
+	Status             CashbackStatus    `json:"status" db:"status"`
+	AvailableAt        time.Time         `json:"available_at" db:"available_at"`
+	PayoutAttemptedAt  *time.Time        `json:"-" db:"payout_attempted_at"` // Last payout the funding pool could not cover
+	PaidAt             *time.Time        `json:"paid_at,omitempty" db:"paid_at"`
+	ReversedAt         *time.Time        `json:"reversed_at,omitempty" db:"reversed_at"`
+	LedgerTxID         *uuid.UUID        `json:"-" db:"ledger_transaction_id"`
+	ReversalLedgerTxID *uuid.UUID        `json:"-" db:"reversal_ledger_transaction_id"`
+	CreatedAt          time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CashbackSummary totals a user's cashback by status
+type CashbackSummary struct {
+	Pending         decimal.Decimal `json:"pending" db:"pending"`
+	Available       decimal.Decimal `json:"available" db:"available"`
+	Paid            decimal.Decimal `json:"paid" db:"paid"`
+	LifetimeEarned  decimal.Decimal `json:"lifetime_earned" db:"lifetime_earned"`
+	RewardCount     int             `json:"reward_count" db:"reward_count"`
+	NextAvailableAt *time.Time      `json:"next_available_at,omitempty" db:"next_available_at"`
+}
+
+// CreateRewardOfferRequest is the admin request to create a cashback offer
+type CreateRewardOfferRequest struct {
+	Name              string            `json:"name" binding:"required"`
+	Description       *string           `json:"description,omitempty"`
+	Merchant          *string           `json:"merchant,omitempty"` // Merchant name; normalized to a merchant key
+	Category          *SpendingCategory `json:"category,omitempty"`
+	Rate              decimal.Decimal   `json:"rate"`
+	PerTransactionCap *decimal.Decimal  `json:"per_transaction_cap,omitempty"`
+	MonthlyCap        *decimal.Decimal  `json:"monthly_cap,omitempty"`
+	StartsAt          *time.Time        `json:"starts_at,omitempty"` // Defaults to now
+	EndsAt            *time.Time        `json:"ends_at,omitempty"`
+}
+
+// UpdateRewardOfferRequest is the admin request to change an offer. Accrued rewards keep the
+// rate they were earned at.
+type UpdateRewardOfferRequest struct {
+	Active            *bool            `json:"active,omitempty"`
+	Rate              *decimal.Decimal `json:"rate,omitempty"`
+	PerTransactionCap *decimal.Decimal `json:"per_transaction_cap,omitempty"` // 0 removes the cap
+	MonthlyCap        *decimal.Decimal `json:"monthly_cap,omitempty"`         // 0 removes the cap
+	EndsAt            *time.Time       `json:"ends_at,omitempty"`
+}
@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Risk profile errors
+var (
+	ErrRiskAcknowledgmentRequired = errors.New("investment exceeds your risk profile: acknowledge the risk to continue")
+)
+
+// RiskBand is a user's assessed risk tolerance, or the risk of an investment product
+type RiskBand string
+
+const (
+	RiskBandConservative RiskBand = "conservative"
+	RiskBandModerate     RiskBand = "moderate"
+	RiskBandBalanced     RiskBand = "balanced"
+	RiskBandGrowth       RiskBand = "growth"
+	RiskBandAggressive   RiskBand = "aggressive"
+)
+
+// Rank orders risk bands from 1 (conservative) to 5 (aggressive); unknown bands rank 0
+func (b RiskBand) Rank() int {
+	switch b {
+	case RiskBandConservative:
+		return 1
+	case RiskBandModerate:
+		return 2
+	case RiskBandBalanced:
+		return 3
+	case RiskBandGrowth:
+		return 4
+	case RiskBandAggressive:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// IsValid checks if the risk band is one of the defined bands
+func (b RiskBand) IsValid() bool {
+	return b.Rank() > 0
+}
+
+// Exceeds reports whether this band is riskier than another. Unknown bands never exceed.
+func (b RiskBand) Exceeds(other RiskBand) bool {
+	return b.IsValid() && other.IsValid() && b.Rank() > other.Rank()
+}
+
+// RiskProduct identifies the kind of investment a suitability check applies to
+type RiskProduct string
+
+const (
+	RiskProductAutoInvest  RiskProduct = "auto_invest"
+	RiskProductBasket      RiskProduct = "basket"
+	RiskProductCopyTrading RiskProduct = "copy_trading"
+)
+
+// RiskQuestionnaireStatus represents whether a questionnaire version is the one users answer
+type RiskQuestionnaireStatus string
+
+const (
+	RiskQuestionnaireStatusActive  RiskQuestionnaireStatus = "active"
+	RiskQuestionnaireStatusRetired RiskQuestionnaireStatus = "retired"
+)
+
+// RiskQuestionnaire is one version of the risk profiling questionnaire. Publishing a new version
+// retires the previous one; assessments keep the version they answered.
+type RiskQuestionnaire struct {
+	ID                uuid.UUID               `json:"id" db:"id"`
+	Version           int                     `json:"version" db:"version"`
+	Status            RiskQuestionnaireStatus `json:"status" db:"status"`
+	Questions         []RiskQuestion          `json:"questions" db:"-"`
+	Bands             []RiskBandThreshold     `json:"bands" db:"-"`
+	ReassessAfterDays int                     `json:"reassess_after_days" db:"reassess_after_days"`
+	CreatedBy         *uuid.UUID              `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt         time.Time               `json:"created_at" db:"created_at"`
+}
+
+// RiskQuestion is a multiple-choice question; each option adds its score to the total
+type RiskQuestion struct {
+	Key     string               `json:"key" binding:"required"`
+	Text    string               `json:"text" binding:"required"`
+	Options []RiskQuestionOption `json:"options" binding:"required,min=2,dive"`
+}
+
+// RiskQuestionOption is one answer to a question. Options that set HorizonYears also record the
+// user's investment horizon.
+type RiskQuestionOption struct {
+	Value        string `json:"value" binding:"required"`
+	Label        string `json:"label" binding:"required"`
+	Score        int    `json:"score"`
+	HorizonYears *int   `json:"horizon_years,omitempty"`
+}
+
+// RiskBandThreshold assigns a band to total scores at or above MinScore
+type RiskBandThreshold struct {
+	Band     RiskBand `json:"band" binding:"required"`
+	MinScore int      `json:"min_score"`
+}
+
+// RiskAssessment is a user's scored answers to a questionnaire version
+type RiskAssessment struct {
+	ID                   uuid.UUID         `json:"id" db:"id"`
+	UserID               uuid.UUID         `json:"user_id" db:"user_id"`
+	QuestionnaireID      uuid.UUID         `json:"questionnaire_id" db:"questionnaire_id"`
+	QuestionnaireVersion int               `json:"questionnaire_version" db:"questionnaire_version"`
+	Answers              map[string]string `json:"answers" db:"-"`
+	Score                int               `json:"score" db:"score"`
+	RiskBand             RiskBand          `json:"risk_band" db:"risk_band"`
+	TimeHorizonYears     *int              `json:"time_horizon_years,omitempty" db:"time_horizon_years"`
+	ReassessAt           time.Time         `json:"reassess_at" db:"reassess_at"`
+	PromptedAt           *time.Time        `json:"prompted_at,omitempty" db:"prompted_at"`
+	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
+}
+
+// RiskAcknowledgment records a user accepting an investment riskier than their assessed band.
+// ResourceID is the basket or conductor acknowledged; standing auto-invest acknowledgments have none.
+type RiskAcknowledgment struct {
+	ID              uuid.UUID   `json:"id" db:"id"`
+	UserID          uuid.UUID   `json:"user_id" db:"user_id"`
+	AssessmentID    uuid.UUID   `json:"assessment_id" db:"assessment_id"`
+	Product         RiskProduct `json:"product" db:"product"`
+	ResourceID      *uuid.UUID  `json:"resource_id,omitempty" db:"resource_id"`
+	ProductRiskBand RiskBand    `json:"product_risk_band" db:"product_risk_band"`
+	UserRiskBand    RiskBand    `json:"user_risk_band" db:"user_risk_band"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+}
+
+// RiskProfile is a user's current assessment and whether they should retake the questionnaire
+type RiskProfile struct {
+	Assessment         *RiskAssessment `json:"assessment"`
+	ReassessmentDue    bool            `json:"reassessment_due"`
+	ReassessmentReason string          `json:"reassessment_reason,omitempty"`
+}
+
+// SubmitRiskAssessmentRequest represents a user's answers keyed by question key
+type SubmitRiskAssessmentRequest struct {
+	Answers map[string]string `json:"answers" binding:"required"`
+}
+
+// CreateRiskQuestionnaireRequest represents an admin publishing a questionnaire version
+type CreateRiskQuestionnaireRequest struct {
+	Questions         []RiskQuestion      `json:"questions" binding:"required,min=1,dive"`
+	Bands             []RiskBandThreshold `json:"bands" binding:"required,min=1,dive"`
+	ReassessAfterDays int                 `json:"reassess_after_days" binding:"min=0"` // Defaults to 365
+}
+
+// AcknowledgeRiskRequest represents a user accepting auto-investment strategies up to a risk band
+type AcknowledgeRiskRequest struct {
+	Product  RiskProduct `json:"product" binding:"required,oneof=auto_invest"`
+	RiskBand RiskBand    `json:"risk_band"` // Defaults to aggressive
+}
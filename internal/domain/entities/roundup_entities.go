@@ -25,19 +25,27 @@ const (
 	RoundupStatusCollected RoundupStatus = "collected"
 	RoundupStatusInvested  RoundupStatus = "invested"
 	RoundupStatusFailed    RoundupStatus = "failed"
+	RoundupStatusReversed  RoundupStatus = "reversed" // Source transaction was refunded
+	RoundupStatusSkipped   RoundupStatus = "skipped"  // A rule (cap or balance safeguard) produced no round-up
 )
 
 // RoundupSettings represents user's round-up configuration
 type RoundupSettings struct {
-	UserID              uuid.UUID       `json:"user_id" db:"user_id"`
-	Enabled             bool            `json:"enabled" db:"enabled"`
-	Multiplier          decimal.Decimal `json:"multiplier" db:"multiplier"`           // 1x-10x
-	Threshold           decimal.Decimal `json:"threshold" db:"threshold"`             // Min amount before auto-invest
-	AutoInvestEnabled   bool            `json:"auto_invest_enabled" db:"auto_invest_enabled"`
-	AutoInvestBasketID  *uuid.UUID      `json:"auto_invest_basket_id,omitempty" db:"auto_invest_basket_id"`
-	AutoInvestSymbol    *string         `json:"auto_invest_symbol,omitempty" db:"auto_invest_symbol"`
-	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time       `json:"updated_at" db:"updated_at"`
+	UserID             uuid.UUID        `json:"user_id" db:"user_id"`
+	Enabled            bool             `json:"enabled" db:"enabled"`
+	Multiplier         decimal.Decimal  `json:"multiplier" db:"multiplier"` // 1x-10x
+	Threshold          decimal.Decimal  `json:"threshold" db:"threshold"`   // Min amount before auto-invest
+	AutoInvestEnabled  bool             `json:"auto_invest_enabled" db:"auto_invest_enabled"`
+	AutoInvestBasketID *uuid.UUID       `json:"auto_invest_basket_id,omitempty" db:"auto_invest_basket_id"`
+	AutoInvestSymbol   *string          `json:"auto_invest_symbol,omitempty" db:"auto_invest_symbol"`
+	WeeklyCap          *decimal.Decimal `json:"weekly_cap,omitempty" db:"weekly_cap"`             // Max round-up total per week (Mon-Sun UTC)
+	MonthlyCap         *decimal.Decimal `json:"monthly_cap,omitempty" db:"monthly_cap"`           // Max round-up total per calendar month
+	BoostMultiplier    *decimal.Decimal `json:"boost_multiplier,omitempty" db:"boost_multiplier"` // Stacks on Multiplier until BoostEndsAt
+	BoostEndsAt        *time.Time       `json:"boost_ends_at,omitempty" db:"boost_ends_at"`
+	LowBalanceFloor    *decimal.Decimal `json:"low_balance_floor,omitempty" db:"low_balance_floor"`   // Below this spend balance multipliers drop to 1x
+	SkipBelowBalance   *decimal.Decimal `json:"skip_below_balance,omitempty" db:"skip_below_balance"` // Skip round-ups that would leave less than this
+	CreatedAt          time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at" db:"updated_at"`
 }
 
 // Validate validates round-up settings
@@ -51,9 +59,32 @@ func (s *RoundupSettings) Validate() error {
 	if s.AutoInvestEnabled && s.AutoInvestBasketID == nil && s.AutoInvestSymbol == nil {
 		return fmt.Errorf("auto-invest requires a basket or symbol target")
 	}
+	for name, limit := range map[string]*decimal.Decimal{
+		"weekly cap":         s.WeeklyCap,
+		"monthly cap":        s.MonthlyCap,
+		"low balance floor":  s.LowBalanceFloor,
+		"skip below balance": s.SkipBelowBalance,
+	} {
+		if limit != nil && limit.IsNegative() {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+	if s.BoostMultiplier != nil {
+		if s.BoostEndsAt == nil {
+			return fmt.Errorf("boost requires an end time")
+		}
+		if s.BoostMultiplier.LessThan(decimal.NewFromInt(1)) || s.BoostMultiplier.GreaterThan(MaxRoundupMultiplier) {
+			return fmt.Errorf("boost multiplier must be between 1 and 10")
+		}
+	}
 	return nil
 }
 
+// BoostActive reports whether a boost period is in effect at the given time
+func (s *RoundupSettings) BoostActive(now time.Time) bool {
+	return s.BoostMultiplier != nil && s.BoostEndsAt != nil && now.Before(*s.BoostEndsAt)
+}
+
 // DefaultRoundupSettings returns default settings for a new user
 func DefaultRoundupSettings(userID uuid.UUID) *RoundupSettings {
 	now := time.Now()
@@ -83,6 +114,8 @@ type RoundupTransaction struct {
 	CollectedAt       *time.Time        `json:"collected_at,omitempty" db:"collected_at"`
 	InvestedAt        *time.Time        `json:"invested_at,omitempty" db:"invested_at"`
 	InvestmentOrderID *uuid.UUID        `json:"investment_order_id,omitempty" db:"investment_order_id"`
+	LedgerTxID        *uuid.UUID        `json:"ledger_transaction_id,omitempty" db:"ledger_transaction_id"` // Spending-to-stash transfer
+	SkipReason        *string           `json:"skip_reason,omitempty" db:"skip_reason"`
 	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
 }
 
@@ -106,18 +139,114 @@ type RoundupSummary struct {
 	TransactionCount int              `json:"transaction_count"`
 }
 
+// MaxRoundupMultiplier bounds the base multiplier and the effective multiplier with a boost
+var MaxRoundupMultiplier = decimal.NewFromInt(10)
+
+// Round-up skip reasons
+const (
+	RoundupSkipLowBalance = "low_balance"
+	RoundupSkipCapReached = "cap_reached"
+)
+
+// RoundupRuleInput is the state round-up rules are evaluated against
+type RoundupRuleInput struct {
+	Amount       decimal.Decimal
+	SpendBalance *decimal.Decimal // Nil when the balance is unknown; balance rules are then not applied
+	WeekToDate   decimal.Decimal  // Round-ups already taken this week
+	MonthToDate  decimal.Decimal  // Round-ups already taken this month
+	Now          time.Time
+}
+
+// RoundupEvaluation explains how a round-up amount was derived from the settings
+type RoundupEvaluation struct {
+	OriginalAmount      decimal.Decimal `json:"original_amount"`
+	RoundedAmount       decimal.Decimal `json:"rounded_amount"`
+	SpareChange         decimal.Decimal `json:"spare_change"`
+	Multiplier          decimal.Decimal `json:"multiplier"`
+	EffectiveMultiplier decimal.Decimal `json:"effective_multiplier"`
+	MultipliedAmount    decimal.Decimal `json:"multiplied_amount"` // Amount to sweep after caps
+	BoostApplied        bool            `json:"boost_applied"`
+	ScaledDown          bool            `json:"scaled_down"`         // Spend balance under the low balance floor
+	CappedBy            string          `json:"capped_by,omitempty"` // weekly_cap or monthly_cap
+	Skipped             bool            `json:"skipped"`
+	SkipReason          string          `json:"skip_reason,omitempty"`
+}
+
+// EvaluateRoundup applies the multiplier, boost, low balance scaling, caps and the balance
+// safeguard to a purchase amount
+func (s *RoundupSettings) EvaluateRoundup(in RoundupRuleInput) *RoundupEvaluation {
+	rounded, spareChange, _ := CalculateRoundup(in.Amount, decimal.NewFromInt(1))
+	eval := &RoundupEvaluation{
+		OriginalAmount:      in.Amount,
+		RoundedAmount:       rounded,
+		SpareChange:         spareChange,
+		Multiplier:          s.Multiplier,
+		EffectiveMultiplier: s.Multiplier,
+	}
+
+	if s.BoostActive(in.Now) {
+		eval.EffectiveMultiplier = decimal.Min(s.Multiplier.Mul(*s.BoostMultiplier), MaxRoundupMultiplier)
+		eval.BoostApplied = true
+	}
+	if in.SpendBalance != nil && s.LowBalanceFloor != nil && in.SpendBalance.LessThan(*s.LowBalanceFloor) {
+		eval.EffectiveMultiplier = decimal.NewFromInt(1)
+		eval.BoostApplied = false
+		eval.ScaledDown = true
+	}
+	amount := spareChange.Mul(eval.EffectiveMultiplier)
+
+	for _, c := range []struct {
+		name  string
+		cap   *decimal.Decimal
+		spent decimal.Decimal
+	}{
+		{"weekly_cap", s.WeeklyCap, in.WeekToDate},
+		{"monthly_cap", s.MonthlyCap, in.MonthToDate},
+	} {
+		if c.cap == nil {
+			continue
+		}
+		if remaining := decimal.Max(c.cap.Sub(c.spent), decimal.Zero); amount.GreaterThan(remaining) {
+			amount = remaining
+			eval.CappedBy = c.name
+		}
+	}
+	eval.MultipliedAmount = amount
+
+	switch {
+	case !amount.IsPositive():
+		eval.Skipped = true
+		eval.SkipReason = RoundupSkipCapReached
+	case in.SpendBalance != nil && s.SkipBelowBalance != nil &&
+		in.SpendBalance.Sub(amount).LessThan(*s.SkipBelowBalance):
+		eval.Skipped = true
+		eval.SkipReason = RoundupSkipLowBalance
+	}
+	if eval.Skipped {
+		eval.MultipliedAmount = decimal.Zero
+	}
+	return eval
+}
+
+// StartOfRoundupWeek returns Monday 00:00 UTC of the week containing t
+func StartOfRoundupWeek(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
 // CalculateRoundup calculates spare change from a transaction amount
 func CalculateRoundup(amount, multiplier decimal.Decimal) (rounded, spareChange, multiplied decimal.Decimal) {
 	// Round up to nearest dollar
 	rounded = amount.Ceil()
 	spareChange = rounded.Sub(amount)
-	
+
 	// If amount is exactly a dollar, round up to next dollar
 	if spareChange.IsZero() {
 		spareChange = decimal.NewFromInt(1)
 		rounded = rounded.Add(decimal.NewFromInt(1))
 	}
-	
+
 	multiplied = spareChange.Mul(multiplier)
 	return rounded, spareChange, multiplied
 }
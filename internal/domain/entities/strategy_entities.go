@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ModelPortfolioStatus represents whether a model portfolio can be assigned
+type ModelPortfolioStatus string
+
+const (
+	ModelPortfolioStatusActive  ModelPortfolioStatus = "active"
+	ModelPortfolioStatusRetired ModelPortfolioStatus = "retired"
+)
+
+// ModelPortfolio is a strategy the auto-investment engine can assign to users. Its allocations and
+// selection criteria live on versions so historical assignments stay explainable.
+type ModelPortfolio struct {
+	ID             uuid.UUID              `json:"id" db:"id"`
+	Slug           string                 `json:"slug" db:"slug"`
+	Name           string                 `json:"name" db:"name"`
+	Description    string                 `json:"description" db:"description"`
+	Status         ModelPortfolioStatus   `json:"status" db:"status"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
+	CurrentVersion *ModelPortfolioVersion `json:"current_version,omitempty" db:"-"`
+}
+
+// ModelPortfolioAllocation is one asset's percentage weight (0-100) in a model portfolio
+type ModelPortfolioAllocation struct {
+	Symbol string          `json:"symbol" binding:"required"`
+	Weight decimal.Decimal `json:"weight"`
+}
+
+// ModelPortfolioCriteria decides which users a model portfolio version suits. Unset bounds match
+// every user; a set bound only matches users for whom that signal is known.
+type ModelPortfolioCriteria struct {
+	MinAge          *int             `json:"min_age,omitempty"`
+	MaxAge          *int             `json:"max_age,omitempty"`
+	RiskBands       []string         `json:"risk_bands,omitempty"`
+	MinBalance      *decimal.Decimal `json:"min_balance,omitempty"`
+	MaxBalance      *decimal.Decimal `json:"max_balance,omitempty"`
+	MinHorizonYears *int             `json:"min_horizon_years,omitempty"`
+	MaxHorizonYears *int             `json:"max_horizon_years,omitempty"`
+}
+
+// Specificity counts the criteria that are set, used to prefer narrower matches
+func (c ModelPortfolioCriteria) Specificity() int {
+	n := 0
+	for _, set := range []bool{
+		c.MinAge != nil, c.MaxAge != nil, len(c.RiskBands) > 0, c.MinBalance != nil,
+		c.MaxBalance != nil, c.MinHorizonYears != nil, c.MaxHorizonYears != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// ModelPortfolioVersion is a model portfolio's allocations and criteria from a point in time.
+// Among matching portfolios the engine picks the highest priority. Versions riskier than a user's
+// assessed band are not assigned to them.
+type ModelPortfolioVersion struct {
+	ID            uuid.UUID                  `json:"id" db:"id"`
+	PortfolioID   uuid.UUID                  `json:"portfolio_id" db:"portfolio_id"`
+	PortfolioName string                     `json:"portfolio_name,omitempty" db:"portfolio_name"`
+	Version       int                        `json:"version" db:"version"`
+	EffectiveFrom time.Time                  `json:"effective_from" db:"effective_from"`
+	Priority      int                        `json:"priority" db:"priority"`
+	RiskBand      RiskBand                   `json:"risk_band,omitempty" db:"risk_band"`
+	Criteria      ModelPortfolioCriteria     `json:"criteria" db:"-"`
+	Allocations   []ModelPortfolioAllocation `json:"allocations" db:"-"`
+	Notes         string                     `json:"notes" db:"notes"`
+	CreatedBy     *uuid.UUID                 `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time                  `json:"created_at" db:"created_at"`
+}
+
+// StrategySignals is the snapshot of user signals a strategy was selected from
+type StrategySignals struct {
+	Age              *int             `json:"age,omitempty"`
+	RiskBand         string           `json:"risk_band,omitempty"`
+	Balance          *decimal.Decimal `json:"balance,omitempty"`
+	TimeHorizonYears *int             `json:"time_horizon_years,omitempty"`
+}
+
+// StrategyAssignment records the model portfolio version used for one auto-investment. Built-in
+// fallback portfolios have no portfolio or version ID.
+type StrategyAssignment struct {
+	ID                 uuid.UUID                  `json:"id" db:"id"`
+	UserID             uuid.UUID                  `json:"user_id" db:"user_id"`
+	PortfolioID        *uuid.UUID                 `json:"portfolio_id,omitempty" db:"portfolio_id"`
+	PortfolioVersionID *uuid.UUID                 `json:"portfolio_version_id,omitempty" db:"portfolio_version_id"`
+	PortfolioName      string                     `json:"portfolio_name" db:"portfolio_name"`
+	Version            int                        `json:"version" db:"version"`
+	CorrelationID      string                     `json:"correlation_id" db:"correlation_id"`
+	Amount             decimal.Decimal            `json:"amount" db:"amount"`
+	Signals            StrategySignals            `json:"signals" db:"-"`
+	Allocations        []ModelPortfolioAllocation `json:"allocations" db:"-"`
+	CreatedAt          time.Time                  `json:"created_at" db:"created_at"`
+}
+
+// CreateModelPortfolioVersionRequest represents an admin publishing model portfolio allocations
+type CreateModelPortfolioVersionRequest struct {
+	EffectiveFrom *time.Time                 `json:"effective_from,omitempty"` // Defaults to now
+	Priority      int                        `json:"priority"`
+	RiskBand      RiskBand                   `json:"risk_band" binding:"omitempty,oneof=conservative moderate balanced growth aggressive"`
+	Criteria      ModelPortfolioCriteria     `json:"criteria"`
+	Allocations   []ModelPortfolioAllocation `json:"allocations" binding:"required,min=1,dive"`
+	Notes         string                     `json:"notes" binding:"max=1000"`
+}
+
+// CreateModelPortfolioRequest represents an admin creating a model portfolio with its first version
+type CreateModelPortfolioRequest struct {
+	Slug        string                             `json:"slug" binding:"required,min=2,max=100"`
+	Name        string                             `json:"name" binding:"required,min=2,max=200"`
+	Description string                             `json:"description" binding:"max=1000"`
+	Version     CreateModelPortfolioVersionRequest `json:"version" binding:"required"`
+}
+
+// UpdateModelPortfolioStatusRequest represents an admin retiring or reactivating a model portfolio
+type UpdateModelPortfolioStatusRequest struct {
+	Status ModelPortfolioStatus `json:"status" binding:"required,oneof=active retired"`
+}
@@ -0,0 +1,226 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TaxLotMethod selects which lots a sale relieves
+type TaxLotMethod string
+
+const (
+	TaxLotMethodFIFO       TaxLotMethod = "fifo"        // Oldest lots first
+	TaxLotMethodSpecificID TaxLotMethod = "specific_id" // Lots selected for each order, then oldest first
+	TaxLotMethodHIFO       TaxLotMethod = "hifo"        // Highest cost per share first
+)
+
+// IsValid reports whether the method is supported
+func (m TaxLotMethod) IsValid() bool {
+	switch m {
+	case TaxLotMethodFIFO, TaxLotMethodSpecificID, TaxLotMethodHIFO:
+		return true
+	}
+	return false
+}
+
+// HoldingPeriod classifies a realized gain for tax purposes
+type HoldingPeriod string
+
+const (
+	HoldingPeriodShortTerm HoldingPeriod = "short_term"
+	HoldingPeriodLongTerm  HoldingPeriod = "long_term"
+)
+
+// WashSaleWindow is how far before or after a loss sale a purchase of the same security
+// makes it a wash sale
+const WashSaleWindow = 30 * 24 * time.Hour
+
+// HoldingPeriodFor returns the holding period of shares acquired and sold at the given times.
+// Shares held for more than one year are long-term.
+func HoldingPeriodFor(acquiredAt, soldAt time.Time) HoldingPeriod {
+	if soldAt.After(acquiredAt.AddDate(1, 0, 0)) {
+		return HoldingPeriodLongTerm
+	}
+	return HoldingPeriodShortTerm
+}
+
+// TaxLot is a block of shares bought in one fill. AcquiredAt starts the holding period and
+// moves earlier when the lot replaces shares sold in a wash sale.
+type TaxLot struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	UserID             uuid.UUID       `json:"user_id" db:"user_id"`
+	Symbol             string          `json:"symbol" db:"symbol"`
+	AlpacaOrderID      string          `json:"alpaca_order_id" db:"alpaca_order_id"`
+	Quantity           decimal.Decimal `json:"quantity" db:"quantity"`
+	RemainingQuantity  decimal.Decimal `json:"remaining_quantity" db:"remaining_quantity"`
+	CostBasis          decimal.Decimal `json:"cost_basis" db:"cost_basis"`
+	RemainingCostBasis decimal.Decimal `json:"remaining_cost_basis" db:"remaining_cost_basis"`
+	WashSaleAdjustment decimal.Decimal `json:"wash_sale_adjustment" db:"wash_sale_adjustment"`
+	IsReplacement      bool            `json:"is_replacement" db:"is_replacement"`
+	PurchasedAt        time.Time       `json:"purchased_at" db:"purchased_at"`
+	AcquiredAt         time.Time       `json:"acquired_at" db:"acquired_at"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// CostPerShare returns the adjusted cost basis per share still held
+func (l *TaxLot) CostPerShare() decimal.Decimal {
+	if l.RemainingQuantity.IsZero() {
+		return decimal.Zero
+	}
+	return l.RemainingCostBasis.Div(l.RemainingQuantity)
+}
+
+// CostOf returns the adjusted cost basis of quantity shares of the lot. Relieving the last
+// shares takes whatever basis is left, so rounding never strands cents on a closed lot.
+func (l *TaxLot) CostOf(quantity decimal.Decimal) decimal.Decimal {
+	if quantity.GreaterThanOrEqual(l.RemainingQuantity) {
+		return l.RemainingCostBasis
+	}
+	return l.CostPerShare().Mul(quantity).Round(2)
+}
+
+// RealizedGain records shares of one lot relieved by a sale. A wash sale disallows part of a
+// loss, which is added to the basis of the replacement lot instead.
+type RealizedGain struct {
+	ID               uuid.UUID       `json:"id" db:"id"`
+	UserID           uuid.UUID       `json:"user_id" db:"user_id"`
+	LotID            uuid.UUID       `json:"lot_id" db:"lot_id"`
+	Symbol           string          `json:"symbol" db:"symbol"`
+	AlpacaOrderID    string          `json:"alpaca_order_id" db:"alpaca_order_id"`
+	Quantity         decimal.Decimal `json:"quantity" db:"quantity"`
+	Proceeds         decimal.Decimal `json:"proceeds" db:"proceeds"`
+	CostBasis        decimal.Decimal `json:"cost_basis" db:"cost_basis"`
+	GainLoss         decimal.Decimal `json:"gain_loss" db:"gain_loss"`
+	WashSaleQuantity decimal.Decimal `json:"wash_sale_quantity" db:"wash_sale_quantity"`
+	DisallowedLoss   decimal.Decimal `json:"disallowed_loss" db:"disallowed_loss"`
+	HoldingPeriod    HoldingPeriod   `json:"holding_period" db:"holding_period"`
+	AcquiredAt       time.Time       `json:"acquired_at" db:"acquired_at"`
+	SoldAt           time.Time       `json:"sold_at" db:"sold_at"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ReportableGainLoss returns the gain or loss after adding back any disallowed wash sale loss
+func (g *RealizedGain) ReportableGainLoss() decimal.Decimal {
+	return g.GainLoss.Add(g.DisallowedLoss)
+}
+
+// UnmatchedWashQuantity returns the shares of a loss sale not yet matched to a replacement lot
+func (g *RealizedGain) UnmatchedWashQuantity() decimal.Decimal {
+	if !g.GainLoss.IsNegative() {
+		return decimal.Zero
+	}
+	return g.Quantity.Sub(g.WashSaleQuantity)
+}
+
+// TaxLotFill tracks how much of an Alpaca order has been applied to tax lots. Fill events carry
+// cumulative quantities, so each event is applied as the difference from the last one.
+type TaxLotFill struct {
+	AlpacaOrderID string          `json:"alpaca_order_id" db:"alpaca_order_id"`
+	UserID        uuid.UUID       `json:"user_id" db:"user_id"`
+	Symbol        string          `json:"symbol" db:"symbol"`
+	Side          AlpacaOrderSide `json:"side" db:"side"`
+	FilledQty     decimal.Decimal `json:"filled_qty" db:"filled_qty"`
+	FilledAmount  decimal.Decimal `json:"filled_amount" db:"filled_amount"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// TaxLotFillChanges is everything one fill changes, saved together
+type TaxLotFillChanges struct {
+	Fill         *TaxLotFill
+	CreatedLots  []*TaxLot
+	UpdatedLots  []*TaxLot
+	CreatedGains []*RealizedGain
+	UpdatedGains []*RealizedGain
+}
+
+// TaxLotSettings holds a user's lot relief method and tax-loss harvesting opt-in
+type TaxLotSettings struct {
+	UserID            uuid.UUID    `json:"user_id" db:"user_id"`
+	Method            TaxLotMethod `json:"method" db:"method"`
+	HarvestingEnabled bool         `json:"harvesting_enabled" db:"harvesting_enabled"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateTaxLotSettingsRequest changes a user's lot method and/or harvesting opt-in
+type UpdateTaxLotSettingsRequest struct {
+	Method            *TaxLotMethod `json:"method,omitempty"`
+	HarvestingEnabled *bool         `json:"harvesting_enabled,omitempty"`
+}
+
+// TaxLotSelection picks shares of a lot for a sell order
+type TaxLotSelection struct {
+	OrderID  uuid.UUID       `json:"order_id" db:"order_id"`
+	LotID    uuid.UUID       `json:"lot_id" db:"lot_id" binding:"required"`
+	Quantity decimal.Decimal `json:"quantity" db:"quantity"`
+}
+
+// SelectTaxLotsRequest chooses the lots a sell order relieves
+type SelectTaxLotsRequest struct {
+	OrderID uuid.UUID         `json:"order_id" binding:"required"`
+	Lots    []TaxLotSelection `json:"lots" binding:"required,min=1,dive"`
+}
+
+// RealizedGainsReport summarizes a user's realized gains and losses for a tax year
+type RealizedGainsReport struct {
+	UserID             uuid.UUID       `json:"user_id"`
+	TaxYear            int             `json:"tax_year"`
+	Method             TaxLotMethod    `json:"method"`
+	Proceeds           decimal.Decimal `json:"proceeds"`
+	CostBasis          decimal.Decimal `json:"cost_basis"`
+	ShortTermGainLoss  decimal.Decimal `json:"short_term_gain_loss"`
+	LongTermGainLoss   decimal.Decimal `json:"long_term_gain_loss"`
+	TotalGains         decimal.Decimal `json:"total_gains"`
+	TotalLosses        decimal.Decimal `json:"total_losses"`
+	WashSaleDisallowed decimal.Decimal `json:"wash_sale_disallowed"`
+	Gains              []*RealizedGain `json:"gains"`
+	GeneratedAt        time.Time       `json:"generated_at"`
+}
+
+// TaxReportFormat is an export format for the realized gains report
+type TaxReportFormat string
+
+const (
+	TaxReportFormatJSON TaxReportFormat = "json"
+	TaxReportFormatCSV  TaxReportFormat = "csv"
+)
+
+// TaxLossHarvestStatus represents the progress of a harvest's sell and substitute buy
+type TaxLossHarvestStatus string
+
+const (
+	TaxLossHarvestStatusPending   TaxLossHarvestStatus = "pending"   // Loss lots sold, substitute not yet bought
+	TaxLossHarvestStatusCompleted TaxLossHarvestStatus = "completed" // Substitute bought
+	TaxLossHarvestStatusFailed    TaxLossHarvestStatus = "failed"
+)
+
+// TaxLossHarvest records loss lots of a symbol sold and swapped into a correlated substitute.
+// Until WashWindowEndsAt, buys of the harvested symbol are redirected to the substitute.
+type TaxLossHarvest struct {
+	ID               uuid.UUID            `json:"id" db:"id"`
+	UserID           uuid.UUID            `json:"user_id" db:"user_id"`
+	Symbol           string               `json:"symbol" db:"symbol"`
+	SubstituteSymbol string               `json:"substitute_symbol" db:"substitute_symbol"`
+	Quantity         decimal.Decimal      `json:"quantity" db:"quantity"`
+	CostBasis        decimal.Decimal      `json:"cost_basis" db:"cost_basis"`
+	MarketValue      decimal.Decimal      `json:"market_value" db:"market_value"`
+	HarvestedLoss    decimal.Decimal      `json:"harvested_loss" db:"harvested_loss"`
+	SellOrderID      *uuid.UUID           `json:"sell_order_id,omitempty" db:"sell_order_id"`
+	BuyOrderID       *uuid.UUID           `json:"buy_order_id,omitempty" db:"buy_order_id"`
+	Status           TaxLossHarvestStatus `json:"status" db:"status"`
+	Error            *string              `json:"error,omitempty" db:"error"`
+	WashWindowEndsAt time.Time            `json:"wash_window_ends_at" db:"wash_window_ends_at"`
+	CreatedAt        time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// TaxLossHarvestReport totals the losses harvested for a user in a tax year
+type TaxLossHarvestReport struct {
+	UserID        uuid.UUID         `json:"user_id"`
+	TaxYear       int               `json:"tax_year"`
+	HarvestedLoss decimal.Decimal   `json:"harvested_loss"`
+	Harvests      []*TaxLossHarvest `json:"harvests"`
+}
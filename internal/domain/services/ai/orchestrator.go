@@ -73,6 +73,7 @@ type ContributionSummary struct {
 	Deposits  decimal.Decimal `json:"deposits"`
 	Roundups  decimal.Decimal `json:"roundups"`
 	Cashback  decimal.Decimal `json:"cashback"`
+	Dividends decimal.Decimal `json:"dividends"`
 	Total     decimal.Decimal `json:"total"`
 }
 
@@ -144,11 +145,11 @@ func (o *Orchestrator) GetTools() []ai.Tool {
 		},
 		{
 			Name:        ToolGetContributions,
-			Description: "Get user contributions (deposits, round-ups, cashback)",
+			Description: "Get user contributions (deposits, round-ups, cashback, dividends)",
 			Parameters: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"type":   map[string]interface{}{"type": "string", "enum": []string{"all", "deposit", "roundup", "cashback"}},
+					"type":   map[string]interface{}{"type": "string", "enum": []string{"all", "deposit", "roundup", "cashback", "dividend"}},
 					"period": map[string]interface{}{"type": "string", "enum": []string{"1w", "1m", "3m"}},
 				},
 			},
@@ -262,8 +263,9 @@ func (o *Orchestrator) executeTool(ctx context.Context, userID uuid.UUID, tc ai.
 		return map[string]interface{}{
 			"deposits": summary.Deposits.String(),
 			"roundups": summary.Roundups.String(),
-			"cashback": summary.Cashback.String(),
-			"total":    summary.Total.String(),
+			"cashback":  summary.Cashback.String(),
+			"dividends": summary.Dividends.String(),
+			"total":     summary.Total.String(),
 		}, nil
 
 	case ToolGetWeeklyNews:
@@ -48,6 +48,7 @@ type StreakRepository interface {
 type PortfolioDataProviderImpl struct {
 	portfolioValueProvider PortfolioValueProvider
 	positionRepo           PositionRepository
+	basketRepo             BasketRepository
 	logger                 *zap.Logger
 }
 
@@ -64,6 +65,24 @@ func NewPortfolioDataProvider(
 	}
 }
 
+// SetBasketRepository sets the curated basket repository used to name positions
+func (p *PortfolioDataProviderImpl) SetBasketRepository(basketRepo BasketRepository) {
+	p.basketRepo = basketRepo
+}
+
+// basketName returns a basket's curated name, falling back to its ID prefix
+func (p *PortfolioDataProviderImpl) basketName(ctx context.Context, basketID uuid.UUID) string {
+	if p.basketRepo != nil {
+		basket, err := p.basketRepo.GetByID(ctx, basketID)
+		if err != nil {
+			p.logger.Warn("Failed to get basket", zap.String("basket_id", basketID.String()), zap.Error(err))
+		} else if basket != nil {
+			return basket.Name
+		}
+	}
+	return "Basket " + basketID.String()[:8]
+}
+
 // GetWeeklyStats returns weekly portfolio statistics
 func (p *PortfolioDataProviderImpl) GetWeeklyStats(ctx context.Context, userID uuid.UUID) (*PortfolioStats, error) {
 	now := time.Now()
@@ -119,7 +138,7 @@ func (p *PortfolioDataProviderImpl) GetTopMovers(ctx context.Context, userID uui
 
 		movers = append(movers, &Mover{
 			Symbol:    pos.BasketID.String()[:8], // Use basket ID prefix as identifier
-			Name:      "Basket Position",
+			Name:      p.basketName(ctx, pos.BasketID),
 			Return:    returnAmt,
 			ReturnPct: returnPct,
 		})
@@ -165,7 +184,7 @@ func (p *PortfolioDataProviderImpl) GetAllocations(ctx context.Context, userID u
 
 		result = append(result, &Allocation{
 			BasketID:   pos.BasketID,
-			BasketName: "Basket " + pos.BasketID.String()[:8],
+			BasketName: p.basketName(ctx, pos.BasketID),
 			Value:      pos.MarketValue,
 			Weight:     weight,
 		})
@@ -203,10 +222,11 @@ func (a *ActivityDataProviderImpl) GetContributions(ctx context.Context, userID
 	}
 
 	summary := &ContributionSummary{
-		Deposits: decimal.Zero,
-		Roundups: decimal.Zero,
-		Cashback: decimal.Zero,
-		Total:    decimal.Zero,
+		Deposits:  decimal.Zero,
+		Roundups:  decimal.Zero,
+		Cashback:  decimal.Zero,
+		Dividends: decimal.Zero,
+		Total:     decimal.Zero,
 	}
 
 	for contribType, amountStr := range totals {
@@ -218,6 +238,8 @@ func (a *ActivityDataProviderImpl) GetContributions(ctx context.Context, userID
 			summary.Roundups = amount
 		case entities.ContributionTypeCashback:
 			summary.Cashback = amount
+		case entities.ContributionTypeDividend:
+			summary.Dividends = amount
 		}
 		summary.Total = summary.Total.Add(amount)
 	}
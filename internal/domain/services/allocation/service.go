@@ -2,6 +2,7 @@ package allocation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"time"
@@ -358,11 +359,20 @@ func (s *Service) ProcessIncomingFunds(ctx context.Context, req *entities.Incomi
 			bgCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			if err := s.autoInvestService.TriggerAutoInvestment(bgCtx, autoinvest.TriggerRequest{
+			err := s.autoInvestService.TriggerAutoInvestment(bgCtx, autoinvest.TriggerRequest{
 				UserID:        userID,
 				StashID:       stashID,
 				CorrelationID: correlationID,
-			}); err != nil {
+			})
+			switch {
+			case errors.Is(err, autoinvest.ErrDeferredUntilOpen):
+				s.logger.Info("Auto-investment deferred until market open, funds held in stash",
+					"user_id", userID)
+			case errors.Is(err, entities.ErrRiskAcknowledgmentRequired):
+				s.logger.Warn("Auto-investment awaiting risk acknowledgment, funds held in stash",
+					"user_id", userID,
+					"error", err)
+			case err != nil:
 				s.logger.Error("Failed to trigger auto-investment",
 					"user_id", userID,
 					"error", err)
@@ -33,6 +33,16 @@ type EventRepository interface {
 	GetUnprocessed(ctx context.Context, limit int) ([]*entities.AlpacaEvent, error)
 }
 
+// TaxLotRecorder opens and relieves tax lots from order fills
+type TaxLotRecorder interface {
+	RecordFill(ctx context.Context, order *entities.InvestmentOrder, event *entities.AlpacaOrderFillEvent) error
+}
+
+// DividendRecorder posts dividend and withholding activities to the ledger
+type DividendRecorder interface {
+	RecordDividend(ctx context.Context, userID uuid.UUID, activity *entities.AlpacaNonTradeActivityEvent) (*entities.Dividend, error)
+}
+
 // EventProcessor handles Alpaca webhook and SSE events
 type EventProcessor struct {
 	accountRepo  AccountRepository
@@ -40,6 +50,8 @@ type EventProcessor struct {
 	positionRepo PositionRepository
 	eventRepo    EventRepository
 	balanceRepo  BalanceRepository
+	taxLots      TaxLotRecorder
+	dividends    DividendRecorder
 	logger       *zap.Logger
 }
 
@@ -61,6 +73,16 @@ func NewEventProcessor(
 	}
 }
 
+// SetTaxLotRecorder sets the tax lot tracker updated from fills (optional)
+func (p *EventProcessor) SetTaxLotRecorder(taxLots TaxLotRecorder) {
+	p.taxLots = taxLots
+}
+
+// SetDividendRecorder sets the recorder for dividend activities (optional)
+func (p *EventProcessor) SetDividendRecorder(dividends DividendRecorder) {
+	p.dividends = dividends
+}
+
 // ProcessOrderFill handles order fill events from Alpaca
 func (p *EventProcessor) ProcessOrderFill(ctx context.Context, event *entities.AlpacaOrderFillEvent) error {
 	p.logger.Info("Processing order fill event",
@@ -92,6 +114,11 @@ func (p *EventProcessor) ProcessOrderFill(ctx context.Context, event *entities.A
 		if err := p.updatePositionFromFill(ctx, order, event); err != nil {
 			p.logger.Error("Failed to update position from fill", zap.Error(err))
 		}
+		if p.taxLots != nil {
+			if err := p.taxLots.RecordFill(ctx, order, event); err != nil {
+				p.logger.Error("Failed to record tax lots from fill", zap.Error(err))
+			}
+		}
 	}
 
 	p.logger.Info("Order fill processed",
@@ -194,6 +221,38 @@ func (p *EventProcessor) ProcessPositionUpdate(ctx context.Context, event *entit
 	return nil
 }
 
+// ProcessNonTradeActivity handles non-trade activity events. Only dividends and their tax
+// withholding are acted on; fees, journals and other activities are ignored.
+func (p *EventProcessor) ProcessNonTradeActivity(ctx context.Context, event *entities.AlpacaNonTradeActivityEvent) error {
+	if !entities.IsDividendActivity(event.Type()) {
+		return nil
+	}
+	if p.dividends == nil {
+		p.logger.Warn("Dividend recorder not configured, skipping dividend activity", zap.String("activity_id", event.ID))
+		return nil
+	}
+
+	p.logger.Info("Processing dividend activity",
+		zap.String("activity_id", event.ID),
+		zap.String("account_id", event.AccountID),
+		zap.String("activity_type", event.Type()),
+		zap.String("symbol", event.Symbol))
+
+	account, err := p.accountRepo.GetByAlpacaID(ctx, event.AccountID)
+	if err != nil {
+		return fmt.Errorf("get account: %w", err)
+	}
+	if account == nil {
+		p.logger.Warn("Account not found", zap.String("alpaca_account_id", event.AccountID))
+		return nil
+	}
+
+	if _, err := p.dividends.RecordDividend(ctx, account.UserID, event); err != nil {
+		return fmt.Errorf("record dividend: %w", err)
+	}
+	return nil
+}
+
 // StoreEvent stores a raw event for later processing
 func (p *EventProcessor) StoreEvent(ctx context.Context, eventType, eventID string, payload []byte, userID *uuid.UUID, alpacaAccountID *uuid.UUID) error {
 	event := &entities.AlpacaEvent{
@@ -254,6 +313,13 @@ func (p *EventProcessor) processEvent(ctx context.Context, event *entities.Alpac
 		}
 		return p.ProcessPositionUpdate(ctx, &posEvent)
 
+	case "nta":
+		var activity entities.AlpacaNonTradeActivityEvent
+		if err := json.Unmarshal(event.Payload, &activity); err != nil {
+			return fmt.Errorf("unmarshal non-trade activity: %w", err)
+		}
+		return p.ProcessNonTradeActivity(ctx, &activity)
+
 	default:
 		p.logger.Debug("Unknown event type", zap.String("type", event.EventType))
 		return nil
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -18,6 +20,10 @@ import (
 
 var tracer = otel.Tracer("autoinvest-service")
 
+// ErrDeferredUntilOpen is returned when the market is closed and the auto-investment has been
+// queued to run at the next open, leaving the funds in the stash
+var ErrDeferredUntilOpen = errors.New("market closed, auto-investment deferred until next open")
+
 // Config holds configuration for auto-investment
 type Config struct {
 	// MinThreshold is the minimum stash balance to trigger auto-investment
@@ -41,6 +47,31 @@ type OrderPlacer interface {
 // StrategyEngine defines strategy selection operations
 type StrategyEngine interface {
 	GetStrategy(ctx context.Context, userID uuid.UUID) (*strategy.StrategyResult, error)
+	RecordAssignment(ctx context.Context, userID uuid.UUID, correlationID string, amount decimal.Decimal, result *strategy.StrategyResult) error
+}
+
+// SuitabilityChecker checks strategies against the user's assessed risk band
+type SuitabilityChecker interface {
+	CheckSuitability(ctx context.Context, userID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand, acknowledged bool) error
+}
+
+// MarketHours reports when the exchange is open for regular trading
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+	NextOpen(t time.Time) time.Time
+}
+
+// CashFlowInvestor invests deposits into the targets of the user's active rebalancing config
+type CashFlowInvestor interface {
+	HasActiveConfig(ctx context.Context, userID uuid.UUID) (bool, error)
+	InvestCashFlow(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, source entities.CashFlowSource) ([]*entities.InvestmentOrder, decimal.Decimal, error)
+}
+
+// DeferralStore queues auto-investments triggered while the market is closed
+type DeferralStore interface {
+	SaveDeferral(ctx context.Context, deferral *entities.DeferredAutoInvestment) error
+	GetDueDeferrals(ctx context.Context, now time.Time, limit int) ([]*entities.DeferredAutoInvestment, error)
+	DeleteDeferral(ctx context.Context, correlationID string) error
 }
 
 // Service handles automatic investment from stash balance
@@ -48,6 +79,10 @@ type Service struct {
 	ledgerService  LedgerService
 	orderPlacer    OrderPlacer
 	strategyEngine StrategyEngine
+	suitability    SuitabilityChecker
+	marketHours    MarketHours
+	deferrals      DeferralStore
+	cashFlow       CashFlowInvestor
 	config         Config
 	logger         *logger.Logger
 }
@@ -79,6 +114,29 @@ func (s *Service) SetStrategyEngine(engine StrategyEngine) {
 	s.strategyEngine = engine
 }
 
+// SetSuitabilityChecker sets the risk profile check for selected strategies (optional)
+func (s *Service) SetSuitabilityChecker(checker SuitabilityChecker) {
+	s.suitability = checker
+}
+
+// SetMarketHours sets the exchange calendar used to hold auto-investments until regular
+// hours (optional)
+func (s *Service) SetMarketHours(marketHours MarketHours) {
+	s.marketHours = marketHours
+}
+
+// SetDeferralStore sets the queue auto-investments wait in while the market is closed (optional).
+// Without it, orders are placed straight away and queue at the broker until the open.
+func (s *Service) SetDeferralStore(deferrals DeferralStore) {
+	s.deferrals = deferrals
+}
+
+// SetCashFlowInvestor sets the investor used for users with an active rebalancing config, whose
+// deposits go into their own targets rather than the strategy engine's (optional)
+func (s *Service) SetCashFlowInvestor(investor CashFlowInvestor) {
+	s.cashFlow = investor
+}
+
 // TriggerRequest contains parameters for triggering auto-investment.
 // This type is aliased in the allocation package as AutoInvestTriggerRequest.
 type TriggerRequest struct {
@@ -142,18 +200,84 @@ func (s *Service) executeAutoInvestment(ctx context.Context, userID, stashID uui
 		))
 	defer span.End()
 
+	// Users with an active rebalancing config invest into the targets they chose
+	useTargets := false
+	if s.cashFlow != nil {
+		active, err := s.cashFlow.HasActiveConfig(ctx, userID)
+		if err != nil {
+			s.logger.Warn("Failed to check rebalancing config, using strategy",
+				"user_id", userID,
+				"error", err)
+		}
+		useTargets = active
+	}
+
+	// Get strategy allocation
+	var strategyResult *strategy.StrategyResult
+	var strategyErr error
+	if !useTargets {
+		strategyResult, strategyErr = s.getStrategyAllocation(ctx, userID)
+	}
+
+	// A strategy above the user's risk band is only used with a standing acknowledgment;
+	// otherwise the funds stay in the stash and the caller is told an acknowledgment is needed
+	if !useTargets && strategyErr == nil && s.suitability != nil {
+		if err := s.suitability.CheckSuitability(ctx, userID, entities.RiskProductAutoInvest, nil, strategyResult.RiskBand, false); err != nil {
+			if errors.Is(err, entities.ErrRiskAcknowledgmentRequired) {
+				s.logger.Warn("Holding auto-investment, strategy exceeds risk profile",
+					"user_id", userID,
+					"strategy", strategyResult.StrategyName,
+					"risk_band", strategyResult.RiskBand)
+				span.SetAttributes(attribute.String("status", "acknowledgment_required"))
+				return fmt.Errorf("%w: %s is %s", entities.ErrRiskAcknowledgmentRequired, strategyResult.StrategyName, strategyResult.RiskBand)
+			}
+			span.RecordError(err)
+			return fmt.Errorf("failed to check strategy suitability: %w", err)
+		}
+	}
+
+	// Outside regular hours the funds stay in the stash and the investment runs at the next open,
+	// so orders are priced against the opening market rather than queued at the broker
+	if s.marketHours != nil {
+		if now := time.Now(); !s.marketHours.IsOpen(now) {
+			nextOpen := s.marketHours.NextOpen(now)
+			span.SetAttributes(attribute.String("queued_until", nextOpen.Format(time.RFC3339)))
+			if s.deferrals != nil {
+				if err := s.deferrals.SaveDeferral(ctx, &entities.DeferredAutoInvestment{
+					CorrelationID: correlationID,
+					UserID:        userID,
+					StashID:       stashID,
+					NotBefore:     nextOpen,
+					CreatedAt:     now,
+				}); err != nil {
+					span.RecordError(err)
+					return fmt.Errorf("failed to defer auto-investment: %w", err)
+				}
+				s.logger.Info("Market closed, auto-investment deferred until next open",
+					"user_id", userID,
+					"next_open", nextOpen)
+				return ErrDeferredUntilOpen
+			}
+			s.logger.Info("Market closed, auto-investment orders will fill at next open",
+				"user_id", userID,
+				"next_open", nextOpen)
+		}
+	}
+
 	// Transfer from stash to fiat exposure (buying power)
 	if err := s.transferStashToFiatExposure(ctx, userID, stashID, amount, correlationID); err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("failed to transfer to buying power: %w", err)
 	}
 
-	// Get strategy allocation
-	strategyResult, err := s.getStrategyAllocation(ctx, userID)
-	if err != nil {
+	if useTargets {
+		return s.investIntoTargets(ctx, userID, amount)
+	}
+
+	if strategyErr != nil {
 		s.logger.Warn("Failed to get strategy, using fallback single asset",
 			"user_id", userID,
-			"error", err)
+			"error", strategyErr)
 		// Fallback to single SPY order if strategy engine fails
 		return s.placeSingleOrder(ctx, userID, stashID, "SPY", amount, correlationID)
 	}
@@ -164,10 +288,82 @@ func (s *Service) executeAutoInvestment(ctx context.Context, userID, stashID uui
 		"allocations", len(strategyResult.Allocations),
 		"total_amount", amount)
 
+	// Record the strategy version behind this allocation for later explanation
+	if s.strategyEngine != nil {
+		if err := s.strategyEngine.RecordAssignment(ctx, userID, correlationID, amount, strategyResult); err != nil {
+			s.logger.Warn("Failed to record strategy assignment",
+				"user_id", userID,
+				"strategy", strategyResult.StrategyName,
+				"error", err)
+		}
+	}
+
 	// Place orders for each allocation
 	return s.placeStrategyOrders(ctx, userID, stashID, amount, correlationID, strategyResult)
 }
 
+// ProcessDeferredInvestments triggers auto-investments deferred while the market was closed once
+// it has opened. A deferral is kept for the next pass only when triggering it fails outright; one
+// that still needs a risk acknowledgment is dropped, as the next deposit triggers it again. It
+// returns the number of deferrals triggered.
+func (s *Service) ProcessDeferredInvestments(ctx context.Context, limit int) (int, error) {
+	if s.deferrals == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	if s.marketHours != nil && !s.marketHours.IsOpen(now) {
+		return 0, nil
+	}
+
+	deferrals, err := s.deferrals.GetDueDeferrals(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deferred auto-investments: %w", err)
+	}
+
+	triggered := 0
+	for _, deferral := range deferrals {
+		err := s.TriggerAutoInvestment(ctx, TriggerRequest{
+			UserID:        deferral.UserID,
+			StashID:       deferral.StashID,
+			CorrelationID: deferral.CorrelationID,
+		})
+		switch {
+		case errors.Is(err, ErrDeferredUntilOpen):
+			// The market closed again before this pass reached it
+			continue
+		case err != nil && !errors.Is(err, entities.ErrRiskAcknowledgmentRequired):
+			s.logger.Error("Failed to trigger deferred auto-investment",
+				"user_id", deferral.UserID,
+				"correlation_id", deferral.CorrelationID,
+				"error", err)
+			continue
+		}
+		if err := s.deferrals.DeleteDeferral(ctx, deferral.CorrelationID); err != nil {
+			s.logger.Warn("Failed to clear deferred auto-investment",
+				"correlation_id", deferral.CorrelationID,
+				"error", err)
+		}
+		triggered++
+	}
+	return triggered, nil
+}
+
+// investIntoTargets invests a deposit already moved to buying power into the underweight assets
+// of the user's rebalancing config. Whatever trade minimums leave over stays as buying power.
+func (s *Service) investIntoTargets(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	orders, invested, err := s.cashFlow.InvestCashFlow(ctx, userID, amount, entities.CashFlowSourceDeposit)
+	if err != nil {
+		return fmt.Errorf("failed to invest into rebalancing targets: %w", err)
+	}
+
+	s.logger.Info("Auto-investment placed against rebalancing targets",
+		"user_id", userID,
+		"amount", amount,
+		"invested", invested,
+		"orders", len(orders))
+	return nil
+}
+
 // getStrategyAllocation retrieves the strategy allocation for a user
 func (s *Service) getStrategyAllocation(ctx context.Context, userID uuid.UUID) (*strategy.StrategyResult, error) {
 	if s.strategyEngine == nil {
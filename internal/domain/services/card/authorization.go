@@ -0,0 +1,475 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/security"
+)
+
+var (
+	ErrAuthorizationDeclined = errors.New("card authorization declined")
+	ErrAuthorizationTimeout  = errors.New("card authorization exceeded latency budget")
+)
+
+// FraudChecker scores a transaction for fraud and velocity risk
+type FraudChecker interface {
+	CheckTransaction(ctx context.Context, txCtx *security.TransactionContext) (*security.FraudCheckResult, error)
+}
+
+// AuthorizationPolicy configures the rules applied to real-time card authorizations
+type AuthorizationPolicy struct {
+	BlockedMCCs          []string        // Merchant category codes that are always declined
+	MaxTransactionAmount decimal.Decimal // Per-transaction ceiling; zero disables the check
+	DailyLimit           decimal.Decimal // Rolling per-card daily spend ceiling; zero disables the check
+	AllowedCountries     []string        // ISO country codes; empty allows all
+	AllowedCurrencies    []string        // ISO currency codes; empty allows all
+	FraudBlockScore      float64         // Fraud score at or above which the authorization is declined
+	DecisionTimeout      time.Duration   // Latency budget for the whole pipeline
+	StandInLimit         decimal.Decimal // Fallback approves up to this amount when the budget expires; zero always declines
+}
+
+// DefaultAuthorizationPolicy returns the default authorization policy
+func DefaultAuthorizationPolicy() AuthorizationPolicy {
+	return AuthorizationPolicy{
+		BlockedMCCs: []string{
+			"7995", // Betting, casino gambling
+			"6051", // Quasi-cash, crypto purchases
+			"4829", // Wire transfers and money orders
+		},
+		MaxTransactionAmount: decimal.NewFromInt(5000),
+		DailyLimit:           decimal.NewFromInt(10000),
+		AllowedCurrencies:    []string{"USD"},
+		FraudBlockScore:      0.8,
+		DecisionTimeout:      1500 * time.Millisecond,
+		StandInLimit:         decimal.Zero,
+	}
+}
+
+// AuthorizationRequest carries the details of a card authorization through the pipeline
+type AuthorizationRequest struct {
+	BridgeCardID     string
+	BridgeTransID    string // Optional; when set the decision is persisted on the card transaction
+	Amount           decimal.Decimal
+	Currency         string
+	MerchantName     string
+	MerchantCategory string // Merchant category code (MCC) or category label
+	MerchantCountry  string
+	CardNotPresent   bool // Online or other card-not-present transaction
+}
+
+// RuleResult is the outcome of a single authorization rule
+type RuleResult struct {
+	DeclineCode entities.CardDeclineCode // Empty when the rule passes
+	RiskScore   float64
+}
+
+// AuthorizationRule is a single step of the authorization pipeline.
+// Rules are evaluated in order and the first decline wins.
+type AuthorizationRule interface {
+	Name() string
+	Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error)
+}
+
+func pass() *RuleResult {
+	return &RuleResult{}
+}
+
+func decline(code entities.CardDeclineCode) *RuleResult {
+	return &RuleResult{DeclineCode: code}
+}
+
+// cardStatusRule declines authorizations on cards that are not active
+type cardStatusRule struct{}
+
+func (cardStatusRule) Name() string { return "card_status" }
+
+func (cardStatusRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	switch card.Status {
+	case entities.CardStatusActive:
+		return pass(), nil
+	case entities.CardStatusFrozen:
+		return decline(entities.CardDeclineCardFrozen), nil
+	case entities.CardStatusCancelled:
+		return decline(entities.CardDeclineCardCancelled), nil
+	default:
+		return decline(entities.CardDeclineCardInactive), nil
+	}
+}
+
+// merchantCategoryRule declines blocked merchant categories
+type merchantCategoryRule struct {
+	blocked map[string]bool
+}
+
+func newMerchantCategoryRule(mccs []string) *merchantCategoryRule {
+	blocked := make(map[string]bool, len(mccs))
+	for _, mcc := range mccs {
+		blocked[strings.ToLower(strings.TrimSpace(mcc))] = true
+	}
+	return &merchantCategoryRule{blocked: blocked}
+}
+
+func (r *merchantCategoryRule) Name() string { return "merchant_category" }
+
+func (r *merchantCategoryRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	if r.blocked[strings.ToLower(strings.TrimSpace(req.MerchantCategory))] {
+		return decline(entities.CardDeclineMerchantBlocked), nil
+	}
+	return pass(), nil
+}
+
+// geographyRule restricts merchant country and transaction currency
+type geographyRule struct {
+	countries  map[string]bool
+	currencies map[string]bool
+}
+
+func newGeographyRule(countries, currencies []string) *geographyRule {
+	return &geographyRule{
+		countries:  upperSet(countries),
+		currencies: upperSet(currencies),
+	}
+}
+
+func (r *geographyRule) Name() string { return "geography" }
+
+func (r *geographyRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	if len(r.countries) > 0 && req.MerchantCountry != "" && !r.countries[strings.ToUpper(req.MerchantCountry)] {
+		return decline(entities.CardDeclineCountryRestricted), nil
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = card.Currency
+	}
+	if len(r.currencies) > 0 && currency != "" && !r.currencies[strings.ToUpper(currency)] {
+		return decline(entities.CardDeclineCurrencyRestricted), nil
+	}
+	return pass(), nil
+}
+
+// transactionLimitRule enforces the per-transaction ceiling
+type transactionLimitRule struct {
+	max decimal.Decimal
+}
+
+func (r *transactionLimitRule) Name() string { return "transaction_limit" }
+
+func (r *transactionLimitRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	if r.max.IsPositive() && req.Amount.GreaterThan(r.max) {
+		return decline(entities.CardDeclineTransactionLimit), nil
+	}
+	return pass(), nil
+}
+
+// dailyLimitRule enforces the per-card daily spend ceiling
+type dailyLimitRule struct {
+	repo  CardRepository
+	limit decimal.Decimal
+}
+
+func (r *dailyLimitRule) Name() string { return "daily_limit" }
+
+func (r *dailyLimitRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	if !r.limit.IsPositive() {
+		return pass(), nil
+	}
+	spent, err := r.repo.GetCardSpendSince(ctx, card.ID, startOfDay(time.Now().UTC()))
+	if err != nil {
+		return decline(entities.CardDeclineProcessingError), err
+	}
+	if spent.Add(req.Amount).GreaterThan(r.limit) {
+		return decline(entities.CardDeclineDailyLimit), nil
+	}
+	return pass(), nil
+}
+
+// balanceRule declines when the spend balance cannot cover the amount
+type balanceRule struct {
+	balanceProvider BalanceProvider
+}
+
+func (r *balanceRule) Name() string { return "spend_balance" }
+
+func (r *balanceRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	balance, err := r.balanceProvider.GetSpendBalance(ctx, card.UserID)
+	if err != nil {
+		return decline(entities.CardDeclineBalanceCheckFailed), err
+	}
+	if balance.LessThan(req.Amount) {
+		return decline(entities.CardDeclineInsufficientFunds), nil
+	}
+	return pass(), nil
+}
+
+// velocityRule scores the authorization with the fraud detection service
+type velocityRule struct {
+	checker    FraudChecker
+	blockScore float64
+	logger     *zap.Logger
+}
+
+func (r *velocityRule) Name() string { return "velocity" }
+
+func (r *velocityRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	if r.checker == nil {
+		return pass(), nil
+	}
+	result, err := r.checker.CheckTransaction(ctx, &security.TransactionContext{
+		UserID:      card.UserID,
+		Amount:      req.Amount,
+		Type:        "card_payment",
+		Destination: req.MerchantName,
+	})
+	if err != nil || result == nil {
+		// Fraud scoring is advisory; never decline because the scorer is unavailable
+		r.logger.Warn("Fraud check unavailable for card authorization",
+			zap.String("card_id", card.ID.String()),
+			zap.Error(err))
+		return pass(), nil
+	}
+
+	res := &RuleResult{RiskScore: result.Score}
+	for _, signal := range result.Signals {
+		if signal.Type == "velocity" && result.Action == security.FraudActionBlock {
+			res.DeclineCode = entities.CardDeclineVelocityExceeded
+			return res, nil
+		}
+	}
+	if result.Action == security.FraudActionBlock || result.Score >= r.blockScore {
+		res.DeclineCode = entities.CardDeclineSuspectedFraud
+	}
+	return res, nil
+}
+
+// buildAuthorizationRules assembles the default pipeline from the policy
+func (s *Service) buildAuthorizationRules() []AuthorizationRule {
+	return []AuthorizationRule{
+		cardStatusRule{},
+		&cardControlsRule{repo: s.repo},
+		newMerchantCategoryRule(s.authPolicy.BlockedMCCs),
+		newGeographyRule(s.authPolicy.AllowedCountries, s.authPolicy.AllowedCurrencies),
+		&transactionLimitRule{max: s.authPolicy.MaxTransactionAmount},
+		&dailyLimitRule{repo: s.repo, limit: s.authPolicy.DailyLimit},
+		&balanceRule{balanceProvider: s.balanceProvider},
+		&velocityRule{checker: s.fraudChecker, blockScore: s.authPolicy.FraudBlockScore, logger: s.logger},
+	}
+}
+
+// SetAuthorizationPolicy replaces the authorization policy and rebuilds the default pipeline.
+// Rules added with AddAuthorizationRule are kept.
+func (s *Service) SetAuthorizationPolicy(policy AuthorizationPolicy) {
+	s.authPolicy = policy
+	s.authRules = append(s.buildAuthorizationRules(), s.extraAuthRules...)
+}
+
+// SetFraudChecker sets the fraud checker used for velocity and risk scoring (optional)
+func (s *Service) SetFraudChecker(checker FraudChecker) {
+	s.fraudChecker = checker
+	s.authRules = append(s.buildAuthorizationRules(), s.extraAuthRules...)
+}
+
+// AddAuthorizationRule appends a custom rule to the end of the pipeline
+func (s *Service) AddAuthorizationRule(rule AuthorizationRule) {
+	s.extraAuthRules = append(s.extraAuthRules, rule)
+	s.authRules = append(s.authRules, rule)
+}
+
+// AuthorizeTransaction runs the authorization pipeline within the latency budget and
+// returns a structured decision. When the request carries a Bridge transaction ID the
+// decision is persisted on the card transaction.
+func (s *Service) AuthorizeTransaction(ctx context.Context, req *AuthorizationRequest) (*entities.CardAuthorizationDecision, error) {
+	start := time.Now()
+
+	card, err := s.repo.GetByBridgeCardID(ctx, req.BridgeCardID)
+	if err != nil || card == nil {
+		return &entities.CardAuthorizationDecision{DeclineCode: entities.CardDeclineCardNotFound}, ErrCardNotFound
+	}
+
+	var decision *entities.CardAuthorizationDecision
+	if card.Controls, err = s.repo.GetCardControls(ctx, card.ID); err != nil {
+		// Fail closed: user-configured restrictions must never be skipped
+		decision = &entities.CardAuthorizationDecision{DeclineCode: entities.CardDeclineProcessingError, DeclinedBy: "card_controls"}
+	} else {
+		decision, err = s.evaluateWithinBudget(ctx, card, req)
+	}
+	decision.LatencyMs = time.Since(start).Milliseconds()
+
+	if req.BridgeTransID != "" {
+		s.persistAuthorization(ctx, card, req, decision)
+	}
+
+	if decision.Approved {
+		s.lockMerchantOnFirstUse(ctx, card, req.MerchantName)
+		s.logger.Info("Card authorization approved",
+			zap.String("card_id", card.ID.String()),
+			zap.String("amount", req.Amount.String()),
+			zap.Float64("risk_score", decision.RiskScore),
+			zap.Bool("fallback", decision.Fallback),
+			zap.Int64("latency_ms", decision.LatencyMs))
+		return decision, nil
+	}
+
+	s.logger.Warn("Card authorization declined",
+		zap.String("card_id", card.ID.String()),
+		zap.String("amount", req.Amount.String()),
+		zap.String("decline_code", string(decision.DeclineCode)),
+		zap.String("rule", decision.DeclinedBy),
+		zap.Int64("latency_ms", decision.LatencyMs))
+
+	if err != nil {
+		return decision, err
+	}
+	return decision, declineError(decision.DeclineCode)
+}
+
+// evaluateWithinBudget runs the rules and falls back to a stand-in decision if the budget expires
+func (s *Service) evaluateWithinBudget(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*entities.CardAuthorizationDecision, error) {
+	budget := s.authPolicy.DecisionTimeout
+	if budget <= 0 {
+		return s.runAuthorizationRules(ctx, card, req)
+	}
+
+	budgetCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	type outcome struct {
+		decision *entities.CardAuthorizationDecision
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		decision, err := s.runAuthorizationRules(budgetCtx, card, req)
+		done <- outcome{decision: decision, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.decision, out.err
+	case <-budgetCtx.Done():
+		s.logger.Warn("Card authorization exceeded latency budget, using fallback decision",
+			zap.String("card_id", card.ID.String()),
+			zap.Duration("budget", budget))
+		return s.fallbackDecision(card, req), nil
+	}
+}
+
+// runAuthorizationRules evaluates each rule in order; the first decline wins
+func (s *Service) runAuthorizationRules(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*entities.CardAuthorizationDecision, error) {
+	decision := &entities.CardAuthorizationDecision{Approved: true}
+	for _, rule := range s.authRules {
+		result, err := rule.Evaluate(ctx, card, req)
+		if result != nil && result.RiskScore > decision.RiskScore {
+			decision.RiskScore = result.RiskScore
+		}
+		if err != nil {
+			s.logger.Error("Card authorization rule failed",
+				zap.String("rule", rule.Name()),
+				zap.Error(err))
+			decision.Approved = false
+			decision.DeclinedBy = rule.Name()
+			decision.DeclineCode = entities.CardDeclineProcessingError
+			if result != nil && result.DeclineCode != "" {
+				decision.DeclineCode = result.DeclineCode
+			}
+			return decision, err
+		}
+		if result != nil && result.DeclineCode != "" {
+			decision.Approved = false
+			decision.DeclinedBy = rule.Name()
+			decision.DeclineCode = result.DeclineCode
+			return decision, nil
+		}
+	}
+	return decision, nil
+}
+
+// fallbackDecision is the stand-in decision used when the pipeline misses its latency budget
+func (s *Service) fallbackDecision(card *entities.BridgeCard, req *AuthorizationRequest) *entities.CardAuthorizationDecision {
+	decision := &entities.CardAuthorizationDecision{Fallback: true}
+	if card.Status == entities.CardStatusActive &&
+		s.authPolicy.StandInLimit.IsPositive() &&
+		req.Amount.LessThanOrEqual(s.authPolicy.StandInLimit) {
+		decision.Approved = true
+		return decision
+	}
+	decision.DeclineCode = entities.CardDeclineAuthorizationTimeout
+	decision.DeclinedBy = "fallback"
+	return decision
+}
+
+// persistAuthorization records the authorization decision on the card transaction
+func (s *Service) persistAuthorization(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest, decision *entities.CardAuthorizationDecision) {
+	existing, _ := s.repo.GetTransactionByBridgeID(ctx, req.BridgeTransID)
+	if existing != nil {
+		return
+	}
+
+	riskScore := decision.RiskScore
+	currency := req.Currency
+	if currency == "" {
+		currency = card.Currency
+	}
+	tx := &entities.BridgeCardTransaction{
+		ID:               uuid.New(),
+		CardID:           card.ID,
+		UserID:           card.UserID,
+		BridgeTransID:    req.BridgeTransID,
+		Type:             "authorization",
+		Amount:           req.Amount,
+		Currency:         currency,
+		MerchantName:     nilIfEmpty(req.MerchantName),
+		MerchantCategory: nilIfEmpty(req.MerchantCategory),
+		Status:           string(entities.CardTxStatusPending),
+		RiskScore:        &riskScore,
+	}
+	if !decision.Approved {
+		tx.Status = string(entities.CardTxStatusDeclined)
+		tx.DeclineCode = nilIfEmpty(string(decision.DeclineCode))
+		tx.DeclineReason = nilIfEmpty(string(decision.DeclineCode))
+	}
+	s.enrichTransaction(ctx, tx)
+
+	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
+		s.logger.Error("Failed to persist card authorization decision",
+			zap.String("bridge_trans_id", req.BridgeTransID),
+			zap.Error(err))
+	}
+}
+
+// declineError maps a decline code to the error returned to callers
+func declineError(code entities.CardDeclineCode) error {
+	switch code {
+	case entities.CardDeclineCardNotFound:
+		return ErrCardNotFound
+	case entities.CardDeclineCardFrozen:
+		return ErrCardFrozen
+	case entities.CardDeclineCardCancelled:
+		return ErrCardCancelled
+	case entities.CardDeclineInsufficientFunds:
+		return ErrInsufficientFunds
+	case entities.CardDeclineAuthorizationTimeout:
+		return ErrAuthorizationTimeout
+	default:
+		return ErrAuthorizationDeclined
+	}
+}
+
+func upperSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToUpper(strings.TrimSpace(v))] = true
+	}
+	return set
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
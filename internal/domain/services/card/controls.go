@@ -0,0 +1,327 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+const (
+	atmMCC             = "6011" // Automated cash disbursements
+	cardHomeCountry    = "US"
+	singleUseCancelled = "single_use_consumed"
+)
+
+var (
+	ErrInvalidCardControls = errors.New("invalid card controls")
+	ErrSingleUseCard       = errors.New("single-use card controls cannot be changed")
+)
+
+var mccPattern = regexp.MustCompile(`^\d{4}$`)
+
+// cardControlsRule enforces the user's per-card controls
+type cardControlsRule struct {
+	repo CardRepository
+}
+
+func (r *cardControlsRule) Name() string { return "card_controls" }
+
+func (r *cardControlsRule) Evaluate(ctx context.Context, card *entities.BridgeCard, req *AuthorizationRequest) (*RuleResult, error) {
+	controls := card.Controls
+	if controls == nil {
+		return pass(), nil
+	}
+
+	mcc := strings.TrimSpace(req.MerchantCategory)
+	if mcc == atmMCC && !controls.ATMEnabled {
+		return decline(entities.CardDeclineATMDisabled), nil
+	}
+	if req.CardNotPresent && !controls.OnlineEnabled {
+		return decline(entities.CardDeclineOnlineDisabled), nil
+	}
+	if req.MerchantCountry != "" && !strings.EqualFold(req.MerchantCountry, cardHomeCountry) && !controls.InternationalEnabled {
+		return decline(entities.CardDeclineInternationalBlocked), nil
+	}
+	if containsMCC(controls.BlockedMCCs, mcc) ||
+		(len(controls.AllowedMCCs) > 0 && !containsMCC(controls.AllowedMCCs, mcc)) {
+		return decline(entities.CardDeclineMerchantBlocked), nil
+	}
+	if controls.MerchantLocked && controls.LockedMerchant != nil &&
+		normalizeMerchant(req.MerchantName) != *controls.LockedMerchant {
+		return decline(entities.CardDeclineMerchantLocked), nil
+	}
+	if controls.PerTransactionLimit != nil && req.Amount.GreaterThan(*controls.PerTransactionLimit) {
+		return decline(entities.CardDeclineTransactionLimit), nil
+	}
+
+	now := time.Now().UTC()
+	if controls.DailyLimit != nil {
+		spent, err := r.repo.GetCardSpendSince(ctx, card.ID, startOfDay(now))
+		if err != nil {
+			return decline(entities.CardDeclineProcessingError), err
+		}
+		if spent.Add(req.Amount).GreaterThan(*controls.DailyLimit) {
+			return decline(entities.CardDeclineDailyLimit), nil
+		}
+	}
+	if controls.MonthlyLimit != nil {
+		spent, err := r.repo.GetCardSpendSince(ctx, card.ID, startOfMonth(now))
+		if err != nil {
+			return decline(entities.CardDeclineProcessingError), err
+		}
+		if spent.Add(req.Amount).GreaterThan(*controls.MonthlyLimit) {
+			return decline(entities.CardDeclineMonthlyLimit), nil
+		}
+	}
+	return pass(), nil
+}
+
+// GetCardControls retrieves the controls for a user's card
+func (s *Service) GetCardControls(ctx context.Context, userID, cardID uuid.UUID) (*entities.CardControls, error) {
+	card, err := s.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadCardControls(ctx, card.ID)
+}
+
+// UpdateCardControls applies a partial update to a card's controls
+func (s *Service) UpdateCardControls(ctx context.Context, userID, cardID uuid.UUID, req *entities.UpdateCardControlsRequest) (*entities.CardControls, error) {
+	card, err := s.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == entities.CardStatusCancelled {
+		return nil, ErrCardCancelled
+	}
+
+	for _, limit := range []*decimal.Decimal{req.PerTransactionLimit, req.DailyLimit, req.MonthlyLimit} {
+		if limit != nil && limit.IsNegative() {
+			return nil, fmt.Errorf("%w: limits cannot be negative", ErrInvalidCardControls)
+		}
+	}
+
+	controls, err := s.loadCardControls(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	if controls.SingleUse && (req.MerchantLocked != nil || req.PerTransactionLimit != nil) {
+		return nil, ErrSingleUseCard
+	}
+
+	if req.PerTransactionLimit != nil {
+		controls.PerTransactionLimit = limitOrNil(*req.PerTransactionLimit)
+	}
+	if req.DailyLimit != nil {
+		controls.DailyLimit = limitOrNil(*req.DailyLimit)
+	}
+	if req.MonthlyLimit != nil {
+		controls.MonthlyLimit = limitOrNil(*req.MonthlyLimit)
+	}
+	if req.AllowedMCCs != nil {
+		if controls.AllowedMCCs, err = normalizeMCCs(*req.AllowedMCCs); err != nil {
+			return nil, err
+		}
+	}
+	if req.BlockedMCCs != nil {
+		if controls.BlockedMCCs, err = normalizeMCCs(*req.BlockedMCCs); err != nil {
+			return nil, err
+		}
+	}
+	if req.MerchantLocked != nil {
+		controls.MerchantLocked = *req.MerchantLocked
+		if !controls.MerchantLocked {
+			controls.LockedMerchant = nil
+		}
+	}
+	if req.OnlineEnabled != nil {
+		controls.OnlineEnabled = *req.OnlineEnabled
+	}
+	if req.ATMEnabled != nil {
+		controls.ATMEnabled = *req.ATMEnabled
+	}
+	if req.InternationalEnabled != nil {
+		controls.InternationalEnabled = *req.InternationalEnabled
+	}
+
+	if err := validateCardControls(controls); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpsertCardControls(ctx, controls); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Card controls updated",
+		zap.String("card_id", card.ID.String()),
+		zap.String("user_id", userID.String()))
+	return controls, nil
+}
+
+// CreateSingleUseCard issues a disposable virtual card that is cancelled after its first capture
+func (s *Service) CreateSingleUseCard(ctx context.Context, userID uuid.UUID, req *entities.CreateSingleUseCardRequest) (*entities.BridgeCard, error) {
+	if req.Limit != nil && req.Limit.IsNegative() {
+		return nil, fmt.Errorf("%w: limits cannot be negative", ErrInvalidCardControls)
+	}
+
+	controls := entities.DefaultCardControls(uuid.Nil)
+	controls.SingleUse = true
+	controls.MerchantLocked = req.MerchantLocked
+	if req.Limit != nil {
+		controls.PerTransactionLimit = limitOrNil(*req.Limit)
+	}
+	if err := validateCardControls(controls); err != nil {
+		return nil, err
+	}
+
+	card, err := s.issueCard(ctx, userID, entities.CardTypeVirtual, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	controls.CardID = card.ID
+	if err := s.repo.UpsertCardControls(ctx, controls); err != nil {
+		// A single-use card without its controls would behave like a permanent card
+		s.cancelCard(ctx, card, singleUseCancelled)
+		return nil, err
+	}
+
+	card.Controls = controls
+	s.logger.Info("Single-use card created",
+		zap.String("card_id", card.ID.String()),
+		zap.String("user_id", userID.String()))
+	return card, nil
+}
+
+// loadCardControls returns the stored controls for a card, or the defaults if none exist
+func (s *Service) loadCardControls(ctx context.Context, cardID uuid.UUID) (*entities.CardControls, error) {
+	controls, err := s.repo.GetCardControls(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if controls == nil {
+		return entities.DefaultCardControls(cardID), nil
+	}
+	return controls, nil
+}
+
+// lockMerchantOnFirstUse binds a merchant-locked card to the merchant of its first approved authorization
+func (s *Service) lockMerchantOnFirstUse(ctx context.Context, card *entities.BridgeCard, merchantName string) {
+	controls := card.Controls
+	if controls == nil || !controls.MerchantLocked || controls.LockedMerchant != nil {
+		return
+	}
+	merchant := normalizeMerchant(merchantName)
+	if merchant == "" {
+		return
+	}
+	if err := s.repo.LockCardMerchant(ctx, card.ID, merchant); err != nil {
+		s.logger.Error("Failed to lock card to merchant",
+			zap.String("card_id", card.ID.String()),
+			zap.Error(err))
+		return
+	}
+	controls.LockedMerchant = &merchant
+}
+
+// consumeSingleUseCard cancels a single-use card once it has been captured
+func (s *Service) consumeSingleUseCard(ctx context.Context, card *entities.BridgeCard) {
+	controls, err := s.repo.GetCardControls(ctx, card.ID)
+	if err != nil {
+		s.logger.Error("Failed to load card controls after capture",
+			zap.String("card_id", card.ID.String()),
+			zap.Error(err))
+		return
+	}
+	if controls == nil || !controls.SingleUse || card.Status == entities.CardStatusCancelled {
+		return
+	}
+	s.cancelCard(ctx, card, singleUseCancelled)
+	s.logger.Info("Single-use card consumed", zap.String("card_id", card.ID.String()))
+}
+
+// cancelCard cancels a card on Bridge and locally; Bridge failures are logged so the card is
+// still blocked by the local status check
+func (s *Service) cancelCard(ctx context.Context, card *entities.BridgeCard, reason string) {
+	if s.bridgeAdapter != nil {
+		if _, err := s.bridgeAdapter.Client().CancelCardAccount(ctx, card.BridgeCustomerID, card.BridgeCardID); err != nil {
+			s.logger.Error("Failed to cancel card on Bridge",
+				zap.String("card_id", card.ID.String()),
+				zap.Error(err))
+		}
+	}
+	if err := s.repo.Cancel(ctx, card.ID, reason); err != nil {
+		s.logger.Error("Failed to cancel card",
+			zap.String("card_id", card.ID.String()),
+			zap.Error(err))
+		return
+	}
+	card.Status = entities.CardStatusCancelled
+}
+
+// validateCardControls checks that limits are consistent with each other
+func validateCardControls(c *entities.CardControls) error {
+	if c.PerTransactionLimit != nil && c.DailyLimit != nil && c.PerTransactionLimit.GreaterThan(*c.DailyLimit) {
+		return fmt.Errorf("%w: per-transaction limit exceeds daily limit", ErrInvalidCardControls)
+	}
+	if c.DailyLimit != nil && c.MonthlyLimit != nil && c.DailyLimit.GreaterThan(*c.MonthlyLimit) {
+		return fmt.Errorf("%w: daily limit exceeds monthly limit", ErrInvalidCardControls)
+	}
+	for _, mcc := range c.AllowedMCCs {
+		if containsMCC(c.BlockedMCCs, mcc) {
+			return fmt.Errorf("%w: merchant category %s is both allowed and blocked", ErrInvalidCardControls, mcc)
+		}
+	}
+	return nil
+}
+
+// normalizeMCCs validates and de-duplicates a list of merchant category codes
+func normalizeMCCs(mccs []string) ([]string, error) {
+	result := make([]string, 0, len(mccs))
+	seen := make(map[string]bool, len(mccs))
+	for _, mcc := range mccs {
+		mcc = strings.TrimSpace(mcc)
+		if !mccPattern.MatchString(mcc) {
+			return nil, fmt.Errorf("%w: invalid merchant category code %q", ErrInvalidCardControls, mcc)
+		}
+		if !seen[mcc] {
+			seen[mcc] = true
+			result = append(result, mcc)
+		}
+	}
+	return result, nil
+}
+
+// limitOrNil treats a zero limit as "no limit"
+func limitOrNil(limit decimal.Decimal) *decimal.Decimal {
+	if !limit.IsPositive() {
+		return nil
+	}
+	return &limit
+}
+
+func containsMCC(mccs []string, mcc string) bool {
+	for _, m := range mccs {
+		if m == mcc {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMerchant produces a stable key for merchant-lock comparisons
+func normalizeMerchant(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), " "))
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,369 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// disputeWindow is how long after a transaction a user may open a dispute
+const disputeWindow = 120 * 24 * time.Hour
+
+var (
+	ErrTransactionNotFound      = errors.New("card transaction not found")
+	ErrTransactionNotDisputable = errors.New("card transaction cannot be disputed")
+	ErrDisputeWindowExpired     = errors.New("dispute window has expired")
+	ErrDisputeExists            = errors.New("transaction has already been disputed")
+	ErrDisputeNotFound          = errors.New("card dispute not found")
+	ErrInvalidDisputeTransition = errors.New("invalid dispute status transition")
+	ErrDisputeResolved          = errors.New("card dispute is already resolved")
+)
+
+// AuditService records compliance audit events for card disputes
+type AuditService interface {
+	LogStatusTransition(ctx context.Context, userID uuid.UUID, entityID uuid.UUID, entityType, fromStatus, toStatus, triggeredBy string) error
+}
+
+// SetAuditService sets the audit service for dispute state changes (optional)
+func (s *Service) SetAuditService(auditService AuditService) {
+	s.auditService = auditService
+}
+
+// OpenDispute opens a dispute on a settled card transaction and posts provisional credit
+func (s *Service) OpenDispute(ctx context.Context, userID uuid.UUID, req *entities.OpenCardDisputeRequest) (*entities.CardDisputeResponse, error) {
+	tx, err := s.repo.GetTransactionByID(ctx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil || tx.UserID != userID {
+		return nil, ErrTransactionNotFound
+	}
+	if tx.Status != string(entities.CardTxStatusCompleted) || !tx.Amount.IsPositive() {
+		return nil, ErrTransactionNotDisputable
+	}
+	if time.Since(tx.CreatedAt) > disputeWindow {
+		return nil, ErrDisputeWindowExpired
+	}
+
+	// A transaction is disputed once; a lost dispute cannot be reopened for another provisional credit
+	existing, err := s.repo.GetDisputeByTransactionID(ctx, tx.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrDisputeExists
+	}
+
+	dispute := &entities.CardDispute{
+		ID:                      uuid.New(),
+		UserID:                  userID,
+		CardID:                  tx.CardID,
+		TransactionID:           tx.ID,
+		Reason:                  req.Reason,
+		Description:             strings.TrimSpace(req.Description),
+		Amount:                  tx.Amount,
+		Currency:                tx.Currency,
+		Status:                  entities.CardDisputeSubmitted,
+		ProvisionalCreditStatus: entities.ProvisionalCreditNone,
+	}
+	evidence := buildDisputeEvidence(dispute.ID, req.Evidence)
+
+	if err := s.repo.CreateDispute(ctx, dispute, evidence); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Card dispute opened",
+		zap.String("dispute_id", dispute.ID.String()),
+		zap.String("transaction_id", tx.ID.String()),
+		zap.String("reason", string(dispute.Reason)),
+		zap.String("amount", dispute.Amount.String()))
+	s.auditDispute(ctx, dispute, "", entities.CardDisputeSubmitted, "user")
+
+	// Provisional credit is posted up front so the user is not out of pocket while the case is open
+	ledgerTxID, err := s.postDisputeLedgerEntry(ctx, dispute, true)
+	if err != nil {
+		s.logger.Error("Failed to post provisional dispute credit",
+			zap.String("dispute_id", dispute.ID.String()),
+			zap.Error(err))
+	} else if ledgerTxID != nil {
+		dispute.ProvisionalCreditStatus = entities.ProvisionalCreditPosted
+		dispute.ProvisionalCreditTxID = ledgerTxID
+		if err := s.repo.UpdateDispute(ctx, dispute); err != nil {
+			s.logger.Error("Failed to record provisional dispute credit",
+				zap.String("dispute_id", dispute.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	message := fmt.Sprintf("We received your dispute for $%s.", dispute.Amount.StringFixed(2))
+	if dispute.ProvisionalCreditStatus == entities.ProvisionalCreditPosted {
+		message += " A provisional credit has been added to your spending balance while we investigate."
+	}
+	s.notify(ctx, userID, "Dispute submitted", message)
+
+	return &entities.CardDisputeResponse{Dispute: dispute, Evidence: evidence}, nil
+}
+
+// GetDispute retrieves a user's dispute with its evidence
+func (s *Service) GetDispute(ctx context.Context, userID, disputeID uuid.UUID) (*entities.CardDisputeResponse, error) {
+	dispute, err := s.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil || dispute.UserID != userID {
+		return nil, ErrDisputeNotFound
+	}
+
+	evidence, err := s.repo.GetDisputeEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.CardDisputeResponse{Dispute: dispute, Evidence: evidence}, nil
+}
+
+// ListDisputes retrieves a user's disputes
+func (s *Service) ListDisputes(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CardDispute, error) {
+	return s.repo.GetDisputesByUserID(ctx, userID, limit, offset)
+}
+
+// ListDisputesByStatus retrieves disputes awaiting operator action
+func (s *Service) ListDisputesByStatus(ctx context.Context, status entities.CardDisputeStatus, limit, offset int) ([]*entities.CardDispute, error) {
+	return s.repo.GetDisputesByStatus(ctx, status, limit, offset)
+}
+
+// AddDisputeEvidence attaches additional evidence to an unresolved dispute
+func (s *Service) AddDisputeEvidence(ctx context.Context, userID, disputeID uuid.UUID, req *entities.AddDisputeEvidenceRequest) (*entities.CardDisputeResponse, error) {
+	dispute, err := s.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil || dispute.UserID != userID {
+		return nil, ErrDisputeNotFound
+	}
+	if dispute.Status.IsTerminal() {
+		return nil, ErrDisputeResolved
+	}
+
+	if err := s.repo.AddDisputeEvidence(ctx, buildDisputeEvidence(dispute.ID, req.Evidence)); err != nil {
+		return nil, err
+	}
+
+	evidence, err := s.repo.GetDisputeEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.CardDisputeResponse{Dispute: dispute, Evidence: evidence}, nil
+}
+
+// MarkDisputeUnderReview moves a submitted dispute into review with the card network
+func (s *Service) MarkDisputeUnderReview(ctx context.Context, disputeID, reviewerID uuid.UUID) (*entities.CardDispute, error) {
+	dispute, err := s.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	if !dispute.Status.CanTransitionTo(entities.CardDisputeUnderReview) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidDisputeTransition, dispute.Status, entities.CardDisputeUnderReview)
+	}
+
+	from := dispute.Status
+	dispute.Status = entities.CardDisputeUnderReview
+	if err := s.repo.UpdateDispute(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	s.auditDispute(ctx, dispute, from, dispute.Status, reviewerID.String())
+	s.notify(ctx, dispute.UserID, "Dispute under review",
+		fmt.Sprintf("Your dispute for $%s is now being reviewed with the merchant.", dispute.Amount.StringFixed(2)))
+	return dispute, nil
+}
+
+// ResolveDispute records the chargeback outcome, finalizing or clawing back provisional credit
+func (s *Service) ResolveDispute(ctx context.Context, disputeID, resolverID uuid.UUID, req *entities.ResolveCardDisputeRequest) (*entities.CardDispute, error) {
+	dispute, err := s.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	if dispute.Status.IsTerminal() {
+		return nil, ErrDisputeResolved
+	}
+	if !dispute.Status.CanTransitionTo(req.Outcome) || !req.Outcome.IsTerminal() {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidDisputeTransition, dispute.Status, req.Outcome)
+	}
+
+	from := dispute.Status
+	now := time.Now().UTC()
+	dispute.Status = req.Outcome
+	dispute.ResolvedBy = &resolverID
+	dispute.ResolvedAt = &now
+	if note := strings.TrimSpace(req.Note); note != "" {
+		dispute.ResolutionNote = &note
+	}
+
+	var title, message string
+	switch req.Outcome {
+	case entities.CardDisputeWon:
+		if dispute.ProvisionalCreditStatus == entities.ProvisionalCreditPosted {
+			dispute.ProvisionalCreditStatus = entities.ProvisionalCreditFinalized
+		} else {
+			// Credit was never posted; pay the refund out now
+			ledgerTxID, err := s.postDisputeLedgerEntry(ctx, dispute, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to credit won dispute: %w", err)
+			}
+			if ledgerTxID != nil {
+				dispute.ProvisionalCreditTxID = ledgerTxID
+				dispute.ProvisionalCreditStatus = entities.ProvisionalCreditFinalized
+			}
+		}
+		if err := s.repo.UpdateTransactionStatus(ctx, dispute.TransactionID, string(entities.CardTxStatusReversed), nil); err != nil {
+			s.logger.Error("Failed to mark disputed transaction reversed",
+				zap.String("transaction_id", dispute.TransactionID.String()),
+				zap.Error(err))
+		}
+		// The purchase is refunded, so its round-up and cashback are reversed as for any reversal
+		if tx, err := s.repo.GetTransactionByID(ctx, dispute.TransactionID); err != nil || tx == nil {
+			s.logger.Error("Failed to load disputed transaction for reversal",
+				zap.String("transaction_id", dispute.TransactionID.String()),
+				zap.Error(err))
+		} else {
+			s.reverseRoundUp(ctx, tx)
+			s.reverseCashback(ctx, tx)
+		}
+		title = "Dispute resolved in your favor"
+		message = fmt.Sprintf("Your dispute for $%s was successful. The credit to your spending balance is now final.", dispute.Amount.StringFixed(2))
+
+	case entities.CardDisputeLost:
+		if dispute.ProvisionalCreditStatus == entities.ProvisionalCreditPosted {
+			ledgerTxID, err := s.postDisputeLedgerEntry(ctx, dispute, false)
+			if err != nil {
+				// Resolution stands; the clawback is flagged for manual recovery
+				s.logger.Error("Failed to claw back provisional dispute credit",
+					zap.String("dispute_id", dispute.ID.String()),
+					zap.Error(err))
+				dispute.ProvisionalCreditStatus = entities.ProvisionalCreditReversalFailed
+			} else {
+				dispute.ClawbackTxID = ledgerTxID
+				dispute.ProvisionalCreditStatus = entities.ProvisionalCreditReversed
+			}
+		}
+		title = "Dispute closed"
+		message = fmt.Sprintf("Your dispute for $%s was not successful.", dispute.Amount.StringFixed(2))
+		switch dispute.ProvisionalCreditStatus {
+		case entities.ProvisionalCreditReversed:
+			message += " The provisional credit has been removed from your spending balance."
+		case entities.ProvisionalCreditReversalFailed:
+			message += " The provisional credit will be removed from your spending balance."
+		}
+	}
+
+	if err := s.repo.UpdateDispute(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Card dispute resolved",
+		zap.String("dispute_id", dispute.ID.String()),
+		zap.String("outcome", string(dispute.Status)),
+		zap.String("provisional_credit_status", string(dispute.ProvisionalCreditStatus)))
+	s.auditDispute(ctx, dispute, from, dispute.Status, resolverID.String())
+	s.notify(ctx, dispute.UserID, title, message)
+	return dispute, nil
+}
+
+// postDisputeLedgerEntry moves the disputed amount between the user's spending balance and the
+// system fiat buffer. credit=true posts the provisional credit; credit=false claws it back.
+func (s *Service) postDisputeLedgerEntry(ctx context.Context, dispute *entities.CardDispute, credit bool) (*uuid.UUID, error) {
+	if s.ledgerService == nil {
+		return nil, nil
+	}
+
+	spendAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, dispute.UserID, entities.AccountTypeSpendingBalance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spend account: %w", err)
+	}
+	bufferAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemBufferFiat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system buffer account: %w", err)
+	}
+
+	userEntry, bufferEntry := entities.EntryTypeDebit, entities.EntryTypeCredit // Increase spend balance
+	desc := fmt.Sprintf("Provisional credit for card dispute %s", dispute.ID)
+	idempotencyKey := fmt.Sprintf("card-dispute-credit:%s", dispute.ID)
+	if !credit {
+		userEntry, bufferEntry = entities.EntryTypeCredit, entities.EntryTypeDebit // Decrease spend balance
+		desc = fmt.Sprintf("Provisional credit reversal for card dispute %s", dispute.ID)
+		idempotencyKey = fmt.Sprintf("card-dispute-clawback:%s", dispute.ID)
+	}
+	referenceType := "card_dispute"
+
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &dispute.UserID,
+		TransactionType: entities.TransactionTypeCardDispute,
+		ReferenceID:     &dispute.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  idempotencyKey,
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   spendAccount.ID,
+				EntryType:   userEntry,
+				Amount:      dispute.Amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   bufferAccount.ID,
+				EntryType:   bufferEntry,
+				Amount:      dispute.Amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ledgerTx.ID, nil
+}
+
+// auditDispute records a dispute status transition in the compliance audit log
+func (s *Service) auditDispute(ctx context.Context, dispute *entities.CardDispute, from, to entities.CardDisputeStatus, triggeredBy string) {
+	if s.auditService == nil {
+		return
+	}
+	if err := s.auditService.LogStatusTransition(ctx, dispute.UserID, dispute.ID, "card_dispute", string(from), string(to), triggeredBy); err != nil {
+		s.logger.Warn("Failed to audit card dispute transition",
+			zap.String("dispute_id", dispute.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// buildDisputeEvidence converts submitted evidence into records for a dispute
+func buildDisputeEvidence(disputeID uuid.UUID, inputs []entities.DisputeEvidenceInput) []*entities.CardDisputeEvidence {
+	evidence := make([]*entities.CardDisputeEvidence, 0, len(inputs))
+	for _, in := range inputs {
+		e := &entities.CardDisputeEvidence{
+			ID:          uuid.New(),
+			DisputeID:   disputeID,
+			Kind:        in.Kind,
+			Description: strings.TrimSpace(in.Description),
+		}
+		if url := strings.TrimSpace(in.URL); url != "" {
+			e.URL = &url
+		}
+		evidence = append(evidence, e)
+	}
+	return evidence
+}
@@ -0,0 +1,403 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+const (
+	recurringLookback        = 100 * 24 * time.Hour
+	recurringMinCharges      = 3 // Including the charge being enriched
+	recurringAmountTolerance = 0.2
+)
+
+var ErrInvalidCategory = errors.New("invalid spending category")
+
+var (
+	// processorPrefix matches payment facilitator prefixes such as "SQ *" or "TST* "
+	processorPrefix = regexp.MustCompile(`^(SQ|TST|SP|PP|PAYPAL|PY|DD|IN|POS|CKO|SUMUP|IZ|ZETTLE)\s?\*\s*`)
+	nonWordChars    = regexp.MustCompile(`[^A-Z0-9&]+`)
+	usStateCodes    = map[string]bool{
+		"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true, "DE": true,
+		"FL": true, "GA": true, "HI": true, "ID": true, "IL": true, "IN": true, "IA": true, "KS": true,
+		"KY": true, "LA": true, "ME": true, "MD": true, "MA": true, "MI": true, "MN": true, "MS": true,
+		"MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true, "NM": true, "NY": true,
+		"NC": true, "ND": true, "OH": true, "OK": true, "OR": true, "PA": true, "RI": true, "SC": true,
+		"SD": true, "TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true, "WV": true,
+		"WI": true, "WY": true, "DC": true,
+	}
+)
+
+// knownMerchant is a merchant directory entry matched by merchant key prefix
+type knownMerchant struct {
+	prefix       string
+	name         string
+	category     entities.SpendingCategory
+	subscription bool
+}
+
+// knownMerchants lists more specific prefixes before the broader ones they share a prefix with
+var knownMerchants = []knownMerchant{
+	{"UBER EATS", "Uber Eats", entities.SpendingCategoryFoodAndDrink, false},
+	{"UBER", "Uber", entities.SpendingCategoryTransportation, false},
+	{"LYFT", "Lyft", entities.SpendingCategoryTransportation, false},
+	{"DOORDASH", "DoorDash", entities.SpendingCategoryFoodAndDrink, false},
+	{"GRUBHUB", "Grubhub", entities.SpendingCategoryFoodAndDrink, false},
+	{"STARBUCKS", "Starbucks", entities.SpendingCategoryFoodAndDrink, false},
+	{"MCDONALD", "McDonald's", entities.SpendingCategoryFoodAndDrink, false},
+	{"CHIPOTLE", "Chipotle", entities.SpendingCategoryFoodAndDrink, false},
+	{"WHOLEFDS", "Whole Foods", entities.SpendingCategoryGroceries, false},
+	{"WHOLE FOODS", "Whole Foods", entities.SpendingCategoryGroceries, false},
+	{"TRADER JOE", "Trader Joe's", entities.SpendingCategoryGroceries, false},
+	{"KROGER", "Kroger", entities.SpendingCategoryGroceries, false},
+	{"SAFEWAY", "Safeway", entities.SpendingCategoryGroceries, false},
+	{"COSTCO", "Costco", entities.SpendingCategoryGroceries, false},
+	{"WALMART", "Walmart", entities.SpendingCategoryShopping, false},
+	{"WAL MART", "Walmart", entities.SpendingCategoryShopping, false},
+	{"TARGET", "Target", entities.SpendingCategoryShopping, false},
+	{"AMZN PRIME", "Amazon Prime", entities.SpendingCategorySubscriptions, true},
+	{"AMAZON PRIME", "Amazon Prime", entities.SpendingCategorySubscriptions, true},
+	{"AMZN", "Amazon", entities.SpendingCategoryShopping, false},
+	{"AMAZON", "Amazon", entities.SpendingCategoryShopping, false},
+	{"NETFLIX", "Netflix", entities.SpendingCategorySubscriptions, true},
+	{"SPOTIFY", "Spotify", entities.SpendingCategorySubscriptions, true},
+	{"HULU", "Hulu", entities.SpendingCategorySubscriptions, true},
+	{"DISNEY PLUS", "Disney+", entities.SpendingCategorySubscriptions, true},
+	{"DISNEYPLUS", "Disney+", entities.SpendingCategorySubscriptions, true},
+	{"GOOGLE YOUTUBE", "YouTube", entities.SpendingCategorySubscriptions, true},
+	{"APPLE COM BILL", "Apple", entities.SpendingCategorySubscriptions, true},
+	{"AIRBNB", "Airbnb", entities.SpendingCategoryTravel, false},
+	{"SHELL", "Shell", entities.SpendingCategoryTransportation, false},
+	{"CHEVRON", "Chevron", entities.SpendingCategoryTransportation, false},
+	{"EXXON", "Exxon", entities.SpendingCategoryTransportation, false},
+	{"CVS", "CVS", entities.SpendingCategoryHealth, false},
+	{"WALGREENS", "Walgreens", entities.SpendingCategoryHealth, false},
+	{"VERIZON", "Verizon", entities.SpendingCategoryUtilities, true},
+	{"AT&T", "AT&T", entities.SpendingCategoryUtilities, true},
+	{"COMCAST", "Xfinity", entities.SpendingCategoryUtilities, true},
+	{"XFINITY", "Xfinity", entities.SpendingCategoryUtilities, true},
+}
+
+// categoryKeywords maps free-text merchant category labels to the taxonomy
+var categoryKeywords = []struct {
+	keyword  string
+	category entities.SpendingCategory
+}{
+	{"GROCER", entities.SpendingCategoryGroceries},
+	{"SUPERMARKET", entities.SpendingCategoryGroceries},
+	{"RESTAURANT", entities.SpendingCategoryFoodAndDrink},
+	{"DINING", entities.SpendingCategoryFoodAndDrink},
+	{"FOOD", entities.SpendingCategoryFoodAndDrink},
+	{"COFFEE", entities.SpendingCategoryFoodAndDrink},
+	{"AIRLINE", entities.SpendingCategoryTravel},
+	{"HOTEL", entities.SpendingCategoryTravel},
+	{"LODGING", entities.SpendingCategoryTravel},
+	{"TRAVEL", entities.SpendingCategoryTravel},
+	{"FUEL", entities.SpendingCategoryTransportation},
+	{"GAS", entities.SpendingCategoryTransportation},
+	{"TRANSIT", entities.SpendingCategoryTransportation},
+	{"TAXI", entities.SpendingCategoryTransportation},
+	{"PARKING", entities.SpendingCategoryTransportation},
+	{"SUBSCRIPTION", entities.SpendingCategorySubscriptions},
+	{"STREAMING", entities.SpendingCategorySubscriptions},
+	{"ENTERTAINMENT", entities.SpendingCategoryEntertainment},
+	{"PHARMACY", entities.SpendingCategoryHealth},
+	{"MEDICAL", entities.SpendingCategoryHealth},
+	{"HEALTH", entities.SpendingCategoryHealth},
+	{"UTILIT", entities.SpendingCategoryUtilities},
+	{"TELECOM", entities.SpendingCategoryUtilities},
+	{"ATM", entities.SpendingCategoryCash},
+	{"CASH", entities.SpendingCategoryCash},
+	{"FEE", entities.SpendingCategoryFees},
+	{"RETAIL", entities.SpendingCategoryShopping},
+	{"SHOP", entities.SpendingCategoryShopping},
+}
+
+// merchantProfile is the rule-independent enrichment of a raw merchant descriptor
+type merchantProfile struct {
+	key          string
+	displayName  string
+	category     entities.SpendingCategory
+	source       entities.CategorySource
+	subscription bool
+}
+
+// profileMerchant normalizes a raw merchant descriptor and assigns a category from the
+// merchant directory, the MCC, or a free-text category label, in that order
+func profileMerchant(merchantName, merchantCategory string) *merchantProfile {
+	profile := &merchantProfile{
+		category: entities.SpendingCategoryOther,
+		source:   entities.CategorySourceMCC,
+	}
+
+	name := strings.ToUpper(strings.TrimSpace(merchantName))
+	for processorPrefix.MatchString(name) {
+		name = processorPrefix.ReplaceAllString(name, "")
+	}
+
+	cleaned := strings.Join(strings.Fields(nonWordChars.ReplaceAllString(name, " ")), " ")
+	for _, m := range knownMerchants {
+		if cleaned == m.prefix || strings.HasPrefix(cleaned, m.prefix+" ") {
+			profile.key = m.prefix
+			profile.displayName = m.name
+			profile.category = m.category
+			profile.source = entities.CategorySourceMerchant
+			profile.subscription = m.subscription
+			return profile
+		}
+	}
+
+	profile.key = merchantKey(name)
+	profile.displayName = titleCase(profile.key)
+	if category, ok := categoryFromMerchantCategory(merchantCategory); ok {
+		profile.category = category
+	}
+	return profile
+}
+
+// merchantKey strips reference suffixes, store numbers and trailing state codes from a descriptor
+func merchantKey(name string) string {
+	if i := strings.Index(name, "*"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.ReplaceAll(name, ".COM", "")
+
+	tokens := make([]string, 0, 4)
+	for _, token := range strings.Fields(nonWordChars.ReplaceAllString(name, " ")) {
+		if strings.ContainsAny(token, "0123456789") {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	if len(tokens) > 2 && usStateCodes[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return strings.Join(tokens, " ")
+}
+
+// categoryFromMerchantCategory maps an MCC or a free-text category label to the taxonomy
+func categoryFromMerchantCategory(merchantCategory string) (entities.SpendingCategory, bool) {
+	value := strings.TrimSpace(merchantCategory)
+	if value == "" {
+		return "", false
+	}
+	if mccPattern.MatchString(value) {
+		return categoryForMCC(value), true
+	}
+	if category := entities.SpendingCategory(strings.ToLower(value)); category.IsValid() {
+		return category, true
+	}
+	upper := strings.ToUpper(value)
+	for _, k := range categoryKeywords {
+		if strings.Contains(upper, k.keyword) {
+			return k.category, true
+		}
+	}
+	return "", false
+}
+
+// categoryForMCC maps an ISO 18245 merchant category code to the taxonomy
+func categoryForMCC(mcc string) entities.SpendingCategory {
+	code, err := strconv.Atoi(mcc)
+	if err != nil {
+		return entities.SpendingCategoryOther
+	}
+
+	switch {
+	case code >= 3000 && code <= 3999, code == 4511, code == 4722, code == 7011, code == 7512:
+		return entities.SpendingCategoryTravel
+	case code == 4111, code == 4121, code == 4131, code == 4784, code == 4789,
+		code == 5541, code == 5542, code == 7523:
+		return entities.SpendingCategoryTransportation
+	case code == 4814, code == 4816, code == 4899, code == 4900:
+		return entities.SpendingCategoryUtilities
+	case code == 4829, code == 6012, code == 6051, code == 6540:
+		return entities.SpendingCategoryTransfers
+	case code == 5411, code == 5422, code == 5441, code == 5451, code == 5462, code == 5499:
+		return entities.SpendingCategoryGroceries
+	case code >= 5811 && code <= 5814:
+		return entities.SpendingCategoryFoodAndDrink
+	case code >= 5815 && code <= 5818, code == 5968:
+		return entities.SpendingCategorySubscriptions
+	case code == 5122, code == 5912, code >= 8011 && code <= 8099:
+		return entities.SpendingCategoryHealth
+	case code == 6010, code == 6011:
+		return entities.SpendingCategoryCash
+	case code == 7832, code == 7841, code >= 7911 && code <= 7999:
+		return entities.SpendingCategoryEntertainment
+	case code == 6300, code == 6381, code >= 8211 && code <= 8299, code >= 9211 && code <= 9399:
+		return entities.SpendingCategoryBills
+	case code >= 5200 && code <= 5999:
+		return entities.SpendingCategoryShopping
+	default:
+		return entities.SpendingCategoryOther
+	}
+}
+
+// enrichTransaction fills in the normalized merchant, category and recurring flag before a
+// transaction is stored. Lookup failures are logged so enrichment never blocks recording.
+func (s *Service) enrichTransaction(ctx context.Context, tx *entities.BridgeCardTransaction) {
+	if tx.MerchantName == nil {
+		return
+	}
+	profile := profileMerchant(*tx.MerchantName, stringValue(tx.MerchantCategory))
+	applyProfile(tx, profile)
+	if profile.key == "" {
+		return
+	}
+
+	rule, err := s.repo.GetCategoryRule(ctx, tx.UserID, profile.key)
+	if err != nil {
+		s.logger.Warn("Failed to load category rule",
+			zap.String("user_id", tx.UserID.String()),
+			zap.Error(err))
+	} else if rule != nil {
+		category, source := rule.Category, entities.CategorySourceRule
+		tx.Category, tx.CategorySource = &category, &source
+	}
+
+	if tx.Status == string(entities.CardTxStatusDeclined) {
+		return
+	}
+	tx.IsRecurring = profile.subscription || s.detectRecurring(ctx, tx, profile.key)
+}
+
+// detectRecurring reports whether the charge continues a weekly or monthly series of similar
+// amounts at the same merchant, and flags the earlier charges of the series
+func (s *Service) detectRecurring(ctx context.Context, tx *entities.BridgeCardTransaction, key string) bool {
+	now := time.Now().UTC()
+	history, err := s.repo.GetMerchantTransactionsSince(ctx, tx.UserID, key, now.Add(-recurringLookback))
+	if err != nil {
+		s.logger.Warn("Failed to load merchant history",
+			zap.String("user_id", tx.UserID.String()),
+			zap.Error(err))
+		return false
+	}
+	if len(history) < recurringMinCharges-1 {
+		return false
+	}
+
+	series := history[len(history)-(recurringMinCharges-1):]
+	if !isRecurringSeries(series, tx.Amount, now) {
+		return false
+	}
+
+	if err := s.repo.MarkMerchantRecurring(ctx, tx.UserID, key, series[0].CreatedAt); err != nil {
+		s.logger.Warn("Failed to flag recurring charges",
+			zap.String("user_id", tx.UserID.String()),
+			zap.Error(err))
+	}
+	return true
+}
+
+// isRecurringSeries checks that prior charges (oldest first) followed by a charge of amount at
+// now share a weekly or monthly cadence and similar amounts
+func isRecurringSeries(prior []*entities.BridgeCardTransaction, amount decimal.Decimal, now time.Time) bool {
+	tolerance := amount.Abs().Mul(decimal.NewFromFloat(recurringAmountTolerance))
+	dates := make([]time.Time, 0, len(prior)+1)
+	for _, p := range prior {
+		if p.Amount.Sub(amount).Abs().GreaterThan(tolerance) {
+			return false
+		}
+		dates = append(dates, p.CreatedAt)
+	}
+	dates = append(dates, now)
+
+	weekly, monthly := true, true
+	for i := 1; i < len(dates); i++ {
+		days := dates[i].Sub(dates[i-1]).Hours() / 24
+		weekly = weekly && days >= 5 && days <= 9
+		monthly = monthly && days >= 26 && days <= 35
+	}
+	return weekly || monthly
+}
+
+// applyProfile copies a merchant profile onto a transaction
+func applyProfile(tx *entities.BridgeCardTransaction, profile *merchantProfile) {
+	category, source := profile.category, profile.source
+	tx.Category, tx.CategorySource = &category, &source
+	if profile.key != "" {
+		key, displayName := profile.key, profile.displayName
+		tx.MerchantKey, tx.MerchantDisplayName = &key, &displayName
+	}
+}
+
+// enrichForDisplay fills enrichment for transactions recorded before enrichment existed
+func enrichForDisplay(txs []*entities.BridgeCardTransaction) []*entities.BridgeCardTransaction {
+	for _, tx := range txs {
+		if tx.Category == nil && tx.MerchantName != nil {
+			applyProfile(tx, profileMerchant(*tx.MerchantName, stringValue(tx.MerchantCategory)))
+		}
+	}
+	return txs
+}
+
+// RecategorizeTransaction changes a transaction's category. When applyToMerchant is set the
+// choice is remembered as a rule and applied to the user's other charges at the merchant.
+func (s *Service) RecategorizeTransaction(ctx context.Context, userID, transactionID uuid.UUID, req *entities.RecategorizeTransactionRequest) (*entities.BridgeCardTransaction, error) {
+	if !req.Category.IsValid() {
+		return nil, ErrInvalidCategory
+	}
+
+	tx, err := s.repo.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil || tx.UserID != userID {
+		return nil, ErrTransactionNotFound
+	}
+	enrichForDisplay([]*entities.BridgeCardTransaction{tx})
+
+	applyToMerchant := req.ApplyToMerchant == nil || *req.ApplyToMerchant
+	if applyToMerchant && tx.MerchantKey != nil {
+		rule := &entities.CardCategoryRule{
+			UserID:      userID,
+			MerchantKey: *tx.MerchantKey,
+			Category:    req.Category,
+		}
+		if err := s.repo.UpsertCategoryRule(ctx, rule); err != nil {
+			return nil, err
+		}
+		if err := s.repo.UpdateMerchantCategory(ctx, userID, *tx.MerchantKey, req.Category, entities.CategorySourceRule); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateTransactionCategory(ctx, tx.ID, req.Category, entities.CategorySourceUser); err != nil {
+		return nil, err
+	}
+
+	category, source := req.Category, entities.CategorySourceUser
+	tx.Category, tx.CategorySource = &category, &source
+
+	s.logger.Info("Card transaction recategorized",
+		zap.String("transaction_id", tx.ID.String()),
+		zap.String("category", string(req.Category)),
+		zap.Bool("apply_to_merchant", applyToMerchant))
+	return tx, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// titleCase formats an upper-case merchant key for display
+func titleCase(key string) string {
+	words := strings.Fields(strings.ToLower(key))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
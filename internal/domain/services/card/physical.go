@@ -0,0 +1,517 @@
+package card
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/infrastructure/adapters/bridge"
+)
+
+var (
+	ErrInvalidShippingAddress       = errors.New("invalid shipping address")
+	ErrPhysicalCardExists           = errors.New("user already has a physical card")
+	ErrNotPhysicalCard              = errors.New("card is not a physical card")
+	ErrCardAlreadyActive            = errors.New("card is already active")
+	ErrCardNotShipped               = errors.New("card has not shipped yet")
+	ErrActivationVerificationFailed = errors.New("card activation verification failed")
+	ErrShipmentNotFound             = errors.New("card shipment not found")
+)
+
+// supportedShippingCountries lists countries physical cards can be shipped to
+var supportedShippingCountries = map[string]bool{
+	"US": true,
+}
+
+var (
+	usPostalCodePattern = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+	usStatePattern      = regexp.MustCompile(`^[A-Z]{2}$`)
+	poBoxPattern        = regexp.MustCompile(`(?i)\bp\.?\s*o\.?\s*box\b|\bpost\s+office\s+box\b`)
+)
+
+// Notifier sends user-facing notifications for card lifecycle events
+type Notifier interface {
+	SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error
+}
+
+// PasscodeVerifier verifies a user's transaction passcode
+type PasscodeVerifier interface {
+	VerifyPasscode(ctx context.Context, userID uuid.UUID, passcode string) (string, time.Time, error)
+}
+
+// ShipmentUpdate carries a shipment status change reported by Bridge
+type ShipmentUpdate struct {
+	Status         entities.CardShipmentStatus
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    string
+}
+
+// SetNotifier sets the notifier for card lifecycle events (optional)
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// SetPasscodeVerifier sets the passcode verifier used for card activation
+func (s *Service) SetPasscodeVerifier(verifier PasscodeVerifier) {
+	s.passcodeVerifier = verifier
+}
+
+// OrderPhysicalCard orders a physical card shipped to the given address
+func (s *Service) OrderPhysicalCard(ctx context.Context, userID uuid.UUID, req *entities.OrderPhysicalCardRequest) (*entities.PhysicalCardResponse, error) {
+	s.logger.Info("Ordering physical card", zap.String("user_id", userID.String()))
+
+	address, err := normalizeShippingAddress(req.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing cards: %w", err)
+	}
+	for _, c := range existing {
+		if c.Type == entities.CardTypePhysical && c.Status != entities.CardStatusCancelled {
+			return nil, ErrPhysicalCardExists
+		}
+	}
+
+	return s.issuePhysicalCard(ctx, userID, address, nil)
+}
+
+// GetCardShipment retrieves the shipment for a user's physical card
+func (s *Service) GetCardShipment(ctx context.Context, userID, cardID uuid.UUID) (*entities.CardShipment, error) {
+	card, err := s.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Type != entities.CardTypePhysical {
+		return nil, ErrNotPhysicalCard
+	}
+
+	shipment, err := s.repo.GetShipmentByCardID(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment == nil {
+		return nil, ErrShipmentNotFound
+	}
+	return shipment, nil
+}
+
+// ProcessShipmentUpdate applies a shipment status change reported by Bridge webhooks
+func (s *Service) ProcessShipmentUpdate(ctx context.Context, bridgeCardID string, update *ShipmentUpdate) error {
+	if !update.Status.IsValid() {
+		return fmt.Errorf("invalid shipment status: %s", update.Status)
+	}
+
+	card, err := s.repo.GetByBridgeCardID(ctx, bridgeCardID)
+	if err != nil || card == nil {
+		return ErrCardNotFound
+	}
+
+	shipment, err := s.repo.GetShipmentByCardID(ctx, card.ID)
+	if err != nil {
+		return err
+	}
+	if shipment == nil {
+		return ErrShipmentNotFound
+	}
+	if shipment.Status == update.Status {
+		return nil // Duplicate webhook
+	}
+	if update.Status.Stage() < shipment.Status.Stage() {
+		// Out-of-order webhook: never move a shipment backwards
+		s.logger.Info("Ignoring stale card shipment update",
+			zap.String("card_id", card.ID.String()),
+			zap.String("status", string(shipment.Status)),
+			zap.String("update_status", string(update.Status)))
+		return nil
+	}
+
+	now := time.Now().UTC()
+	shipment.Status = update.Status
+	if update.Carrier != "" {
+		shipment.Carrier = &update.Carrier
+	}
+	if update.TrackingNumber != "" {
+		shipment.TrackingNumber = &update.TrackingNumber
+	}
+	if update.TrackingURL != "" {
+		shipment.TrackingURL = &update.TrackingURL
+	}
+	switch update.Status {
+	case entities.CardShipmentShipped:
+		shipment.ShippedAt = &now
+	case entities.CardShipmentDelivered:
+		shipment.DeliveredAt = &now
+	}
+
+	if err := s.repo.UpdateShipment(ctx, shipment); err != nil {
+		return err
+	}
+
+	s.logger.Info("Card shipment updated",
+		zap.String("card_id", card.ID.String()),
+		zap.String("status", string(update.Status)))
+
+	switch update.Status {
+	case entities.CardShipmentShipped:
+		msg := "Your Rail card is on its way."
+		if shipment.Carrier != nil && shipment.TrackingNumber != nil {
+			msg = fmt.Sprintf("Your Rail card is on its way via %s. Tracking number: %s.", *shipment.Carrier, *shipment.TrackingNumber)
+		}
+		s.notify(ctx, card.UserID, "Card shipped", msg)
+	case entities.CardShipmentDelivered:
+		s.notify(ctx, card.UserID, "Card delivered", "Your Rail card has been delivered. Activate it in the app to start spending.")
+	case entities.CardShipmentReturned, entities.CardShipmentFailed:
+		s.notify(ctx, card.UserID, "Card delivery problem", "We couldn't deliver your Rail card. Please check your shipping address and contact support.")
+	}
+
+	return nil
+}
+
+// UpdateCardShipment implements BridgeCardProcessor for shipment webhooks. Bridge's shipping
+// status is mapped to the card shipment status; unknown statuses are ignored.
+func (s *Service) UpdateCardShipment(ctx context.Context, bridgeCardID, status, carrier, trackingNumber, trackingURL string) error {
+	shipmentStatus, ok := bridgeShipmentStatus(status)
+	if !ok {
+		s.logger.Warn("Ignoring unknown Bridge shipment status",
+			zap.String("bridge_card_id", bridgeCardID),
+			zap.String("status", status))
+		return nil
+	}
+	return s.ProcessShipmentUpdate(ctx, bridgeCardID, &ShipmentUpdate{
+		Status:         shipmentStatus,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		TrackingURL:    trackingURL,
+	})
+}
+
+// bridgeShipmentStatus maps a Bridge shipping status to a card shipment status
+func bridgeShipmentStatus(status string) (entities.CardShipmentStatus, bool) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "pending", "created", "ordered":
+		return entities.CardShipmentOrdered, true
+	case "processing", "printing", "in_production":
+		return entities.CardShipmentProcessing, true
+	case "shipped", "in_transit", "out_for_delivery":
+		return entities.CardShipmentShipped, true
+	case "delivered":
+		return entities.CardShipmentDelivered, true
+	case "returned", "returned_to_sender":
+		return entities.CardShipmentReturned, true
+	case "failed", "lost", "undeliverable", "canceled", "cancelled":
+		return entities.CardShipmentFailed, true
+	default:
+		return "", false
+	}
+}
+
+// ActivateCard activates a delivered physical card after verifying the last 4 digits and passcode
+func (s *Service) ActivateCard(ctx context.Context, userID, cardID uuid.UUID, req *entities.ActivateCardRequest) (*entities.BridgeCard, error) {
+	card, err := s.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Type != entities.CardTypePhysical {
+		return nil, ErrNotPhysicalCard
+	}
+	switch card.Status {
+	case entities.CardStatusCancelled:
+		return nil, ErrCardCancelled
+	case entities.CardStatusActive, entities.CardStatusFrozen:
+		return nil, ErrCardAlreadyActive
+	}
+
+	shipment, err := s.repo.GetShipmentByCardID(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment == nil || (shipment.Status != entities.CardShipmentShipped && shipment.Status != entities.CardShipmentDelivered) {
+		return nil, ErrCardNotShipped
+	}
+
+	if s.passcodeVerifier == nil {
+		return nil, fmt.Errorf("%w: passcode verification unavailable", ErrActivationVerificationFailed)
+	}
+	if _, _, err := s.passcodeVerifier.VerifyPasscode(ctx, userID, req.Passcode); err != nil {
+		s.logger.Warn("Card activation passcode verification failed",
+			zap.String("card_id", card.ID.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", ErrActivationVerificationFailed, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(req.Last4), []byte(card.Last4)) != 1 {
+		s.logger.Warn("Card activation last 4 mismatch", zap.String("card_id", card.ID.String()))
+		return nil, fmt.Errorf("%w: last 4 digits do not match", ErrActivationVerificationFailed)
+	}
+
+	if s.bridgeAdapter == nil {
+		return nil, fmt.Errorf("bridge adapter not configured")
+	}
+	if _, err := s.bridgeAdapter.Client().ActivateCardAccount(ctx, card.BridgeCustomerID, card.BridgeCardID, &bridge.ActivateCardAccountRequest{
+		Last4: req.Last4,
+	}); err != nil {
+		s.logger.Error("Failed to activate card on Bridge", zap.Error(err))
+		return nil, fmt.Errorf("failed to activate card: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.MarkActivated(ctx, card.ID, now); err != nil {
+		return nil, err
+	}
+
+	// Activation confirms receipt even if the carrier never reported delivery
+	if shipment.Status != entities.CardShipmentDelivered {
+		shipment.Status = entities.CardShipmentDelivered
+		shipment.DeliveredAt = &now
+		if err := s.repo.UpdateShipment(ctx, shipment); err != nil {
+			s.logger.Warn("Failed to mark shipment delivered on activation", zap.Error(err))
+		}
+	}
+
+	card.Status = entities.CardStatusActive
+	card.ActivatedAt = &now
+	s.logger.Info("Physical card activated", zap.String("card_id", card.ID.String()))
+	s.notify(ctx, userID, "Card activated", fmt.Sprintf("Your Rail card ending in %s is active and ready to use.", card.Last4))
+	return card, nil
+}
+
+// ReplaceCard cancels a lost, stolen or damaged card and issues a replacement of the same type
+func (s *Service) ReplaceCard(ctx context.Context, userID, cardID uuid.UUID, req *entities.ReplaceCardRequest) (*entities.PhysicalCardResponse, error) {
+	card, err := s.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == entities.CardStatusCancelled {
+		return nil, ErrCardCancelled
+	}
+	if s.bridgeAdapter == nil {
+		return nil, fmt.Errorf("bridge adapter not configured")
+	}
+
+	// Resolve the shipping address before cancelling so a bad address doesn't strand the user
+	var address *entities.ShippingAddress
+	if card.Type == entities.CardTypePhysical {
+		address = req.ShippingAddress
+		if address == nil {
+			previous, err := s.repo.GetShipmentByCardID(ctx, card.ID)
+			if err != nil {
+				return nil, err
+			}
+			if previous == nil {
+				return nil, fmt.Errorf("%w: shipping address is required", ErrInvalidShippingAddress)
+			}
+			address = shipmentAddress(previous)
+		}
+		if address, err = normalizeShippingAddress(address); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.bridgeAdapter.Client().CancelCardAccount(ctx, card.BridgeCustomerID, card.BridgeCardID); err != nil {
+		s.logger.Error("Failed to cancel card on Bridge", zap.Error(err))
+		return nil, fmt.Errorf("failed to cancel card: %w", err)
+	}
+	if err := s.repo.Cancel(ctx, card.ID, string(req.Reason)); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Card cancelled for replacement",
+		zap.String("card_id", card.ID.String()),
+		zap.String("reason", string(req.Reason)))
+
+	var replacement *entities.PhysicalCardResponse
+	if card.Type == entities.CardTypePhysical {
+		replacement, err = s.issuePhysicalCard(ctx, userID, address, &card.ID)
+	} else {
+		var newCard *entities.BridgeCard
+		newCard, err = s.issueCard(ctx, userID, entities.CardTypeVirtual, nil, &card.ID)
+		replacement = &entities.PhysicalCardResponse{Card: newCard}
+	}
+	if err != nil {
+		s.notify(ctx, userID, "Card cancelled",
+			fmt.Sprintf("Your card ending in %s was cancelled. We couldn't issue a replacement automatically; please request a new card.", card.Last4))
+		return nil, fmt.Errorf("failed to issue replacement card: %w", err)
+	}
+
+	s.notify(ctx, userID, "Card replaced",
+		fmt.Sprintf("Your card ending in %s was cancelled and a replacement is on the way.", card.Last4))
+	return replacement, nil
+}
+
+// issuePhysicalCard creates the Bridge card, the local card record and its shipment
+func (s *Service) issuePhysicalCard(ctx context.Context, userID uuid.UUID, address *entities.ShippingAddress, replaces *uuid.UUID) (*entities.PhysicalCardResponse, error) {
+	card, err := s.issueCard(ctx, userID, entities.CardTypePhysical, address, replaces)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &entities.CardShipment{
+		ID:          uuid.New(),
+		CardID:      card.ID,
+		UserID:      userID,
+		StreetLine1: address.StreetLine1,
+		StreetLine2: nilIfEmpty(address.StreetLine2),
+		City:        address.City,
+		State:       nilIfEmpty(address.State),
+		PostalCode:  address.PostalCode,
+		Country:     address.Country,
+		Status:      entities.CardShipmentOrdered,
+	}
+	if err := s.repo.CreateShipment(ctx, shipment); err != nil {
+		return nil, fmt.Errorf("failed to save card shipment: %w", err)
+	}
+
+	s.notify(ctx, userID, "Card ordered", "Your Rail card has been ordered. We'll let you know when it ships.")
+	return &entities.PhysicalCardResponse{Card: card, Shipment: shipment}, nil
+}
+
+// issueCard creates a card account on Bridge and persists the local card record
+func (s *Service) issueCard(ctx context.Context, userID uuid.UUID, cardType entities.CardType, address *entities.ShippingAddress, replaces *uuid.UUID) (*entities.BridgeCard, error) {
+	profile, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+	if profile.BridgeCustomerID == nil || *profile.BridgeCustomerID == "" {
+		return nil, ErrCustomerNotFound
+	}
+
+	wallet, err := s.walletProvider.GetUserWalletByChain(ctx, userID, s.defaultChain)
+	if err != nil || wallet == nil {
+		return nil, ErrWalletNotFound
+	}
+
+	bridgeReq := &bridge.CreateCardAccountRequest{
+		Currency: bridge.CurrencyUSDC,
+		Chain:    bridge.PaymentRailSolana,
+		CryptoAccount: bridge.CryptoAccount{
+			Type:    "wallet",
+			Address: wallet.Address,
+		},
+		CardType: bridge.CardAccountType(cardType),
+	}
+	if address != nil {
+		bridgeReq.ShippingAddress = &bridge.CardShippingAddress{
+			StreetLine1: address.StreetLine1,
+			StreetLine2: address.StreetLine2,
+			City:        address.City,
+			State:       address.State,
+			PostalCode:  address.PostalCode,
+			Country:     address.Country,
+		}
+	}
+
+	bridgeCard, err := s.bridgeAdapter.CreateCardAccountForCustomer(ctx, *profile.BridgeCustomerID, bridgeReq)
+	if err != nil {
+		s.logger.Error("Failed to create Bridge card account", zap.Error(err))
+		return nil, fmt.Errorf("failed to create card on Bridge: %w", err)
+	}
+
+	status := mapBridgeCardStatus(bridgeCard.Status)
+	if cardType == entities.CardTypePhysical {
+		// Physical cards stay pending until the user activates them on receipt
+		status = entities.CardStatusPending
+	}
+
+	card := &entities.BridgeCard{
+		ID:               uuid.New(),
+		UserID:           userID,
+		BridgeCardID:     bridgeCard.ID,
+		BridgeCustomerID: *profile.BridgeCustomerID,
+		Type:             cardType,
+		Status:           status,
+		Last4:            bridgeCard.CardDetails.Last4,
+		Expiry:           bridgeCard.CardDetails.Expiry,
+		CardImageURL:     nilIfEmpty(bridgeCard.CardImageURL),
+		Currency:         string(bridgeCard.Currency),
+		Chain:            string(bridgeCard.Chain),
+		WalletAddress:    wallet.Address,
+		ReplacesCardID:   replaces,
+	}
+
+	if err := s.repo.Create(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to save card: %w", err)
+	}
+
+	s.logger.Info("Card issued",
+		zap.String("card_id", card.ID.String()),
+		zap.String("type", string(cardType)),
+		zap.String("bridge_card_id", card.BridgeCardID))
+
+	return card, nil
+}
+
+// notify sends a best-effort notification; failures are logged and never fail the operation
+func (s *Service) notify(ctx context.Context, userID uuid.UUID, title, message string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendGenericNotification(ctx, userID, title, message); err != nil {
+		s.logger.Warn("Failed to send card notification",
+			zap.String("user_id", userID.String()),
+			zap.String("title", title),
+			zap.Error(err))
+	}
+}
+
+// normalizeShippingAddress trims and validates a shipping address for physical card delivery
+func normalizeShippingAddress(addr *entities.ShippingAddress) (*entities.ShippingAddress, error) {
+	if addr == nil {
+		return nil, fmt.Errorf("%w: shipping address is required", ErrInvalidShippingAddress)
+	}
+
+	normalized := &entities.ShippingAddress{
+		StreetLine1: strings.TrimSpace(addr.StreetLine1),
+		StreetLine2: strings.TrimSpace(addr.StreetLine2),
+		City:        strings.TrimSpace(addr.City),
+		State:       strings.ToUpper(strings.TrimSpace(addr.State)),
+		PostalCode:  strings.TrimSpace(addr.PostalCode),
+		Country:     strings.ToUpper(strings.TrimSpace(addr.Country)),
+	}
+
+	if normalized.StreetLine1 == "" || normalized.City == "" || normalized.PostalCode == "" || normalized.Country == "" {
+		return nil, fmt.Errorf("%w: street, city, postal code and country are required", ErrInvalidShippingAddress)
+	}
+	if !supportedShippingCountries[normalized.Country] {
+		return nil, fmt.Errorf("%w: cards cannot be shipped to %s", ErrInvalidShippingAddress, normalized.Country)
+	}
+	if poBoxPattern.MatchString(normalized.StreetLine1) || poBoxPattern.MatchString(normalized.StreetLine2) {
+		return nil, fmt.Errorf("%w: cards cannot be shipped to a PO box", ErrInvalidShippingAddress)
+	}
+	if normalized.Country == "US" {
+		if !usStatePattern.MatchString(normalized.State) {
+			return nil, fmt.Errorf("%w: a two-letter state code is required", ErrInvalidShippingAddress)
+		}
+		if !usPostalCodePattern.MatchString(normalized.PostalCode) {
+			return nil, fmt.Errorf("%w: invalid ZIP code", ErrInvalidShippingAddress)
+		}
+	}
+
+	return normalized, nil
+}
+
+func shipmentAddress(shipment *entities.CardShipment) *entities.ShippingAddress {
+	addr := &entities.ShippingAddress{
+		StreetLine1: shipment.StreetLine1,
+		City:        shipment.City,
+		PostalCode:  shipment.PostalCode,
+		Country:     shipment.Country,
+	}
+	if shipment.StreetLine2 != nil {
+		addr.StreetLine2 = *shipment.StreetLine2
+	}
+	if shipment.State != nil {
+		addr.State = *shipment.State
+	}
+	return addr
+}
@@ -0,0 +1,49 @@
+package card
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// RewardsProcessor accrues cashback on settled card purchases and reverses it on refund
+type RewardsProcessor interface {
+	AccrueCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) (*entities.CashbackReward, error)
+	ReverseCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) error
+}
+
+// SetRewardsProcessor sets the processor that accrues cashback on settled card purchases
+func (s *Service) SetRewardsProcessor(processor RewardsProcessor) {
+	s.rewardsProcessor = processor
+}
+
+// accrueCashback accrues cashback for a settled purchase, or reverses it when the settlement is a
+// refund. Failures are logged so they never fail the card settlement itself.
+func (s *Service) accrueCashback(ctx context.Context, tx *entities.BridgeCardTransaction) {
+	if s.rewardsProcessor == nil {
+		return
+	}
+	if tx.Type == "refund" {
+		s.reverseCashback(ctx, tx)
+		return
+	}
+	if _, err := s.rewardsProcessor.AccrueCardSettlement(ctx, tx); err != nil {
+		s.logger.Error("Failed to accrue card cashback",
+			zap.String("transaction_id", tx.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// reverseCashback reverses the cashback of a refunded or reversed purchase
+func (s *Service) reverseCashback(ctx context.Context, tx *entities.BridgeCardTransaction) {
+	if s.rewardsProcessor == nil {
+		return
+	}
+	if err := s.rewardsProcessor.ReverseCardSettlement(ctx, tx); err != nil {
+		s.logger.Error("Failed to reverse card cashback",
+			zap.String("transaction_id", tx.ID.String()),
+			zap.Error(err))
+	}
+}
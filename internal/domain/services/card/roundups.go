@@ -0,0 +1,50 @@
+package card
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// RoundupProcessor generates round-ups from settled card purchases and reverses them on refund
+type RoundupProcessor interface {
+	ProcessCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) (*entities.RoundupTransaction, error)
+	ReverseCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) error
+}
+
+// SetRoundupProcessor sets the processor that rounds up settled card purchases
+func (s *Service) SetRoundupProcessor(processor RoundupProcessor) {
+	s.roundupProcessor = processor
+}
+
+// roundUpSettlement generates the round-up for a settled purchase, or reverses the original
+// purchase's round-up when the settlement is a refund. Failures are logged so they never fail
+// the card settlement itself.
+func (s *Service) roundUpSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) {
+	if s.roundupProcessor == nil {
+		return
+	}
+	if tx.Type == "refund" {
+		s.reverseRoundUp(ctx, tx)
+		return
+	}
+	if _, err := s.roundupProcessor.ProcessCardSettlement(ctx, tx); err != nil {
+		s.logger.Error("Failed to process card round-up",
+			zap.String("transaction_id", tx.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// reverseRoundUp reverses the round-up of a refunded purchase
+func (s *Service) reverseRoundUp(ctx context.Context, tx *entities.BridgeCardTransaction) {
+	if s.roundupProcessor == nil {
+		return
+	}
+	if err := s.roundupProcessor.ReverseCardSettlement(ctx, tx); err != nil {
+		s.logger.Error("Failed to reverse card round-up",
+			zap.String("transaction_id", tx.ID.String()),
+			zap.Error(err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -18,6 +19,7 @@ var (
 	ErrCardAlreadyExists = errors.New("user already has an active card of this type")
 	ErrCardFrozen        = errors.New("card is frozen")
 	ErrCardCancelled     = errors.New("card is cancelled")
+	ErrCardNotActivated  = errors.New("card has not been activated")
 	ErrInsufficientFunds = errors.New("insufficient spend balance")
 	ErrCustomerNotFound  = errors.New("bridge customer not found")
 	ErrWalletNotFound    = errors.New("wallet not found for card creation")
@@ -31,12 +33,36 @@ type CardRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.BridgeCard, error)
 	GetActiveVirtualCard(ctx context.Context, userID uuid.UUID) (*entities.BridgeCard, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entities.CardStatus) error
+	MarkActivated(ctx context.Context, id uuid.UUID, activatedAt time.Time) error
+	Cancel(ctx context.Context, id uuid.UUID, reason string) error
+	CreateShipment(ctx context.Context, shipment *entities.CardShipment) error
+	GetShipmentByCardID(ctx context.Context, cardID uuid.UUID) (*entities.CardShipment, error)
+	UpdateShipment(ctx context.Context, shipment *entities.CardShipment) error
 	CreateTransaction(ctx context.Context, tx *entities.BridgeCardTransaction) error
+	GetTransactionByID(ctx context.Context, id uuid.UUID) (*entities.BridgeCardTransaction, error)
 	GetTransactionByBridgeID(ctx context.Context, bridgeTransID string) (*entities.BridgeCardTransaction, error)
 	GetTransactionsByCardID(ctx context.Context, cardID uuid.UUID, limit, offset int) ([]*entities.BridgeCardTransaction, error)
 	GetTransactionsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.BridgeCardTransaction, error)
 	UpdateTransactionStatus(ctx context.Context, id uuid.UUID, status string, declineReason *string) error
+	GetMerchantTransactionsSince(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) ([]*entities.BridgeCardTransaction, error)
+	UpdateTransactionCategory(ctx context.Context, id uuid.UUID, category entities.SpendingCategory, source entities.CategorySource) error
+	UpdateMerchantCategory(ctx context.Context, userID uuid.UUID, merchantKey string, category entities.SpendingCategory, source entities.CategorySource) error
+	MarkMerchantRecurring(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) error
+	GetCategoryRule(ctx context.Context, userID uuid.UUID, merchantKey string) (*entities.CardCategoryRule, error)
+	UpsertCategoryRule(ctx context.Context, rule *entities.CardCategoryRule) error
+	GetCardSpendSince(ctx context.Context, cardID uuid.UUID, since time.Time) (decimal.Decimal, error)
+	GetCardControls(ctx context.Context, cardID uuid.UUID) (*entities.CardControls, error)
+	UpsertCardControls(ctx context.Context, controls *entities.CardControls) error
+	LockCardMerchant(ctx context.Context, cardID uuid.UUID, merchant string) error
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	CreateDispute(ctx context.Context, dispute *entities.CardDispute, evidence []*entities.CardDisputeEvidence) error
+	AddDisputeEvidence(ctx context.Context, evidence []*entities.CardDisputeEvidence) error
+	GetDisputeByID(ctx context.Context, id uuid.UUID) (*entities.CardDispute, error)
+	GetDisputeByTransactionID(ctx context.Context, transactionID uuid.UUID) (*entities.CardDispute, error)
+	GetDisputesByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CardDispute, error)
+	GetDisputesByStatus(ctx context.Context, status entities.CardDisputeStatus, limit, offset int) ([]*entities.CardDispute, error)
+	GetDisputeEvidence(ctx context.Context, disputeID uuid.UUID) ([]*entities.CardDisputeEvidence, error)
+	UpdateDispute(ctx context.Context, dispute *entities.CardDispute) error
 }
 
 // UserProfileProvider provides user profile data
@@ -59,19 +85,29 @@ type BalanceProvider interface {
 type LedgerService interface {
 	GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error)
 	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	GetSystemAccount(ctx context.Context, accountType entities.AccountType) (*entities.LedgerAccount, error)
 	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
 }
 
 // Service handles card business logic
 type Service struct {
-	repo            CardRepository
-	bridgeAdapter   *bridge.Adapter
-	userProvider    UserProfileProvider
-	walletProvider  WalletProvider
-	balanceProvider BalanceProvider
-	ledgerService   LedgerService
-	logger          *zap.Logger
-	defaultChain    string
+	repo             CardRepository
+	bridgeAdapter    *bridge.Adapter
+	userProvider     UserProfileProvider
+	walletProvider   WalletProvider
+	balanceProvider  BalanceProvider
+	ledgerService    LedgerService
+	fraudChecker     FraudChecker
+	notifier         Notifier
+	passcodeVerifier PasscodeVerifier
+	auditService     AuditService
+	roundupProcessor RoundupProcessor
+	rewardsProcessor RewardsProcessor
+	authPolicy       AuthorizationPolicy
+	authRules        []AuthorizationRule
+	extraAuthRules   []AuthorizationRule
+	logger           *zap.Logger
+	defaultChain     string
 }
 
 // NewService creates a new card service
@@ -83,15 +119,18 @@ func NewService(
 	balanceProvider BalanceProvider,
 	logger *zap.Logger,
 ) *Service {
-	return &Service{
+	s := &Service{
 		repo:            repo,
 		bridgeAdapter:   bridgeAdapter,
 		userProvider:    userProvider,
 		walletProvider:  walletProvider,
 		balanceProvider: balanceProvider,
+		authPolicy:      DefaultAuthorizationPolicy(),
 		logger:          logger,
 		defaultChain:    "ethereum", // Default chain for card funding
 	}
+	s.authRules = s.buildAuthorizationRules()
+	return s
 }
 
 // SetLedgerService sets the ledger service after initialization.
@@ -113,55 +152,9 @@ func (s *Service) CreateVirtualCard(ctx context.Context, userID uuid.UUID) (*ent
 		return existing, nil // Return existing card
 	}
 
-	// Get user profile for Bridge customer ID
-	profile, err := s.userProvider.GetByID(ctx, userID)
+	card, err := s.issueCard(ctx, userID, entities.CardTypeVirtual, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user profile: %w", err)
-	}
-	if profile.BridgeCustomerID == nil || *profile.BridgeCustomerID == "" {
-		return nil, ErrCustomerNotFound
-	}
-
-	// Get user's wallet for card funding
-	wallet, err := s.walletProvider.GetUserWalletByChain(ctx, userID, s.defaultChain)
-	if err != nil || wallet == nil {
-		return nil, ErrWalletNotFound
-	}
-
-	// Create card account on Bridge
-	bridgeReq := &bridge.CreateCardAccountRequest{
-		Currency: bridge.CurrencyUSDC,
-		Chain:    bridge.PaymentRailSolana,
-		CryptoAccount: bridge.CryptoAccount{
-			Type:    "wallet",
-			Address: wallet.Address,
-		},
-	}
-
-	bridgeCard, err := s.bridgeAdapter.CreateCardAccountForCustomer(ctx, *profile.BridgeCustomerID, bridgeReq)
-	if err != nil {
-		s.logger.Error("Failed to create Bridge card account", zap.Error(err))
-		return nil, fmt.Errorf("failed to create card on Bridge: %w", err)
-	}
-
-	// Create local card record
-	card := &entities.BridgeCard{
-		ID:               uuid.New(),
-		UserID:           userID,
-		BridgeCardID:     bridgeCard.ID,
-		BridgeCustomerID: *profile.BridgeCustomerID,
-		Type:             entities.CardTypeVirtual,
-		Status:           mapBridgeCardStatus(bridgeCard.Status),
-		Last4:            bridgeCard.CardDetails.Last4,
-		Expiry:           bridgeCard.CardDetails.Expiry,
-		CardImageURL:     nilIfEmpty(bridgeCard.CardImageURL),
-		Currency:         string(bridgeCard.Currency),
-		Chain:            string(bridgeCard.Chain),
-		WalletAddress:    wallet.Address,
-	}
-
-	if err := s.repo.Create(ctx, card); err != nil {
-		return nil, fmt.Errorf("failed to save card: %w", err)
+		return nil, err
 	}
 
 	s.logger.Info("Virtual card created",
@@ -198,6 +191,9 @@ func (s *Service) FreezeCard(ctx context.Context, userID, cardID uuid.UUID) (*en
 	if card.Status == entities.CardStatusCancelled {
 		return nil, ErrCardCancelled
 	}
+	if !isActivated(card) {
+		return nil, ErrCardNotActivated
+	}
 	if card.Status == entities.CardStatusFrozen {
 		return card, nil // Already frozen
 	}
@@ -229,6 +225,10 @@ func (s *Service) UnfreezeCard(ctx context.Context, userID, cardID uuid.UUID) (*
 	if card.Status == entities.CardStatusCancelled {
 		return nil, ErrCardCancelled
 	}
+	// Unfreezing must not become a way to skip physical card activation
+	if !isActivated(card) {
+		return nil, ErrCardNotActivated
+	}
 	if card.Status == entities.CardStatusActive {
 		return card, nil // Already active
 	}
@@ -258,49 +258,49 @@ func (s *Service) GetCardTransactions(ctx context.Context, userID, cardID uuid.U
 		return nil, err
 	}
 
-	return s.repo.GetTransactionsByCardID(ctx, card.ID, limit, offset)
+	txs, err := s.repo.GetTransactionsByCardID(ctx, card.ID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return enrichForDisplay(txs), nil
 }
 
 // GetUserTransactions retrieves all card transactions for a user
 func (s *Service) GetUserTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.BridgeCardTransaction, error) {
-	return s.repo.GetTransactionsByUserID(ctx, userID, limit, offset)
+	txs, err := s.repo.GetTransactionsByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return enrichForDisplay(txs), nil
 }
 
-// ProcessCardAuthorization handles real-time card authorization
+// ProcessCardAuthorization handles real-time card authorization for callers that only know the
+// card, amount and merchant. It runs the authorization pipeline and returns the decline code when declined.
 func (s *Service) ProcessCardAuthorization(ctx context.Context, bridgeCardID string, amount decimal.Decimal, merchantName, merchantCategory string) (bool, string, error) {
-	s.logger.Info("Processing card authorization",
-		zap.String("bridge_card_id", bridgeCardID),
-		zap.String("amount", amount.String()))
-
-	// Get card
-	card, err := s.repo.GetByBridgeCardID(ctx, bridgeCardID)
-	if err != nil || card == nil {
-		return false, "card_not_found", ErrCardNotFound
-	}
-
-	// Check card status
-	if card.Status == entities.CardStatusFrozen {
-		return false, "card_frozen", ErrCardFrozen
-	}
-	if card.Status == entities.CardStatusCancelled {
-		return false, "card_cancelled", ErrCardCancelled
-	}
-
-	// Check spend balance
-	balance, err := s.balanceProvider.GetSpendBalance(ctx, card.UserID)
-	if err != nil {
-		s.logger.Error("Failed to get spend balance", zap.Error(err))
-		return false, "balance_check_failed", err
-	}
+	return s.processAuthorization(ctx, &AuthorizationRequest{
+		BridgeCardID:     bridgeCardID,
+		Amount:           amount,
+		MerchantName:     merchantName,
+		MerchantCategory: merchantCategory,
+	})
+}
 
-	if balance.LessThan(amount) {
-		return false, "insufficient_funds", ErrInsufficientFunds
+// processAuthorization runs the authorization pipeline and returns the decline code when declined
+func (s *Service) processAuthorization(ctx context.Context, req *AuthorizationRequest) (bool, string, error) {
+	s.logger.Info("Processing card authorization",
+		zap.String("bridge_card_id", req.BridgeCardID),
+		zap.String("bridge_trans_id", req.BridgeTransID),
+		zap.String("amount", req.Amount.String()))
+
+	decision, err := s.AuthorizeTransaction(ctx, req)
+	if decision == nil || !decision.Approved {
+		code := ""
+		if decision != nil {
+			code = string(decision.DeclineCode)
+		}
+		return false, code, err
 	}
 
-	s.logger.Info("Card authorization approved",
-		zap.String("card_id", card.ID.String()),
-		zap.String("amount", amount.String()))
-
 	return true, "", nil
 }
 
@@ -310,22 +310,45 @@ func (s *Service) RecordTransaction(ctx context.Context, bridgeCardID, bridgeTra
 	if err != nil || card == nil {
 		return ErrCardNotFound
 	}
+	status = normalizeTransactionStatus(status)
+	// Bridge delivers refunds as completed transactions with a negative amount
+	refund := txType == "refund" || amount.IsNegative()
+	if refund {
+		txType = "refund"
+	}
 
 	// Check for duplicate
 	existing, _ := s.repo.GetTransactionByBridgeID(ctx, bridgeTransID)
 	if existing != nil {
 		// Update status if changed
 		if existing.Status != status {
-			// If transitioning to completed, deduct from spend balance
+			// If transitioning to completed, deduct from spend balance or credit the refund
 			if status == "completed" && existing.Status != "completed" {
-				if err := s.settleTransaction(ctx, card.UserID, amount, bridgeTransID, merchantName); err != nil {
+				if err := s.settleOrCreditRefund(ctx, card.UserID, refund, amount, bridgeTransID, merchantName); err != nil {
 					s.logger.Error("Failed to settle card transaction",
 						zap.String("transaction_id", bridgeTransID),
 						zap.Error(err))
 					return err
 				}
 			}
-			return s.repo.UpdateTransactionStatus(ctx, existing.ID, status, declineReason)
+			if err := s.repo.UpdateTransactionStatus(ctx, existing.ID, status, declineReason); err != nil {
+				return err
+			}
+			switch status {
+			case "completed":
+				settled := *existing
+				settled.Amount = amount
+				if refund {
+					settled.Type = txType
+				} else {
+					s.consumeSingleUseCard(ctx, card)
+				}
+				s.roundUpSettlement(ctx, &settled)
+				s.accrueCashback(ctx, &settled)
+			case string(entities.CardTxStatusReversed):
+				s.reverseRoundUp(ctx, existing)
+				s.reverseCashback(ctx, existing)
+			}
 		}
 		return nil
 	}
@@ -342,24 +365,98 @@ func (s *Service) RecordTransaction(ctx context.Context, bridgeCardID, bridgeTra
 		Status:           status,
 		DeclineReason:    declineReason,
 	}
+	if declineReason != nil && entities.CardDeclineCode(*declineReason).IsValid() {
+		tx.DeclineCode = declineReason
+	}
+	s.enrichTransaction(ctx, tx)
 
 	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
 		return err
 	}
 
-	// If transaction is already completed (captured), deduct from spend balance
+	// If transaction is already completed (captured), deduct from spend balance or credit the refund
 	if status == "completed" {
-		if err := s.settleTransaction(ctx, card.UserID, amount, bridgeTransID, merchantName); err != nil {
+		if err := s.settleOrCreditRefund(ctx, card.UserID, refund, amount, bridgeTransID, merchantName); err != nil {
 			s.logger.Error("Failed to settle card transaction",
 				zap.String("transaction_id", bridgeTransID),
 				zap.Error(err))
 			return err
 		}
+		if !refund {
+			s.consumeSingleUseCard(ctx, card)
+		}
+		s.roundUpSettlement(ctx, tx)
+		s.accrueCashback(ctx, tx)
 	}
 
 	return nil
 }
 
+// settleOrCreditRefund deducts a settled purchase from the spend balance, or credits a refund back to it
+func (s *Service) settleOrCreditRefund(ctx context.Context, userID uuid.UUID, refund bool, amount decimal.Decimal, transactionID, merchantName string) error {
+	if refund {
+		return s.creditRefund(ctx, userID, amount.Abs(), transactionID, merchantName)
+	}
+	return s.settleTransaction(ctx, userID, amount, transactionID, merchantName)
+}
+
+// creditRefund returns a refunded card amount from the settlement buffer to the spend balance.
+// The idempotency key is derived from the refund so a redelivered webhook never credits twice.
+func (s *Service) creditRefund(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, transactionID, merchantName string) error {
+	if !amount.IsPositive() {
+		return nil
+	}
+	if s.ledgerService == nil {
+		return fmt.Errorf("ledger service required to credit card refund")
+	}
+	s.logger.Info("Crediting card refund",
+		zap.String("user_id", userID.String()),
+		zap.String("amount", amount.String()),
+		zap.String("transaction_id", transactionID))
+
+	spendAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, userID, entities.AccountTypeSpendingBalance)
+	if err != nil {
+		return fmt.Errorf("failed to get spend account: %w", err)
+	}
+	bufferAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemBufferFiat)
+	if err != nil {
+		return fmt.Errorf("failed to get system buffer account: %w", err)
+	}
+
+	desc := fmt.Sprintf("Card refund: %s", merchantName)
+	if merchantName == "" {
+		desc = fmt.Sprintf("Card refund: %s", transactionID)
+	}
+	referenceType := "card_transaction"
+	_, err = s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &userID,
+		TransactionType: entities.TransactionTypeReversal,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  fmt.Sprintf("card-refund:%s", transactionID),
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   spendAccount.ID,
+				EntryType:   entities.EntryTypeDebit, // Increase spend balance
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   bufferAccount.ID,
+				EntryType:   entities.EntryTypeCredit,
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to credit card refund: %w", err)
+	}
+	return nil
+}
+
 // settleTransaction deducts from spend balance and creates ledger entry
 func (s *Service) settleTransaction(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, transactionID, merchantName string) error {
 	s.logger.Info("Settling card transaction",
@@ -420,18 +517,16 @@ func (s *Service) createCardTransactionLedgerEntry(ctx context.Context, userID u
 	return err
 }
 
-// ProcessAuthorization implements BridgeCardProcessor interface for webhook handling
-func (s *Service) ProcessAuthorization(ctx context.Context, cardID string, amount decimal.Decimal, merchantName, merchantCategory string) error {
-	approved, declineReason, err := s.ProcessCardAuthorization(ctx, cardID, amount, merchantName, merchantCategory)
-	if err != nil {
-		return err
-	}
-	if !approved {
-		s.logger.Warn("Card authorization declined",
-			zap.String("card_id", cardID),
-			zap.String("reason", declineReason))
-	}
-	return nil
+// ProcessAuthorization implements BridgeCardProcessor interface for webhook handling. The request
+// carries the Bridge transaction ID so the decision, decline code and risk score are persisted,
+// and the decision is returned so the webhook can approve or decline the transaction at Bridge.
+func (s *Service) ProcessAuthorization(ctx context.Context, req *AuthorizationRequest) (*entities.CardAuthorizationDecision, error) {
+	s.logger.Info("Processing card authorization",
+		zap.String("bridge_card_id", req.BridgeCardID),
+		zap.String("bridge_trans_id", req.BridgeTransID),
+		zap.String("amount", req.Amount.String()))
+
+	return s.AuthorizeTransaction(ctx, req)
 }
 
 // RecordDeclinedTransaction implements BridgeCardProcessor interface for webhook handling
@@ -446,6 +541,9 @@ func (s *Service) SyncCardStatus(ctx context.Context, bridgeCardID string) error
 	if err != nil || card == nil {
 		return ErrCardNotFound
 	}
+	if s.bridgeAdapter == nil {
+		return fmt.Errorf("bridge adapter not configured")
+	}
 
 	bridgeCard, err := s.bridgeAdapter.Client().GetCardAccount(ctx, card.BridgeCustomerID, bridgeCardID)
 	if err != nil {
@@ -453,6 +551,10 @@ func (s *Service) SyncCardStatus(ctx context.Context, bridgeCardID string) error
 	}
 
 	newStatus := mapBridgeCardStatus(bridgeCard.Status)
+	// Physical cards only become active through user activation
+	if card.Type == entities.CardTypePhysical && card.ActivatedAt == nil && newStatus == entities.CardStatusActive {
+		return nil
+	}
 	if card.Status != newStatus {
 		return s.repo.UpdateStatus(ctx, card.ID, newStatus)
 	}
@@ -462,6 +564,15 @@ func (s *Service) SyncCardStatus(ctx context.Context, bridgeCardID string) error
 
 // Helper functions
 
+// isActivated reports whether a card has left the pending state, which for
+// physical cards only happens through user activation
+func isActivated(card *entities.BridgeCard) bool {
+	if card.Status == entities.CardStatusPending {
+		return false
+	}
+	return card.Type != entities.CardTypePhysical || card.ActivatedAt != nil
+}
+
 func mapBridgeCardStatus(status bridge.CardAccountStatus) entities.CardStatus {
 	switch status {
 	case bridge.CardAccountStatusActive:
@@ -475,6 +586,17 @@ func mapBridgeCardStatus(status bridge.CardAccountStatus) entities.CardStatus {
 	}
 }
 
+// normalizeTransactionStatus maps Bridge settlement statuses onto the statuses card_transactions stores
+func normalizeTransactionStatus(status string) string {
+	switch status {
+	case "posted", "settled", "captured":
+		return string(entities.CardTxStatusCompleted)
+	case "refunded":
+		return string(entities.CardTxStatusReversed)
+	}
+	return status
+}
+
 func nilIfEmpty(s string) *string {
 	if s == "" {
 		return nil
@@ -0,0 +1,437 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// BlockTradingAdapter executes aggregated orders on the firm (omnibus) account and journals the
+// resulting shares and cash between the firm account and drafters' brokerage accounts
+type BlockTradingAdapter interface {
+	PlaceBlockOrder(ctx context.Context, symbol string, side string, quantity decimal.Decimal) (orderID string, filledQuantity, averagePrice decimal.Decimal, err error)
+	// JournalShares moves shares from the firm account to the user for buys, and back for sells
+	JournalShares(ctx context.Context, userID uuid.UUID, symbol string, side string, quantity decimal.Decimal) (journalID string, err error)
+	// JournalCash moves cash from the user to the firm account to pay for buys, and sale
+	// proceeds or unused buy cash from the firm account back to the user for sells
+	JournalCash(ctx context.Context, userID uuid.UUID, side string, amount decimal.Decimal) (journalID string, err error)
+}
+
+// SetBlockTradingAdapter enables block-order execution for signals followed by several drafts
+func (s *Service) SetBlockTradingAdapter(adapter BlockTradingAdapter) {
+	s.blockAdapter = adapter
+}
+
+// blockLeg is one draft's part of a block order
+type blockLeg struct {
+	draft          *entities.Draft
+	idempotencyKey string
+	allocation     *entities.BlockAllocation
+	funded         decimal.Decimal // Cash journaled from the drafter to the firm account for a buy
+}
+
+// === Block Order Execution ===
+
+// executeBlockOrder sizes every draft's copy of a signal, places the total as one firm-account
+// order and allocates the fill back to the drafts pro rata. Each drafter's cash for a buy is
+// journaled to the firm account before the order is placed and bought shares reach the drafter by
+// journal after the fill, with unused cash returned. Shares to sell are journaled to the firm
+// account before the order is placed, so the firm never sells shares it does not hold; unsold
+// shares are journaled back and the sale proceeds are journaled to each drafter.
+// Every draft gets its own execution log at the block's average fill price. It returns the
+// execution error per draft, with drafts that were skipped or already executed mapped to nil.
+func (s *Service) executeBlockOrder(ctx context.Context, signal *entities.Signal, drafts []*entities.Draft) map[uuid.UUID]error {
+	results := make(map[uuid.UUID]error, len(drafts))
+	if signal.ConductorAUMAtSignal.IsZero() {
+		for _, draft := range drafts {
+			results[draft.ID] = fmt.Errorf("conductor AUM is zero")
+		}
+		return results
+	}
+
+	currentPrice, priceErr := s.tradingAdapter.GetCurrentPrice(ctx, signal.AssetTicker)
+
+	var legs []*blockLeg
+	for _, draft := range drafts {
+		idempotencyKey := fmt.Sprintf("copy_%s_%s", draft.ID.String(), signal.ID.String())
+		existing, err := s.repo.GetExecutionLogByIdempotencyKey(ctx, idempotencyKey)
+		if err != nil {
+			results[draft.ID] = fmt.Errorf("failed to check idempotency: %w", err)
+			continue
+		}
+		if existing != nil {
+			s.logger.Debug("Trade already executed", zap.String("idempotency_key", idempotencyKey))
+			continue
+		}
+		if priceErr != nil {
+			results[draft.ID] = s.logExecution(ctx, draft, signal, idempotencyKey, decimal.Zero, decimal.Zero,
+				entities.ExecutionStatusFailed, fmt.Sprintf("failed to get price: %v", priceErr))
+			continue
+		}
+
+		quantity, status, reason := s.sizeDraftTrade(ctx, draft, signal, currentPrice)
+		if status != entities.ExecutionStatusSuccess {
+			results[draft.ID] = s.logExecution(ctx, draft, signal, idempotencyKey, quantity, currentPrice, status, reason)
+			continue
+		}
+		legs = append(legs, &blockLeg{
+			draft:          draft,
+			idempotencyKey: idempotencyKey,
+			allocation: &entities.BlockAllocation{
+				DraftID:           draft.ID,
+				RequestedQuantity: quantity.Truncate(entities.BlockAllocationPrecision),
+			},
+		})
+	}
+	if signal.Side == "sell" {
+		legs = s.journalSellLegs(ctx, signal, legs, currentPrice, results)
+	} else {
+		legs = s.fundBuyLegs(ctx, signal, legs, currentPrice, results)
+	}
+	if len(legs) == 0 {
+		return results
+	}
+
+	total := decimal.Zero
+	for _, leg := range legs {
+		total = total.Add(leg.allocation.RequestedQuantity)
+	}
+	block := &entities.BlockOrder{
+		ID:                uuid.New(),
+		SignalID:          signal.ID,
+		AssetTicker:       signal.AssetTicker,
+		Side:              signal.Side,
+		RequestedQuantity: total,
+		FilledQuantity:    decimal.Zero,
+		AveragePrice:      decimal.Zero,
+		DraftCount:        len(legs),
+		Status:            entities.BlockOrderStatusPending,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err := s.repo.CreateBlockOrder(ctx, block); err != nil {
+		for _, leg := range legs {
+			s.returnUnsoldShares(ctx, signal, leg, leg.allocation.RequestedQuantity)
+			s.returnUnusedCash(ctx, signal, leg, leg.funded)
+			results[leg.draft.ID] = s.logBlockExecution(ctx, signal, nil, leg, decimal.Zero, currentPrice,
+				entities.ExecutionStatusFailed, fmt.Sprintf("failed to record block order: %v", err))
+		}
+		return results
+	}
+
+	orderID, filled, averagePrice, err := s.blockAdapter.PlaceBlockOrder(ctx, signal.AssetTicker, signal.Side, total)
+	completedAt := time.Now().UTC()
+	block.CompletedAt = &completedAt
+	if err != nil {
+		block.Status = entities.BlockOrderStatusFailed
+		block.ErrorMessage = err.Error()
+		s.saveBlockOrder(ctx, block)
+		for _, leg := range legs {
+			s.returnUnsoldShares(ctx, signal, leg, leg.allocation.RequestedQuantity)
+			s.returnUnusedCash(ctx, signal, leg, leg.funded)
+			results[leg.draft.ID] = s.logBlockExecution(ctx, signal, block, leg, leg.allocation.RequestedQuantity, currentPrice,
+				entities.ExecutionStatusFailed, fmt.Sprintf("block order failed: %v", err))
+		}
+		return results
+	}
+
+	block.OrderID = orderID
+	block.FilledQuantity = decimal.Min(filled, total)
+	block.AveragePrice = averagePrice
+	switch {
+	case block.FilledQuantity.Equal(total):
+		block.Status = entities.BlockOrderStatusFilled
+	case block.FilledQuantity.IsPositive():
+		block.Status = entities.BlockOrderStatusPartiallyFilled
+	default:
+		block.Status = entities.BlockOrderStatusFailed
+		block.ErrorMessage = "block order not filled"
+	}
+
+	allocations := make([]*entities.BlockAllocation, len(legs))
+	for i, leg := range legs {
+		allocations[i] = leg.allocation
+	}
+	// Sold fractions below the allocation precision round up so the unsold shares journaled back
+	// never exceed what the firm account still holds
+	allocatable := block.FilledQuantity
+	if signal.Side == "sell" {
+		allocatable = decimal.Min(allocatable.RoundCeil(entities.BlockAllocationPrecision), total)
+	}
+	residual := allocateBlockFill(allocations, allocatable)
+	if residual.IsPositive() {
+		s.logger.Warn("Block order residual left on firm account",
+			zap.String("block_order_id", block.ID.String()),
+			zap.String("residual", residual.String()))
+	}
+	s.saveBlockOrder(ctx, block)
+
+	for _, leg := range legs {
+		results[leg.draft.ID] = s.allocateBlockLeg(ctx, signal, block, leg)
+	}
+
+	s.logger.Info("Block order executed",
+		zap.String("block_order_id", block.ID.String()),
+		zap.String("signal_id", signal.ID.String()),
+		zap.Int("drafts", len(legs)),
+		zap.String("requested", total.String()),
+		zap.String("filled", block.FilledQuantity.String()),
+		zap.String("average_price", averagePrice.String()))
+
+	return results
+}
+
+// allocateBlockLeg journals a draft's bought shares and settles its buy cash, or returns its unsold
+// shares and journals its sale proceeds, and books the trade against its cash
+func (s *Service) allocateBlockLeg(ctx context.Context, signal *entities.Signal, block *entities.BlockOrder, leg *blockLeg) error {
+	allocated := leg.allocation.AllocatedQuantity
+	if signal.Side == "sell" {
+		s.returnUnsoldShares(ctx, signal, leg, leg.allocation.RequestedQuantity.Sub(allocated))
+	}
+	if !allocated.IsPositive() {
+		s.returnUnusedCash(ctx, signal, leg, leg.funded)
+		return s.logBlockExecution(ctx, signal, block, leg, leg.allocation.RequestedQuantity, block.AveragePrice,
+			entities.ExecutionStatusFailed, "block order not filled")
+	}
+
+	executedValue := allocated.Mul(block.AveragePrice)
+	if signal.Side == "buy" {
+		if _, err := s.blockAdapter.JournalShares(ctx, leg.draft.DrafterID, signal.AssetTicker, signal.Side, allocated); err != nil {
+			s.returnUnusedCash(ctx, signal, leg, leg.funded)
+			return s.logBlockExecution(ctx, signal, block, leg, allocated, block.AveragePrice,
+				entities.ExecutionStatusFailed, fmt.Sprintf("journal failed: %v", err))
+		}
+		s.settleBuyCash(ctx, signal, leg, executedValue.Round(2))
+	} else {
+		if _, err := s.blockAdapter.JournalCash(ctx, leg.draft.DrafterID, "sell", executedValue.RoundDown(2)); err != nil {
+			return s.logBlockExecution(ctx, signal, block, leg, allocated, block.AveragePrice,
+				entities.ExecutionStatusFailed, fmt.Sprintf("proceeds journal failed: %v", err))
+		}
+	}
+
+	newAUM := leg.draft.CurrentAUM
+	if signal.Side == "buy" {
+		newAUM = newAUM.Sub(executedValue)
+	} else {
+		newAUM = newAUM.Add(executedValue)
+	}
+	if err := s.repo.UpdateDraftAUM(ctx, leg.draft.ID, newAUM, newAUM.Sub(leg.draft.StartValue)); err != nil {
+		s.logger.Error("Failed to update draft AUM", zap.Error(err))
+	}
+
+	status := entities.ExecutionStatusSuccess
+	if allocated.LessThan(leg.allocation.RequestedQuantity) {
+		status = entities.ExecutionStatusPartial
+	}
+	return s.logBlockExecution(ctx, signal, block, leg, allocated, block.AveragePrice, status, "")
+}
+
+// journalSellLegs moves each draft's shares to sell into the firm account ahead of the block
+// order. Drafts whose journal fails are logged as failed and left out of the block.
+func (s *Service) journalSellLegs(ctx context.Context, signal *entities.Signal, legs []*blockLeg, price decimal.Decimal, results map[uuid.UUID]error) []*blockLeg {
+	journaled := legs[:0]
+	for _, leg := range legs {
+		if _, err := s.blockAdapter.JournalShares(ctx, leg.draft.DrafterID, signal.AssetTicker, "sell", leg.allocation.RequestedQuantity); err != nil {
+			results[leg.draft.ID] = s.logBlockExecution(ctx, signal, nil, leg, decimal.Zero, price,
+				entities.ExecutionStatusFailed, fmt.Sprintf("journal failed: %v", err))
+			continue
+		}
+		journaled = append(journaled, leg)
+	}
+	return journaled
+}
+
+// fundBuyLegs journals each draft's buy notional at the current price from the drafter to the
+// firm account ahead of the block order. Drafts whose journal fails are logged as failed and left
+// out of the block.
+func (s *Service) fundBuyLegs(ctx context.Context, signal *entities.Signal, legs []*blockLeg, price decimal.Decimal, results map[uuid.UUID]error) []*blockLeg {
+	funded := legs[:0]
+	for _, leg := range legs {
+		notional := leg.allocation.RequestedQuantity.Mul(price).Round(2)
+		if _, err := s.blockAdapter.JournalCash(ctx, leg.draft.DrafterID, "buy", notional); err != nil {
+			results[leg.draft.ID] = s.logBlockExecution(ctx, signal, nil, leg, decimal.Zero, price,
+				entities.ExecutionStatusFailed, fmt.Sprintf("cash journal failed: %v", err))
+			continue
+		}
+		leg.funded = notional
+		funded = append(funded, leg)
+	}
+	return funded
+}
+
+// settleBuyCash squares a draft's funded cash with what its shares cost at the fill price,
+// returning unused cash or collecting the difference when the price rose. A failed journal is
+// logged for reconciliation since the shares have already been delivered.
+func (s *Service) settleBuyCash(ctx context.Context, signal *entities.Signal, leg *blockLeg, cost decimal.Decimal) {
+	diff := leg.funded.Sub(cost)
+	if diff.IsPositive() {
+		s.returnUnusedCash(ctx, signal, leg, diff)
+		return
+	}
+	if !diff.IsNegative() {
+		return
+	}
+	if _, err := s.blockAdapter.JournalCash(ctx, leg.draft.DrafterID, "buy", diff.Neg()); err != nil {
+		s.logger.Error("Failed to collect buy price difference from drafter, owed to firm account",
+			zap.String("draft_id", leg.draft.ID.String()),
+			zap.String("drafter_id", leg.draft.DrafterID.String()),
+			zap.String("amount", diff.Neg().String()),
+			zap.Error(err))
+	}
+}
+
+// returnUnusedCash journals buy cash a draft sent to the firm account back to the drafter when it
+// was not spent. A failed return leaves the cash on the firm account, which is logged for
+// reconciliation.
+func (s *Service) returnUnusedCash(ctx context.Context, signal *entities.Signal, leg *blockLeg, amount decimal.Decimal) {
+	if signal.Side != "buy" || !amount.IsPositive() {
+		return
+	}
+	if _, err := s.blockAdapter.JournalCash(ctx, leg.draft.DrafterID, "sell", amount); err != nil {
+		s.logger.Error("Failed to return unused cash to drafter, left on firm account",
+			zap.String("draft_id", leg.draft.ID.String()),
+			zap.String("drafter_id", leg.draft.DrafterID.String()),
+			zap.String("amount", amount.String()),
+			zap.Error(err))
+	}
+}
+
+// returnUnsoldShares journals shares a draft sent to the firm account for a sell back to the
+// drafter when they were not sold. A failed return leaves the shares on the firm account, which
+// is logged for reconciliation.
+func (s *Service) returnUnsoldShares(ctx context.Context, signal *entities.Signal, leg *blockLeg, quantity decimal.Decimal) {
+	if signal.Side != "sell" || !quantity.IsPositive() {
+		return
+	}
+	if _, err := s.blockAdapter.JournalShares(ctx, leg.draft.DrafterID, signal.AssetTicker, "buy", quantity); err != nil {
+		s.logger.Error("Failed to return unsold shares to drafter, left on firm account",
+			zap.String("draft_id", leg.draft.ID.String()),
+			zap.String("drafter_id", leg.draft.DrafterID.String()),
+			zap.String("symbol", signal.AssetTicker),
+			zap.String("quantity", quantity.String()),
+			zap.Error(err))
+	}
+}
+
+// logBlockExecution records a draft's execution log for its part of a block order
+func (s *Service) logBlockExecution(ctx context.Context, signal *entities.Signal, block *entities.BlockOrder, leg *blockLeg,
+	quantity, price decimal.Decimal, status entities.ExecutionStatus, errMsg string) error {
+
+	now := time.Now().UTC()
+	log := &entities.SignalExecutionLog{
+		ID:               uuid.New(),
+		DraftID:          leg.draft.ID,
+		SignalID:         &signal.ID,
+		ExecutionType:    entities.ExecutionTypeSignal,
+		AssetTicker:      signal.AssetTicker,
+		Side:             signal.Side,
+		ExecutedQuantity: quantity,
+		ExecutedPrice:    price,
+		ExecutedValue:    quantity.Mul(price),
+		Status:           status,
+		FeeApplied:       decimal.Zero,
+		ErrorMessage:     errMsg,
+		IdempotencyKey:   leg.idempotencyKey,
+		CreatedAt:        now,
+	}
+	if block != nil {
+		log.BlockOrderID = &block.ID
+		log.OrderID = block.OrderID
+	}
+
+	if status == entities.ExecutionStatusFailed {
+		if err := s.repo.CreateExecutionLog(ctx, log); err != nil {
+			s.logger.Error("Failed to create execution log", zap.Error(err))
+		}
+		return fmt.Errorf("execution failed: %s", errMsg)
+	}
+
+	log.ExecutedAt = &now
+	return s.repo.CreateExecutionLog(ctx, log)
+}
+
+// saveBlockOrder persists a block order's outcome, logging rather than returning failures
+func (s *Service) saveBlockOrder(ctx context.Context, block *entities.BlockOrder) {
+	if err := s.repo.UpdateBlockOrder(ctx, block); err != nil {
+		s.logger.Error("Failed to update block order",
+			zap.String("block_order_id", block.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// allocateBlockFill splits a filled quantity across allocations in proportion to their requested
+// quantities. Each share is truncated to BlockAllocationPrecision and the leftover fractional units
+// go to the largest remainders, never beyond what an allocation requested. Whatever cannot be
+// allocated stays on the firm account and is returned.
+func allocateBlockFill(allocations []*entities.BlockAllocation, filled decimal.Decimal) decimal.Decimal {
+	requested := decimal.Zero
+	for _, a := range allocations {
+		requested = requested.Add(a.RequestedQuantity)
+	}
+	if !requested.IsPositive() || !filled.IsPositive() {
+		for _, a := range allocations {
+			a.AllocatedQuantity = decimal.Zero
+		}
+		return decimal.Max(filled, decimal.Zero)
+	}
+	filled = decimal.Min(filled, requested)
+
+	allocated := decimal.Zero
+	remainders := make([]decimal.Decimal, len(allocations))
+	for i, a := range allocations {
+		exact := a.RequestedQuantity.Mul(filled).Div(requested)
+		a.AllocatedQuantity = exact.Truncate(entities.BlockAllocationPrecision)
+		remainders[i] = exact.Sub(a.AllocatedQuantity)
+		allocated = allocated.Add(a.AllocatedQuantity)
+	}
+
+	order := make([]int, len(allocations))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].GreaterThan(remainders[order[j]])
+	})
+
+	unit := decimal.New(1, -entities.BlockAllocationPrecision)
+	leftover := filled.Sub(allocated)
+	for _, i := range order {
+		if leftover.LessThan(unit) {
+			break
+		}
+		a := allocations[i]
+		if !remainders[i].IsPositive() || a.AllocatedQuantity.Add(unit).GreaterThan(a.RequestedQuantity) {
+			continue
+		}
+		a.AllocatedQuantity = a.AllocatedQuantity.Add(unit)
+		leftover = leftover.Sub(unit)
+	}
+	return leftover
+}
+
+// sizeCopyTrade returns the quantity a draft copies of a signal at the given price. When the draft
+// cannot trade it returns the status and reason to log instead of ExecutionStatusSuccess.
+// Drafter Quantity = (Drafter's Allocated Capital / Conductor's Source AUM) × Conductor's Base Quantity × Copy Ratio
+func sizeCopyTrade(draft *entities.Draft, signal *entities.Signal, price decimal.Decimal) (decimal.Decimal, entities.ExecutionStatus, string) {
+	ratio := draft.AllocatedCapital.Div(signal.ConductorAUMAtSignal)
+	quantity := signal.BaseQuantity.Mul(ratio).Mul(draft.CopyRatio)
+
+	if quantity.Mul(price).LessThan(entities.MinimumTradeValue) {
+		return quantity, entities.ExecutionStatusSkippedTooSmall, "trade value below minimum"
+	}
+
+	// Buys larger than the draft's cash are scaled down to what it can afford
+	if signal.Side == "buy" && quantity.Mul(price).GreaterThan(draft.CurrentAUM) {
+		if draft.CurrentAUM.LessThan(entities.MinimumTradeValue) {
+			return decimal.Zero, entities.ExecutionStatusInsufficientFunds, "insufficient funds for minimum trade"
+		}
+		quantity = draft.CurrentAUM.Div(price)
+	}
+	return quantity, entities.ExecutionStatusSuccess, ""
+}
@@ -0,0 +1,295 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// AssetEligibilityProvider looks up the brokerage data used to decide whether a conductor's buy of
+// an asset may be copied
+type AssetEligibilityProvider interface {
+	GetAssetEligibility(ctx context.Context, symbol string) (*entities.AssetEligibility, error)
+}
+
+// SetAssetEligibilityProvider sets the asset eligibility provider (optional). Without one, the
+// asset allowlist is not enforced.
+func (s *Service) SetAssetEligibilityProvider(provider AssetEligibilityProvider) {
+	s.assetEligibility = provider
+}
+
+// === Compliance Operations ===
+
+// checkSignalCompliance decides whether a conductor trade may become a signal. It returns the
+// control the trade ran into and an explanation, or an empty event type when nothing applies.
+// Sells reduce or close a position, so followers must be able to copy them: they skip the
+// frequency limit, and a blackout only records the sell rather than blocking it.
+func (s *Service) checkSignalCompliance(ctx context.Context, conductor *entities.Conductor, ticker, side string, at time.Time) (entities.ComplianceEventType, string, bool, error) {
+	windows, err := s.repo.GetActiveBlackoutWindows(ctx, conductor.ID, ticker, at)
+	if err != nil {
+		return "", "", false, err
+	}
+	if len(windows) > 0 {
+		return entities.ComplianceEventBlackout,
+			fmt.Sprintf("blackout until %s: %s", windows[0].EndsAt.Format(time.RFC3339), windows[0].Reason), side != "sell", nil
+	}
+	if side == "sell" {
+		return "", "", false, nil
+	}
+
+	hourly, err := s.repo.CountConductorTradeSignalsSince(ctx, conductor.ID, at.Add(-time.Hour))
+	if err != nil {
+		return "", "", false, err
+	}
+	if hourly >= entities.ComplianceMaxSignalsPerHour {
+		return entities.ComplianceEventFrequencyLimit,
+			fmt.Sprintf("%d signals in the last hour, limit is %d", hourly, entities.ComplianceMaxSignalsPerHour), true, nil
+	}
+	daily, err := s.repo.CountConductorTradeSignalsSince(ctx, conductor.ID, at.Add(-24*time.Hour))
+	if err != nil {
+		return "", "", false, err
+	}
+	if daily >= entities.ComplianceMaxSignalsPerDay {
+		return entities.ComplianceEventFrequencyLimit,
+			fmt.Sprintf("%d signals in the last day, limit is %d", daily, entities.ComplianceMaxSignalsPerDay), true, nil
+	}
+
+	// Only buys are held to the allowlist; followers must always be able to exit a position
+	if side != "buy" || s.assetEligibility == nil {
+		return "", "", false, nil
+	}
+	asset, err := s.assetEligibility.GetAssetEligibility(ctx, ticker)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to check asset eligibility: %w", err)
+	}
+	if reason := assetRestriction(asset); reason != "" {
+		return entities.ComplianceEventRestrictedAsset, reason, true, nil
+	}
+	return "", "", false, nil
+}
+
+// assetRestriction explains why an asset is outside the allowlist, or returns "" if it is eligible
+func assetRestriction(asset *entities.AssetEligibility) string {
+	switch {
+	case asset == nil:
+		return "asset not found"
+	case !asset.Tradable:
+		return "asset is not tradable"
+	case !entities.ComplianceExchanges[strings.ToUpper(asset.Exchange)]:
+		return fmt.Sprintf("asset is listed on %s, which is not an approved exchange", asset.Exchange)
+	case asset.AverageDollarVolume.LessThan(entities.ComplianceMinDollarVolume):
+		return fmt.Sprintf("average daily dollar volume %s is below the %s minimum",
+			asset.AverageDollarVolume.StringFixed(0), entities.ComplianceMinDollarVolume.StringFixed(0))
+	}
+	return ""
+}
+
+// detectFrontRunning flags a conductor sell of an asset that followers bought on the conductor's
+// signals within the front-running window. The sell is still copied; the flag is for review.
+func (s *Service) detectFrontRunning(ctx context.Context, conductor *entities.Conductor, signal *entities.Signal) {
+	since := signal.CreatedAt.Add(-entities.ComplianceFrontRunningWindow)
+	value, count, err := s.repo.GetFollowerBuysSince(ctx, conductor.ID, signal.AssetTicker, since)
+	if err != nil {
+		s.logger.Error("Failed to check follower buys", zap.String("signal_id", signal.ID.String()), zap.Error(err))
+		return
+	}
+	if count == 0 {
+		return
+	}
+
+	details := fmt.Sprintf("sold %s into %d follower buys worth %s within %s",
+		signal.AssetTicker, count, value.StringFixed(2), entities.ComplianceFrontRunningWindow)
+	s.recordComplianceEvent(ctx, conductor, &signal.ID, entities.ComplianceEventFrontRunning,
+		signal.AssetTicker, signal.Side, signal.BaseValue, false, details)
+}
+
+// recordComplianceEvent stores a compliance event, logging rather than returning failures
+func (s *Service) recordComplianceEvent(ctx context.Context, conductor *entities.Conductor, signalID *uuid.UUID, eventType entities.ComplianceEventType, ticker, side string, value decimal.Decimal, blocked bool, details string) {
+	event := &entities.ConductorComplianceEvent{
+		ID:          uuid.New(),
+		ConductorID: conductor.ID,
+		SignalID:    signalID,
+		EventType:   eventType,
+		AssetTicker: ticker,
+		Side:        side,
+		TradeValue:  value,
+		Blocked:     blocked,
+		Details:     details,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.repo.CreateComplianceEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record compliance event",
+			zap.String("conductor_id", conductor.ID.String()),
+			zap.String("event_type", string(eventType)),
+			zap.Error(err))
+	}
+
+	s.logger.Warn("Conductor compliance event",
+		zap.String("conductor_id", conductor.ID.String()),
+		zap.String("event_type", string(eventType)),
+		zap.String("ticker", ticker),
+		zap.Bool("blocked", blocked),
+		zap.String("details", details))
+}
+
+// GetConductorComplianceEvents returns a conductor's recent compliance events
+func (s *Service) GetConductorComplianceEvents(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorComplianceEvent, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	events, err := s.repo.GetComplianceEventsByConductor(ctx, conductorID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance events: %w", err)
+	}
+	return events, nil
+}
+
+// GetComplianceReport summarises compliance events since a time for each conductor that had any,
+// flagging conductors that front-ran followers or repeatedly tried restricted trades
+func (s *Service) GetComplianceReport(ctx context.Context, since time.Time) (*entities.ComplianceReport, error) {
+	counts, err := s.repo.GetComplianceEventCounts(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance events: %w", err)
+	}
+
+	byConductor := make(map[uuid.UUID]*entities.ConductorSurveillance)
+	for _, c := range counts {
+		line, ok := byConductor[c.ConductorID]
+		if !ok {
+			line = &entities.ConductorSurveillance{
+				ConductorID: c.ConductorID,
+				EventCounts: make(map[entities.ComplianceEventType]int),
+			}
+			byConductor[c.ConductorID] = line
+		}
+		line.EventCounts[c.EventType] += c.Count
+		if c.Blocked {
+			line.BlockedTrades += c.Count
+		}
+		if c.EventType == entities.ComplianceEventFrontRunning {
+			line.FrontRunningFlags += c.Count
+		}
+		if c.LastEventAt.After(line.LastEventAt) {
+			line.LastEventAt = c.LastEventAt
+		}
+	}
+
+	report := &entities.ComplianceReport{
+		Since:       since,
+		GeneratedAt: time.Now().UTC(),
+		Conductors:  make([]*entities.ConductorSurveillance, 0, len(byConductor)),
+	}
+	for _, line := range byConductor {
+		conductor, err := s.repo.GetConductorByID(ctx, line.ConductorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get conductor: %w", err)
+		}
+		if conductor != nil {
+			line.DisplayName = conductor.DisplayName
+			line.Status = conductor.Status
+		}
+		line.Suspicious = line.FrontRunningFlags > 0 || line.BlockedTrades >= entities.ComplianceSuspiciousBlocks
+		report.Conductors = append(report.Conductors, line)
+	}
+	sort.Slice(report.Conductors, func(i, j int) bool {
+		a, b := report.Conductors[i], report.Conductors[j]
+		if a.Suspicious != b.Suspicious {
+			return a.Suspicious
+		}
+		if a.FrontRunningFlags != b.FrontRunningFlags {
+			return a.FrontRunningFlags > b.FrontRunningFlags
+		}
+		return a.BlockedTrades > b.BlockedTrades
+	})
+
+	return report, nil
+}
+
+// SuspendConductor stops a conductor's trades from generating signals. Existing drafts stay open
+// so followers can withdraw or unlink on their own terms.
+func (s *Service) SuspendConductor(ctx context.Context, adminID, conductorID uuid.UUID, reason string) (*entities.Conductor, error) {
+	conductor, err := s.repo.GetConductorByID(ctx, conductorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("conductor not found")
+	}
+	if conductor.Status == entities.ConductorStatusSuspended {
+		return nil, fmt.Errorf("conductor already suspended")
+	}
+
+	if err := s.repo.UpdateConductorStatus(ctx, conductorID, entities.ConductorStatusSuspended); err != nil {
+		return nil, fmt.Errorf("failed to suspend conductor: %w", err)
+	}
+	conductor.Status = entities.ConductorStatusSuspended
+
+	s.recordComplianceEvent(ctx, conductor, nil, entities.ComplianceEventSuspended, "", "", decimal.Zero, false,
+		fmt.Sprintf("suspended by %s: %s", adminID.String(), reason))
+	s.notifyDrafter(ctx, conductor.UserID, "Conductor account suspended",
+		fmt.Sprintf("Your trades are no longer copied to followers pending a compliance review: %s", reason))
+
+	return conductor, nil
+}
+
+// === Blackout Window Operations ===
+
+// CreateBlackoutWindow schedules a period in which matching conductor trades are not copied
+func (s *Service) CreateBlackoutWindow(ctx context.Context, adminID uuid.UUID, req *entities.CreateBlackoutWindowRequest) (*entities.SignalBlackoutWindow, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("blackout must end after it starts")
+	}
+	if req.ConductorID != nil {
+		conductor, err := s.repo.GetConductorByID(ctx, *req.ConductorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get conductor: %w", err)
+		}
+		if conductor == nil {
+			return nil, fmt.Errorf("conductor not found")
+		}
+	}
+
+	window := &entities.SignalBlackoutWindow{
+		ID:          uuid.New(),
+		ConductorID: req.ConductorID,
+		AssetTicker: strings.ToUpper(strings.TrimSpace(req.AssetTicker)),
+		StartsAt:    req.StartsAt.UTC(),
+		EndsAt:      req.EndsAt.UTC(),
+		Reason:      req.Reason,
+		CreatedBy:   adminID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.repo.CreateBlackoutWindow(ctx, window); err != nil {
+		return nil, fmt.Errorf("failed to create blackout window: %w", err)
+	}
+	return window, nil
+}
+
+// GetBlackoutWindows returns blackout windows that are active or upcoming
+func (s *Service) GetBlackoutWindows(ctx context.Context) ([]*entities.SignalBlackoutWindow, error) {
+	windows, err := s.repo.GetBlackoutWindows(ctx, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blackout windows: %w", err)
+	}
+	return windows, nil
+}
+
+// DeleteBlackoutWindow removes a blackout window
+func (s *Service) DeleteBlackoutWindow(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteBlackoutWindow(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blackout window: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("blackout window not found")
+	}
+	return nil
+}
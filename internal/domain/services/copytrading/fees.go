@@ -0,0 +1,462 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// conductorPayoutNamespace derives payout IDs from payout idempotency keys, so a retried payout is
+// recorded under the same ID
+var conductorPayoutNamespace = uuid.MustParse("9a4e6c1d-2f7b-4d83-b5a0-7c3e8f1d2b64")
+
+// LedgerService posts copy trading fees from drafters to conductors
+type LedgerService interface {
+	GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error)
+	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
+}
+
+// SetLedgerService sets the ledger used to charge draft fees and pay conductors
+func (s *Service) SetLedgerService(ledgerService LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// SetCrystallizationPeriod overrides how often accrued fees are charged to drafts
+func (s *Service) SetCrystallizationPeriod(period time.Duration) {
+	s.crystallizationPeriod = period
+}
+
+// === Fee Operations ===
+
+// ProcessFees accrues management fees on drafts, crystallizes fees for drafts whose period has
+// ended and pays out conductors. It returns the number of drafts charged.
+func (s *Service) ProcessFees(ctx context.Context, batchSize int) (int, error) {
+	if s.ledgerService == nil {
+		return 0, fmt.Errorf("ledger service not configured")
+	}
+
+	drafts, err := s.repo.GetDraftsForFeeProcessing(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get drafts: %w", err)
+	}
+
+	now := time.Now().UTC()
+	crystallized := 0
+	for _, draft := range drafts {
+		if !now.Before(feePeriodStart(draft).Add(s.crystallizationPeriod)) {
+			if _, err := s.CrystallizeDraftFees(ctx, draft); err != nil {
+				s.logger.Error("Failed to crystallize draft fees",
+					zap.String("draft_id", draft.ID.String()),
+					zap.Error(err))
+				continue
+			}
+			crystallized++
+			continue
+		}
+
+		if draft.LastFeeAccrualAt != nil && now.Sub(*draft.LastFeeAccrualAt) < entities.FeeAccrualInterval {
+			continue
+		}
+		if err := s.accrueDraftFees(ctx, draft, now); err != nil {
+			s.logger.Error("Failed to accrue draft fees",
+				zap.String("draft_id", draft.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	if _, err := s.PayoutConductorFees(ctx); err != nil {
+		return crystallized, fmt.Errorf("failed to pay out conductors: %w", err)
+	}
+
+	return crystallized, nil
+}
+
+// accrueDraftFees adds the management fee earned since the last accrual to the draft
+func (s *Service) accrueDraftFees(ctx context.Context, draft *entities.Draft, now time.Time) error {
+	conductor, err := s.repo.GetConductorByID(ctx, draft.ConductorID)
+	if err != nil {
+		return fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return fmt.Errorf("conductor not found")
+	}
+	if !conductor.ManagementFee.IsPositive() {
+		return s.repo.UpdateDraftFeeAccrual(ctx, draft.ID, draft.AccruedFees, now)
+	}
+
+	value, err := s.valueDraft(ctx, draft)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateDraftFeeAccrual(ctx, draft.ID, accrueManagementFee(draft, conductor, value, now), now)
+}
+
+// CrystallizeDraftFees charges a draft's accrued management fee plus the performance fee on gains
+// above its high-water mark. The fee moves from the drafter's reserved funds to the conductor's
+// payable account; whatever the draft's cash cannot cover stays accrued for the next period.
+func (s *Service) CrystallizeDraftFees(ctx context.Context, draft *entities.Draft) (*entities.DraftFeeStatement, error) {
+	if s.ledgerService == nil {
+		return nil, fmt.Errorf("ledger service not configured")
+	}
+
+	conductor, err := s.repo.GetConductorByID(ctx, draft.ConductorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("conductor not found")
+	}
+
+	value, err := s.valueDraft(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	managementFee := accrueManagementFee(draft, conductor, value, now)
+
+	// Performance is measured after management fees so they are not charged on themselves
+	performanceFee := decimal.Zero
+	if gain := value.Sub(managementFee).Sub(draft.HighWaterMark); gain.IsPositive() {
+		performanceFee = gain.Mul(conductor.FeeRate).Round(8)
+	}
+	totalFee := managementFee.Add(performanceFee)
+
+	charge := decimal.Min(totalFee, draft.CurrentAUM).Truncate(2)
+	if charge.IsNegative() {
+		charge = decimal.Zero
+	}
+
+	statement := &entities.DraftFeeStatement{
+		ID:                 uuid.New(),
+		DraftID:            draft.ID,
+		DrafterID:          draft.DrafterID,
+		ConductorID:        conductor.ID,
+		PeriodStart:        feePeriodStart(draft),
+		PeriodEnd:          now,
+		DraftValue:         value,
+		OpeningHighWater:   draft.HighWaterMark,
+		ClosingHighWater:   decimal.Max(draft.HighWaterMark, value.Sub(totalFee)),
+		PerformanceFeeRate: conductor.FeeRate,
+		ManagementFee:      managementFee,
+		PerformanceFee:     performanceFee,
+		TotalFee:           totalFee,
+		AmountCharged:      charge,
+		CreatedAt:          now,
+	}
+
+	if charge.IsPositive() {
+		ledgerTxID, err := s.postDraftFee(ctx, draft, conductor, statement)
+		if err != nil {
+			return nil, err
+		}
+		statement.LedgerTxID = ledgerTxID
+	}
+
+	if err := s.repo.RecordFeeCrystallization(ctx, statement); err != nil {
+		return nil, fmt.Errorf("failed to record fee statement: %w", err)
+	}
+
+	s.logger.Info("Draft fees crystallized",
+		zap.String("draft_id", draft.ID.String()),
+		zap.String("conductor_id", conductor.ID.String()),
+		zap.String("management_fee", managementFee.String()),
+		zap.String("performance_fee", performanceFee.String()),
+		zap.String("charged", charge.String()))
+
+	return statement, nil
+}
+
+// postDraftFee moves a crystallized fee from the drafter to the conductor's payable account.
+// The idempotency key is tied to the fee period so a retried crystallization cannot charge twice.
+func (s *Service) postDraftFee(ctx context.Context, draft *entities.Draft, conductor *entities.Conductor, statement *entities.DraftFeeStatement) (*uuid.UUID, error) {
+	drafterAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, draft.DrafterID, entities.AccountTypePendingInvestment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drafter account: %w", err)
+	}
+	payableAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, conductor.UserID, entities.AccountTypeConductorPayable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor payable account: %w", err)
+	}
+
+	desc := fmt.Sprintf("Copy trading fee: %s", conductor.DisplayName)
+	referenceType := "draft_fee_statement"
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &draft.DrafterID,
+		TransactionType: entities.TransactionTypeCopyTradingFee,
+		ReferenceID:     &statement.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  fmt.Sprintf("copy-fee:%s:%d", draft.ID, statement.PeriodStart.Unix()),
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   drafterAccount.ID,
+				EntryType:   entities.EntryTypeCredit,
+				Amount:      statement.AmountCharged,
+				Currency:    "USDC",
+				Description: &desc,
+			},
+			{
+				AccountID:   payableAccount.ID,
+				EntryType:   entities.EntryTypeDebit,
+				Amount:      statement.AmountCharged,
+				Currency:    "USDC",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post fee: %w", err)
+	}
+	return &ledgerTx.ID, nil
+}
+
+// PayoutConductorFees pays each conductor's payable balance into their own USDC balance
+func (s *Service) PayoutConductorFees(ctx context.Context) (int, error) {
+	if s.ledgerService == nil {
+		return 0, fmt.Errorf("ledger service not configured")
+	}
+
+	conductors, err := s.repo.GetConductorsWithUnpaidFees(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0
+	for _, conductor := range conductors {
+		if err := s.payoutConductor(ctx, conductor); err != nil {
+			s.logger.Error("Failed to pay out conductor",
+				zap.String("conductor_id", conductor.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		paid++
+	}
+	return paid, nil
+}
+
+// payoutConductor pays out the fees crystallized for a conductor through their latest charged
+// statement. The idempotency key and payout ID are derived from that statement, so a retried pass
+// over the same fees posts and records one payout.
+func (s *Service) payoutConductor(ctx context.Context, conductor *entities.Conductor) error {
+	latest, err := s.repo.GetLatestChargedFeeStatement(ctx, conductor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest fee statement: %w", err)
+	}
+	if latest == nil {
+		return nil
+	}
+
+	balance, err := s.ledgerService.GetAccountBalance(ctx, conductor.UserID, entities.AccountTypeConductorPayable)
+	if err != nil {
+		return fmt.Errorf("failed to get payable balance: %w", err)
+	}
+	if balance.LessThan(entities.MinConductorPayout) {
+		return nil
+	}
+
+	payableAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, conductor.UserID, entities.AccountTypeConductorPayable)
+	if err != nil {
+		return fmt.Errorf("failed to get payable account: %w", err)
+	}
+	balanceAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, conductor.UserID, entities.AccountTypeUSDCBalance)
+	if err != nil {
+		return fmt.Errorf("failed to get balance account: %w", err)
+	}
+
+	idempotencyKey := fmt.Sprintf("conductor-payout:%s:%s", conductor.ID, latest.ID)
+	payout := &entities.ConductorPayout{
+		ID:          uuid.NewSHA1(conductorPayoutNamespace, []byte(idempotencyKey)),
+		ConductorID: conductor.ID,
+		Amount:      balance,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	desc := "Copy trading fee payout"
+	referenceType := "conductor_payout"
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &conductor.UserID,
+		TransactionType: entities.TransactionTypeCopyTradingFee,
+		ReferenceID:     &payout.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  idempotencyKey,
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   payableAccount.ID,
+				EntryType:   entities.EntryTypeCredit,
+				Amount:      balance,
+				Currency:    "USDC",
+				Description: &desc,
+			},
+			{
+				AccountID:   balanceAccount.ID,
+				EntryType:   entities.EntryTypeDebit,
+				Amount:      balance,
+				Currency:    "USDC",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post payout: %w", err)
+	}
+	payout.LedgerTxID = &ledgerTx.ID
+
+	if err := s.repo.CreateConductorPayout(ctx, payout); err != nil {
+		return err
+	}
+
+	s.logger.Info("Conductor paid out",
+		zap.String("conductor_id", conductor.ID.String()),
+		zap.String("amount", balance.String()))
+
+	return nil
+}
+
+// GetDraftFeeStatements returns the fee statements for a user's draft
+func (s *Service) GetDraftFeeStatements(ctx context.Context, userID, draftID uuid.UUID, limit int) ([]*entities.DraftFeeStatement, error) {
+	draft, err := s.repo.GetDraftByID(ctx, draftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if draft == nil {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if draft.DrafterID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 24
+	}
+
+	return s.repo.GetFeeStatementsByDraft(ctx, draftID, limit)
+}
+
+// GetConductorEarnings returns the fee income of the conductor profile owned by a user
+func (s *Service) GetConductorEarnings(ctx context.Context, userID uuid.UUID) (*entities.ConductorEarnings, error) {
+	conductor, err := s.repo.GetConductorByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("user is not a conductor")
+	}
+
+	earned, paidOut, err := s.repo.GetConductorFeeTotals(ctx, conductor.ID)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := s.repo.GetConductorPayouts(ctx, conductor.ID, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	payable := decimal.Zero
+	if s.ledgerService != nil {
+		payable, err = s.ledgerService.GetAccountBalance(ctx, userID, entities.AccountTypeConductorPayable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payable balance: %w", err)
+		}
+	}
+
+	return &entities.ConductorEarnings{
+		ConductorID:    conductor.ID,
+		PayableBalance: payable,
+		TotalEarned:    earned,
+		TotalPaidOut:   paidOut,
+		RecentPayouts:  payouts,
+	}, nil
+}
+
+// UpdateConductorFees changes the fee schedule of the conductor profile owned by a user.
+// New rates apply to all of the conductor's drafts from their next accrual.
+func (s *Service) UpdateConductorFees(ctx context.Context, userID uuid.UUID, req *entities.UpdateConductorFeesRequest) (*entities.Conductor, error) {
+	conductor, err := s.repo.GetConductorByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("user is not a conductor")
+	}
+
+	if req.PerformanceFeeRate != nil {
+		if req.PerformanceFeeRate.IsNegative() || req.PerformanceFeeRate.GreaterThan(entities.MaxPerformanceFeeRate) {
+			return nil, fmt.Errorf("performance fee rate must be between 0 and %s", entities.MaxPerformanceFeeRate.String())
+		}
+		conductor.FeeRate = *req.PerformanceFeeRate
+	}
+	if req.ManagementFeeRate != nil {
+		if req.ManagementFeeRate.IsNegative() || req.ManagementFeeRate.GreaterThan(entities.MaxManagementFeeRate) {
+			return nil, fmt.Errorf("management fee rate must be between 0 and %s", entities.MaxManagementFeeRate.String())
+		}
+		conductor.ManagementFee = *req.ManagementFeeRate
+	}
+
+	if err := s.repo.UpdateConductorFees(ctx, conductor.ID, conductor.FeeRate, conductor.ManagementFee); err != nil {
+		return nil, fmt.Errorf("failed to update fees: %w", err)
+	}
+
+	s.logger.Info("Conductor fees updated",
+		zap.String("conductor_id", conductor.ID.String()),
+		zap.String("fee_rate", conductor.FeeRate.String()),
+		zap.String("management_fee_rate", conductor.ManagementFee.String()))
+
+	return conductor, nil
+}
+
+// valueDraft returns the draft's uninvested cash plus its holdings at current prices
+func (s *Service) valueDraft(ctx context.Context, draft *entities.Draft) (decimal.Decimal, error) {
+	return s.valueDraftAt(ctx, draft, make(map[string]decimal.Decimal))
+}
+
+// valueDraftAt values a draft like valueDraft, reusing and filling a shared price cache
+func (s *Service) valueDraftAt(ctx context.Context, draft *entities.Draft, prices map[string]decimal.Decimal) (decimal.Decimal, error) {
+	holdings, err := s.repo.GetDraftHoldings(ctx, draft.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	value := draft.CurrentAUM
+	for ticker, quantity := range holdings {
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		value = value.Add(quantity.Mul(price))
+	}
+	return value, nil
+}
+
+// accrueManagementFee returns the draft's accrued fees plus the annual management fee
+// prorated over the time since the last accrual
+func accrueManagementFee(draft *entities.Draft, conductor *entities.Conductor, value decimal.Decimal, now time.Time) decimal.Decimal {
+	since := feePeriodStart(draft)
+	if draft.LastFeeAccrualAt != nil {
+		since = *draft.LastFeeAccrualAt
+	}
+	elapsed := now.Sub(since)
+	if elapsed <= 0 || !conductor.ManagementFee.IsPositive() || !value.IsPositive() {
+		return draft.AccruedFees
+	}
+
+	years := decimal.NewFromFloat(elapsed.Hours() / (24 * 365))
+	return draft.AccruedFees.Add(value.Mul(conductor.ManagementFee).Mul(years).Round(8))
+}
+
+// feePeriodStart returns when the draft's current fee period began
+func feePeriodStart(draft *entities.Draft) time.Time {
+	if draft.LastCrystallized != nil {
+		return *draft.LastCrystallized
+	}
+	return draft.CreatedAt
+}
@@ -0,0 +1,257 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Portfolio Mirroring ===
+
+// SyncDraftPositions places catch-up orders that move a portfolio-mode draft to its target weights:
+// the conductor's current holdings, or the allocations of the track the draft follows. Positions
+// within the drift tolerance are left alone, sells run before buys, and buys are limited to cash.
+func (s *Service) SyncDraftPositions(ctx context.Context, draft *entities.Draft) ([]*entities.SignalExecutionLog, error) {
+	if draft.MirrorMode != entities.DraftMirrorModePortfolio {
+		return nil, fmt.Errorf("draft is not in portfolio mode")
+	}
+	if draft.Status != entities.DraftStatusActive {
+		return nil, fmt.Errorf("draft is not active")
+	}
+
+	prices := make(map[string]decimal.Decimal)
+	weights, err := s.targetWeights(ctx, draft, prices)
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := s.repo.GetDraftHoldings(ctx, draft.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft holdings: %w", err)
+	}
+
+	tickers := make([]string, 0, len(weights)+len(holdings))
+	for ticker := range weights {
+		tickers = append(tickers, ticker)
+	}
+	for ticker := range holdings {
+		if _, ok := weights[ticker]; !ok {
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+
+	value := draft.CurrentAUM
+	for _, ticker := range tickers {
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			return nil, err
+		}
+		value = value.Add(holdings[ticker].Mul(price))
+	}
+	if !value.IsPositive() {
+		return nil, nil
+	}
+
+	threshold := decimal.Max(entities.MinimumTradeValue, value.Mul(entities.MirrorDriftTolerance))
+	sells := make(map[string]decimal.Decimal)
+	buys := make(map[string]decimal.Decimal)
+	for _, ticker := range tickers {
+		price := prices[ticker]
+		if !price.IsPositive() {
+			continue
+		}
+		target := value.Mul(weights[ticker]).Mul(draft.CopyRatio)
+		diff := target.Sub(holdings[ticker].Mul(price))
+		if diff.Abs().LessThan(threshold) {
+			continue
+		}
+		if diff.IsNegative() {
+			sells[ticker] = decimal.Min(diff.Abs().Div(price), holdings[ticker])
+		} else {
+			buys[ticker] = diff
+		}
+	}
+
+	cash := draft.CurrentAUM
+	var logs []*entities.SignalExecutionLog
+	for _, ticker := range tickers {
+		quantity, ok := sells[ticker]
+		if !ok {
+			continue
+		}
+		log := s.placeDraftOrder(ctx, draft, entities.ExecutionTypeCatchUp, ticker, "sell", quantity.Truncate(8), prices[ticker])
+		if log.Status == entities.ExecutionStatusSuccess {
+			cash = cash.Add(log.ExecutedValue)
+		}
+		logs = append(logs, log)
+	}
+	for _, ticker := range tickers {
+		buyValue, ok := buys[ticker]
+		if !ok {
+			continue
+		}
+		buyValue = decimal.Min(buyValue, cash)
+		if buyValue.LessThan(entities.MinimumTradeValue) {
+			continue
+		}
+		log := s.placeDraftOrder(ctx, draft, entities.ExecutionTypeCatchUp, ticker, "buy", buyValue.Div(prices[ticker]).Truncate(8), prices[ticker])
+		if log.Status == entities.ExecutionStatusSuccess {
+			cash = cash.Sub(log.ExecutedValue)
+		}
+		logs = append(logs, log)
+	}
+
+	if !cash.Equal(draft.CurrentAUM) {
+		if err := s.repo.UpdateDraftAUM(ctx, draft.ID, cash, cash.Sub(draft.StartValue)); err != nil {
+			return logs, fmt.Errorf("failed to update draft AUM: %w", err)
+		}
+		draft.CurrentAUM = cash
+	}
+
+	s.logger.Info("Draft positions synced",
+		zap.String("draft_id", draft.ID.String()),
+		zap.Int("orders", len(logs)),
+		zap.String("draft_value", value.String()))
+
+	return logs, nil
+}
+
+// SyncUserDraft runs a catch-up for one of a user's portfolio-mode drafts
+func (s *Service) SyncUserDraft(ctx context.Context, userID, draftID uuid.UUID) ([]*entities.SignalExecutionLog, error) {
+	draft, err := s.repo.GetDraftByID(ctx, draftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if draft == nil {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if draft.DrafterID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	return s.SyncDraftPositions(ctx, draft)
+}
+
+// catchUpDraft syncs a portfolio-mode draft, logging rather than returning failures
+func (s *Service) catchUpDraft(ctx context.Context, draft *entities.Draft, reason string) {
+	if draft.MirrorMode != entities.DraftMirrorModePortfolio {
+		return
+	}
+	if _, err := s.SyncDraftPositions(ctx, draft); err != nil {
+		s.logger.Error("Failed to catch up draft positions",
+			zap.String("draft_id", draft.ID.String()),
+			zap.String("reason", reason),
+			zap.Error(err))
+	}
+}
+
+// targetWeights returns the share of draft value each ticker should hold
+func (s *Service) targetWeights(ctx context.Context, draft *entities.Draft, prices map[string]decimal.Decimal) (map[string]decimal.Decimal, error) {
+	weights := make(map[string]decimal.Decimal)
+
+	if draft.TrackID != nil {
+		allocations, err := s.repo.GetTrackAllocations(ctx, *draft.TrackID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track allocations: %w", err)
+		}
+		for _, a := range allocations {
+			weights[a.AssetTicker] = a.TargetWeight.Div(decimal.NewFromInt(100))
+		}
+		return weights, nil
+	}
+
+	conductor, err := s.repo.GetConductorByID(ctx, draft.ConductorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("conductor not found")
+	}
+	holdings, err := s.repo.GetConductorHoldings(ctx, conductor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor holdings: %w", err)
+	}
+
+	// The conductor's uninvested cash is the part of their AUM not covered by holdings
+	invested := decimal.Zero
+	values := make(map[string]decimal.Decimal, len(holdings))
+	for ticker, quantity := range holdings {
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			return nil, err
+		}
+		values[ticker] = quantity.Mul(price)
+		invested = invested.Add(values[ticker])
+	}
+	total := decimal.Max(invested, conductor.SourceAUM)
+	if !total.IsPositive() {
+		return weights, nil
+	}
+	for ticker, v := range values {
+		weights[ticker] = v.Div(total)
+	}
+	return weights, nil
+}
+
+// priceOf returns a ticker's current price, fetching it once per sync
+func (s *Service) priceOf(ctx context.Context, prices map[string]decimal.Decimal, ticker string) (decimal.Decimal, error) {
+	if price, ok := prices[ticker]; ok {
+		return price, nil
+	}
+	price, err := s.tradingAdapter.GetCurrentPrice(ctx, ticker)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to price %s: %w", ticker, err)
+	}
+	prices[ticker] = price
+	return price, nil
+}
+
+// placeDraftOrder places and logs one order a draft makes outside of a signal, such as a
+// catch-up or liquidation order
+func (s *Service) placeDraftOrder(ctx context.Context, draft *entities.Draft, executionType entities.ExecutionType, ticker, side string, quantity, price decimal.Decimal) *entities.SignalExecutionLog {
+	now := time.Now().UTC()
+	log := &entities.SignalExecutionLog{
+		ID:             uuid.New(),
+		DraftID:        draft.ID,
+		ExecutionType:  executionType,
+		AssetTicker:    ticker,
+		Side:           side,
+		FeeApplied:     decimal.Zero,
+		IdempotencyKey: fmt.Sprintf("%s_%s_%s_%d", strings.ReplaceAll(string(executionType), "_", ""), draft.ID.String(), ticker, now.UnixNano()),
+		CreatedAt:      now,
+	}
+	return s.placeLoggedOrder(ctx, draft, log, quantity, price)
+}
+
+// placeLoggedOrder places a draft's order for the log's ticker and side, then records the outcome
+// on the log and stores it
+func (s *Service) placeLoggedOrder(ctx context.Context, draft *entities.Draft, log *entities.SignalExecutionLog, quantity, price decimal.Decimal) *entities.SignalExecutionLog {
+	orderID, executedPrice, err := s.tradingAdapter.PlaceOrder(ctx, draft.DrafterID, log.AssetTicker, log.Side, quantity)
+	if err != nil {
+		log.ExecutedQuantity = quantity
+		log.ExecutedPrice = price
+		log.ExecutedValue = quantity.Mul(price)
+		log.Status = entities.ExecutionStatusFailed
+		log.ErrorMessage = fmt.Sprintf("order failed: %v", err)
+	} else {
+		now := time.Now().UTC()
+		log.ExecutedQuantity = quantity
+		log.ExecutedPrice = executedPrice
+		log.ExecutedValue = quantity.Mul(executedPrice)
+		log.Status = entities.ExecutionStatusSuccess
+		log.OrderID = orderID
+		log.ExecutedAt = &now
+	}
+
+	if err := s.repo.CreateExecutionLog(ctx, log); err != nil {
+		s.logger.Error("Failed to create draft order execution log", zap.String("execution_type", string(log.ExecutionType)), zap.Error(err))
+	}
+	return log
+}
@@ -0,0 +1,284 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// conductorBook is a conductor's portfolio rebuilt by replaying their signals
+type conductorBook struct {
+	cash       decimal.Decimal
+	holdings   map[string]decimal.Decimal
+	roundTrips int
+	wins       int
+}
+
+// === Performance Operations ===
+
+// ProcessConductorPerformance recomputes every active conductor's AUM and metrics and records
+// today's snapshot. It returns the number of conductors updated.
+func (s *Service) ProcessConductorPerformance(ctx context.Context, batchSize int) (int, error) {
+	asOf := time.Now().UTC()
+	updated := 0
+
+	// Pages are keyed on the last conductor seen, so conductors gaining or losing followers
+	// mid-run are neither skipped nor processed twice by shifting offsets
+	var after *entities.Conductor
+	for {
+		conductors, err := s.repo.GetActiveConductorsAfter(ctx, after, batchSize)
+		if err != nil {
+			return updated, fmt.Errorf("failed to get conductors: %w", err)
+		}
+
+		for _, conductor := range conductors {
+			if _, err := s.ComputeConductorPerformance(ctx, conductor, asOf); err != nil {
+				s.logger.Error("Failed to compute conductor performance",
+					zap.String("conductor_id", conductor.ID.String()),
+					zap.Error(err))
+				continue
+			}
+			updated++
+		}
+
+		if len(conductors) < batchSize {
+			return updated, nil
+		}
+		after = conductors[len(conductors)-1]
+	}
+}
+
+// ComputeConductorPerformance rebuilds a conductor's portfolio from their signals, prices it and
+// records the day's snapshot. Returns are time-weighted by chaining daily snapshots; drawdown and
+// Sharpe ratio are derived from that series and win rate from closed round-trips.
+func (s *Service) ComputeConductorPerformance(ctx context.Context, conductor *entities.Conductor, asOf time.Time) (*entities.ConductorPerformanceHistory, error) {
+	signals, err := s.repo.GetConductorSignalHistory(ctx, conductor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signal history: %w", err)
+	}
+
+	// The book starts from the AUM the conductor had when they first traded
+	startingCapital := conductor.SourceAUM
+	if len(signals) > 0 && signals[0].ConductorAUMAtSignal.IsPositive() {
+		startingCapital = signals[0].ConductorAUMAtSignal
+	}
+	book := buildConductorBook(startingCapital, signals)
+
+	aum := book.cash
+	prices := make(map[string]decimal.Decimal)
+	for ticker, quantity := range book.holdings {
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			return nil, err
+		}
+		aum = aum.Add(quantity.Mul(price))
+	}
+
+	snapshotDate := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	history, err := s.repo.GetPerformanceHistory(ctx, conductor.ID, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance history: %w", err)
+	}
+
+	// A rerun on the same day replaces that day's snapshot rather than chaining onto it
+	var past []*entities.ConductorPerformanceHistory
+	for _, h := range history {
+		if h.SnapshotDate.Before(snapshotDate) {
+			past = append(past, h)
+		}
+	}
+
+	previousAUM, previousReturn := startingCapital, decimal.Zero
+	if len(past) > 0 {
+		previousAUM, previousReturn = past[len(past)-1].AUM, past[len(past)-1].CumulativeReturn
+	}
+	dailyReturn := decimal.Zero
+	if previousAUM.IsPositive() {
+		dailyReturn = aum.Div(previousAUM).Sub(decimal.NewFromInt(1))
+	}
+	cumulativeReturn := previousReturn.Add(decimal.NewFromInt(1)).Mul(dailyReturn.Add(decimal.NewFromInt(1))).Sub(decimal.NewFromInt(1))
+
+	tradesCount := 0
+	for _, signal := range signals {
+		if !signal.CreatedAt.UTC().Before(snapshotDate) && signal.CreatedAt.UTC().Before(snapshotDate.AddDate(0, 0, 1)) {
+			tradesCount++
+		}
+	}
+
+	snapshot := &entities.ConductorPerformanceHistory{
+		ID:               uuid.New(),
+		ConductorID:      conductor.ID,
+		SnapshotDate:     snapshotDate,
+		AUM:              aum.Round(8),
+		DailyReturn:      dailyReturn.Round(6),
+		CumulativeReturn: cumulativeReturn.Round(6),
+		FollowersCount:   conductor.FollowersCount,
+		TradesCount:      tradesCount,
+		CreatedAt:        asOf,
+	}
+	if err := s.repo.UpsertPerformanceSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	series := append(past, snapshot)
+	conductor.SourceAUM = snapshot.AUM
+	conductor.TotalReturn = snapshot.CumulativeReturn
+	conductor.MaxDrawdown = maxDrawdown(series)
+	conductor.SharpeRatio = sharpeRatio(series)
+	conductor.TotalTrades = len(signals)
+	conductor.WinRate = decimal.Zero
+	if book.roundTrips > 0 {
+		conductor.WinRate = decimal.NewFromInt(int64(book.wins)).Div(decimal.NewFromInt(int64(book.roundTrips))).Round(4)
+	}
+	if err := s.repo.UpdateConductorPerformance(ctx, conductor); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Conductor performance computed",
+		zap.String("conductor_id", conductor.ID.String()),
+		zap.String("aum", conductor.SourceAUM.String()),
+		zap.String("total_return", conductor.TotalReturn.String()),
+		zap.String("max_drawdown", conductor.MaxDrawdown.String()),
+		zap.String("sharpe_ratio", conductor.SharpeRatio.String()),
+		zap.Int("round_trips", book.roundTrips))
+
+	return snapshot, nil
+}
+
+// GetConductorPerformance returns a conductor's performance chart for a period (1m, 3m, 6m, 1y or all)
+func (s *Service) GetConductorPerformance(ctx context.Context, conductorID uuid.UUID, period string) (*entities.ConductorPerformanceChart, error) {
+	lookback, ok := entities.ConductorPerformancePeriods[period]
+	if !ok {
+		return nil, fmt.Errorf("invalid period")
+	}
+
+	conductor, err := s.repo.GetConductorByID(ctx, conductorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conductor: %w", err)
+	}
+	if conductor == nil {
+		return nil, fmt.Errorf("conductor not found")
+	}
+
+	var since time.Time
+	if lookback > 0 {
+		since = time.Now().UTC().Add(-lookback)
+	}
+	points, err := s.repo.GetPerformanceHistory(ctx, conductorID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance history: %w", err)
+	}
+	if points == nil {
+		points = []*entities.ConductorPerformanceHistory{}
+	}
+
+	return &entities.ConductorPerformanceChart{
+		ConductorID: conductor.ID,
+		Period:      period,
+		TotalReturn: conductor.TotalReturn,
+		MaxDrawdown: conductor.MaxDrawdown,
+		SharpeRatio: conductor.SharpeRatio,
+		WinRate:     conductor.WinRate,
+		Points:      points,
+	}, nil
+}
+
+// buildConductorBook replays signals against a starting capital. Each ticker's position is held at
+// average cost; a round-trip closes when the position returns to zero and wins if the sells
+// realized more than the shares cost. Shares sold beyond what the signals bought were held before
+// the conductor's first signal, so their proceeds count as cash but not towards any round-trip.
+func buildConductorBook(startingCapital decimal.Decimal, signals []*entities.Signal) *conductorBook {
+	book := &conductorBook{
+		cash:     startingCapital,
+		holdings: make(map[string]decimal.Decimal),
+	}
+	costBasis := make(map[string]decimal.Decimal)
+	realized := make(map[string]decimal.Decimal)
+
+	for _, signal := range signals {
+		ticker := signal.AssetTicker
+		quantity := signal.BaseQuantity
+		value := quantity.Mul(signal.BasePrice)
+
+		if signal.Side == "buy" {
+			book.cash = book.cash.Sub(value)
+			book.holdings[ticker] = book.holdings[ticker].Add(quantity)
+			costBasis[ticker] = costBasis[ticker].Add(value)
+			continue
+		}
+
+		book.cash = book.cash.Add(value)
+		held := book.holdings[ticker]
+		if !held.IsPositive() {
+			continue
+		}
+		closed := decimal.Min(quantity, held)
+		cost := costBasis[ticker].Mul(closed).Div(held)
+		realized[ticker] = realized[ticker].Add(closed.Mul(signal.BasePrice).Sub(cost))
+		costBasis[ticker] = costBasis[ticker].Sub(cost)
+		book.holdings[ticker] = held.Sub(closed)
+
+		if book.holdings[ticker].IsZero() {
+			book.roundTrips++
+			if realized[ticker].IsPositive() {
+				book.wins++
+			}
+			delete(book.holdings, ticker)
+			delete(costBasis, ticker)
+			delete(realized, ticker)
+		}
+	}
+	return book
+}
+
+// maxDrawdown returns the largest peak-to-trough fall in cumulative return, as a positive fraction
+func maxDrawdown(series []*entities.ConductorPerformanceHistory) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	peak := one
+	drawdown := decimal.Zero
+	for _, point := range series {
+		wealth := one.Add(point.CumulativeReturn)
+		if wealth.GreaterThan(peak) {
+			peak = wealth
+			continue
+		}
+		if peak.IsPositive() {
+			drawdown = decimal.Max(drawdown, peak.Sub(wealth).Div(peak))
+		}
+	}
+	return drawdown.Round(6)
+}
+
+// sharpeRatio annualizes the mean over the sample standard deviation of daily returns, taking the
+// risk-free rate as zero. It is zero until there are at least two returns with any variation.
+func sharpeRatio(series []*entities.ConductorPerformanceHistory) decimal.Decimal {
+	if len(series) < 2 {
+		return decimal.Zero
+	}
+
+	n := float64(len(series))
+	mean := 0.0
+	for _, point := range series {
+		mean += point.DailyReturn.InexactFloat64()
+	}
+	mean /= n
+
+	variance := 0.0
+	for _, point := range series {
+		d := point.DailyReturn.InexactFloat64() - mean
+		variance += d * d
+	}
+	stddev := math.Sqrt(variance / (n - 1))
+	if stddev == 0 {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromFloat(mean / stddev * math.Sqrt(entities.TradingDaysPerYear)).Round(6)
+}
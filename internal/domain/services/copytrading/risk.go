@@ -0,0 +1,326 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// Notifier tells drafters when their drafts are paused or closed automatically
+type Notifier interface {
+	SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error
+}
+
+// SetNotifier sets the notifier for automatic draft actions (optional)
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// === Risk Operations ===
+
+// UpdateDraftRisk replaces the risk limits on one of a user's drafts. Enabling a trailing stop
+// starts it from the draft's current value.
+func (s *Service) UpdateDraftRisk(ctx context.Context, userID, draftID uuid.UUID, req *entities.UpdateDraftRiskRequest) (*entities.Draft, error) {
+	draft, err := s.repo.GetDraftByID(ctx, draftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if draft == nil {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if draft.DrafterID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if draft.Status == entities.DraftStatusUnlinked || draft.Status == entities.DraftStatusUnlinking {
+		return nil, fmt.Errorf("draft already unlinked")
+	}
+
+	for name, limit := range map[string]*decimal.Decimal{
+		"max drawdown":      req.MaxDrawdown,
+		"trailing stop":     req.TrailingStop,
+		"max concentration": req.MaxConcentration,
+	} {
+		if limit != nil && (!limit.IsPositive() || limit.GreaterThan(decimal.NewFromInt(1))) {
+			return nil, fmt.Errorf("invalid risk settings: %s must be between 0 and 1", name)
+		}
+	}
+	if req.MaxTradeValue != nil && req.MaxTradeValue.LessThan(entities.MinimumTradeValue) {
+		return nil, fmt.Errorf("invalid risk settings: max trade value must be at least %s", entities.MinimumTradeValue.String())
+	}
+
+	action := req.BreachAction
+	if action == "" {
+		action = entities.DraftRiskActionPause
+	}
+	if action != entities.DraftRiskActionPause && action != entities.DraftRiskActionUnlink {
+		return nil, fmt.Errorf("invalid risk settings: breach action must be pause or unlink")
+	}
+
+	settings := entities.DraftRiskSettings{
+		MaxDrawdown:      req.MaxDrawdown,
+		TrailingStop:     req.TrailingStop,
+		MaxConcentration: req.MaxConcentration,
+		MaxTradeValue:    req.MaxTradeValue,
+		BreachAction:     action,
+	}
+	if err := s.repo.UpdateDraftRiskSettings(ctx, draftID, settings); err != nil {
+		return nil, err
+	}
+
+	if settings.TrailingStop != nil && draft.TrailingStop == nil {
+		value, err := s.valueDraft(ctx, draft)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value draft: %w", err)
+		}
+		if err := s.repo.UpdateDraftRiskState(ctx, draftID, value, draft.RiskBreachReason); err != nil {
+			return nil, fmt.Errorf("failed to reset peak value: %w", err)
+		}
+	}
+
+	s.logger.Info("Draft risk settings updated",
+		zap.String("draft_id", draftID.String()),
+		zap.String("breach_action", string(action)))
+
+	return s.repo.GetDraftByID(ctx, draftID)
+}
+
+// ProcessDraftRisk checks every active draft with a max drawdown or trailing stop against current
+// prices. It returns the number of drafts that breached a limit.
+func (s *Service) ProcessDraftRisk(ctx context.Context, batchSize int) (int, error) {
+	prices := make(map[string]decimal.Decimal)
+	breached := 0
+	afterID := uuid.Nil
+
+	for {
+		drafts, err := s.repo.GetDraftsWithStopLimits(ctx, afterID, batchSize)
+		if err != nil {
+			return breached, fmt.Errorf("failed to get drafts: %w", err)
+		}
+
+		for _, draft := range drafts {
+			hit, err := s.enforceDraftRisk(ctx, draft, prices)
+			if err != nil {
+				s.logger.Error("Failed to check draft risk",
+					zap.String("draft_id", draft.ID.String()),
+					zap.Error(err))
+				continue
+			}
+			if hit {
+				breached++
+			}
+		}
+
+		if len(drafts) < batchSize {
+			return breached, nil
+		}
+		afterID = drafts[len(drafts)-1].ID
+	}
+}
+
+// EnforceDraftRisk values an active draft, tracks its peak and applies its breach action if it
+// has fallen past its max drawdown or trailing stop. It reports whether a limit was breached.
+func (s *Service) EnforceDraftRisk(ctx context.Context, draft *entities.Draft) (bool, error) {
+	return s.enforceDraftRisk(ctx, draft, make(map[string]decimal.Decimal))
+}
+
+func (s *Service) enforceDraftRisk(ctx context.Context, draft *entities.Draft, prices map[string]decimal.Decimal) (bool, error) {
+	if draft.Status != entities.DraftStatusActive || !draft.HasStopLimits() {
+		return false, nil
+	}
+
+	value, err := s.valueDraftAt(ctx, draft, prices)
+	if err != nil {
+		return false, fmt.Errorf("failed to value draft: %w", err)
+	}
+	peak := decimal.Max(draft.PeakValue, value)
+
+	reason := riskBreachReason(draft, value, peak)
+	if reason == "" {
+		if peak.GreaterThan(draft.PeakValue) {
+			if err := s.repo.UpdateDraftRiskState(ctx, draft.ID, peak, draft.RiskBreachReason); err != nil {
+				return false, fmt.Errorf("failed to update peak value: %w", err)
+			}
+			draft.PeakValue = peak
+		}
+		return false, nil
+	}
+
+	if err := s.repo.UpdateDraftRiskState(ctx, draft.ID, peak, reason); err != nil {
+		return false, fmt.Errorf("failed to record risk breach: %w", err)
+	}
+	draft.PeakValue = peak
+	draft.RiskBreachReason = reason
+
+	s.logger.Warn("Draft risk limit breached",
+		zap.String("draft_id", draft.ID.String()),
+		zap.String("value", value.String()),
+		zap.String("action", string(draft.BreachAction)),
+		zap.String("reason", reason))
+
+	return true, s.applyBreachAction(ctx, draft, reason, prices)
+}
+
+// applyBreachAction pauses or liquidates and unlinks a draft that breached a limit, then tells the
+// drafter why. A draft whose holdings cannot all be sold is paused instead of unlinked.
+func (s *Service) applyBreachAction(ctx context.Context, draft *entities.Draft, reason string, prices map[string]decimal.Decimal) error {
+	conductorName := "your conductor"
+	if conductor, err := s.repo.GetConductorByID(ctx, draft.ConductorID); err == nil && conductor != nil {
+		conductorName = conductor.DisplayName
+	}
+
+	if draft.BreachAction == entities.DraftRiskActionUnlink {
+		if s.liquidateDraft(ctx, draft, prices) {
+			if err := s.UnlinkDraft(ctx, draft.DrafterID, draft.ID); err != nil {
+				return fmt.Errorf("failed to unlink draft: %w", err)
+			}
+			s.notifyDrafter(ctx, draft.DrafterID, "Copy trading draft closed",
+				fmt.Sprintf("We stopped copying %s because %s. Its holdings were sold and the proceeds returned to your balance.",
+					conductorName, reason))
+			return nil
+		}
+		reason += "; some holdings could not be sold, so the draft was paused instead"
+		if err := s.repo.UpdateDraftRiskState(ctx, draft.ID, draft.PeakValue, reason); err != nil {
+			s.logger.Error("Failed to record risk breach", zap.Error(err))
+		}
+	}
+
+	if err := s.repo.UpdateDraftStatus(ctx, draft.ID, entities.DraftStatusPaused); err != nil {
+		return fmt.Errorf("failed to pause draft: %w", err)
+	}
+	draft.Status = entities.DraftStatusPaused
+	s.notifyDrafter(ctx, draft.DrafterID, "Copy trading draft paused",
+		fmt.Sprintf("We paused copying %s because %s. Resume the draft or adjust its limits to continue.",
+			conductorName, reason))
+	return nil
+}
+
+// liquidateDraft sells every holding of a draft back to cash. It reports whether all sells filled.
+func (s *Service) liquidateDraft(ctx context.Context, draft *entities.Draft, prices map[string]decimal.Decimal) bool {
+	holdings, err := s.repo.GetDraftHoldings(ctx, draft.ID)
+	if err != nil {
+		s.logger.Error("Failed to get draft holdings for liquidation", zap.Error(err))
+		return false
+	}
+
+	tickers := make([]string, 0, len(holdings))
+	for ticker := range holdings {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	cash := draft.CurrentAUM
+	complete := true
+	for _, ticker := range tickers {
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			s.logger.Error("Failed to price holding for liquidation", zap.String("ticker", ticker), zap.Error(err))
+			complete = false
+			continue
+		}
+		log := s.placeDraftOrder(ctx, draft, entities.ExecutionTypeLiquidation, ticker, "sell", holdings[ticker], price)
+		if log.Status != entities.ExecutionStatusSuccess {
+			complete = false
+			continue
+		}
+		cash = cash.Add(log.ExecutedValue)
+	}
+
+	if !cash.Equal(draft.CurrentAUM) {
+		if err := s.repo.UpdateDraftAUM(ctx, draft.ID, cash, cash.Sub(draft.StartValue)); err != nil {
+			s.logger.Error("Failed to update draft AUM after liquidation", zap.Error(err))
+			return false
+		}
+		draft.CurrentAUM = cash
+	}
+	return complete
+}
+
+// applyTradeLimits scales a copied buy down to the draft's max trade value and the room left under
+// its concentration cap. Sells are never limited since they only reduce risk.
+func (s *Service) applyTradeLimits(ctx context.Context, draft *entities.Draft, signal *entities.Signal, quantity, price decimal.Decimal) (decimal.Decimal, entities.ExecutionStatus, string) {
+	if signal.Side != "buy" || !price.IsPositive() {
+		return quantity, entities.ExecutionStatusSuccess, ""
+	}
+
+	if draft.MaxTradeValue != nil && quantity.Mul(price).GreaterThan(*draft.MaxTradeValue) {
+		quantity = draft.MaxTradeValue.Div(price)
+	}
+
+	if draft.MaxConcentration != nil {
+		holdings, err := s.repo.GetDraftHoldings(ctx, draft.ID)
+		if err != nil {
+			return quantity, entities.ExecutionStatusFailed, fmt.Sprintf("failed to check concentration: %v", err)
+		}
+		prices := map[string]decimal.Decimal{signal.AssetTicker: price}
+		value := draft.CurrentAUM
+		for ticker, held := range holdings {
+			p, err := s.priceOf(ctx, prices, ticker)
+			if err != nil {
+				return quantity, entities.ExecutionStatusFailed, fmt.Sprintf("failed to check concentration: %v", err)
+			}
+			value = value.Add(held.Mul(p))
+		}
+
+		room := value.Mul(*draft.MaxConcentration).Sub(holdings[signal.AssetTicker].Mul(price))
+		if room.LessThan(entities.MinimumTradeValue) {
+			return decimal.Zero, entities.ExecutionStatusSkippedRiskLimit, "asset concentration limit reached"
+		}
+		if quantity.Mul(price).GreaterThan(room) {
+			quantity = room.Div(price)
+		}
+	}
+
+	if quantity.Mul(price).LessThan(entities.MinimumTradeValue) {
+		return quantity, entities.ExecutionStatusSkippedRiskLimit, "max trade value below minimum trade"
+	}
+	return quantity, entities.ExecutionStatusSuccess, ""
+}
+
+// sizeDraftTrade sizes a draft's copy of a signal and applies the draft's trade limits
+func (s *Service) sizeDraftTrade(ctx context.Context, draft *entities.Draft, signal *entities.Signal, price decimal.Decimal) (decimal.Decimal, entities.ExecutionStatus, string) {
+	quantity, status, reason := sizeCopyTrade(draft, signal, price)
+	if status != entities.ExecutionStatusSuccess {
+		return quantity, status, reason
+	}
+	return s.applyTradeLimits(ctx, draft, signal, quantity, price)
+}
+
+// notifyDrafter sends a notification, logging rather than returning failures
+func (s *Service) notifyDrafter(ctx context.Context, userID uuid.UUID, title, message string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendGenericNotification(ctx, userID, title, message); err != nil {
+		s.logger.Warn("Failed to notify drafter", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
+// riskBreachReason explains which stop limit a draft's value has crossed, or returns "" if none
+func riskBreachReason(draft *entities.Draft, value, peak decimal.Decimal) string {
+	if draft.MaxDrawdown != nil && draft.StartValue.IsPositive() {
+		drawdown := draft.StartValue.Sub(value).Div(draft.StartValue)
+		if drawdown.GreaterThanOrEqual(*draft.MaxDrawdown) {
+			return fmt.Sprintf("its value fell %s%% below its starting value, past your %s%% max drawdown",
+				percent(drawdown), percent(*draft.MaxDrawdown))
+		}
+	}
+	if draft.TrailingStop != nil && peak.IsPositive() {
+		fall := peak.Sub(value).Div(peak)
+		if fall.GreaterThanOrEqual(*draft.TrailingStop) {
+			return fmt.Sprintf("its value fell %s%% from its peak of $%s, past your %s%% trailing stop",
+				percent(fall), peak.StringFixed(2), percent(*draft.TrailingStop))
+		}
+	}
+	return ""
+}
+
+// percent formats a fraction as a percentage with up to two decimals
+func percent(fraction decimal.Decimal) string {
+	return fraction.Mul(decimal.NewFromInt(100)).Round(2).String()
+}
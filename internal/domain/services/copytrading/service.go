@@ -15,6 +15,7 @@ import (
 type Repository interface {
 	// Conductor operations
 	GetActiveConductors(ctx context.Context, limit, offset int, sortBy string) ([]*entities.Conductor, int, error)
+	GetActiveConductorsAfter(ctx context.Context, after *entities.Conductor, limit int) ([]*entities.Conductor, error)
 	GetConductorByID(ctx context.Context, id uuid.UUID) (*entities.Conductor, error)
 	GetConductorByUserID(ctx context.Context, userID uuid.UUID) (*entities.Conductor, error)
 	CreateConductor(ctx context.Context, conductor *entities.Conductor) error
@@ -39,7 +40,9 @@ type Repository interface {
 	// Track allocation operations
 	CreateTrackAllocations(ctx context.Context, allocations []entities.TrackAllocation) error
 	GetTrackAllocations(ctx context.Context, trackID uuid.UUID) ([]entities.TrackAllocation, error)
-	DeleteTrackAllocations(ctx context.Context, trackID uuid.UUID) error
+	GetTrackAllocationsByVersion(ctx context.Context, trackID uuid.UUID, version int) ([]entities.TrackAllocation, error)
+	CreateTrackVersion(ctx context.Context, version *entities.TrackVersion) error
+	GetTrackVersions(ctx context.Context, trackID uuid.UUID, limit int) ([]*entities.TrackVersion, error)
 
 	// Draft operations
 	CreateDraft(ctx context.Context, draft *entities.Draft) error
@@ -57,11 +60,52 @@ type Repository interface {
 	GetPendingSignals(ctx context.Context, limit int) ([]*entities.Signal, error)
 	UpdateSignalStatus(ctx context.Context, signalID uuid.UUID, status entities.SignalStatus, processedCount, failedCount int) error
 	GetSignalsByConductor(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.Signal, error)
+	GetConductorHoldings(ctx context.Context, conductorID uuid.UUID) (map[string]decimal.Decimal, error)
 
 	// Execution log operations
 	CreateExecutionLog(ctx context.Context, log *entities.SignalExecutionLog) error
 	GetExecutionLogByIdempotencyKey(ctx context.Context, key string) (*entities.SignalExecutionLog, error)
 	GetExecutionLogsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.SignalExecutionLog, error)
+
+	// Fee operations
+	GetDraftsForFeeProcessing(ctx context.Context, limit int) ([]*entities.Draft, error)
+	GetDraftHoldings(ctx context.Context, draftID uuid.UUID) (map[string]decimal.Decimal, error)
+	UpdateDraftFeeAccrual(ctx context.Context, draftID uuid.UUID, accruedFees decimal.Decimal, accruedAt time.Time) error
+	RecordFeeCrystallization(ctx context.Context, statement *entities.DraftFeeStatement) error
+	GetFeeStatementsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.DraftFeeStatement, error)
+	UpdateConductorFees(ctx context.Context, conductorID uuid.UUID, feeRate, managementFeeRate decimal.Decimal) error
+	GetConductorsWithUnpaidFees(ctx context.Context) ([]*entities.Conductor, error)
+	GetLatestChargedFeeStatement(ctx context.Context, conductorID uuid.UUID) (*entities.DraftFeeStatement, error)
+	CreateConductorPayout(ctx context.Context, payout *entities.ConductorPayout) error
+	GetConductorPayouts(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorPayout, error)
+	GetConductorFeeTotals(ctx context.Context, conductorID uuid.UUID) (earned, paidOut decimal.Decimal, err error)
+
+	// Performance operations
+	GetConductorSignalHistory(ctx context.Context, conductorID uuid.UUID) ([]*entities.Signal, error)
+	UpsertPerformanceSnapshot(ctx context.Context, snapshot *entities.ConductorPerformanceHistory) error
+	GetPerformanceHistory(ctx context.Context, conductorID uuid.UUID, since time.Time) ([]*entities.ConductorPerformanceHistory, error)
+	UpdateConductorPerformance(ctx context.Context, conductor *entities.Conductor) error
+
+	// Block order operations
+	CreateBlockOrder(ctx context.Context, order *entities.BlockOrder) error
+	UpdateBlockOrder(ctx context.Context, order *entities.BlockOrder) error
+
+	// Risk operations
+	UpdateDraftRiskSettings(ctx context.Context, draftID uuid.UUID, settings entities.DraftRiskSettings) error
+	UpdateDraftRiskState(ctx context.Context, draftID uuid.UUID, peakValue decimal.Decimal, breachReason string) error
+	GetDraftsWithStopLimits(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Draft, error)
+
+	// Compliance operations
+	CreateComplianceEvent(ctx context.Context, event *entities.ConductorComplianceEvent) error
+	GetComplianceEventsByConductor(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorComplianceEvent, error)
+	GetComplianceEventCounts(ctx context.Context, since time.Time) ([]*entities.ComplianceEventCount, error)
+	CountConductorTradeSignalsSince(ctx context.Context, conductorID uuid.UUID, since time.Time) (int, error)
+	GetFollowerBuysSince(ctx context.Context, conductorID uuid.UUID, ticker string, since time.Time) (decimal.Decimal, int, error)
+	UpdateConductorStatus(ctx context.Context, conductorID uuid.UUID, status entities.ConductorStatus) error
+	CreateBlackoutWindow(ctx context.Context, window *entities.SignalBlackoutWindow) error
+	GetBlackoutWindows(ctx context.Context, endingAfter time.Time) ([]*entities.SignalBlackoutWindow, error)
+	GetActiveBlackoutWindows(ctx context.Context, conductorID uuid.UUID, ticker string, at time.Time) ([]*entities.SignalBlackoutWindow, error)
+	DeleteBlackoutWindow(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
 // UserProvider checks if a user exists
@@ -84,11 +128,18 @@ type TradingAdapter interface {
 
 // Service handles copy trading business logic
 type Service struct {
-	repo            Repository
-	userProvider    UserProvider
-	balanceProvider BalanceProvider
-	tradingAdapter  TradingAdapter
-	logger          *zap.Logger
+	repo             Repository
+	userProvider     UserProvider
+	balanceProvider  BalanceProvider
+	tradingAdapter   TradingAdapter
+	blockAdapter     BlockTradingAdapter
+	notifier         Notifier
+	assetEligibility AssetEligibilityProvider
+	suitability      SuitabilityChecker
+	ledgerService    LedgerService
+	logger           *zap.Logger
+
+	crystallizationPeriod time.Duration
 }
 
 // NewService creates a new copy trading service
@@ -98,6 +149,8 @@ func NewService(repo Repository, balanceProvider BalanceProvider, tradingAdapter
 		balanceProvider: balanceProvider,
 		tradingAdapter: tradingAdapter,
 		logger:         logger,
+
+		crystallizationPeriod: entities.DefaultFeeCrystallizationPeriod,
 	}
 }
 
@@ -197,6 +250,35 @@ func (s *Service) CreateDraft(ctx context.Context, drafterID uuid.UUID, req *ent
 		return nil, fmt.Errorf("already following this conductor")
 	}
 
+	// Validate mirroring options
+	mirrorMode := req.MirrorMode
+	if mirrorMode == "" {
+		mirrorMode = entities.DraftMirrorModeSignals
+	}
+	if mirrorMode != entities.DraftMirrorModeSignals && mirrorMode != entities.DraftMirrorModePortfolio {
+		return nil, fmt.Errorf("invalid mirror mode")
+	}
+	var track *entities.Track
+	if req.TrackID != nil {
+		if mirrorMode != entities.DraftMirrorModePortfolio {
+			return nil, fmt.Errorf("track mirroring requires portfolio mode")
+		}
+		track, err = s.repo.GetTrackByID(ctx, *req.TrackID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+		if track == nil || track.ConductorID != conductor.ID || !track.IsActive {
+			return nil, fmt.Errorf("track not found")
+		}
+	}
+
+	// Conductors riskier than the drafter's risk band need an explicit acknowledgment
+	if s.suitability != nil {
+		if err := s.suitability.CheckSuitability(ctx, drafterID, entities.RiskProductCopyTrading, &conductor.ID, draftRiskBand(conductor, track), req.AcknowledgeRisk); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check user has sufficient balance
 	balance, err := s.balanceProvider.GetAvailableBalance(ctx, drafterID)
 	if err != nil {
@@ -229,10 +311,17 @@ func (s *Service) CreateDraft(ctx context.Context, drafterID uuid.UUID, req *ent
 		StartValue:       req.AllocatedCapital,
 		TotalProfitLoss:  decimal.Zero,
 		TotalFeesPaid:    decimal.Zero,
+		HighWaterMark:    req.AllocatedCapital,
+		PeakValue:        req.AllocatedCapital,
+		AccruedFees:      decimal.Zero,
 		CopyRatio:        copyRatio,
 		AutoAdjust:       req.AutoAdjust,
+		MirrorMode:       mirrorMode,
+		TrackID:          req.TrackID,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+
+		DraftRiskSettings: entities.DraftRiskSettings{BreachAction: entities.DraftRiskActionPause},
 	}
 
 	if err := s.repo.CreateDraft(ctx, draft); err != nil {
@@ -252,6 +341,9 @@ func (s *Service) CreateDraft(ctx context.Context, drafterID uuid.UUID, req *ent
 		zap.String("conductor_id", req.ConductorID.String()),
 		zap.String("allocated_capital", req.AllocatedCapital.String()))
 
+	// New portfolio-mode drafts buy into the conductor's current positions straight away
+	s.catchUpDraft(ctx, draft, "draft_created")
+
 	return draft, nil
 }
 
@@ -351,6 +443,17 @@ func (s *Service) ResumeDraft(ctx context.Context, userID, draftID uuid.UUID) er
 		return fmt.Errorf("draft is not paused")
 	}
 
+	// A draft paused by a risk limit restarts its trailing stop from today's value
+	if draft.RiskBreachReason != "" {
+		value, err := s.valueDraft(ctx, draft)
+		if err != nil {
+			return fmt.Errorf("failed to value draft: %w", err)
+		}
+		if err := s.repo.UpdateDraftRiskState(ctx, draftID, value, ""); err != nil {
+			return fmt.Errorf("failed to reset risk state: %w", err)
+		}
+	}
+
 	if err := s.repo.UpdateDraftStatus(ctx, draftID, entities.DraftStatusActive); err != nil {
 		return fmt.Errorf("failed to resume draft: %w", err)
 	}
@@ -375,14 +478,24 @@ func (s *Service) UnlinkDraft(ctx context.Context, userID, draftID uuid.UUID) er
 		return fmt.Errorf("draft already unlinked")
 	}
 
+	// Settle fees owed up to now before the draft's cash is returned
+	returnAmount := draft.CurrentAUM
+	if s.ledgerService != nil {
+		statement, err := s.CrystallizeDraftFees(ctx, draft)
+		if err != nil {
+			return fmt.Errorf("failed to settle fees: %w", err)
+		}
+		returnAmount = returnAmount.Sub(statement.AmountCharged)
+	}
+
 	// Mark as unlinking
 	if err := s.repo.UpdateDraftStatus(ctx, draftID, entities.DraftStatusUnlinking); err != nil {
 		return fmt.Errorf("failed to update draft status: %w", err)
 	}
 
 	// Return current AUM to user's balance
-	if draft.CurrentAUM.GreaterThan(decimal.Zero) {
-		err = s.balanceProvider.AddBalance(ctx, userID, draft.CurrentAUM, "Copy trading unlink - funds returned")
+	if returnAmount.GreaterThan(decimal.Zero) {
+		err = s.balanceProvider.AddBalance(ctx, userID, returnAmount, "Copy trading unlink - funds returned")
 		if err != nil {
 			s.logger.Error("Failed to return funds on unlink", zap.Error(err))
 		}
@@ -400,7 +513,7 @@ func (s *Service) UnlinkDraft(ctx context.Context, userID, draftID uuid.UUID) er
 
 	s.logger.Info("Draft unlinked",
 		zap.String("draft_id", draftID.String()),
-		zap.String("returned_amount", draft.CurrentAUM.String()))
+		zap.String("returned_amount", returnAmount.String()))
 
 	return nil
 }
@@ -445,7 +558,10 @@ func (s *Service) ResizeDraft(ctx context.Context, userID, draftID uuid.UUID, ne
 			return fmt.Errorf("failed to deduct balance: %w", err)
 		}
 	} else if diff.LessThan(decimal.Zero) {
-		// Reducing capital - return funds
+		// Reducing capital - return funds, which must come from the draft's uninvested cash
+		if diff.Abs().GreaterThan(draft.CurrentAUM) {
+			return fmt.Errorf("insufficient uninvested cash in draft")
+		}
 		if err := s.balanceProvider.AddBalance(ctx, userID, diff.Abs(), "Copy trading allocation decrease"); err != nil {
 			return fmt.Errorf("failed to add balance: %w", err)
 		}
@@ -460,6 +576,12 @@ func (s *Service) ResizeDraft(ctx context.Context, userID, draftID uuid.UUID, ne
 		zap.String("old_capital", draft.AllocatedCapital.String()),
 		zap.String("new_capital", newCapital.String()))
 
+	if draft.MirrorMode == entities.DraftMirrorModePortfolio {
+		if resized, err := s.repo.GetDraftByID(ctx, draftID); err == nil && resized != nil {
+			s.catchUpDraft(ctx, resized, "draft_resized")
+		}
+	}
+
 	return nil
 }
 
@@ -498,11 +620,31 @@ func (s *Service) ProcessSignal(ctx context.Context, signal *entities.Signal) er
 		return fmt.Errorf("failed to get active drafts: %w", err)
 	}
 
+	// Rebalance signals only move the drafts following the rebalanced track
+	rebalance := signal.SignalType == entities.SignalTypeRebalance
+	if rebalance {
+		drafts = draftsFollowingTrack(drafts, signal.TrackID)
+	}
+
+	// Signals followed by several drafts go to the brokerage as one block order
+	var blockResults map[uuid.UUID]error
+	if !rebalance && s.blockAdapter != nil && len(drafts) >= entities.MinBlockOrderDrafts {
+		blockResults = s.executeBlockOrder(ctx, signal, drafts)
+	}
+
 	processedCount := 0
 	failedCount := 0
 
 	for _, draft := range drafts {
-		err := s.executeCopyTrade(ctx, draft, signal)
+		var err error
+		switch {
+		case blockResults != nil:
+			err = blockResults[draft.ID]
+		case rebalance:
+			err = s.executeTrackRebalance(ctx, draft, signal)
+		default:
+			err = s.executeCopyTrade(ctx, draft, signal)
+		}
 		if err != nil {
 			s.logger.Error("Failed to execute copy trade",
 				zap.String("draft_id", draft.ID.String()),
@@ -512,6 +654,24 @@ func (s *Service) ProcessSignal(ctx context.Context, signal *entities.Signal) er
 		} else {
 			processedCount++
 		}
+
+		// Portfolio-mode drafts that missed the signal catch up to the conductor's weights instead
+		if draft.MirrorMode == entities.DraftMirrorModePortfolio && s.signalMissed(ctx, draft, signal, err) {
+			if refreshed, getErr := s.repo.GetDraftByID(ctx, draft.ID); getErr == nil && refreshed != nil {
+				s.catchUpDraft(ctx, refreshed, "signal_missed")
+			}
+		}
+
+		// Drafts with stop limits are checked against their new value after every signal
+		if draft.HasStopLimits() {
+			if refreshed, getErr := s.repo.GetDraftByID(ctx, draft.ID); getErr == nil && refreshed != nil {
+				if _, riskErr := s.EnforceDraftRisk(ctx, refreshed); riskErr != nil {
+					s.logger.Error("Failed to check draft risk",
+						zap.String("draft_id", draft.ID.String()),
+						zap.Error(riskErr))
+				}
+			}
+		}
 	}
 
 	// Update signal status
@@ -532,6 +692,29 @@ func (s *Service) ProcessSignal(ctx context.Context, signal *entities.Signal) er
 	return nil
 }
 
+// signalMissed reports whether a draft's execution of a signal did not fully go through
+func (s *Service) signalMissed(ctx context.Context, draft *entities.Draft, signal *entities.Signal, execErr error) bool {
+	if execErr != nil {
+		return true
+	}
+	log, err := s.repo.GetExecutionLogByIdempotencyKey(ctx, fmt.Sprintf("copy_%s_%s", draft.ID.String(), signal.ID.String()))
+	if err != nil || log == nil {
+		return false
+	}
+	return log.Status != entities.ExecutionStatusSuccess
+}
+
+// draftsFollowingTrack returns the drafts that mirror the given track
+func draftsFollowingTrack(drafts []*entities.Draft, trackID *uuid.UUID) []*entities.Draft {
+	var following []*entities.Draft
+	for _, draft := range drafts {
+		if trackID != nil && draft.TrackID != nil && *draft.TrackID == *trackID {
+			following = append(following, draft)
+		}
+	}
+	return following
+}
+
 // executeCopyTrade executes a single copy trade for a drafter
 func (s *Service) executeCopyTrade(ctx context.Context, draft *entities.Draft, signal *entities.Signal) error {
 	// Generate idempotency key
@@ -547,15 +730,10 @@ func (s *Service) executeCopyTrade(ctx context.Context, draft *entities.Draft, s
 		return nil
 	}
 
-	// Calculate proportional quantity
-	// Drafter Quantity = (Drafter's Allocated Capital / Conductor's Source AUM) × Conductor's Base Quantity × Copy Ratio
 	if signal.ConductorAUMAtSignal.IsZero() {
 		return fmt.Errorf("conductor AUM is zero")
 	}
 
-	ratio := draft.AllocatedCapital.Div(signal.ConductorAUMAtSignal)
-	drafterQuantity := signal.BaseQuantity.Mul(ratio).Mul(draft.CopyRatio)
-
 	// Get current price
 	currentPrice, err := s.tradingAdapter.GetCurrentPrice(ctx, signal.AssetTicker)
 	if err != nil {
@@ -563,24 +741,10 @@ func (s *Service) executeCopyTrade(ctx context.Context, draft *entities.Draft, s
 			entities.ExecutionStatusFailed, fmt.Sprintf("failed to get price: %v", err))
 	}
 
-	// Calculate trade value
-	tradeValue := drafterQuantity.Mul(currentPrice)
-
-	// Check minimum trade value
-	if tradeValue.LessThan(entities.MinimumTradeValue) {
-		return s.logExecution(ctx, draft, signal, idempotencyKey, drafterQuantity, currentPrice,
-			entities.ExecutionStatusSkippedTooSmall, "trade value below minimum")
-	}
-
-	// Check available capital in draft
-	if signal.Side == "buy" && tradeValue.GreaterThan(draft.CurrentAUM) {
-		// Partial execution with available funds
-		if draft.CurrentAUM.LessThan(entities.MinimumTradeValue) {
-			return s.logExecution(ctx, draft, signal, idempotencyKey, decimal.Zero, currentPrice,
-				entities.ExecutionStatusInsufficientFunds, "insufficient funds for minimum trade")
-		}
-		drafterQuantity = draft.CurrentAUM.Div(currentPrice)
-		tradeValue = draft.CurrentAUM
+	// Calculate proportional quantity
+	drafterQuantity, status, reason := s.sizeDraftTrade(ctx, draft, signal, currentPrice)
+	if status != entities.ExecutionStatusSuccess {
+		return s.logExecution(ctx, draft, signal, idempotencyKey, drafterQuantity, currentPrice, status, reason)
 	}
 
 	// Execute the trade
@@ -610,7 +774,10 @@ func (s *Service) executeCopyTrade(ctx context.Context, draft *entities.Draft, s
 	log := &entities.SignalExecutionLog{
 		ID:               uuid.New(),
 		DraftID:          draft.ID,
-		SignalID:         signal.ID,
+		SignalID:         &signal.ID,
+		ExecutionType:    entities.ExecutionTypeSignal,
+		AssetTicker:      signal.AssetTicker,
+		Side:             signal.Side,
 		ExecutedQuantity: drafterQuantity,
 		ExecutedPrice:    executedPrice,
 		ExecutedValue:    executedValue,
@@ -633,7 +800,10 @@ func (s *Service) logExecution(ctx context.Context, draft *entities.Draft, signa
 	log := &entities.SignalExecutionLog{
 		ID:               uuid.New(),
 		DraftID:          draft.ID,
-		SignalID:         signal.ID,
+		SignalID:         &signal.ID,
+		ExecutionType:    entities.ExecutionTypeSignal,
+		AssetTicker:      signal.AssetTicker,
+		Side:             signal.Side,
 		ExecutedQuantity: quantity,
 		ExecutedPrice:    price,
 		ExecutedValue:    quantity.Mul(price),
@@ -672,6 +842,16 @@ func (s *Service) CreateSignalFromConductorTrade(ctx context.Context, conductorU
 		signalType = entities.SignalTypeSell
 	}
 
+	now := time.Now().UTC()
+	violation, details, blocked, err := s.checkSignalCompliance(ctx, conductor, ticker, side, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check compliance: %w", err)
+	}
+	if blocked {
+		s.recordComplianceEvent(ctx, conductor, nil, violation, ticker, side, quantity.Mul(price), true, details)
+		return nil, nil // Trade is not copied
+	}
+
 	signal := &entities.Signal{
 		ID:                   uuid.New(),
 		ConductorID:          conductor.ID,
@@ -684,13 +864,20 @@ func (s *Service) CreateSignalFromConductorTrade(ctx context.Context, conductorU
 		ConductorAUMAtSignal: conductor.SourceAUM,
 		OrderID:              orderID,
 		Status:               entities.SignalStatusPending,
-		CreatedAt:            time.Now().UTC(),
+		CreatedAt:            now,
 	}
 
 	if err := s.repo.CreateSignal(ctx, signal); err != nil {
 		return nil, fmt.Errorf("failed to create signal: %w", err)
 	}
 
+	if violation != "" {
+		s.recordComplianceEvent(ctx, conductor, &signal.ID, violation, ticker, side, signal.BaseValue, false, details)
+	}
+	if signalType == entities.SignalTypeSell {
+		s.detectFrontRunning(ctx, conductor, signal)
+	}
+
 	s.logger.Info("Signal created from conductor trade",
 		zap.String("signal_id", signal.ID.String()),
 		zap.String("conductor_id", conductor.ID.String()),
@@ -863,15 +1050,8 @@ func (s *Service) CreateTrack(ctx context.Context, userID uuid.UUID, req *entiti
 	}
 
 	// Validate allocations sum to 100%
-	totalWeight := decimal.Zero
-	for _, alloc := range req.Allocations {
-		if alloc.TargetWeight.LessThanOrEqual(decimal.Zero) {
-			return nil, fmt.Errorf("allocation weight must be positive")
-		}
-		totalWeight = totalWeight.Add(alloc.TargetWeight)
-	}
-	if !totalWeight.Equal(decimal.NewFromInt(100)) {
-		return nil, fmt.Errorf("allocations must sum to 100%%, got %s%%", totalWeight.String())
+	if err := validateTrackAllocations(req.Allocations); err != nil {
+		return nil, err
 	}
 
 	now := time.Now().UTC()
@@ -884,6 +1064,7 @@ func (s *Service) CreateTrack(ctx context.Context, userID uuid.UUID, req *entiti
 		IsActive:       true,
 		FollowersCount: 0,
 		TotalReturn:    decimal.Zero,
+		Version:        1,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -901,6 +1082,7 @@ func (s *Service) CreateTrack(ctx context.Context, userID uuid.UUID, req *entiti
 			AssetTicker:  alloc.AssetTicker,
 			AssetName:    alloc.AssetName,
 			TargetWeight: alloc.TargetWeight,
+			Version:      track.Version,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -909,6 +1091,14 @@ func (s *Service) CreateTrack(ctx context.Context, userID uuid.UUID, req *entiti
 	if err := s.repo.CreateTrackAllocations(ctx, allocations); err != nil {
 		return nil, fmt.Errorf("failed to create allocations: %w", err)
 	}
+	if err := s.repo.CreateTrackVersion(ctx, &entities.TrackVersion{
+		ID:        uuid.New(),
+		TrackID:   track.ID,
+		Version:   track.Version,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create track version: %w", err)
+	}
 
 	track.Allocations = allocations
 
@@ -981,7 +1171,8 @@ func (s *Service) GetConductorTracks(ctx context.Context, conductorID uuid.UUID)
 	return tracks, nil
 }
 
-// UpdateTrack updates a track's details and allocations
+// UpdateTrack updates a track's details. New allocations are published as the track's next
+// version, which rebalances its followers.
 func (s *Service) UpdateTrack(ctx context.Context, userID, trackID uuid.UUID, req *entities.UpdateTrackRequest) (*entities.Track, error) {
 	track, err := s.repo.GetTrackByID(ctx, trackID)
 	if err != nil {
@@ -1000,6 +1191,12 @@ func (s *Service) UpdateTrack(ctx context.Context, userID, trackID uuid.UUID, re
 		return nil, fmt.Errorf("unauthorized to update this track")
 	}
 
+	if len(req.Allocations) > 0 {
+		if err := validateTrackAllocations(req.Allocations); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields
 	if req.Name != nil {
 		track.Name = *req.Name
@@ -1016,44 +1213,16 @@ func (s *Service) UpdateTrack(ctx context.Context, userID, trackID uuid.UUID, re
 		return nil, fmt.Errorf("failed to update track: %w", err)
 	}
 
-	// Update allocations if provided
+	// Publish allocations if provided
 	if len(req.Allocations) > 0 {
-		totalWeight := decimal.Zero
-		for _, alloc := range req.Allocations {
-			totalWeight = totalWeight.Add(alloc.TargetWeight)
-		}
-		if !totalWeight.Equal(decimal.NewFromInt(100)) {
-			return nil, fmt.Errorf("allocations must sum to 100%%")
-		}
-
-		// Delete old allocations and create new ones
-		if err := s.repo.DeleteTrackAllocations(ctx, trackID); err != nil {
-			return nil, fmt.Errorf("failed to delete old allocations: %w", err)
-		}
-
-		allocations := make([]entities.TrackAllocation, len(req.Allocations))
-		now := time.Now().UTC()
-		for i, alloc := range req.Allocations {
-			allocations[i] = entities.TrackAllocation{
-				ID:           uuid.New(),
-				TrackID:      trackID,
-				AssetTicker:  alloc.AssetTicker,
-				AssetName:    alloc.AssetName,
-				TargetWeight: alloc.TargetWeight,
-				CreatedAt:    now,
-				UpdatedAt:    now,
-			}
+		if err := s.publishTrackVersion(ctx, track, conductor, req.Allocations); err != nil {
+			return nil, err
 		}
-
-		if err := s.repo.CreateTrackAllocations(ctx, allocations); err != nil {
-			return nil, fmt.Errorf("failed to create allocations: %w", err)
-		}
-
-		track.Allocations = allocations
 	}
 
 	s.logger.Info("Track updated",
-		zap.String("track_id", trackID.String()))
+		zap.String("track_id", trackID.String()),
+		zap.Int("version", track.Version))
 
 	return track, nil
 }
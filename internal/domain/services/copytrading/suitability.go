@@ -0,0 +1,45 @@
+package copytrading
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// SuitabilityChecker checks new drafts against the drafter's assessed risk band
+type SuitabilityChecker interface {
+	CheckSuitability(ctx context.Context, userID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand, acknowledged bool) error
+}
+
+// SetSuitabilityChecker sets the risk profile check for new drafts (optional)
+func (s *Service) SetSuitabilityChecker(checker SuitabilityChecker) {
+	s.suitability = checker
+}
+
+// draftRiskBand rates the risk of copying a conductor. A mirrored track uses its stated risk level;
+// otherwise the conductor's max drawdown decides. Copying a single trader is never rated below
+// balanced.
+func draftRiskBand(conductor *entities.Conductor, track *entities.Track) entities.RiskBand {
+	if track != nil {
+		switch track.RiskLevel {
+		case "low":
+			return entities.RiskBandBalanced
+		case "medium":
+			return entities.RiskBandGrowth
+		case "high":
+			return entities.RiskBandAggressive
+		}
+	}
+
+	switch {
+	case conductor.MaxDrawdown.LessThan(decimal.NewFromFloat(0.15)):
+		return entities.RiskBandBalanced
+	case conductor.MaxDrawdown.LessThan(decimal.NewFromFloat(0.30)):
+		return entities.RiskBandGrowth
+	default:
+		return entities.RiskBandAggressive
+	}
+}
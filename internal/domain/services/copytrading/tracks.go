@@ -0,0 +1,310 @@
+package copytrading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Track Versioning ===
+
+// GetTrackVersions returns a track's allocation history, newest first, with each version's changes
+// against the one before it
+func (s *Service) GetTrackVersions(ctx context.Context, trackID uuid.UUID, limit int) ([]*entities.TrackVersion, error) {
+	track, err := s.repo.GetTrackByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, fmt.Errorf("track not found")
+	}
+
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	versions, err := s.repo.GetTrackVersions(ctx, trackID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track versions: %w", err)
+	}
+
+	allocationsByVersion := make(map[int][]entities.TrackAllocation)
+	allocationsAt := func(version int) ([]entities.TrackAllocation, error) {
+		if allocations, ok := allocationsByVersion[version]; ok {
+			return allocations, nil
+		}
+		allocations, err := s.repo.GetTrackAllocationsByVersion(ctx, trackID, version)
+		if err != nil {
+			return nil, err
+		}
+		allocationsByVersion[version] = allocations
+		return allocations, nil
+	}
+
+	for _, version := range versions {
+		if version.Allocations, err = allocationsAt(version.Version); err != nil {
+			return nil, fmt.Errorf("failed to get allocations: %w", err)
+		}
+		var previous []entities.TrackAllocation
+		if version.Version > 1 {
+			if previous, err = allocationsAt(version.Version - 1); err != nil {
+				return nil, fmt.Errorf("failed to get allocations: %w", err)
+			}
+		}
+		version.Changes = trackAllocationChanges(previous, version.Allocations)
+	}
+
+	return versions, nil
+}
+
+// publishTrackVersion stores new allocations as the track's next version and emits the REBALANCE
+// signal that moves its followers. Allocations identical to the current ones create no version.
+func (s *Service) publishTrackVersion(ctx context.Context, track *entities.Track, conductor *entities.Conductor, requested []entities.CreateTrackAllocationRequest) error {
+	current, err := s.repo.GetTrackAllocations(ctx, track.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get allocations: %w", err)
+	}
+
+	now := time.Now().UTC()
+	version := track.Version + 1
+	allocations := make([]entities.TrackAllocation, len(requested))
+	for i, alloc := range requested {
+		allocations[i] = entities.TrackAllocation{
+			ID:           uuid.New(),
+			TrackID:      track.ID,
+			AssetTicker:  alloc.AssetTicker,
+			AssetName:    alloc.AssetName,
+			TargetWeight: alloc.TargetWeight,
+			Version:      version,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+	}
+
+	if len(trackAllocationChanges(current, allocations)) == 0 {
+		track.Allocations = current
+		return nil
+	}
+
+	if err := s.repo.CreateTrackAllocations(ctx, allocations); err != nil {
+		return fmt.Errorf("failed to create allocations: %w", err)
+	}
+
+	signal := &entities.Signal{
+		ID:                   uuid.New(),
+		ConductorID:          conductor.ID,
+		SignalType:           entities.SignalTypeRebalance,
+		BaseQuantity:         decimal.Zero,
+		BasePrice:            decimal.Zero,
+		BaseValue:            decimal.Zero,
+		ConductorAUMAtSignal: conductor.SourceAUM,
+		Status:               entities.SignalStatusPending,
+		CreatedAt:            now,
+		TrackID:              &track.ID,
+		TrackVersion:         &version,
+	}
+	if err := s.repo.CreateSignal(ctx, signal); err != nil {
+		return fmt.Errorf("failed to create rebalance signal: %w", err)
+	}
+
+	if err := s.repo.CreateTrackVersion(ctx, &entities.TrackVersion{
+		ID:        uuid.New(),
+		TrackID:   track.ID,
+		Version:   version,
+		SignalID:  &signal.ID,
+		CreatedAt: now,
+	}); err != nil {
+		return fmt.Errorf("failed to create track version: %w", err)
+	}
+
+	track.Version = version
+	track.Allocations = allocations
+
+	s.logger.Info("Track version published",
+		zap.String("track_id", track.ID.String()),
+		zap.Int("version", version),
+		zap.String("signal_id", signal.ID.String()))
+
+	return nil
+}
+
+// executeTrackRebalance moves a follower of a track from its previous allocations to the version
+// carried by a REBALANCE signal. Each asset's position changes by the draft's value times the change
+// in its weight, so drift the follower has built up elsewhere is left alone; assets dropped from the
+// track are sold outright. Sells run before buys and buys are limited to cash.
+func (s *Service) executeTrackRebalance(ctx context.Context, draft *entities.Draft, signal *entities.Signal) error {
+	if signal.TrackID == nil || signal.TrackVersion == nil {
+		return fmt.Errorf("rebalance signal has no track version")
+	}
+
+	previous, err := s.repo.GetTrackAllocationsByVersion(ctx, *signal.TrackID, *signal.TrackVersion-1)
+	if err != nil {
+		return fmt.Errorf("failed to get previous allocations: %w", err)
+	}
+	next, err := s.repo.GetTrackAllocationsByVersion(ctx, *signal.TrackID, *signal.TrackVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get allocations: %w", err)
+	}
+	changes := trackAllocationChanges(previous, next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	holdings, err := s.repo.GetDraftHoldings(ctx, draft.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get draft holdings: %w", err)
+	}
+	prices := make(map[string]decimal.Decimal)
+	value, err := s.valueDraftAt(ctx, draft, prices)
+	if err != nil {
+		return fmt.Errorf("failed to value draft: %w", err)
+	}
+
+	hundred := decimal.NewFromInt(100)
+	sells := make(map[string]decimal.Decimal)
+	buys := make(map[string]decimal.Decimal)
+	for _, change := range changes {
+		ticker := change.AssetTicker
+		price, err := s.priceOf(ctx, prices, ticker)
+		if err != nil {
+			return err
+		}
+		if !price.IsPositive() {
+			continue
+		}
+
+		if change.NewWeight.IsZero() {
+			if holdings[ticker].IsPositive() {
+				sells[ticker] = holdings[ticker]
+			}
+			continue
+		}
+		delta := value.Mul(change.NewWeight.Sub(change.PreviousWeight)).Div(hundred).Mul(draft.CopyRatio)
+		if delta.Abs().LessThan(entities.MinimumTradeValue) {
+			continue
+		}
+		if delta.IsNegative() {
+			if quantity := decimal.Min(delta.Abs().Div(price), holdings[ticker]); quantity.IsPositive() {
+				sells[ticker] = quantity
+			}
+		} else {
+			buys[ticker] = delta
+		}
+	}
+
+	cash := draft.CurrentAUM
+	failed := 0
+	place := func(ticker, side string, quantity decimal.Decimal) {
+		idempotencyKey := fmt.Sprintf("rebalance_%s_%s_%s", draft.ID.String(), signal.ID.String(), ticker)
+		existing, err := s.repo.GetExecutionLogByIdempotencyKey(ctx, idempotencyKey)
+		if err != nil {
+			s.logger.Error("Failed to check idempotency", zap.String("idempotency_key", idempotencyKey), zap.Error(err))
+			failed++
+			return
+		}
+		if existing != nil {
+			return
+		}
+
+		log := s.placeLoggedOrder(ctx, draft, &entities.SignalExecutionLog{
+			ID:             uuid.New(),
+			DraftID:        draft.ID,
+			SignalID:       &signal.ID,
+			ExecutionType:  entities.ExecutionTypeSignal,
+			AssetTicker:    ticker,
+			Side:           side,
+			FeeApplied:     decimal.Zero,
+			IdempotencyKey: idempotencyKey,
+			CreatedAt:      time.Now().UTC(),
+		}, quantity, prices[ticker])
+		switch {
+		case log.Status != entities.ExecutionStatusSuccess:
+			failed++
+		case side == "buy":
+			cash = cash.Sub(log.ExecutedValue)
+		default:
+			cash = cash.Add(log.ExecutedValue)
+		}
+	}
+
+	for _, change := range changes {
+		if quantity, ok := sells[change.AssetTicker]; ok {
+			place(change.AssetTicker, "sell", quantity.Truncate(8))
+		}
+	}
+	for _, change := range changes {
+		buyValue, ok := buys[change.AssetTicker]
+		if !ok {
+			continue
+		}
+		buyValue = decimal.Min(buyValue, cash)
+		if buyValue.LessThan(entities.MinimumTradeValue) {
+			continue
+		}
+		place(change.AssetTicker, "buy", buyValue.Div(prices[change.AssetTicker]).Truncate(8))
+	}
+
+	if !cash.Equal(draft.CurrentAUM) {
+		if err := s.repo.UpdateDraftAUM(ctx, draft.ID, cash, cash.Sub(draft.StartValue)); err != nil {
+			return fmt.Errorf("failed to update draft AUM: %w", err)
+		}
+		draft.CurrentAUM = cash
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d rebalance orders failed", failed)
+	}
+	return nil
+}
+
+// validateTrackAllocations checks that allocation weights are positive and sum to 100%
+func validateTrackAllocations(allocations []entities.CreateTrackAllocationRequest) error {
+	totalWeight := decimal.Zero
+	seen := make(map[string]bool, len(allocations))
+	for _, alloc := range allocations {
+		if alloc.TargetWeight.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("allocation weight must be positive")
+		}
+		if seen[alloc.AssetTicker] {
+			return fmt.Errorf("allocations must not repeat an asset, got %s twice", alloc.AssetTicker)
+		}
+		seen[alloc.AssetTicker] = true
+		totalWeight = totalWeight.Add(alloc.TargetWeight)
+	}
+	if !totalWeight.Equal(decimal.NewFromInt(100)) {
+		return fmt.Errorf("allocations must sum to 100%%, got %s%%", totalWeight.String())
+	}
+	return nil
+}
+
+// trackAllocationChanges lists the assets whose target weight differs between two allocation sets,
+// ordered by ticker. Assets missing from either set have a weight of zero there.
+func trackAllocationChanges(previous, next []entities.TrackAllocation) []entities.TrackAllocationChange {
+	weights := make(map[string]*entities.TrackAllocationChange)
+	for _, a := range previous {
+		weights[a.AssetTicker] = &entities.TrackAllocationChange{AssetTicker: a.AssetTicker, PreviousWeight: a.TargetWeight}
+	}
+	for _, a := range next {
+		change, ok := weights[a.AssetTicker]
+		if !ok {
+			change = &entities.TrackAllocationChange{AssetTicker: a.AssetTicker}
+			weights[a.AssetTicker] = change
+		}
+		change.NewWeight = a.TargetWeight
+	}
+
+	changes := make([]entities.TrackAllocationChange, 0, len(weights))
+	for _, change := range weights {
+		if !change.PreviousWeight.Equal(change.NewWeight) {
+			changes = append(changes, *change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].AssetTicker < changes[j].AssetTicker })
+	return changes
+}
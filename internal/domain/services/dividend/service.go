@@ -0,0 +1,493 @@
+package dividend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// Repository persists dividends and reinvestment settings
+type Repository interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*entities.DividendSettings, error)
+	UpsertSettings(ctx context.Context, settings *entities.DividendSettings) error
+	GetByActivityID(ctx context.Context, activityID string) (*entities.Dividend, error)
+	Create(ctx context.Context, dividend *entities.Dividend) error
+	Update(ctx context.Context, dividend *entities.Dividend) error
+	GetWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) (decimal.Decimal, error)
+	GetAwaitingWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) ([]*entities.Dividend, error)
+	ListAwaitingWithholding(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.Dividend, error)
+	ClaimAwaiting(ctx context.Context, dividendID uuid.UUID) (bool, error)
+	GetByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.Dividend, error)
+}
+
+// LedgerService posts dividend cash between the broker's operational account and the user's
+// buying power
+type LedgerService interface {
+	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	GetSystemAccount(ctx context.Context, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
+}
+
+// ContributionRecorder records dividends on the activity timeline
+type ContributionRecorder interface {
+	RecordContribution(ctx context.Context, userID uuid.UUID, contributionType entities.ContributionType, amount decimal.Decimal, source string) error
+}
+
+// OrderPlacer buys more of the paying asset
+type OrderPlacer interface {
+	PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error)
+}
+
+// CashFlowInvestor invests cash across the user's target allocation
+type CashFlowInvestor interface {
+	InvestCashFlow(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, source entities.CashFlowSource) ([]*entities.InvestmentOrder, decimal.Decimal, error)
+}
+
+// Service posts Alpaca dividend activities to the ledger and reinvests them when the user
+// has opted in
+type Service struct {
+	repo                 Repository
+	ledgerService        LedgerService
+	contributionRecorder ContributionRecorder
+	orderPlacer          OrderPlacer
+	cashFlowInvestor     CashFlowInvestor
+	logger               *zap.Logger
+}
+
+// NewService creates a new dividend service
+func NewService(
+	repo Repository,
+	ledgerService LedgerService,
+	contributionRecorder ContributionRecorder,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:                 repo,
+		ledgerService:        ledgerService,
+		contributionRecorder: contributionRecorder,
+		logger:               logger,
+	}
+}
+
+// SetOrderPlacer sets the order placer used to reinvest into the paying asset (optional)
+func (s *Service) SetOrderPlacer(orderPlacer OrderPlacer) {
+	s.orderPlacer = orderPlacer
+}
+
+// SetCashFlowInvestor sets the investor used to reinvest across the user's target allocation (optional)
+func (s *Service) SetCashFlowInvestor(investor CashFlowInvestor) {
+	s.cashFlowInvestor = investor
+}
+
+// GetSettings returns the user's reinvestment settings, defaulting to keeping dividends as cash
+func (s *Service) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.DividendSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &entities.DividendSettings{UserID: userID, ReinvestMode: entities.DividendReinvestOff}
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes what happens to the user's future dividends
+func (s *Service) UpdateSettings(ctx context.Context, userID uuid.UUID, mode entities.DividendReinvestMode) (*entities.DividendSettings, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid reinvest mode")
+	}
+	settings := &entities.DividendSettings{UserID: userID, ReinvestMode: mode, UpdatedAt: time.Now()}
+	if err := s.repo.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// RecordDividend posts a DIV or DIVNRA activity to the user's buying power. Dividends are
+// recorded on the activity timeline and reinvested per the user's settings once tax withheld
+// from the same payment is known; withholding reduces buying power and releases the dividend
+// it was withheld from for reinvestment. Each activity is
+// processed once, so redelivered webhooks are safe, and a redelivery of an activity whose
+// ledger post failed posts it again.
+func (s *Service) RecordDividend(ctx context.Context, userID uuid.UUID, activity *entities.AlpacaNonTradeActivityEvent) (*entities.Dividend, error) {
+	activityType := activity.Type()
+	if !entities.IsDividendActivity(activityType) {
+		return nil, fmt.Errorf("not a dividend activity")
+	}
+	if activity.ID == "" {
+		return nil, fmt.Errorf("activity id required")
+	}
+
+	existing, err := s.repo.GetByActivityID(ctx, activity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.LedgerTransactionID != nil || existing.NetAmount.IsZero() {
+			return existing, nil
+		}
+		// An earlier attempt recorded the dividend but never credited it; the ledger
+		// idempotency key makes posting it again safe
+		return s.creditDividend(ctx, existing)
+	}
+
+	now := time.Now()
+	dividend := &entities.Dividend{
+		ID:                uuid.New(),
+		UserID:            userID,
+		ActivityID:        activity.ID,
+		ActivityType:      activityType,
+		Symbol:            strings.ToUpper(activity.Symbol),
+		Quantity:          activity.Qty,
+		PerShareAmount:    activity.PerShareAmount,
+		GrossAmount:       decimal.Zero,
+		WithholdingAmount: decimal.Zero,
+		NetAmount:         activity.NetAmount,
+		PayDate:           activity.PayDate(),
+		ReinvestStatus:    entities.DividendReinvestStatusNone,
+		ReinvestAmount:    decimal.Zero,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if activityType == entities.ActivityTypeDividend {
+		dividend.GrossAmount = activity.NetAmount
+	} else {
+		dividend.WithholdingAmount = activity.NetAmount.Abs()
+	}
+
+	if err := s.repo.Create(ctx, dividend); err != nil {
+		return nil, err
+	}
+	return s.creditDividend(ctx, dividend)
+}
+
+// creditDividend posts a recorded dividend to the ledger, saving the ledger transaction before
+// anything else happens, then records the contribution and reinvests it, or reinvests the
+// dividends a withholding was waiting on
+func (s *Service) creditDividend(ctx context.Context, dividend *entities.Dividend) (*entities.Dividend, error) {
+	ledgerTxID, err := s.postToLedger(ctx, dividend)
+	if err != nil {
+		return nil, fmt.Errorf("post dividend to ledger: %w", err)
+	}
+	dividend.LedgerTransactionID = ledgerTxID
+	dividend.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, dividend); err != nil {
+		return nil, err
+	}
+
+	if dividend.ActivityType == entities.ActivityTypeDividend && dividend.GrossAmount.IsPositive() {
+		if s.contributionRecorder != nil {
+			if err := s.contributionRecorder.RecordContribution(ctx, dividend.UserID, entities.ContributionTypeDividend, dividend.GrossAmount, "dividend:"+dividend.Symbol); err != nil {
+				s.logger.Warn("Failed to record dividend contribution", zap.String("dividend_id", dividend.ID.String()), zap.Error(err))
+			}
+		}
+		s.scheduleReinvestment(ctx, dividend)
+	}
+
+	dividend.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, dividend); err != nil {
+		return nil, err
+	}
+
+	if dividend.ActivityType == entities.ActivityTypeDividendNRAWithholding {
+		awaiting, err := s.repo.GetAwaitingWithholding(ctx, dividend.UserID, dividend.Symbol, dividend.PayDate)
+		if err != nil {
+			s.logger.Warn("Failed to get dividends awaiting withholding", zap.String("dividend_id", dividend.ID.String()), zap.Error(err))
+		}
+		for _, paid := range awaiting {
+			if err := s.reinvestAwaiting(ctx, paid); err != nil {
+				s.logger.Warn("Failed to reinvest dividend after withholding", zap.String("dividend_id", paid.ID.String()), zap.Error(err))
+			}
+		}
+	}
+
+	s.logger.Info("Dividend recorded",
+		zap.String("user_id", dividend.UserID.String()),
+		zap.String("symbol", dividend.Symbol),
+		zap.String("activity_type", dividend.ActivityType),
+		zap.String("net_amount", dividend.NetAmount.String()),
+		zap.String("reinvest_status", dividend.ReinvestStatus))
+	return dividend, nil
+}
+
+// postToLedger books dividend cash the broker paid in to the user's buying power, or withholding
+// back out of it, against the broker incoming clearing account so the pre-funded operational pool
+// is left untouched. Withholding is posted in full even when the dividend has already been spent;
+// whatever buying power cannot cover is booked to the user's withholding receivable, which later
+// dividends settle before adding to buying power. The idempotency key is derived from the activity.
+func (s *Service) postToLedger(ctx context.Context, dividend *entities.Dividend) (*uuid.UUID, error) {
+	amount := dividend.NetAmount.Abs()
+	if amount.IsZero() {
+		return nil, nil
+	}
+
+	brokerAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeBrokerIncoming)
+	if err != nil {
+		return nil, fmt.Errorf("get broker incoming account: %w", err)
+	}
+	exposureAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, dividend.UserID, entities.AccountTypeFiatExposure)
+	if err != nil {
+		return nil, fmt.Errorf("get fiat exposure account: %w", err)
+	}
+
+	receivableAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, dividend.UserID, entities.AccountTypeWithholdingReceivable)
+	if err != nil {
+		return nil, fmt.Errorf("get withholding receivable account: %w", err)
+	}
+
+	brokerEntry, exposureEntry, receivableEntry := entities.EntryTypeCredit, entities.EntryTypeDebit, entities.EntryTypeDebit // Buying power up
+	desc := fmt.Sprintf("Dividend: %s", dividend.Symbol)
+	receivableDesc := fmt.Sprintf("Dividend settles tax withheld beyond buying power: %s", dividend.Symbol)
+	// Withholding owed from earlier payments comes out of the dividend first
+	receivableAmount := decimal.Min(decimal.Max(receivableAccount.Balance.Neg(), decimal.Zero), amount)
+	if dividend.NetAmount.IsNegative() {
+		brokerEntry, exposureEntry, receivableEntry = entities.EntryTypeDebit, entities.EntryTypeCredit, entities.EntryTypeCredit // Buying power down
+		desc = fmt.Sprintf("Dividend tax withheld: %s", dividend.Symbol)
+		receivableDesc = fmt.Sprintf("Dividend tax withheld beyond buying power: %s", dividend.Symbol)
+		receivableAmount = decimal.Max(amount.Sub(decimal.Max(exposureAccount.Balance, decimal.Zero)), decimal.Zero)
+	}
+	exposureAmount := amount.Sub(receivableAmount)
+	referenceType := "dividend"
+
+	entries := []entities.CreateEntryRequest{
+		{
+			AccountID:   brokerAccount.ID,
+			EntryType:   brokerEntry,
+			Amount:      amount,
+			Currency:    "USD",
+			Description: &desc,
+		},
+	}
+	if exposureAmount.IsPositive() {
+		entries = append(entries, entities.CreateEntryRequest{
+			AccountID:   exposureAccount.ID,
+			EntryType:   exposureEntry,
+			Amount:      exposureAmount,
+			Currency:    "USD",
+			Description: &desc,
+		})
+	}
+	if receivableAmount.IsPositive() {
+		entries = append(entries, entities.CreateEntryRequest{
+			AccountID:   receivableAccount.ID,
+			EntryType:   receivableEntry,
+			Amount:      receivableAmount,
+			Currency:    "USD",
+			Description: &receivableDesc,
+		})
+		s.logger.Info("Dividend withholding receivable adjusted",
+			zap.String("user_id", dividend.UserID.String()),
+			zap.String("dividend_id", dividend.ID.String()),
+			zap.String("entry_type", string(receivableEntry)),
+			zap.String("amount", receivableAmount.String()))
+	}
+
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &dividend.UserID,
+		TransactionType: entities.TransactionTypeDividend,
+		ReferenceID:     &dividend.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  fmt.Sprintf("dividend:%s", dividend.ActivityID),
+		Description:     &desc,
+		Entries:         entries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ledgerTx.ID, nil
+}
+
+// scheduleReinvestment reinvests a dividend straight away when tax withheld from the same payment
+// is already known, and otherwise holds it until the withholding arrives or the wait runs out
+func (s *Service) scheduleReinvestment(ctx context.Context, dividend *entities.Dividend) {
+	settings, err := s.GetSettings(ctx, dividend.UserID)
+	if err != nil {
+		s.failReinvest(dividend, fmt.Errorf("get dividend settings: %w", err))
+		return
+	}
+	if settings.ReinvestMode == entities.DividendReinvestOff {
+		return
+	}
+
+	withholding, err := s.repo.GetWithholding(ctx, dividend.UserID, dividend.Symbol, dividend.PayDate)
+	if err != nil {
+		s.failReinvest(dividend, err)
+		return
+	}
+	if withholding.IsZero() {
+		dividend.ReinvestStatus = entities.DividendReinvestStatusAwaiting
+		return
+	}
+	s.reinvest(ctx, dividend, settings, withholding)
+}
+
+// ReinvestAwaitingDividends reinvests dividends that were recorded before createdBefore and are
+// still waiting on withholding, treating any withholding recorded since as final. It returns
+// how many dividends it looked at.
+func (s *Service) ReinvestAwaitingDividends(ctx context.Context, createdBefore time.Time, limit int) (int, error) {
+	dividends, err := s.repo.ListAwaitingWithholding(ctx, createdBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, dividend := range dividends {
+		if err := s.reinvestAwaiting(ctx, dividend); err != nil {
+			s.logger.Warn("Failed to reinvest awaiting dividend", zap.String("dividend_id", dividend.ID.String()), zap.Error(err))
+		}
+	}
+	return len(dividends), nil
+}
+
+// reinvestAwaiting claims a dividend held for withholding and reinvests it net of the withholding
+// recorded so far. The claim keeps a withholding arriving while the wait runs out from
+// reinvesting it twice.
+func (s *Service) reinvestAwaiting(ctx context.Context, dividend *entities.Dividend) error {
+	claimed, err := s.repo.ClaimAwaiting(ctx, dividend.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	dividend.ReinvestStatus = entities.DividendReinvestStatusNone
+
+	settings, err := s.GetSettings(ctx, dividend.UserID)
+	if err != nil {
+		s.failReinvest(dividend, fmt.Errorf("get dividend settings: %w", err))
+	} else if settings.ReinvestMode != entities.DividendReinvestOff {
+		withholding, err := s.repo.GetWithholding(ctx, dividend.UserID, dividend.Symbol, dividend.PayDate)
+		if err != nil {
+			s.failReinvest(dividend, err)
+		} else {
+			s.reinvest(ctx, dividend, settings, withholding)
+		}
+	}
+
+	dividend.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, dividend); err != nil {
+		return err
+	}
+	s.logger.Info("Awaiting dividend reinvested",
+		zap.String("dividend_id", dividend.ID.String()),
+		zap.String("reinvest_status", dividend.ReinvestStatus),
+		zap.String("reinvest_amount", dividend.ReinvestAmount.String()))
+	return nil
+}
+
+// reinvest buys with a dividend, net of tax withheld from the same payment, per the user's
+// settings. Failures leave the dividend as buying power and are recorded on it.
+func (s *Service) reinvest(ctx context.Context, dividend *entities.Dividend, settings *entities.DividendSettings, withholding decimal.Decimal) {
+	var err error
+	amount := dividend.GrossAmount.Sub(withholding)
+	if amount.LessThan(entities.MinDividendReinvestment) {
+		dividend.ReinvestStatus = entities.DividendReinvestStatusSkipped
+		reason := fmt.Sprintf("$%s is below the $%s minimum reinvestment", amount.StringFixed(2), entities.MinDividendReinvestment.StringFixed(2))
+		dividend.ReinvestError = &reason
+		return
+	}
+
+	var orders []*entities.InvestmentOrder
+	switch settings.ReinvestMode {
+	case entities.DividendReinvestSameAsset:
+		if s.orderPlacer == nil {
+			s.failReinvest(dividend, fmt.Errorf("order placer not configured"))
+			return
+		}
+		order, err := s.orderPlacer.PlaceMarketOrder(ctx, dividend.UserID, dividend.Symbol, amount)
+		if err != nil {
+			s.failReinvest(dividend, err)
+			return
+		}
+		orders = append(orders, order)
+	case entities.DividendReinvestStrategy:
+		if s.cashFlowInvestor == nil {
+			s.failReinvest(dividend, fmt.Errorf("cash flow investor not configured"))
+			return
+		}
+		var invested decimal.Decimal
+		orders, invested, err = s.cashFlowInvestor.InvestCashFlow(ctx, dividend.UserID, amount, entities.CashFlowSourceDividend)
+		if err != nil {
+			// Orders placed before the failure still stand
+			dividend.ReinvestAmount = invested
+			dividend.ReinvestOrders = len(orders)
+			s.failReinvest(dividend, err)
+			return
+		}
+		if orders == nil {
+			dividend.ReinvestStatus = entities.DividendReinvestStatusSkipped
+			reason := "no target allocation to reinvest into"
+			dividend.ReinvestError = &reason
+			return
+		}
+		// Trade minimums and whole-share rounding can leave part of the dividend as cash
+		amount = invested
+	}
+
+	dividend.ReinvestStatus = entities.DividendReinvestStatusPlaced
+	dividend.ReinvestAmount = amount
+	dividend.ReinvestOrders = len(orders)
+}
+
+func (s *Service) failReinvest(dividend *entities.Dividend, err error) {
+	s.logger.Error("Failed to reinvest dividend",
+		zap.String("dividend_id", dividend.ID.String()),
+		zap.String("symbol", dividend.Symbol),
+		zap.Error(err))
+	dividend.ReinvestStatus = entities.DividendReinvestStatusFailed
+	errMsg := err.Error()
+	dividend.ReinvestError = &errMsg
+}
+
+// GetDividendReport totals the user's dividends and withholding for a calendar tax year (UTC) by asset
+func (s *Service) GetDividendReport(ctx context.Context, userID uuid.UUID, year int) (*entities.DividendReport, error) {
+	if year < 1900 || year > time.Now().Year() {
+		return nil, fmt.Errorf("invalid tax year")
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dividends, err := s.repo.GetByUser(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.DividendReport{
+		UserID:      userID,
+		Year:        year,
+		Gross:       decimal.Zero,
+		Withholding: decimal.Zero,
+		Net:         decimal.Zero,
+		Reinvested:  decimal.Zero,
+		BySymbol:    []*entities.DividendSymbolSummary{},
+		Dividends:   dividends,
+	}
+	bySymbol := make(map[string]*entities.DividendSymbolSummary)
+	for _, dividend := range dividends {
+		summary, ok := bySymbol[dividend.Symbol]
+		if !ok {
+			summary = &entities.DividendSymbolSummary{Symbol: dividend.Symbol}
+			bySymbol[dividend.Symbol] = summary
+			report.BySymbol = append(report.BySymbol, summary)
+		}
+		summary.Gross = summary.Gross.Add(dividend.GrossAmount)
+		summary.Withholding = summary.Withholding.Add(dividend.WithholdingAmount)
+		summary.Net = summary.Net.Add(dividend.NetAmount)
+		if dividend.ActivityType == entities.ActivityTypeDividend {
+			summary.Payments++
+		}
+
+		report.Gross = report.Gross.Add(dividend.GrossAmount)
+		report.Withholding = report.Withholding.Add(dividend.WithholdingAmount)
+		report.Net = report.Net.Add(dividend.NetAmount)
+		report.Reinvested = report.Reinvested.Add(dividend.ReinvestAmount)
+	}
+	sort.Slice(report.BySymbol, func(i, j int) bool {
+		return report.BySymbol[i].Gross.GreaterThan(report.BySymbol[j].Gross)
+	})
+	return report, nil
+}
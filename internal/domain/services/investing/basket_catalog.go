@@ -0,0 +1,482 @@
+package investing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// minMigrationTrade is the smallest weight change, in dollars, worth trading when migrating a holder
+var minMigrationTrade = decimal.NewFromInt(1)
+
+// BasketCatalogRepository stores curated baskets, their composition versions and holder migrations
+type BasketCatalogRepository interface {
+	GetAll(ctx context.Context) ([]*entities.Basket, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error)
+	Create(ctx context.Context, basket *entities.Basket, version *entities.BasketVersion) error
+	UpdateDetails(ctx context.Context, basket *entities.Basket) error
+	CreateVersion(ctx context.Context, version *entities.BasketVersion) error
+	GetVersion(ctx context.Context, basketID uuid.UUID, version int) (*entities.BasketVersion, error)
+	GetVersions(ctx context.Context, basketID uuid.UUID) ([]*entities.BasketVersion, error)
+	GetDueVersions(ctx context.Context, at time.Time, limit int) ([]*entities.BasketVersion, error)
+	ActivateVersion(ctx context.Context, version *entities.BasketVersion) (int, error)
+	GetPendingHolderMigrations(ctx context.Context, limit int) ([]*entities.BasketHolderMigration, error)
+	UpdateHolderMigration(ctx context.Context, migration *entities.BasketHolderMigration) error
+}
+
+// AssetLookup looks up broker assets to check they can be traded
+type AssetLookup interface {
+	GetAsset(ctx context.Context, symbolOrID string) (*entities.AlpacaAssetResponse, error)
+}
+
+// BasketPositionProvider returns a user's position in a basket
+type BasketPositionProvider interface {
+	GetByUserAndBasket(ctx context.Context, userID, basketID uuid.UUID) (*entities.Position, error)
+}
+
+// BasketCatalogService manages curated baskets: versioned compositions, scheduled changes and
+// migrating holders when a new composition takes effect
+type BasketCatalogService struct {
+	repo        BasketCatalogRepository
+	assets      AssetLookup
+	positions   BasketPositionProvider
+	orderPlacer OrderPlacer
+	logger      *zap.Logger
+}
+
+// NewBasketCatalogService creates a new basket catalog service
+func NewBasketCatalogService(repo BasketCatalogRepository, logger *zap.Logger) *BasketCatalogService {
+	return &BasketCatalogService{repo: repo, logger: logger}
+}
+
+// SetAssetLookup sets the broker asset lookup used to reject compositions with untradable symbols
+func (s *BasketCatalogService) SetAssetLookup(assets AssetLookup) {
+	s.assets = assets
+}
+
+// SetHolderMigration sets the positions and order placer used to move holders to a new composition
+func (s *BasketCatalogService) SetHolderMigration(positions BasketPositionProvider, orderPlacer OrderPlacer) {
+	s.positions = positions
+	s.orderPlacer = orderPlacer
+}
+
+// ListBaskets returns every curated basket with its active composition
+func (s *BasketCatalogService) ListBaskets(ctx context.Context) ([]*entities.Basket, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetBasket returns a curated basket, or nil if it does not exist
+func (s *BasketCatalogService) GetBasket(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetVersions returns a basket's composition versions, newest first
+func (s *BasketCatalogService) GetVersions(ctx context.Context, basketID uuid.UUID) ([]*entities.BasketVersion, error) {
+	return s.repo.GetVersions(ctx, basketID)
+}
+
+// GetAllocations returns the active composition of a basket as executor allocations
+func (s *BasketCatalogService) GetAllocations(ctx context.Context, basketID uuid.UUID) ([]BasketAllocation, error) {
+	basket, err := s.repo.GetByID(ctx, basketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get basket: %w", err)
+	}
+	if basket == nil {
+		return nil, ErrBasketNotFound
+	}
+	return BasketAllocationsFromComposition(basket.Composition), nil
+}
+
+// CreateBasket validates a composition's symbols with the broker and stores the basket with its
+// composition as version 1
+func (s *BasketCatalogService) CreateBasket(ctx context.Context, adminID uuid.UUID, req *entities.CuratedBasketRequest) (*entities.Basket, error) {
+	composition, err := s.validateComposition(ctx, req.Composition)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	basket := &entities.Basket{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Description:    req.Description,
+		RiskLevel:      req.RiskLevel,
+		Composition:    composition,
+		CurrentVersion: 1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	version := &entities.BasketVersion{
+		ID:          uuid.New(),
+		BasketID:    basket.ID,
+		Version:     1,
+		Composition: composition,
+		Status:      entities.BasketVersionStatusActive,
+		EffectiveAt: now,
+		ActivatedAt: &now,
+		CreatedBy:   &adminID,
+		CreatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, basket, version); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Curated basket created",
+		zap.String("basket_id", basket.ID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.Int("components", len(composition)))
+	return basket, nil
+}
+
+// UpdateBasket updates a basket's details and, when the composition changed, adds a version that
+// takes effect at req.EffectiveAt or immediately. Activating a version queues holder migrations.
+func (s *BasketCatalogService) UpdateBasket(ctx context.Context, adminID, basketID uuid.UUID, req *entities.CuratedBasketRequest) (*entities.BasketVersionChange, error) {
+	basket, err := s.repo.GetByID(ctx, basketID)
+	if err != nil {
+		return nil, err
+	}
+	if basket == nil {
+		return nil, ErrBasketNotFound
+	}
+
+	composition, err := s.validateComposition(ctx, req.Composition)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	basket.Name = req.Name
+	basket.Description = req.Description
+	basket.RiskLevel = req.RiskLevel
+	basket.UpdatedAt = now
+	if err := s.repo.UpdateDetails(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	change := &entities.BasketVersionChange{Basket: basket}
+	if sameComposition(basket.Composition, composition) {
+		return change, nil
+	}
+
+	version := &entities.BasketVersion{
+		ID:          uuid.New(),
+		BasketID:    basket.ID,
+		Composition: composition,
+		Status:      entities.BasketVersionStatusScheduled,
+		EffectiveAt: now,
+		CreatedBy:   &adminID,
+		CreatedAt:   now,
+	}
+	if req.EffectiveAt != nil && req.EffectiveAt.After(now) {
+		version.EffectiveAt = req.EffectiveAt.UTC()
+	}
+	if err := s.repo.CreateVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	change.Version = version
+
+	s.logger.Info("Basket composition change saved",
+		zap.String("basket_id", basket.ID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.Int("version", version.Version),
+		zap.Time("effective_at", version.EffectiveAt))
+
+	if version.EffectiveAt.After(now) {
+		return change, nil
+	}
+	if err := s.activateVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	basket.Composition = composition
+	basket.CurrentVersion = version.Version
+	return change, nil
+}
+
+// ActivateDueVersions activates scheduled compositions whose effective time has passed
+func (s *BasketCatalogService) ActivateDueVersions(ctx context.Context, limit int) (int, error) {
+	versions, err := s.repo.GetDueVersions(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due basket versions: %w", err)
+	}
+
+	activated := 0
+	for _, version := range versions {
+		if err := s.activateVersion(ctx, version); err != nil {
+			s.logger.Error("Failed to activate basket version",
+				zap.String("basket_id", version.BasketID.String()),
+				zap.Int("version", version.Version),
+				zap.Error(err))
+			continue
+		}
+		activated++
+	}
+	return activated, nil
+}
+
+func (s *BasketCatalogService) activateVersion(ctx context.Context, version *entities.BasketVersion) error {
+	now := time.Now().UTC()
+	version.ActivatedAt = &now
+	holders, err := s.repo.ActivateVersion(ctx, version)
+	if err != nil {
+		return err
+	}
+	version.Status = entities.BasketVersionStatusActive
+
+	s.logger.Info("Basket composition activated",
+		zap.String("basket_id", version.BasketID.String()),
+		zap.Int("version", version.Version),
+		zap.Int("holders_to_migrate", holders))
+	return nil
+}
+
+// MigrateHolders trades pending holders' positions from the composition they held into the
+// basket's new one: sells for reduced weights first, then buys for increased weights. A holder
+// whose trades stop partway stays pending and the next pass places the legs left, until
+// MaxBasketMigrationAttempts passes have failed.
+func (s *BasketCatalogService) MigrateHolders(ctx context.Context, limit int) (int, error) {
+	if s.positions == nil || s.orderPlacer == nil {
+		return 0, nil
+	}
+
+	migrations, err := s.repo.GetPendingHolderMigrations(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get basket holder migrations: %w", err)
+	}
+
+	migrated := 0
+	for _, migration := range migrations {
+		now := time.Now().UTC()
+		if err := s.migrateHolder(ctx, migration); err != nil {
+			errMsg := err.Error()
+			migration.Error = &errMsg
+			migration.Attempts++
+			if migration.Attempts >= entities.MaxBasketMigrationAttempts {
+				migration.Status = entities.BasketHolderMigrationStatusFailed
+				migration.CompletedAt = &now
+			}
+			s.logger.Error("Failed to migrate basket holder",
+				zap.String("migration_id", migration.ID.String()),
+				zap.String("basket_id", migration.BasketID.String()),
+				zap.String("user_id", migration.UserID.String()),
+				zap.Int("attempts", migration.Attempts),
+				zap.String("status", string(migration.Status)),
+				zap.Error(err))
+		} else {
+			migration.Status = entities.BasketHolderMigrationStatusCompleted
+			migration.Error = nil
+			migration.CompletedAt = &now
+			migrated++
+		}
+
+		if err := s.repo.UpdateHolderMigration(ctx, migration); err != nil {
+			s.logger.Error("Failed to save basket holder migration",
+				zap.String("migration_id", migration.ID.String()),
+				zap.Error(err))
+		}
+	}
+	return migrated, nil
+}
+
+// migrateHolder plans a holder's trades on the first pass and places every leg not yet placed,
+// saving each leg's order as it goes so a failure leaves the placed legs recorded
+func (s *BasketCatalogService) migrateHolder(ctx context.Context, migration *entities.BasketHolderMigration) error {
+	if len(migration.Trades) == 0 {
+		planned, err := s.planMigration(ctx, migration)
+		if err != nil {
+			return err
+		}
+		if !planned {
+			return nil
+		}
+	}
+
+	for _, side := range []string{"sell", "buy"} {
+		for i := range migration.Trades {
+			trade := &migration.Trades[i]
+			if trade.Side != side || trade.PlacedAt != nil {
+				continue
+			}
+			notional := trade.Amount
+			if side == "sell" {
+				notional = notional.Neg()
+			}
+			order, err := s.orderPlacer.PlaceMarketOrder(ctx, migration.UserID, trade.Symbol, notional)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", side, trade.Symbol, err)
+			}
+			placedAt := time.Now().UTC()
+			trade.OrderID = &order.ID
+			trade.PlacedAt = &placedAt
+			if err := s.repo.UpdateHolderMigration(ctx, migration); err != nil {
+				s.logger.Warn("Failed to save basket migration progress",
+					zap.String("migration_id", migration.ID.String()),
+					zap.String("symbol", trade.Symbol),
+					zap.Error(err))
+			}
+		}
+	}
+
+	s.logger.Info("Basket holder migrated",
+		zap.String("basket_id", migration.BasketID.String()),
+		zap.String("user_id", migration.UserID.String()),
+		zap.Int("from_version", migration.FromVersion),
+		zap.Int("to_version", migration.ToVersion),
+		zap.Int("trades", len(migration.Trades)))
+	return nil
+}
+
+// planMigration works out a holder's trades from their position and saves them before any is
+// placed, so later passes trade the same legs rather than re-planning against a position that
+// has partly moved. It reports false when the holder has nothing to migrate.
+func (s *BasketCatalogService) planMigration(ctx context.Context, migration *entities.BasketHolderMigration) (bool, error) {
+	from, err := s.repo.GetVersion(ctx, migration.BasketID, migration.FromVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to get basket version %d: %w", migration.FromVersion, err)
+	}
+	to, err := s.repo.GetVersion(ctx, migration.BasketID, migration.ToVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to get basket version %d: %w", migration.ToVersion, err)
+	}
+	if from == nil || to == nil {
+		return false, fmt.Errorf("basket version not found")
+	}
+
+	position, err := s.positions.GetByUserAndBasket(ctx, migration.UserID, migration.BasketID)
+	if err != nil && err != ErrPositionNotFound {
+		return false, fmt.Errorf("failed to get position: %w", err)
+	}
+	if position == nil || !position.MarketValue.IsPositive() {
+		return false, nil
+	}
+
+	for _, trade := range migrationTrades(position.MarketValue, from.Composition, to.Composition) {
+		migration.Trades = append(migration.Trades, entities.BasketMigrationTrade{RebalanceTradeOrder: trade})
+	}
+	if len(migration.Trades) == 0 {
+		return false, nil
+	}
+	if err := s.repo.UpdateHolderMigration(ctx, migration); err != nil {
+		return false, fmt.Errorf("failed to save migration trades: %w", err)
+	}
+	return true, nil
+}
+
+// migrationTrades returns the trades that move a position's value from one composition's
+// weights to another's, skipping changes below minMigrationTrade
+func migrationTrades(value decimal.Decimal, from, to []entities.BasketComponent) []entities.RebalanceTradeOrder {
+	current := make(map[string]decimal.Decimal, len(from))
+	for _, component := range from {
+		current[component.Symbol] = component.Weight
+	}
+	target := make(map[string]decimal.Decimal, len(to))
+	for _, component := range to {
+		target[component.Symbol] = component.Weight
+	}
+
+	symbols := make([]string, 0, len(current)+len(target))
+	for symbol := range current {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range target {
+		if _, ok := current[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	hundred := decimal.NewFromInt(100)
+	trades := make([]entities.RebalanceTradeOrder, 0, len(symbols))
+	for _, symbol := range symbols {
+		drift := target[symbol].Sub(current[symbol])
+		amount := value.Mul(drift).Abs().Round(2)
+		if amount.LessThan(minMigrationTrade) {
+			continue
+		}
+		side := "buy"
+		if drift.IsNegative() {
+			side = "sell"
+		}
+		trades = append(trades, entities.RebalanceTradeOrder{
+			Symbol:     symbol,
+			Side:       side,
+			CurrentPct: current[symbol].Mul(hundred),
+			TargetPct:  target[symbol].Mul(hundred),
+			DriftPct:   drift.Mul(hundred),
+			Amount:     amount,
+		})
+	}
+	return trades
+}
+
+// validateComposition normalizes symbols, rejects duplicates and, when an asset lookup is set,
+// symbols the broker does not list as active, tradable and fractionable
+func (s *BasketCatalogService) validateComposition(ctx context.Context, components []entities.BasketComponent) ([]entities.BasketComponent, error) {
+	composition := make([]entities.BasketComponent, 0, len(components))
+	seen := make(map[string]bool, len(components))
+	for _, component := range components {
+		symbol := strings.ToUpper(strings.TrimSpace(component.Symbol))
+		if seen[symbol] {
+			return nil, fmt.Errorf("duplicate symbol %s in composition", symbol)
+		}
+		seen[symbol] = true
+		composition = append(composition, entities.BasketComponent{Symbol: symbol, Weight: component.Weight})
+	}
+
+	if s.assets == nil {
+		return composition, nil
+	}
+	for _, component := range composition {
+		asset, err := s.assets.GetAsset(ctx, component.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up asset %s: %w", component.Symbol, err)
+		}
+		if asset == nil {
+			return nil, fmt.Errorf("asset %s not found", component.Symbol)
+		}
+		if asset.Status != entities.AlpacaAssetStatusActive || !asset.Tradable {
+			return nil, fmt.Errorf("asset %s is not tradable", component.Symbol)
+		}
+		if !asset.Fractionable {
+			return nil, fmt.Errorf("asset %s does not support fractional orders", component.Symbol)
+		}
+	}
+	return composition, nil
+}
+
+// sameComposition reports whether two compositions hold the same symbols at the same weights
+func sameComposition(a, b []entities.BasketComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	weights := make(map[string]decimal.Decimal, len(a))
+	for _, component := range a {
+		weights[component.Symbol] = component.Weight
+	}
+	for _, component := range b {
+		weight, ok := weights[component.Symbol]
+		if !ok || !weight.Equal(component.Weight) {
+			return false
+		}
+	}
+	return true
+}
+
+// BasketAllocationsFromComposition converts a basket composition's 0-1 weights into executor allocations
+func BasketAllocationsFromComposition(composition []entities.BasketComponent) []BasketAllocation {
+	allocations := make([]BasketAllocation, 0, len(composition))
+	for _, component := range composition {
+		allocations = append(allocations, BasketAllocation{
+			Symbol:     component.Symbol,
+			Percentage: component.Weight.Mul(decimal.NewFromInt(100)),
+		})
+	}
+	return allocations
+}
@@ -2,7 +2,9 @@ package investing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -11,106 +13,420 @@ import (
 	"go.uber.org/zap"
 )
 
+// basketExecutionNamespace derives execution IDs from idempotency keys, so a retried
+// investment maps to the same execution and the same client order IDs
+var basketExecutionNamespace = uuid.MustParse("6f1c2a8e-4b7d-4e2a-9c3f-5d8e7a1b0c42")
+
+// BasketOrderClient places and looks up broker orders
+type BasketOrderClient interface {
+	CreateOrder(ctx context.Context, accountID string, req *entities.AlpacaCreateOrderRequest) (*entities.AlpacaOrderResponse, error)
+	ListOrders(ctx context.Context, accountID string, query map[string]string) ([]entities.AlpacaOrderResponse, error)
+}
+
+// BasketExecutionRepository persists basket executions and their legs
+type BasketExecutionRepository interface {
+	CreateExecution(ctx context.Context, execution *entities.BasketExecution) error
+	GetExecution(ctx context.Context, id uuid.UUID) (*entities.BasketExecution, error)
+	GetExecutionByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*entities.BasketExecution, error)
+	UpdateExecution(ctx context.Context, execution *entities.BasketExecution) error
+	UpdateLeg(ctx context.Context, leg *entities.BasketExecutionLeg) error
+}
+
+// FundsReleaser returns funds reserved for an investment to the user's balance
+type FundsReleaser interface {
+	ReleaseReservation(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error
+}
+
 // BasketExecutor handles batch order execution for investment baskets
 type BasketExecutor struct {
-	alpacaService *alpaca.Service
+	alpacaService BasketOrderClient
+	baskets       BasketRepository
+	repo          BasketExecutionRepository
+	funds         FundsReleaser
 	logger        *zap.Logger
+	maxAttempts   int
+	baseBackoff   time.Duration
 }
 
-func NewBasketExecutor(alpacaService *alpaca.Service, logger *zap.Logger) *BasketExecutor {
+func NewBasketExecutor(alpacaService BasketOrderClient, logger *zap.Logger) *BasketExecutor {
 	return &BasketExecutor{
 		alpacaService: alpacaService,
 		logger:        logger,
+		maxAttempts:   3,
+		baseBackoff:   2 * time.Second,
 	}
 }
 
+// SetBasketRepository sets the repository curated basket compositions are read from
+func (s *BasketExecutor) SetBasketRepository(baskets BasketRepository) {
+	s.baskets = baskets
+}
+
+// SetExecutionRepository sets the repository that persists executions so they can be resumed and reported
+func (s *BasketExecutor) SetExecutionRepository(repo BasketExecutionRepository) {
+	s.repo = repo
+}
+
+// SetFundsReleaser sets the ledger used to release the reserved funds of failed legs
+func (s *BasketExecutor) SetFundsReleaser(funds FundsReleaser) {
+	s.funds = funds
+}
+
+// SetRetryPolicy sets how many times a leg is attempted and the backoff before the first retry,
+// which doubles on each further retry
+func (s *BasketExecutor) SetRetryPolicy(maxAttempts int, baseBackoff time.Duration) {
+	s.maxAttempts = maxAttempts
+	s.baseBackoff = baseBackoff
+}
+
 // BasketAllocation represents a single asset allocation in a basket
 type BasketAllocation struct {
 	Symbol     string
 	Percentage decimal.Decimal // 0-100
 }
 
-// ExecuteBasket places fractional orders for all assets in a basket
-func (s *BasketExecutor) ExecuteBasket(
-	ctx context.Context,
-	alpacaAccountID string,
-	totalAmount decimal.Decimal,
-	allocations []BasketAllocation,
-) ([]*entities.AlpacaOrderResponse, error) {
+// BasketExecutionRequest describes a basket investment whose total amount the caller has already
+// reserved in the ledger. Without allocations, the curated basket's active composition is used.
+// Repeating a request with the same idempotency key resumes the same execution.
+type BasketExecutionRequest struct {
+	UserID          uuid.UUID
+	BasketID        *uuid.UUID
+	AlpacaAccountID string
+	TotalAmount     decimal.Decimal
+	Allocations     []BasketAllocation
+	IdempotencyKey  string
+}
+
+// ExecuteBasket places a fractional market order for each asset in a basket and returns the
+// execution report. Retryable broker errors are retried with backoff; legs that still fail have
+// their share of the reservation released. Calling it again with the same idempotency key resumes
+// an unfinished execution or returns the finished report without placing new orders. When every
+// leg fails the report is returned together with an error.
+func (s *BasketExecutor) ExecuteBasket(ctx context.Context, req *BasketExecutionRequest) (*entities.BasketExecution, error) {
+	if req.IdempotencyKey == "" {
+		return nil, fmt.Errorf("idempotency key is required")
+	}
+	if !req.TotalAmount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if len(req.Allocations) == 0 && req.BasketID != nil && s.baskets != nil {
+		basket, err := s.baskets.GetByID(ctx, *req.BasketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get basket: %w", err)
+		}
+		if basket == nil {
+			return nil, ErrBasketNotFound
+		}
+		req.Allocations = BasketAllocationsFromComposition(basket.Composition)
+	}
+	if len(req.Allocations) == 0 {
+		return nil, fmt.Errorf("basket has no allocations")
+	}
+
+	execution, err := s.loadOrCreateExecution(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if execution.Status.IsTerminal() {
+		return execution, nil
+	}
+
 	s.logger.Info("Executing basket orders",
-		zap.String("alpaca_account_id", alpacaAccountID),
-		zap.String("total_amount", totalAmount.String()),
-		zap.Int("allocations", len(allocations)))
+		zap.String("execution_id", execution.ID.String()),
+		zap.String("alpaca_account_id", execution.AlpacaAccountID),
+		zap.String("total_amount", execution.TotalAmount.String()),
+		zap.Int("legs", len(execution.Legs)))
+
+	if execution.Status == entities.BasketExecutionStatusPending {
+		execution.Status = entities.BasketExecutionStatusExecuting
+		s.saveExecution(ctx, execution)
+	}
+
+	var legErrs []error
+	for _, leg := range execution.Legs {
+		if leg.Status == entities.BasketExecutionLegStatusPending {
+			if err := s.submitLeg(ctx, execution, leg); err != nil {
+				legErrs = append(legErrs, err)
+			}
+		}
+		if leg.Status == entities.BasketExecutionLegStatusFailed && s.funds != nil {
+			if err := s.releaseLeg(ctx, execution, leg); err != nil {
+				legErrs = append(legErrs, err)
+			}
+		}
+	}
+
+	if len(legErrs) > 0 {
+		// Unfinished legs keep the execution open so the next attempt resumes it
+		s.saveExecution(ctx, execution)
+		return execution, fmt.Errorf("basket execution incomplete: %w", errors.Join(legErrs...))
+	}
 
-	orders := make([]*entities.AlpacaOrderResponse, 0, len(allocations))
+	s.completeExecution(ctx, execution)
+	if execution.Status == entities.BasketExecutionStatusFailed {
+		return execution, fmt.Errorf("all basket orders failed")
+	}
+	return execution, nil
+}
+
+// GetExecution returns a user's basket execution report, or nil if it does not exist
+func (s *BasketExecutor) GetExecution(ctx context.Context, userID, executionID uuid.UUID) (*entities.BasketExecution, error) {
+	if s.repo == nil {
+		return nil, fmt.Errorf("basket executions are not persisted")
+	}
+	execution, err := s.repo.GetExecution(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil || execution.UserID != userID {
+		return nil, nil
+	}
+	return execution, nil
+}
+
+func (s *BasketExecutor) loadOrCreateExecution(ctx context.Context, req *BasketExecutionRequest) (*entities.BasketExecution, error) {
+	if s.repo != nil {
+		existing, err := s.repo.GetExecutionByIdempotencyKey(ctx, req.UserID, req.IdempotencyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get basket execution: %w", err)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
 
-	for _, allocation := range allocations {
-		// Calculate dollar amount for this allocation
-		allocationAmount := totalAmount.Mul(allocation.Percentage).Div(decimal.NewFromInt(100))
+	now := time.Now().UTC()
+	execution := &entities.BasketExecution{
+		ID:              uuid.NewSHA1(basketExecutionNamespace, []byte(req.UserID.String()+":"+req.IdempotencyKey)),
+		UserID:          req.UserID,
+		BasketID:        req.BasketID,
+		AlpacaAccountID: req.AlpacaAccountID,
+		IdempotencyKey:  req.IdempotencyKey,
+		TotalAmount:     req.TotalAmount,
+		SubmittedAmount: decimal.Zero,
+		ReleasedAmount:  decimal.Zero,
+		Status:          entities.BasketExecutionStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
 
-		order, err := s.alpacaService.CreateOrder(ctx, alpacaAccountID, &entities.AlpacaCreateOrderRequest{
+	amounts := splitBasketAmount(req.TotalAmount, req.Allocations)
+	for i, allocation := range req.Allocations {
+		if !amounts[i].IsPositive() {
+			continue
+		}
+		execution.Legs = append(execution.Legs, &entities.BasketExecutionLeg{
+			ID:            uuid.New(),
+			ExecutionID:   execution.ID,
 			Symbol:        allocation.Symbol,
-			Notional:      &allocationAmount,
+			Percentage:    allocation.Percentage,
+			Amount:        amounts[i],
+			ClientOrderID: fmt.Sprintf("basket-%s-%s", execution.ID, allocation.Symbol),
+			Status:        entities.BasketExecutionLegStatusPending,
+			UpdatedAt:     now,
+		})
+	}
+
+	if s.repo != nil {
+		if err := s.repo.CreateExecution(ctx, execution); err != nil {
+			return nil, fmt.Errorf("failed to create basket execution: %w", err)
+		}
+	}
+	return execution, nil
+}
+
+// splitBasketAmount divides the total by allocation percentage in whole cents, giving the
+// rounding residual to the largest allocation so the legs add up to the total
+func splitBasketAmount(total decimal.Decimal, allocations []BasketAllocation) []decimal.Decimal {
+	amounts := make([]decimal.Decimal, len(allocations))
+	allocated := decimal.Zero
+	largest := 0
+	for i, allocation := range allocations {
+		amounts[i] = total.Mul(allocation.Percentage).Div(decimal.NewFromInt(100)).Truncate(2)
+		allocated = allocated.Add(amounts[i])
+		if allocation.Percentage.GreaterThan(allocations[largest].Percentage) {
+			largest = i
+		}
+	}
+	amounts[largest] = amounts[largest].Add(total.Sub(allocated))
+	return amounts
+}
+
+// submitLeg places a leg's order, retrying retryable broker errors and transport errors such as
+// timeouts with backoff. Either may hide an order the broker accepted, so before each retry, and
+// before giving up, it looks for an order placed under the deterministic client order ID, which
+// is therefore never submitted twice. Only an outright broker rejection fails the leg at once. It
+// returns an error only when the leg is left pending.
+func (s *BasketExecutor) submitLeg(ctx context.Context, execution *entities.BasketExecution, leg *entities.BasketExecutionLeg) error {
+	for leg.Status == entities.BasketExecutionLegStatusPending {
+		if leg.Attempts > 0 {
+			order, err := s.findOrder(ctx, execution, leg)
+			if err != nil {
+				// Without the lookup an earlier attempt may have been placed, so neither resubmit nor release
+				return fmt.Errorf("failed to look up order for %s: %w", leg.Symbol, err)
+			}
+			if order != nil {
+				s.transitionLeg(ctx, execution, leg, entities.BasketExecutionLegStatusSubmitted, order)
+				return nil
+			}
+			if leg.Attempts >= s.maxAttempts {
+				s.transitionLeg(ctx, execution, leg, entities.BasketExecutionLegStatusFailed, nil)
+				return nil
+			}
+
+			select {
+			case <-time.After(s.baseBackoff * time.Duration(1<<(leg.Attempts-1))):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		leg.Attempts++
+		amount := leg.Amount
+		order, err := s.alpacaService.CreateOrder(ctx, execution.AlpacaAccountID, &entities.AlpacaCreateOrderRequest{
+			Symbol:        leg.Symbol,
+			Notional:      &amount,
 			Side:          entities.AlpacaOrderSideBuy,
 			Type:          entities.AlpacaOrderTypeMarket,
 			TimeInForce:   entities.AlpacaTimeInForceDay,
 			ExtendedHours: false,
-			ClientOrderID: uuid.New().String(),
+			ClientOrderID: leg.ClientOrderID,
 		})
+		if err == nil {
+			s.transitionLeg(ctx, execution, leg, entities.BasketExecutionLegStatusSubmitted, order)
+			return nil
+		}
 
-		if err != nil {
-			s.logger.Error("Failed to place basket order",
-				zap.String("symbol", allocation.Symbol),
-				zap.String("amount", allocationAmount.String()),
-				zap.Error(err))
-			// Continue with other orders even if one fails
-			continue
+		errMsg := err.Error()
+		leg.LastError = &errMsg
+		s.logger.Warn("Basket order attempt failed",
+			zap.String("execution_id", execution.ID.String()),
+			zap.String("symbol", leg.Symbol),
+			zap.String("amount", leg.Amount.String()),
+			zap.Int("attempt", leg.Attempts),
+			zap.Error(err))
+
+		var alpacaErr alpaca.AlpacaError
+		if errors.As(err, &alpacaErr) && !alpacaErr.IsRetryable() {
+			s.transitionLeg(ctx, execution, leg, entities.BasketExecutionLegStatusFailed, nil)
+			return nil
 		}
+		// The outcome is unknown, so the next pass looks the order up before resubmitting or failing
+		s.saveLeg(ctx, leg)
+	}
+	return nil
+}
 
+// findOrder returns the broker order placed with a leg's client order ID, or nil if there is none
+func (s *BasketExecutor) findOrder(ctx context.Context, execution *entities.BasketExecution, leg *entities.BasketExecutionLeg) (*entities.AlpacaOrderResponse, error) {
+	orders, err := s.alpacaService.ListOrders(ctx, execution.AlpacaAccountID, map[string]string{
+		"status":  "all",
+		"symbols": leg.Symbol,
+		"after":   execution.CreatedAt.Add(-time.Minute).Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if orders[i].ClientOrderID == leg.ClientOrderID {
+			return &orders[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// releaseLeg returns a failed leg's share of the reservation to the user's balance
+func (s *BasketExecutor) releaseLeg(ctx context.Context, execution *entities.BasketExecution, leg *entities.BasketExecutionLeg) error {
+	if err := s.funds.ReleaseReservation(ctx, execution.UserID, leg.Amount); err != nil {
+		s.logger.Error("Failed to release funds for basket order",
+			zap.String("execution_id", execution.ID.String()),
+			zap.String("symbol", leg.Symbol),
+			zap.String("amount", leg.Amount.String()),
+			zap.Error(err))
+		return fmt.Errorf("failed to release funds for %s: %w", leg.Symbol, err)
+	}
+	s.transitionLeg(ctx, execution, leg, entities.BasketExecutionLegStatusReleased, nil)
+	return nil
+}
+
+// transitionLeg moves a leg to its next status, updates the execution totals and persists the leg
+func (s *BasketExecutor) transitionLeg(ctx context.Context, execution *entities.BasketExecution, leg *entities.BasketExecutionLeg, status entities.BasketExecutionLegStatus, order *entities.AlpacaOrderResponse) {
+	if err := leg.Status.ValidateTransition(status); err != nil {
+		s.logger.Error("Invalid basket leg transition", zap.String("symbol", leg.Symbol), zap.Error(err))
+		return
+	}
+
+	now := time.Now().UTC()
+	leg.Status = status
+	switch status {
+	case entities.BasketExecutionLegStatusSubmitted:
+		leg.AlpacaOrderID = &order.ID
+		leg.SubmittedAt = &now
+		leg.LastError = nil
+		execution.SubmittedAmount = execution.SubmittedAmount.Add(leg.Amount)
 		s.logger.Info("Basket order placed",
-			zap.String("symbol", allocation.Symbol),
+			zap.String("execution_id", execution.ID.String()),
+			zap.String("symbol", leg.Symbol),
 			zap.String("order_id", order.ID),
-			zap.String("amount", allocationAmount.String()))
+			zap.String("amount", leg.Amount.String()))
+	case entities.BasketExecutionLegStatusReleased:
+		leg.ReleasedAt = &now
+		execution.ReleasedAmount = execution.ReleasedAmount.Add(leg.Amount)
+	}
+	s.saveLeg(ctx, leg)
+}
 
-		orders = append(orders, order)
+// completeExecution records the final status once every leg is submitted or, for failed legs, released
+func (s *BasketExecutor) completeExecution(ctx context.Context, execution *entities.BasketExecution) {
+	submitted := 0
+	for _, leg := range execution.Legs {
+		if leg.Status == entities.BasketExecutionLegStatusSubmitted {
+			submitted++
+		}
 	}
 
-	if len(orders) == 0 {
-		return nil, fmt.Errorf("all basket orders failed")
+	status := entities.BasketExecutionStatusPartiallyCompleted
+	switch submitted {
+	case len(execution.Legs):
+		status = entities.BasketExecutionStatusCompleted
+	case 0:
+		status = entities.BasketExecutionStatusFailed
+	}
+	if err := execution.Status.ValidateTransition(status); err != nil {
+		s.logger.Error("Invalid basket execution transition", zap.String("execution_id", execution.ID.String()), zap.Error(err))
+		return
 	}
 
+	now := time.Now().UTC()
+	execution.Status = status
+	execution.CompletedAt = &now
+	s.saveExecution(ctx, execution)
+
 	s.logger.Info("Basket execution completed",
-		zap.Int("successful_orders", len(orders)),
-		zap.Int("total_allocations", len(allocations)))
-
-	return orders, nil
-}
-
-// GetBasketAllocations returns predefined basket allocations
-func GetBasketAllocations(basketType string) []BasketAllocation {
-	baskets := map[string][]BasketAllocation{
-		"tech-growth": {
-			{Symbol: "AAPL", Percentage: decimal.NewFromInt(20)},
-			{Symbol: "MSFT", Percentage: decimal.NewFromInt(20)},
-			{Symbol: "GOOGL", Percentage: decimal.NewFromInt(15)},
-			{Symbol: "NVDA", Percentage: decimal.NewFromInt(15)},
-			{Symbol: "TSLA", Percentage: decimal.NewFromInt(15)},
-			{Symbol: "META", Percentage: decimal.NewFromInt(15)},
-		},
-		"sustainability": {
-			{Symbol: "ICLN", Percentage: decimal.NewFromInt(30)}, // Clean energy ETF
-			{Symbol: "TAN", Percentage: decimal.NewFromInt(25)},  // Solar ETF
-			{Symbol: "TSLA", Percentage: decimal.NewFromInt(20)},
-			{Symbol: "NEE", Percentage: decimal.NewFromInt(15)},  // NextEra Energy
-			{Symbol: "ENPH", Percentage: decimal.NewFromInt(10)}, // Enphase Energy
-		},
-		"balanced-etf": {
-			{Symbol: "SPY", Percentage: decimal.NewFromInt(40)},  // S&P 500
-			{Symbol: "QQQ", Percentage: decimal.NewFromInt(30)},  // Nasdaq 100
-			{Symbol: "VTI", Percentage: decimal.NewFromInt(20)},  // Total market
-			{Symbol: "AGG", Percentage: decimal.NewFromInt(10)},  // Bond ETF
-		},
-	}
-
-	return baskets[basketType]
+		zap.String("execution_id", execution.ID.String()),
+		zap.String("status", string(status)),
+		zap.Int("submitted_legs", submitted),
+		zap.Int("total_legs", len(execution.Legs)),
+		zap.String("submitted_amount", execution.SubmittedAmount.String()),
+		zap.String("released_amount", execution.ReleasedAmount.String()))
+}
+
+func (s *BasketExecutor) saveExecution(ctx context.Context, execution *entities.BasketExecution) {
+	execution.UpdatedAt = time.Now().UTC()
+	if s.repo == nil {
+		return
+	}
+	if err := s.repo.UpdateExecution(ctx, execution); err != nil {
+		s.logger.Error("Failed to save basket execution", zap.String("execution_id", execution.ID.String()), zap.Error(err))
+	}
+}
+
+func (s *BasketExecutor) saveLeg(ctx context.Context, leg *entities.BasketExecutionLeg) {
+	leg.UpdatedAt = time.Now().UTC()
+	if s.repo == nil {
+		return
+	}
+	if err := s.repo.UpdateLeg(ctx, leg); err != nil {
+		s.logger.Error("Failed to save basket execution leg", zap.String("leg_id", leg.ID.String()), zap.Error(err))
+	}
 }
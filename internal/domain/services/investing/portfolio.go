@@ -2,7 +2,7 @@ package investing
 
 import (
 	"context"
-	"time"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -10,34 +10,65 @@ import (
 	"go.uber.org/zap"
 )
 
+// TaxReporter totals a user's realized gains from their tax lots
+type TaxReporter interface {
+	GenerateRealizedGainsReport(ctx context.Context, userID uuid.UUID, year int) (*entities.RealizedGainsReport, error)
+}
+
 type PortfolioService struct {
-	logger *zap.Logger
+	taxReporter TaxReporter
+	logger      *zap.Logger
 }
 
 func NewPortfolioService(logger *zap.Logger) *PortfolioService {
 	return &PortfolioService{logger: logger}
 }
 
-func (s *PortfolioService) CalculateRebalance(ctx context.Context, portfolioID uuid.UUID, target, current map[string]decimal.Decimal) ([]entities.RebalanceTrade, error) {
-	trades := []entities.RebalanceTrade{}
-	
-	for symbol, targetPct := range target {
-		currentPct := current[symbol]
-		diff := targetPct.Sub(currentPct)
-		
-		if diff.Abs().GreaterThan(decimal.NewFromFloat(0.05)) {
-			action := "buy"
-			if diff.IsNegative() {
-				action = "sell"
-			}
-			
-			trades = append(trades, entities.RebalanceTrade{
-				Symbol:   symbol,
-				Action:   action,
-				Quantity: diff.Abs(),
-				Price:    decimal.Zero,
-			})
+// SetTaxReporter sets the realized gains source for tax reports
+func (s *PortfolioService) SetTaxReporter(taxReporter TaxReporter) {
+	s.taxReporter = taxReporter
+}
+
+// CalculateRebalance returns the trades that bring a portfolio's current market values to the
+// target weights (percentages summing to 100) at the given prices. It uses the same rules as
+// RebalancingService: drift within 5 percentage points is tolerated and positions on a gain are
+// trimmed only to the edge of that band. Quantity is a number of shares.
+func (s *PortfolioService) CalculateRebalance(ctx context.Context, portfolioID uuid.UUID, target, current, prices map[string]decimal.Decimal) ([]entities.RebalanceTrade, error) {
+	holdings := make(map[string]*rebalanceHolding)
+	for symbol, value := range current {
+		holdings[symbol] = &rebalanceHolding{symbol: symbol, value: value, price: prices[symbol], fractionable: true}
+	}
+	for symbol, pct := range target {
+		if _, ok := holdings[symbol]; !ok {
+			holdings[symbol] = &rebalanceHolding{symbol: symbol, price: prices[symbol], fractionable: true}
+		}
+		holdings[symbol].targetPct = pct
+	}
+	list := make([]*rebalanceHolding, 0, len(holdings))
+	for _, h := range holdings {
+		if h.price.IsPositive() {
+			h.quantity = h.value.Div(h.price)
 		}
+		list = append(list, h)
+	}
+
+	plan, err := planRebalance(list, rebalanceOptions{
+		thresholdPct:   decimal.NewFromInt(5),
+		minTradeAmount: DefaultMinRebalanceTrade,
+		allowSells:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]entities.RebalanceTrade, 0, len(plan.Trades))
+	for _, trade := range plan.Trades {
+		trades = append(trades, entities.RebalanceTrade{
+			Symbol:   trade.Symbol,
+			Action:   trade.Side,
+			Quantity: trade.EstimatedQty,
+			Price:    prices[trade.Symbol],
+		})
 	}
 	
 	s.logger.Info("Rebalance calculated", 
@@ -47,22 +78,33 @@ func (s *PortfolioService) CalculateRebalance(ctx context.Context, portfolioID u
 	return trades, nil
 }
 
+// GenerateTaxReport summarizes a user's realized gains for a tax year. The itemized report is
+// downloadable from ReportURL.
 func (s *PortfolioService) GenerateTaxReport(ctx context.Context, userID uuid.UUID, year int) (*entities.TaxReport, error) {
+	if s.taxReporter == nil {
+		return nil, fmt.Errorf("tax lot tracking not configured")
+	}
+	gains, err := s.taxReporter.GenerateRealizedGainsReport(ctx, userID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate realized gains report: %w", err)
+	}
+
 	report := &entities.TaxReport{
 		ID:             uuid.New(),
 		UserID:         userID,
 		TaxYear:        year,
-		TotalGains:     decimal.Zero,
-		TotalLosses:    decimal.Zero,
-		ShortTermGains: decimal.Zero,
-		LongTermGains:  decimal.Zero,
-		ReportURL:      "tax_report_url",
-		GeneratedAt:    time.Now(),
+		TotalGains:     gains.TotalGains,
+		TotalLosses:    gains.TotalLosses,
+		ShortTermGains: gains.ShortTermGainLoss,
+		LongTermGains:  gains.LongTermGainLoss,
+		ReportURL:      fmt.Sprintf("/api/v1/tax/realized-gains?year=%d&format=csv", year),
+		GeneratedAt:    gains.GeneratedAt,
 	}
 	
 	s.logger.Info("Tax report generated", 
 		zap.String("user_id", userID.String()),
-		zap.Int("year", year))
+		zap.Int("year", year),
+		zap.Int("realized_gains", len(gains.Gains)))
 	
 	return report, nil
 }
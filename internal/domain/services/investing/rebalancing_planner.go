@@ -0,0 +1,387 @@
+package investing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// DefaultMinRebalanceTrade is the smallest trade a rebalance places, matching the broker's $1 notional minimum
+var DefaultMinRebalanceTrade = decimal.NewFromInt(1)
+
+var hundredPct = decimal.NewFromInt(100)
+
+// rebalanceHolding is one symbol's position, price and target weight in a rebalance
+type rebalanceHolding struct {
+	symbol       string
+	value        decimal.Decimal
+	quantity     decimal.Decimal
+	costBasis    decimal.Decimal // Zero when unknown
+	price        decimal.Decimal // Zero when no quote is available
+	targetPct    decimal.Decimal
+	fractionable bool
+}
+
+// gainRatio is the fraction of a sale's proceeds expected to be realized gain, or zero when the
+// cost basis is unknown
+func (h *rebalanceHolding) gainRatio() decimal.Decimal {
+	if h.costBasis.IsZero() || !h.value.IsPositive() {
+		return decimal.Zero
+	}
+	return h.value.Sub(h.costBasis).Div(h.value)
+}
+
+// rebalanceOptions controls how a plan trades
+type rebalanceOptions struct {
+	thresholdPct   decimal.Decimal // Drift in percentage points tolerated before selling
+	cashFlow       decimal.Decimal // New cash to invest before anything is sold
+	minTradeAmount decimal.Decimal
+	allowSells     bool // False invests the cash flow only
+}
+
+// rebalanceTrade accumulates what a plan buys or sells of one symbol before sizing rules apply
+type rebalanceTrade struct {
+	holding    *rebalanceHolding
+	cashBuy    decimal.Decimal
+	fundedBuy  decimal.Decimal
+	sell       decimal.Decimal
+	priority   decimal.Decimal // Buy deficit, larger is filled first when funds fall short
+	sellReason string
+}
+
+// planRebalance builds a rebalancing plan that invests new cash into underweight assets before
+// selling anything, only sells when drift after the cash flow exceeds the threshold, trims
+// positions sitting on gains to the edge of their band rather than to target, funds any
+// remaining shortfall from the positions with the lowest gains, and drops trades below the
+// minimum size or smaller than one share of a non-fractionable asset.
+func planRebalance(holdings []*rebalanceHolding, opts rebalanceOptions) (*entities.RebalancingPlan, error) {
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].symbol < holdings[j].symbol })
+
+	invested := decimal.Zero
+	for _, h := range holdings {
+		invested = invested.Add(h.value)
+	}
+	total := invested.Add(opts.cashFlow)
+	if !total.IsPositive() {
+		return nil, fmt.Errorf("portfolio has no value")
+	}
+
+	plan := &entities.RebalancingPlan{
+		CurrentAllocations:   make(map[string]decimal.Decimal),
+		TargetAllocations:    make(map[string]decimal.Decimal),
+		ProjectedAllocations: make(map[string]decimal.Decimal),
+		Trades:               []entities.RebalanceTradeOrder{},
+		CashFlow:             opts.cashFlow,
+	}
+	projected := make(map[string]decimal.Decimal, len(holdings))
+	trades := make(map[string]*rebalanceTrade, len(holdings))
+	for _, h := range holdings {
+		if invested.IsPositive() && h.value.IsPositive() {
+			plan.CurrentAllocations[h.symbol] = h.value.Div(invested).Mul(hundredPct)
+		}
+		if h.targetPct.IsPositive() {
+			plan.TargetAllocations[h.symbol] = h.targetPct
+		}
+		projected[h.symbol] = h.value
+		trades[h.symbol] = &rebalanceTrade{holding: h}
+	}
+	targetValue := func(h *rebalanceHolding) decimal.Decimal {
+		return h.targetPct.Div(hundredPct).Mul(total)
+	}
+	driftPct := func(h *rebalanceHolding) decimal.Decimal {
+		return projected[h.symbol].Div(total).Mul(hundredPct).Sub(h.targetPct)
+	}
+	underweight := func() []*rebalanceHolding {
+		var under []*rebalanceHolding
+		for _, h := range holdings {
+			if targetValue(h).GreaterThan(projected[h.symbol]) {
+				under = append(under, h)
+			}
+		}
+		sort.SliceStable(under, func(i, j int) bool {
+			return targetValue(under[i]).Sub(projected[under[i].symbol]).GreaterThan(targetValue(under[j]).Sub(projected[under[j].symbol]))
+		})
+		return under
+	}
+
+	// New cash goes to the most underweight assets first, then at target weights once none are
+	remaining := opts.cashFlow
+	for _, h := range underweight() {
+		if !remaining.IsPositive() {
+			break
+		}
+		deficit := targetValue(h).Sub(projected[h.symbol])
+		amount := decimal.Min(deficit, remaining)
+		trades[h.symbol].cashBuy = trades[h.symbol].cashBuy.Add(amount)
+		trades[h.symbol].priority = deficit
+		projected[h.symbol] = projected[h.symbol].Add(amount)
+		remaining = remaining.Sub(amount)
+	}
+	if remaining.IsPositive() {
+		spread := remaining
+		for _, h := range holdings {
+			if !h.targetPct.IsPositive() {
+				continue
+			}
+			amount := spread.Mul(h.targetPct).Div(hundredPct)
+			trades[h.symbol].cashBuy = trades[h.symbol].cashBuy.Add(amount)
+			projected[h.symbol] = projected[h.symbol].Add(amount)
+		}
+	}
+
+	if opts.allowSells {
+		planSells(holdings, opts, total, projected, trades, targetValue, driftPct, underweight)
+	}
+
+	finalizeRebalancePlan(plan, holdings, trades, opts, invested, total)
+	return plan, nil
+}
+
+// planSells sells only when drift after the cash flow exceeds the threshold and spends the proceeds
+// on underweight assets
+func planSells(
+	holdings []*rebalanceHolding,
+	opts rebalanceOptions,
+	total decimal.Decimal,
+	projected map[string]decimal.Decimal,
+	trades map[string]*rebalanceTrade,
+	targetValue, driftPct func(*rebalanceHolding) decimal.Decimal,
+	underweight func() []*rebalanceHolding,
+) {
+	proceeds := decimal.Zero
+	sell := func(h *rebalanceHolding, amount decimal.Decimal, reason string) {
+		trades[h.symbol].sell = trades[h.symbol].sell.Add(amount)
+		if trades[h.symbol].sellReason == "" {
+			trades[h.symbol].sellReason = reason
+		}
+		projected[h.symbol] = projected[h.symbol].Sub(amount)
+		proceeds = proceeds.Add(amount)
+	}
+
+	// Overweight beyond the band: positions on a gain are trimmed to the band edge, others to target
+	for _, h := range holdings {
+		if !driftPct(h).GreaterThan(opts.thresholdPct) {
+			continue
+		}
+		floorPct := h.targetPct
+		reason := fmt.Sprintf("Sells %s down to its %s%% target", h.symbol, h.targetPct.StringFixed(2))
+		if h.targetPct.IsZero() {
+			reason = fmt.Sprintf("Sells %s, which is not in the target allocation", h.symbol)
+		}
+		if h.gainRatio().IsPositive() {
+			floorPct = h.targetPct.Add(opts.thresholdPct)
+			reason = fmt.Sprintf("Trims %s to the edge of its %s%% drift band instead of its %s%% target to limit realized gains",
+				h.symbol, opts.thresholdPct.StringFixed(2), h.targetPct.StringFixed(2))
+		}
+		sell(h, projected[h.symbol].Sub(floorPct.Div(hundredPct).Mul(total)), reason)
+	}
+
+	// Underweight beyond the band must at least reach its edge; make up any shortfall by selling
+	// the overweight positions with the lowest gains first
+	required := decimal.Zero
+	for _, h := range holdings {
+		if driftPct(h).LessThan(opts.thresholdPct.Neg()) {
+			edge := h.targetPct.Sub(opts.thresholdPct).Div(hundredPct).Mul(total)
+			required = required.Add(edge.Sub(projected[h.symbol]))
+		}
+	}
+	if shortfall := required.Sub(proceeds); shortfall.IsPositive() {
+		var overweight []*rebalanceHolding
+		for _, h := range holdings {
+			if projected[h.symbol].GreaterThan(targetValue(h)) {
+				overweight = append(overweight, h)
+			}
+		}
+		sort.SliceStable(overweight, func(i, j int) bool {
+			return overweight[i].gainRatio().LessThan(overweight[j].gainRatio())
+		})
+		for _, h := range overweight {
+			if !shortfall.IsPositive() {
+				break
+			}
+			amount := decimal.Min(projected[h.symbol].Sub(targetValue(h)), shortfall)
+			sell(h, amount, fmt.Sprintf("Sells %s, one of the lowest-gain overweight positions, to fund underweight assets", h.symbol))
+			shortfall = shortfall.Sub(amount)
+		}
+	}
+
+	// Proceeds go to the most underweight assets first
+	for _, h := range underweight() {
+		if !proceeds.IsPositive() {
+			break
+		}
+		deficit := targetValue(h).Sub(projected[h.symbol])
+		amount := decimal.Min(deficit, proceeds)
+		trades[h.symbol].fundedBuy = trades[h.symbol].fundedBuy.Add(amount)
+		if deficit.GreaterThan(trades[h.symbol].priority) {
+			trades[h.symbol].priority = deficit
+		}
+		projected[h.symbol] = projected[h.symbol].Add(amount)
+		proceeds = proceeds.Sub(amount)
+	}
+}
+
+// finalizeRebalancePlan nets each symbol's buys and sells into one trade, applies minimum size and
+// whole-share rules, caps buys at the cash actually available and explains each trade
+func finalizeRebalancePlan(
+	plan *entities.RebalancingPlan,
+	holdings []*rebalanceHolding,
+	trades map[string]*rebalanceTrade,
+	opts rebalanceOptions,
+	invested, total decimal.Decimal,
+) {
+	final := make(map[string]decimal.Decimal, len(holdings))
+	for _, h := range holdings {
+		final[h.symbol] = h.value
+	}
+
+	// Sells first: what they raise, with the cash flow, is the budget for buys
+	budget := opts.cashFlow
+	var buys []*rebalanceTrade
+	for _, h := range holdings {
+		t := trades[h.symbol]
+		net := t.cashBuy.Add(t.fundedBuy).Sub(t.sell)
+		if net.IsPositive() {
+			buys = append(buys, t)
+			continue
+		}
+		if !net.IsNegative() {
+			continue
+		}
+		amount, qty, ok := sizeRebalanceTrade(plan, h, net.Neg(), "sell", opts.minTradeAmount)
+		if !ok {
+			continue
+		}
+		gain := amount.Mul(h.gainRatio()).Round(2)
+		budget = budget.Add(amount)
+		final[h.symbol] = final[h.symbol].Sub(amount)
+		plan.TotalSellAmount = plan.TotalSellAmount.Add(amount)
+		plan.EstimatedRealizedGain = plan.EstimatedRealizedGain.Add(gain)
+		plan.Trades = append(plan.Trades, entities.RebalanceTradeOrder{
+			Symbol:        h.symbol,
+			Side:          "sell",
+			CurrentPct:    plan.CurrentAllocations[h.symbol],
+			TargetPct:     h.targetPct,
+			DriftPct:      plan.CurrentAllocations[h.symbol].Sub(h.targetPct),
+			Amount:        amount,
+			EstimatedQty:  qty,
+			WholeShares:   !h.fractionable,
+			EstimatedGain: gain,
+			Reason:        fmt.Sprintf("%s; estimated realized gain $%s", t.sellReason, gain.StringFixed(2)),
+		})
+	}
+
+	// Buys in order of how far below target they are, within the budget
+	sort.SliceStable(buys, func(i, j int) bool { return buys[i].priority.GreaterThan(buys[j].priority) })
+	for _, t := range buys {
+		h := t.holding
+		want := decimal.Min(t.cashBuy.Add(t.fundedBuy).Sub(t.sell), budget).RoundDown(2)
+		amount, qty, ok := sizeRebalanceTrade(plan, h, want, "buy", opts.minTradeAmount)
+		if !ok {
+			continue
+		}
+		budget = budget.Sub(amount)
+		final[h.symbol] = final[h.symbol].Add(amount)
+		plan.TotalBuyAmount = plan.TotalBuyAmount.Add(amount)
+
+		fromCash := decimal.Min(t.cashBuy, amount)
+		var reason string
+		switch {
+		case fromCash.Equal(amount):
+			reason = fmt.Sprintf("Directs $%s of new cash into %s", amount.StringFixed(2), h.symbol)
+		case fromCash.IsPositive():
+			reason = fmt.Sprintf("Buys %s with $%s of new cash and $%s of sale proceeds",
+				h.symbol, fromCash.StringFixed(2), amount.Sub(fromCash).StringFixed(2))
+		default:
+			reason = fmt.Sprintf("Buys %s with sale proceeds", h.symbol)
+		}
+		plan.Trades = append(plan.Trades, entities.RebalanceTradeOrder{
+			Symbol:         h.symbol,
+			Side:           "buy",
+			CurrentPct:     plan.CurrentAllocations[h.symbol],
+			TargetPct:      h.targetPct,
+			DriftPct:       h.targetPct.Sub(plan.CurrentAllocations[h.symbol]),
+			Amount:         amount,
+			EstimatedQty:   qty,
+			WholeShares:    !h.fractionable,
+			CashFlowAmount: fromCash,
+			Reason:         fmt.Sprintf("%s toward its %s%% target", reason, h.targetPct.StringFixed(2)),
+		})
+	}
+	plan.UninvestedCash = budget.Round(2)
+
+	for _, h := range holdings {
+		if final[h.symbol].IsPositive() {
+			plan.ProjectedAllocations[h.symbol] = final[h.symbol].Div(total).Mul(hundredPct).Round(2)
+		}
+	}
+	for i := range plan.Trades {
+		plan.Trades[i].ProjectedPct = plan.ProjectedAllocations[plan.Trades[i].Symbol]
+	}
+	plan.Summary = summarizeRebalancePlan(plan, opts)
+}
+
+// sizeRebalanceTrade applies whole-share and minimum size rules to a trade amount, recording the
+// trade as skipped when nothing is left of it
+func sizeRebalanceTrade(plan *entities.RebalancingPlan, h *rebalanceHolding, amount decimal.Decimal, side string, minTrade decimal.Decimal) (decimal.Decimal, decimal.Decimal, bool) {
+	skip := func(reason string) (decimal.Decimal, decimal.Decimal, bool) {
+		plan.Skipped = append(plan.Skipped, entities.RebalanceSkippedTrade{
+			Symbol: h.symbol,
+			Side:   side,
+			Amount: amount.Round(2),
+			Reason: reason,
+		})
+		return decimal.Zero, decimal.Zero, false
+	}
+
+	qty := decimal.Zero
+	if h.price.IsPositive() {
+		qty = amount.Div(h.price)
+		if side == "sell" && qty.GreaterThan(h.quantity) && h.quantity.IsPositive() {
+			qty = h.quantity
+		}
+		if !h.fractionable {
+			qty = qty.Floor()
+			if qty.IsZero() {
+				return skip(fmt.Sprintf("%s is not fractionable and the trade is less than one share", h.symbol))
+			}
+			amount = qty.Mul(h.price)
+		} else {
+			qty = qty.Round(6)
+		}
+	} else if !h.fractionable {
+		return skip(fmt.Sprintf("No price for %s to size a whole-share order", h.symbol))
+	}
+
+	amount = amount.Round(2)
+	if amount.LessThan(minTrade) {
+		return skip(fmt.Sprintf("Below the $%s minimum trade", minTrade.StringFixed(2)))
+	}
+	return amount, qty, true
+}
+
+// summarizeRebalancePlan explains the plan in one sentence
+func summarizeRebalancePlan(plan *entities.RebalancingPlan, opts rebalanceOptions) string {
+	if len(plan.Trades) == 0 {
+		if opts.cashFlow.IsPositive() {
+			return "No trades: the new cash is too small to place any trade"
+		}
+		return fmt.Sprintf("No trades: every asset is within its %s%% drift band", opts.thresholdPct.StringFixed(2))
+	}
+
+	var parts []string
+	if opts.cashFlow.IsPositive() {
+		parts = append(parts, fmt.Sprintf("directs $%s of new cash into underweight assets", opts.cashFlow.Sub(plan.UninvestedCash).StringFixed(2)))
+	}
+	if plan.TotalSellAmount.IsPositive() {
+		parts = append(parts, fmt.Sprintf("sells $%s with an estimated realized gain of $%s",
+			plan.TotalSellAmount.StringFixed(2), plan.EstimatedRealizedGain.StringFixed(2)))
+	} else {
+		parts = append(parts, "sells nothing")
+	}
+	return fmt.Sprintf("%d trade(s): %s", len(plan.Trades), strings.Join(parts, " and "))
+}
@@ -30,13 +30,20 @@ type QuoteProvider interface {
 	GetQuotes(ctx context.Context, symbols []string) (map[string]*entities.MarketQuote, error)
 }
 
+// QuantityOrderPlacer places orders for a number of shares, needed for assets that are not fractionable
+type QuantityOrderPlacer interface {
+	PlaceQuantityOrder(ctx context.Context, userID uuid.UUID, symbol string, side entities.AlpacaOrderSide, quantity decimal.Decimal) (*entities.InvestmentOrder, error)
+}
+
 // RebalancingService handles portfolio rebalancing
 type RebalancingService struct {
-	configRepo    RebalancingConfigRepository
-	positionRepo  PositionProvider
-	quoteProvider QuoteProvider
-	orderPlacer   OrderPlacer
-	logger        *zap.Logger
+	configRepo          RebalancingConfigRepository
+	positionRepo        PositionProvider
+	quoteProvider       QuoteProvider
+	orderPlacer         OrderPlacer
+	quantityOrderPlacer QuantityOrderPlacer
+	assets              AssetLookup
+	logger              *zap.Logger
 }
 
 func NewRebalancingService(
@@ -55,6 +62,16 @@ func NewRebalancingService(
 	}
 }
 
+// SetAssetLookup sets the broker asset lookup used to size trades in non-fractionable assets as whole shares
+func (s *RebalancingService) SetAssetLookup(assets AssetLookup) {
+	s.assets = assets
+}
+
+// SetQuantityOrderPlacer sets the order placer used for whole-share trades
+func (s *RebalancingService) SetQuantityOrderPlacer(placer QuantityOrderPlacer) {
+	s.quantityOrderPlacer = placer
+}
+
 // CreateRebalancingConfig creates a new rebalancing configuration
 func (s *RebalancingService) CreateRebalancingConfig(ctx context.Context, userID uuid.UUID, name string, allocations map[string]decimal.Decimal, thresholdPct decimal.Decimal, frequency *string) (*entities.RebalancingConfig, error) {
 	// Validate allocations sum to 100
@@ -138,6 +155,12 @@ func (s *RebalancingService) DeleteConfig(ctx context.Context, id uuid.UUID) err
 
 // GenerateRebalancingPlan calculates trades needed to rebalance
 func (s *RebalancingService) GenerateRebalancingPlan(ctx context.Context, userID uuid.UUID, configID uuid.UUID) (*entities.RebalancingPlan, error) {
+	return s.GenerateRebalancingPlanWithCashFlow(ctx, userID, configID, decimal.Zero)
+}
+
+// GenerateRebalancingPlanWithCashFlow calculates trades that invest cashFlow into underweight
+// assets first and then sell only what drift beyond the config's threshold still requires
+func (s *RebalancingService) GenerateRebalancingPlanWithCashFlow(ctx context.Context, userID uuid.UUID, configID uuid.UUID, cashFlow decimal.Decimal) (*entities.RebalancingPlan, error) {
 	config, err := s.configRepo.GetByID(ctx, configID)
 	if err != nil {
 		return nil, err
@@ -145,104 +168,160 @@ func (s *RebalancingService) GenerateRebalancingPlan(ctx context.Context, userID
 	if config == nil {
 		return nil, fmt.Errorf("config not found")
 	}
-
-	positions, err := s.positionRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get positions: %w", err)
+	if cashFlow.IsNegative() {
+		return nil, fmt.Errorf("cash flow cannot be negative")
 	}
 
-	// Calculate total portfolio value
-	var totalValue decimal.Decimal
-	positionMap := make(map[string]*entities.InvestmentPosition)
-	for _, pos := range positions {
-		totalValue = totalValue.Add(pos.MarketValue)
-		positionMap[pos.Symbol] = pos
+	return s.planForConfig(ctx, userID, config, cashFlow, true)
+}
+
+// InvestCashFlow invests new cash from a deposit, round-up or dividend into the most underweight
+// assets of the user's active rebalancing config without selling anything. Alongside the orders it
+// returns the amount they invested, which falls short of the cash flow by whatever trade minimums
+// and whole-share rounding leave over, or by the unplaced trades when an order fails part way. It
+// returns no orders when the user has no active config.
+func (s *RebalancingService) InvestCashFlow(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, source entities.CashFlowSource) ([]*entities.InvestmentOrder, decimal.Decimal, error) {
+	if !amount.IsPositive() {
+		return nil, decimal.Zero, fmt.Errorf("cash flow must be positive")
 	}
 
-	if totalValue.IsZero() {
-		return nil, fmt.Errorf("portfolio has no value")
+	config, err := s.activeConfig(ctx, userID)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+	if config == nil {
+		return nil, decimal.Zero, nil
 	}
 
-	// Calculate current allocations
-	currentAllocs := make(map[string]decimal.Decimal)
-	for _, pos := range positions {
-		currentAllocs[pos.Symbol] = pos.MarketValue.Div(totalValue).Mul(decimal.NewFromInt(100))
+	plan, err := s.planForConfig(ctx, userID, config, amount, false)
+	if err != nil {
+		return nil, decimal.Zero, err
 	}
 
-	// Generate trades
-	plan := &entities.RebalancingPlan{
-		ConfigID:           configID,
-		CurrentAllocations: currentAllocs,
-		TargetAllocations:  config.TargetAllocations,
-		Trades:             []entities.RebalanceTradeOrder{},
+	orders := make([]*entities.InvestmentOrder, 0, len(plan.Trades))
+	invested := decimal.Zero
+	for _, trade := range plan.Trades {
+		order, err := s.placeTrade(ctx, userID, trade)
+		if err != nil {
+			return orders, invested, fmt.Errorf("buy %s: %w", trade.Symbol, err)
+		}
+		orders = append(orders, order)
+		invested = invested.Add(trade.Amount)
 	}
 
-	// Get quotes for target symbols
-	symbols := make([]string, 0, len(config.TargetAllocations))
-	for sym := range config.TargetAllocations {
-		symbols = append(symbols, sym)
+	s.logger.Info("Cash flow invested into underweight assets",
+		zap.String("user_id", userID.String()),
+		zap.String("source", string(source)),
+		zap.String("amount", amount.String()),
+		zap.String("invested", invested.String()),
+		zap.Int("orders", len(orders)))
+
+	return orders, invested, nil
+}
+
+// HasActiveConfig reports whether the user has an active rebalancing config that new cash is
+// invested into
+func (s *RebalancingService) HasActiveConfig(ctx context.Context, userID uuid.UUID) (bool, error) {
+	config, err := s.activeConfig(ctx, userID)
+	if err != nil {
+		return false, err
 	}
-	quotes, err := s.quoteProvider.GetQuotes(ctx, symbols)
+	return config != nil, nil
+}
+
+// activeConfig returns the user's active rebalancing config, or nil if none is active
+func (s *RebalancingService) activeConfig(ctx context.Context, userID uuid.UUID) (*entities.RebalancingConfig, error) {
+	configs, err := s.configRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		s.logger.Warn("Failed to get quotes", zap.Error(err))
+		return nil, fmt.Errorf("get configs: %w", err)
 	}
+	for _, c := range configs {
+		if c.Status == entities.ScheduleStatusActive {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
 
-	hundred := decimal.NewFromInt(100)
+// planForConfig loads the user's positions, prices and asset rules and plans against the config
+func (s *RebalancingService) planForConfig(ctx context.Context, userID uuid.UUID, config *entities.RebalancingConfig, cashFlow decimal.Decimal, allowSells bool) (*entities.RebalancingPlan, error) {
+	positions, err := s.positionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
 
-	// Calculate sells first (positions we have but shouldn't or have too much)
-	for symbol, currentPct := range currentAllocs {
-		targetPct, inTarget := config.TargetAllocations[symbol]
-		if !inTarget {
-			targetPct = decimal.Zero
+	holdings := make(map[string]*rebalanceHolding)
+	for _, pos := range positions {
+		holdings[pos.Symbol] = &rebalanceHolding{
+			symbol:       pos.Symbol,
+			value:        pos.MarketValue,
+			quantity:     pos.Qty,
+			costBasis:    pos.CostBasis,
+			price:        pos.CurrentPrice,
+			fractionable: true,
 		}
-
-		drift := currentPct.Sub(targetPct)
-		if drift.GreaterThan(config.ThresholdPct) {
-			// Need to sell
-			sellAmount := drift.Div(hundred).Mul(totalValue)
-			trade := entities.RebalanceTradeOrder{
-				Symbol:     symbol,
-				Side:       "sell",
-				CurrentPct: currentPct,
-				TargetPct:  targetPct,
-				DriftPct:   drift,
-				Amount:     sellAmount,
-			}
-			if quote, ok := quotes[symbol]; ok && quote.Price.GreaterThan(decimal.Zero) {
-				trade.EstimatedQty = sellAmount.Div(quote.Price)
-			}
-			plan.Trades = append(plan.Trades, trade)
-			plan.TotalSellAmount = plan.TotalSellAmount.Add(sellAmount)
+	}
+	for symbol, pct := range config.TargetAllocations {
+		if _, ok := holdings[symbol]; !ok {
+			holdings[symbol] = &rebalanceHolding{symbol: symbol, fractionable: true}
 		}
+		holdings[symbol].targetPct = pct
 	}
 
-	// Calculate buys (positions we should have more of)
-	for symbol, targetPct := range config.TargetAllocations {
-		currentPct := currentAllocs[symbol]
-		drift := targetPct.Sub(currentPct)
-
-		if drift.GreaterThan(config.ThresholdPct) {
-			// Need to buy
-			buyAmount := drift.Div(hundred).Mul(totalValue)
-			trade := entities.RebalanceTradeOrder{
-				Symbol:     symbol,
-				Side:       "buy",
-				CurrentPct: currentPct,
-				TargetPct:  targetPct,
-				DriftPct:   drift,
-				Amount:     buyAmount,
-			}
-			if quote, ok := quotes[symbol]; ok && quote.Price.GreaterThan(decimal.Zero) {
-				trade.EstimatedQty = buyAmount.Div(quote.Price)
+	symbols := make([]string, 0, len(holdings))
+	list := make([]*rebalanceHolding, 0, len(holdings))
+	for symbol, h := range holdings {
+		symbols = append(symbols, symbol)
+		list = append(list, h)
+	}
+
+	// Get quotes for all symbols
+	quotes, err := s.quoteProvider.GetQuotes(ctx, symbols)
+	if err != nil {
+		s.logger.Warn("Failed to get quotes", zap.Error(err))
+	}
+	for symbol, h := range holdings {
+		if quote, ok := quotes[symbol]; ok && quote.Price.GreaterThan(decimal.Zero) {
+			h.price = quote.Price
+		}
+		if s.assets != nil {
+			asset, err := s.assets.GetAsset(ctx, symbol)
+			if err != nil {
+				s.logger.Warn("Failed to look up asset", zap.String("symbol", symbol), zap.Error(err))
+			} else if asset != nil {
+				h.fractionable = asset.Fractionable
 			}
-			plan.Trades = append(plan.Trades, trade)
-			plan.TotalBuyAmount = plan.TotalBuyAmount.Add(buyAmount)
 		}
 	}
 
+	plan, err := planRebalance(list, rebalanceOptions{
+		thresholdPct:   config.ThresholdPct,
+		cashFlow:       cashFlow,
+		minTradeAmount: DefaultMinRebalanceTrade,
+		allowSells:     allowSells,
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.ConfigID = config.ID
+	plan.TargetAllocations = config.TargetAllocations
 	return plan, nil
 }
 
+// placeTrade places a plan trade, by quantity for whole-share trades when a quantity order placer is set
+func (s *RebalancingService) placeTrade(ctx context.Context, userID uuid.UUID, trade entities.RebalanceTradeOrder) (*entities.InvestmentOrder, error) {
+	side := entities.AlpacaOrderSideBuy
+	amount := trade.Amount
+	if trade.Side == "sell" {
+		side = entities.AlpacaOrderSideSell
+		amount = amount.Neg()
+	}
+	if trade.WholeShares && s.quantityOrderPlacer != nil {
+		return s.quantityOrderPlacer.PlaceQuantityOrder(ctx, userID, trade.Symbol, side, trade.EstimatedQty)
+	}
+	return s.orderPlacer.PlaceMarketOrder(ctx, userID, trade.Symbol, amount)
+}
+
 // ExecuteRebalancingPlan executes the trades in a rebalancing plan
 func (s *RebalancingService) ExecuteRebalancingPlan(ctx context.Context, userID uuid.UUID, plan *entities.RebalancingPlan) error {
 	if len(plan.Trades) == 0 {
@@ -252,7 +331,7 @@ func (s *RebalancingService) ExecuteRebalancingPlan(ctx context.Context, userID
 	// Execute sells first to free up cash
 	for _, trade := range plan.Trades {
 		if trade.Side == "sell" {
-			_, err := s.orderPlacer.PlaceMarketOrder(ctx, userID, trade.Symbol, trade.Amount.Neg())
+			_, err := s.placeTrade(ctx, userID, trade)
 			if err != nil {
 				s.logger.Error("Failed to place sell order",
 					zap.String("symbol", trade.Symbol),
@@ -265,7 +344,7 @@ func (s *RebalancingService) ExecuteRebalancingPlan(ctx context.Context, userID
 	// Then execute buys
 	for _, trade := range plan.Trades {
 		if trade.Side == "buy" {
-			_, err := s.orderPlacer.PlaceMarketOrder(ctx, userID, trade.Symbol, trade.Amount)
+			_, err := s.placeTrade(ctx, userID, trade)
 			if err != nil {
 				s.logger.Error("Failed to place buy order",
 					zap.String("symbol", trade.Symbol),
@@ -285,7 +364,8 @@ func (s *RebalancingService) ExecuteRebalancingPlan(ctx context.Context, userID
 
 	s.logger.Info("Rebalancing executed",
 		zap.String("config_id", plan.ConfigID.String()),
-		zap.Int("trades", len(plan.Trades)))
+		zap.Int("trades", len(plan.Trades)),
+		zap.String("estimated_realized_gain", plan.EstimatedRealizedGain.String()))
 
 	return nil
 }
@@ -297,11 +377,16 @@ func (s *RebalancingService) CheckDrift(ctx context.Context, userID uuid.UUID, c
 		return false, decimal.Zero, err
 	}
 
-	// Find max drift
+	// Find max drift across target and held symbols
 	maxDrift := decimal.Zero
-	for _, trade := range plan.Trades {
-		if trade.DriftPct.Abs().GreaterThan(maxDrift) {
-			maxDrift = trade.DriftPct.Abs()
+	for symbol, targetPct := range plan.TargetAllocations {
+		if drift := plan.CurrentAllocations[symbol].Sub(targetPct).Abs(); drift.GreaterThan(maxDrift) {
+			maxDrift = drift
+		}
+	}
+	for symbol, currentPct := range plan.CurrentAllocations {
+		if _, ok := plan.TargetAllocations[symbol]; !ok && currentPct.GreaterThan(maxDrift) {
+			maxDrift = currentPct
 		}
 	}
 
@@ -17,6 +17,7 @@ type ScheduledInvestmentRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.ScheduledInvestment, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.ScheduledInvestment, error)
 	GetDueForExecution(ctx context.Context, before time.Time) ([]*entities.ScheduledInvestment, error)
+	GetDueForReminder(ctx context.Context, before time.Time) ([]*entities.ScheduledInvestment, error)
 	Update(ctx context.Context, si *entities.ScheduledInvestment) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	CreateExecution(ctx context.Context, exec *entities.ScheduledInvestmentExecution) error
@@ -33,11 +34,43 @@ type BasketOrderPlacer interface {
 	PlaceOrder(ctx context.Context, userID, basketID uuid.UUID, side entities.OrderSide, amount decimal.Decimal) (*BrokerageOrderResponse, error)
 }
 
+// ScheduledFundingLedger checks and debits the spending balance that funds scheduled investments
+type ScheduledFundingLedger interface {
+	GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error)
+	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
+	ReverseTransaction(ctx context.Context, originalTxID uuid.UUID, reason string) error
+}
+
+// ScheduleNotifier tells users about upcoming and unfunded scheduled investments
+type ScheduleNotifier interface {
+	SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error
+}
+
+// TradingCalendar reports the days the exchange is closed or closes early
+type TradingCalendar interface {
+	IsTradingDay(t time.Time) bool
+	IsEarlyClose(t time.Time) bool
+	NextTradingDay(t time.Time) time.Time
+}
+
+const (
+	// missedRunGrace is how late a run can be before the catch-up policy treats it as missed
+	missedRunGrace = 24 * time.Hour
+	// maxCatchUpRuns bounds the runs processed for one schedule in a pass
+	maxCatchUpRuns = 31
+	// reminderLeadTime is how far ahead of a run its reminder is sent
+	reminderLeadTime = 24 * time.Hour
+)
+
 // ScheduledInvestmentService handles recurring investments and DCA
 type ScheduledInvestmentService struct {
 	repo              ScheduledInvestmentRepository
 	orderPlacer       OrderPlacer
 	basketOrderPlacer BasketOrderPlacer
+	ledger            ScheduledFundingLedger
+	notifier          ScheduleNotifier
+	calendar          TradingCalendar
 	logger            *zap.Logger
 }
 
@@ -55,6 +88,22 @@ func NewScheduledInvestmentService(
 	}
 }
 
+// SetFundingLedger sets the ledger used to check and debit the spending balance before each run.
+// Without it runs are placed against the user's buying power as before.
+func (s *ScheduledInvestmentService) SetFundingLedger(ledger ScheduledFundingLedger) {
+	s.ledger = ledger
+}
+
+// SetNotifier sets the notifier for reminders ahead of a run and funding failures
+func (s *ScheduledInvestmentService) SetNotifier(notifier ScheduleNotifier) {
+	s.notifier = notifier
+}
+
+// SetTradingCalendar sets the exchange calendar used to defer or skip runs on holidays and early closes
+func (s *ScheduledInvestmentService) SetTradingCalendar(calendar TradingCalendar) {
+	s.calendar = calendar
+}
+
 // CreateScheduledInvestment creates a new recurring investment
 func (s *ScheduledInvestmentService) CreateScheduledInvestment(ctx context.Context, req *CreateScheduledInvestmentRequest) (*entities.ScheduledInvestment, error) {
 	if req.Amount.LessThanOrEqual(decimal.Zero) {
@@ -63,6 +112,10 @@ func (s *ScheduledInvestmentService) CreateScheduledInvestment(ctx context.Conte
 	if req.Symbol == nil && req.BasketID == nil {
 		return nil, fmt.Errorf("symbol or basket_id required")
 	}
+	catchUpPolicy, holidayPolicy, err := schedulePolicies(req.CatchUpPolicy, req.HolidayPolicy)
+	if err != nil {
+		return nil, err
+	}
 
 	nextExec := s.calculateNextExecution(req.Frequency, req.DayOfWeek, req.DayOfMonth, time.Now())
 
@@ -81,6 +134,8 @@ func (s *ScheduledInvestmentService) CreateScheduledInvestment(ctx context.Conte
 		Status:          entities.ScheduleStatusActive,
 		TotalInvested:   decimal.Zero,
 		ExecutionCount:  0,
+		CatchUpPolicy:   catchUpPolicy,
+		HolidayPolicy:   holidayPolicy,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -153,14 +208,63 @@ func (s *ScheduledInvestmentService) UpdateScheduledInvestment(ctx context.Conte
 	return s.repo.Update(ctx, si)
 }
 
+// UpdateSchedulePolicies changes how missed runs and runs on market holidays are handled
+func (s *ScheduledInvestmentService) UpdateSchedulePolicies(ctx context.Context, id uuid.UUID, catchUpPolicy, holidayPolicy *string) error {
+	si, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if si == nil {
+		return fmt.Errorf("scheduled investment not found")
+	}
+
+	if catchUpPolicy == nil {
+		catchUpPolicy = &si.CatchUpPolicy
+	}
+	if holidayPolicy == nil {
+		holidayPolicy = &si.HolidayPolicy
+	}
+	si.CatchUpPolicy, si.HolidayPolicy, err = schedulePolicies(catchUpPolicy, holidayPolicy)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, si)
+}
+
+// schedulePolicies validates catch-up and holiday policies, defaulting unset ones
+func schedulePolicies(catchUpPolicy, holidayPolicy *string) (string, string, error) {
+	catchUp := entities.CatchUpPolicyRunOnce
+	if catchUpPolicy != nil && *catchUpPolicy != "" {
+		catchUp = *catchUpPolicy
+	}
+	switch catchUp {
+	case entities.CatchUpPolicySkip, entities.CatchUpPolicyRunOnce, entities.CatchUpPolicyRunAll:
+	default:
+		return "", "", fmt.Errorf("catch_up_policy must be skip, run_once or run_all")
+	}
+
+	holiday := entities.HolidayPolicyDefer
+	if holidayPolicy != nil && *holidayPolicy != "" {
+		holiday = *holidayPolicy
+	}
+	switch holiday {
+	case entities.HolidayPolicyDefer, entities.HolidayPolicySkip:
+	default:
+		return "", "", fmt.Errorf("holiday_policy must be defer or skip")
+	}
+	return catchUp, holiday, nil
+}
+
 // GetExecutionHistory returns execution history for a scheduled investment
 func (s *ScheduledInvestmentService) GetExecutionHistory(ctx context.Context, scheduleID uuid.UUID, limit int) ([]*entities.ScheduledInvestmentExecution, error) {
 	return s.repo.GetExecutions(ctx, scheduleID, limit)
 }
 
-// ProcessDueInvestments executes all due scheduled investments
+// ProcessDueInvestments runs every due scheduled investment through its pre-flight checks
 func (s *ScheduledInvestmentService) ProcessDueInvestments(ctx context.Context) error {
-	due, err := s.repo.GetDueForExecution(ctx, time.Now())
+	now := time.Now()
+	due, err := s.repo.GetDueForExecution(ctx, now)
 	if err != nil {
 		return fmt.Errorf("get due investments: %w", err)
 	}
@@ -168,7 +272,7 @@ func (s *ScheduledInvestmentService) ProcessDueInvestments(ctx context.Context)
 	s.logger.Info("Processing due scheduled investments", zap.Int("count", len(due)))
 
 	for _, si := range due {
-		if err := s.executeScheduledInvestment(ctx, si); err != nil {
+		if err := s.processSchedule(ctx, si, now); err != nil {
 			s.logger.Error("Failed to execute scheduled investment",
 				zap.String("id", si.ID.String()),
 				zap.Error(err))
@@ -178,12 +282,93 @@ func (s *ScheduledInvestmentService) ProcessDueInvestments(ctx context.Context)
 	return nil
 }
 
-func (s *ScheduledInvestmentService) executeScheduledInvestment(ctx context.Context, si *entities.ScheduledInvestment) error {
-	exec := &entities.ScheduledInvestmentExecution{
-		ID:                    uuid.New(),
-		ScheduledInvestmentID: si.ID,
-		Amount:                si.Amount,
-		ExecutedAt:            time.Now(),
+// processSchedule works through a schedule's due runs, oldest first. Each run is deferred or
+// skipped if the market is closed or closes early today, skipped if the catch-up policy treats
+// it as missed, and otherwise executed once its funding is confirmed.
+func (s *ScheduledInvestmentService) processSchedule(ctx context.Context, si *entities.ScheduledInvestment, now time.Time) error {
+	var lastErr error
+	for i := 0; i < maxCatchUpRuns && !si.NextExecutionAt.After(now); i++ {
+		scheduledFor := si.NextExecutionAt
+		next := s.calculateNextExecution(si.Frequency, si.DayOfWeek, si.DayOfMonth, scheduledFor.Add(time.Second))
+
+		if reason := s.marketClosedReason(now); reason != "" {
+			if si.HolidayPolicy == entities.HolidayPolicySkip {
+				s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunSkipped, reason, nil, nil, nil)
+				si.NextExecutionAt = next
+				continue
+			}
+			// Defer to the next full session; the following run is computed from the deferred date
+			deferred := s.calendar.NextTradingDay(now)
+			for s.calendar.IsEarlyClose(deferred) {
+				deferred = s.calendar.NextTradingDay(deferred)
+			}
+			si.NextExecutionAt = time.Date(deferred.Year(), deferred.Month(), deferred.Day(), 14, 0, 0, 0, time.UTC)
+			s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunDeferred,
+				fmt.Sprintf("%s; deferred to %s", reason, si.NextExecutionAt.Format("2006-01-02")), nil, nil, nil)
+			break
+		}
+
+		late := now.Sub(scheduledFor) > missedRunGrace
+		laterRunDue := !next.After(now)
+		switch {
+		case si.CatchUpPolicy == entities.CatchUpPolicySkip && late:
+			s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunSkipped, "missed run skipped by catch-up policy", nil, nil, nil)
+		case si.CatchUpPolicy != entities.CatchUpPolicyRunAll && si.CatchUpPolicy != entities.CatchUpPolicySkip && laterRunDue:
+			s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunSkipped, "missed run; a later run is due and catch-up runs once", nil, nil, nil)
+		default:
+			if err := s.executeScheduledInvestment(ctx, si, scheduledFor); err != nil {
+				lastErr = err
+			}
+		}
+		si.NextExecutionAt = next
+	}
+
+	if err := s.repo.Update(ctx, si); err != nil {
+		s.logger.Error("Failed to update schedule", zap.Error(err))
+	}
+	return lastErr
+}
+
+// marketClosedReason explains why runs cannot execute today, or returns "" if the market is open
+// for a full session
+func (s *ScheduledInvestmentService) marketClosedReason(now time.Time) string {
+	if s.calendar == nil {
+		return ""
+	}
+	if !s.calendar.IsTradingDay(now) {
+		return "market closed today"
+	}
+	if s.calendar.IsEarlyClose(now) {
+		return "market closes early today"
+	}
+	return ""
+}
+
+// executeScheduledInvestment checks the spending balance covers the run, debits it and places the
+// order, reversing the debit if the order fails
+func (s *ScheduledInvestmentService) executeScheduledInvestment(ctx context.Context, si *entities.ScheduledInvestment, scheduledFor time.Time) error {
+	// Pre-flight: the spending balance must cover the run
+	if s.ledger != nil {
+		balance, err := s.ledger.GetAccountBalance(ctx, si.UserID, entities.AccountTypeSpendingBalance)
+		if err != nil {
+			err = fmt.Errorf("get spending balance: %w", err)
+			s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunFailed, "", nil, nil, err)
+			return err
+		}
+		if balance.LessThan(si.Amount) {
+			reason := fmt.Sprintf("spending balance $%s is below the $%s scheduled investment", balance.StringFixed(2), si.Amount.StringFixed(2))
+			s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunInsufficientFunds, reason, nil, nil, nil)
+			s.notify(ctx, si.UserID, "Scheduled investment not funded",
+				fmt.Sprintf("Your $%s investment in %s was not placed because your spending balance is $%s. Add funds to keep your plan on track.",
+					si.Amount.StringFixed(2), scheduleTarget(si), balance.StringFixed(2)))
+			return nil
+		}
+	}
+
+	ledgerTxID, err := s.debitSpendingBalance(ctx, si, scheduledFor)
+	if err != nil {
+		s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunFailed, "", nil, nil, err)
+		return err
 	}
 
 	// Place the order
@@ -221,35 +406,158 @@ func (s *ScheduledInvestmentService) executeScheduledInvestment(ctx context.Cont
 	}
 
 	if execErr != nil {
-		exec.Status = "failed"
-		errMsg := execErr.Error()
-		exec.ErrorMessage = &errMsg
-	} else {
-		exec.Status = "success"
-		if order != nil {
-			exec.OrderID = &order.ID
+		if ledgerTxID != nil {
+			if err := s.ledger.ReverseTransaction(ctx, *ledgerTxID, "scheduled investment order failed"); err != nil {
+				s.logger.Error("Failed to reverse scheduled investment debit",
+					zap.String("ledger_transaction_id", ledgerTxID.String()),
+					zap.Error(err))
+			}
 		}
+		s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunFailed, "", nil, ledgerTxID, execErr)
+		return execErr
 	}
 
-	// Log execution
-	if err := s.repo.CreateExecution(ctx, exec); err != nil {
-		s.logger.Error("Failed to log execution", zap.Error(err))
+	var orderID *uuid.UUID
+	if order != nil {
+		orderID = &order.ID
 	}
+	s.recordRun(ctx, si, scheduledFor, entities.ScheduledRunSuccess, "", orderID, ledgerTxID, nil)
 
-	// Update schedule
 	now := time.Now()
 	si.LastExecutedAt = &now
 	si.ExecutionCount++
-	if exec.Status == "success" {
-		si.TotalInvested = si.TotalInvested.Add(si.Amount)
+	si.TotalInvested = si.TotalInvested.Add(si.Amount)
+	return nil
+}
+
+// debitSpendingBalance moves the run's amount from the spending balance to buying power. The
+// idempotency key is derived from the run so a retried run never debits twice.
+func (s *ScheduledInvestmentService) debitSpendingBalance(ctx context.Context, si *entities.ScheduledInvestment, scheduledFor time.Time) (*uuid.UUID, error) {
+	if s.ledger == nil {
+		return nil, nil
 	}
-	si.NextExecutionAt = s.calculateNextExecution(si.Frequency, si.DayOfWeek, si.DayOfMonth, now)
 
-	if err := s.repo.Update(ctx, si); err != nil {
-		s.logger.Error("Failed to update schedule", zap.Error(err))
+	spendAccount, err := s.ledger.GetOrCreateUserAccount(ctx, si.UserID, entities.AccountTypeSpendingBalance)
+	if err != nil {
+		return nil, fmt.Errorf("get spending account: %w", err)
+	}
+	exposureAccount, err := s.ledger.GetOrCreateUserAccount(ctx, si.UserID, entities.AccountTypeFiatExposure)
+	if err != nil {
+		return nil, fmt.Errorf("get fiat exposure account: %w", err)
+	}
+
+	desc := "Scheduled investment"
+	referenceType := "scheduled_investment"
+	ledgerTx, err := s.ledger.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &si.UserID,
+		TransactionType: entities.TransactionTypeInvestment,
+		ReferenceID:     &si.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  fmt.Sprintf("scheduled-investment:%s:%d", si.ID, scheduledFor.Unix()),
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   spendAccount.ID,
+				EntryType:   entities.EntryTypeCredit, // Spending down
+				Amount:      si.Amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   exposureAccount.ID,
+				EntryType:   entities.EntryTypeDebit, // Buying power up
+				Amount:      si.Amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debit spending balance: %w", err)
+	}
+	return &ledgerTx.ID, nil
+}
+
+// recordRun logs the outcome of one scheduled run
+func (s *ScheduledInvestmentService) recordRun(ctx context.Context, si *entities.ScheduledInvestment, scheduledFor time.Time, status, reason string, orderID, ledgerTxID *uuid.UUID, runErr error) {
+	exec := &entities.ScheduledInvestmentExecution{
+		ID:                    uuid.New(),
+		ScheduledInvestmentID: si.ID,
+		OrderID:               orderID,
+		LedgerTransactionID:   ledgerTxID,
+		Amount:                si.Amount,
+		Status:                status,
+		ScheduledFor:          &scheduledFor,
+		ExecutedAt:            time.Now(),
+	}
+	if reason != "" {
+		exec.Reason = &reason
+	}
+	if runErr != nil {
+		errMsg := runErr.Error()
+		exec.ErrorMessage = &errMsg
+	}
+
+	if err := s.repo.CreateExecution(ctx, exec); err != nil {
+		s.logger.Error("Failed to log execution", zap.Error(err))
+	}
+}
+
+// SendUpcomingReminders notifies users of runs in the next day, warning when the spending
+// balance will not cover them
+func (s *ScheduledInvestmentService) SendUpcomingReminders(ctx context.Context) (int, error) {
+	if s.notifier == nil {
+		return 0, nil
+	}
+
+	upcoming, err := s.repo.GetDueForReminder(ctx, time.Now().Add(reminderLeadTime))
+	if err != nil {
+		return 0, fmt.Errorf("get upcoming investments: %w", err)
+	}
+
+	sent := 0
+	for _, si := range upcoming {
+		message := fmt.Sprintf("Your $%s investment in %s runs tomorrow from your spending balance.",
+			si.Amount.StringFixed(2), scheduleTarget(si))
+		if s.ledger != nil {
+			balance, err := s.ledger.GetAccountBalance(ctx, si.UserID, entities.AccountTypeSpendingBalance)
+			if err == nil && balance.LessThan(si.Amount) {
+				message += fmt.Sprintf(" Your balance is $%s; add funds before then so it is not skipped.", balance.StringFixed(2))
+			}
+		}
+		s.notify(ctx, si.UserID, "Upcoming scheduled investment", message)
+
+		reminded := si.NextExecutionAt
+		si.ReminderSentFor = &reminded
+		if err := s.repo.Update(ctx, si); err != nil {
+			s.logger.Error("Failed to record reminder", zap.String("id", si.ID.String()), zap.Error(err))
+			continue
+		}
+		sent++
 	}
+	return sent, nil
+}
 
-	return execErr
+func (s *ScheduledInvestmentService) notify(ctx context.Context, userID uuid.UUID, title, message string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendGenericNotification(ctx, userID, title, message); err != nil {
+		s.logger.Warn("Failed to send scheduled investment notification",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+}
+
+// scheduleTarget names what a schedule buys for notifications
+func scheduleTarget(si *entities.ScheduledInvestment) string {
+	if si.Name != nil && *si.Name != "" {
+		return *si.Name
+	}
+	if si.Symbol != nil {
+		return *si.Symbol
+	}
+	return "your basket"
 }
 
 func (s *ScheduledInvestmentService) calculateNextExecution(frequency string, dayOfWeek, dayOfMonth *int, from time.Time) time.Time {
@@ -302,12 +610,14 @@ func (s *ScheduledInvestmentService) calculateNextExecution(frequency string, da
 
 // CreateScheduledInvestmentRequest represents a request to create a scheduled investment
 type CreateScheduledInvestmentRequest struct {
-	UserID     uuid.UUID
-	Name       *string
-	Symbol     *string
-	BasketID   *uuid.UUID
-	Amount     decimal.Decimal
-	Frequency  string // daily, weekly, biweekly, monthly
-	DayOfWeek  *int   // 0-6 for weekly
-	DayOfMonth *int   // 1-28 for monthly
+	UserID        uuid.UUID
+	Name          *string
+	Symbol        *string
+	BasketID      *uuid.UUID
+	Amount        decimal.Decimal
+	Frequency     string  // daily, weekly, biweekly, monthly
+	DayOfWeek     *int    // 0-6 for weekly
+	DayOfMonth    *int    // 1-28 for monthly
+	CatchUpPolicy *string // skip, run_once (default), run_all
+	HolidayPolicy *string // defer (default), skip
 }
@@ -28,6 +28,25 @@ type AllocationService interface {
 	LogDeclinedSpending(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, reason string) error
 }
 
+// SuitabilityChecker checks investments against the user's assessed risk band
+type SuitabilityChecker interface {
+	CheckSuitability(ctx context.Context, userID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand, acknowledged bool) error
+}
+
+// BasketExecutionRunner places a basket buy as an idempotent execution of per-asset orders
+type BasketExecutionRunner interface {
+	ExecuteBasket(ctx context.Context, req *BasketExecutionRequest) (*entities.BasketExecution, error)
+}
+
+// BrokerageAccountProvider looks up the brokerage account basket orders are placed in
+type BrokerageAccountProvider interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.AlpacaAccount, error)
+}
+
+// orderNamespace derives order IDs from client idempotency keys, so a retried order maps to the
+// same order, the same reservation and the same basket execution
+var orderNamespace = uuid.MustParse("3b9d7e52-0c4f-4a61-8e2d-9f7a1c5b6d83")
+
 // AllocationNotificationManager interface for sending allocation notifications
 type AllocationNotificationManager interface {
 	NotifyTransactionDeclined(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, transactionType string) error
@@ -44,6 +63,9 @@ type Service struct {
 	circleClient       CircleClient
 	allocationService  AllocationService
 	allocationNotifier AllocationNotificationManager
+	suitability        SuitabilityChecker
+	basketExecutor     BasketExecutionRunner
+	brokerageAccounts  BrokerageAccountProvider
 	logger             *logger.Logger
 }
 
@@ -123,6 +145,18 @@ func NewService(
 	}
 }
 
+// SetSuitabilityChecker sets the risk profile check for basket buys (optional)
+func (s *Service) SetSuitabilityChecker(checker SuitabilityChecker) {
+	s.suitability = checker
+}
+
+// SetBasketExecutor routes basket buys through the basket executor, which must release failed
+// legs to the same buying power CreateOrder reserves from (optional)
+func (s *Service) SetBasketExecutor(executor BasketExecutionRunner, accounts BrokerageAccountProvider) {
+	s.basketExecutor = executor
+	s.brokerageAccounts = accounts
+}
+
 // ListBaskets returns all available curated baskets
 func (s *Service) ListBaskets(ctx context.Context) ([]*entities.Basket, error) {
 	baskets, err := s.basketRepo.GetAll(ctx)
@@ -144,10 +178,26 @@ func (s *Service) GetBasket(ctx context.Context, basketID uuid.UUID) (*entities.
 	return basket, nil
 }
 
-// CreateOrder places a new investment order
+// CreateOrder places a new investment order. Repeating a request with the same idempotency key
+// returns the original order, resuming its basket execution if it was left unfinished.
 func (s *Service) CreateOrder(ctx context.Context, userID uuid.UUID, req *entities.OrderCreateRequest) (*entities.Order, error) {
 	s.logger.Info("Creating order", "user_id", userID, "basket_id", req.BasketID, "side", req.Side, "amount", req.Amount)
 
+	orderID := uuid.New()
+	if req.IdempotencyKey != nil && *req.IdempotencyKey != "" {
+		orderID = uuid.NewSHA1(orderNamespace, []byte(userID.String()+":"+*req.IdempotencyKey))
+		existing, err := s.orderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		if existing != nil {
+			if existing.Side == entities.OrderSideBuy && existing.Status == entities.OrderStatusAccepted && s.basketExecutor != nil {
+				return s.executeBasketOrder(ctx, existing)
+			}
+			return existing, nil
+		}
+	}
+
 	// Validate basket exists
 	basket, err := s.basketRepo.GetByID(ctx, req.BasketID)
 	if err != nil {
@@ -168,6 +218,13 @@ func (s *Service) CreateOrder(ctx context.Context, userID uuid.UUID, req *entiti
 		return nil, ErrInvalidAmount
 	}
 
+	// Baskets above the user's risk band need an explicit acknowledgment
+	if req.Side == entities.OrderSideBuy && s.suitability != nil {
+		if err := s.suitability.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basket.ID, entities.RiskBand(basket.RiskLevel), req.AcknowledgeRisk); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check 70/30 allocation mode spending limit for buy orders
 	if req.Side == entities.OrderSideBuy {
 		if s.allocationService != nil {
@@ -220,7 +277,7 @@ func (s *Service) CreateOrder(ctx context.Context, userID uuid.UUID, req *entiti
 
 	// Create order record
 	order := &entities.Order{
-		ID:           uuid.New(),
+		ID:           orderID,
 		UserID:       userID,
 		BasketID:     req.BasketID,
 		Side:         req.Side,
@@ -243,6 +300,10 @@ func (s *Service) CreateOrder(ctx context.Context, userID uuid.UUID, req *entiti
 		}
 	}
 
+	if req.Side == entities.OrderSideBuy && s.basketExecutor != nil {
+		return s.executeBasketOrder(ctx, order)
+	}
+
 	// Submit order to brokerage asynchronously
 	go func() {
 		brokerageResp, err := s.brokerageAPI.PlaceOrder(ctx, userID, req.BasketID, req.Side, amount)
@@ -261,6 +322,63 @@ func (s *Service) CreateOrder(ctx context.Context, userID uuid.UUID, req *entiti
 	return order, nil
 }
 
+// executeBasketOrder places a reserved basket buy through the basket executor. The execution is
+// keyed by the order, so running it again resumes it instead of placing new orders. Failed legs are
+// released by the executor; an order it leaves unfinished stays accepted for a retry to resume.
+func (s *Service) executeBasketOrder(ctx context.Context, order *entities.Order) (*entities.Order, error) {
+	account, err := s.brokerageAccounts.GetByUserID(ctx, order.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage account: %w", err)
+	}
+	if account == nil {
+		// Nothing was placed, so the whole reservation goes back
+		if err := s.balanceRepo.AddBuyingPower(ctx, order.UserID, order.Amount); err != nil {
+			s.logger.Error("Failed to refund buying power", "order_id", order.ID, "error", err)
+		}
+		if err := s.orderRepo.UpdateStatus(ctx, order.ID, entities.OrderStatusFailed, nil); err != nil {
+			s.logger.Error("Failed to update order status", "order_id", order.ID, "error", err)
+		}
+		return nil, ErrBrokerageAccountNotFound
+	}
+
+	execution, err := s.basketExecutor.ExecuteBasket(ctx, &BasketExecutionRequest{
+		UserID:          order.UserID,
+		BasketID:        &order.BasketID,
+		AlpacaAccountID: account.AlpacaAccountID,
+		TotalAmount:     order.Amount,
+		IdempotencyKey:  "order-" + order.ID.String(),
+	})
+	if execution == nil {
+		return nil, fmt.Errorf("failed to execute basket order: %w", err)
+	}
+
+	executionRef := execution.ID.String()
+	order.BrokerageRef = &executionRef
+	switch execution.Status {
+	case entities.BasketExecutionStatusCompleted, entities.BasketExecutionStatusPartiallyCompleted:
+		order.Status = entities.OrderStatusPending
+	case entities.BasketExecutionStatusFailed:
+		order.Status = entities.OrderStatusFailed
+	default:
+		s.logger.Warn("Basket execution incomplete, order left to resume",
+			"order_id", order.ID,
+			"execution_id", execution.ID,
+			"error", err)
+		return order, nil
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, order.Status, order.BrokerageRef); err != nil {
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
+	order.UpdatedAt = time.Now()
+	if order.Status == entities.OrderStatusFailed {
+		return nil, ErrBasketOrderFailed
+	}
+
+	s.logger.Info("Basket order submitted", "order_id", order.ID, "execution_id", execution.ID, "status", execution.Status)
+	return order, nil
+}
+
 // ListOrders returns orders for a user
 func (s *Service) ListOrders(ctx context.Context, userID uuid.UUID, limit, offset int, status *entities.OrderStatus) ([]*entities.Order, error) {
 	orders, err := s.orderRepo.GetByUserID(ctx, userID, limit, offset, status)
@@ -528,10 +646,12 @@ func (s *Service) updatePositions(ctx context.Context, order *entities.Order, fi
 
 // Common errors
 var (
-	ErrBasketNotFound       = fmt.Errorf("basket not found")
-	ErrOrderNotFound        = fmt.Errorf("order not found")
-	ErrPositionNotFound     = fmt.Errorf("position not found")
-	ErrInvalidAmount        = fmt.Errorf("invalid amount")
-	ErrInsufficientFunds    = fmt.Errorf("insufficient buying power")
-	ErrInsufficientPosition = fmt.Errorf("insufficient position")
+	ErrBasketNotFound           = fmt.Errorf("basket not found")
+	ErrOrderNotFound            = fmt.Errorf("order not found")
+	ErrPositionNotFound         = fmt.Errorf("position not found")
+	ErrInvalidAmount            = fmt.Errorf("invalid amount")
+	ErrInsufficientFunds        = fmt.Errorf("insufficient buying power")
+	ErrInsufficientPosition     = fmt.Errorf("insufficient position")
+	ErrBrokerageAccountNotFound = fmt.Errorf("brokerage account not found")
+	ErrBasketOrderFailed        = fmt.Errorf("basket orders could not be placed")
 )
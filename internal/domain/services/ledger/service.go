@@ -129,10 +129,11 @@ func (s *Service) CreateTransaction(ctx context.Context, req *entities.CreateTra
 // updateAccountBalanceInTx updates an account balance within a database transaction
 func (s *Service) updateAccountBalanceInTx(ctx context.Context, accountID uuid.UUID, entryType entities.EntryType, amount decimal.Decimal) error {
 	// Get current balance
-	currentBalance, err := s.ledgerRepo.GetAccountBalance(ctx, accountID)
+	account, err := s.ledgerRepo.GetAccountByID(ctx, accountID)
 	if err != nil {
 		return fmt.Errorf("get account balance: %w", err)
 	}
+	currentBalance := account.Balance
 
 	// Calculate new balance
 	var newBalance decimal.Decimal
@@ -145,8 +146,8 @@ func (s *Service) updateAccountBalanceInTx(ctx context.Context, accountID uuid.U
 		newBalance = currentBalance.Sub(amount)
 	}
 
-	// Ensure balance doesn't go negative
-	if newBalance.IsNegative() {
+	// Ensure balance doesn't go negative, except on clearing accounts
+	if newBalance.IsNegative() && !account.AccountType.AllowsNegativeBalance() {
 		return fmt.Errorf("insufficient balance: current=%s, adjustment=%s %s",
 			currentBalance.String(), amount.String(), entryType)
 	}
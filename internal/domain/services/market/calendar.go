@@ -0,0 +1,177 @@
+package market
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// ExchangeCalendar computes NYSE full-day holidays and 1 PM early closes from the exchange's
+// published rules, so trading days can be known without a network call
+type ExchangeCalendar struct {
+	location *time.Location
+}
+
+// NewExchangeCalendar creates a calendar for the US equity exchanges
+func NewExchangeCalendar() *ExchangeCalendar {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		location = time.FixedZone("EST", -5*60*60)
+	}
+	return &ExchangeCalendar{location: location}
+}
+
+// Location returns the exchange's time zone
+func (c *ExchangeCalendar) Location() *time.Location {
+	return c.location
+}
+
+// IsTradingDay reports whether the exchange opens on the day containing t, in exchange time
+func (c *ExchangeCalendar) IsTradingDay(t time.Time) bool {
+	date := c.date(t)
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// IsEarlyClose reports whether the exchange closes at 1 PM on the day containing t
+func (c *ExchangeCalendar) IsEarlyClose(t time.Time) bool {
+	date := c.date(t)
+	if !c.IsTradingDay(date) {
+		return false
+	}
+	year := date.Year()
+	earlyCloses := []time.Time{
+		c.day(year, time.July, 3),
+		nthWeekday(year, time.November, time.Thursday, 4, c.location).AddDate(0, 0, 1),
+		c.day(year, time.December, 24),
+	}
+	for _, early := range earlyCloses {
+		if date.Equal(early) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextTradingDay returns the first trading day after the day containing t, at midnight exchange time
+func (c *ExchangeCalendar) NextTradingDay(t time.Time) time.Time {
+	date := c.date(t).AddDate(0, 0, 1)
+	for !c.IsTradingDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// Holiday returns the name of the full-day exchange holiday on the day containing t
+func (c *ExchangeCalendar) Holiday(t time.Time) (string, bool) {
+	date := c.date(t)
+	year := date.Year()
+	holidays := map[time.Time]string{
+		nthWeekday(year, time.January, time.Monday, 3, c.location):    "Martin Luther King Jr. Day",
+		nthWeekday(year, time.February, time.Monday, 3, c.location):   "Washington's Birthday",
+		easter(year, c.location).AddDate(0, 0, -2):                    "Good Friday",
+		lastWeekday(year, time.May, time.Monday, c.location):          "Memorial Day",
+		nthWeekday(year, time.September, time.Monday, 1, c.location):  "Labor Day",
+		nthWeekday(year, time.November, time.Thursday, 4, c.location): "Thanksgiving Day",
+		c.observed(year, time.July, 4):                                "Independence Day",
+		c.observed(year, time.December, 25):                           "Christmas Day",
+	}
+	if year >= 2022 {
+		holidays[c.observed(year, time.June, 19)] = "Juneteenth"
+	}
+	// New Year's Day falling on a Saturday is not observed on the prior Friday
+	if newYear := c.day(year, time.January, 1); newYear.Weekday() == time.Sunday {
+		holidays[newYear.AddDate(0, 0, 1)] = "New Year's Day"
+	} else if newYear.Weekday() != time.Saturday {
+		holidays[newYear] = "New Year's Day"
+	}
+
+	name, ok := holidays[date]
+	return name, ok
+}
+
+// Sessions returns the trading sessions from the day containing from through the day containing to.
+// Regular hours are 9:30 AM to 4 PM, or 1 PM on early closes; extended hours run from 4 AM to
+// 8 PM, or 5 PM on early closes.
+func (c *ExchangeCalendar) Sessions(ctx context.Context, from, to time.Time) ([]entities.MarketSession, error) {
+	var sessions []entities.MarketSession
+	last := c.date(to)
+	for date := c.date(from); !date.After(last); date = date.AddDate(0, 0, 1) {
+		if !c.IsTradingDay(date) {
+			continue
+		}
+		closeHour, extendedCloseHour := 16, 20
+		early := c.IsEarlyClose(date)
+		if early {
+			closeHour, extendedCloseHour = 13, 17
+		}
+		sessions = append(sessions, entities.MarketSession{
+			Date:          date,
+			Open:          date.Add(9*time.Hour + 30*time.Minute),
+			Close:         date.Add(time.Duration(closeHour) * time.Hour),
+			ExtendedOpen:  date.Add(4 * time.Hour),
+			ExtendedClose: date.Add(time.Duration(extendedCloseHour) * time.Hour),
+			EarlyClose:    early,
+		})
+	}
+	return sessions, nil
+}
+
+// date truncates t to midnight of its exchange-time day
+func (c *ExchangeCalendar) date(t time.Time) time.Time {
+	t = t.In(c.location)
+	return c.day(t.Year(), t.Month(), t.Day())
+}
+
+func (c *ExchangeCalendar) day(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location)
+}
+
+// observed moves a fixed-date holiday on a weekend to the nearest weekday
+func (c *ExchangeCalendar) observed(year int, month time.Month, day int) time.Time {
+	date := c.day(year, month, day)
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday returns the nth given weekday of a month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int, location *time.Location) time.Time {
+	date := time.Date(year, month, 1, 0, 0, 0, 0, location)
+	offset := (int(weekday) - int(date.Weekday()) + 7) % 7
+	return date.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last given weekday of a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday, location *time.Location) time.Time {
+	date := time.Date(year, month+1, 1, 0, 0, 0, 0, location).AddDate(0, 0, -1)
+	offset := (int(date.Weekday()) - int(weekday) + 7) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday using the anonymous Gregorian algorithm
+func easter(year int, location *time.Location) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, location)
+}
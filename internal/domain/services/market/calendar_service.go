@@ -0,0 +1,321 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+const (
+	// calendarCacheTTL is how long a loaded month of sessions is trusted before reloading
+	calendarCacheTTL = 24 * time.Hour
+	// calendarRetryAfter is how long a month built from the exchange rules is kept after the
+	// source failed, before the source is tried again
+	calendarRetryAfter = 15 * time.Minute
+	// calendarLoadTimeout bounds a single source load
+	calendarLoadTimeout = 10 * time.Second
+	// maxCalendarScan bounds searches for the next session
+	maxCalendarScan = 366
+)
+
+// CalendarSource loads the exchange sessions between two dates
+type CalendarSource interface {
+	Sessions(ctx context.Context, from, to time.Time) ([]entities.MarketSession, error)
+}
+
+// CalendarService answers market-hours questions from exchange sessions loaded a month at a time
+// and cached. When the source is unavailable it falls back to the exchange's published rules.
+type CalendarService struct {
+	source   CalendarSource
+	rules    *ExchangeCalendar
+	location *time.Location
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	sessions map[string]entities.MarketSession // keyed by 2006-01-02
+	months   map[string]time.Time              // keyed by 2006-01, value is when the month expires
+}
+
+// NewCalendarService creates a calendar service backed by source
+func NewCalendarService(source CalendarSource, logger *zap.Logger) *CalendarService {
+	rules := NewExchangeCalendar()
+	if source == nil {
+		source = rules
+	}
+	return &CalendarService{
+		source:   source,
+		rules:    rules,
+		location: rules.Location(),
+		logger:   logger,
+		sessions: make(map[string]entities.MarketSession),
+		months:   make(map[string]time.Time),
+	}
+}
+
+// Location returns the exchange's time zone
+func (s *CalendarService) Location() *time.Location {
+	return s.location
+}
+
+// Session returns the trading session on the day containing t
+func (s *CalendarService) Session(t time.Time) (entities.MarketSession, bool) {
+	date := s.rules.date(t)
+	s.ensureMonth(date)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	session, ok := s.sessions[date.Format("2006-01-02")]
+	return session, ok
+}
+
+// IsTradingDay reports whether the exchange opens on the day containing t
+func (s *CalendarService) IsTradingDay(t time.Time) bool {
+	_, ok := s.Session(t)
+	return ok
+}
+
+// IsEarlyClose reports whether the exchange closes early on the day containing t
+func (s *CalendarService) IsEarlyClose(t time.Time) bool {
+	session, ok := s.Session(t)
+	return ok && session.EarlyClose
+}
+
+// IsOpen reports whether t falls in regular trading hours
+func (s *CalendarService) IsOpen(t time.Time) bool {
+	session, ok := s.Session(t)
+	return ok && !t.Before(session.Open) && t.Before(session.Close)
+}
+
+// IsExtendedHours reports whether t falls in pre-market or after-hours trading
+func (s *CalendarService) IsExtendedHours(t time.Time) bool {
+	session, ok := s.Session(t)
+	if !ok {
+		return false
+	}
+	preMarket := !t.Before(session.ExtendedOpen) && t.Before(session.Open)
+	afterHours := !t.Before(session.Close) && t.Before(session.ExtendedClose)
+	return preMarket || afterHours
+}
+
+// NextOpen returns the next regular-hours open after t
+func (s *CalendarService) NextOpen(t time.Time) time.Time {
+	return s.nextSessionTime(t, func(session entities.MarketSession) time.Time { return session.Open })
+}
+
+// NextClose returns the next regular-hours close after t, which is today's close while the
+// market is open
+func (s *CalendarService) NextClose(t time.Time) time.Time {
+	return s.nextSessionTime(t, func(session entities.MarketSession) time.Time { return session.Close })
+}
+
+// NextTradingDay returns the first trading day after the day containing t, at midnight exchange time
+func (s *CalendarService) NextTradingDay(t time.Time) time.Time {
+	return s.AddTradingDays(t, 1)
+}
+
+// PreviousTradingDay returns the last trading day before the day containing t, at midnight exchange time
+func (s *CalendarService) PreviousTradingDay(t time.Time) time.Time {
+	return s.AddTradingDays(t, -1)
+}
+
+// AddTradingDays moves n trading days from the day containing t, backwards when n is negative,
+// and returns that day at midnight exchange time
+func (s *CalendarService) AddTradingDays(t time.Time, n int) time.Time {
+	date := s.rules.date(t)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	limit := maxCalendarScan + 7*n
+	for scanned := 0; n > 0 && scanned < limit; scanned++ {
+		date = date.AddDate(0, 0, step)
+		if s.IsTradingDay(date) {
+			n--
+		}
+	}
+	return date
+}
+
+// TradingDaysBetween counts the trading days after the day containing from, up to and including
+// the day containing to
+func (s *CalendarService) TradingDaysBetween(from, to time.Time) int {
+	last := s.rules.date(to)
+	count := 0
+	for date := s.rules.date(from).AddDate(0, 0, 1); !date.After(last); date = date.AddDate(0, 0, 1) {
+		if s.IsTradingDay(date) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *CalendarService) nextSessionTime(t time.Time, at func(entities.MarketSession) time.Time) time.Time {
+	date := s.rules.date(t)
+	for i := 0; i < maxCalendarScan; i++ {
+		if session, ok := s.Session(date); ok && at(session).After(t) {
+			return at(session)
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// ensureMonth loads the month containing date unless it is cached and fresh
+func (s *CalendarService) ensureMonth(date time.Time) {
+	key := date.Format("2006-01")
+	s.mu.RLock()
+	expiresAt, ok := s.months[key]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(expiresAt) {
+		return
+	}
+
+	from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, s.location)
+	to := from.AddDate(0, 1, -1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), calendarLoadTimeout)
+	defer cancel()
+
+	ttl := calendarCacheTTL
+	sessions, err := s.source.Sessions(ctx, from, to)
+	if err != nil {
+		s.logger.Warn("Failed to load market calendar, using exchange rules",
+			zap.String("month", key),
+			zap.Error(err))
+		sessions, _ = s.rules.Sessions(ctx, from, to)
+		ttl = calendarRetryAfter
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		delete(s.sessions, day.Format("2006-01-02"))
+	}
+	for _, session := range sessions {
+		s.sessions[session.Date.In(s.location).Format("2006-01-02")] = session
+	}
+	s.months[key] = time.Now().Add(ttl)
+}
+
+// AlpacaCalendarClient fetches the exchange calendar from Alpaca
+type AlpacaCalendarClient interface {
+	GetCalendar(ctx context.Context, start, end time.Time) ([]entities.AlpacaCalendarDay, error)
+}
+
+// AlpacaCalendarSource loads sessions from Alpaca's calendar
+type AlpacaCalendarSource struct {
+	client   AlpacaCalendarClient
+	location *time.Location
+}
+
+// NewAlpacaCalendarSource creates a calendar source backed by Alpaca
+func NewAlpacaCalendarSource(client AlpacaCalendarClient) *AlpacaCalendarSource {
+	return &AlpacaCalendarSource{client: client, location: NewExchangeCalendar().Location()}
+}
+
+// Sessions returns the sessions Alpaca reports between from and to
+func (a *AlpacaCalendarSource) Sessions(ctx context.Context, from, to time.Time) ([]entities.MarketSession, error) {
+	days, err := a.client.GetCalendar(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return parseCalendarDays(days, a.location)
+}
+
+// FixtureCalendarSource serves a fixed set of calendar days, for tests and offline use
+type FixtureCalendarSource struct {
+	days     []entities.AlpacaCalendarDay
+	location *time.Location
+}
+
+// NewFixtureCalendarSource creates a calendar source from calendar days in Alpaca's format
+func NewFixtureCalendarSource(days []entities.AlpacaCalendarDay) *FixtureCalendarSource {
+	return &FixtureCalendarSource{days: days, location: NewExchangeCalendar().Location()}
+}
+
+// LoadFixtureCalendarSource reads a JSON array of calendar days in Alpaca's format
+func LoadFixtureCalendarSource(path string) (*FixtureCalendarSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read calendar fixture: %w", err)
+	}
+	var days []entities.AlpacaCalendarDay
+	if err := json.Unmarshal(data, &days); err != nil {
+		return nil, fmt.Errorf("parse calendar fixture: %w", err)
+	}
+	return NewFixtureCalendarSource(days), nil
+}
+
+// Sessions returns the fixture days between from and to
+func (f *FixtureCalendarSource) Sessions(ctx context.Context, from, to time.Time) ([]entities.MarketSession, error) {
+	sessions, err := parseCalendarDays(f.days, f.location)
+	if err != nil {
+		return nil, err
+	}
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, f.location)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, f.location)
+
+	var inRange []entities.MarketSession
+	for _, session := range sessions {
+		if !session.Date.Before(first) && !session.Date.After(last) {
+			inRange = append(inRange, session)
+		}
+	}
+	return inRange, nil
+}
+
+// parseCalendarDays converts Alpaca calendar days to sessions in exchange time
+func parseCalendarDays(days []entities.AlpacaCalendarDay, location *time.Location) ([]entities.MarketSession, error) {
+	sessions := make([]entities.MarketSession, 0, len(days))
+	for _, day := range days {
+		date, err := time.ParseInLocation("2006-01-02", day.Date, location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar date %q: %w", day.Date, err)
+		}
+		open, err := clockTime(date, day.Open)
+		if err != nil {
+			return nil, err
+		}
+		closeAt, err := clockTime(date, day.Close)
+		if err != nil {
+			return nil, err
+		}
+		session := entities.MarketSession{
+			Date:          date,
+			Open:          open,
+			Close:         closeAt,
+			ExtendedOpen:  open,
+			ExtendedClose: closeAt,
+			EarlyClose:    closeAt.Hour() < 16,
+		}
+		if day.SessionOpen != "" {
+			if session.ExtendedOpen, err = clockTime(date, day.SessionOpen); err != nil {
+				return nil, err
+			}
+		}
+		if day.SessionClose != "" {
+			if session.ExtendedClose, err = clockTime(date, day.SessionClose); err != nil {
+				return nil, err
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// clockTime sets a 15:04 or 1504 wall-clock time on date
+func clockTime(date time.Time, clock string) (time.Time, error) {
+	clock = strings.ReplaceAll(clock, ":", "")
+	parsed, err := time.Parse("1504", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid calendar time %q: %w", clock, err)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, date.Location()), nil
+}
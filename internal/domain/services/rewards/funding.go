@@ -0,0 +1,117 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+var ErrInvalidFundingAmount = errors.New("funding amount must be positive")
+
+// DefaultLowFundingThreshold is the rewards pool balance below which an alert is raised
+var DefaultLowFundingThreshold = decimal.NewFromInt(1000)
+
+// FundingAlerter is told when the rewards funding pool runs low or cannot cover cleared cashback
+type FundingAlerter interface {
+	AlertLowRewardsFunding(ctx context.Context, balance, threshold, shortfall decimal.Decimal) error
+}
+
+// SetFundingAlerter sets the alerter and the pool balance below which it fires (optional)
+func (s *Service) SetFundingAlerter(alerter FundingAlerter, threshold decimal.Decimal) {
+	s.fundingAlerter = alerter
+	s.lowFundingThreshold = threshold
+}
+
+// GetFundingBalance returns the balance of the rewards funding pool
+func (s *Service) GetFundingBalance(ctx context.Context) (decimal.Decimal, error) {
+	if s.ledgerService == nil {
+		return decimal.Zero, fmt.Errorf("ledger service not configured")
+	}
+	pool, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemRewardsFunding)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("get rewards funding account: %w", err)
+	}
+	return pool.Balance, nil
+}
+
+// FundPool tops up the rewards funding pool from the treasury's USD buffer. The reference, e.g. a
+// treasury transfer ID, keys the ledger transaction so a retried top-up is only booked once.
+func (s *Service) FundPool(ctx context.Context, amount decimal.Decimal, reference string) (*entities.LedgerTransaction, error) {
+	if s.ledgerService == nil {
+		return nil, fmt.Errorf("ledger service not configured")
+	}
+	if !amount.IsPositive() {
+		return nil, ErrInvalidFundingAmount
+	}
+	if reference == "" {
+		return nil, fmt.Errorf("funding reference is required")
+	}
+
+	treasuryAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemBufferFiat)
+	if err != nil {
+		return nil, fmt.Errorf("get treasury account: %w", err)
+	}
+	poolAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemRewardsFunding)
+	if err != nil {
+		return nil, fmt.Errorf("get rewards funding account: %w", err)
+	}
+
+	desc := "Rewards pool funding"
+	referenceType := "rewards_funding"
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		TransactionType: entities.TransactionTypeBufferReplenishment,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  fmt.Sprintf("rewards-funding:%s", reference),
+		Description:     &desc,
+		Metadata:        map[string]any{"reference": reference},
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   treasuryAccount.ID,
+				EntryType:   entities.EntryTypeCredit, // Treasury down
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   poolAccount.ID,
+				EntryType:   entities.EntryTypeDebit, // Pool up
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create ledger transaction: %w", err)
+	}
+
+	s.logger.Info("Rewards pool funded",
+		zap.String("amount", amount.String()),
+		zap.String("reference", reference),
+		zap.String("ledger_transaction_id", ledgerTx.ID.String()))
+	return ledgerTx, nil
+}
+
+// checkFunding raises a low-balance alert when the pool is under the threshold or could not cover
+// every cleared reward in a payout run
+func (s *Service) checkFunding(ctx context.Context, balance, shortfall decimal.Decimal) {
+	if !shortfall.IsPositive() && !balance.LessThan(s.lowFundingThreshold) {
+		return
+	}
+
+	s.logger.Error("Rewards funding pool low",
+		zap.String("balance", balance.String()),
+		zap.String("threshold", s.lowFundingThreshold.String()),
+		zap.String("shortfall", shortfall.String()))
+	if s.fundingAlerter == nil {
+		return
+	}
+	if err := s.fundingAlerter.AlertLowRewardsFunding(ctx, balance, s.lowFundingThreshold, shortfall); err != nil {
+		s.logger.Warn("Failed to send rewards funding alert", zap.Error(err))
+	}
+}
@@ -0,0 +1,505 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+var (
+	ErrOfferNotFound            = errors.New("reward offer not found")
+	ErrInvalidOffer             = errors.New("invalid reward offer")
+	ErrInsufficientStashBalance = errors.New("insufficient stash balance to reclaim cashback")
+)
+
+// Repository interface for rewards persistence
+type Repository interface {
+	CreateOffer(ctx context.Context, offer *entities.RewardOffer) error
+	UpdateOffer(ctx context.Context, offer *entities.RewardOffer) error
+	GetOffer(ctx context.Context, id uuid.UUID) (*entities.RewardOffer, error)
+	ListOffers(ctx context.Context, activeOnly bool) ([]*entities.RewardOffer, error)
+	CreateReward(ctx context.Context, reward *entities.CashbackReward) error
+	GetRewardByCardTransaction(ctx context.Context, cardTransactionID uuid.UUID) (*entities.CashbackReward, error)
+	FindRefundableReward(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.CashbackReward, error)
+	UpdateRewardStatus(ctx context.Context, id uuid.UUID, status entities.CashbackStatus, ledgerTxID *uuid.UUID) error
+	ReduceReward(ctx context.Context, id uuid.UUID, purchaseAmount, amount decimal.Decimal) error
+	SumOfferRewardsSince(ctx context.Context, userID, offerID uuid.UUID, since time.Time) (decimal.Decimal, error)
+	GetRewardsReadyForPayout(ctx context.Context, now time.Time, limit int) ([]*entities.CashbackReward, error)
+	MarkPayoutAttempted(ctx context.Context, id uuid.UUID, attemptedAt time.Time) error
+	GetUserRewards(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CashbackReward, error)
+	GetUserSummary(ctx context.Context, userID uuid.UUID) (*entities.CashbackSummary, error)
+}
+
+// LedgerService moves cashback between the rewards funding pool and the user's stash
+type LedgerService interface {
+	GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error)
+	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	GetSystemAccount(ctx context.Context, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
+}
+
+// ContributionRecorder interface for recording contributions
+type ContributionRecorder interface {
+	RecordContribution(ctx context.Context, userID uuid.UUID, contributionType entities.ContributionType, amount decimal.Decimal, source string) error
+}
+
+// Service accrues cashback on settled card purchases and pays it into the stash once cleared
+type Service struct {
+	repo                 Repository
+	ledgerService        LedgerService
+	contributionRecorder ContributionRecorder
+	fundingAlerter       FundingAlerter
+	lowFundingThreshold  decimal.Decimal
+	clearingPeriod       time.Duration
+	logger               *zap.Logger
+}
+
+// NewService creates a new rewards service
+func NewService(
+	repo Repository,
+	ledgerService LedgerService,
+	contributionRecorder ContributionRecorder,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:                 repo,
+		ledgerService:        ledgerService,
+		contributionRecorder: contributionRecorder,
+		lowFundingThreshold:  DefaultLowFundingThreshold,
+		clearingPeriod:       entities.DefaultCashbackClearingPeriod,
+		logger:               logger,
+	}
+}
+
+// SetClearingPeriod overrides how long accrued cashback stays pending
+func (s *Service) SetClearingPeriod(period time.Duration) {
+	s.clearingPeriod = period
+}
+
+// AccrueCardSettlement accrues cashback for a settled card purchase from the best matching offer.
+// Each card transaction earns cashback at most once; purchases with no matching offer return nil.
+func (s *Service) AccrueCardSettlement(ctx context.Context, cardTx *entities.BridgeCardTransaction) (*entities.CashbackReward, error) {
+	existing, err := s.repo.GetRewardByCardTransaction(ctx, cardTx.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get reward by card transaction: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	purchase := cardTx.Amount.Abs()
+	if !purchase.IsPositive() {
+		return nil, nil
+	}
+
+	offers, err := s.repo.ListOffers(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("list offers: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var best *entities.RewardOffer
+	bestAmount := decimal.Zero
+	for _, offer := range offers {
+		if !offer.AppliesTo(cardTx, now) {
+			continue
+		}
+		amount, err := s.offerCashback(ctx, offer, cardTx.UserID, purchase, now)
+		if err != nil {
+			return nil, err
+		}
+		if amount.GreaterThan(bestAmount) {
+			best, bestAmount = offer, amount
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	merchantName := cardTx.MerchantDisplayName
+	if merchantName == nil {
+		merchantName = cardTx.MerchantName
+	}
+	reward := &entities.CashbackReward{
+		ID:                uuid.New(),
+		UserID:            cardTx.UserID,
+		OfferID:           best.ID,
+		OfferName:         best.Name,
+		CardTransactionID: cardTx.ID,
+		MerchantName:      merchantName,
+		MerchantKey:       cardTx.MerchantKey,
+		Category:          cardTx.Category,
+		PurchaseAmount:    purchase,
+		Rate:              best.Rate,
+		Amount:            bestAmount,
+		Status:            entities.CashbackStatusPending,
+		AvailableAt:       now.Add(s.clearingPeriod),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.repo.CreateReward(ctx, reward); err != nil {
+		return nil, fmt.Errorf("create reward: %w", err)
+	}
+
+	s.logger.Info("Cashback accrued",
+		zap.String("user_id", reward.UserID.String()),
+		zap.String("card_transaction_id", cardTx.ID.String()),
+		zap.String("offer_id", best.ID.String()),
+		zap.String("amount", reward.Amount.String()))
+	return reward, nil
+}
+
+// offerCashback computes the cashback an offer pays on a purchase after its caps, rounded down to cents
+func (s *Service) offerCashback(ctx context.Context, offer *entities.RewardOffer, userID uuid.UUID, purchase decimal.Decimal, now time.Time) (decimal.Decimal, error) {
+	amount := purchase.Mul(offer.Rate).RoundDown(2)
+	if offer.PerTransactionCap != nil {
+		amount = decimal.Min(amount, *offer.PerTransactionCap)
+	}
+	if offer.MonthlyCap != nil && amount.IsPositive() {
+		earned, err := s.repo.SumOfferRewardsSince(ctx, userID, offer.ID, startOfMonth(now))
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("sum offer rewards: %w", err)
+		}
+		amount = decimal.Min(amount, offer.MonthlyCap.Sub(earned))
+	}
+	return decimal.Max(amount, decimal.Zero), nil
+}
+
+// ReverseCardSettlement reverses the cashback of a refunded purchase. A reversed transaction is
+// matched by ID; a refund transaction is matched to the latest rewarded purchase at the same
+// merchant of at least the refunded amount, and a partial refund reverses a matching share of
+// the cashback. Cashback already paid out is reclaimed from the stash.
+func (s *Service) ReverseCardSettlement(ctx context.Context, cardTx *entities.BridgeCardTransaction) error {
+	var reward *entities.CashbackReward
+	var err error
+	refunded := cardTx.Amount.Abs()
+	if cardTx.Type == "refund" {
+		if cardTx.MerchantKey == nil {
+			return nil
+		}
+		reward, err = s.repo.FindRefundableReward(ctx, cardTx.UserID, *cardTx.MerchantKey, refunded)
+	} else {
+		reward, err = s.repo.GetRewardByCardTransaction(ctx, cardTx.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("get reward: %w", err)
+	}
+	if reward == nil || reward.Status == entities.CashbackStatusReversed {
+		return nil
+	}
+
+	partial := cardTx.Type == "refund" && refunded.LessThan(reward.PurchaseAmount)
+	clawback := reward.Amount
+	var refundID *uuid.UUID
+	if partial {
+		clawback = entities.RefundShare(reward.Amount, reward.PurchaseAmount, refunded)
+		refundID = &cardTx.ID
+	}
+
+	var reversalTxID *uuid.UUID
+	if reward.Status == entities.CashbackStatusPaid && clawback.IsPositive() {
+		if s.ledgerService == nil {
+			return fmt.Errorf("ledger service required to reclaim paid cashback")
+		}
+		balance, err := s.ledgerService.GetAccountBalance(ctx, reward.UserID, entities.AccountTypeStashBalance)
+		if err != nil {
+			return fmt.Errorf("get stash balance: %w", err)
+		}
+		if balance.LessThan(clawback) {
+			return ErrInsufficientStashBalance
+		}
+		if reversalTxID, err = s.transferCashback(ctx, reward, clawback, true, refundID); err != nil {
+			return err
+		}
+	}
+
+	// A partial refund that leaves no cashback reverses the reward outright
+	if partial && reward.Amount.Sub(clawback).IsPositive() {
+		if err := s.repo.ReduceReward(ctx, reward.ID, reward.PurchaseAmount.Sub(refunded), reward.Amount.Sub(clawback)); err != nil {
+			return fmt.Errorf("reduce reward: %w", err)
+		}
+	} else if err := s.repo.UpdateRewardStatus(ctx, reward.ID, entities.CashbackStatusReversed, reversalTxID); err != nil {
+		return fmt.Errorf("update reward status: %w", err)
+	}
+
+	s.logger.Info("Cashback reversed",
+		zap.String("user_id", reward.UserID.String()),
+		zap.String("reward_id", reward.ID.String()),
+		zap.String("amount", clawback.String()),
+		zap.Bool("partial", partial),
+		zap.Bool("reclaimed", reversalTxID != nil))
+	return nil
+}
+
+// ReleaseClearedRewards clears rewards past their clearing period and pays them into the stash.
+// Rewards whose payout fails, including those the funding pool cannot cover, stay available and
+// are retried after rewards not yet attempted, so a short pool never stalls the batch on the same
+// held rewards. A pool that is low or short raises a funding alert.
+func (s *Service) ReleaseClearedRewards(ctx context.Context, batchSize int) (int, error) {
+	if s.ledgerService == nil {
+		return 0, fmt.Errorf("ledger service not configured")
+	}
+
+	now := time.Now().UTC()
+	rewards, err := s.repo.GetRewardsReadyForPayout(ctx, now, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("get rewards ready for payout: %w", err)
+	}
+	if len(rewards) == 0 {
+		return 0, nil
+	}
+
+	pool, err := s.GetFundingBalance(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0
+	shortfall := decimal.Zero
+	for _, reward := range rewards {
+		if reward.Status == entities.CashbackStatusPending {
+			if err := s.repo.UpdateRewardStatus(ctx, reward.ID, entities.CashbackStatusAvailable, nil); err != nil {
+				s.logger.Error("Failed to clear cashback", zap.String("reward_id", reward.ID.String()), zap.Error(err))
+				continue
+			}
+			reward.Status = entities.CashbackStatusAvailable
+		}
+
+		if pool.LessThan(reward.Amount) {
+			shortfall = shortfall.Add(reward.Amount)
+			s.holdReward(ctx, reward, now)
+			continue
+		}
+		ledgerTxID, err := s.transferCashback(ctx, reward, reward.Amount, false, nil)
+		if err != nil {
+			s.logger.Error("Failed to pay out cashback", zap.String("reward_id", reward.ID.String()), zap.Error(err))
+			s.holdReward(ctx, reward, now)
+			continue
+		}
+		pool = pool.Sub(reward.Amount)
+		if err := s.repo.UpdateRewardStatus(ctx, reward.ID, entities.CashbackStatusPaid, ledgerTxID); err != nil {
+			s.logger.Error("Failed to mark cashback paid", zap.String("reward_id", reward.ID.String()), zap.Error(err))
+			continue
+		}
+		paid++
+
+		if s.contributionRecorder != nil {
+			if err := s.contributionRecorder.RecordContribution(ctx, reward.UserID, entities.ContributionTypeCashback, reward.Amount, "card_cashback"); err != nil {
+				s.logger.Warn("Failed to record cashback contribution", zap.String("reward_id", reward.ID.String()), zap.Error(err))
+			}
+		}
+	}
+
+	if paid > 0 {
+		s.logger.Info("Cashback paid out", zap.Int("count", paid))
+	}
+	s.checkFunding(ctx, pool, shortfall)
+	return paid, nil
+}
+
+// holdReward records an unpaid payout attempt so the reward is retried after rewards not yet attempted
+func (s *Service) holdReward(ctx context.Context, reward *entities.CashbackReward, now time.Time) {
+	if err := s.repo.MarkPayoutAttempted(ctx, reward.ID, now); err != nil {
+		s.logger.Warn("Failed to record cashback payout attempt", zap.String("reward_id", reward.ID.String()), zap.Error(err))
+		return
+	}
+	reward.PayoutAttemptedAt = &now
+}
+
+// transferCashback moves cashback from the rewards funding pool into the user's stash, or back
+// on reversal. Idempotency keys are derived from the reward, and from the refund for partial
+// reversals, so retries never pay twice.
+func (s *Service) transferCashback(ctx context.Context, reward *entities.CashbackReward, amount decimal.Decimal, reverse bool, refundID *uuid.UUID) (*uuid.UUID, error) {
+	fundingAccount, err := s.ledgerService.GetSystemAccount(ctx, entities.AccountTypeSystemRewardsFunding)
+	if err != nil {
+		return nil, fmt.Errorf("get rewards funding account: %w", err)
+	}
+	stashAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, reward.UserID, entities.AccountTypeStashBalance)
+	if err != nil {
+		return nil, fmt.Errorf("get stash account: %w", err)
+	}
+
+	fundingEntry, stashEntry := entities.EntryTypeCredit, entities.EntryTypeDebit // Pool down, stash up
+	desc := fmt.Sprintf("Cashback: %s", reward.OfferName)
+	idempotencyKey := fmt.Sprintf("cashback:%s", reward.ID)
+	if reverse {
+		fundingEntry, stashEntry = entities.EntryTypeDebit, entities.EntryTypeCredit
+		desc = fmt.Sprintf("Cashback reversal: %s", reward.OfferName)
+		idempotencyKey = fmt.Sprintf("cashback-reversal:%s", reward.ID)
+		if refundID != nil {
+			idempotencyKey = fmt.Sprintf("cashback-reversal:%s:%s", reward.ID, refundID)
+		}
+	}
+	referenceType := "cashback_reward"
+
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &reward.UserID,
+		TransactionType: entities.TransactionTypeCashback,
+		ReferenceID:     &reward.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  idempotencyKey,
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   fundingAccount.ID,
+				EntryType:   fundingEntry,
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   stashAccount.ID,
+				EntryType:   stashEntry,
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create ledger transaction: %w", err)
+	}
+	return &ledgerTx.ID, nil
+}
+
+// GetSummary returns a user's cashback totals
+func (s *Service) GetSummary(ctx context.Context, userID uuid.UUID) (*entities.CashbackSummary, error) {
+	summary, err := s.repo.GetUserSummary(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetRewards returns a user's cashback history, newest first
+func (s *Service) GetRewards(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CashbackReward, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	rewards, err := s.repo.GetUserRewards(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get rewards: %w", err)
+	}
+	return rewards, nil
+}
+
+// ListOffers returns reward offers; users only see offers that are currently running
+func (s *Service) ListOffers(ctx context.Context, activeOnly bool) ([]*entities.RewardOffer, error) {
+	offers, err := s.repo.ListOffers(ctx, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list offers: %w", err)
+	}
+	if !activeOnly {
+		return offers, nil
+	}
+
+	now := time.Now().UTC()
+	running := make([]*entities.RewardOffer, 0, len(offers))
+	for _, offer := range offers {
+		if !now.Before(offer.StartsAt) && (offer.EndsAt == nil || now.Before(*offer.EndsAt)) {
+			running = append(running, offer)
+		}
+	}
+	return running, nil
+}
+
+// CreateOffer creates a cashback offer
+func (s *Service) CreateOffer(ctx context.Context, req *entities.CreateRewardOfferRequest) (*entities.RewardOffer, error) {
+	now := time.Now().UTC()
+	offer := &entities.RewardOffer{
+		ID:                uuid.New(),
+		Name:              strings.TrimSpace(req.Name),
+		Description:       req.Description,
+		Category:          req.Category,
+		Rate:              req.Rate,
+		PerTransactionCap: req.PerTransactionCap,
+		MonthlyCap:        req.MonthlyCap,
+		StartsAt:          now,
+		EndsAt:            req.EndsAt,
+		Active:            true,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if req.Merchant != nil {
+		key := NormalizeMerchantKey(*req.Merchant)
+		offer.MerchantKey = &key
+	}
+	if req.StartsAt != nil {
+		offer.StartsAt = req.StartsAt.UTC()
+	}
+	if err := offer.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOffer, err)
+	}
+
+	if err := s.repo.CreateOffer(ctx, offer); err != nil {
+		return nil, fmt.Errorf("create offer: %w", err)
+	}
+	s.logger.Info("Reward offer created",
+		zap.String("offer_id", offer.ID.String()),
+		zap.String("name", offer.Name),
+		zap.String("rate", offer.Rate.String()))
+	return offer, nil
+}
+
+// UpdateOffer applies a partial update to an offer
+func (s *Service) UpdateOffer(ctx context.Context, id uuid.UUID, req *entities.UpdateRewardOfferRequest) (*entities.RewardOffer, error) {
+	offer, err := s.repo.GetOffer(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get offer: %w", err)
+	}
+	if offer == nil {
+		return nil, ErrOfferNotFound
+	}
+
+	if req.Active != nil {
+		offer.Active = *req.Active
+	}
+	if req.Rate != nil {
+		offer.Rate = *req.Rate
+	}
+	if req.PerTransactionCap != nil {
+		offer.PerTransactionCap = positiveOrNil(*req.PerTransactionCap)
+	}
+	if req.MonthlyCap != nil {
+		offer.MonthlyCap = positiveOrNil(*req.MonthlyCap)
+	}
+	if req.EndsAt != nil {
+		endsAt := req.EndsAt.UTC()
+		offer.EndsAt = &endsAt
+	}
+	if err := offer.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOffer, err)
+	}
+
+	offer.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateOffer(ctx, offer); err != nil {
+		return nil, fmt.Errorf("update offer: %w", err)
+	}
+	return offer, nil
+}
+
+// NormalizeMerchantKey converts a merchant name into the upper-case key card transactions are
+// enriched with, e.g. "Starbucks" -> "STARBUCKS"
+func NormalizeMerchantKey(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), " "))
+}
+
+// positiveOrNil treats a zero cap as "no cap"
+func positiveOrNil(d decimal.Decimal) *decimal.Decimal {
+	if !d.IsPositive() {
+		return nil
+	}
+	return &d
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
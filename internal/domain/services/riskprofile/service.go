@@ -0,0 +1,346 @@
+package riskprofile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/strategy"
+)
+
+// DefaultReassessAfterDays is how long an assessment stays current when a questionnaire sets no period
+const DefaultReassessAfterDays = 365
+
+// Repository persists risk questionnaires, assessments and acknowledgments
+type Repository interface {
+	CreateQuestionnaire(ctx context.Context, questionnaire *entities.RiskQuestionnaire) error
+	GetActiveQuestionnaire(ctx context.Context) (*entities.RiskQuestionnaire, error)
+	GetQuestionnaires(ctx context.Context) ([]*entities.RiskQuestionnaire, error)
+	CreateAssessment(ctx context.Context, assessment *entities.RiskAssessment) error
+	GetLatestAssessment(ctx context.Context, userID uuid.UUID) (*entities.RiskAssessment, error)
+	GetAssessmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.RiskAssessment, error)
+	GetAssessmentsDueForReassessment(ctx context.Context, at time.Time, limit int) ([]*entities.RiskAssessment, error)
+	MarkReassessmentPrompted(ctx context.Context, assessmentID uuid.UUID) error
+	CreateAcknowledgment(ctx context.Context, ack *entities.RiskAcknowledgment) error
+	GetAcknowledgments(ctx context.Context, userID, assessmentID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID) ([]*entities.RiskAcknowledgment, error)
+}
+
+// AuditLogger records assessments and risk acknowledgments in the compliance audit log
+type AuditLogger interface {
+	Log(ctx context.Context, userID uuid.UUID, action entities.AuditAction, resource string, resourceID *uuid.UUID, metadata map[string]interface{}) error
+}
+
+// Notifier prompts users to retake the questionnaire
+type Notifier interface {
+	SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error
+}
+
+// Service scores risk questionnaires and checks investments against users' risk bands
+type Service struct {
+	repo        Repository
+	auditLogger AuditLogger
+	notifier    Notifier
+	logger      *zap.Logger
+}
+
+// NewService creates a new risk profile service
+func NewService(repo Repository, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SetAuditLogger sets the audit log for assessments and acknowledgments (optional)
+func (s *Service) SetAuditLogger(auditLogger AuditLogger) {
+	s.auditLogger = auditLogger
+}
+
+// SetNotifier sets the notifier for re-assessment prompts (optional)
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// === Questionnaire Operations ===
+
+// GetActiveQuestionnaire returns the questionnaire version users answer
+func (s *Service) GetActiveQuestionnaire(ctx context.Context) (*entities.RiskQuestionnaire, error) {
+	questionnaire, err := s.repo.GetActiveQuestionnaire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaire: %w", err)
+	}
+	if questionnaire == nil {
+		return nil, fmt.Errorf("risk questionnaire not configured")
+	}
+	return questionnaire, nil
+}
+
+// ListQuestionnaires returns every questionnaire version, newest first
+func (s *Service) ListQuestionnaires(ctx context.Context) ([]*entities.RiskQuestionnaire, error) {
+	questionnaires, err := s.repo.GetQuestionnaires(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires: %w", err)
+	}
+	return questionnaires, nil
+}
+
+// PublishQuestionnaire stores a new questionnaire version and makes it the active one. Users
+// assessed on an older version are prompted to retake it.
+func (s *Service) PublishQuestionnaire(ctx context.Context, adminID uuid.UUID, req *entities.CreateRiskQuestionnaireRequest) (*entities.RiskQuestionnaire, error) {
+	if err := validateQuestionnaire(req); err != nil {
+		return nil, err
+	}
+
+	questionnaires, err := s.repo.GetQuestionnaires(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires: %w", err)
+	}
+	next := 1
+	if len(questionnaires) > 0 {
+		next = questionnaires[0].Version + 1
+	}
+
+	reassessAfter := req.ReassessAfterDays
+	if reassessAfter == 0 {
+		reassessAfter = DefaultReassessAfterDays
+	}
+	bands := append([]entities.RiskBandThreshold(nil), req.Bands...)
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinScore < bands[j].MinScore })
+
+	questionnaire := &entities.RiskQuestionnaire{
+		ID:                uuid.New(),
+		Version:           next,
+		Status:            entities.RiskQuestionnaireStatusActive,
+		Questions:         req.Questions,
+		Bands:             bands,
+		ReassessAfterDays: reassessAfter,
+		CreatedBy:         &adminID,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err := s.repo.CreateQuestionnaire(ctx, questionnaire); err != nil {
+		return nil, fmt.Errorf("failed to create questionnaire: %w", err)
+	}
+
+	s.logger.Info("Risk questionnaire published",
+		zap.Int("version", next),
+		zap.String("admin_id", adminID.String()))
+
+	return questionnaire, nil
+}
+
+// === Assessment Operations ===
+
+// SubmitAssessment scores a user's answers to the active questionnaire and stores the result as
+// their current risk profile
+func (s *Service) SubmitAssessment(ctx context.Context, userID uuid.UUID, req *entities.SubmitRiskAssessmentRequest) (*entities.RiskAssessment, error) {
+	questionnaire, err := s.GetActiveQuestionnaire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	score, horizon, err := scoreAnswers(questionnaire, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	assessment := &entities.RiskAssessment{
+		ID:                   uuid.New(),
+		UserID:               userID,
+		QuestionnaireID:      questionnaire.ID,
+		QuestionnaireVersion: questionnaire.Version,
+		Answers:              req.Answers,
+		Score:                score,
+		RiskBand:             bandForScore(questionnaire.Bands, score),
+		TimeHorizonYears:     horizon,
+		ReassessAt:           now.AddDate(0, 0, questionnaire.ReassessAfterDays),
+		CreatedAt:            now,
+	}
+	if err := s.repo.CreateAssessment(ctx, assessment); err != nil {
+		return nil, fmt.Errorf("failed to save risk assessment: %w", err)
+	}
+
+	s.audit(ctx, userID, entities.AuditActionRiskAssessment, "risk_assessment", &assessment.ID, map[string]interface{}{
+		"questionnaire_version": assessment.QuestionnaireVersion,
+		"score":                 assessment.Score,
+		"risk_band":             assessment.RiskBand,
+	})
+
+	s.logger.Info("Risk assessment submitted",
+		zap.String("user_id", userID.String()),
+		zap.Int("score", score),
+		zap.String("risk_band", string(assessment.RiskBand)))
+
+	return assessment, nil
+}
+
+// GetProfile returns a user's current assessment and whether it is due for re-assessment,
+// either because it has expired or because a newer questionnaire version was published
+func (s *Service) GetProfile(ctx context.Context, userID uuid.UUID) (*entities.RiskProfile, error) {
+	assessment, err := s.repo.GetLatestAssessment(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessment: %w", err)
+	}
+	if assessment == nil {
+		return &entities.RiskProfile{ReassessmentDue: true, ReassessmentReason: "not_assessed"}, nil
+	}
+
+	profile := &entities.RiskProfile{Assessment: assessment}
+	if !time.Now().Before(assessment.ReassessAt) {
+		profile.ReassessmentDue = true
+		profile.ReassessmentReason = "expired"
+		return profile, nil
+	}
+
+	questionnaire, err := s.repo.GetActiveQuestionnaire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaire: %w", err)
+	}
+	if questionnaire != nil && questionnaire.Version > assessment.QuestionnaireVersion {
+		profile.ReassessmentDue = true
+		profile.ReassessmentReason = "questionnaire_updated"
+	}
+	return profile, nil
+}
+
+// GetAssessmentHistory returns a user's assessments, newest first
+func (s *Service) GetAssessmentHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.RiskAssessment, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	assessments, err := s.repo.GetAssessmentsByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessments: %w", err)
+	}
+	return assessments, nil
+}
+
+// GetRiskProfile implements strategy.RiskProfileProvider. Users without an assessment have no
+// profile, and an expired assessment still applies until it is retaken.
+func (s *Service) GetRiskProfile(ctx context.Context, userID uuid.UUID) (*strategy.RiskProfile, error) {
+	assessment, err := s.repo.GetLatestAssessment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment == nil {
+		return nil, nil
+	}
+	return &strategy.RiskProfile{
+		RiskBand:         string(assessment.RiskBand),
+		TimeHorizonYears: assessment.TimeHorizonYears,
+	}, nil
+}
+
+// SendReassessmentPrompts notifies users whose assessments have expired, once per assessment.
+// Returns the number of users prompted.
+func (s *Service) SendReassessmentPrompts(ctx context.Context, limit int) (int, error) {
+	due, err := s.repo.GetAssessmentsDueForReassessment(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get assessments due for reassessment: %w", err)
+	}
+
+	prompted := 0
+	for _, assessment := range due {
+		if s.notifier != nil {
+			if err := s.notifier.SendGenericNotification(ctx, assessment.UserID, "Time to review your risk profile",
+				"Your circumstances may have changed since you last answered the risk questionnaire. Retake it so your investments stay suitable."); err != nil {
+				s.logger.Warn("Failed to send reassessment prompt",
+					zap.String("user_id", assessment.UserID.String()),
+					zap.Error(err))
+				continue
+			}
+		}
+		if err := s.repo.MarkReassessmentPrompted(ctx, assessment.ID); err != nil {
+			s.logger.Error("Failed to mark reassessment prompted",
+				zap.String("assessment_id", assessment.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		prompted++
+	}
+	return prompted, nil
+}
+
+// scoreAnswers totals the option scores for a complete set of answers and returns the investment
+// horizon if a chosen option sets one
+func scoreAnswers(questionnaire *entities.RiskQuestionnaire, answers map[string]string) (int, *int, error) {
+	score := 0
+	var horizon *int
+	for _, question := range questionnaire.Questions {
+		value, ok := answers[question.Key]
+		if !ok {
+			return 0, nil, fmt.Errorf("missing answer for question %s", question.Key)
+		}
+		var chosen *entities.RiskQuestionOption
+		for i := range question.Options {
+			if question.Options[i].Value == value {
+				chosen = &question.Options[i]
+				break
+			}
+		}
+		if chosen == nil {
+			return 0, nil, fmt.Errorf("invalid answer %q for question %s", value, question.Key)
+		}
+		score += chosen.Score
+		if chosen.HorizonYears != nil {
+			years := *chosen.HorizonYears
+			horizon = &years
+		}
+	}
+	if len(answers) != len(questionnaire.Questions) {
+		return 0, nil, fmt.Errorf("answers include unknown questions")
+	}
+	return score, horizon, nil
+}
+
+// bandForScore returns the band with the highest threshold the score reaches. Bands are sorted
+// by ascending minimum score.
+func bandForScore(bands []entities.RiskBandThreshold, score int) entities.RiskBand {
+	band := entities.RiskBandConservative
+	for _, threshold := range bands {
+		if score >= threshold.MinScore {
+			band = threshold.Band
+		}
+	}
+	return band
+}
+
+// validateQuestionnaire checks that question keys and option values are unique and that every
+// band is a known band listed once
+func validateQuestionnaire(req *entities.CreateRiskQuestionnaireRequest) error {
+	keys := make(map[string]bool, len(req.Questions))
+	for _, question := range req.Questions {
+		if keys[question.Key] {
+			return fmt.Errorf("question keys must be unique, got %s twice", question.Key)
+		}
+		keys[question.Key] = true
+
+		values := make(map[string]bool, len(question.Options))
+		for _, option := range question.Options {
+			if values[option.Value] {
+				return fmt.Errorf("question %s repeats option %s", question.Key, option.Value)
+			}
+			values[option.Value] = true
+			if option.HorizonYears != nil && *option.HorizonYears < 0 {
+				return fmt.Errorf("horizon_years must not be negative")
+			}
+		}
+	}
+
+	bands := make(map[entities.RiskBand]bool, len(req.Bands))
+	for _, threshold := range req.Bands {
+		if !threshold.Band.IsValid() {
+			return fmt.Errorf("invalid risk band %s", threshold.Band)
+		}
+		if bands[threshold.Band] {
+			return fmt.Errorf("risk bands must be unique, got %s twice", threshold.Band)
+		}
+		bands[threshold.Band] = true
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package riskprofile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Suitability Operations ===
+
+// CheckSuitability allows an investment whose risk band is at or below the user's assessed band.
+// A riskier investment needs an acknowledgment: an earlier one for the same product and resource
+// under the current assessment, or a new one when acknowledged is true, which is recorded in the
+// audit log. Otherwise it returns entities.ErrRiskAcknowledgmentRequired. Users who have not been
+// assessed, and products without a known band, are not restricted.
+func (s *Service) CheckSuitability(ctx context.Context, userID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand, acknowledged bool) error {
+	if !productBand.IsValid() {
+		return nil
+	}
+
+	assessment, err := s.repo.GetLatestAssessment(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get risk assessment: %w", err)
+	}
+	if assessment == nil || !productBand.Exceeds(assessment.RiskBand) {
+		return nil
+	}
+
+	acks, err := s.repo.GetAcknowledgments(ctx, userID, assessment.ID, product, resourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get risk acknowledgments: %w", err)
+	}
+	for _, ack := range acks {
+		if !productBand.Exceeds(ack.ProductRiskBand) {
+			return nil
+		}
+	}
+
+	if !acknowledged {
+		s.logger.Info("Investment above risk band needs acknowledgment",
+			zap.String("user_id", userID.String()),
+			zap.String("product", string(product)),
+			zap.String("product_risk_band", string(productBand)),
+			zap.String("user_risk_band", string(assessment.RiskBand)))
+		return entities.ErrRiskAcknowledgmentRequired
+	}
+
+	_, err = s.recordAcknowledgment(ctx, assessment, product, resourceID, productBand)
+	return err
+}
+
+// AcknowledgeRisk records a standing acknowledgment that lets auto-investment use strategies up to
+// a risk band above the user's own. It lasts until the user is re-assessed.
+func (s *Service) AcknowledgeRisk(ctx context.Context, userID uuid.UUID, req *entities.AcknowledgeRiskRequest) (*entities.RiskAcknowledgment, error) {
+	band := req.RiskBand
+	if band == "" {
+		band = entities.RiskBandAggressive
+	}
+	if !band.IsValid() {
+		return nil, fmt.Errorf("invalid risk band %s", band)
+	}
+
+	assessment, err := s.repo.GetLatestAssessment(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessment: %w", err)
+	}
+	if assessment == nil {
+		return nil, fmt.Errorf("risk assessment required")
+	}
+	if !band.Exceeds(assessment.RiskBand) {
+		return nil, fmt.Errorf("risk band %s is within your risk profile", band)
+	}
+
+	return s.recordAcknowledgment(ctx, assessment, req.Product, nil, band)
+}
+
+func (s *Service) recordAcknowledgment(ctx context.Context, assessment *entities.RiskAssessment, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand) (*entities.RiskAcknowledgment, error) {
+	ack := &entities.RiskAcknowledgment{
+		ID:              uuid.New(),
+		UserID:          assessment.UserID,
+		AssessmentID:    assessment.ID,
+		Product:         product,
+		ResourceID:      resourceID,
+		ProductRiskBand: productBand,
+		UserRiskBand:    assessment.RiskBand,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := s.repo.CreateAcknowledgment(ctx, ack); err != nil {
+		return nil, fmt.Errorf("failed to record risk acknowledgment: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"product":           ack.Product,
+		"product_risk_band": ack.ProductRiskBand,
+		"user_risk_band":    ack.UserRiskBand,
+		"assessment_id":     ack.AssessmentID.String(),
+	}
+	if resourceID != nil {
+		metadata["resource_id"] = resourceID.String()
+	}
+	s.audit(ctx, ack.UserID, entities.AuditActionRiskAcknowledge, "risk_acknowledgment", &ack.ID, metadata)
+
+	s.logger.Info("Risk acknowledgment recorded",
+		zap.String("user_id", ack.UserID.String()),
+		zap.String("product", string(product)),
+		zap.String("product_risk_band", string(productBand)),
+		zap.String("user_risk_band", string(ack.UserRiskBand)))
+
+	return ack, nil
+}
+
+// audit writes an audit log entry, logging rather than returning failures
+func (s *Service) audit(ctx context.Context, userID uuid.UUID, action entities.AuditAction, resource string, resourceID *uuid.UUID, metadata map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	if err := s.auditLogger.Log(ctx, userID, action, resource, resourceID, metadata); err != nil {
+		s.logger.Warn("Failed to write audit log",
+			zap.String("action", string(action)),
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,274 @@
+package roundup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+var ErrInsufficientSpendingBalance = errors.New("insufficient spending balance for round-up")
+
+// LedgerService moves round-up funds between the user's ledger accounts
+type LedgerService interface {
+	GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error)
+	GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error)
+}
+
+// SetLedgerService sets the ledger service used to sweep spare change into the stash
+func (s *Service) SetLedgerService(ledgerService LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// ProcessCardSettlement generates a round-up for a settled card purchase and sweeps the spare
+// change from the spending balance to the stash. Each card transaction rounds up at most once.
+// Without a ledger service the round-up stays pending for CollectPendingRoundups.
+func (s *Service) ProcessCardSettlement(ctx context.Context, cardTx *entities.BridgeCardTransaction) (*entities.RoundupTransaction, error) {
+	sourceRef := cardTx.ID.String()
+	existing, err := s.repo.GetTransactionBySourceRef(ctx, cardTx.UserID, entities.RoundupSourceCard, sourceRef)
+	if err != nil {
+		return nil, fmt.Errorf("get round-up by source: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	settings, err := s.GetSettings(ctx, cardTx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	amount := cardTx.Amount.Abs()
+	if !settings.Enabled || !amount.IsPositive() {
+		return nil, nil
+	}
+
+	eval, err := s.evaluateRoundup(ctx, settings, amount)
+	if err != nil {
+		return nil, err
+	}
+	multiplied := eval.MultipliedAmount
+	merchantName := cardTx.MerchantDisplayName
+	if merchantName == nil {
+		merchantName = cardTx.MerchantName
+	}
+
+	now := time.Now()
+	tx := &entities.RoundupTransaction{
+		ID:               uuid.New(),
+		UserID:           cardTx.UserID,
+		OriginalAmount:   amount,
+		RoundedAmount:    eval.RoundedAmount,
+		SpareChange:      eval.SpareChange,
+		MultipliedAmount: multiplied,
+		SourceType:       entities.RoundupSourceCard,
+		SourceRef:        &sourceRef,
+		MerchantName:     merchantName,
+		Status:           entities.RoundupStatusPending,
+		CreatedAt:        now,
+	}
+
+	switch {
+	case eval.Skipped:
+		tx.Status = entities.RoundupStatusSkipped
+		tx.SkipReason = &eval.SkipReason
+	case s.ledgerService != nil:
+		ledgerTxID, err := s.transferRoundup(ctx, tx, multiplied, false, nil)
+		switch {
+		case errors.Is(err, ErrInsufficientSpendingBalance):
+			tx.Status = entities.RoundupStatusFailed
+		case err != nil:
+			return nil, err
+		default:
+			tx.Status = entities.RoundupStatusCollected
+			tx.CollectedAt = &now
+			tx.LedgerTxID = ledgerTxID
+		}
+	}
+
+	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("create transaction: %w", err)
+	}
+
+	if tx.Status == entities.RoundupStatusCollected || tx.Status == entities.RoundupStatusPending {
+		acc, err := s.repo.GetAccumulator(ctx, cardTx.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("get accumulator: %w", err)
+		}
+		if tx.Status == entities.RoundupStatusCollected {
+			acc.TotalCollected = acc.TotalCollected.Add(multiplied)
+			acc.LastCollectionAt = &now
+		} else {
+			acc.PendingAmount = acc.PendingAmount.Add(multiplied)
+		}
+		if err := s.repo.UpsertAccumulator(ctx, acc); err != nil {
+			return nil, fmt.Errorf("update accumulator: %w", err)
+		}
+	}
+
+	s.logger.Info("Card round-up processed",
+		zap.String("user_id", cardTx.UserID.String()),
+		zap.String("card_transaction_id", sourceRef),
+		zap.String("amount", multiplied.String()),
+		zap.String("status", string(tx.Status)))
+
+	return tx, nil
+}
+
+// ReverseCardSettlement reverses the round-up of a refunded card purchase, returning swept
+// spare change to the spending balance. A reversed transaction is matched by ID; a refund
+// transaction is matched to the latest rounded-up purchase at the same merchant of at least the
+// refunded amount, and a partial refund reverses a matching share of the round-up. Invested
+// round-ups are left in place.
+func (s *Service) ReverseCardSettlement(ctx context.Context, cardTx *entities.BridgeCardTransaction) error {
+	var tx *entities.RoundupTransaction
+	var err error
+	refunded := cardTx.Amount.Abs()
+	if cardTx.Type == "refund" {
+		if cardTx.MerchantKey == nil {
+			return nil
+		}
+		tx, err = s.repo.FindRefundableRoundup(ctx, cardTx.UserID, *cardTx.MerchantKey, refunded)
+	} else {
+		tx, err = s.repo.GetTransactionBySourceRef(ctx, cardTx.UserID, entities.RoundupSourceCard, cardTx.ID.String())
+	}
+	if err != nil {
+		return fmt.Errorf("get round-up by source: %w", err)
+	}
+	if tx == nil {
+		return nil
+	}
+
+	switch tx.Status {
+	case entities.RoundupStatusCollected, entities.RoundupStatusPending:
+	case entities.RoundupStatusInvested:
+		s.logger.Warn("Round-up already invested, not reversing",
+			zap.String("roundup_id", tx.ID.String()),
+			zap.String("card_transaction_id", cardTx.ID.String()))
+		return nil
+	default:
+		return nil
+	}
+
+	partial := cardTx.Type == "refund" && refunded.LessThan(tx.OriginalAmount)
+	reversed := tx.MultipliedAmount
+	var refundID *uuid.UUID
+	if partial {
+		reversed = entities.RefundShare(tx.MultipliedAmount, tx.OriginalAmount, refunded)
+		refundID = &cardTx.ID
+	}
+
+	collected := tx.Status == entities.RoundupStatusCollected
+	if collected && tx.LedgerTxID != nil && reversed.IsPositive() {
+		if s.ledgerService == nil {
+			return fmt.Errorf("ledger service required to reverse collected round-up")
+		}
+		if _, err := s.transferRoundup(ctx, tx, reversed, true, refundID); err != nil {
+			return err
+		}
+	}
+
+	if partial {
+		if err := s.repo.ReduceTransaction(ctx, tx.ID, tx.OriginalAmount.Sub(refunded), tx.MultipliedAmount.Sub(reversed)); err != nil {
+			return fmt.Errorf("reduce transaction: %w", err)
+		}
+	} else if err := s.repo.UpdateTransactionStatus(ctx, tx.ID, entities.RoundupStatusReversed, nil); err != nil {
+		return fmt.Errorf("update transaction status: %w", err)
+	}
+
+	acc, err := s.repo.GetAccumulator(ctx, tx.UserID)
+	if err != nil {
+		return fmt.Errorf("get accumulator: %w", err)
+	}
+	if collected {
+		acc.TotalCollected = decimal.Max(acc.TotalCollected.Sub(reversed), decimal.Zero)
+	} else {
+		acc.PendingAmount = decimal.Max(acc.PendingAmount.Sub(reversed), decimal.Zero)
+	}
+	if err := s.repo.UpsertAccumulator(ctx, acc); err != nil {
+		return fmt.Errorf("update accumulator: %w", err)
+	}
+
+	s.logger.Info("Card round-up reversed",
+		zap.String("user_id", tx.UserID.String()),
+		zap.String("roundup_id", tx.ID.String()),
+		zap.String("amount", reversed.String()),
+		zap.Bool("partial", partial))
+	return nil
+}
+
+// transferRoundup moves a round-up between the spending and stash balances. Idempotency keys
+// are derived from the source transaction, and from the refund for partial reversals, so
+// retries never move the spare change twice.
+func (s *Service) transferRoundup(ctx context.Context, tx *entities.RoundupTransaction, amount decimal.Decimal, reverse bool, refundID *uuid.UUID) (*uuid.UUID, error) {
+	if !reverse {
+		balance, err := s.ledgerService.GetAccountBalance(ctx, tx.UserID, entities.AccountTypeSpendingBalance)
+		if err != nil {
+			return nil, fmt.Errorf("get spending balance: %w", err)
+		}
+		if balance.LessThan(amount) {
+			return nil, ErrInsufficientSpendingBalance
+		}
+	}
+
+	spendAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, tx.UserID, entities.AccountTypeSpendingBalance)
+	if err != nil {
+		return nil, fmt.Errorf("get spending account: %w", err)
+	}
+	stashAccount, err := s.ledgerService.GetOrCreateUserAccount(ctx, tx.UserID, entities.AccountTypeStashBalance)
+	if err != nil {
+		return nil, fmt.Errorf("get stash account: %w", err)
+	}
+
+	sourceRef := ""
+	if tx.SourceRef != nil {
+		sourceRef = *tx.SourceRef
+	}
+	spendEntry, stashEntry := entities.EntryTypeCredit, entities.EntryTypeDebit // Spending down, stash up
+	desc := "Round-up to stash"
+	idempotencyKey := fmt.Sprintf("roundup:%s:%s", tx.SourceType, sourceRef)
+	if reverse {
+		spendEntry, stashEntry = entities.EntryTypeDebit, entities.EntryTypeCredit
+		desc = "Round-up reversal"
+		idempotencyKey = fmt.Sprintf("roundup-reversal:%s:%s", tx.SourceType, sourceRef)
+		if refundID != nil {
+			idempotencyKey = fmt.Sprintf("roundup-reversal:%s:%s:%s", tx.SourceType, sourceRef, refundID)
+		}
+	}
+	referenceType := "roundup"
+
+	ledgerTx, err := s.ledgerService.CreateTransaction(ctx, &entities.CreateTransactionRequest{
+		UserID:          &tx.UserID,
+		TransactionType: entities.TransactionTypeInternalTransfer,
+		ReferenceID:     &tx.ID,
+		ReferenceType:   &referenceType,
+		IdempotencyKey:  idempotencyKey,
+		Description:     &desc,
+		Entries: []entities.CreateEntryRequest{
+			{
+				AccountID:   spendAccount.ID,
+				EntryType:   spendEntry,
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+			{
+				AccountID:   stashAccount.ID,
+				EntryType:   stashEntry,
+				Amount:      amount,
+				Currency:    "USD",
+				Description: &desc,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create ledger transaction: %w", err)
+	}
+	return &ledgerTx.ID, nil
+}
@@ -0,0 +1,97 @@
+package roundup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// maxBoostDays bounds how long a boost period may run
+const maxBoostDays = 90
+
+// PreviewRoundup evaluates the user's round-up rules against an amount without saving anything
+func (s *Service) PreviewRoundup(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (*entities.RoundupEvaluation, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluateRoundup(ctx, settings, amount)
+}
+
+// evaluateRoundup gathers the spend balance and cap usage and applies the user's round-up rules
+func (s *Service) evaluateRoundup(ctx context.Context, settings *entities.RoundupSettings, amount decimal.Decimal) (*entities.RoundupEvaluation, error) {
+	now := time.Now().UTC()
+	input := entities.RoundupRuleInput{Amount: amount, Now: now}
+
+	if s.ledgerService != nil && (settings.LowBalanceFloor != nil || settings.SkipBelowBalance != nil) {
+		balance, err := s.ledgerService.GetAccountBalance(ctx, settings.UserID, entities.AccountTypeSpendingBalance)
+		if err != nil {
+			return nil, fmt.Errorf("get spending balance: %w", err)
+		}
+		input.SpendBalance = &balance
+	}
+	if settings.WeeklyCap != nil {
+		total, err := s.repo.SumRoundupsSince(ctx, settings.UserID, entities.StartOfRoundupWeek(now))
+		if err != nil {
+			return nil, fmt.Errorf("sum weekly round-ups: %w", err)
+		}
+		input.WeekToDate = total
+	}
+	if settings.MonthlyCap != nil {
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		total, err := s.repo.SumRoundupsSince(ctx, settings.UserID, monthStart)
+		if err != nil {
+			return nil, fmt.Errorf("sum monthly round-ups: %w", err)
+		}
+		input.MonthToDate = total
+	}
+
+	return settings.EvaluateRoundup(input), nil
+}
+
+// applyRuleUpdates applies cap, boost and balance safeguard changes; zero values clear a rule
+func applyRuleUpdates(settings *entities.RoundupSettings, req *UpdateSettingsRequest) error {
+	if req.WeeklyCap != nil {
+		settings.WeeklyCap = positiveOrNil(*req.WeeklyCap)
+	}
+	if req.MonthlyCap != nil {
+		settings.MonthlyCap = positiveOrNil(*req.MonthlyCap)
+	}
+	if req.LowBalanceFloor != nil {
+		settings.LowBalanceFloor = positiveOrNil(*req.LowBalanceFloor)
+	}
+	if req.SkipBelowBalance != nil {
+		settings.SkipBelowBalance = positiveOrNil(*req.SkipBelowBalance)
+	}
+
+	if req.BoostMultiplier != nil || req.BoostDays != nil {
+		if req.BoostMultiplier == nil || req.BoostDays == nil {
+			return fmt.Errorf("boost requires both a multiplier and a duration")
+		}
+		if *req.BoostDays < 0 || *req.BoostDays > maxBoostDays {
+			return fmt.Errorf("boost duration must be between 0 and %d days", maxBoostDays)
+		}
+		if *req.BoostDays == 0 || req.BoostMultiplier.LessThanOrEqual(decimal.NewFromInt(1)) {
+			settings.BoostMultiplier = nil
+			settings.BoostEndsAt = nil
+			return nil
+		}
+		endsAt := time.Now().UTC().AddDate(0, 0, *req.BoostDays)
+		multiplier := *req.BoostMultiplier
+		settings.BoostMultiplier = &multiplier
+		settings.BoostEndsAt = &endsAt
+	}
+	return nil
+}
+
+func positiveOrNil(value decimal.Decimal) *decimal.Decimal {
+	if !value.IsPositive() {
+		return nil
+	}
+	return &value
+}
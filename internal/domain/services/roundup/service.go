@@ -18,8 +18,12 @@ type Repository interface {
 	CreateTransaction(ctx context.Context, tx *entities.RoundupTransaction) error
 	UpdateTransactionStatus(ctx context.Context, id uuid.UUID, status entities.RoundupStatus, orderID *uuid.UUID) error
 	GetPendingTransactions(ctx context.Context, userID uuid.UUID) ([]*entities.RoundupTransaction, error)
+	GetTransactionBySourceRef(ctx context.Context, userID uuid.UUID, sourceType entities.RoundupSourceType, sourceRef string) (*entities.RoundupTransaction, error)
+	FindRefundableRoundup(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.RoundupTransaction, error)
+	ReduceTransaction(ctx context.Context, id uuid.UUID, originalAmount, multipliedAmount decimal.Decimal) error
 	GetTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.RoundupTransaction, error)
 	CountTransactions(ctx context.Context, userID uuid.UUID) (int, error)
+	SumRoundupsSince(ctx context.Context, userID uuid.UUID, since time.Time) (decimal.Decimal, error)
 	GetAccumulator(ctx context.Context, userID uuid.UUID) (*entities.RoundupAccumulator, error)
 	UpsertAccumulator(ctx context.Context, acc *entities.RoundupAccumulator) error
 	GetUsersReadyForAutoInvest(ctx context.Context) ([]uuid.UUID, error)
@@ -35,6 +39,13 @@ type OrderPlacer interface {
 	PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error)
 }
 
+// CashFlowInvestor invests new cash into a user's most underweight target assets and returns the
+// amount actually invested, which can fall short of the cash offered. It returns no orders when
+// the user has no target allocation.
+type CashFlowInvestor interface {
+	InvestCashFlow(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, source entities.CashFlowSource) ([]*entities.InvestmentOrder, decimal.Decimal, error)
+}
+
 // ContributionRecorder interface for recording contributions
 type ContributionRecorder interface {
 	RecordContribution(ctx context.Context, userID uuid.UUID, contributionType entities.ContributionType, amount decimal.Decimal, source string) error
@@ -46,6 +57,8 @@ type Service struct {
 	allocationService    AllocationService
 	orderPlacer          OrderPlacer
 	contributionRecorder ContributionRecorder
+	ledgerService        LedgerService
+	cashFlowInvestor     CashFlowInvestor
 	logger               *zap.Logger
 }
 
@@ -66,6 +79,12 @@ func NewService(
 	}
 }
 
+// SetCashFlowInvestor sets the investor that directs round-ups into underweight assets of the
+// user's target allocation ahead of the auto-invest symbol
+func (s *Service) SetCashFlowInvestor(investor CashFlowInvestor) {
+	s.cashFlowInvestor = investor
+}
+
 // GetSettings retrieves round-up settings for a user
 func (s *Service) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.RoundupSettings, error) {
 	settings, err := s.repo.GetSettings(ctx, userID)
@@ -106,6 +125,9 @@ func (s *Service) UpdateSettings(ctx context.Context, userID uuid.UUID, req *Upd
 		settings.AutoInvestSymbol = req.AutoInvestSymbol
 		settings.AutoInvestBasketID = nil
 	}
+	if err := applyRuleUpdates(settings, req); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
 
 	if err := settings.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid settings: %w", err)
@@ -135,14 +157,18 @@ func (s *Service) ProcessTransaction(ctx context.Context, req *ProcessTransactio
 	}
 
 	// Calculate round-up
-	rounded, spareChange, multiplied := entities.CalculateRoundup(req.Amount, settings.Multiplier)
+	eval, err := s.evaluateRoundup(ctx, settings, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	spareChange, multiplied := eval.SpareChange, eval.MultipliedAmount
 
 	now := time.Now()
 	tx := &entities.RoundupTransaction{
 		ID:               uuid.New(),
 		UserID:           req.UserID,
 		OriginalAmount:   req.Amount,
-		RoundedAmount:    rounded,
+		RoundedAmount:    eval.RoundedAmount,
 		SpareChange:      spareChange,
 		MultipliedAmount: multiplied,
 		SourceType:       req.SourceType,
@@ -151,10 +177,17 @@ func (s *Service) ProcessTransaction(ctx context.Context, req *ProcessTransactio
 		Status:           entities.RoundupStatusPending,
 		CreatedAt:        now,
 	}
+	if eval.Skipped {
+		tx.Status = entities.RoundupStatusSkipped
+		tx.SkipReason = &eval.SkipReason
+	}
 
 	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
 		return nil, fmt.Errorf("create transaction: %w", err)
 	}
+	if eval.Skipped {
+		return tx, nil
+	}
 
 	// Update accumulator
 	acc, err := s.repo.GetAccumulator(ctx, req.UserID)
@@ -251,42 +284,71 @@ func (s *Service) triggerAutoInvest(ctx context.Context, userID uuid.UUID) {
 
 	investAmount := acc.PendingAmount
 
-	// Place order
+	// Underweight assets of the user's target allocation come first
 	var order *entities.InvestmentOrder
-	if settings.AutoInvestSymbol != nil && s.orderPlacer != nil {
+	invested := decimal.Zero
+	target := "target allocation"
+	if s.cashFlowInvestor != nil {
+		orders, amount, err := s.cashFlowInvestor.InvestCashFlow(ctx, userID, investAmount, entities.CashFlowSourceRoundup)
+		if err != nil {
+			s.logger.Error("Auto-invest into target allocation failed", zap.Error(err), zap.String("user_id", userID.String()))
+			// Orders already placed still count; otherwise retry on the next trigger
+			if len(orders) == 0 {
+				return
+			}
+		}
+		if len(orders) > 0 {
+			order = orders[0]
+			invested = amount
+		}
+	}
+
+	// Place order
+	if order == nil && settings.AutoInvestSymbol != nil && s.orderPlacer != nil {
+		target = *settings.AutoInvestSymbol
 		order, err = s.orderPlacer.PlaceMarketOrder(ctx, userID, *settings.AutoInvestSymbol, investAmount)
 		if err != nil {
 			s.logger.Error("Auto-invest order failed", zap.Error(err), zap.String("user_id", userID.String()))
 			return
 		}
+		invested = investAmount
+	}
+	if !invested.IsPositive() {
+		return
 	}
 
-	// Update transactions to invested
+	// Round-ups are invested oldest first; any the invested amount does not fully cover stay pending
 	pendingTxs, _ := s.repo.GetPendingTransactions(ctx, userID)
 	var orderID *uuid.UUID
 	if order != nil {
 		orderID = &order.ID
 	}
+	covered := invested
 	for _, tx := range pendingTxs {
+		if tx.MultipliedAmount.GreaterThan(covered) {
+			break
+		}
 		s.repo.UpdateTransactionStatus(ctx, tx.ID, entities.RoundupStatusInvested, orderID)
+		covered = covered.Sub(tx.MultipliedAmount)
 	}
 
-	// Update accumulator
+	// Update accumulator, keeping what was not invested pending for the next trigger
 	now := time.Now()
-	acc.TotalInvested = acc.TotalInvested.Add(investAmount)
+	acc.TotalInvested = acc.TotalInvested.Add(invested)
 	acc.LastInvestmentAt = &now
-	acc.PendingAmount = decimal.Zero
+	acc.PendingAmount = decimal.Max(acc.PendingAmount.Sub(invested), decimal.Zero)
 	s.repo.UpsertAccumulator(ctx, acc)
 
 	// Record contribution
 	if s.contributionRecorder != nil {
-		s.contributionRecorder.RecordContribution(ctx, userID, entities.ContributionTypeRoundup, investAmount, "auto_invest")
+		s.contributionRecorder.RecordContribution(ctx, userID, entities.ContributionTypeRoundup, invested, "auto_invest")
 	}
 
 	s.logger.Info("Auto-invest triggered",
 		zap.String("user_id", userID.String()),
-		zap.String("amount", investAmount.String()),
-		zap.String("symbol", *settings.AutoInvestSymbol))
+		zap.String("amount", invested.String()),
+		zap.String("pending", acc.PendingAmount.String()),
+		zap.String("target", target))
 }
 
 // ProcessAutoInvestBatch processes auto-invest for all eligible users
@@ -347,6 +409,12 @@ type UpdateSettingsRequest struct {
 	AutoInvestEnabled  *bool            `json:"auto_invest_enabled,omitempty"`
 	AutoInvestBasketID *uuid.UUID       `json:"auto_invest_basket_id,omitempty"`
 	AutoInvestSymbol   *string          `json:"auto_invest_symbol,omitempty"`
+	WeeklyCap          *decimal.Decimal `json:"weekly_cap,omitempty"`
+	MonthlyCap         *decimal.Decimal `json:"monthly_cap,omitempty"`
+	BoostMultiplier    *decimal.Decimal `json:"boost_multiplier,omitempty"`
+	BoostDays          *int             `json:"boost_days,omitempty"`
+	LowBalanceFloor    *decimal.Decimal `json:"low_balance_floor,omitempty"`
+	SkipBelowBalance   *decimal.Decimal `json:"skip_below_balance,omitempty"`
 }
 
 // ProcessTransactionRequest represents a request to process a transaction
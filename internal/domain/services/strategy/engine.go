@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,15 +19,24 @@ type Allocation struct {
 
 // StrategyResult contains the computed allocation for a user
 type StrategyResult struct {
-	StrategyName string       // Name of the selected strategy
-	Allocations  []Allocation // Asset allocations with weights
+	StrategyName string            // Name of the selected strategy
+	Allocations  []Allocation      // Asset allocations with weights
+	RiskBand     entities.RiskBand // Risk of the strategy, checked against the user's risk band
+
+	PortfolioID        *uuid.UUID   // Model portfolio selected; nil for built-in strategies
+	PortfolioVersionID *uuid.UUID   // Version of the model portfolio in effect
+	Version            int          // Version number; 0 for built-in strategies
+	Signals            *UserSignals // Signals the strategy was selected from
 }
 
 // UserSignals contains signals used for strategy personalization
 type UserSignals struct {
-	UserID      uuid.UUID
-	Age         *int       // Calculated from DateOfBirth
-	DateOfBirth *time.Time // Raw date of birth
+	UserID           uuid.UUID
+	Age              *int             // Calculated from DateOfBirth
+	DateOfBirth      *time.Time       // Raw date of birth
+	RiskBand         string           // Assessed risk band, empty if not assessed
+	TimeHorizonYears *int             // Investment horizon from the risk assessment
+	Balance          *decimal.Decimal // Total USD value of the user's balances
 }
 
 // UserProfileProvider retrieves user profile data for signal extraction
@@ -34,10 +44,42 @@ type UserProfileProvider interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.UserProfile, error)
 }
 
+// RiskProfile is the suitability data a user's risk assessment provides
+type RiskProfile struct {
+	RiskBand         string
+	TimeHorizonYears *int
+}
+
+// RiskProfileProvider retrieves a user's current risk assessment, or nil if they have none
+type RiskProfileProvider interface {
+	GetRiskProfile(ctx context.Context, userID uuid.UUID) (*RiskProfile, error)
+}
+
+// BalanceProvider retrieves a user's balances for balance-based strategy selection
+type BalanceProvider interface {
+	GetUserBalances(ctx context.Context, userID uuid.UUID) (*entities.UserBalances, error)
+}
+
+// Repository persists model portfolios and the strategies assigned to users
+type Repository interface {
+	CreateModelPortfolio(ctx context.Context, portfolio *entities.ModelPortfolio, version *entities.ModelPortfolioVersion) error
+	GetModelPortfolios(ctx context.Context) ([]*entities.ModelPortfolio, error)
+	GetModelPortfolioByID(ctx context.Context, id uuid.UUID) (*entities.ModelPortfolio, error)
+	UpdateModelPortfolioStatus(ctx context.Context, id uuid.UUID, status entities.ModelPortfolioStatus) error
+	CreateModelPortfolioVersion(ctx context.Context, version *entities.ModelPortfolioVersion) error
+	GetModelPortfolioVersions(ctx context.Context, portfolioID uuid.UUID) ([]*entities.ModelPortfolioVersion, error)
+	GetEffectiveModelPortfolioVersions(ctx context.Context, at time.Time) ([]*entities.ModelPortfolioVersion, error)
+	CreateStrategyAssignment(ctx context.Context, assignment *entities.StrategyAssignment) error
+	GetStrategyAssignmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.StrategyAssignment, error)
+}
+
 // Engine determines investment strategy based on user signals
 type Engine struct {
-	userProvider UserProfileProvider
-	logger       *logger.Logger
+	userProvider    UserProfileProvider
+	riskProvider    RiskProfileProvider
+	balanceProvider BalanceProvider
+	repo            Repository
+	logger          *logger.Logger
 }
 
 // NewEngine creates a new strategy engine
@@ -48,6 +90,21 @@ func NewEngine(userProvider UserProfileProvider, logger *logger.Logger) *Engine
 	}
 }
 
+// SetRepository sets the model portfolio repository. Without one, the built-in portfolios are used.
+func (e *Engine) SetRepository(repo Repository) {
+	e.repo = repo
+}
+
+// SetRiskProfileProvider sets the risk assessment source (optional)
+func (e *Engine) SetRiskProfileProvider(provider RiskProfileProvider) {
+	e.riskProvider = provider
+}
+
+// SetBalanceProvider sets the balance source (optional)
+func (e *Engine) SetBalanceProvider(provider BalanceProvider) {
+	e.balanceProvider = provider
+}
+
 // GetStrategy determines the optimal strategy for a user based on their signals
 func (e *Engine) GetStrategy(ctx context.Context, userID uuid.UUID) (*StrategyResult, error) {
 	signals, err := e.collectSignals(ctx, userID)
@@ -58,55 +115,211 @@ func (e *Engine) GetStrategy(ctx context.Context, userID uuid.UUID) (*StrategyRe
 		return e.getFallbackStrategy(), nil
 	}
 
-	return e.selectStrategy(signals), nil
+	return e.selectStrategy(ctx, signals), nil
 }
 
-// collectSignals gathers user signals for strategy personalization
+// RecordAssignment stores the strategy used for an auto-investment so the allocation can be
+// explained later. It is a no-op without a repository.
+func (e *Engine) RecordAssignment(ctx context.Context, userID uuid.UUID, correlationID string, amount decimal.Decimal, result *StrategyResult) error {
+	if e.repo == nil {
+		return nil
+	}
+
+	assignment := &entities.StrategyAssignment{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		PortfolioID:        result.PortfolioID,
+		PortfolioVersionID: result.PortfolioVersionID,
+		PortfolioName:      result.StrategyName,
+		Version:            result.Version,
+		CorrelationID:      correlationID,
+		Amount:             amount,
+		Allocations:        make([]entities.ModelPortfolioAllocation, len(result.Allocations)),
+		CreatedAt:          time.Now().UTC(),
+	}
+	for i, alloc := range result.Allocations {
+		assignment.Allocations[i] = entities.ModelPortfolioAllocation{Symbol: alloc.Symbol, Weight: alloc.Weight}
+	}
+	if s := result.Signals; s != nil {
+		assignment.Signals = entities.StrategySignals{
+			Age:              s.Age,
+			RiskBand:         s.RiskBand,
+			Balance:          s.Balance,
+			TimeHorizonYears: s.TimeHorizonYears,
+		}
+	}
+
+	return e.repo.CreateStrategyAssignment(ctx, assignment)
+}
+
+// collectSignals gathers user signals for strategy personalization. Only the profile is required;
+// risk and balance signals are left unset when their sources are unavailable.
 func (e *Engine) collectSignals(ctx context.Context, userID uuid.UUID) (*UserSignals, error) {
 	signals := &UserSignals{UserID: userID}
 
-	if e.userProvider == nil {
-		return signals, nil
+	if e.userProvider != nil {
+		profile, err := e.userProvider.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if profile != nil && profile.DateOfBirth != nil {
+			signals.DateOfBirth = profile.DateOfBirth
+			age := calculateAge(*profile.DateOfBirth)
+			signals.Age = &age
+		}
 	}
 
-	profile, err := e.userProvider.GetByID(ctx, userID)
-	if err != nil {
-		return nil, err
+	if e.riskProvider != nil {
+		risk, err := e.riskProvider.GetRiskProfile(ctx, userID)
+		if err != nil {
+			e.logger.Warn("Failed to get risk profile", "user_id", userID, "error", err)
+		} else if risk != nil {
+			signals.RiskBand = risk.RiskBand
+			signals.TimeHorizonYears = risk.TimeHorizonYears
+		}
 	}
 
-	if profile != nil && profile.DateOfBirth != nil {
-		signals.DateOfBirth = profile.DateOfBirth
-		age := calculateAge(*profile.DateOfBirth)
-		signals.Age = &age
+	if e.balanceProvider != nil {
+		balances, err := e.balanceProvider.GetUserBalances(ctx, userID)
+		if err != nil {
+			e.logger.Warn("Failed to get user balances", "user_id", userID, "error", err)
+		} else if balances != nil {
+			total := balances.TotalValue()
+			signals.Balance = &total
+		}
 	}
 
 	return signals, nil
 }
 
-// selectStrategy chooses a strategy based on user signals
-func (e *Engine) selectStrategy(signals *UserSignals) *StrategyResult {
-	// Age-based strategy selection
-	if signals.Age != nil {
-		age := *signals.Age
+// selectStrategy chooses the highest-priority model portfolio whose criteria match the user's
+// signals, preferring narrower criteria on ties. Portfolios riskier than the user's assessed risk
+// band are skipped; when none match, the highest-ranked portfolio within the band is used.
+func (e *Engine) selectStrategy(ctx context.Context, signals *UserSignals) *StrategyResult {
+	candidates := e.modelPortfolios(ctx)
 
-		// Young investors (18-25): More aggressive growth
-		if age >= 18 && age <= 25 {
-			return e.getAggressiveGrowthStrategy()
+	var inBand, matches []*entities.ModelPortfolioVersion
+	for _, v := range candidates {
+		if v.RiskBand.Exceeds(entities.RiskBand(signals.RiskBand)) {
+			continue
+		}
+		inBand = append(inBand, v)
+		if matchesCriteria(v.Criteria, signals) {
+			matches = append(matches, v)
 		}
+	}
+	if len(matches) == 0 {
+		matches = inBand
+	}
+	if len(matches) == 0 {
+		// Nothing fits the user's band, so the default is returned for the suitability check
+		// to require an acknowledgment rather than investing outside the band silently
+		e.logger.Warn("No model portfolio within risk band, using global fallback",
+			"user_id", signals.UserID,
+			"risk_band", signals.RiskBand)
+		result := e.getFallbackStrategy()
+		result.Signals = signals
+		return result
+	}
+
+	rankPortfolios(matches)
+
+	result := strategyFromVersion(matches[0])
+	result.Signals = signals
+	return result
+}
 
-		// Mid-age investors (26-40): Balanced growth
-		if age >= 26 && age <= 40 {
-			return e.getBalancedGrowthStrategy()
+// rankPortfolios orders portfolio versions by priority, then narrower criteria, then name
+func rankPortfolios(versions []*entities.ModelPortfolioVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, b := versions[i], versions[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
 		}
+		if a.Criteria.Specificity() != b.Criteria.Specificity() {
+			return a.Criteria.Specificity() > b.Criteria.Specificity()
+		}
+		return a.PortfolioName < b.PortfolioName
+	})
+}
+
+// modelPortfolios returns the model portfolio versions in effect now, falling back to the
+// built-in portfolios when none are stored or they cannot be loaded
+func (e *Engine) modelPortfolios(ctx context.Context) []*entities.ModelPortfolioVersion {
+	if e.repo == nil {
+		return builtinModelPortfolios()
+	}
+	versions, err := e.repo.GetEffectiveModelPortfolioVersions(ctx, time.Now().UTC())
+	if err != nil {
+		e.logger.Warn("Failed to load model portfolios, using built-in portfolios", "error", err)
+		return builtinModelPortfolios()
+	}
+	if len(versions) == 0 {
+		return builtinModelPortfolios()
+	}
+	return versions
+}
 
-		// Mature investors (41+): Conservative
-		if age > 40 {
-			return e.getConservativeStrategy()
+// matchesCriteria reports whether a user's signals fall inside every bound the criteria set
+func matchesCriteria(c entities.ModelPortfolioCriteria, s *UserSignals) bool {
+	if c.MinAge != nil || c.MaxAge != nil {
+		if s.Age == nil || !intWithin(*s.Age, c.MinAge, c.MaxAge) {
+			return false
+		}
+	}
+	if c.MinHorizonYears != nil || c.MaxHorizonYears != nil {
+		if s.TimeHorizonYears == nil || !intWithin(*s.TimeHorizonYears, c.MinHorizonYears, c.MaxHorizonYears) {
+			return false
 		}
 	}
+	if c.MinBalance != nil || c.MaxBalance != nil {
+		if s.Balance == nil {
+			return false
+		}
+		if c.MinBalance != nil && s.Balance.LessThan(*c.MinBalance) {
+			return false
+		}
+		if c.MaxBalance != nil && s.Balance.GreaterThan(*c.MaxBalance) {
+			return false
+		}
+	}
+	if len(c.RiskBands) > 0 {
+		found := false
+		for _, band := range c.RiskBands {
+			if band == s.RiskBand {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
 
-	// Default to fallback if no age signal
-	return e.getFallbackStrategy()
+func intWithin(v int, min, max *int) bool {
+	return (min == nil || v >= *min) && (max == nil || v <= *max)
+}
+
+// strategyFromVersion converts a model portfolio version into a strategy result
+func strategyFromVersion(v *entities.ModelPortfolioVersion) *StrategyResult {
+	result := &StrategyResult{
+		StrategyName: v.PortfolioName,
+		Allocations:  make([]Allocation, len(v.Allocations)),
+		RiskBand:     v.RiskBand,
+		Version:      v.Version,
+	}
+	for i, alloc := range v.Allocations {
+		result.Allocations[i] = Allocation{Symbol: alloc.Symbol, Weight: alloc.Weight}
+	}
+	if v.ID != uuid.Nil {
+		portfolioID, versionID := v.PortfolioID, v.ID
+		result.PortfolioID = &portfolioID
+		result.PortfolioVersionID = &versionID
+	}
+	return result
 }
 
 // getFallbackStrategy returns the global default strategy
@@ -115,48 +328,58 @@ func (e *Engine) getFallbackStrategy() *StrategyResult {
 	return &StrategyResult{
 		StrategyName: "Global Fallback",
 		Allocations: []Allocation{
-			{Symbol: "SPY", Weight: decimal.NewFromInt(60)},  // S&P 500 ETF
-			{Symbol: "QQQ", Weight: decimal.NewFromInt(25)},  // Tech-heavy NASDAQ ETF
-			{Symbol: "BND", Weight: decimal.NewFromInt(15)},  // Bond ETF for stability
+			{Symbol: "SPY", Weight: decimal.NewFromInt(60)}, // S&P 500 ETF
+			{Symbol: "QQQ", Weight: decimal.NewFromInt(25)}, // Tech-heavy NASDAQ ETF
+			{Symbol: "BND", Weight: decimal.NewFromInt(15)}, // Bond ETF for stability
 		},
+		RiskBand: entities.RiskBandBalanced,
 	}
 }
 
-// getAggressiveGrowthStrategy for young investors (18-25)
-// Higher tech and growth exposure
-func (e *Engine) getAggressiveGrowthStrategy() *StrategyResult {
-	return &StrategyResult{
-		StrategyName: "Aggressive Growth",
-		Allocations: []Allocation{
-			{Symbol: "QQQ", Weight: decimal.NewFromInt(40)},  // Tech-heavy NASDAQ
-			{Symbol: "SPY", Weight: decimal.NewFromInt(35)},  // S&P 500
-			{Symbol: "VUG", Weight: decimal.NewFromInt(25)},  // Growth ETF
-		},
+// builtinModelPortfolios returns the age-banded portfolios used when none are stored. They match
+// the versions seeded by the model portfolio migration.
+func builtinModelPortfolios() []*entities.ModelPortfolioVersion {
+	age := func(v int) *int { return &v }
+	weight := func(symbol string, pct int64) entities.ModelPortfolioAllocation {
+		return entities.ModelPortfolioAllocation{Symbol: symbol, Weight: decimal.NewFromInt(pct)}
 	}
-}
 
-// getBalancedGrowthStrategy for mid-age investors (26-40)
-// Balanced between growth and stability
-func (e *Engine) getBalancedGrowthStrategy() *StrategyResult {
-	return &StrategyResult{
-		StrategyName: "Balanced Growth",
-		Allocations: []Allocation{
-			{Symbol: "SPY", Weight: decimal.NewFromInt(50)},  // S&P 500
-			{Symbol: "QQQ", Weight: decimal.NewFromInt(30)},  // Tech exposure
-			{Symbol: "BND", Weight: decimal.NewFromInt(20)},  // Bonds
+	return []*entities.ModelPortfolioVersion{
+		{
+			PortfolioName: "Global Fallback",
+			RiskBand:      entities.RiskBandBalanced,
+			Allocations:   []entities.ModelPortfolioAllocation{weight("SPY", 60), weight("QQQ", 25), weight("BND", 15)},
 		},
-	}
-}
-
-// getConservativeStrategy for mature investors (41+)
-// Focus on stability and income
-func (e *Engine) getConservativeStrategy() *StrategyResult {
-	return &StrategyResult{
-		StrategyName: "Conservative",
-		Allocations: []Allocation{
-			{Symbol: "SPY", Weight: decimal.NewFromInt(40)},  // S&P 500
-			{Symbol: "BND", Weight: decimal.NewFromInt(35)},  // Bonds
-			{Symbol: "VYM", Weight: decimal.NewFromInt(25)},  // Dividend ETF
+		{
+			// In-band default for conservative and moderate risk bands
+			PortfolioName: "Capital Preservation",
+			RiskBand:      entities.RiskBandConservative,
+			Criteria:      entities.ModelPortfolioCriteria{RiskBands: []string{"conservative", "moderate"}},
+			Allocations:   []entities.ModelPortfolioAllocation{weight("BND", 60), weight("SPY", 25), weight("VYM", 15)},
+		},
+		{
+			// Young investors (18-25): higher tech and growth exposure
+			PortfolioName: "Aggressive Growth",
+			Priority:      10,
+			RiskBand:      entities.RiskBandAggressive,
+			Criteria:      entities.ModelPortfolioCriteria{MinAge: age(18), MaxAge: age(25)},
+			Allocations:   []entities.ModelPortfolioAllocation{weight("QQQ", 40), weight("SPY", 35), weight("VUG", 25)},
+		},
+		{
+			// Mid-age investors (26-40): balanced between growth and stability
+			PortfolioName: "Balanced Growth",
+			Priority:      10,
+			RiskBand:      entities.RiskBandBalanced,
+			Criteria:      entities.ModelPortfolioCriteria{MinAge: age(26), MaxAge: age(40)},
+			Allocations:   []entities.ModelPortfolioAllocation{weight("SPY", 50), weight("QQQ", 30), weight("BND", 20)},
+		},
+		{
+			// Mature investors (41+): focus on stability and income
+			PortfolioName: "Conservative",
+			Priority:      10,
+			RiskBand:      entities.RiskBandConservative,
+			Criteria:      entities.ModelPortfolioCriteria{MinAge: age(41)},
+			Allocations:   []entities.ModelPortfolioAllocation{weight("SPY", 40), weight("BND", 35), weight("VYM", 25)},
 		},
 	}
 }
@@ -0,0 +1,227 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Model Portfolio Administration ===
+
+// CreateModelPortfolio creates a model portfolio and its first version
+func (e *Engine) CreateModelPortfolio(ctx context.Context, adminID uuid.UUID, req *entities.CreateModelPortfolioRequest) (*entities.ModelPortfolio, error) {
+	if e.repo == nil {
+		return nil, fmt.Errorf("model portfolios not configured")
+	}
+	if err := validateModelPortfolioVersion(&req.Version); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	portfolio := &entities.ModelPortfolio{
+		ID:          uuid.New(),
+		Slug:        strings.ToLower(strings.TrimSpace(req.Slug)),
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      entities.ModelPortfolioStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	version := newModelPortfolioVersion(portfolio, 1, adminID, &req.Version, now)
+
+	if err := e.repo.CreateModelPortfolio(ctx, portfolio, version); err != nil {
+		return nil, fmt.Errorf("failed to create model portfolio: %w", err)
+	}
+	portfolio.CurrentVersion = version
+
+	e.logger.Info("Model portfolio created",
+		"portfolio_id", portfolio.ID,
+		"slug", portfolio.Slug,
+		"admin_id", adminID)
+
+	return portfolio, nil
+}
+
+// PublishModelPortfolioVersion adds a version to a model portfolio. It applies to auto-investments
+// from its effective date; earlier assignments keep pointing at the version they used.
+func (e *Engine) PublishModelPortfolioVersion(ctx context.Context, adminID, portfolioID uuid.UUID, req *entities.CreateModelPortfolioVersionRequest) (*entities.ModelPortfolioVersion, error) {
+	portfolio, err := e.getModelPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateModelPortfolioVersion(req); err != nil {
+		return nil, err
+	}
+
+	versions, err := e.repo.GetModelPortfolioVersions(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model portfolio versions: %w", err)
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[0].Version + 1
+	}
+
+	version := newModelPortfolioVersion(portfolio, next, adminID, req, time.Now().UTC())
+	if err := e.repo.CreateModelPortfolioVersion(ctx, version); err != nil {
+		return nil, fmt.Errorf("failed to create model portfolio version: %w", err)
+	}
+
+	e.logger.Info("Model portfolio version published",
+		"portfolio_id", portfolioID,
+		"version", next,
+		"effective_from", version.EffectiveFrom,
+		"admin_id", adminID)
+
+	return version, nil
+}
+
+// ListModelPortfolios returns every model portfolio with the version currently in effect
+func (e *Engine) ListModelPortfolios(ctx context.Context) ([]*entities.ModelPortfolio, error) {
+	if e.repo == nil {
+		return nil, fmt.Errorf("model portfolios not configured")
+	}
+	portfolios, err := e.repo.GetModelPortfolios(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model portfolios: %w", err)
+	}
+	effective, err := e.repo.GetEffectiveModelPortfolioVersions(ctx, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get effective versions: %w", err)
+	}
+
+	byPortfolio := make(map[uuid.UUID]*entities.ModelPortfolioVersion, len(effective))
+	for _, v := range effective {
+		byPortfolio[v.PortfolioID] = v
+	}
+	for _, p := range portfolios {
+		p.CurrentVersion = byPortfolio[p.ID]
+	}
+	return portfolios, nil
+}
+
+// GetModelPortfolioVersions returns a model portfolio's versions, newest first, including ones
+// scheduled for the future
+func (e *Engine) GetModelPortfolioVersions(ctx context.Context, portfolioID uuid.UUID) ([]*entities.ModelPortfolioVersion, error) {
+	if _, err := e.getModelPortfolio(ctx, portfolioID); err != nil {
+		return nil, err
+	}
+	versions, err := e.repo.GetModelPortfolioVersions(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model portfolio versions: %w", err)
+	}
+	return versions, nil
+}
+
+// UpdateModelPortfolioStatus retires a model portfolio so it is no longer assigned, or reactivates it
+func (e *Engine) UpdateModelPortfolioStatus(ctx context.Context, portfolioID uuid.UUID, status entities.ModelPortfolioStatus) (*entities.ModelPortfolio, error) {
+	portfolio, err := e.getModelPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.repo.UpdateModelPortfolioStatus(ctx, portfolioID, status); err != nil {
+		return nil, fmt.Errorf("failed to update model portfolio: %w", err)
+	}
+	portfolio.Status = status
+	return portfolio, nil
+}
+
+// GetUserAssignments returns the strategies a user's auto-investments used, newest first
+func (e *Engine) GetUserAssignments(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.StrategyAssignment, error) {
+	if e.repo == nil {
+		return nil, fmt.Errorf("model portfolios not configured")
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	assignments, err := e.repo.GetStrategyAssignmentsByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+func (e *Engine) getModelPortfolio(ctx context.Context, portfolioID uuid.UUID) (*entities.ModelPortfolio, error) {
+	if e.repo == nil {
+		return nil, fmt.Errorf("model portfolios not configured")
+	}
+	portfolio, err := e.repo.GetModelPortfolioByID(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model portfolio: %w", err)
+	}
+	if portfolio == nil {
+		return nil, fmt.Errorf("model portfolio not found")
+	}
+	return portfolio, nil
+}
+
+func newModelPortfolioVersion(portfolio *entities.ModelPortfolio, number int, adminID uuid.UUID, req *entities.CreateModelPortfolioVersionRequest, now time.Time) *entities.ModelPortfolioVersion {
+	effectiveFrom := now
+	if req.EffectiveFrom != nil {
+		effectiveFrom = req.EffectiveFrom.UTC()
+	}
+	allocations := make([]entities.ModelPortfolioAllocation, len(req.Allocations))
+	for i, alloc := range req.Allocations {
+		allocations[i] = entities.ModelPortfolioAllocation{
+			Symbol: strings.ToUpper(strings.TrimSpace(alloc.Symbol)),
+			Weight: alloc.Weight,
+		}
+	}
+
+	return &entities.ModelPortfolioVersion{
+		ID:            uuid.New(),
+		PortfolioID:   portfolio.ID,
+		PortfolioName: portfolio.Name,
+		Version:       number,
+		EffectiveFrom: effectiveFrom,
+		Priority:      req.Priority,
+		RiskBand:      req.RiskBand,
+		Criteria:      req.Criteria,
+		Allocations:   allocations,
+		Notes:         req.Notes,
+		CreatedBy:     &adminID,
+		CreatedAt:     now,
+	}
+}
+
+// validateModelPortfolioVersion checks that weights are positive, unique per symbol and sum to
+// 100%, and that criteria ranges are not inverted
+func validateModelPortfolioVersion(req *entities.CreateModelPortfolioVersionRequest) error {
+	total := decimal.Zero
+	seen := make(map[string]bool, len(req.Allocations))
+	for _, alloc := range req.Allocations {
+		symbol := strings.ToUpper(strings.TrimSpace(alloc.Symbol))
+		if symbol == "" {
+			return fmt.Errorf("allocation symbol is required")
+		}
+		if !alloc.Weight.IsPositive() {
+			return fmt.Errorf("allocation weight must be positive")
+		}
+		if seen[symbol] {
+			return fmt.Errorf("allocations must not repeat a symbol, got %s twice", symbol)
+		}
+		seen[symbol] = true
+		total = total.Add(alloc.Weight)
+	}
+	if !total.Equal(decimal.NewFromInt(100)) {
+		return fmt.Errorf("allocations must sum to 100%%, got %s%%", total.String())
+	}
+
+	c := req.Criteria
+	if c.MinAge != nil && c.MaxAge != nil && *c.MinAge > *c.MaxAge {
+		return fmt.Errorf("min_age must not exceed max_age")
+	}
+	if c.MinHorizonYears != nil && c.MaxHorizonYears != nil && *c.MinHorizonYears > *c.MaxHorizonYears {
+		return fmt.Errorf("min_horizon_years must not exceed max_horizon_years")
+	}
+	if c.MinBalance != nil && c.MaxBalance != nil && c.MinBalance.GreaterThan(*c.MaxBalance) {
+		return fmt.Errorf("min_balance must not exceed max_balance")
+	}
+	return nil
+}
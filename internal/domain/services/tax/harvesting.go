@@ -0,0 +1,385 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// HarvestingConfig sets when losses are harvested and which substitute each symbol is swapped into
+type HarvestingConfig struct {
+	MinLoss        decimal.Decimal   // Minimum unrealized loss across a symbol's loss lots, in USD
+	MinLossPercent decimal.Decimal   // Minimum loss as a fraction of those lots' cost basis
+	Substitutes    map[string]string // Symbol -> correlated but not substantially identical substitute
+}
+
+// DefaultHarvestingConfig pairs the ETFs auto-invest and the rebalancer hold with substitutes
+// tracking a different index, so swapping does not buy a substantially identical security
+func DefaultHarvestingConfig() HarvestingConfig {
+	return HarvestingConfig{
+		MinLoss:        decimal.NewFromInt(100),
+		MinLossPercent: decimal.NewFromFloat(0.05),
+		Substitutes: map[string]string{
+			"SPY":  "SCHX",
+			"SCHX": "SPY",
+			"VOO":  "IWB",
+			"IWB":  "VOO",
+			"QQQ":  "VGT",
+			"VGT":  "QQQ",
+			"BND":  "IUSB",
+			"IUSB": "BND",
+			"AGG":  "FBND",
+			"FBND": "AGG",
+			"VUG":  "SCHG",
+			"SCHG": "VUG",
+			"VYM":  "SCHD",
+			"SCHD": "VYM",
+			"VTI":  "ITOT",
+			"ITOT": "VTI",
+		},
+	}
+}
+
+// HarvestRepository persists tax-loss harvests and finds users who opted in
+type HarvestRepository interface {
+	GetHarvestingUserIDs(ctx context.Context, limit int) ([]uuid.UUID, error)
+	CreateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error
+	UpdateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error
+	GetActiveHarvests(ctx context.Context, userID uuid.UUID, at time.Time) ([]*entities.TaxLossHarvest, error)
+	GetHarvests(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.TaxLossHarvest, error)
+}
+
+// LotProvider reads a user's lots and recent loss sales and selects the lots a sale relieves
+type LotProvider interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*entities.TaxLotSettings, error)
+	GetOpenLots(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLot, error)
+	GetRecentLossSales(ctx context.Context, userID uuid.UUID, symbol string, since time.Time) ([]*entities.RealizedGain, error)
+	SelectLots(ctx context.Context, userID uuid.UUID, req *entities.SelectTaxLotsRequest) ([]*entities.TaxLotSelection, error)
+}
+
+// QuoteProvider returns latest market quotes
+type QuoteProvider interface {
+	GetQuotes(ctx context.Context, symbols []string) (map[string]*entities.MarketQuote, error)
+}
+
+// HarvestOrderPlacer sells lots by quantity and buys substitutes by amount. Sells are placed
+// under the given order ID so the lots they relieve can be selected before the order exists.
+type HarvestOrderPlacer interface {
+	PlaceSellOrder(ctx context.Context, orderID, userID uuid.UUID, symbol string, quantity decimal.Decimal) (*entities.InvestmentOrder, error)
+	PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error)
+}
+
+// HarvestingService sells lots with unrealized losses and swaps into a substitute, keeping the
+// harvested symbol out of the user's buys until the wash sale window closes
+type HarvestingService struct {
+	repo        HarvestRepository
+	lots        LotProvider
+	quotes      QuoteProvider
+	orderPlacer HarvestOrderPlacer
+	config      HarvestingConfig
+	logger      *zap.Logger
+}
+
+// NewHarvestingService creates a new tax-loss harvesting service
+func NewHarvestingService(
+	repo HarvestRepository,
+	lots LotProvider,
+	quotes QuoteProvider,
+	orderPlacer HarvestOrderPlacer,
+	config HarvestingConfig,
+	logger *zap.Logger,
+) *HarvestingService {
+	substitutes := make(map[string]string, len(config.Substitutes))
+	for symbol, substitute := range config.Substitutes {
+		substitutes[strings.ToUpper(symbol)] = strings.ToUpper(substitute)
+	}
+	config.Substitutes = substitutes
+
+	return &HarvestingService{
+		repo:        repo,
+		lots:        lots,
+		quotes:      quotes,
+		orderPlacer: orderPlacer,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// harvestCandidate is a symbol's loss lots that clear the thresholds
+type harvestCandidate struct {
+	symbol      string
+	substitute  string
+	lots        []*entities.TaxLot
+	quantity    decimal.Decimal
+	costBasis   decimal.Decimal
+	marketValue decimal.Decimal
+}
+
+// HarvestAll harvests losses for up to limit opted-in users and returns the harvests placed
+func (s *HarvestingService) HarvestAll(ctx context.Context, limit int) (int, error) {
+	userIDs, err := s.repo.GetHarvestingUserIDs(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get harvesting users: %w", err)
+	}
+
+	harvested := 0
+	for _, userID := range userIDs {
+		harvests, err := s.HarvestUser(ctx, userID)
+		if err != nil {
+			s.logger.Error("Failed to harvest tax losses",
+				zap.String("user_id", userID.String()),
+				zap.Error(err))
+			continue
+		}
+		harvested += len(harvests)
+	}
+	return harvested, nil
+}
+
+// HarvestUser sells each substitutable symbol whose loss lots clear the thresholds and buys the
+// substitute. Symbols are skipped when the sale or the substitute buy would be a wash sale.
+func (s *HarvestingService) HarvestUser(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLossHarvest, error) {
+	settings, err := s.lots.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.HarvestingEnabled {
+		return nil, nil
+	}
+
+	now := time.Now()
+	active, err := s.repo.GetActiveHarvests(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active harvests: %w", err)
+	}
+	// Finish swaps whose substitute buy failed on an earlier run
+	for _, harvest := range active {
+		if harvest.Status == entities.TaxLossHarvestStatusPending && harvest.BuyOrderID == nil {
+			s.buySubstitute(ctx, harvest)
+		}
+	}
+
+	lots, err := s.lots.GetOpenLots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	bySymbol := make(map[string][]*entities.TaxLot)
+	for _, lot := range lots {
+		if _, ok := s.config.Substitutes[lot.Symbol]; ok {
+			bySymbol[lot.Symbol] = append(bySymbol[lot.Symbol], lot)
+		}
+	}
+	if len(bySymbol) == 0 {
+		return nil, nil
+	}
+
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	quotes, err := s.quotes.GetQuotes(ctx, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes: %w", err)
+	}
+
+	var harvests []*entities.TaxLossHarvest
+	for _, symbol := range symbols {
+		quote, ok := quotes[symbol]
+		if !ok || !quote.Price.IsPositive() {
+			continue
+		}
+		candidate := s.evaluate(symbol, bySymbol[symbol], quote.Price, now)
+		if candidate == nil {
+			continue
+		}
+		if reason, err := s.washSaleConflict(ctx, userID, candidate, active, now); err != nil {
+			return harvests, err
+		} else if reason != "" {
+			s.logger.Info("Skipping tax-loss harvest",
+				zap.String("user_id", userID.String()),
+				zap.String("symbol", symbol),
+				zap.String("reason", reason))
+			continue
+		}
+
+		harvest, err := s.harvest(ctx, userID, candidate, now)
+		if err != nil {
+			s.logger.Error("Tax-loss harvest failed",
+				zap.String("user_id", userID.String()),
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			continue
+		}
+		harvests = append(harvests, harvest)
+		active = append(active, harvest)
+	}
+	return harvests, nil
+}
+
+// evaluate returns the symbol's loss lots if their combined loss clears both thresholds
+func (s *HarvestingService) evaluate(symbol string, lots []*entities.TaxLot, price decimal.Decimal, now time.Time) *harvestCandidate {
+	candidate := &harvestCandidate{
+		symbol:      symbol,
+		substitute:  s.config.Substitutes[symbol],
+		quantity:    decimal.Zero,
+		costBasis:   decimal.Zero,
+		marketValue: decimal.Zero,
+	}
+	for _, lot := range lots {
+		value := price.Mul(lot.RemainingQuantity).Round(2)
+		if value.GreaterThanOrEqual(lot.RemainingCostBasis) {
+			// Shares bought in the window before the sale and kept would make it a wash sale
+			if lot.PurchasedAt.After(now.Add(-entities.WashSaleWindow)) {
+				return nil
+			}
+			continue
+		}
+		candidate.lots = append(candidate.lots, lot)
+		candidate.quantity = candidate.quantity.Add(lot.RemainingQuantity)
+		candidate.costBasis = candidate.costBasis.Add(lot.RemainingCostBasis)
+		candidate.marketValue = candidate.marketValue.Add(value)
+	}
+	if len(candidate.lots) == 0 {
+		return nil
+	}
+
+	loss := candidate.costBasis.Sub(candidate.marketValue)
+	if loss.LessThan(s.config.MinLoss) || loss.Div(candidate.costBasis).LessThan(s.config.MinLossPercent) {
+		return nil
+	}
+	return candidate
+}
+
+// washSaleConflict explains why harvesting the candidate would create a wash sale, or returns ""
+func (s *HarvestingService) washSaleConflict(ctx context.Context, userID uuid.UUID, candidate *harvestCandidate, active []*entities.TaxLossHarvest, now time.Time) (string, error) {
+	for _, harvest := range active {
+		if harvest.Symbol == candidate.symbol || harvest.SubstituteSymbol == candidate.symbol ||
+			harvest.Symbol == candidate.substitute {
+			return fmt.Sprintf("harvest of %s into %s is within its wash sale window", harvest.Symbol, harvest.SubstituteSymbol), nil
+		}
+	}
+
+	// Buying the substitute would wash any recent loss sale of it
+	losses, err := s.lots.GetRecentLossSales(ctx, userID, candidate.substitute, now.Add(-entities.WashSaleWindow))
+	if err != nil {
+		return "", err
+	}
+	if len(losses) > 0 {
+		return fmt.Sprintf("%s was sold at a loss within the wash sale window", candidate.substitute), nil
+	}
+	return "", nil
+}
+
+// harvest pins a sale to the candidate's loss lots, sells them and buys the substitute
+func (s *HarvestingService) harvest(ctx context.Context, userID uuid.UUID, candidate *harvestCandidate, now time.Time) (*entities.TaxLossHarvest, error) {
+	harvest := &entities.TaxLossHarvest{
+		ID:               uuid.New(),
+		UserID:           userID,
+		Symbol:           candidate.symbol,
+		SubstituteSymbol: candidate.substitute,
+		Quantity:         candidate.quantity,
+		CostBasis:        candidate.costBasis,
+		MarketValue:      candidate.marketValue,
+		HarvestedLoss:    candidate.costBasis.Sub(candidate.marketValue),
+		Status:           entities.TaxLossHarvestStatusPending,
+		WashWindowEndsAt: now.Add(entities.WashSaleWindow),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	// Pin the sale to the loss lots before placing it, so a fill can never relieve other lots
+	orderID := uuid.New()
+	selection := &entities.SelectTaxLotsRequest{OrderID: orderID}
+	for _, lot := range candidate.lots {
+		selection.Lots = append(selection.Lots, entities.TaxLotSelection{LotID: lot.ID, Quantity: lot.RemainingQuantity})
+	}
+	if _, err := s.lots.SelectLots(ctx, userID, selection); err != nil {
+		return nil, s.failHarvest(ctx, harvest, fmt.Errorf("failed to select lots of %s: %w", candidate.symbol, err))
+	}
+
+	order, err := s.orderPlacer.PlaceSellOrder(ctx, orderID, userID, candidate.symbol, candidate.quantity)
+	if err != nil {
+		return nil, s.failHarvest(ctx, harvest, fmt.Errorf("failed to sell %s: %w", candidate.symbol, err))
+	}
+	harvest.SellOrderID = &order.ID
+
+	if err := s.repo.CreateHarvest(ctx, harvest); err != nil {
+		return nil, fmt.Errorf("failed to record harvest: %w", err)
+	}
+	s.buySubstitute(ctx, harvest)
+
+	s.logger.Info("Tax loss harvested",
+		zap.String("user_id", userID.String()),
+		zap.String("symbol", harvest.Symbol),
+		zap.String("substitute", harvest.SubstituteSymbol),
+		zap.String("quantity", harvest.Quantity.String()),
+		zap.String("harvested_loss", harvest.HarvestedLoss.String()))
+	return harvest, nil
+}
+
+// failHarvest records a harvest that placed no sale and returns its error
+func (s *HarvestingService) failHarvest(ctx context.Context, harvest *entities.TaxLossHarvest, err error) error {
+	msg := err.Error()
+	harvest.Status = entities.TaxLossHarvestStatusFailed
+	harvest.Error = &msg
+	if createErr := s.repo.CreateHarvest(ctx, harvest); createErr != nil {
+		s.logger.Error("Failed to record failed harvest", zap.Error(createErr))
+	}
+	return err
+}
+
+// buySubstitute reinvests the harvest's sale value in the substitute. A failed buy leaves the
+// harvest pending so the next run retries it.
+func (s *HarvestingService) buySubstitute(ctx context.Context, harvest *entities.TaxLossHarvest) {
+	order, err := s.orderPlacer.PlaceMarketOrder(ctx, harvest.UserID, harvest.SubstituteSymbol, harvest.MarketValue)
+	if err != nil {
+		msg := fmt.Sprintf("failed to buy %s: %s", harvest.SubstituteSymbol, err.Error())
+		harvest.Error = &msg
+		s.logger.Error("Failed to buy harvest substitute",
+			zap.String("harvest_id", harvest.ID.String()),
+			zap.String("substitute", harvest.SubstituteSymbol),
+			zap.Error(err))
+	} else {
+		harvest.BuyOrderID = &order.ID
+		harvest.Status = entities.TaxLossHarvestStatusCompleted
+		harvest.Error = nil
+	}
+	harvest.UpdatedAt = time.Now()
+	if err := s.repo.UpdateHarvest(ctx, harvest); err != nil {
+		s.logger.Error("Failed to update harvest", zap.String("harvest_id", harvest.ID.String()), zap.Error(err))
+	}
+}
+
+// GetHarvestReport totals the losses harvested for a user in a calendar tax year (UTC)
+func (s *HarvestingService) GetHarvestReport(ctx context.Context, userID uuid.UUID, year int) (*entities.TaxLossHarvestReport, error) {
+	if year < 1900 || year > time.Now().Year() {
+		return nil, fmt.Errorf("invalid tax year")
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	harvests, err := s.repo.GetHarvests(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get harvests: %w", err)
+	}
+
+	report := &entities.TaxLossHarvestReport{
+		UserID:        userID,
+		TaxYear:       year,
+		HarvestedLoss: decimal.Zero,
+		Harvests:      harvests,
+	}
+	for _, harvest := range harvests {
+		if harvest.Status != entities.TaxLossHarvestStatusFailed {
+			report.HarvestedLoss = report.HarvestedLoss.Add(harvest.HarvestedLoss)
+		}
+	}
+	return report, nil
+}
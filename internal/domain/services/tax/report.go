@@ -0,0 +1,114 @@
+package tax
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// realizedGainsCSVHeader lists the columns of the realized gains CSV export, one row per lot sold
+var realizedGainsCSVHeader = []string{
+	"symbol", "quantity", "date_acquired", "date_sold", "proceeds", "cost_basis",
+	"wash_sale_loss_disallowed", "gain_loss", "term",
+}
+
+// GenerateRealizedGainsReport totals a user's realized gains for sales in a calendar tax year (UTC)
+func (s *Service) GenerateRealizedGainsReport(ctx context.Context, userID uuid.UUID, year int) (*entities.RealizedGainsReport, error) {
+	if year < 1900 || year > time.Now().Year() {
+		return nil, fmt.Errorf("invalid tax year")
+	}
+	method, err := s.GetMethod(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gains, err := s.repo.GetRealizedGains(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realized gains: %w", err)
+	}
+
+	report := &entities.RealizedGainsReport{
+		UserID:             userID,
+		TaxYear:            year,
+		Method:             method,
+		Proceeds:           decimal.Zero,
+		CostBasis:          decimal.Zero,
+		ShortTermGainLoss:  decimal.Zero,
+		LongTermGainLoss:   decimal.Zero,
+		TotalGains:         decimal.Zero,
+		TotalLosses:        decimal.Zero,
+		WashSaleDisallowed: decimal.Zero,
+		Gains:              gains,
+		GeneratedAt:        time.Now(),
+	}
+	for _, gain := range gains {
+		reportable := gain.ReportableGainLoss()
+		report.Proceeds = report.Proceeds.Add(gain.Proceeds)
+		report.CostBasis = report.CostBasis.Add(gain.CostBasis)
+		report.WashSaleDisallowed = report.WashSaleDisallowed.Add(gain.DisallowedLoss)
+		if gain.HoldingPeriod == entities.HoldingPeriodLongTerm {
+			report.LongTermGainLoss = report.LongTermGainLoss.Add(reportable)
+		} else {
+			report.ShortTermGainLoss = report.ShortTermGainLoss.Add(reportable)
+		}
+		if reportable.IsPositive() {
+			report.TotalGains = report.TotalGains.Add(reportable)
+		} else {
+			report.TotalLosses = report.TotalLosses.Add(reportable.Neg())
+		}
+	}
+	return report, nil
+}
+
+// ExportRealizedGainsReport renders a realized gains report as JSON or as CSV with one row per
+// lot sold, in the column order of a broker's gains and losses statement
+func (s *Service) ExportRealizedGainsReport(report *entities.RealizedGainsReport, format entities.TaxReportFormat) ([]byte, error) {
+	switch format {
+	case entities.TaxReportFormatJSON:
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tax report: %w", err)
+		}
+		return data, nil
+
+	case entities.TaxReportFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(realizedGainsCSVHeader); err != nil {
+			return nil, fmt.Errorf("failed to encode tax report: %w", err)
+		}
+		for _, gain := range report.Gains {
+			row := []string{
+				gain.Symbol,
+				gain.Quantity.String(),
+				gain.AcquiredAt.UTC().Format("2006-01-02"),
+				gain.SoldAt.UTC().Format("2006-01-02"),
+				gain.Proceeds.StringFixed(2),
+				gain.CostBasis.StringFixed(2),
+				gain.DisallowedLoss.StringFixed(2),
+				gain.ReportableGainLoss().StringFixed(2),
+				string(gain.HoldingPeriod),
+			}
+			if err := w.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to encode tax report: %w", err)
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to encode tax report: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported tax report format")
+	}
+}
@@ -0,0 +1,489 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// Repository persists tax lots, realized gains and lot settings
+type Repository interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*entities.TaxLotSettings, error)
+	UpsertSettings(ctx context.Context, settings *entities.TaxLotSettings) error
+	GetFill(ctx context.Context, alpacaOrderID string) (*entities.TaxLotFill, error)
+	GetLot(ctx context.Context, id uuid.UUID) (*entities.TaxLot, error)
+	GetOpenLots(ctx context.Context, userID uuid.UUID, symbol string) ([]*entities.TaxLot, error)
+	GetOpenLotsByUser(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLot, error)
+	GetWashSaleCandidates(ctx context.Context, userID uuid.UUID, symbol string, since time.Time) ([]*entities.RealizedGain, error)
+	GetRealizedGains(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.RealizedGain, error)
+	ApplyFill(ctx context.Context, changes *entities.TaxLotFillChanges) error
+	GetSelections(ctx context.Context, orderID uuid.UUID) ([]*entities.TaxLotSelection, error)
+	ReplaceSelections(ctx context.Context, orderID uuid.UUID, selections []*entities.TaxLotSelection) error
+	GetActiveHarvests(ctx context.Context, userID uuid.UUID, at time.Time) ([]*entities.TaxLossHarvest, error)
+}
+
+// Service tracks tax lots from order fills, relieves them on sales and reports realized gains
+type Service struct {
+	repo   Repository
+	logger *zap.Logger
+}
+
+// NewService creates a new tax lot service
+func NewService(repo Repository, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// === Settings ===
+
+// GetSettings returns a user's lot settings: FIFO with harvesting off unless they chose otherwise
+func (s *Service) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.TaxLotSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lot settings: %w", err)
+	}
+	if settings == nil {
+		settings = &entities.TaxLotSettings{UserID: userID, Method: entities.TaxLotMethodFIFO}
+	}
+	return settings, nil
+}
+
+// GetMethod returns a user's lot relief method
+func (s *Service) GetMethod(ctx context.Context, userID uuid.UUID) (entities.TaxLotMethod, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Method, nil
+}
+
+// SetMethod changes the lot relief method applied to a user's future sales
+func (s *Service) SetMethod(ctx context.Context, userID uuid.UUID, method entities.TaxLotMethod) (*entities.TaxLotSettings, error) {
+	return s.UpdateSettings(ctx, userID, &entities.UpdateTaxLotSettingsRequest{Method: &method})
+}
+
+// UpdateSettings changes a user's lot relief method and tax-loss harvesting opt-in
+func (s *Service) UpdateSettings(ctx context.Context, userID uuid.UUID, req *entities.UpdateTaxLotSettingsRequest) (*entities.TaxLotSettings, error) {
+	if req.Method != nil && !req.Method.IsValid() {
+		return nil, fmt.Errorf("invalid tax lot method")
+	}
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Method != nil {
+		settings.Method = *req.Method
+	}
+	if req.HarvestingEnabled != nil {
+		settings.HarvestingEnabled = *req.HarvestingEnabled
+	}
+	settings.UpdatedAt = time.Now()
+	if err := s.repo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save tax lot settings: %w", err)
+	}
+	return settings, nil
+}
+
+// === Lots ===
+
+// GetOpenLots returns the lots a user still holds shares of
+func (s *Service) GetOpenLots(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLot, error) {
+	lots, err := s.repo.GetOpenLotsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lots: %w", err)
+	}
+	return lots, nil
+}
+
+// GetRecentLossSales returns a user's loss sales of a symbol since a time whose shares have not
+// all been matched to replacement purchases
+func (s *Service) GetRecentLossSales(ctx context.Context, userID uuid.UUID, symbol string, since time.Time) ([]*entities.RealizedGain, error) {
+	losses, err := s.repo.GetWashSaleCandidates(ctx, userID, strings.ToUpper(symbol), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent loss sales: %w", err)
+	}
+	return losses, nil
+}
+
+// SubstituteFor returns the symbol a buy should use instead while a tax-loss harvest of symbol
+// is within its wash sale window, or "" if symbol can be bought. Every order path that buys for
+// a user checks it, so round-ups and scheduled buys cannot wash a harvested loss.
+func (s *Service) SubstituteFor(ctx context.Context, userID uuid.UUID, symbol string) (string, error) {
+	harvests, err := s.repo.GetActiveHarvests(ctx, userID, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to get active harvests: %w", err)
+	}
+	symbol = strings.ToUpper(symbol)
+	for _, harvest := range harvests {
+		if harvest.Symbol == symbol {
+			return harvest.SubstituteSymbol, nil
+		}
+	}
+	return "", nil
+}
+
+// SelectLots chooses the lots a sell order relieves. Shares the selection does not cover are
+// relieved by the user's method.
+func (s *Service) SelectLots(ctx context.Context, userID uuid.UUID, req *entities.SelectTaxLotsRequest) ([]*entities.TaxLotSelection, error) {
+	seen := make(map[uuid.UUID]bool, len(req.Lots))
+	selections := make([]*entities.TaxLotSelection, 0, len(req.Lots))
+	for _, sel := range req.Lots {
+		if !sel.Quantity.IsPositive() {
+			return nil, fmt.Errorf("invalid lot quantity")
+		}
+		if seen[sel.LotID] {
+			return nil, fmt.Errorf("duplicate tax lot in selection")
+		}
+		seen[sel.LotID] = true
+
+		lot, err := s.repo.GetLot(ctx, sel.LotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tax lot: %w", err)
+		}
+		if lot == nil || lot.UserID != userID {
+			return nil, fmt.Errorf("tax lot not found")
+		}
+		if sel.Quantity.GreaterThan(lot.RemainingQuantity) {
+			return nil, fmt.Errorf("selected quantity exceeds lot remaining quantity")
+		}
+		selections = append(selections, &entities.TaxLotSelection{
+			OrderID:  req.OrderID,
+			LotID:    sel.LotID,
+			Quantity: sel.Quantity,
+		})
+	}
+
+	if err := s.repo.ReplaceSelections(ctx, req.OrderID, selections); err != nil {
+		return nil, fmt.Errorf("failed to save tax lot selections: %w", err)
+	}
+	return selections, nil
+}
+
+// === Fills ===
+
+// RecordFill applies an order fill to the user's tax lots: buys open a lot, sells relieve lots
+// by the user's method and realize gains. Fill events carry the order's cumulative quantity and
+// average price, so only the part not yet applied is recorded and replays are ignored.
+func (s *Service) RecordFill(ctx context.Context, order *entities.InvestmentOrder, event *entities.AlpacaOrderFillEvent) error {
+	fill, err := s.repo.GetFill(ctx, event.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get tax lot fill: %w", err)
+	}
+	if fill == nil {
+		fill = &entities.TaxLotFill{
+			AlpacaOrderID: event.OrderID,
+			UserID:        order.UserID,
+			Symbol:        strings.ToUpper(event.Symbol),
+			Side:          order.Side,
+			FilledQty:     decimal.Zero,
+			FilledAmount:  decimal.Zero,
+		}
+	}
+
+	quantity := event.FilledQty.Sub(fill.FilledQty)
+	if !quantity.IsPositive() {
+		return nil
+	}
+	filledAmount := event.FilledQty.Mul(event.FilledAvgPrice).Round(2)
+	amount := filledAmount.Sub(fill.FilledAmount)
+
+	filledAt := event.FilledAt
+	if filledAt.IsZero() {
+		filledAt = time.Now()
+	}
+
+	fill.FilledQty = event.FilledQty
+	fill.FilledAmount = filledAmount
+	fill.UpdatedAt = time.Now()
+	changes := &entities.TaxLotFillChanges{Fill: fill}
+
+	if fill.Side == entities.AlpacaOrderSideSell {
+		err = s.relieveLots(ctx, order, fill, quantity, amount, filledAt, changes)
+	} else {
+		err = s.openLot(ctx, fill, quantity, amount, filledAt, changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ApplyFill(ctx, changes); err != nil {
+		return fmt.Errorf("failed to save tax lots: %w", err)
+	}
+
+	s.logger.Info("Tax lots updated from fill",
+		zap.String("user_id", fill.UserID.String()),
+		zap.String("alpaca_order_id", fill.AlpacaOrderID),
+		zap.String("symbol", fill.Symbol),
+		zap.String("side", string(fill.Side)),
+		zap.String("quantity", quantity.String()),
+		zap.Int("realized_gains", len(changes.CreatedGains)))
+	return nil
+}
+
+// openLot records a purchase and, if it follows a loss sale of the same symbol within the wash
+// sale window, makes it the replacement for those shares
+func (s *Service) openLot(ctx context.Context, fill *entities.TaxLotFill, quantity, cost decimal.Decimal, purchasedAt time.Time, changes *entities.TaxLotFillChanges) error {
+	now := time.Now()
+	lot := &entities.TaxLot{
+		ID:                 uuid.New(),
+		UserID:             fill.UserID,
+		Symbol:             fill.Symbol,
+		AlpacaOrderID:      fill.AlpacaOrderID,
+		Quantity:           quantity,
+		RemainingQuantity:  quantity,
+		CostBasis:          cost,
+		RemainingCostBasis: cost,
+		WashSaleAdjustment: decimal.Zero,
+		PurchasedAt:        purchasedAt,
+		AcquiredAt:         purchasedAt,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	changes.CreatedLots = append(changes.CreatedLots, lot)
+
+	losses, err := s.repo.GetWashSaleCandidates(ctx, fill.UserID, fill.Symbol, purchasedAt.Add(-entities.WashSaleWindow))
+	if err != nil {
+		return fmt.Errorf("failed to get wash sale candidates: %w", err)
+	}
+	for _, loss := range losses {
+		if loss.SoldAt.After(purchasedAt) {
+			continue
+		}
+		replacement := s.applyWashSale(loss, lot, changes)
+		addUpdatedGain(changes, loss)
+		if replacement == lot {
+			break
+		}
+	}
+	return nil
+}
+
+// relieveLots relieves the sold quantity from the user's lots and records the realized gains.
+// Losses are then checked against shares of the symbol bought in the 30 days before the sale.
+func (s *Service) relieveLots(ctx context.Context, order *entities.InvestmentOrder, fill *entities.TaxLotFill, quantity, proceeds decimal.Decimal, soldAt time.Time, changes *entities.TaxLotFillChanges) error {
+	lots, err := s.repo.GetOpenLots(ctx, fill.UserID, fill.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get tax lots: %w", err)
+	}
+	method, err := s.GetMethod(ctx, fill.UserID)
+	if err != nil {
+		return err
+	}
+	plan, err := s.reliefPlan(ctx, method, order.ID, lots)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	remaining := quantity
+	allocated := decimal.Zero
+	relieved := make(map[uuid.UUID]bool)
+	for _, step := range plan {
+		if !remaining.IsPositive() {
+			break
+		}
+		take := decimal.Min(remaining, step.quantity, step.lot.RemainingQuantity)
+		if !take.IsPositive() {
+			continue
+		}
+		remaining = remaining.Sub(take)
+
+		// Proceeds are split by shares; the last portion takes the rounding residual
+		share := proceeds.Mul(take).Div(quantity).Round(2)
+		if !remaining.IsPositive() {
+			share = proceeds.Sub(allocated)
+		}
+		allocated = allocated.Add(share)
+
+		cost := step.lot.CostOf(take)
+		step.lot.RemainingQuantity = step.lot.RemainingQuantity.Sub(take)
+		step.lot.RemainingCostBasis = step.lot.RemainingCostBasis.Sub(cost)
+		step.lot.UpdatedAt = now
+		relieved[step.lot.ID] = true
+		addUpdatedLot(changes, step.lot)
+
+		changes.CreatedGains = append(changes.CreatedGains, &entities.RealizedGain{
+			ID:               uuid.New(),
+			UserID:           fill.UserID,
+			LotID:            step.lot.ID,
+			Symbol:           fill.Symbol,
+			AlpacaOrderID:    fill.AlpacaOrderID,
+			Quantity:         take,
+			Proceeds:         share,
+			CostBasis:        cost,
+			GainLoss:         share.Sub(cost),
+			WashSaleQuantity: decimal.Zero,
+			DisallowedLoss:   decimal.Zero,
+			HoldingPeriod:    entities.HoldingPeriodFor(step.lot.AcquiredAt, soldAt),
+			AcquiredAt:       step.lot.AcquiredAt,
+			SoldAt:           soldAt,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+	}
+	if remaining.IsPositive() {
+		s.logger.Warn("Sale exceeds tracked tax lots",
+			zap.String("user_id", fill.UserID.String()),
+			zap.String("symbol", fill.Symbol),
+			zap.String("untracked_quantity", remaining.String()))
+	}
+
+	// Shares bought within the window before the sale and still held replace the sold shares
+	var replacements []*entities.TaxLot
+	for _, lot := range lots {
+		if relieved[lot.ID] || lot.IsReplacement || !lot.RemainingQuantity.IsPositive() {
+			continue
+		}
+		if lot.PurchasedAt.Before(soldAt.Add(-entities.WashSaleWindow)) || lot.PurchasedAt.After(soldAt) {
+			continue
+		}
+		replacements = append(replacements, lot)
+	}
+	for _, gain := range changes.CreatedGains {
+		for len(replacements) > 0 && gain.UnmatchedWashQuantity().IsPositive() {
+			replacement := s.applyWashSale(gain, replacements[0], changes)
+			if replacement == replacements[0] {
+				replacements = replacements[1:]
+			}
+		}
+	}
+	return nil
+}
+
+type reliefStep struct {
+	lot      *entities.TaxLot
+	quantity decimal.Decimal
+}
+
+// reliefPlan orders the open lots for a sale. Lots selected for the order come first, capped
+// at the selected quantity, followed by every lot in the method's order.
+func (s *Service) reliefPlan(ctx context.Context, method entities.TaxLotMethod, orderID uuid.UUID, lots []*entities.TaxLot) ([]reliefStep, error) {
+	selections, err := s.repo.GetSelections(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lot selections: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entities.TaxLot, len(lots))
+	for _, lot := range lots {
+		byID[lot.ID] = lot
+	}
+
+	var plan []reliefStep
+	for _, sel := range selections {
+		if lot, ok := byID[sel.LotID]; ok {
+			plan = append(plan, reliefStep{lot: lot, quantity: sel.Quantity})
+		}
+	}
+
+	ordered := make([]*entities.TaxLot, len(lots))
+	copy(ordered, lots)
+	if method == entities.TaxLotMethodHIFO {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].CostPerShare().GreaterThan(ordered[j].CostPerShare())
+		})
+	}
+	for _, lot := range ordered {
+		plan = append(plan, reliefStep{lot: lot, quantity: lot.RemainingQuantity})
+	}
+	return plan, nil
+}
+
+// applyWashSale matches unmatched shares of a loss sale to a replacement lot. The disallowed
+// loss moves into the replacement's basis and the sold shares' holding period carries over.
+// A lot with more shares than needed is split so only the matched shares become a replacement
+// and the original keeps the rest. It returns the lot that received the adjustment.
+func (s *Service) applyWashSale(gain *entities.RealizedGain, lot *entities.TaxLot, changes *entities.TaxLotFillChanges) *entities.TaxLot {
+	now := time.Now()
+	unmatched := gain.UnmatchedWashQuantity()
+	matched := decimal.Min(unmatched, lot.RemainingQuantity)
+
+	disallowed := gain.GainLoss.Neg().Mul(matched).Div(gain.Quantity).Round(2)
+	if matched.Equal(unmatched) {
+		disallowed = gain.GainLoss.Neg().Sub(gain.DisallowedLoss)
+	}
+	gain.WashSaleQuantity = gain.WashSaleQuantity.Add(matched)
+	gain.DisallowedLoss = gain.DisallowedLoss.Add(disallowed)
+	gain.UpdatedAt = now
+
+	replacement := lot
+	if matched.LessThan(lot.RemainingQuantity) {
+		cost := lot.CostOf(matched)
+		replacement = &entities.TaxLot{
+			ID:                 uuid.New(),
+			UserID:             lot.UserID,
+			Symbol:             lot.Symbol,
+			AlpacaOrderID:      lot.AlpacaOrderID,
+			Quantity:           matched,
+			RemainingQuantity:  matched,
+			CostBasis:          cost,
+			RemainingCostBasis: cost,
+			WashSaleAdjustment: decimal.Zero,
+			PurchasedAt:        lot.PurchasedAt,
+			AcquiredAt:         lot.AcquiredAt,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+		lot.Quantity = lot.Quantity.Sub(matched)
+		lot.RemainingQuantity = lot.RemainingQuantity.Sub(matched)
+		lot.CostBasis = lot.CostBasis.Sub(cost)
+		lot.RemainingCostBasis = lot.RemainingCostBasis.Sub(cost)
+		lot.UpdatedAt = now
+		addUpdatedLot(changes, lot)
+		changes.CreatedLots = append(changes.CreatedLots, replacement)
+	}
+
+	replacement.CostBasis = replacement.CostBasis.Add(disallowed)
+	replacement.RemainingCostBasis = replacement.RemainingCostBasis.Add(disallowed)
+	replacement.WashSaleAdjustment = replacement.WashSaleAdjustment.Add(disallowed)
+	replacement.AcquiredAt = replacement.PurchasedAt.Add(-gain.SoldAt.Sub(gain.AcquiredAt))
+	replacement.IsReplacement = true
+	replacement.UpdatedAt = now
+	addUpdatedLot(changes, replacement)
+
+	s.logger.Info("Wash sale loss disallowed",
+		zap.String("user_id", gain.UserID.String()),
+		zap.String("symbol", gain.Symbol),
+		zap.String("realized_gain_id", gain.ID.String()),
+		zap.String("replacement_lot_id", replacement.ID.String()),
+		zap.String("disallowed_loss", disallowed.String()))
+	return replacement
+}
+
+// addUpdatedLot records a changed lot unless it is already being created or updated
+func addUpdatedLot(changes *entities.TaxLotFillChanges, lot *entities.TaxLot) {
+	for _, l := range changes.CreatedLots {
+		if l == lot {
+			return
+		}
+	}
+	for _, l := range changes.UpdatedLots {
+		if l == lot {
+			return
+		}
+	}
+	changes.UpdatedLots = append(changes.UpdatedLots, lot)
+}
+
+// addUpdatedGain records a changed realized gain unless it is already being saved
+func addUpdatedGain(changes *entities.TaxLotFillChanges, gain *entities.RealizedGain) {
+	for _, g := range changes.CreatedGains {
+		if g == gain {
+			return
+		}
+	}
+	for _, g := range changes.UpdatedGains {
+		if g == gain {
+			return
+		}
+	}
+	changes.UpdatedGains = append(changes.UpdatedGains, gain)
+}
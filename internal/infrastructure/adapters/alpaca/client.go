@@ -39,6 +39,7 @@ const (
 	portfolioEndpoint  = "/v1/trading/accounts/%s/portfolio"  // account_id parameter
 	watchlistsEndpoint = "/v1/trading/accounts/%s/watchlists" // account_id parameter
 	newsEndpoint       = "/v1beta1/news"
+	calendarEndpoint   = "/v1/calendar"
 )
 
 // Config represents Alpaca API configuration
@@ -342,6 +343,28 @@ func (c *Client) ListAssets(ctx context.Context, query map[string]string) ([]ent
 	return response, nil
 }
 
+// Calendar Methods
+
+// GetCalendar retrieves the exchange trading days between start and end, inclusive
+func (c *Client) GetCalendar(ctx context.Context, start, end time.Time) ([]entities.AlpacaCalendarDay, error) {
+	params := url.Values{}
+	params.Add("start", start.Format("2006-01-02"))
+	params.Add("end", end.Format("2006-01-02"))
+	endpoint := fmt.Sprintf("%s?%s", calendarEndpoint, params.Encode())
+
+	var response []entities.AlpacaCalendarDay
+	_, err := c.circuitBreaker.Execute(func() (interface{}, error) {
+		return &response, c.doRequestWithRetry(ctx, "GET", endpoint, nil, &response, false)
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to get Alpaca calendar", zap.Error(err))
+		return nil, fmt.Errorf("get calendar failed: %w", err)
+	}
+
+	return response, nil
+}
+
 // Position Methods
 
 // GetPosition retrieves a position by symbol
@@ -286,6 +286,24 @@ func (c *Client) UnfreezeCardAccount(ctx context.Context, customerID, cardAccoun
 	return &card, nil
 }
 
+// ActivateCardAccount activates a physical card account after delivery
+func (c *Client) ActivateCardAccount(ctx context.Context, customerID, cardAccountID string, req *ActivateCardAccountRequest) (*CardAccount, error) {
+	var card CardAccount
+	if err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("/v0/customers/%s/card_accounts/%s/activate", url.PathEscape(customerID), url.PathEscape(cardAccountID)), req, &card); err != nil {
+		return nil, fmt.Errorf("activate card account failed: %w", err)
+	}
+	return &card, nil
+}
+
+// CancelCardAccount permanently cancels a card account
+func (c *Client) CancelCardAccount(ctx context.Context, customerID, cardAccountID string) (*CardAccount, error) {
+	var card CardAccount
+	if err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("/v0/customers/%s/card_accounts/%s/cancel", url.PathEscape(customerID), url.PathEscape(cardAccountID)), nil, &card); err != nil {
+		return nil, fmt.Errorf("cancel card account failed: %w", err)
+	}
+	return &card, nil
+}
+
 // CreateTransfer creates a transfer
 func (c *Client) CreateTransfer(ctx context.Context, req *CreateTransferRequest) (*Transfer, error) {
 	var transfer Transfer
@@ -32,6 +32,8 @@ type BridgeClient interface {
 	GetCardAccount(ctx context.Context, customerID, cardAccountID string) (*CardAccount, error)
 	FreezeCardAccount(ctx context.Context, customerID, cardAccountID string) (*CardAccount, error)
 	UnfreezeCardAccount(ctx context.Context, customerID, cardAccountID string) (*CardAccount, error)
+	ActivateCardAccount(ctx context.Context, customerID, cardAccountID string, req *ActivateCardAccountRequest) (*CardAccount, error)
+	CancelCardAccount(ctx context.Context, customerID, cardAccountID string) (*CardAccount, error)
 
 	// Transfers
 	CreateTransfer(ctx context.Context, req *CreateTransferRequest) (*Transfer, error)
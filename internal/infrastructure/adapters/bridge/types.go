@@ -281,12 +281,37 @@ type CryptoAccount struct {
 	Address string `json:"address"`
 }
 
+// CardAccountType represents the form factor of a card account
+type CardAccountType string
+
+const (
+	CardAccountTypeVirtual  CardAccountType = "virtual"
+	CardAccountTypePhysical CardAccountType = "physical"
+)
+
 // CreateCardAccountRequest represents a request to create a card account
 type CreateCardAccountRequest struct {
-	ClientReferenceID string        `json:"client_reference_id,omitempty"`
-	Currency          Currency      `json:"currency"`
-	Chain             PaymentRail   `json:"chain"`
-	CryptoAccount     CryptoAccount `json:"crypto_account"`
+	ClientReferenceID string               `json:"client_reference_id,omitempty"`
+	Currency          Currency             `json:"currency"`
+	Chain             PaymentRail          `json:"chain"`
+	CryptoAccount     CryptoAccount        `json:"crypto_account"`
+	CardType          CardAccountType      `json:"card_type,omitempty"`
+	ShippingAddress   *CardShippingAddress `json:"shipping_address,omitempty"`
+}
+
+// CardShippingAddress represents the delivery address for a physical card
+type CardShippingAddress struct {
+	StreetLine1 string `json:"street_line_1"`
+	StreetLine2 string `json:"street_line_2,omitempty"`
+	City        string `json:"city"`
+	State       string `json:"state,omitempty"`
+	PostalCode  string `json:"postal_code"`
+	Country     string `json:"country"`
+}
+
+// ActivateCardAccountRequest represents a request to activate a physical card
+type ActivateCardAccountRequest struct {
+	Last4 string `json:"last_4"`
 }
 
 // CardDetails represents card details
@@ -37,6 +37,7 @@ type Config struct {
 	SocialAuth     SocialAuthConfig     `mapstructure:"social_auth"`
 	WebAuthn       WebAuthnConfig       `mapstructure:"webauthn"`
 	AI             AIConfig             `mapstructure:"ai"`
+	TaxHarvesting  TaxHarvestingConfig  `mapstructure:"tax_harvesting"`
 }
 
 // GridConfig contains Grid API configuration
@@ -355,6 +356,15 @@ type AlpacaConfig struct {
 	WebhookSecret string `mapstructure:"webhook_secret"`  // Secret for verifying Alpaca webhooks
 }
 
+// TaxHarvestingConfig contains tax-loss harvesting configuration
+type TaxHarvestingConfig struct {
+	Enabled         bool              `mapstructure:"enabled"`          // Run the harvesting worker
+	IntervalMinutes int               `mapstructure:"interval_minutes"` // Minutes between harvesting scans
+	MinLoss         float64           `mapstructure:"min_loss"`         // Minimum unrealized loss per symbol in USD
+	MinLossPercent  float64           `mapstructure:"min_loss_percent"` // Minimum loss as a fraction of cost basis
+	Substitutes     map[string]string `mapstructure:"substitutes"`      // Symbol -> correlated substitute; empty uses built-in pairs
+}
+
 // ReconciliationConfig contains reconciliation service configuration
 type ReconciliationConfig struct {
 	Enabled                bool   `mapstructure:"enabled"`                   // Enable/disable reconciliation
@@ -648,6 +658,12 @@ func setDefaults() {
 	viper.SetDefault("alpaca.data_base_url", "https://data.sandbox.alpaca.markets")
 	viper.SetDefault("alpaca.timeout", 30)
 
+	// Tax-loss harvesting defaults
+	viper.SetDefault("tax_harvesting.enabled", true)
+	viper.SetDefault("tax_harvesting.interval_minutes", 360)
+	viper.SetDefault("tax_harvesting.min_loss", 100.0)
+	viper.SetDefault("tax_harvesting.min_loss_percent", 0.05)
+
 	// Bridge defaults
 	viper.SetDefault("bridge.environment", "sandbox")
 	viper.SetDefault("bridge.base_url", "https://api.bridge.xyz")
@@ -1,13 +1,38 @@
 package di
 
 import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
 	"github.com/rail-service/rail_service/internal/domain/services"
 	"github.com/rail-service/rail_service/internal/domain/services/funding"
+	"github.com/rail-service/rail_service/internal/infrastructure/repositories"
 )
 
-// InitializeBasketExecutor creates a new basket executor
+// InitializeBasketExecutor returns the basket executor, creating it on first use
 func (c *Container) InitializeBasketExecutor() *services.BasketExecutor {
-	return services.NewBasketExecutor(c.AlpacaService, c.ZapLog)
+	if c.BasketExecutor != nil {
+		return c.BasketExecutor
+	}
+	executor := services.NewBasketExecutor(c.AlpacaService, c.ZapLog)
+	executor.SetBasketRepository(repositories.NewBasketRepository(c.DB, c.ZapLog))
+	executor.SetExecutionRepository(repositories.NewBasketExecutionRepository(sqlx.NewDb(c.DB, "postgres")))
+	// Basket buys reserve buying power, so failed legs are released back to it
+	executor.SetFundsReleaser(&buyingPowerReleaser{balances: c.BalanceRepo})
+	c.BasketExecutor = executor
+	return executor
+}
+
+// buyingPowerReleaser returns reserved basket funds to the user's buying power
+type buyingPowerReleaser struct {
+	balances *repositories.BalanceRepository
+}
+
+func (r *buyingPowerReleaser) ReleaseReservation(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	return r.balances.AddBuyingPower(ctx, userID, amount)
 }
 
 // InitializeBrokerageOnboarding creates a new brokerage onboarding service
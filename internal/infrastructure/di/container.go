@@ -23,6 +23,7 @@ import (
 	"github.com/rail-service/rail_service/internal/domain/services/autoinvest"
 	"github.com/rail-service/rail_service/internal/domain/services/card"
 	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+	"github.com/rail-service/rail_service/internal/domain/services/dividend"
 	entitysecret "github.com/rail-service/rail_service/internal/domain/services/entity_secret"
 	"github.com/rail-service/rail_service/internal/domain/services/funding"
 	"github.com/rail-service/rail_service/internal/domain/services/integration"
@@ -34,12 +35,15 @@ import (
 	"github.com/rail-service/rail_service/internal/domain/services/onboarding"
 	"github.com/rail-service/rail_service/internal/domain/services/passcode"
 	"github.com/rail-service/rail_service/internal/domain/services/reconciliation"
+	"github.com/rail-service/rail_service/internal/domain/services/rewards"
+	"github.com/rail-service/rail_service/internal/domain/services/riskprofile"
 	"github.com/rail-service/rail_service/internal/domain/services/roundup"
 	"github.com/rail-service/rail_service/internal/domain/services/security"
 	"github.com/rail-service/rail_service/internal/domain/services/session"
 	"github.com/rail-service/rail_service/internal/domain/services/socialauth"
 	"github.com/rail-service/rail_service/internal/domain/services/station"
 	"github.com/rail-service/rail_service/internal/domain/services/strategy"
+	"github.com/rail-service/rail_service/internal/domain/services/tax"
 	"github.com/rail-service/rail_service/internal/domain/services/twofa"
 	"github.com/rail-service/rail_service/internal/domain/services/wallet"
 	"github.com/rail-service/rail_service/internal/domain/services/webauthn"
@@ -374,6 +378,7 @@ type Container struct {
 	WalletService           *wallet.Service
 	FundingService          *funding.Service
 	InvestingService        *investing.Service
+	BasketExecutor          *services.BasketExecutor
 	BalanceService          *services.BalanceService
 	EntitySecretService     *entitysecret.Service
 	LedgerService           *ledger.Service
@@ -382,6 +387,10 @@ type Container struct {
 	AllocationService       *allocation.Service
 	AutoInvestService       *autoinvest.Service
 	StrategyEngine          *strategy.Engine
+	RiskProfileService      *riskprofile.Service
+	TaxService              *tax.Service
+	TaxHarvestingService    *tax.HarvestingService
+	DividendService         *dividend.Service
 	StationService          *station.Service
 	NotificationService     *services.NotificationService
 	SocialAuthService       *socialauth.Service
@@ -423,8 +432,10 @@ type Container struct {
 	// Advanced Features Services
 	PortfolioAnalyticsService  *analyticsservice.PortfolioAnalyticsService
 	MarketDataService          *marketservice.MarketDataService
+	MarketCalendar             *marketservice.CalendarService
 	ScheduledInvestmentService *investing.ScheduledInvestmentService
 	RebalancingService         *investing.RebalancingService
+	BasketCatalogService       *investing.BasketCatalogService
 
 	// Brokerage Adapter
 	BrokerageAdapter *adapters.BrokerageAdapter
@@ -441,6 +452,10 @@ type Container struct {
 	CardRepo    *repositories.CardRepository
 	CardService *card.Service
 
+	// Cashback Rewards Services
+	RewardsRepo    *repositories.RewardsRepository
+	RewardsService *rewards.Service
+
 	// Workers
 	WalletProvisioningScheduler interface{} // Type interface{} to avoid circular dependency, will be set at runtime
 	FundingWebhookManager       interface{} // Type interface{} to avoid circular dependency, will be set at runtime
@@ -813,8 +828,16 @@ func (c *Container) initializeDomainServices() error {
 		c.Logger,
 	)
 
+	// Initialize the exchange calendar shared by everything that schedules trades or snapshots.
+	// Sessions come from Alpaca when configured, otherwise from the exchange's published rules.
+	var calendarSource marketservice.CalendarSource
+	if c.AlpacaClient != nil {
+		calendarSource = marketservice.NewAlpacaCalendarSource(c.AlpacaClient)
+	}
+	c.MarketCalendar = marketservice.NewCalendarService(calendarSource, c.ZapLog)
+
 	// Initialize auto-invest service (OrderPlacer will be set after InvestingService is created)
-	_ = repositories.NewAutoInvestRepository(sqlxDB) // Keep for future use
+	autoInvestRepo := repositories.NewAutoInvestRepository(sqlxDB)
 	autoInvestConfig := autoinvest.Config{}
 	c.AutoInvestService = autoinvest.NewService(
 		c.LedgerService,
@@ -825,6 +848,8 @@ func (c *Container) initializeDomainServices() error {
 
 	// Wire auto-invest service to allocation service for automatic triggering
 	c.AllocationService.SetAutoInvestService(c.AutoInvestService)
+	c.AutoInvestService.SetMarketHours(c.MarketCalendar)
+	c.AutoInvestService.SetDeferralStore(autoInvestRepo)
 
 	// Inject allocation service into onboarding service (for auto-enabling 70/30 mode)
 	c.OnboardingService.SetAllocationService(c.AllocationService)
@@ -878,6 +903,9 @@ func (c *Container) initializeDomainServices() error {
 
 	// Initialize strategy engine and wire to auto-invest service
 	c.StrategyEngine = strategy.NewEngine(&strategyUserProfileAdapter{userRepo: c.UserRepo}, c.Logger)
+	// Select from model portfolios stored in the database and record each assignment
+	c.StrategyEngine.SetRepository(repositories.NewStrategyRepository(sqlxDB))
+	c.StrategyEngine.SetBalanceProvider(c.LedgerService)
 	c.AutoInvestService.SetStrategyEngine(c.StrategyEngine)
 
 	// Initialize reconciliation service
@@ -893,6 +921,14 @@ func (c *Container) initializeDomainServices() error {
 	auditRepo := repositories.NewAuditRepository(sqlxDB)
 	c.DomainAuditService = audit.NewService(auditRepo, c.ZapLog)
 
+	// Initialize risk profiling; its bands feed strategy selection and gate riskier investments
+	c.RiskProfileService = riskprofile.NewService(repositories.NewRiskProfileRepository(sqlxDB), c.ZapLog)
+	c.RiskProfileService.SetAuditLogger(c.DomainAuditService)
+	c.RiskProfileService.SetNotifier(c.NotificationService)
+	c.StrategyEngine.SetRiskProfileProvider(c.RiskProfileService)
+	c.AutoInvestService.SetSuitabilityChecker(c.RiskProfileService)
+	c.InvestingService.SetSuitabilityChecker(c.RiskProfileService)
+
 	// Initialize security services
 	c.LoginProtectionService = security.NewLoginProtectionService(c.RedisClient.Client(), c.ZapLog)
 	c.DeviceTrackingService = security.NewDeviceTrackingService(c.DB, c.ZapLog)
@@ -1009,6 +1045,10 @@ func (c *Container) initializeDomainServices() error {
 	if err := c.initializeAlpacaInvestmentServices(sqlxDB); err != nil {
 		c.ZapLog.Warn("Alpaca investment services initialization failed", zap.Error(err))
 	}
+	// Auto-invest buys swap into the harvest substitute during a wash sale window
+	if c.TaxService != nil {
+		autoInvestOrderPlacer.washSaleGuard = c.TaxService
+	}
 
 	// Initialize advanced features (analytics, market data, scheduled investments, rebalancing)
 	if err := c.initializeAdvancedFeatures(sqlxDB); err != nil {
@@ -1384,6 +1424,18 @@ func (m *reconciliationMetricsService) RecordReconciliationAlert(checkType, seve
 	commonmetrics.ReconciliationAlertsTotal.WithLabelValues(checkType, severity).Inc()
 }
 
+// rewardsFundingAlerter raises low rewards funding pool alerts through Prometheus metrics
+type rewardsFundingAlerter struct{}
+
+func (a *rewardsFundingAlerter) AlertLowRewardsFunding(ctx context.Context, balance, threshold, shortfall decimal.Decimal) error {
+	reason := "below_threshold"
+	if shortfall.IsPositive() {
+		reason = "shortfall"
+	}
+	commonmetrics.RewardsFundingLowAlertsTotal.WithLabelValues(reason).Inc()
+	return nil
+}
+
 // Helper function to create pointer to value
 func ptrOf[T any](v T) *T {
 	return &v
@@ -1501,6 +1553,7 @@ func (c *Container) initializeAIServices(sqlxDB *sqlx.DB, positionRepo *reposito
 		positionRepo,
 		c.ZapLog,
 	)
+	c.PortfolioDataProvider.SetBasketRepository(&basketRepoAdapter{repo: basketRepo})
 
 	c.ActivityDataProvider = aiservice.NewActivityDataProvider(
 		&contributionRepoAdapter{repo: contributionsRepo},
@@ -1680,6 +1733,10 @@ func (c *Container) initializeAlpacaInvestmentServices(sqlxDB *sqlx.DB) error {
 		c.ZapLog,
 	)
 
+	// Tax lots are opened and relieved from the fills the event processor handles
+	c.TaxService = tax.NewService(repositories.NewTaxLotRepository(sqlxDB), c.ZapLog)
+	c.AlpacaEventProcessor.SetTaxLotRecorder(c.TaxService)
+
 	// Initialize Portfolio Sync Service
 	c.AlpacaPortfolioSync = alpacaservice.NewPortfolioSyncService(
 		c.AlpacaClient,
@@ -1689,6 +1746,9 @@ func (c *Container) initializeAlpacaInvestmentServices(sqlxDB *sqlx.DB) error {
 		c.ZapLog,
 	)
 
+	// Basket buys run as resumable executions once brokerage accounts can be looked up
+	c.InvestingService.SetBasketExecutor(c.InitializeBasketExecutor(), c.AlpacaAccountRepo)
+
 	c.ZapLog.Info("Alpaca investment services initialized")
 	return nil
 }
@@ -1725,6 +1785,25 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 		orderRepo:        c.InvestmentOrderRepo,
 		logger:           c.ZapLog,
 	}
+	if c.TaxService != nil {
+		// Scheduled, round-up and rebalancing buys swap into the harvest substitute during a wash sale window
+		orderPlacer.washSaleGuard = c.TaxService
+
+		harvestingConfig := tax.DefaultHarvestingConfig()
+		harvestingConfig.MinLoss = decimal.NewFromFloat(c.Config.TaxHarvesting.MinLoss)
+		harvestingConfig.MinLossPercent = decimal.NewFromFloat(c.Config.TaxHarvesting.MinLossPercent)
+		if len(c.Config.TaxHarvesting.Substitutes) > 0 {
+			harvestingConfig.Substitutes = c.Config.TaxHarvesting.Substitutes
+		}
+		c.TaxHarvestingService = tax.NewHarvestingService(
+			repositories.NewTaxLotRepository(sqlxDB),
+			c.TaxService,
+			c.MarketDataService,
+			orderPlacer,
+			harvestingConfig,
+			c.ZapLog,
+		)
+	}
 
 	// Initialize Scheduled Investment Service
 	c.ScheduledInvestmentService = investing.NewScheduledInvestmentService(
@@ -1733,6 +1812,10 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 		c.BrokerageAdapter, // BasketOrderPlacer
 		c.ZapLog,
 	)
+	// Runs are funded from the spending balance and held back on market holidays
+	c.ScheduledInvestmentService.SetFundingLedger(c.LedgerService)
+	c.ScheduledInvestmentService.SetNotifier(c.NotificationService)
+	c.ScheduledInvestmentService.SetTradingCalendar(c.MarketCalendar)
 
 	// Initialize Rebalancing Service
 	c.RebalancingService = investing.NewRebalancingService(
@@ -1742,6 +1825,21 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 		orderPlacer,
 		c.ZapLog,
 	)
+	// Non-fractionable assets are rebalanced in whole shares
+	if c.AlpacaClient != nil {
+		c.RebalancingService.SetAssetLookup(c.AlpacaClient)
+	}
+	c.RebalancingService.SetQuantityOrderPlacer(orderPlacer)
+
+	// Initialize curated basket catalog; compositions are versioned and holders migrated on change
+	c.BasketCatalogService = investing.NewBasketCatalogService(
+		repositories.NewBasketRepository(c.DB, c.ZapLog),
+		c.ZapLog,
+	)
+	if c.AlpacaClient != nil {
+		c.BasketCatalogService.SetAssetLookup(c.AlpacaClient)
+	}
+	c.BasketCatalogService.SetHolderMigration(repositories.NewPositionRepository(c.DB, c.ZapLog), orderPlacer)
 
 	// Initialize Round-up Service
 	c.RoundupRepo = repositories.NewRoundupRepository(sqlxDB)
@@ -1752,6 +1850,22 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 		nil, // ContributionRecorder - can be added later
 		c.ZapLog,
 	)
+	// Round-ups fill underweight assets of the user's rebalancing target before the auto-invest symbol
+	c.RoundupService.SetCashFlowInvestor(c.RebalancingService)
+
+	// Dividends are credited to buying power and reinvested into the paying asset or the target allocation
+	c.DividendService = dividend.NewService(
+		repositories.NewDividendRepository(sqlxDB),
+		c.LedgerService,
+		repositories.NewUserContributionsRepository(c.DB, c.ZapLog),
+		c.ZapLog,
+	)
+	c.DividendService.SetOrderPlacer(orderPlacer)
+	c.DividendService.SetCashFlowInvestor(c.RebalancingService)
+	c.AutoInvestService.SetCashFlowInvestor(c.RebalancingService)
+	if c.AlpacaEventProcessor != nil {
+		c.AlpacaEventProcessor.SetDividendRecorder(c.DividendService)
+	}
 
 	// Initialize Copy Trading Service
 	c.CopyTradingRepo = repositories.NewCopyTradingRepository(sqlxDB)
@@ -1761,6 +1875,23 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 		&copyTradingTradingAdapter{alpacaClient: c.AlpacaClient, accountRepo: c.AlpacaAccountRepo},
 		c.ZapLog,
 	)
+	// Charge conductor fees to drafts and pay conductors through the ledger
+	c.CopyTradingService.SetLedgerService(c.LedgerService)
+	// Tell drafters when a risk limit pauses or closes their draft
+	c.CopyTradingService.SetNotifier(c.NotificationService)
+	// Only copy conductor buys of listed, liquid assets
+	c.CopyTradingService.SetAssetEligibilityProvider(&copyTradingAssetEligibilityAdapter{alpacaClient: c.AlpacaClient})
+	// Require drafters to acknowledge conductors above their risk band
+	c.CopyTradingService.SetSuitabilityChecker(c.RiskProfileService)
+	// Execute signals with several followers as block orders on the firm account
+	if c.Config.Alpaca.FirmAccountNo != "" {
+		c.CopyTradingService.SetBlockTradingAdapter(&copyTradingBlockAdapter{
+			alpacaClient:  c.AlpacaClient,
+			alpacaService: c.AlpacaService,
+			accountRepo:   c.AlpacaAccountRepo,
+			firmAccountID: c.Config.Alpaca.FirmAccountNo,
+		})
+	}
 
 	// Initialize Card Service
 	c.CardRepo = repositories.NewCardRepository(sqlxDB)
@@ -1774,6 +1905,32 @@ func (c *Container) initializeAdvancedFeatures(sqlxDB *sqlx.DB) error {
 	)
 	// Wire ledger service to card service for transaction ledger entries
 	c.CardService.SetLedgerService(c.LedgerService)
+	// Wire fraud detection for card authorization velocity and risk scoring
+	if c.FraudDetectionService != nil {
+		c.CardService.SetFraudChecker(c.FraudDetectionService)
+	}
+	// Wire notifications and passcode verification for the physical card lifecycle
+	c.CardService.SetNotifier(c.NotificationService)
+	c.CardService.SetPasscodeVerifier(c.PasscodeService)
+	// Wire audit logging for card dispute state changes
+	c.CardService.SetAuditService(c.DomainAuditService)
+	// Generate round-ups server-side from settled card purchases
+	c.RoundupService.SetLedgerService(c.LedgerService)
+	c.CardService.SetRoundupProcessor(c.RoundupService)
+	// Accrue cashback on settled card purchases and pay it into the stash once cleared
+	c.RewardsRepo = repositories.NewRewardsRepository(sqlxDB)
+	c.RewardsService = rewards.NewService(
+		c.RewardsRepo,
+		c.LedgerService,
+		repositories.NewUserContributionsRepository(c.DB, c.ZapLog),
+		c.ZapLog,
+	)
+	c.RewardsService.SetFundingAlerter(&rewardsFundingAlerter{}, rewards.DefaultLowFundingThreshold)
+	c.CardService.SetRewardsProcessor(c.RewardsService)
+	// Route Bridge card webhooks (authorizations, settlements, status and shipments) to the card service
+	if c.BridgeWebhookHandler != nil {
+		c.BridgeWebhookHandler.SetService(handlers.NewBridgeWebhookService(nil, nil, c.CardService, nil, c.ZapLog))
+	}
 
 	c.ZapLog.Info("Advanced features initialized")
 	return nil
@@ -1883,15 +2040,173 @@ func (a *copyTradingTradingAdapter) GetCurrentPrice(ctx context.Context, symbol
 	return quote.Ask, nil
 }
 
+// copyTradingAssetEligibilityAdapter implements copytrading.AssetEligibilityProvider using Alpaca
+// asset data. Alpaca does not report market capitalisation, so average daily dollar volume over
+// recent bars stands in for the asset's size and liquidity.
+type copyTradingAssetEligibilityAdapter struct {
+	alpacaClient *alpaca.Client
+}
+
+func (a *copyTradingAssetEligibilityAdapter) GetAssetEligibility(ctx context.Context, symbol string) (*entities.AssetEligibility, error) {
+	if a.alpacaClient == nil {
+		return nil, fmt.Errorf("asset eligibility adapter not configured")
+	}
+
+	asset, err := a.alpacaClient.GetAsset(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	eligibility := &entities.AssetEligibility{
+		Symbol:   asset.Symbol,
+		Tradable: asset.Tradable && asset.Status == entities.AlpacaAssetStatusActive,
+		Exchange: asset.Exchange,
+	}
+	if !eligibility.Tradable || asset.Class != entities.AlpacaAssetClassUSEquity {
+		return eligibility, nil
+	}
+
+	// Fetch enough calendar days to cover the lookback in trading days
+	end := time.Now().UTC()
+	bars, err := a.alpacaClient.GetBars(ctx, symbol, "1Day", end.AddDate(0, 0, -2*entities.ComplianceLiquidityLookback), end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bars: %w", err)
+	}
+	if len(bars) > entities.ComplianceLiquidityLookback {
+		bars = bars[len(bars)-entities.ComplianceLiquidityLookback:]
+	}
+	if len(bars) > 0 {
+		total := decimal.Zero
+		for _, bar := range bars {
+			total = total.Add(bar.Close.Mul(decimal.NewFromInt(bar.Volume)))
+		}
+		eligibility.AverageDollarVolume = total.Div(decimal.NewFromInt(int64(len(bars))))
+	}
+
+	return eligibility, nil
+}
+
+// copyTradingBlockAdapter implements copytrading.BlockTradingAdapter interface
+type copyTradingBlockAdapter struct {
+	alpacaClient  *alpaca.Client
+	alpacaService *alpaca.Service
+	accountRepo   *repositories.AlpacaAccountRepository
+	firmAccountID string
+}
+
+func (a *copyTradingBlockAdapter) PlaceBlockOrder(ctx context.Context, symbol string, side string, quantity decimal.Decimal) (string, decimal.Decimal, decimal.Decimal, error) {
+	orderSide := entities.AlpacaOrderSideBuy
+	if side == "sell" {
+		orderSide = entities.AlpacaOrderSideSell
+	}
+
+	orderReq := &entities.AlpacaCreateOrderRequest{
+		Symbol:      symbol,
+		Qty:         &quantity,
+		Side:        orderSide,
+		Type:        entities.AlpacaOrderTypeMarket,
+		TimeInForce: entities.AlpacaTimeInForceDay,
+	}
+
+	resp, err := a.alpacaClient.CreateOrder(ctx, a.firmAccountID, orderReq)
+	if err != nil {
+		return "", decimal.Zero, decimal.Zero, fmt.Errorf("failed to place block order: %w", err)
+	}
+
+	// Market orders usually fill within moments; wait briefly for the fill before allocating
+	for attempt := 0; attempt < 10 && resp.Status != entities.AlpacaOrderStatusFilled; attempt++ {
+		if resp.Status == entities.AlpacaOrderStatusCanceled || resp.Status == entities.AlpacaOrderStatusExpired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return resp.ID, decimal.Zero, decimal.Zero, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+		if resp, err = a.alpacaClient.GetOrder(ctx, a.firmAccountID, resp.ID); err != nil {
+			return "", decimal.Zero, decimal.Zero, fmt.Errorf("failed to get block order: %w", err)
+		}
+	}
+
+	// Cancel whatever is still open so later fills cannot land on the firm account unallocated
+	if resp.Status != entities.AlpacaOrderStatusFilled && resp.Status != entities.AlpacaOrderStatusCanceled &&
+		resp.Status != entities.AlpacaOrderStatusExpired {
+		if err := a.alpacaClient.CancelOrder(ctx, a.firmAccountID, resp.ID); err != nil {
+			return resp.ID, decimal.Zero, decimal.Zero, fmt.Errorf("failed to cancel unfilled block order: %w", err)
+		}
+		if resp, err = a.alpacaClient.GetOrder(ctx, a.firmAccountID, resp.ID); err != nil {
+			return "", decimal.Zero, decimal.Zero, fmt.Errorf("failed to get block order: %w", err)
+		}
+	}
+
+	averagePrice := decimal.Zero
+	if resp.FilledAvgPrice != nil {
+		averagePrice = *resp.FilledAvgPrice
+	}
+	return resp.ID, resp.FilledQty, averagePrice, nil
+}
+
+func (a *copyTradingBlockAdapter) JournalShares(ctx context.Context, userID uuid.UUID, symbol string, side string, quantity decimal.Decimal) (string, error) {
+	account, err := a.accountRepo.GetByUserID(ctx, userID)
+	if err != nil || account == nil {
+		return "", fmt.Errorf("user has no brokerage account")
+	}
+
+	req := &entities.AlpacaJournalRequest{
+		FromAccount: a.firmAccountID,
+		ToAccount:   account.AlpacaAccountID,
+		EntryType:   "JNLS",
+		Symbol:      symbol,
+		Qty:         &quantity,
+		Description: "Copy trading block order allocation",
+	}
+	if side == "sell" {
+		req.FromAccount, req.ToAccount = account.AlpacaAccountID, a.firmAccountID
+	}
+
+	resp, err := a.alpacaService.CreateJournal(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to journal shares: %w", err)
+	}
+	return resp.ID, nil
+}
+
+func (a *copyTradingBlockAdapter) JournalCash(ctx context.Context, userID uuid.UUID, side string, amount decimal.Decimal) (string, error) {
+	account, err := a.accountRepo.GetByUserID(ctx, userID)
+	if err != nil || account == nil {
+		return "", fmt.Errorf("user has no brokerage account")
+	}
+
+	req := &entities.AlpacaJournalRequest{
+		FromAccount: account.AlpacaAccountID,
+		ToAccount:   a.firmAccountID,
+		EntryType:   "JNLC",
+		Amount:      amount,
+		Description: "Copy trading block order funding",
+	}
+	if side == "sell" {
+		req.FromAccount, req.ToAccount = a.firmAccountID, account.AlpacaAccountID
+		req.Description = "Copy trading block order proceeds"
+	}
+
+	resp, err := a.alpacaService.CreateJournal(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to journal cash: %w", err)
+	}
+	return resp.ID, nil
+}
+
 // autoInvestOrderPlacerAdapter implements autoinvest.OrderPlacer interface
 type autoInvestOrderPlacerAdapter struct {
 	accountService *alpacaservice.AccountService
 	alpacaClient   *alpaca.Client
 	orderRepo      *repositories.InvestmentOrderRepository
+	washSaleGuard  washSaleGuard
 	logger         *zap.Logger
 }
 
 func (a *autoInvestOrderPlacerAdapter) PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, amount decimal.Decimal) (*entities.AlpacaOrderResponse, error) {
+	symbol = guardBuySymbol(ctx, a.washSaleGuard, userID, symbol, a.logger)
+
 	// Get user's Alpaca account
 	account, err := a.accountService.GetUserAccount(ctx, userID)
 	if err != nil {
@@ -1953,41 +2268,102 @@ func (a *strategyUserProfileAdapter) GetByID(ctx context.Context, id uuid.UUID)
 	return a.userRepo.GetByID(ctx, id)
 }
 
+// washSaleGuard names the substitute for symbols harvested within the wash sale window
+type washSaleGuard interface {
+	SubstituteFor(ctx context.Context, userID uuid.UUID, symbol string) (string, error)
+}
+
+// guardBuySymbol returns the symbol a buy should use, swapping in the harvest substitute while
+// the harvested symbol is within its wash sale window
+func guardBuySymbol(ctx context.Context, guard washSaleGuard, userID uuid.UUID, symbol string, logger *zap.Logger) string {
+	if guard == nil {
+		return symbol
+	}
+	substitute, err := guard.SubstituteFor(ctx, userID, symbol)
+	if err != nil {
+		logger.Warn("Failed to check wash sale window", zap.String("symbol", symbol), zap.Error(err))
+		return symbol
+	}
+	if substitute == "" {
+		return symbol
+	}
+	logger.Info("Redirecting buy to harvest substitute",
+		zap.String("user_id", userID.String()),
+		zap.String("symbol", symbol),
+		zap.String("substitute", substitute))
+	return substitute
+}
+
 // orderPlacerAdapter implements OrderPlacer interface for scheduled investments
 type orderPlacerAdapter struct {
 	investingService *investing.Service
 	accountService   *alpacaservice.AccountService
 	alpacaClient     *alpaca.Client
 	orderRepo        *repositories.InvestmentOrderRepository
+	washSaleGuard    washSaleGuard
 	logger           *zap.Logger
 }
 
 func (a *orderPlacerAdapter) PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error) {
-	// Get user's Alpaca account
-	account, err := a.accountService.GetUserAccount(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get account: %w", err)
-	}
-	if account == nil {
-		return nil, fmt.Errorf("user has no Alpaca account")
-	}
-
 	// Determine side based on notional sign
 	side := entities.AlpacaOrderSideBuy
 	if notional.LessThan(decimal.Zero) {
 		side = entities.AlpacaOrderSideSell
 		notional = notional.Abs()
+	} else {
+		symbol = guardBuySymbol(ctx, a.washSaleGuard, userID, symbol, a.logger)
 	}
 
-	// Create order via Alpaca
-	orderReq := &entities.AlpacaCreateOrderRequest{
+	return a.placeOrder(ctx, userID, &entities.AlpacaCreateOrderRequest{
 		Symbol:      symbol,
 		Notional:    &notional,
 		Side:        side,
 		Type:        entities.AlpacaOrderTypeMarket,
 		TimeInForce: entities.AlpacaTimeInForceDay,
+	})
+}
+
+// PlaceSellOrder sells a quantity of shares under a preassigned order ID, used when specific lots
+// have been selected for the order
+func (a *orderPlacerAdapter) PlaceSellOrder(ctx context.Context, orderID, userID uuid.UUID, symbol string, quantity decimal.Decimal) (*entities.InvestmentOrder, error) {
+	return a.placeOrderWithID(ctx, orderID, userID, &entities.AlpacaCreateOrderRequest{
+		Symbol:      symbol,
+		Qty:         &quantity,
+		Side:        entities.AlpacaOrderSideSell,
+		Type:        entities.AlpacaOrderTypeMarket,
+		TimeInForce: entities.AlpacaTimeInForceDay,
+	})
+}
+
+// PlaceQuantityOrder buys or sells a number of shares, used for assets that are not fractionable
+func (a *orderPlacerAdapter) PlaceQuantityOrder(ctx context.Context, userID uuid.UUID, symbol string, side entities.AlpacaOrderSide, quantity decimal.Decimal) (*entities.InvestmentOrder, error) {
+	if side == entities.AlpacaOrderSideBuy {
+		symbol = guardBuySymbol(ctx, a.washSaleGuard, userID, symbol, a.logger)
 	}
+	return a.placeOrder(ctx, userID, &entities.AlpacaCreateOrderRequest{
+		Symbol:      symbol,
+		Qty:         &quantity,
+		Side:        side,
+		Type:        entities.AlpacaOrderTypeMarket,
+		TimeInForce: entities.AlpacaTimeInForceDay,
+	})
+}
 
+func (a *orderPlacerAdapter) placeOrder(ctx context.Context, userID uuid.UUID, orderReq *entities.AlpacaCreateOrderRequest) (*entities.InvestmentOrder, error) {
+	return a.placeOrderWithID(ctx, uuid.New(), userID, orderReq)
+}
+
+func (a *orderPlacerAdapter) placeOrderWithID(ctx context.Context, orderID, userID uuid.UUID, orderReq *entities.AlpacaCreateOrderRequest) (*entities.InvestmentOrder, error) {
+	// Get user's Alpaca account
+	account, err := a.accountService.GetUserAccount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("user has no Alpaca account")
+	}
+
+	// Create order via Alpaca
 	alpacaOrder, err := a.alpacaClient.CreateOrder(ctx, account.AlpacaAccountID, orderReq)
 	if err != nil {
 		return nil, fmt.Errorf("create order: %w", err)
@@ -1996,16 +2372,17 @@ func (a *orderPlacerAdapter) PlaceMarketOrder(ctx context.Context, userID uuid.U
 	// Store order in database
 	now := time.Now()
 	order := &entities.InvestmentOrder{
-		ID:              uuid.New(),
+		ID:              orderID,
 		UserID:          userID,
 		AlpacaAccountID: &account.ID,
 		AlpacaOrderID:   &alpacaOrder.ID,
 		ClientOrderID:   alpacaOrder.ClientOrderID,
-		Symbol:          symbol,
-		Side:            side,
+		Symbol:          orderReq.Symbol,
+		Side:            orderReq.Side,
 		OrderType:       entities.AlpacaOrderTypeMarket,
 		TimeInForce:     entities.AlpacaTimeInForceDay,
-		Notional:        &notional,
+		Qty:             orderReq.Qty,
+		Notional:        orderReq.Notional,
 		Status:          alpacaOrder.Status,
 		SubmittedAt:     &now,
 		CreatedAt:       now,
@@ -2103,6 +2480,11 @@ func (c *Container) GetMarketDataService() *marketservice.MarketDataService {
 	return c.MarketDataService
 }
 
+// GetMarketCalendar returns the exchange calendar
+func (c *Container) GetMarketCalendar() *marketservice.CalendarService {
+	return c.MarketCalendar
+}
+
 // GetScheduledInvestmentService returns the scheduled investment service
 func (c *Container) GetScheduledInvestmentService() *investing.ScheduledInvestmentService {
 	return c.ScheduledInvestmentService
@@ -2113,6 +2495,20 @@ func (c *Container) GetRebalancingService() *investing.RebalancingService {
 	return c.RebalancingService
 }
 
+// GetBasketCatalogService returns the curated basket catalog service
+func (c *Container) GetBasketCatalogService() *investing.BasketCatalogService {
+	return c.BasketCatalogService
+}
+
+// GetAdminHandlers returns admin handlers with curated basket management
+func (c *Container) GetAdminHandlers() *handlers.AdminHandlers {
+	adminHandlers := handlers.NewAdminHandlers(c.DB, c.Config, c.ZapLog)
+	if c.BasketCatalogService != nil {
+		adminHandlers.SetBasketCatalog(c.BasketCatalogService)
+	}
+	return adminHandlers
+}
+
 // GetInvestmentHandlers returns investment handlers
 func (c *Container) GetInvestmentHandlers() *handlers.InvestmentHandlers {
 	if c.AlpacaAccountService == nil {
@@ -2186,6 +2582,19 @@ func (c *Container) GetRoundupHandlers() *handlers.RoundupHandlers {
 	return handlers.NewRoundupHandlers(c.RoundupService, c.ZapLog)
 }
 
+// GetRewardsService returns the cashback rewards service
+func (c *Container) GetRewardsService() *rewards.Service {
+	return c.RewardsService
+}
+
+// GetRewardsHandlers returns cashback rewards handlers
+func (c *Container) GetRewardsHandlers() *handlers.RewardsHandlers {
+	if c.RewardsService == nil {
+		return nil
+	}
+	return handlers.NewRewardsHandlers(c.RewardsService, c.ZapLog)
+}
+
 // GetCopyTradingService returns the copy trading service
 func (c *Container) GetCopyTradingService() *copytrading.Service {
 	return c.CopyTradingService
@@ -2243,6 +2652,58 @@ func (c *Container) GetInvestmentStashHandlers() *handlers.InvestmentStashHandle
 	)
 }
 
+// GetStrategyHandlers returns model portfolio and strategy assignment handlers
+func (c *Container) GetStrategyHandlers() *handlers.StrategyHandlers {
+	if c.StrategyEngine == nil {
+		return nil
+	}
+	return handlers.NewStrategyHandlers(c.StrategyEngine, c.Logger)
+}
+
+// GetRiskProfileHandlers returns risk questionnaire and risk profile handlers
+func (c *Container) GetRiskProfileHandlers() *handlers.RiskProfileHandlers {
+	if c.RiskProfileService == nil {
+		return nil
+	}
+	return handlers.NewRiskProfileHandlers(c.RiskProfileService, c.Logger)
+}
+
+// GetRiskProfileService returns the risk profile service
+func (c *Container) GetRiskProfileService() *riskprofile.Service {
+	return c.RiskProfileService
+}
+
+// GetTaxHandlers returns tax lot and realized gains handlers
+func (c *Container) GetTaxHandlers() *handlers.TaxHandlers {
+	if c.TaxService == nil {
+		return nil
+	}
+	return handlers.NewTaxHandlers(c.TaxService, c.TaxHarvestingService, c.Logger)
+}
+
+// GetTaxService returns the tax lot service
+func (c *Container) GetTaxService() *tax.Service {
+	return c.TaxService
+}
+
+// GetTaxHarvestingService returns the tax-loss harvesting service
+func (c *Container) GetTaxHarvestingService() *tax.HarvestingService {
+	return c.TaxHarvestingService
+}
+
+// GetDividendHandlers returns dividend history and reinvestment settings handlers
+func (c *Container) GetDividendHandlers() *handlers.DividendHandlers {
+	if c.DividendService == nil {
+		return nil
+	}
+	return handlers.NewDividendHandlers(c.DividendService, c.Logger)
+}
+
+// GetDividendService returns the dividend service
+func (c *Container) GetDividendService() *dividend.Service {
+	return c.DividendService
+}
+
 // GetCopyTradingRepository returns the copy trading repository
 func (c *Container) GetCopyTradingRepository() *repositories.CopyTradingRepository {
 	return c.CopyTradingRepo
@@ -2295,8 +2756,7 @@ func (c *Container) initializeBridgeServices() {
 	// Determine if webhook verification should be skipped (only in development)
 	skipWebhookVerification := c.Config.Environment == "development" && webhookSecret == ""
 
-	// Create a minimal webhook service for now
-	// Full service will be wired after domain services are initialized
+	// The webhook service is set once the card service exists (initializeAdvancedFeatures)
 	c.BridgeWebhookHandler = handlers.NewBridgeWebhookHandler(
 		nil, // Service will be set later
 		c.ZapLog,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -115,3 +116,55 @@ func (r *AutoInvestRepository) GetPendingUsers(ctx context.Context, threshold de
 
 	return userIDs, nil
 }
+
+// SaveDeferral queues an auto-investment until the market opens. A deferral saved again for the
+// same trigger moves to the new open time.
+func (r *AutoInvestRepository) SaveDeferral(ctx context.Context, deferral *entities.DeferredAutoInvestment) error {
+	query := `
+		INSERT INTO auto_invest_deferrals (correlation_id, user_id, stash_id, not_before, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (correlation_id) DO UPDATE SET not_before = EXCLUDED.not_before
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		deferral.CorrelationID,
+		deferral.UserID,
+		deferral.StashID,
+		deferral.NotBefore,
+		deferral.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save auto-invest deferral: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueDeferrals returns deferred auto-investments whose open time has passed, oldest first
+func (r *AutoInvestRepository) GetDueDeferrals(ctx context.Context, now time.Time, limit int) ([]*entities.DeferredAutoInvestment, error) {
+	query := `
+		SELECT correlation_id, user_id, stash_id, not_before, created_at
+		FROM auto_invest_deferrals
+		WHERE not_before <= $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	var deferrals []*entities.DeferredAutoInvestment
+	if err := r.db.SelectContext(ctx, &deferrals, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to get due auto-invest deferrals: %w", err)
+	}
+
+	return deferrals, nil
+}
+
+// DeleteDeferral removes a deferred auto-investment once it has been triggered
+func (r *AutoInvestRepository) DeleteDeferral(ctx context.Context, correlationID string) error {
+	query := `DELETE FROM auto_invest_deferrals WHERE correlation_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, correlationID); err != nil {
+		return fmt.Errorf("failed to delete auto-invest deferral: %w", err)
+	}
+
+	return nil
+}
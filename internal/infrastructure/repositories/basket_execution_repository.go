@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// BasketExecutionRepository handles basket execution and leg database operations
+type BasketExecutionRepository struct {
+	db *sqlx.DB
+}
+
+// NewBasketExecutionRepository creates a new basket execution repository
+func NewBasketExecutionRepository(db *sqlx.DB) *BasketExecutionRepository {
+	return &BasketExecutionRepository{db: db}
+}
+
+const basketExecutionColumns = `
+	id, user_id, basket_id, alpaca_account_id, idempotency_key, total_amount, submitted_amount,
+	released_amount, status, created_at, updated_at, completed_at`
+
+const basketExecutionLegColumns = `
+	id, execution_id, symbol, percentage, amount, client_order_id, alpaca_order_id, status, attempts,
+	last_error, submitted_at, released_at, updated_at`
+
+// CreateExecution stores an execution together with its legs
+func (r *BasketExecutionRepository) CreateExecution(ctx context.Context, execution *entities.BasketExecution) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO basket_executions (` + basketExecutionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		execution.ID, execution.UserID, execution.BasketID, execution.AlpacaAccountID, execution.IdempotencyKey,
+		execution.TotalAmount, execution.SubmittedAmount, execution.ReleasedAmount, execution.Status,
+		execution.CreatedAt, execution.UpdatedAt, execution.CompletedAt); err != nil {
+		return fmt.Errorf("failed to create basket execution: %w", err)
+	}
+
+	legQuery := `
+		INSERT INTO basket_execution_legs (` + basketExecutionLegColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	for _, leg := range execution.Legs {
+		if _, err := tx.ExecContext(ctx, legQuery,
+			leg.ID, leg.ExecutionID, leg.Symbol, leg.Percentage, leg.Amount, leg.ClientOrderID, leg.AlpacaOrderID,
+			leg.Status, leg.Attempts, leg.LastError, leg.SubmittedAt, leg.ReleasedAt, leg.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to create basket execution leg: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetExecution returns an execution with its legs, or nil if it does not exist
+func (r *BasketExecutionRepository) GetExecution(ctx context.Context, id uuid.UUID) (*entities.BasketExecution, error) {
+	query := `SELECT ` + basketExecutionColumns + ` FROM basket_executions WHERE id = $1`
+	return r.getExecution(ctx, query, id)
+}
+
+// GetExecutionByIdempotencyKey returns a user's execution for an idempotency key, or nil if none exists
+func (r *BasketExecutionRepository) GetExecutionByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*entities.BasketExecution, error) {
+	query := `SELECT ` + basketExecutionColumns + ` FROM basket_executions WHERE user_id = $1 AND idempotency_key = $2`
+	return r.getExecution(ctx, query, userID, key)
+}
+
+func (r *BasketExecutionRepository) getExecution(ctx context.Context, query string, args ...interface{}) (*entities.BasketExecution, error) {
+	var execution entities.BasketExecution
+	err := r.db.GetContext(ctx, &execution, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get basket execution: %w", err)
+	}
+
+	legQuery := `
+		SELECT ` + basketExecutionLegColumns + `
+		FROM basket_execution_legs
+		WHERE execution_id = $1
+		ORDER BY percentage DESC, symbol
+	`
+	if err := r.db.SelectContext(ctx, &execution.Legs, legQuery, execution.ID); err != nil {
+		return nil, fmt.Errorf("failed to get basket execution legs: %w", err)
+	}
+	return &execution, nil
+}
+
+// UpdateExecution saves an execution's status and running totals
+func (r *BasketExecutionRepository) UpdateExecution(ctx context.Context, execution *entities.BasketExecution) error {
+	query := `
+		UPDATE basket_executions
+		SET status = $2, submitted_amount = $3, released_amount = $4, updated_at = $5, completed_at = $6
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		execution.ID, execution.Status, execution.SubmittedAmount, execution.ReleasedAmount,
+		execution.UpdatedAt, execution.CompletedAt); err != nil {
+		return fmt.Errorf("failed to update basket execution: %w", err)
+	}
+	return nil
+}
+
+// UpdateLeg saves a leg's status, attempts and broker order
+func (r *BasketExecutionRepository) UpdateLeg(ctx context.Context, leg *entities.BasketExecutionLeg) error {
+	query := `
+		UPDATE basket_execution_legs
+		SET alpaca_order_id = $2, status = $3, attempts = $4, last_error = $5, submitted_at = $6,
+		    released_at = $7, updated_at = $8
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		leg.ID, leg.AlpacaOrderID, leg.Status, leg.Attempts, leg.LastError, leg.SubmittedAt,
+		leg.ReleasedAt, leg.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update basket execution leg: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rail-service/rail_service/internal/domain/entities"
@@ -28,7 +29,7 @@ func NewBasketRepository(db *sql.DB, logger *zap.Logger) *BasketRepository {
 // GetAll retrieves all available baskets
 func (r *BasketRepository) GetAll(ctx context.Context) ([]*entities.Basket, error) {
 	query := `
-		SELECT id, name, description, risk_level, composition_json, created_at, updated_at
+		SELECT id, name, description, risk_level, composition_json, current_version, created_at, updated_at
 		FROM baskets
 		ORDER BY name ASC
 	`
@@ -50,6 +51,7 @@ func (r *BasketRepository) GetAll(ctx context.Context) ([]*entities.Basket, erro
 			&basket.Description,
 			&basket.RiskLevel,
 			&compositionJSON,
+			&basket.CurrentVersion,
 			&basket.CreatedAt,
 			&basket.UpdatedAt,
 		); err != nil {
@@ -78,7 +80,7 @@ func (r *BasketRepository) GetAll(ctx context.Context) ([]*entities.Basket, erro
 // GetByID retrieves a specific basket by ID
 func (r *BasketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
 	query := `
-		SELECT id, name, description, risk_level, composition_json, created_at, updated_at
+		SELECT id, name, description, risk_level, composition_json, current_version, created_at, updated_at
 		FROM baskets
 		WHERE id = $1
 	`
@@ -91,6 +93,7 @@ func (r *BasketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities
 		&basket.Description,
 		&basket.RiskLevel,
 		&compositionJSON,
+		&basket.CurrentVersion,
 		&basket.CreatedAt,
 		&basket.UpdatedAt,
 	)
@@ -114,3 +117,296 @@ func (r *BasketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities
 	r.logger.Debug("Retrieved basket", zap.String("basket_id", id.String()))
 	return basket, nil
 }
+
+// Create stores a new basket together with its first composition version
+func (r *BasketRepository) Create(ctx context.Context, basket *entities.Basket, version *entities.BasketVersion) error {
+	composition, err := json.Marshal(basket.Composition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal basket composition: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO baskets (id, name, description, risk_level, composition_json, current_version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		basket.ID, basket.Name, basket.Description, basket.RiskLevel, composition,
+		basket.CurrentVersion, basket.CreatedAt, basket.UpdatedAt); err != nil {
+		r.logger.Error("Failed to create basket", zap.Error(err))
+		return fmt.Errorf("failed to create basket: %w", err)
+	}
+
+	if err := r.insertVersion(ctx, tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateDetails updates a basket's name, description and risk level. It returns sql.ErrNoRows
+// if the basket does not exist.
+func (r *BasketRepository) UpdateDetails(ctx context.Context, basket *entities.Basket) error {
+	query := `
+		UPDATE baskets
+		SET name = $2, description = $3, risk_level = $4, updated_at = $5
+		WHERE id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		basket.ID, basket.Name, basket.Description, basket.RiskLevel, basket.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to update basket", zap.Error(err), zap.String("basket_id", basket.ID.String()))
+		return fmt.Errorf("failed to update basket: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateVersion stores a new composition version numbered after the basket's latest one. A
+// scheduled version replaces any change that was already scheduled.
+func (r *BasketRepository) CreateVersion(ctx context.Context, version *entities.BasketVersion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the basket so concurrent changes get distinct version numbers
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM baskets WHERE id = $1 FOR UPDATE`, version.BasketID); err != nil {
+		return fmt.Errorf("failed to lock basket: %w", err)
+	}
+
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM basket_versions WHERE basket_id = $1`,
+		version.BasketID).Scan(&version.Version); err != nil {
+		return fmt.Errorf("failed to get next basket version: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE basket_versions SET status = 'cancelled' WHERE basket_id = $1 AND status = 'scheduled'`,
+		version.BasketID); err != nil {
+		return fmt.Errorf("failed to cancel scheduled basket version: %w", err)
+	}
+
+	if err := r.insertVersion(ctx, tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *BasketRepository) insertVersion(ctx context.Context, tx *sql.Tx, version *entities.BasketVersion) error {
+	composition, err := json.Marshal(version.Composition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal basket composition: %w", err)
+	}
+
+	query := `
+		INSERT INTO basket_versions (id, basket_id, version, composition, status, effective_at, activated_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		version.ID, version.BasketID, version.Version, composition, version.Status,
+		version.EffectiveAt, version.ActivatedAt, version.CreatedBy, version.CreatedAt); err != nil {
+		r.logger.Error("Failed to create basket version", zap.Error(err), zap.String("basket_id", version.BasketID.String()))
+		return fmt.Errorf("failed to create basket version: %w", err)
+	}
+	return nil
+}
+
+const basketVersionColumns = `id, basket_id, version, composition, status, effective_at, activated_at, created_by, created_at`
+
+// GetVersion retrieves one composition version of a basket, or nil if it does not exist
+func (r *BasketRepository) GetVersion(ctx context.Context, basketID uuid.UUID, version int) (*entities.BasketVersion, error) {
+	query := `SELECT ` + basketVersionColumns + ` FROM basket_versions WHERE basket_id = $1 AND version = $2`
+	versions, err := r.queryVersions(ctx, query, basketID, version)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	return versions[0], nil
+}
+
+// GetVersions retrieves every composition version of a basket, newest first
+func (r *BasketRepository) GetVersions(ctx context.Context, basketID uuid.UUID) ([]*entities.BasketVersion, error) {
+	query := `SELECT ` + basketVersionColumns + ` FROM basket_versions WHERE basket_id = $1 ORDER BY version DESC`
+	return r.queryVersions(ctx, query, basketID)
+}
+
+// GetDueVersions retrieves scheduled versions whose effective time has passed
+func (r *BasketRepository) GetDueVersions(ctx context.Context, at time.Time, limit int) ([]*entities.BasketVersion, error) {
+	query := `
+		SELECT ` + basketVersionColumns + `
+		FROM basket_versions
+		WHERE status = 'scheduled' AND effective_at <= $1
+		ORDER BY effective_at
+		LIMIT $2
+	`
+	return r.queryVersions(ctx, query, at, limit)
+}
+
+func (r *BasketRepository) queryVersions(ctx context.Context, query string, args ...interface{}) ([]*entities.BasketVersion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to query basket versions", zap.Error(err))
+		return nil, fmt.Errorf("failed to query basket versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*entities.BasketVersion
+	for rows.Next() {
+		version := &entities.BasketVersion{}
+		var compositionJSON []byte
+		if err := rows.Scan(
+			&version.ID,
+			&version.BasketID,
+			&version.Version,
+			&compositionJSON,
+			&version.Status,
+			&version.EffectiveAt,
+			&version.ActivatedAt,
+			&version.CreatedBy,
+			&version.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan basket version: %w", err)
+		}
+		if err := json.Unmarshal(compositionJSON, &version.Composition); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal basket composition: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating basket versions: %w", err)
+	}
+	return versions, nil
+}
+
+// ActivateVersion makes a version the basket's composition, supersedes the previous one and
+// queues a migration for every user holding the basket. It returns the number of holders queued.
+func (r *BasketRepository) ActivateVersion(ctx context.Context, version *entities.BasketVersion) (int, error) {
+	composition, err := json.Marshal(version.Composition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal basket composition: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT current_version FROM baskets WHERE id = $1 FOR UPDATE`, version.BasketID).Scan(&previous); err != nil {
+		return 0, fmt.Errorf("failed to lock basket: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE basket_versions SET status = 'superseded' WHERE basket_id = $1 AND status = 'active'`,
+		version.BasketID); err != nil {
+		return 0, fmt.Errorf("failed to supersede basket version: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE basket_versions SET status = 'active', activated_at = $2 WHERE id = $1`,
+		version.ID, version.ActivatedAt); err != nil {
+		return 0, fmt.Errorf("failed to activate basket version: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE baskets SET composition_json = $2, current_version = $3, updated_at = $4 WHERE id = $1`,
+		version.BasketID, composition, version.Version, version.ActivatedAt); err != nil {
+		return 0, fmt.Errorf("failed to update basket composition: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO basket_holder_migrations (basket_id, user_id, from_version, to_version, created_at)
+		SELECT basket_id, user_id, $2, $3, $4
+		FROM positions
+		WHERE basket_id = $1 AND quantity > 0
+		ON CONFLICT (basket_id, user_id, to_version) DO NOTHING
+	`, version.BasketID, previous, version.Version, version.ActivatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue basket holder migrations: %w", err)
+	}
+	holders, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit basket version activation: %w", err)
+	}
+	return int(holders), nil
+}
+
+// GetPendingHolderMigrations retrieves holder migrations that have not been traded yet, oldest first
+func (r *BasketRepository) GetPendingHolderMigrations(ctx context.Context, limit int) ([]*entities.BasketHolderMigration, error) {
+	query := `
+		SELECT id, basket_id, user_id, from_version, to_version, status, trades, attempts, error, created_at, completed_at
+		FROM basket_holder_migrations
+		WHERE status = 'pending'
+		ORDER BY created_at
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("Failed to query basket holder migrations", zap.Error(err))
+		return nil, fmt.Errorf("failed to query basket holder migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []*entities.BasketHolderMigration
+	for rows.Next() {
+		migration := &entities.BasketHolderMigration{}
+		var tradesJSON []byte
+		if err := rows.Scan(
+			&migration.ID,
+			&migration.BasketID,
+			&migration.UserID,
+			&migration.FromVersion,
+			&migration.ToVersion,
+			&migration.Status,
+			&tradesJSON,
+			&migration.Attempts,
+			&migration.Error,
+			&migration.CreatedAt,
+			&migration.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan basket holder migration: %w", err)
+		}
+		if err := json.Unmarshal(tradesJSON, &migration.Trades); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal migration trades: %w", err)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating basket holder migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+// UpdateHolderMigration saves a holder migration's status, its trades and which have been placed
+func (r *BasketRepository) UpdateHolderMigration(ctx context.Context, migration *entities.BasketHolderMigration) error {
+	trades, err := json.Marshal(migration.Trades)
+	if err != nil {
+		return fmt.Errorf("failed to marshal migration trades: %w", err)
+	}
+
+	query := `
+		UPDATE basket_holder_migrations
+		SET status = $2, trades = $3, attempts = $4, error = $5, completed_at = $6
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		migration.ID, migration.Status, trades, migration.Attempts, migration.Error, migration.CompletedAt); err != nil {
+		r.logger.Error("Failed to update basket holder migration", zap.Error(err))
+		return fmt.Errorf("failed to update basket holder migration: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"github.com/rail-service/rail_service/internal/domain/entities"
 )
 
@@ -27,9 +29,9 @@ func (r *CardRepository) Create(ctx context.Context, card *entities.BridgeCard)
 		INSERT INTO cards (
 			id, user_id, bridge_card_id, bridge_customer_id, type, status,
 			last_4, expiry, card_image_url, currency, chain, wallet_address,
-			created_at, updated_at
+			replaces_card_id, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 		)`
 
 	if card.ID == uuid.Nil {
@@ -42,7 +44,8 @@ func (r *CardRepository) Create(ctx context.Context, card *entities.BridgeCard)
 	_, err := r.db.ExecContext(ctx, query,
 		card.ID, card.UserID, card.BridgeCardID, card.BridgeCustomerID,
 		card.Type, card.Status, card.Last4, card.Expiry, card.CardImageURL,
-		card.Currency, card.Chain, card.WalletAddress, card.CreatedAt, card.UpdatedAt,
+		card.Currency, card.Chain, card.WalletAddress, card.ReplacesCardID,
+		card.CreatedAt, card.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create card: %w", err)
@@ -92,7 +95,12 @@ func (r *CardRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*
 // GetActiveVirtualCard retrieves the user's active virtual card
 func (r *CardRepository) GetActiveVirtualCard(ctx context.Context, userID uuid.UUID) (*entities.BridgeCard, error) {
 	var card entities.BridgeCard
-	query := `SELECT * FROM cards WHERE user_id = $1 AND type = 'virtual' AND status = 'active' LIMIT 1`
+	// Single-use cards are disposable and never count as the user's primary virtual card
+	query := `
+		SELECT * FROM cards
+		WHERE user_id = $1 AND type = 'virtual' AND status = 'active'
+		AND NOT EXISTS (SELECT 1 FROM card_controls cc WHERE cc.card_id = cards.id AND cc.single_use)
+		ORDER BY created_at ASC LIMIT 1`
 	err := r.db.GetContext(ctx, &card, query, userID)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -113,15 +121,99 @@ func (r *CardRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
+// MarkActivated marks a card as active and records the activation time
+func (r *CardRepository) MarkActivated(ctx context.Context, id uuid.UUID, activatedAt time.Time) error {
+	query := `UPDATE cards SET status = 'active', activated_at = $1, updated_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, activatedAt, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark card activated: %w", err)
+	}
+	return nil
+}
+
+// Cancel cancels a card and records the reason
+func (r *CardRepository) Cancel(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `UPDATE cards SET status = 'cancelled', cancel_reason = $1, updated_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, reason, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel card: %w", err)
+	}
+	return nil
+}
+
+// CreateShipment creates a physical card shipment record
+func (r *CardRepository) CreateShipment(ctx context.Context, shipment *entities.CardShipment) error {
+	query := `
+		INSERT INTO card_shipments (
+			id, card_id, user_id, street_line_1, street_line_2, city, state,
+			postal_code, country, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`
+
+	if shipment.ID == uuid.Nil {
+		shipment.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	shipment.CreatedAt = now
+	shipment.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		shipment.ID, shipment.CardID, shipment.UserID, shipment.StreetLine1,
+		shipment.StreetLine2, shipment.City, shipment.State, shipment.PostalCode,
+		shipment.Country, shipment.Status, shipment.CreatedAt, shipment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create card shipment: %w", err)
+	}
+	return nil
+}
+
+// GetShipmentByCardID retrieves the shipment for a physical card
+func (r *CardRepository) GetShipmentByCardID(ctx context.Context, cardID uuid.UUID) (*entities.CardShipment, error) {
+	var shipment entities.CardShipment
+	query := `SELECT * FROM card_shipments WHERE card_id = $1`
+	err := r.db.GetContext(ctx, &shipment, query, cardID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card shipment: %w", err)
+	}
+	return &shipment, nil
+}
+
+// UpdateShipment updates the delivery status and tracking details of a shipment
+func (r *CardRepository) UpdateShipment(ctx context.Context, shipment *entities.CardShipment) error {
+	query := `
+		UPDATE card_shipments SET
+			status = $1, carrier = $2, tracking_number = $3, tracking_url = $4,
+			shipped_at = $5, delivered_at = $6, updated_at = $7
+		WHERE id = $8`
+
+	shipment.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, query,
+		shipment.Status, shipment.Carrier, shipment.TrackingNumber, shipment.TrackingURL,
+		shipment.ShippedAt, shipment.DeliveredAt, shipment.UpdatedAt, shipment.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update card shipment: %w", err)
+	}
+	return nil
+}
+
 // CreateTransaction creates a new card transaction
 func (r *CardRepository) CreateTransaction(ctx context.Context, tx *entities.BridgeCardTransaction) error {
 	query := `
 		INSERT INTO card_transactions (
 			id, card_id, user_id, bridge_trans_id, type, amount, currency,
 			merchant_name, merchant_category, status, decline_reason,
-			created_at, updated_at
+			decline_code, risk_score, created_at, updated_at,
+			merchant_display_name, merchant_key, spending_category,
+			category_source, is_recurring
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20
 		)`
 
 	if tx.ID == uuid.Nil {
@@ -134,7 +226,8 @@ func (r *CardRepository) CreateTransaction(ctx context.Context, tx *entities.Bri
 	_, err := r.db.ExecContext(ctx, query,
 		tx.ID, tx.CardID, tx.UserID, tx.BridgeTransID, tx.Type, tx.Amount,
 		tx.Currency, tx.MerchantName, tx.MerchantCategory, tx.Status,
-		tx.DeclineReason, tx.CreatedAt, tx.UpdatedAt,
+		tx.DeclineReason, tx.DeclineCode, tx.RiskScore, tx.CreatedAt, tx.UpdatedAt,
+		tx.MerchantDisplayName, tx.MerchantKey, tx.Category, tx.CategorySource, tx.IsRecurring,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create card transaction: %w", err)
@@ -156,6 +249,20 @@ func (r *CardRepository) GetTransactionByBridgeID(ctx context.Context, bridgeTra
 	return &tx, nil
 }
 
+// GetTransactionByID retrieves a transaction by ID
+func (r *CardRepository) GetTransactionByID(ctx context.Context, id uuid.UUID) (*entities.BridgeCardTransaction, error) {
+	var tx entities.BridgeCardTransaction
+	query := `SELECT * FROM card_transactions WHERE id = $1`
+	err := r.db.GetContext(ctx, &tx, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &tx, nil
+}
+
 // GetTransactionsByCardID retrieves transactions for a card
 func (r *CardRepository) GetTransactionsByCardID(ctx context.Context, cardID uuid.UUID, limit, offset int) ([]*entities.BridgeCardTransaction, error) {
 	var txs []*entities.BridgeCardTransaction
@@ -188,6 +295,111 @@ func (r *CardRepository) UpdateTransactionStatus(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// GetMerchantTransactionsSince retrieves a user's non-declined transactions at a merchant since the given time
+func (r *CardRepository) GetMerchantTransactionsSince(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) ([]*entities.BridgeCardTransaction, error) {
+	var txs []*entities.BridgeCardTransaction
+	query := `
+		SELECT * FROM card_transactions
+		WHERE user_id = $1 AND merchant_key = $2 AND created_at >= $3
+			AND status IN ('pending', 'completed')
+		ORDER BY created_at ASC`
+	err := r.db.SelectContext(ctx, &txs, query, userID, merchantKey, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// UpdateTransactionCategory sets the category of a single transaction
+func (r *CardRepository) UpdateTransactionCategory(ctx context.Context, id uuid.UUID, category entities.SpendingCategory, source entities.CategorySource) error {
+	query := `UPDATE card_transactions SET spending_category = $1, category_source = $2, updated_at = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, category, source, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction category: %w", err)
+	}
+	return nil
+}
+
+// UpdateMerchantCategory re-categorizes all of a user's transactions at a merchant
+func (r *CardRepository) UpdateMerchantCategory(ctx context.Context, userID uuid.UUID, merchantKey string, category entities.SpendingCategory, source entities.CategorySource) error {
+	query := `
+		UPDATE card_transactions SET spending_category = $1, category_source = $2, updated_at = $3
+		WHERE user_id = $4 AND merchant_key = $5`
+	_, err := r.db.ExecContext(ctx, query, category, source, time.Now().UTC(), userID, merchantKey)
+	if err != nil {
+		return fmt.Errorf("failed to update merchant category: %w", err)
+	}
+	return nil
+}
+
+// MarkMerchantRecurring flags a user's transactions at a merchant since the given time as recurring
+func (r *CardRepository) MarkMerchantRecurring(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) error {
+	query := `
+		UPDATE card_transactions SET is_recurring = TRUE, updated_at = $1
+		WHERE user_id = $2 AND merchant_key = $3 AND created_at >= $4 AND NOT is_recurring`
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), userID, merchantKey, since)
+	if err != nil {
+		return fmt.Errorf("failed to mark recurring transactions: %w", err)
+	}
+	return nil
+}
+
+// GetCategoryRule retrieves a user's learned category rule for a merchant
+func (r *CardRepository) GetCategoryRule(ctx context.Context, userID uuid.UUID, merchantKey string) (*entities.CardCategoryRule, error) {
+	var rule entities.CardCategoryRule
+	query := `SELECT * FROM card_category_rules WHERE user_id = $1 AND merchant_key = $2`
+	err := r.db.GetContext(ctx, &rule, query, userID, merchantKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// UpsertCategoryRule creates or replaces a user's category rule for a merchant
+func (r *CardRepository) UpsertCategoryRule(ctx context.Context, rule *entities.CardCategoryRule) error {
+	query := `
+		INSERT INTO card_category_rules (id, user_id, merchant_key, category, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, merchant_key) DO UPDATE SET
+			category = EXCLUDED.category,
+			updated_at = EXCLUDED.updated_at`
+
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		rule.ID, rule.UserID, rule.MerchantKey, rule.Category, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert category rule: %w", err)
+	}
+	return nil
+}
+
+// GetCardSpendSince totals the purchases on a card since the given time that count toward its
+// spending limits, see entities.CardSpend
+func (r *CardRepository) GetCardSpendSince(ctx context.Context, cardID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var txs []*entities.BridgeCardTransaction
+	query := `
+		SELECT * FROM card_transactions
+		WHERE card_id = $1 AND created_at >= $2 AND amount > 0
+		  AND type IN ('authorization', 'capture') AND status IN ('pending', 'completed')`
+	err := r.db.SelectContext(ctx, &txs, query, cardID, since)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get card spend: %w", err)
+	}
+	return entities.CardSpend(txs, time.Now().UTC()), nil
+}
+
 // CountByUserID counts cards for a user
 func (r *CardRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
 	var count int
@@ -198,3 +410,245 @@ func (r *CardRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (i
 	}
 	return count, nil
 }
+
+// CreateDispute creates a dispute and its initial evidence atomically
+func (r *CardRepository) CreateDispute(ctx context.Context, dispute *entities.CardDispute, evidence []*entities.CardDisputeEvidence) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO card_disputes (
+			id, user_id, card_id, transaction_id, reason, description, amount,
+			currency, status, provisional_credit_status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`
+
+	if dispute.ID == uuid.Nil {
+		dispute.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	dispute.CreatedAt = now
+	dispute.UpdatedAt = now
+
+	_, err = tx.ExecContext(ctx, query,
+		dispute.ID, dispute.UserID, dispute.CardID, dispute.TransactionID,
+		dispute.Reason, dispute.Description, dispute.Amount, dispute.Currency,
+		dispute.Status, dispute.ProvisionalCreditStatus, dispute.CreatedAt, dispute.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create card dispute: %w", err)
+	}
+
+	for _, e := range evidence {
+		e.DisputeID = dispute.ID
+		if err := insertDisputeEvidence(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit card dispute: %w", err)
+	}
+	return nil
+}
+
+// AddDisputeEvidence attaches evidence to an existing dispute
+func (r *CardRepository) AddDisputeEvidence(ctx context.Context, evidence []*entities.CardDisputeEvidence) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, e := range evidence {
+		if err := insertDisputeEvidence(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dispute evidence: %w", err)
+	}
+	return nil
+}
+
+func insertDisputeEvidence(ctx context.Context, tx *sqlx.Tx, e *entities.CardDisputeEvidence) error {
+	query := `
+		INSERT INTO card_dispute_evidence (id, dispute_id, kind, description, url, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	e.CreatedAt = time.Now().UTC()
+
+	_, err := tx.ExecContext(ctx, query, e.ID, e.DisputeID, e.Kind, e.Description, e.URL, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute evidence: %w", err)
+	}
+	return nil
+}
+
+// GetDisputeByID retrieves a dispute by ID
+func (r *CardRepository) GetDisputeByID(ctx context.Context, id uuid.UUID) (*entities.CardDispute, error) {
+	var dispute entities.CardDispute
+	query := `SELECT * FROM card_disputes WHERE id = $1`
+	err := r.db.GetContext(ctx, &dispute, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card dispute: %w", err)
+	}
+	return &dispute, nil
+}
+
+// GetDisputeByTransactionID retrieves the most recent dispute for a transaction, open or resolved, if any
+func (r *CardRepository) GetDisputeByTransactionID(ctx context.Context, transactionID uuid.UUID) (*entities.CardDispute, error) {
+	var dispute entities.CardDispute
+	query := `SELECT * FROM card_disputes WHERE transaction_id = $1 ORDER BY created_at DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &dispute, query, transactionID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card dispute for transaction: %w", err)
+	}
+	return &dispute, nil
+}
+
+// GetDisputesByUserID retrieves disputes for a user
+func (r *CardRepository) GetDisputesByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CardDispute, error) {
+	var disputes []*entities.CardDispute
+	query := `SELECT * FROM card_disputes WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &disputes, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user card disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+// GetDisputesByStatus retrieves disputes in a given status, oldest first
+func (r *CardRepository) GetDisputesByStatus(ctx context.Context, status entities.CardDisputeStatus, limit, offset int) ([]*entities.CardDispute, error) {
+	var disputes []*entities.CardDispute
+	query := `SELECT * FROM card_disputes WHERE status = $1 ORDER BY created_at ASC LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &disputes, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card disputes by status: %w", err)
+	}
+	return disputes, nil
+}
+
+// GetDisputeEvidence retrieves the evidence attached to a dispute
+func (r *CardRepository) GetDisputeEvidence(ctx context.Context, disputeID uuid.UUID) ([]*entities.CardDisputeEvidence, error) {
+	var evidence []*entities.CardDisputeEvidence
+	query := `SELECT * FROM card_dispute_evidence WHERE dispute_id = $1 ORDER BY created_at ASC`
+	err := r.db.SelectContext(ctx, &evidence, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute evidence: %w", err)
+	}
+	return evidence, nil
+}
+
+// UpdateDispute persists dispute status, provisional credit and resolution fields
+func (r *CardRepository) UpdateDispute(ctx context.Context, dispute *entities.CardDispute) error {
+	query := `
+		UPDATE card_disputes SET
+			status = $1, provisional_credit_status = $2, provisional_credit_tx_id = $3,
+			clawback_tx_id = $4, resolution_note = $5, resolved_by = $6,
+			resolved_at = $7, updated_at = $8
+		WHERE id = $9`
+
+	dispute.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, query,
+		dispute.Status, dispute.ProvisionalCreditStatus, dispute.ProvisionalCreditTxID,
+		dispute.ClawbackTxID, dispute.ResolutionNote, dispute.ResolvedBy,
+		dispute.ResolvedAt, dispute.UpdatedAt, dispute.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update card dispute: %w", err)
+	}
+	return nil
+}
+
+// GetCardControls retrieves the controls configured for a card; nil if never customized
+func (r *CardRepository) GetCardControls(ctx context.Context, cardID uuid.UUID) (*entities.CardControls, error) {
+	query := `
+		SELECT card_id, per_transaction_limit, daily_limit, monthly_limit, allowed_mccs,
+			blocked_mccs, merchant_locked, locked_merchant, single_use, online_enabled,
+			atm_enabled, international_enabled, created_at, updated_at
+		FROM card_controls WHERE card_id = $1`
+
+	var controls entities.CardControls
+	var allowed, blocked pq.StringArray
+	err := r.db.QueryRowContext(ctx, query, cardID).Scan(
+		&controls.CardID, &controls.PerTransactionLimit, &controls.DailyLimit, &controls.MonthlyLimit,
+		&allowed, &blocked, &controls.MerchantLocked, &controls.LockedMerchant, &controls.SingleUse,
+		&controls.OnlineEnabled, &controls.ATMEnabled, &controls.InternationalEnabled,
+		&controls.CreatedAt, &controls.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card controls: %w", err)
+	}
+	controls.AllowedMCCs = []string(allowed)
+	controls.BlockedMCCs = []string(blocked)
+	return &controls, nil
+}
+
+// UpsertCardControls creates or replaces the controls for a card
+func (r *CardRepository) UpsertCardControls(ctx context.Context, controls *entities.CardControls) error {
+	query := `
+		INSERT INTO card_controls (
+			card_id, per_transaction_limit, daily_limit, monthly_limit, allowed_mccs,
+			blocked_mccs, merchant_locked, locked_merchant, single_use, online_enabled,
+			atm_enabled, international_enabled, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13
+		)
+		ON CONFLICT (card_id) DO UPDATE SET
+			per_transaction_limit = EXCLUDED.per_transaction_limit,
+			daily_limit = EXCLUDED.daily_limit,
+			monthly_limit = EXCLUDED.monthly_limit,
+			allowed_mccs = EXCLUDED.allowed_mccs,
+			blocked_mccs = EXCLUDED.blocked_mccs,
+			merchant_locked = EXCLUDED.merchant_locked,
+			locked_merchant = EXCLUDED.locked_merchant,
+			single_use = EXCLUDED.single_use,
+			online_enabled = EXCLUDED.online_enabled,
+			atm_enabled = EXCLUDED.atm_enabled,
+			international_enabled = EXCLUDED.international_enabled,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at`
+
+	now := time.Now().UTC()
+	controls.UpdatedAt = now
+	err := r.db.QueryRowContext(ctx, query,
+		controls.CardID, controls.PerTransactionLimit, controls.DailyLimit, controls.MonthlyLimit,
+		pq.Array(controls.AllowedMCCs), pq.Array(controls.BlockedMCCs), controls.MerchantLocked,
+		controls.LockedMerchant, controls.SingleUse, controls.OnlineEnabled, controls.ATMEnabled,
+		controls.InternationalEnabled, now,
+	).Scan(&controls.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert card controls: %w", err)
+	}
+	return nil
+}
+
+// LockCardMerchant records the merchant a merchant-locked card is bound to, if not already set
+func (r *CardRepository) LockCardMerchant(ctx context.Context, cardID uuid.UUID, merchant string) error {
+	query := `
+		UPDATE card_controls SET locked_merchant = $1, updated_at = $2
+		WHERE card_id = $3 AND merchant_locked AND locked_merchant IS NULL`
+	_, err := r.db.ExecContext(ctx, query, merchant, time.Now().UTC(), cardID)
+	if err != nil {
+		return fmt.Errorf("failed to lock card merchant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Block Order Operations ===
+
+// CreateBlockOrder records a block order before it is sent to the brokerage
+func (r *CopyTradingRepository) CreateBlockOrder(ctx context.Context, order *entities.BlockOrder) error {
+	query := `
+		INSERT INTO copy_trading_block_orders (id, signal_id, asset_ticker, side, requested_quantity,
+		                                       filled_quantity, average_price, draft_count, order_id,
+		                                       status, error_message, created_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		order.ID, order.SignalID, order.AssetTicker, order.Side, order.RequestedQuantity,
+		order.FilledQuantity, order.AveragePrice, order.DraftCount, order.OrderID,
+		order.Status, order.ErrorMessage, order.CreatedAt, order.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create block order: %w", err)
+	}
+	return nil
+}
+
+// UpdateBlockOrder records a block order's fill and final status
+func (r *CopyTradingRepository) UpdateBlockOrder(ctx context.Context, order *entities.BlockOrder) error {
+	query := `
+		UPDATE copy_trading_block_orders
+		SET filled_quantity = $1, average_price = $2, order_id = $3, status = $4,
+		    error_message = $5, completed_at = $6
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		order.FilledQuantity, order.AveragePrice, order.OrderID, order.Status,
+		order.ErrorMessage, order.CompletedAt, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update block order: %w", err)
+	}
+	return nil
+}
+
+// GetBlockOrdersBySignal returns the block orders placed for a signal, oldest first
+func (r *CopyTradingRepository) GetBlockOrdersBySignal(ctx context.Context, signalID uuid.UUID) ([]*entities.BlockOrder, error) {
+	query := `
+		SELECT id, signal_id, asset_ticker, side, requested_quantity, filled_quantity, average_price,
+		       draft_count, COALESCE(order_id, '') AS order_id, status,
+		       COALESCE(error_message, '') AS error_message, created_at, completed_at
+		FROM copy_trading_block_orders
+		WHERE signal_id = $1
+		ORDER BY created_at
+	`
+	var orders []*entities.BlockOrder
+	if err := r.db.SelectContext(ctx, &orders, query, signalID); err != nil {
+		return nil, fmt.Errorf("failed to get block orders: %w", err)
+	}
+	return orders, nil
+}
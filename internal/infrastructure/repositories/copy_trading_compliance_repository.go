@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Compliance Operations ===
+
+// CreateComplianceEvent records a blocked or flagged conductor trade
+func (r *CopyTradingRepository) CreateComplianceEvent(ctx context.Context, event *entities.ConductorComplianceEvent) error {
+	query := `
+		INSERT INTO conductor_compliance_events (id, conductor_id, signal_id, event_type, asset_ticker, side,
+		                                         trade_value, blocked, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID, event.ConductorID, event.SignalID, event.EventType, event.AssetTicker, event.Side,
+		event.TradeValue, event.Blocked, event.Details, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create compliance event: %w", err)
+	}
+	return nil
+}
+
+// GetComplianceEventsByConductor returns a conductor's compliance events, newest first
+func (r *CopyTradingRepository) GetComplianceEventsByConductor(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorComplianceEvent, error) {
+	query := `
+		SELECT id, conductor_id, signal_id, event_type, asset_ticker, side, trade_value, blocked, details, created_at
+		FROM conductor_compliance_events
+		WHERE conductor_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	var events []*entities.ConductorComplianceEvent
+	if err := r.db.SelectContext(ctx, &events, query, conductorID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get compliance events: %w", err)
+	}
+	return events, nil
+}
+
+// GetComplianceEventCounts counts compliance events since a time by conductor, type and whether
+// the trade was blocked
+func (r *CopyTradingRepository) GetComplianceEventCounts(ctx context.Context, since time.Time) ([]*entities.ComplianceEventCount, error) {
+	query := `
+		SELECT conductor_id, event_type, blocked, COUNT(*) AS count, MAX(created_at) AS last_event_at
+		FROM conductor_compliance_events
+		WHERE created_at >= $1
+		GROUP BY conductor_id, event_type, blocked
+	`
+	var counts []*entities.ComplianceEventCount
+	if err := r.db.SelectContext(ctx, &counts, query, since); err != nil {
+		return nil, fmt.Errorf("failed to count compliance events: %w", err)
+	}
+	return counts, nil
+}
+
+// CountConductorTradeSignalsSince counts the buy and sell signals a conductor has generated since a time
+func (r *CopyTradingRepository) CountConductorTradeSignalsSince(ctx context.Context, conductorID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM signals
+		WHERE conductor_id = $1 AND signal_type IN ('BUY', 'SELL') AND created_at >= $2
+	`
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, conductorID, since); err != nil {
+		return 0, fmt.Errorf("failed to count signals: %w", err)
+	}
+	return count, nil
+}
+
+// GetFollowerBuysSince totals the executed buys of an asset that followers copied from a conductor since a time
+func (r *CopyTradingRepository) GetFollowerBuysSince(ctx context.Context, conductorID uuid.UUID, ticker string, since time.Time) (decimal.Decimal, int, error) {
+	query := `
+		SELECT COALESCE(SUM(l.executed_value), 0) AS value, COUNT(*) AS count
+		FROM signal_execution_logs l
+		JOIN signals s ON s.id = l.signal_id
+		WHERE s.conductor_id = $1 AND l.asset_ticker = $2 AND l.side = 'buy'
+		  AND l.status IN ('success', 'partial') AND l.created_at >= $3
+	`
+	var result struct {
+		Value decimal.Decimal `db:"value"`
+		Count int             `db:"count"`
+	}
+	if err := r.db.GetContext(ctx, &result, query, conductorID, ticker, since); err != nil {
+		return decimal.Zero, 0, fmt.Errorf("failed to get follower buys: %w", err)
+	}
+	return result.Value, result.Count, nil
+}
+
+// UpdateConductorStatus sets a conductor's status
+func (r *CopyTradingRepository) UpdateConductorStatus(ctx context.Context, conductorID uuid.UUID, status entities.ConductorStatus) error {
+	query := `UPDATE conductors SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, status, conductorID)
+	return err
+}
+
+// === Blackout Window Operations ===
+
+// CreateBlackoutWindow schedules a blackout window
+func (r *CopyTradingRepository) CreateBlackoutWindow(ctx context.Context, window *entities.SignalBlackoutWindow) error {
+	query := `
+		INSERT INTO signal_blackout_windows (id, conductor_id, asset_ticker, starts_at, ends_at, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		window.ID, window.ConductorID, window.AssetTicker, window.StartsAt, window.EndsAt,
+		window.Reason, window.CreatedBy, window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create blackout window: %w", err)
+	}
+	return nil
+}
+
+// GetBlackoutWindows returns blackout windows that end after a time, soonest first
+func (r *CopyTradingRepository) GetBlackoutWindows(ctx context.Context, endingAfter time.Time) ([]*entities.SignalBlackoutWindow, error) {
+	query := `
+		SELECT id, conductor_id, asset_ticker, starts_at, ends_at, reason, created_by, created_at
+		FROM signal_blackout_windows
+		WHERE ends_at > $1
+		ORDER BY starts_at
+	`
+	var windows []*entities.SignalBlackoutWindow
+	if err := r.db.SelectContext(ctx, &windows, query, endingAfter); err != nil {
+		return nil, fmt.Errorf("failed to get blackout windows: %w", err)
+	}
+	return windows, nil
+}
+
+// GetActiveBlackoutWindows returns the windows covering a conductor's trade of an asset at a time
+func (r *CopyTradingRepository) GetActiveBlackoutWindows(ctx context.Context, conductorID uuid.UUID, ticker string, at time.Time) ([]*entities.SignalBlackoutWindow, error) {
+	query := `
+		SELECT id, conductor_id, asset_ticker, starts_at, ends_at, reason, created_by, created_at
+		FROM signal_blackout_windows
+		WHERE starts_at <= $3 AND ends_at > $3
+		  AND (conductor_id IS NULL OR conductor_id = $1)
+		  AND (asset_ticker = '' OR asset_ticker = $2)
+		ORDER BY ends_at DESC
+	`
+	var windows []*entities.SignalBlackoutWindow
+	if err := r.db.SelectContext(ctx, &windows, query, conductorID, ticker, at); err != nil {
+		return nil, fmt.Errorf("failed to get active blackout windows: %w", err)
+	}
+	return windows, nil
+}
+
+// DeleteBlackoutWindow removes a blackout window, reporting whether it existed
+func (r *CopyTradingRepository) DeleteBlackoutWindow(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM signal_blackout_windows WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete blackout window: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete blackout window: %w", err)
+	}
+	return rows > 0, nil
+}
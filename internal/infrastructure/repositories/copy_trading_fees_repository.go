@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Fee Operations ===
+
+// GetDraftsForFeeProcessing returns active and paused drafts, oldest crystallization first
+func (r *CopyTradingRepository) GetDraftsForFeeProcessing(ctx context.Context, limit int) ([]*entities.Draft, error) {
+	query := `
+		SELECT id, drafter_id, conductor_id, status, allocated_capital, current_aum,
+		       start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
+		       high_water_mark, accrued_fees, last_fee_accrual_at, last_crystallized_at,
+		       mirror_mode, track_id, peak_value, risk_breach_reason, risk_max_drawdown, risk_trailing_stop,
+		       risk_max_concentration, risk_max_trade_value, risk_breach_action,
+		       created_at, updated_at, paused_at, unlinked_at
+		FROM drafts
+		WHERE status IN ('active', 'paused')
+		ORDER BY COALESCE(last_crystallized_at, created_at)
+		LIMIT $1
+	`
+	var drafts []*entities.Draft
+	if err := r.db.SelectContext(ctx, &drafts, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get drafts for fee processing: %w", err)
+	}
+	return drafts, nil
+}
+
+// GetDraftHoldings returns the net quantity per ticker a draft holds from its successful copy and catch-up trades
+func (r *CopyTradingRepository) GetDraftHoldings(ctx context.Context, draftID uuid.UUID) (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT asset_ticker,
+		       SUM(CASE WHEN side = 'buy' THEN executed_quantity ELSE -executed_quantity END) AS quantity
+		FROM signal_execution_logs
+		WHERE draft_id = $1 AND status IN ('success', 'partial')
+		GROUP BY asset_ticker
+	`
+	var rows []struct {
+		Ticker   string          `db:"asset_ticker"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, draftID); err != nil {
+		return nil, fmt.Errorf("failed to get draft holdings: %w", err)
+	}
+
+	holdings := make(map[string]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		if row.Quantity.IsPositive() {
+			holdings[row.Ticker] = row.Quantity
+		}
+	}
+	return holdings, nil
+}
+
+// UpdateDraftFeeAccrual records management fees accrued on a draft up to the given time
+func (r *CopyTradingRepository) UpdateDraftFeeAccrual(ctx context.Context, draftID uuid.UUID, accruedFees decimal.Decimal, accruedAt time.Time) error {
+	query := `UPDATE drafts SET accrued_fees = $1, last_fee_accrual_at = $2, updated_at = NOW() WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, accruedFees, accruedAt, draftID)
+	return err
+}
+
+// RecordFeeCrystallization stores a fee statement and applies it to the draft in one transaction.
+// The charged amount leaves the draft's cash, any uncharged remainder stays accrued.
+func (r *CopyTradingRepository) RecordFeeCrystallization(ctx context.Context, statement *entities.DraftFeeStatement) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO draft_fee_statements (id, draft_id, drafter_id, conductor_id, period_start, period_end,
+		                                  draft_value, opening_high_water_mark, closing_high_water_mark,
+		                                  performance_fee_rate, management_fee, performance_fee, total_fee,
+		                                  amount_charged, ledger_transaction_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`,
+		statement.ID, statement.DraftID, statement.DrafterID, statement.ConductorID, statement.PeriodStart,
+		statement.PeriodEnd, statement.DraftValue, statement.OpeningHighWater, statement.ClosingHighWater,
+		statement.PerformanceFeeRate, statement.ManagementFee, statement.PerformanceFee, statement.TotalFee,
+		statement.AmountCharged, statement.LedgerTxID, statement.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fee statement: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE drafts
+		SET current_aum = current_aum - $1,
+		    total_fees_paid = total_fees_paid + $1,
+		    accrued_fees = $2,
+		    high_water_mark = $3,
+		    last_fee_accrual_at = $4,
+		    last_crystallized_at = $4,
+		    updated_at = NOW()
+		WHERE id = $5
+	`,
+		statement.AmountCharged, statement.TotalFee.Sub(statement.AmountCharged), statement.ClosingHighWater,
+		statement.PeriodEnd, statement.DraftID)
+	if err != nil {
+		return fmt.Errorf("failed to apply fee statement: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetFeeStatementsByDraft returns a draft's fee statements, newest first
+func (r *CopyTradingRepository) GetFeeStatementsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.DraftFeeStatement, error) {
+	query := `
+		SELECT id, draft_id, drafter_id, conductor_id, period_start, period_end, draft_value,
+		       opening_high_water_mark, closing_high_water_mark, performance_fee_rate, management_fee,
+		       performance_fee, total_fee, amount_charged, ledger_transaction_id, created_at
+		FROM draft_fee_statements
+		WHERE draft_id = $1
+		ORDER BY period_end DESC
+		LIMIT $2
+	`
+	var statements []*entities.DraftFeeStatement
+	if err := r.db.SelectContext(ctx, &statements, query, draftID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get fee statements: %w", err)
+	}
+	return statements, nil
+}
+
+// UpdateConductorFees updates a conductor's performance and management fee rates
+func (r *CopyTradingRepository) UpdateConductorFees(ctx context.Context, conductorID uuid.UUID, feeRate, managementFeeRate decimal.Decimal) error {
+	query := `UPDATE conductors SET fee_rate = $1, management_fee_rate = $2, updated_at = NOW() WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, feeRate, managementFeeRate, conductorID)
+	return err
+}
+
+// GetConductorsWithUnpaidFees returns conductors whose charged fees exceed their payouts
+func (r *CopyTradingRepository) GetConductorsWithUnpaidFees(ctx context.Context) ([]*entities.Conductor, error) {
+	query := `
+		SELECT c.id, c.user_id, c.display_name, c.status, c.fee_rate, c.management_fee_rate
+		FROM conductors c
+		WHERE (SELECT COALESCE(SUM(amount_charged), 0) FROM draft_fee_statements WHERE conductor_id = c.id)
+		    > (SELECT COALESCE(SUM(amount), 0) FROM conductor_payouts WHERE conductor_id = c.id)
+	`
+	var conductors []*entities.Conductor
+	if err := r.db.SelectContext(ctx, &conductors, query); err != nil {
+		return nil, fmt.Errorf("failed to get conductors with unpaid fees: %w", err)
+	}
+	return conductors, nil
+}
+
+// GetLatestChargedFeeStatement returns the most recent fee statement that charged a conductor's
+// drafters anything, or nil if none has
+func (r *CopyTradingRepository) GetLatestChargedFeeStatement(ctx context.Context, conductorID uuid.UUID) (*entities.DraftFeeStatement, error) {
+	query := `
+		SELECT id, draft_id, drafter_id, conductor_id, period_start, period_end, draft_value,
+		       opening_high_water_mark, closing_high_water_mark, performance_fee_rate, management_fee,
+		       performance_fee, total_fee, amount_charged, ledger_transaction_id, created_at
+		FROM draft_fee_statements
+		WHERE conductor_id = $1 AND amount_charged > 0
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	var statement entities.DraftFeeStatement
+	err := r.db.GetContext(ctx, &statement, query, conductorID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest fee statement: %w", err)
+	}
+	return &statement, nil
+}
+
+// CreateConductorPayout records a payout to a conductor. A retried payout carries the same ID and
+// is recorded once.
+func (r *CopyTradingRepository) CreateConductorPayout(ctx context.Context, payout *entities.ConductorPayout) error {
+	query := `
+		INSERT INTO conductor_payouts (id, conductor_id, amount, ledger_transaction_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, payout.ID, payout.ConductorID, payout.Amount, payout.LedgerTxID, payout.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create conductor payout: %w", err)
+	}
+	return nil
+}
+
+// GetConductorPayouts returns a conductor's payouts, newest first
+func (r *CopyTradingRepository) GetConductorPayouts(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorPayout, error) {
+	query := `
+		SELECT id, conductor_id, amount, ledger_transaction_id, created_at
+		FROM conductor_payouts
+		WHERE conductor_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	var payouts []*entities.ConductorPayout
+	if err := r.db.SelectContext(ctx, &payouts, query, conductorID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get conductor payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+// GetConductorFeeTotals returns the fees charged for a conductor and the amount paid out to them
+func (r *CopyTradingRepository) GetConductorFeeTotals(ctx context.Context, conductorID uuid.UUID) (earned, paidOut decimal.Decimal, err error) {
+	query := `
+		SELECT (SELECT COALESCE(SUM(amount_charged), 0) FROM draft_fee_statements WHERE conductor_id = $1) AS earned,
+		       (SELECT COALESCE(SUM(amount), 0) FROM conductor_payouts WHERE conductor_id = $1) AS paid_out
+	`
+	var totals struct {
+		Earned  decimal.Decimal `db:"earned"`
+		PaidOut decimal.Decimal `db:"paid_out"`
+	}
+	if err := r.db.GetContext(ctx, &totals, query, conductorID); err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("failed to get conductor fee totals: %w", err)
+	}
+	return totals.Earned, totals.PaidOut, nil
+}
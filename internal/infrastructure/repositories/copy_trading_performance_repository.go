@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Performance Operations ===
+
+// GetConductorSignalHistory returns every signal a conductor has generated, oldest first
+func (r *CopyTradingRepository) GetConductorSignalHistory(ctx context.Context, conductorID uuid.UUID) ([]*entities.Signal, error) {
+	query := `
+		SELECT id, conductor_id, asset_ticker, asset_name, signal_type, side, base_quantity,
+		       base_price, base_value, conductor_aum_at_signal, order_id, status,
+		       processed_count, failed_count, created_at, completed_at, track_id, track_version
+		FROM signals
+		WHERE conductor_id = $1
+		ORDER BY created_at
+	`
+	var signals []*entities.Signal
+	if err := r.db.SelectContext(ctx, &signals, query, conductorID); err != nil {
+		return nil, fmt.Errorf("failed to get conductor signal history: %w", err)
+	}
+	return signals, nil
+}
+
+// UpsertPerformanceSnapshot writes a conductor's daily snapshot, replacing any earlier one for the same date
+func (r *CopyTradingRepository) UpsertPerformanceSnapshot(ctx context.Context, snapshot *entities.ConductorPerformanceHistory) error {
+	query := `
+		INSERT INTO conductor_performance_history (id, conductor_id, snapshot_date, aum, daily_return,
+		                                           cumulative_return, followers_count, trades_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (conductor_id, snapshot_date) DO UPDATE
+		SET aum = EXCLUDED.aum,
+		    daily_return = EXCLUDED.daily_return,
+		    cumulative_return = EXCLUDED.cumulative_return,
+		    followers_count = EXCLUDED.followers_count,
+		    trades_count = EXCLUDED.trades_count
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		snapshot.ID, snapshot.ConductorID, snapshot.SnapshotDate, snapshot.AUM, snapshot.DailyReturn,
+		snapshot.CumulativeReturn, snapshot.FollowersCount, snapshot.TradesCount, snapshot.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert performance snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetPerformanceHistory returns a conductor's daily snapshots on or after the given date, oldest first
+func (r *CopyTradingRepository) GetPerformanceHistory(ctx context.Context, conductorID uuid.UUID, since time.Time) ([]*entities.ConductorPerformanceHistory, error) {
+	query := `
+		SELECT id, conductor_id, snapshot_date, aum, COALESCE(daily_return, 0) AS daily_return,
+		       COALESCE(cumulative_return, 0) AS cumulative_return, COALESCE(followers_count, 0) AS followers_count,
+		       COALESCE(trades_count, 0) AS trades_count, created_at
+		FROM conductor_performance_history
+		WHERE conductor_id = $1 AND snapshot_date >= $2
+		ORDER BY snapshot_date
+	`
+	var history []*entities.ConductorPerformanceHistory
+	if err := r.db.SelectContext(ctx, &history, query, conductorID, since); err != nil {
+		return nil, fmt.Errorf("failed to get performance history: %w", err)
+	}
+	return history, nil
+}
+
+// UpdateConductorPerformance stores a conductor's computed AUM and performance metrics
+func (r *CopyTradingRepository) UpdateConductorPerformance(ctx context.Context, conductor *entities.Conductor) error {
+	query := `
+		UPDATE conductors
+		SET source_aum = $1, total_return = $2, win_rate = $3, max_drawdown = $4,
+		    sharpe_ratio = $5, total_trades = $6, updated_at = NOW()
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		conductor.SourceAUM, conductor.TotalReturn, conductor.WinRate, conductor.MaxDrawdown,
+		conductor.SharpeRatio, conductor.TotalTrades, conductor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update conductor performance: %w", err)
+	}
+	return nil
+}
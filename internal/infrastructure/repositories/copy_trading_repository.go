@@ -34,10 +34,16 @@ func (r *CopyTradingRepository) GetActiveConductors(ctx context.Context, limit,
 		orderClause = "source_aum DESC"
 	case "win_rate":
 		orderClause = "win_rate DESC"
+	case "sharpe":
+		orderClause = "sharpe_ratio DESC"
+	case "drawdown":
+		orderClause = "max_drawdown ASC"
+	case "trades":
+		orderClause = "total_trades DESC"
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, source_aum,
+		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, management_fee_rate, source_aum,
 		       total_return, win_rate, max_drawdown, sharpe_ratio, total_trades,
 		       followers_count, min_draft_amount, is_verified, verified_at, last_trade_at,
 		       created_at, updated_at
@@ -61,10 +67,38 @@ func (r *CopyTradingRepository) GetActiveConductors(ctx context.Context, limit,
 	return conductors, total, nil
 }
 
+// GetActiveConductorsAfter returns a page of active conductors ordered by (followers_count, id)
+// descending, starting after the given conductor's followers count and ID (nil for the first page)
+func (r *CopyTradingRepository) GetActiveConductorsAfter(ctx context.Context, after *entities.Conductor, limit int) ([]*entities.Conductor, error) {
+	whereClause := "status = 'active'"
+	args := []interface{}{limit}
+	if after != nil {
+		whereClause += " AND (followers_count, id) < ($2, $3)"
+		args = append(args, after.FollowersCount, after.ID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, management_fee_rate, source_aum,
+		       total_return, win_rate, max_drawdown, sharpe_ratio, total_trades,
+		       followers_count, min_draft_amount, is_verified, verified_at, last_trade_at,
+		       created_at, updated_at
+		FROM conductors
+		WHERE %s
+		ORDER BY followers_count DESC, id DESC
+		LIMIT $1
+	`, whereClause)
+
+	var conductors []*entities.Conductor
+	if err := r.db.SelectContext(ctx, &conductors, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get conductors: %w", err)
+	}
+	return conductors, nil
+}
+
 // GetConductorByID returns a conductor by ID
 func (r *CopyTradingRepository) GetConductorByID(ctx context.Context, id uuid.UUID) (*entities.Conductor, error) {
 	query := `
-		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, source_aum,
+		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, management_fee_rate, source_aum,
 		       total_return, win_rate, max_drawdown, sharpe_ratio, total_trades,
 		       followers_count, min_draft_amount, is_verified, verified_at, last_trade_at,
 		       created_at, updated_at
@@ -83,7 +117,7 @@ func (r *CopyTradingRepository) GetConductorByID(ctx context.Context, id uuid.UU
 // GetConductorByUserID returns a conductor by user ID
 func (r *CopyTradingRepository) GetConductorByUserID(ctx context.Context, userID uuid.UUID) (*entities.Conductor, error) {
 	query := `
-		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, source_aum,
+		SELECT id, user_id, display_name, bio, avatar_url, status, fee_rate, management_fee_rate, source_aum,
 		       total_return, win_rate, max_drawdown, sharpe_ratio, total_trades,
 		       followers_count, min_draft_amount, is_verified, verified_at, last_trade_at,
 		       created_at, updated_at
@@ -120,13 +154,17 @@ func (r *CopyTradingRepository) CreateDraft(ctx context.Context, draft *entities
 	query := `
 		INSERT INTO drafts (id, drafter_id, conductor_id, status, allocated_capital, current_aum,
 		                    start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
-		                    created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		                    high_water_mark, mirror_mode, track_id, peak_value, risk_max_drawdown,
+		                    risk_trailing_stop, risk_max_concentration, risk_max_trade_value,
+		                    risk_breach_action, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		draft.ID, draft.DrafterID, draft.ConductorID, draft.Status, draft.AllocatedCapital,
 		draft.CurrentAUM, draft.StartValue, draft.TotalProfitLoss, draft.TotalFeesPaid,
-		draft.CopyRatio, draft.AutoAdjust, draft.CreatedAt, draft.UpdatedAt)
+		draft.CopyRatio, draft.AutoAdjust, draft.HighWaterMark, draft.MirrorMode, draft.TrackID,
+		draft.PeakValue, draft.MaxDrawdown, draft.TrailingStop, draft.MaxConcentration, draft.MaxTradeValue,
+		draft.BreachAction, draft.CreatedAt, draft.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create draft: %w", err)
 	}
@@ -138,6 +176,9 @@ func (r *CopyTradingRepository) GetDraftByID(ctx context.Context, id uuid.UUID)
 	query := `
 		SELECT id, drafter_id, conductor_id, status, allocated_capital, current_aum,
 		       start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
+		       high_water_mark, accrued_fees, last_fee_accrual_at, last_crystallized_at,
+		       mirror_mode, track_id, peak_value, risk_breach_reason, risk_max_drawdown, risk_trailing_stop,
+		       risk_max_concentration, risk_max_trade_value, risk_breach_action,
 		       created_at, updated_at, paused_at, unlinked_at
 		FROM drafts WHERE id = $1
 	`
@@ -156,6 +197,9 @@ func (r *CopyTradingRepository) GetDraftsByDrafterID(ctx context.Context, drafte
 	query := `
 		SELECT d.id, d.drafter_id, d.conductor_id, d.status, d.allocated_capital, d.current_aum,
 		       d.start_value, d.total_profit_loss, d.total_fees_paid, d.copy_ratio, d.auto_adjust,
+		       d.high_water_mark, d.accrued_fees, d.last_fee_accrual_at, d.last_crystallized_at,
+		       d.mirror_mode, d.track_id, d.peak_value, d.risk_breach_reason, d.risk_max_drawdown,
+		       d.risk_trailing_stop, d.risk_max_concentration, d.risk_max_trade_value, d.risk_breach_action,
 		       d.created_at, d.updated_at, d.paused_at, d.unlinked_at
 		FROM drafts d
 		WHERE d.drafter_id = $1
@@ -173,6 +217,9 @@ func (r *CopyTradingRepository) GetActiveDraftsByConductorID(ctx context.Context
 	query := `
 		SELECT id, drafter_id, conductor_id, status, allocated_capital, current_aum,
 		       start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
+		       high_water_mark, accrued_fees, last_fee_accrual_at, last_crystallized_at,
+		       mirror_mode, track_id, peak_value, risk_breach_reason, risk_max_drawdown, risk_trailing_stop,
+		       risk_max_concentration, risk_max_trade_value, risk_breach_action,
 		       created_at, updated_at, paused_at, unlinked_at
 		FROM drafts
 		WHERE conductor_id = $1 AND status = 'active'
@@ -189,6 +236,9 @@ func (r *CopyTradingRepository) GetExistingDraft(ctx context.Context, drafterID,
 	query := `
 		SELECT id, drafter_id, conductor_id, status, allocated_capital, current_aum,
 		       start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
+		       high_water_mark, accrued_fees, last_fee_accrual_at, last_crystallized_at,
+		       mirror_mode, track_id, peak_value, risk_breach_reason, risk_max_drawdown, risk_trailing_stop,
+		       risk_max_concentration, risk_max_trade_value, risk_breach_action,
 		       created_at, updated_at, paused_at, unlinked_at
 		FROM drafts
 		WHERE drafter_id = $1 AND conductor_id = $2 AND status NOT IN ('unlinked')
@@ -219,9 +269,19 @@ func (r *CopyTradingRepository) UpdateDraftStatus(ctx context.Context, draftID u
 	return err
 }
 
-// UpdateDraftCapital updates the allocated capital of a draft
+// UpdateDraftCapital updates the allocated capital of a draft. The capital change is added to
+// the draft's cash, start value, peak and high-water mark so deposits and withdrawals are neither
+// charged as performance nor counted towards its risk limits.
 func (r *CopyTradingRepository) UpdateDraftCapital(ctx context.Context, draftID uuid.UUID, newCapital decimal.Decimal) error {
-	query := `UPDATE drafts SET allocated_capital = $1, updated_at = NOW() WHERE id = $2`
+	query := `
+		UPDATE drafts
+		SET current_aum = current_aum + ($1 - allocated_capital),
+		    high_water_mark = GREATEST(high_water_mark + ($1 - allocated_capital), 0),
+		    start_value = GREATEST(start_value + ($1 - allocated_capital), 0),
+		    peak_value = GREATEST(peak_value + ($1 - allocated_capital), 0),
+		    allocated_capital = $1, updated_at = NOW()
+		WHERE id = $2
+	`
 	_, err := r.db.ExecContext(ctx, query, newCapital, draftID)
 	return err
 }
@@ -240,14 +300,14 @@ func (r *CopyTradingRepository) CreateSignal(ctx context.Context, signal *entiti
 	query := `
 		INSERT INTO signals (id, conductor_id, asset_ticker, asset_name, signal_type, side,
 		                     base_quantity, base_price, base_value, conductor_aum_at_signal,
-		                     order_id, status, processed_count, failed_count, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		                     order_id, status, processed_count, failed_count, created_at, track_id, track_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		signal.ID, signal.ConductorID, signal.AssetTicker, signal.AssetName, signal.SignalType,
 		signal.Side, signal.BaseQuantity, signal.BasePrice, signal.BaseValue,
 		signal.ConductorAUMAtSignal, signal.OrderID, signal.Status, signal.ProcessedCount,
-		signal.FailedCount, signal.CreatedAt)
+		signal.FailedCount, signal.CreatedAt, signal.TrackID, signal.TrackVersion)
 	return err
 }
 
@@ -256,7 +316,7 @@ func (r *CopyTradingRepository) GetSignalByID(ctx context.Context, id uuid.UUID)
 	query := `
 		SELECT id, conductor_id, asset_ticker, asset_name, signal_type, side, base_quantity,
 		       base_price, base_value, conductor_aum_at_signal, order_id, status,
-		       processed_count, failed_count, created_at, completed_at
+		       processed_count, failed_count, created_at, completed_at, track_id, track_version
 		FROM signals WHERE id = $1
 	`
 	var signal entities.Signal
@@ -274,7 +334,7 @@ func (r *CopyTradingRepository) GetPendingSignals(ctx context.Context, limit int
 	query := `
 		SELECT id, conductor_id, asset_ticker, asset_name, signal_type, side, base_quantity,
 		       base_price, base_value, conductor_aum_at_signal, order_id, status,
-		       processed_count, failed_count, created_at, completed_at
+		       processed_count, failed_count, created_at, completed_at, track_id, track_version
 		FROM signals
 		WHERE status IN ('pending', 'processing')
 		ORDER BY created_at ASC
@@ -304,7 +364,7 @@ func (r *CopyTradingRepository) GetSignalsByConductor(ctx context.Context, condu
 	query := `
 		SELECT id, conductor_id, asset_ticker, asset_name, signal_type, side, base_quantity,
 		       base_price, base_value, conductor_aum_at_signal, order_id, status,
-		       processed_count, failed_count, created_at, completed_at
+		       processed_count, failed_count, created_at, completed_at, track_id, track_version
 		FROM signals
 		WHERE conductor_id = $1
 		ORDER BY created_at DESC
@@ -317,20 +377,48 @@ func (r *CopyTradingRepository) GetSignalsByConductor(ctx context.Context, condu
 	return signals, nil
 }
 
+// GetConductorHoldings returns the net quantity per ticker a conductor holds according to their signals
+func (r *CopyTradingRepository) GetConductorHoldings(ctx context.Context, conductorID uuid.UUID) (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT asset_ticker,
+		       SUM(CASE WHEN side = 'buy' THEN base_quantity ELSE -base_quantity END) AS quantity
+		FROM signals
+		WHERE conductor_id = $1
+		GROUP BY asset_ticker
+	`
+	var rows []struct {
+		Ticker   string          `db:"asset_ticker"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, conductorID); err != nil {
+		return nil, fmt.Errorf("failed to get conductor holdings: %w", err)
+	}
+
+	holdings := make(map[string]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		if row.Quantity.IsPositive() {
+			holdings[row.Ticker] = row.Quantity
+		}
+	}
+	return holdings, nil
+}
+
 // === Signal Execution Log Operations ===
 
 // CreateExecutionLog creates a new execution log entry
 func (r *CopyTradingRepository) CreateExecutionLog(ctx context.Context, log *entities.SignalExecutionLog) error {
 	query := `
-		INSERT INTO signal_execution_logs (id, draft_id, signal_id, executed_quantity, executed_price,
-		                                   executed_value, status, fee_applied, error_message, order_id,
+		INSERT INTO signal_execution_logs (id, draft_id, signal_id, execution_type, asset_ticker, side,
+		                                   executed_quantity, executed_price, executed_value, status,
+		                                   fee_applied, error_message, order_id, block_order_id,
 		                                   idempotency_key, created_at, executed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		ON CONFLICT (idempotency_key) DO NOTHING
 	`
 	_, err := r.db.ExecContext(ctx, query,
-		log.ID, log.DraftID, log.SignalID, log.ExecutedQuantity, log.ExecutedPrice,
-		log.ExecutedValue, log.Status, log.FeeApplied, log.ErrorMessage, log.OrderID,
+		log.ID, log.DraftID, log.SignalID, log.ExecutionType, log.AssetTicker, log.Side,
+		log.ExecutedQuantity, log.ExecutedPrice, log.ExecutedValue, log.Status,
+		log.FeeApplied, log.ErrorMessage, log.OrderID, log.BlockOrderID,
 		log.IdempotencyKey, log.CreatedAt, log.ExecutedAt)
 	return err
 }
@@ -338,8 +426,9 @@ func (r *CopyTradingRepository) CreateExecutionLog(ctx context.Context, log *ent
 // GetExecutionLogByIdempotencyKey checks if an execution already exists
 func (r *CopyTradingRepository) GetExecutionLogByIdempotencyKey(ctx context.Context, key string) (*entities.SignalExecutionLog, error) {
 	query := `
-		SELECT id, draft_id, signal_id, executed_quantity, executed_price, executed_value,
-		       status, fee_applied, error_message, order_id, idempotency_key, created_at, executed_at
+		SELECT id, draft_id, signal_id, execution_type, asset_ticker, side, executed_quantity,
+		       executed_price, executed_value, status, fee_applied, error_message, order_id,
+		       block_order_id, idempotency_key, created_at, executed_at
 		FROM signal_execution_logs WHERE idempotency_key = $1
 	`
 	var log entities.SignalExecutionLog
@@ -355,8 +444,9 @@ func (r *CopyTradingRepository) GetExecutionLogByIdempotencyKey(ctx context.Cont
 // GetExecutionLogsByDraft returns execution logs for a draft
 func (r *CopyTradingRepository) GetExecutionLogsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.SignalExecutionLog, error) {
 	query := `
-		SELECT id, draft_id, signal_id, executed_quantity, executed_price, executed_value,
-		       status, fee_applied, error_message, order_id, idempotency_key, created_at, executed_at
+		SELECT id, draft_id, signal_id, execution_type, asset_ticker, side, executed_quantity,
+		       executed_price, executed_value, status, fee_applied, error_message, order_id,
+		       block_order_id, idempotency_key, created_at, executed_at
 		FROM signal_execution_logs
 		WHERE draft_id = $1
 		ORDER BY created_at DESC
@@ -458,14 +548,14 @@ func (r *CopyTradingRepository) UpdateApplicationStatus(ctx context.Context, id
 func (r *CopyTradingRepository) CreateConductor(ctx context.Context, conductor *entities.Conductor) error {
 	query := `
 		INSERT INTO conductors (id, user_id, display_name, bio, avatar_url, status, fee_rate,
-		                        source_aum, total_return, win_rate, max_drawdown, sharpe_ratio,
-		                        total_trades, followers_count, min_draft_amount, is_verified,
+		                        management_fee_rate, source_aum, total_return, win_rate, max_drawdown,
+		                        sharpe_ratio, total_trades, followers_count, min_draft_amount, is_verified,
 		                        verified_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		conductor.ID, conductor.UserID, conductor.DisplayName, conductor.Bio, conductor.AvatarURL,
-		conductor.Status, conductor.FeeRate, conductor.SourceAUM, conductor.TotalReturn,
+		conductor.Status, conductor.FeeRate, conductor.ManagementFee, conductor.SourceAUM, conductor.TotalReturn,
 		conductor.WinRate, conductor.MaxDrawdown, conductor.SharpeRatio, conductor.TotalTrades,
 		conductor.FollowersCount, conductor.MinDraftAmount, conductor.IsVerified,
 		conductor.VerifiedAt, conductor.CreatedAt, conductor.UpdatedAt)
@@ -478,12 +568,12 @@ func (r *CopyTradingRepository) CreateConductor(ctx context.Context, conductor *
 func (r *CopyTradingRepository) CreateTrack(ctx context.Context, track *entities.Track) error {
 	query := `
 		INSERT INTO tracks (id, conductor_id, name, description, risk_level, is_active,
-		                    followers_count, total_return, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		                    followers_count, total_return, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		track.ID, track.ConductorID, track.Name, track.Description, track.RiskLevel,
-		track.IsActive, track.FollowersCount, track.TotalReturn, track.CreatedAt, track.UpdatedAt)
+		track.IsActive, track.FollowersCount, track.TotalReturn, track.Version, track.CreatedAt, track.UpdatedAt)
 	return err
 }
 
@@ -491,7 +581,7 @@ func (r *CopyTradingRepository) CreateTrack(ctx context.Context, track *entities
 func (r *CopyTradingRepository) GetTrackByID(ctx context.Context, id uuid.UUID) (*entities.Track, error) {
 	query := `
 		SELECT id, conductor_id, name, description, risk_level, is_active,
-		       followers_count, total_return, created_at, updated_at
+		       followers_count, total_return, version, created_at, updated_at
 		FROM tracks WHERE id = $1
 	`
 	var track entities.Track
@@ -508,7 +598,7 @@ func (r *CopyTradingRepository) GetTrackByID(ctx context.Context, id uuid.UUID)
 func (r *CopyTradingRepository) GetTracksByConductorID(ctx context.Context, conductorID uuid.UUID) ([]*entities.Track, error) {
 	query := `
 		SELECT id, conductor_id, name, description, risk_level, is_active,
-		       followers_count, total_return, created_at, updated_at
+		       followers_count, total_return, version, created_at, updated_at
 		FROM tracks WHERE conductor_id = $1
 		ORDER BY created_at DESC
 	`
@@ -523,7 +613,7 @@ func (r *CopyTradingRepository) GetTracksByConductorID(ctx context.Context, cond
 func (r *CopyTradingRepository) GetActiveTracks(ctx context.Context, limit, offset int) ([]*entities.Track, int, error) {
 	query := `
 		SELECT id, conductor_id, name, description, risk_level, is_active,
-		       followers_count, total_return, created_at, updated_at
+		       followers_count, total_return, version, created_at, updated_at
 		FROM tracks WHERE is_active = TRUE
 		ORDER BY followers_count DESC
 		LIMIT $1 OFFSET $2
@@ -564,12 +654,12 @@ func (r *CopyTradingRepository) DeleteTrack(ctx context.Context, id uuid.UUID) e
 // CreateTrackAllocations creates allocations for a track
 func (r *CopyTradingRepository) CreateTrackAllocations(ctx context.Context, allocations []entities.TrackAllocation) error {
 	query := `
-		INSERT INTO track_allocations (id, track_id, asset_ticker, asset_name, target_weight, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO track_allocations (id, track_id, asset_ticker, asset_name, target_weight, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	for _, alloc := range allocations {
 		_, err := r.db.ExecContext(ctx, query,
-			alloc.ID, alloc.TrackID, alloc.AssetTicker, alloc.AssetName, alloc.TargetWeight, alloc.CreatedAt, alloc.UpdatedAt)
+			alloc.ID, alloc.TrackID, alloc.AssetTicker, alloc.AssetName, alloc.TargetWeight, alloc.Version, alloc.CreatedAt, alloc.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to create allocation: %w", err)
 		}
@@ -577,12 +667,14 @@ func (r *CopyTradingRepository) CreateTrackAllocations(ctx context.Context, allo
 	return nil
 }
 
-// GetTrackAllocations returns allocations for a track
+// GetTrackAllocations returns a track's current allocations
 func (r *CopyTradingRepository) GetTrackAllocations(ctx context.Context, trackID uuid.UUID) ([]entities.TrackAllocation, error) {
 	query := `
-		SELECT id, track_id, asset_ticker, asset_name, target_weight, created_at, updated_at
-		FROM track_allocations WHERE track_id = $1
-		ORDER BY target_weight DESC
+		SELECT a.id, a.track_id, a.asset_ticker, a.asset_name, a.target_weight, a.version, a.created_at, a.updated_at
+		FROM track_allocations a
+		JOIN tracks t ON t.id = a.track_id AND t.version = a.version
+		WHERE a.track_id = $1
+		ORDER BY a.target_weight DESC
 	`
 	var allocations []entities.TrackAllocation
 	if err := r.db.SelectContext(ctx, &allocations, query, trackID); err != nil {
@@ -590,10 +682,3 @@ func (r *CopyTradingRepository) GetTrackAllocations(ctx context.Context, trackID
 	}
 	return allocations, nil
 }
-
-// DeleteTrackAllocations deletes all allocations for a track
-func (r *CopyTradingRepository) DeleteTrackAllocations(ctx context.Context, trackID uuid.UUID) error {
-	query := `DELETE FROM track_allocations WHERE track_id = $1`
-	_, err := r.db.ExecContext(ctx, query, trackID)
-	return err
-}
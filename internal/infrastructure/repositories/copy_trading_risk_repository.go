@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Risk Operations ===
+
+// UpdateDraftRiskSettings replaces a draft's risk limits
+func (r *CopyTradingRepository) UpdateDraftRiskSettings(ctx context.Context, draftID uuid.UUID, settings entities.DraftRiskSettings) error {
+	query := `
+		UPDATE drafts
+		SET risk_max_drawdown = $1, risk_trailing_stop = $2, risk_max_concentration = $3,
+		    risk_max_trade_value = $4, risk_breach_action = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		settings.MaxDrawdown, settings.TrailingStop, settings.MaxConcentration,
+		settings.MaxTradeValue, settings.BreachAction, draftID)
+	if err != nil {
+		return fmt.Errorf("failed to update draft risk settings: %w", err)
+	}
+	return nil
+}
+
+// UpdateDraftRiskState records a draft's peak value and the reason for its last risk breach
+func (r *CopyTradingRepository) UpdateDraftRiskState(ctx context.Context, draftID uuid.UUID, peakValue decimal.Decimal, breachReason string) error {
+	query := `UPDATE drafts SET peak_value = $1, risk_breach_reason = $2, updated_at = NOW() WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, peakValue, breachReason, draftID)
+	return err
+}
+
+// GetDraftsWithStopLimits returns active drafts that have a max drawdown or trailing stop set,
+// paging by ID so drafts paused along the way do not shift later pages
+func (r *CopyTradingRepository) GetDraftsWithStopLimits(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Draft, error) {
+	query := `
+		SELECT id, drafter_id, conductor_id, status, allocated_capital, current_aum,
+		       start_value, total_profit_loss, total_fees_paid, copy_ratio, auto_adjust,
+		       high_water_mark, accrued_fees, last_fee_accrual_at, last_crystallized_at,
+		       mirror_mode, track_id, peak_value, risk_breach_reason, risk_max_drawdown, risk_trailing_stop,
+		       risk_max_concentration, risk_max_trade_value, risk_breach_action,
+		       created_at, updated_at, paused_at, unlinked_at
+		FROM drafts
+		WHERE status = 'active' AND (risk_max_drawdown IS NOT NULL OR risk_trailing_stop IS NOT NULL)
+		  AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	var drafts []*entities.Draft
+	if err := r.db.SelectContext(ctx, &drafts, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get drafts with stop limits: %w", err)
+	}
+	return drafts, nil
+}
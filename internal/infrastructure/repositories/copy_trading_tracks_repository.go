@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// === Track Version Operations ===
+
+// CreateTrackVersion records a new allocation version and makes it the track's current version in
+// one transaction. The version's allocations must already be stored.
+func (r *CopyTradingRepository) CreateTrackVersion(ctx context.Context, version *entities.TrackVersion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO track_versions (id, track_id, version, signal_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, version.ID, version.TrackID, version.Version, version.SignalID, version.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create track version: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tracks SET version = $1, updated_at = NOW() WHERE id = $2`,
+		version.Version, version.TrackID)
+	if err != nil {
+		return fmt.Errorf("failed to update track version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetTrackVersions returns a track's allocation versions, newest first
+func (r *CopyTradingRepository) GetTrackVersions(ctx context.Context, trackID uuid.UUID, limit int) ([]*entities.TrackVersion, error) {
+	query := `
+		SELECT id, track_id, version, signal_id, created_at
+		FROM track_versions
+		WHERE track_id = $1
+		ORDER BY version DESC
+		LIMIT $2
+	`
+	var versions []*entities.TrackVersion
+	if err := r.db.SelectContext(ctx, &versions, query, trackID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get track versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetTrackAllocationsByVersion returns the allocations a track held at a given version
+func (r *CopyTradingRepository) GetTrackAllocationsByVersion(ctx context.Context, trackID uuid.UUID, version int) ([]entities.TrackAllocation, error) {
+	query := `
+		SELECT id, track_id, asset_ticker, asset_name, target_weight, version, created_at, updated_at
+		FROM track_allocations WHERE track_id = $1 AND version = $2
+		ORDER BY target_weight DESC
+	`
+	var allocations []entities.TrackAllocation
+	if err := r.db.SelectContext(ctx, &allocations, query, trackID, version); err != nil {
+		return nil, fmt.Errorf("failed to get allocations: %w", err)
+	}
+	return allocations, nil
+}
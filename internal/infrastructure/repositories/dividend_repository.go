@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// DividendRepository handles dividend and dividend setting database operations
+type DividendRepository struct {
+	db *sqlx.DB
+}
+
+// NewDividendRepository creates a new dividend repository
+func NewDividendRepository(db *sqlx.DB) *DividendRepository {
+	return &DividendRepository{db: db}
+}
+
+const dividendColumns = `
+	id, user_id, activity_id, activity_type, symbol, quantity, per_share_amount, gross_amount,
+	withholding_amount, net_amount, pay_date, ledger_transaction_id, reinvest_status, reinvest_amount,
+	reinvest_orders, reinvest_error, created_at, updated_at`
+
+// GetSettings returns a user's dividend settings, or nil if they have not chosen a mode
+func (r *DividendRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.DividendSettings, error) {
+	var settings entities.DividendSettings
+	query := `SELECT user_id, reinvest_mode, updated_at FROM dividend_settings WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &settings, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dividend settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpsertSettings saves a user's dividend settings
+func (r *DividendRepository) UpsertSettings(ctx context.Context, settings *entities.DividendSettings) error {
+	query := `
+		INSERT INTO dividend_settings (user_id, reinvest_mode, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET reinvest_mode = EXCLUDED.reinvest_mode, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, settings.UserID, settings.ReinvestMode, settings.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save dividend settings: %w", err)
+	}
+	return nil
+}
+
+// GetByActivityID returns the dividend recorded for an Alpaca activity, or nil
+func (r *DividendRepository) GetByActivityID(ctx context.Context, activityID string) (*entities.Dividend, error) {
+	var dividend entities.Dividend
+	query := `SELECT ` + dividendColumns + ` FROM dividends WHERE activity_id = $1`
+	err := r.db.GetContext(ctx, &dividend, query, activityID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dividend: %w", err)
+	}
+	return &dividend, nil
+}
+
+// Create records a dividend activity
+func (r *DividendRepository) Create(ctx context.Context, dividend *entities.Dividend) error {
+	query := `
+		INSERT INTO dividends (` + dividendColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		dividend.ID, dividend.UserID, dividend.ActivityID, dividend.ActivityType, dividend.Symbol, dividend.Quantity,
+		dividend.PerShareAmount, dividend.GrossAmount, dividend.WithholdingAmount, dividend.NetAmount, dividend.PayDate,
+		dividend.LedgerTransactionID, dividend.ReinvestStatus, dividend.ReinvestAmount, dividend.ReinvestOrders,
+		dividend.ReinvestError, dividend.CreatedAt, dividend.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create dividend: %w", err)
+	}
+	return nil
+}
+
+// Update saves a dividend's ledger posting and reinvestment outcome
+func (r *DividendRepository) Update(ctx context.Context, dividend *entities.Dividend) error {
+	query := `
+		UPDATE dividends
+		SET ledger_transaction_id = $2, reinvest_status = $3, reinvest_amount = $4, reinvest_orders = $5,
+			reinvest_error = $6, updated_at = $7
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		dividend.ID, dividend.LedgerTransactionID, dividend.ReinvestStatus, dividend.ReinvestAmount,
+		dividend.ReinvestOrders, dividend.ReinvestError, dividend.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update dividend: %w", err)
+	}
+	return nil
+}
+
+// GetWithholding returns the tax withheld from a user's dividends on one asset on a pay date
+func (r *DividendRepository) GetWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) (decimal.Decimal, error) {
+	var withholding decimal.Decimal
+	query := `
+		SELECT COALESCE(SUM(withholding_amount), 0)
+		FROM dividends
+		WHERE user_id = $1 AND symbol = $2 AND pay_date = $3
+	`
+	if err := r.db.GetContext(ctx, &withholding, query, userID, symbol, payDate); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get dividend withholding: %w", err)
+	}
+	return withholding, nil
+}
+
+// GetAwaitingWithholding returns a user's dividends on one asset and pay date that are held
+// until their withholding is known
+func (r *DividendRepository) GetAwaitingWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) ([]*entities.Dividend, error) {
+	query := `
+		SELECT ` + dividendColumns + `
+		FROM dividends
+		WHERE user_id = $1 AND symbol = $2 AND pay_date = $3 AND reinvest_status = $4
+		ORDER BY created_at
+	`
+	var dividends []*entities.Dividend
+	if err := r.db.SelectContext(ctx, &dividends, query, userID, symbol, payDate, entities.DividendReinvestStatusAwaiting); err != nil {
+		return nil, fmt.Errorf("failed to get dividends awaiting withholding: %w", err)
+	}
+	return dividends, nil
+}
+
+// ListAwaitingWithholding returns dividends recorded before createdBefore that are still held
+// for withholding, oldest first
+func (r *DividendRepository) ListAwaitingWithholding(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.Dividend, error) {
+	query := `
+		SELECT ` + dividendColumns + `
+		FROM dividends
+		WHERE reinvest_status = $1 AND created_at < $2
+		ORDER BY created_at
+		LIMIT $3
+	`
+	var dividends []*entities.Dividend
+	if err := r.db.SelectContext(ctx, &dividends, query, entities.DividendReinvestStatusAwaiting, createdBefore, limit); err != nil {
+		return nil, fmt.Errorf("failed to list dividends awaiting withholding: %w", err)
+	}
+	return dividends, nil
+}
+
+// ClaimAwaiting moves a dividend held for withholding back to not reinvested, reporting whether
+// this caller made the move and so owns reinvesting it
+func (r *DividendRepository) ClaimAwaiting(ctx context.Context, dividendID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE dividends
+		SET reinvest_status = $2, updated_at = NOW()
+		WHERE id = $1 AND reinvest_status = $3
+	`
+	result, err := r.db.ExecContext(ctx, query, dividendID, entities.DividendReinvestStatusNone, entities.DividendReinvestStatusAwaiting)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim awaiting dividend: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim awaiting dividend: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// GetByUser returns a user's dividend activities paid in [from, to)
+func (r *DividendRepository) GetByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.Dividend, error) {
+	query := `
+		SELECT ` + dividendColumns + `
+		FROM dividends
+		WHERE user_id = $1 AND pay_date >= $2 AND pay_date < $3
+		ORDER BY pay_date, created_at
+	`
+	var dividends []*entities.Dividend
+	if err := r.db.SelectContext(ctx, &dividends, query, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get dividends: %w", err)
+	}
+	return dividends, nil
+}
@@ -79,11 +79,12 @@ func (r *ScheduledInvestmentRepository) Create(ctx context.Context, si *entities
 	query := `
 		INSERT INTO scheduled_investments (
 			id, user_id, name, symbol, basket_id, amount, frequency, day_of_week, day_of_month,
-			next_execution_at, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+			next_execution_at, status, catch_up_policy, holiday_policy, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 	_, err := r.db.ExecContext(ctx, query,
 		si.ID, si.UserID, si.Name, si.Symbol, si.BasketID, si.Amount, si.Frequency,
-		si.DayOfWeek, si.DayOfMonth, si.NextExecutionAt, si.Status, si.CreatedAt, si.UpdatedAt)
+		si.DayOfWeek, si.DayOfMonth, si.NextExecutionAt, si.Status, si.CatchUpPolicy, si.HolidayPolicy,
+		si.CreatedAt, si.UpdatedAt)
 	return err
 }
 
@@ -110,16 +111,30 @@ func (r *ScheduledInvestmentRepository) GetDueForExecution(ctx context.Context,
 	return investments, err
 }
 
+// GetDueForReminder returns active schedules running between now and before that have not been
+// reminded about their next run
+func (r *ScheduledInvestmentRepository) GetDueForReminder(ctx context.Context, before time.Time) ([]*entities.ScheduledInvestment, error) {
+	var investments []*entities.ScheduledInvestment
+	query := `
+		SELECT * FROM scheduled_investments
+		WHERE status = 'active' AND next_execution_at > NOW() AND next_execution_at <= $1
+			AND (reminder_sent_for IS NULL OR reminder_sent_for <> next_execution_at)`
+	err := r.db.SelectContext(ctx, &investments, query, before)
+	return investments, err
+}
+
 func (r *ScheduledInvestmentRepository) Update(ctx context.Context, si *entities.ScheduledInvestment) error {
 	query := `
 		UPDATE scheduled_investments SET
 			name = $2, amount = $3, frequency = $4, day_of_week = $5, day_of_month = $6,
 			next_execution_at = $7, last_executed_at = $8, status = $9,
-			total_invested = $10, execution_count = $11, updated_at = $12
+			total_invested = $10, execution_count = $11, updated_at = $12,
+			catch_up_policy = $13, holiday_policy = $14, reminder_sent_for = $15
 		WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query,
 		si.ID, si.Name, si.Amount, si.Frequency, si.DayOfWeek, si.DayOfMonth,
-		si.NextExecutionAt, si.LastExecutedAt, si.Status, si.TotalInvested, si.ExecutionCount, time.Now())
+		si.NextExecutionAt, si.LastExecutedAt, si.Status, si.TotalInvested, si.ExecutionCount, time.Now(),
+		si.CatchUpPolicy, si.HolidayPolicy, si.ReminderSentFor)
 	return err
 }
 
@@ -129,9 +144,12 @@ func (r *ScheduledInvestmentRepository) UpdateStatus(ctx context.Context, id uui
 }
 
 func (r *ScheduledInvestmentRepository) CreateExecution(ctx context.Context, exec *entities.ScheduledInvestmentExecution) error {
-	query := `INSERT INTO scheduled_investment_executions (id, scheduled_investment_id, order_id, amount, status, error_message, executed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.ExecContext(ctx, query, exec.ID, exec.ScheduledInvestmentID, exec.OrderID, exec.Amount, exec.Status, exec.ErrorMessage, exec.ExecutedAt)
+	query := `INSERT INTO scheduled_investment_executions (
+			id, scheduled_investment_id, order_id, ledger_transaction_id, amount, status, error_message,
+			reason, scheduled_for, executed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.db.ExecContext(ctx, query, exec.ID, exec.ScheduledInvestmentID, exec.OrderID, exec.LedgerTransactionID,
+		exec.Amount, exec.Status, exec.ErrorMessage, exec.Reason, exec.ScheduledFor, exec.ExecutedAt)
 	return err
 }
 
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// RewardsRepository handles cashback offer and reward persistence
+type RewardsRepository struct {
+	db *sqlx.DB
+}
+
+// NewRewardsRepository creates a new rewards repository
+func NewRewardsRepository(db *sqlx.DB) *RewardsRepository {
+	return &RewardsRepository{db: db}
+}
+
+// CreateOffer creates a new reward offer
+func (r *RewardsRepository) CreateOffer(ctx context.Context, offer *entities.RewardOffer) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO reward_offers (id, name, description, merchant_key, category, rate, per_transaction_cap,
+		                            monthly_cap, starts_at, ends_at, active, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		offer.ID, offer.Name, offer.Description, offer.MerchantKey, offer.Category, offer.Rate,
+		offer.PerTransactionCap, offer.MonthlyCap, offer.StartsAt, offer.EndsAt, offer.Active,
+		offer.CreatedAt, offer.UpdatedAt)
+	return err
+}
+
+// UpdateOffer updates the mutable fields of a reward offer
+func (r *RewardsRepository) UpdateOffer(ctx context.Context, offer *entities.RewardOffer) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE reward_offers SET active = $1, rate = $2, per_transaction_cap = $3, monthly_cap = $4,
+		                          ends_at = $5, updated_at = $6
+		 WHERE id = $7`,
+		offer.Active, offer.Rate, offer.PerTransactionCap, offer.MonthlyCap, offer.EndsAt, offer.UpdatedAt, offer.ID)
+	return err
+}
+
+// GetOffer retrieves a reward offer by ID
+func (r *RewardsRepository) GetOffer(ctx context.Context, id uuid.UUID) (*entities.RewardOffer, error) {
+	var offer entities.RewardOffer
+	err := r.db.GetContext(ctx, &offer, `SELECT * FROM reward_offers WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// ListOffers lists reward offers, optionally only active ones that have not ended
+func (r *RewardsRepository) ListOffers(ctx context.Context, activeOnly bool) ([]*entities.RewardOffer, error) {
+	var offers []*entities.RewardOffer
+	query := `SELECT * FROM reward_offers ORDER BY created_at DESC`
+	if activeOnly {
+		query = `SELECT * FROM reward_offers WHERE active = true AND (ends_at IS NULL OR ends_at > NOW())
+		         ORDER BY created_at DESC`
+	}
+	err := r.db.SelectContext(ctx, &offers, query)
+	return offers, err
+}
+
+// CreateReward creates a new cashback reward
+func (r *RewardsRepository) CreateReward(ctx context.Context, reward *entities.CashbackReward) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO cashback_rewards (id, user_id, offer_id, offer_name, card_transaction_id, merchant_name,
+		                               merchant_key, category, purchase_amount, rate, amount, status,
+		                               available_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		reward.ID, reward.UserID, reward.OfferID, reward.OfferName, reward.CardTransactionID, reward.MerchantName,
+		reward.MerchantKey, reward.Category, reward.PurchaseAmount, reward.Rate, reward.Amount, reward.Status,
+		reward.AvailableAt, reward.CreatedAt, reward.UpdatedAt)
+	return err
+}
+
+// GetRewardByCardTransaction retrieves the reward earned by a card transaction
+func (r *RewardsRepository) GetRewardByCardTransaction(ctx context.Context, cardTransactionID uuid.UUID) (*entities.CashbackReward, error) {
+	var reward entities.CashbackReward
+	err := r.db.GetContext(ctx, &reward,
+		`SELECT * FROM cashback_rewards WHERE card_transaction_id = $1`, cardTransactionID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// FindRefundableReward finds the latest unreversed reward at a merchant for a purchase of at least the refunded amount
+func (r *RewardsRepository) FindRefundableReward(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.CashbackReward, error) {
+	var reward entities.CashbackReward
+	err := r.db.GetContext(ctx, &reward,
+		`SELECT * FROM cashback_rewards
+		 WHERE user_id = $1 AND merchant_key = $2 AND purchase_amount >= $3 AND status <> 'reversed'
+		 ORDER BY created_at DESC LIMIT 1`,
+		userID, merchantKey, amount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// UpdateRewardStatus updates a reward's status. The ledger transaction is recorded as the payout
+// for paid rewards and as the clawback for reversed ones.
+func (r *RewardsRepository) UpdateRewardStatus(ctx context.Context, id uuid.UUID, status entities.CashbackStatus, ledgerTxID *uuid.UUID) error {
+	now := time.Now()
+	var paidAt, reversedAt *time.Time
+	var payoutTxID, reversalTxID *uuid.UUID
+	switch status {
+	case entities.CashbackStatusPaid:
+		paidAt, payoutTxID = &now, ledgerTxID
+	case entities.CashbackStatusReversed:
+		reversedAt, reversalTxID = &now, ledgerTxID
+	}
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cashback_rewards SET status = $1, paid_at = COALESCE($2, paid_at),
+		 reversed_at = COALESCE($3, reversed_at), ledger_transaction_id = COALESCE($4, ledger_transaction_id),
+		 reversal_ledger_transaction_id = COALESCE($5, reversal_ledger_transaction_id), updated_at = $6
+		 WHERE id = $7`,
+		status, paidAt, reversedAt, payoutTxID, reversalTxID, now, id)
+	return err
+}
+
+// ReduceReward lowers the purchase and cashback amounts of a partially refunded reward
+func (r *RewardsRepository) ReduceReward(ctx context.Context, id uuid.UUID, purchaseAmount, amount decimal.Decimal) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cashback_rewards SET purchase_amount = $1, amount = $2, updated_at = $3 WHERE id = $4`,
+		purchaseAmount, amount, time.Now(), id)
+	return err
+}
+
+// SumOfferRewardsSince totals the unreversed cashback a user earned from an offer since the given time
+func (r *RewardsRepository) SumOfferRewardsSince(ctx context.Context, userID, offerID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := r.db.GetContext(ctx, &total,
+		`SELECT COALESCE(SUM(amount), 0) FROM cashback_rewards
+		 WHERE user_id = $1 AND offer_id = $2 AND created_at >= $3 AND status <> 'reversed'`,
+		userID, offerID, since)
+	return total, err
+}
+
+// GetRewardsReadyForPayout finds pending rewards past their clearing period and unpaid available rewards.
+// Rewards whose payout has not been attempted come first, then held rewards from the longest held.
+func (r *RewardsRepository) GetRewardsReadyForPayout(ctx context.Context, now time.Time, limit int) ([]*entities.CashbackReward, error) {
+	var rewards []*entities.CashbackReward
+	err := r.db.SelectContext(ctx, &rewards,
+		`SELECT * FROM cashback_rewards
+		 WHERE (status = 'pending' AND available_at <= $1) OR status = 'available'
+		 ORDER BY payout_attempted_at NULLS FIRST, available_at LIMIT $2`,
+		now, limit)
+	return rewards, err
+}
+
+// MarkPayoutAttempted records a payout attempt that did not pay the reward
+func (r *RewardsRepository) MarkPayoutAttempted(ctx context.Context, id uuid.UUID, attemptedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cashback_rewards SET payout_attempted_at = $1, updated_at = $1 WHERE id = $2`,
+		attemptedAt, id)
+	return err
+}
+
+// GetUserRewards retrieves a user's rewards with pagination
+func (r *RewardsRepository) GetUserRewards(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CashbackReward, error) {
+	var rewards []*entities.CashbackReward
+	err := r.db.SelectContext(ctx, &rewards,
+		`SELECT * FROM cashback_rewards WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	return rewards, err
+}
+
+// GetUserSummary totals a user's cashback by status
+func (r *RewardsRepository) GetUserSummary(ctx context.Context, userID uuid.UUID) (*entities.CashbackSummary, error) {
+	var summary entities.CashbackSummary
+	err := r.db.GetContext(ctx, &summary,
+		`SELECT COALESCE(SUM(amount) FILTER (WHERE status = 'pending'), 0) AS pending,
+		        COALESCE(SUM(amount) FILTER (WHERE status = 'available'), 0) AS available,
+		        COALESCE(SUM(amount) FILTER (WHERE status = 'paid'), 0) AS paid,
+		        COALESCE(SUM(amount) FILTER (WHERE status <> 'reversed'), 0) AS lifetime_earned,
+		        COUNT(*) FILTER (WHERE status <> 'reversed') AS reward_count,
+		        MIN(available_at) FILTER (WHERE status = 'pending') AS next_available_at
+		 FROM cashback_rewards WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
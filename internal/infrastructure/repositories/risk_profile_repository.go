@@ -0,0 +1,269 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// RiskProfileRepository handles risk questionnaire, assessment and acknowledgment database operations
+type RiskProfileRepository struct {
+	db *sqlx.DB
+}
+
+// NewRiskProfileRepository creates a new risk profile repository
+func NewRiskProfileRepository(db *sqlx.DB) *RiskProfileRepository {
+	return &RiskProfileRepository{db: db}
+}
+
+// riskQuestionnaireRow scans a questionnaire with its JSON columns
+type riskQuestionnaireRow struct {
+	entities.RiskQuestionnaire
+	QuestionsJSON []byte `db:"questions"`
+	BandsJSON     []byte `db:"bands"`
+}
+
+func (row *riskQuestionnaireRow) decode() (*entities.RiskQuestionnaire, error) {
+	questionnaire := row.RiskQuestionnaire
+	if err := json.Unmarshal(row.QuestionsJSON, &questionnaire.Questions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal questions: %w", err)
+	}
+	if err := json.Unmarshal(row.BandsJSON, &questionnaire.Bands); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bands: %w", err)
+	}
+	return &questionnaire, nil
+}
+
+// riskAssessmentRow scans an assessment with its JSON answers
+type riskAssessmentRow struct {
+	entities.RiskAssessment
+	AnswersJSON []byte `db:"answers"`
+}
+
+func (row *riskAssessmentRow) decode() (*entities.RiskAssessment, error) {
+	assessment := row.RiskAssessment
+	if err := json.Unmarshal(row.AnswersJSON, &assessment.Answers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal answers: %w", err)
+	}
+	return &assessment, nil
+}
+
+const riskAssessmentColumns = `
+	id, user_id, questionnaire_id, questionnaire_version, answers, score, risk_band,
+	time_horizon_years, reassess_at, prompted_at, created_at`
+
+// === Questionnaire Operations ===
+
+// CreateQuestionnaire stores a questionnaire version as the active one, retiring the previous version
+func (r *RiskProfileRepository) CreateQuestionnaire(ctx context.Context, questionnaire *entities.RiskQuestionnaire) error {
+	questions, err := json.Marshal(questionnaire.Questions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal questions: %w", err)
+	}
+	bands, err := json.Marshal(questionnaire.Bands)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bands: %w", err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE risk_questionnaires SET status = 'retired' WHERE status = 'active'`); err != nil {
+		return fmt.Errorf("failed to retire questionnaire: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_questionnaires (id, version, status, questions, bands, reassess_after_days, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		questionnaire.ID, questionnaire.Version, questionnaire.Status, questions, bands,
+		questionnaire.ReassessAfterDays, questionnaire.CreatedBy, questionnaire.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create questionnaire: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetActiveQuestionnaire returns the questionnaire version users answer, or nil if none is published
+func (r *RiskProfileRepository) GetActiveQuestionnaire(ctx context.Context) (*entities.RiskQuestionnaire, error) {
+	query := `
+		SELECT id, version, status, questions, bands, reassess_after_days, created_by, created_at
+		FROM risk_questionnaires
+		WHERE status = 'active'
+	`
+	var row riskQuestionnaireRow
+	err := r.db.GetContext(ctx, &row, query)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active questionnaire: %w", err)
+	}
+	return row.decode()
+}
+
+// GetQuestionnaires returns every questionnaire version, newest first
+func (r *RiskProfileRepository) GetQuestionnaires(ctx context.Context) ([]*entities.RiskQuestionnaire, error) {
+	query := `
+		SELECT id, version, status, questions, bands, reassess_after_days, created_by, created_at
+		FROM risk_questionnaires
+		ORDER BY version DESC
+	`
+	var rows []riskQuestionnaireRow
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires: %w", err)
+	}
+
+	questionnaires := make([]*entities.RiskQuestionnaire, 0, len(rows))
+	for i := range rows {
+		questionnaire, err := rows[i].decode()
+		if err != nil {
+			return nil, err
+		}
+		questionnaires = append(questionnaires, questionnaire)
+	}
+	return questionnaires, nil
+}
+
+// === Assessment Operations ===
+
+// CreateAssessment stores a user's scored questionnaire answers
+func (r *RiskProfileRepository) CreateAssessment(ctx context.Context, assessment *entities.RiskAssessment) error {
+	answers, err := json.Marshal(assessment.Answers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal answers: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_assessments (id, user_id, questionnaire_id, questionnaire_version, answers, score,
+		                              risk_band, time_horizon_years, reassess_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		assessment.ID, assessment.UserID, assessment.QuestionnaireID, assessment.QuestionnaireVersion,
+		answers, assessment.Score, assessment.RiskBand, assessment.TimeHorizonYears,
+		assessment.ReassessAt, assessment.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create risk assessment: %w", err)
+	}
+	return nil
+}
+
+// GetLatestAssessment returns a user's most recent assessment, or nil if they have none
+func (r *RiskProfileRepository) GetLatestAssessment(ctx context.Context, userID uuid.UUID) (*entities.RiskAssessment, error) {
+	query := `
+		SELECT ` + riskAssessmentColumns + `
+		FROM risk_assessments
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var row riskAssessmentRow
+	err := r.db.GetContext(ctx, &row, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessment: %w", err)
+	}
+	return row.decode()
+}
+
+// GetAssessmentsByUser returns a user's assessments, newest first
+func (r *RiskProfileRepository) GetAssessmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.RiskAssessment, error) {
+	query := `
+		SELECT ` + riskAssessmentColumns + `
+		FROM risk_assessments
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	var rows []riskAssessmentRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get risk assessments: %w", err)
+	}
+	return decodeRiskAssessments(rows)
+}
+
+// GetAssessmentsDueForReassessment returns users' current assessments whose re-assessment date has
+// passed and who have not been prompted yet
+func (r *RiskProfileRepository) GetAssessmentsDueForReassessment(ctx context.Context, at time.Time, limit int) ([]*entities.RiskAssessment, error) {
+	query := `
+		SELECT ` + riskAssessmentColumns + `
+		FROM (
+			SELECT DISTINCT ON (user_id) ` + riskAssessmentColumns + `
+			FROM risk_assessments
+			ORDER BY user_id, created_at DESC
+		) latest
+		WHERE reassess_at <= $1 AND prompted_at IS NULL
+		ORDER BY reassess_at
+		LIMIT $2
+	`
+	var rows []riskAssessmentRow
+	if err := r.db.SelectContext(ctx, &rows, query, at, limit); err != nil {
+		return nil, fmt.Errorf("failed to get assessments due for reassessment: %w", err)
+	}
+	return decodeRiskAssessments(rows)
+}
+
+// MarkReassessmentPrompted records that a user was asked to retake the questionnaire
+func (r *RiskProfileRepository) MarkReassessmentPrompted(ctx context.Context, assessmentID uuid.UUID) error {
+	query := `UPDATE risk_assessments SET prompted_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, assessmentID)
+	return err
+}
+
+func decodeRiskAssessments(rows []riskAssessmentRow) ([]*entities.RiskAssessment, error) {
+	assessments := make([]*entities.RiskAssessment, 0, len(rows))
+	for i := range rows {
+		assessment, err := rows[i].decode()
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, assessment)
+	}
+	return assessments, nil
+}
+
+// === Acknowledgment Operations ===
+
+// CreateAcknowledgment records a user accepting an investment above their risk band
+func (r *RiskProfileRepository) CreateAcknowledgment(ctx context.Context, ack *entities.RiskAcknowledgment) error {
+	query := `
+		INSERT INTO risk_acknowledgments (id, user_id, assessment_id, product, resource_id, product_risk_band,
+		                                  user_risk_band, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		ack.ID, ack.UserID, ack.AssessmentID, ack.Product, ack.ResourceID, ack.ProductRiskBand,
+		ack.UserRiskBand, ack.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create risk acknowledgment: %w", err)
+	}
+	return nil
+}
+
+// GetAcknowledgments returns the acknowledgments a user gave for a product under an assessment.
+// A nil resource ID matches standing acknowledgments only.
+func (r *RiskProfileRepository) GetAcknowledgments(ctx context.Context, userID, assessmentID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID) ([]*entities.RiskAcknowledgment, error) {
+	query := `
+		SELECT id, user_id, assessment_id, product, resource_id, product_risk_band, user_risk_band, created_at
+		FROM risk_acknowledgments
+		WHERE user_id = $1 AND assessment_id = $2 AND product = $3 AND resource_id IS NOT DISTINCT FROM $4
+		ORDER BY created_at DESC
+	`
+	var acks []*entities.RiskAcknowledgment
+	if err := r.db.SelectContext(ctx, &acks, query, userID, assessmentID, product, resourceID); err != nil {
+		return nil, fmt.Errorf("failed to get risk acknowledgments: %w", err)
+	}
+	return acks, nil
+}
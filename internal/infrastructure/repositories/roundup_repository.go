@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/shopspring/decimal"
 )
 
 // RoundupRepository handles round-up data persistence
@@ -25,7 +26,9 @@ func (r *RoundupRepository) GetSettings(ctx context.Context, userID uuid.UUID) (
 	var settings entities.RoundupSettings
 	err := r.db.GetContext(ctx, &settings,
 		`SELECT user_id, enabled, multiplier, threshold, auto_invest_enabled, 
-		        auto_invest_basket_id, auto_invest_symbol, created_at, updated_at
+		        auto_invest_basket_id, auto_invest_symbol, weekly_cap, monthly_cap,
+		        boost_multiplier, boost_ends_at, low_balance_floor, skip_below_balance,
+		        created_at, updated_at
 		 FROM roundup_settings WHERE user_id = $1`, userID)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -41,8 +44,10 @@ func (r *RoundupRepository) UpsertSettings(ctx context.Context, settings *entiti
 	settings.UpdatedAt = time.Now()
 	_, err := r.db.ExecContext(ctx,
 		`INSERT INTO roundup_settings (user_id, enabled, multiplier, threshold, auto_invest_enabled, 
-		                               auto_invest_basket_id, auto_invest_symbol, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		                               auto_invest_basket_id, auto_invest_symbol, weekly_cap, monthly_cap,
+		                               boost_multiplier, boost_ends_at, low_balance_floor, skip_below_balance,
+		                               created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		 ON CONFLICT (user_id) DO UPDATE SET
 		   enabled = EXCLUDED.enabled,
 		   multiplier = EXCLUDED.multiplier,
@@ -50,9 +55,17 @@ func (r *RoundupRepository) UpsertSettings(ctx context.Context, settings *entiti
 		   auto_invest_enabled = EXCLUDED.auto_invest_enabled,
 		   auto_invest_basket_id = EXCLUDED.auto_invest_basket_id,
 		   auto_invest_symbol = EXCLUDED.auto_invest_symbol,
+		   weekly_cap = EXCLUDED.weekly_cap,
+		   monthly_cap = EXCLUDED.monthly_cap,
+		   boost_multiplier = EXCLUDED.boost_multiplier,
+		   boost_ends_at = EXCLUDED.boost_ends_at,
+		   low_balance_floor = EXCLUDED.low_balance_floor,
+		   skip_below_balance = EXCLUDED.skip_below_balance,
 		   updated_at = EXCLUDED.updated_at`,
 		settings.UserID, settings.Enabled, settings.Multiplier, settings.Threshold,
 		settings.AutoInvestEnabled, settings.AutoInvestBasketID, settings.AutoInvestSymbol,
+		settings.WeeklyCap, settings.MonthlyCap, settings.BoostMultiplier, settings.BoostEndsAt,
+		settings.LowBalanceFloor, settings.SkipBelowBalance,
 		settings.CreatedAt, settings.UpdatedAt)
 	return err
 }
@@ -61,10 +74,54 @@ func (r *RoundupRepository) UpsertSettings(ctx context.Context, settings *entiti
 func (r *RoundupRepository) CreateTransaction(ctx context.Context, tx *entities.RoundupTransaction) error {
 	_, err := r.db.ExecContext(ctx,
 		`INSERT INTO roundup_transactions (id, user_id, original_amount, rounded_amount, spare_change,
-		                                   multiplied_amount, source_type, source_ref, merchant_name, status, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		                                   multiplied_amount, source_type, source_ref, merchant_name, status,
+		                                   collected_at, ledger_transaction_id, skip_reason, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
 		tx.ID, tx.UserID, tx.OriginalAmount, tx.RoundedAmount, tx.SpareChange,
-		tx.MultipliedAmount, tx.SourceType, tx.SourceRef, tx.MerchantName, tx.Status, tx.CreatedAt)
+		tx.MultipliedAmount, tx.SourceType, tx.SourceRef, tx.MerchantName, tx.Status,
+		tx.CollectedAt, tx.LedgerTxID, tx.SkipReason, tx.CreatedAt)
+	return err
+}
+
+// GetTransactionBySourceRef retrieves the round-up generated from a source transaction
+func (r *RoundupRepository) GetTransactionBySourceRef(ctx context.Context, userID uuid.UUID, sourceType entities.RoundupSourceType, sourceRef string) (*entities.RoundupTransaction, error) {
+	var tx entities.RoundupTransaction
+	err := r.db.GetContext(ctx, &tx,
+		`SELECT * FROM roundup_transactions WHERE user_id = $1 AND source_type = $2 AND source_ref = $3`,
+		userID, sourceType, sourceRef)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// FindRefundableRoundup finds the latest unreversed card round-up at a merchant for a purchase of at least the refunded amount
+func (r *RoundupRepository) FindRefundableRoundup(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.RoundupTransaction, error) {
+	var tx entities.RoundupTransaction
+	err := r.db.GetContext(ctx, &tx,
+		`SELECT rt.* FROM roundup_transactions rt
+		 JOIN card_transactions ct ON ct.id::text = rt.source_ref
+		 WHERE rt.user_id = $1 AND rt.source_type = $2 AND ct.merchant_key = $3
+		   AND rt.original_amount >= $4 AND rt.status <> 'reversed'
+		 ORDER BY rt.created_at DESC LIMIT 1`,
+		userID, entities.RoundupSourceCard, merchantKey, amount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// ReduceTransaction lowers the purchase and round-up amounts of a partially refunded round-up
+func (r *RoundupRepository) ReduceTransaction(ctx context.Context, id uuid.UUID, originalAmount, multipliedAmount decimal.Decimal) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE roundup_transactions SET original_amount = $1, multiplied_amount = $2 WHERE id = $3`,
+		originalAmount, multipliedAmount, id)
 	return err
 }
 
@@ -101,6 +158,16 @@ func (r *RoundupRepository) GetTransactions(ctx context.Context, userID uuid.UUI
 	return txs, err
 }
 
+// SumRoundupsSince totals the round-up amounts taken since the given time
+func (r *RoundupRepository) SumRoundupsSince(ctx context.Context, userID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := r.db.GetContext(ctx, &total,
+		`SELECT COALESCE(SUM(multiplied_amount), 0) FROM roundup_transactions
+		 WHERE user_id = $1 AND created_at >= $2 AND status IN ('pending', 'collected', 'invested')`,
+		userID, since)
+	return total, err
+}
+
 // CountTransactions counts transactions for a user
 func (r *RoundupRepository) CountTransactions(ctx context.Context, userID uuid.UUID) (int, error) {
 	var count int
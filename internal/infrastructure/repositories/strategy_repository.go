@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// StrategyRepository handles model portfolio and strategy assignment database operations
+type StrategyRepository struct {
+	db *sqlx.DB
+}
+
+// NewStrategyRepository creates a new strategy repository
+func NewStrategyRepository(db *sqlx.DB) *StrategyRepository {
+	return &StrategyRepository{db: db}
+}
+
+const modelPortfolioVersionColumns = `
+	v.id, v.portfolio_id, p.name AS portfolio_name, v.version, v.effective_from, v.priority,
+	v.risk_band, v.criteria, v.allocations, v.notes, v.created_by, v.created_at`
+
+// modelPortfolioVersionRow scans a version with its JSON columns
+type modelPortfolioVersionRow struct {
+	entities.ModelPortfolioVersion
+	CriteriaJSON    []byte `db:"criteria"`
+	AllocationsJSON []byte `db:"allocations"`
+}
+
+func (row *modelPortfolioVersionRow) decode() (*entities.ModelPortfolioVersion, error) {
+	version := row.ModelPortfolioVersion
+	if err := json.Unmarshal(row.CriteriaJSON, &version.Criteria); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal criteria: %w", err)
+	}
+	if err := json.Unmarshal(row.AllocationsJSON, &version.Allocations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allocations: %w", err)
+	}
+	return &version, nil
+}
+
+func decodeModelPortfolioVersions(rows []modelPortfolioVersionRow) ([]*entities.ModelPortfolioVersion, error) {
+	versions := make([]*entities.ModelPortfolioVersion, 0, len(rows))
+	for i := range rows {
+		version, err := rows[i].decode()
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// === Model Portfolio Operations ===
+
+// CreateModelPortfolio creates a model portfolio together with its first version
+func (r *StrategyRepository) CreateModelPortfolio(ctx context.Context, portfolio *entities.ModelPortfolio, version *entities.ModelPortfolioVersion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO model_portfolios (id, slug, name, description, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		portfolio.ID, portfolio.Slug, portfolio.Name, portfolio.Description, portfolio.Status,
+		portfolio.CreatedAt, portfolio.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create model portfolio: %w", err)
+	}
+	if err := insertModelPortfolioVersion(ctx, tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetModelPortfolios returns every model portfolio, ordered by name
+func (r *StrategyRepository) GetModelPortfolios(ctx context.Context) ([]*entities.ModelPortfolio, error) {
+	query := `
+		SELECT id, slug, name, description, status, created_at, updated_at
+		FROM model_portfolios
+		ORDER BY name
+	`
+	var portfolios []*entities.ModelPortfolio
+	if err := r.db.SelectContext(ctx, &portfolios, query); err != nil {
+		return nil, fmt.Errorf("failed to get model portfolios: %w", err)
+	}
+	return portfolios, nil
+}
+
+// GetModelPortfolioByID returns a model portfolio, or nil if it does not exist
+func (r *StrategyRepository) GetModelPortfolioByID(ctx context.Context, id uuid.UUID) (*entities.ModelPortfolio, error) {
+	query := `
+		SELECT id, slug, name, description, status, created_at, updated_at
+		FROM model_portfolios
+		WHERE id = $1
+	`
+	var portfolio entities.ModelPortfolio
+	err := r.db.GetContext(ctx, &portfolio, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model portfolio: %w", err)
+	}
+	return &portfolio, nil
+}
+
+// UpdateModelPortfolioStatus retires or reactivates a model portfolio
+func (r *StrategyRepository) UpdateModelPortfolioStatus(ctx context.Context, id uuid.UUID, status entities.ModelPortfolioStatus) error {
+	query := `UPDATE model_portfolios SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, status, id)
+	return err
+}
+
+// === Model Portfolio Version Operations ===
+
+// CreateModelPortfolioVersion stores a new version of a model portfolio
+func (r *StrategyRepository) CreateModelPortfolioVersion(ctx context.Context, version *entities.ModelPortfolioVersion) error {
+	return insertModelPortfolioVersion(ctx, r.db, version)
+}
+
+func insertModelPortfolioVersion(ctx context.Context, db sqlx.ExecerContext, version *entities.ModelPortfolioVersion) error {
+	criteria, err := json.Marshal(version.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal criteria: %w", err)
+	}
+	allocations, err := json.Marshal(version.Allocations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal allocations: %w", err)
+	}
+
+	query := `
+		INSERT INTO model_portfolio_versions (id, portfolio_id, version, effective_from, priority, risk_band,
+		                                      criteria, allocations, notes, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	if _, err := db.ExecContext(ctx, query,
+		version.ID, version.PortfolioID, version.Version, version.EffectiveFrom, version.Priority,
+		version.RiskBand, criteria, allocations, version.Notes, version.CreatedBy, version.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create model portfolio version: %w", err)
+	}
+	return nil
+}
+
+// GetModelPortfolioVersions returns a model portfolio's versions, newest first
+func (r *StrategyRepository) GetModelPortfolioVersions(ctx context.Context, portfolioID uuid.UUID) ([]*entities.ModelPortfolioVersion, error) {
+	query := `
+		SELECT ` + modelPortfolioVersionColumns + `
+		FROM model_portfolio_versions v
+		JOIN model_portfolios p ON p.id = v.portfolio_id
+		WHERE v.portfolio_id = $1
+		ORDER BY v.version DESC
+	`
+	var rows []modelPortfolioVersionRow
+	if err := r.db.SelectContext(ctx, &rows, query, portfolioID); err != nil {
+		return nil, fmt.Errorf("failed to get model portfolio versions: %w", err)
+	}
+	return decodeModelPortfolioVersions(rows)
+}
+
+// GetEffectiveModelPortfolioVersions returns the version in effect at a time for each active model
+// portfolio: the most recent one whose effective date has passed
+func (r *StrategyRepository) GetEffectiveModelPortfolioVersions(ctx context.Context, at time.Time) ([]*entities.ModelPortfolioVersion, error) {
+	query := `
+		SELECT DISTINCT ON (v.portfolio_id) ` + modelPortfolioVersionColumns + `
+		FROM model_portfolio_versions v
+		JOIN model_portfolios p ON p.id = v.portfolio_id
+		WHERE p.status = 'active' AND v.effective_from <= $1
+		ORDER BY v.portfolio_id, v.effective_from DESC, v.version DESC
+	`
+	var rows []modelPortfolioVersionRow
+	if err := r.db.SelectContext(ctx, &rows, query, at); err != nil {
+		return nil, fmt.Errorf("failed to get effective model portfolio versions: %w", err)
+	}
+	return decodeModelPortfolioVersions(rows)
+}
+
+// === Strategy Assignment Operations ===
+
+// CreateStrategyAssignment records the strategy used for an auto-investment. Retries of the same
+// auto-investment keep the first record.
+func (r *StrategyRepository) CreateStrategyAssignment(ctx context.Context, assignment *entities.StrategyAssignment) error {
+	signals, err := json.Marshal(assignment.Signals)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signals: %w", err)
+	}
+	allocations, err := json.Marshal(assignment.Allocations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal allocations: %w", err)
+	}
+
+	query := `
+		INSERT INTO strategy_assignments (id, user_id, portfolio_id, portfolio_version_id, portfolio_name, version,
+		                                  correlation_id, amount, signals, allocations, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id, correlation_id) DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		assignment.ID, assignment.UserID, assignment.PortfolioID, assignment.PortfolioVersionID,
+		assignment.PortfolioName, assignment.Version, assignment.CorrelationID, assignment.Amount,
+		signals, allocations, assignment.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create strategy assignment: %w", err)
+	}
+	return nil
+}
+
+// GetStrategyAssignmentsByUser returns a user's strategy assignments, newest first
+func (r *StrategyRepository) GetStrategyAssignmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.StrategyAssignment, error) {
+	query := `
+		SELECT id, user_id, portfolio_id, portfolio_version_id, portfolio_name, version, correlation_id,
+		       amount, signals, allocations, created_at
+		FROM strategy_assignments
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	var rows []struct {
+		entities.StrategyAssignment
+		SignalsJSON     []byte `db:"signals"`
+		AllocationsJSON []byte `db:"allocations"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get strategy assignments: %w", err)
+	}
+
+	assignments := make([]*entities.StrategyAssignment, 0, len(rows))
+	for i := range rows {
+		assignment := rows[i].StrategyAssignment
+		if err := json.Unmarshal(rows[i].SignalsJSON, &assignment.Signals); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signals: %w", err)
+		}
+		if err := json.Unmarshal(rows[i].AllocationsJSON, &assignment.Allocations); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal allocations: %w", err)
+		}
+		assignments = append(assignments, &assignment)
+	}
+	return assignments, nil
+}
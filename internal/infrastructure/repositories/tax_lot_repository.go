@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+)
+
+// TaxLotRepository handles tax lot, realized gain and lot setting database operations
+type TaxLotRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaxLotRepository creates a new tax lot repository
+func NewTaxLotRepository(db *sqlx.DB) *TaxLotRepository {
+	return &TaxLotRepository{db: db}
+}
+
+const taxLotColumns = `
+	id, user_id, symbol, alpaca_order_id, quantity, remaining_quantity, cost_basis, remaining_cost_basis,
+	wash_sale_adjustment, is_replacement, purchased_at, acquired_at, created_at, updated_at`
+
+const realizedGainColumns = `
+	id, user_id, lot_id, symbol, alpaca_order_id, quantity, proceeds, cost_basis, gain_loss,
+	wash_sale_quantity, disallowed_loss, holding_period, acquired_at, sold_at, created_at, updated_at`
+
+// GetSettings returns a user's lot settings, or nil if they have not chosen a method
+func (r *TaxLotRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.TaxLotSettings, error) {
+	var settings entities.TaxLotSettings
+	query := `SELECT user_id, method, harvesting_enabled, updated_at FROM tax_lot_settings WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &settings, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lot settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpsertSettings saves a user's lot settings
+func (r *TaxLotRepository) UpsertSettings(ctx context.Context, settings *entities.TaxLotSettings) error {
+	query := `
+		INSERT INTO tax_lot_settings (user_id, method, harvesting_enabled, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET method = EXCLUDED.method, harvesting_enabled = EXCLUDED.harvesting_enabled, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, settings.UserID, settings.Method, settings.HarvestingEnabled, settings.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save tax lot settings: %w", err)
+	}
+	return nil
+}
+
+// GetFill returns how much of an order has been applied to lots, or nil if none has
+func (r *TaxLotRepository) GetFill(ctx context.Context, alpacaOrderID string) (*entities.TaxLotFill, error) {
+	query := `
+		SELECT alpaca_order_id, user_id, symbol, side, filled_qty, filled_amount, updated_at
+		FROM tax_lot_fills
+		WHERE alpaca_order_id = $1
+	`
+	var fill entities.TaxLotFill
+	err := r.db.GetContext(ctx, &fill, query, alpacaOrderID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lot fill: %w", err)
+	}
+	return &fill, nil
+}
+
+// GetLot returns a lot by ID, or nil if it does not exist
+func (r *TaxLotRepository) GetLot(ctx context.Context, id uuid.UUID) (*entities.TaxLot, error) {
+	var lot entities.TaxLot
+	err := r.db.GetContext(ctx, &lot, `SELECT `+taxLotColumns+` FROM tax_lots WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax lot: %w", err)
+	}
+	return &lot, nil
+}
+
+// GetOpenLots returns a user's lots of a symbol that still hold shares, oldest purchase first
+func (r *TaxLotRepository) GetOpenLots(ctx context.Context, userID uuid.UUID, symbol string) ([]*entities.TaxLot, error) {
+	query := `
+		SELECT ` + taxLotColumns + `
+		FROM tax_lots
+		WHERE user_id = $1 AND symbol = $2 AND remaining_quantity > 0
+		ORDER BY purchased_at, created_at
+	`
+	var lots []*entities.TaxLot
+	if err := r.db.SelectContext(ctx, &lots, query, userID, symbol); err != nil {
+		return nil, fmt.Errorf("failed to get open tax lots: %w", err)
+	}
+	return lots, nil
+}
+
+// GetOpenLotsByUser returns all of a user's lots that still hold shares
+func (r *TaxLotRepository) GetOpenLotsByUser(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLot, error) {
+	query := `
+		SELECT ` + taxLotColumns + `
+		FROM tax_lots
+		WHERE user_id = $1 AND remaining_quantity > 0
+		ORDER BY symbol, purchased_at, created_at
+	`
+	var lots []*entities.TaxLot
+	if err := r.db.SelectContext(ctx, &lots, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get open tax lots: %w", err)
+	}
+	return lots, nil
+}
+
+// GetWashSaleCandidates returns a user's loss sales of a symbol since a time that still have
+// shares not matched to a replacement lot
+func (r *TaxLotRepository) GetWashSaleCandidates(ctx context.Context, userID uuid.UUID, symbol string, since time.Time) ([]*entities.RealizedGain, error) {
+	query := `
+		SELECT ` + realizedGainColumns + `
+		FROM realized_gains
+		WHERE user_id = $1 AND symbol = $2 AND sold_at >= $3 AND gain_loss < 0 AND wash_sale_quantity < quantity
+		ORDER BY sold_at, created_at
+	`
+	var gains []*entities.RealizedGain
+	if err := r.db.SelectContext(ctx, &gains, query, userID, symbol, since); err != nil {
+		return nil, fmt.Errorf("failed to get wash sale candidates: %w", err)
+	}
+	return gains, nil
+}
+
+// GetRealizedGains returns a user's realized gains for sales in [from, to)
+func (r *TaxLotRepository) GetRealizedGains(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.RealizedGain, error) {
+	query := `
+		SELECT ` + realizedGainColumns + `
+		FROM realized_gains
+		WHERE user_id = $1 AND sold_at >= $2 AND sold_at < $3
+		ORDER BY sold_at, symbol, created_at
+	`
+	var gains []*entities.RealizedGain
+	if err := r.db.SelectContext(ctx, &gains, query, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get realized gains: %w", err)
+	}
+	return gains, nil
+}
+
+// ApplyFill saves the lots and realized gains a fill changed together with the fill's progress
+func (r *TaxLotRepository) ApplyFill(ctx context.Context, changes *entities.TaxLotFillChanges) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	lotInsert := `
+		INSERT INTO tax_lots (` + taxLotColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+	for _, lot := range changes.CreatedLots {
+		if _, err := tx.ExecContext(ctx, lotInsert,
+			lot.ID, lot.UserID, lot.Symbol, lot.AlpacaOrderID, lot.Quantity, lot.RemainingQuantity, lot.CostBasis,
+			lot.RemainingCostBasis, lot.WashSaleAdjustment, lot.IsReplacement, lot.PurchasedAt, lot.AcquiredAt,
+			lot.CreatedAt, lot.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to create tax lot: %w", err)
+		}
+	}
+
+	lotUpdate := `
+		UPDATE tax_lots
+		SET quantity = $2, remaining_quantity = $3, cost_basis = $4, remaining_cost_basis = $5,
+		    wash_sale_adjustment = $6, is_replacement = $7, acquired_at = $8, updated_at = $9
+		WHERE id = $1
+	`
+	for _, lot := range changes.UpdatedLots {
+		if _, err := tx.ExecContext(ctx, lotUpdate,
+			lot.ID, lot.Quantity, lot.RemainingQuantity, lot.CostBasis, lot.RemainingCostBasis,
+			lot.WashSaleAdjustment, lot.IsReplacement, lot.AcquiredAt, lot.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to update tax lot: %w", err)
+		}
+	}
+
+	gainInsert := `
+		INSERT INTO realized_gains (` + realizedGainColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+	for _, gain := range changes.CreatedGains {
+		if _, err := tx.ExecContext(ctx, gainInsert,
+			gain.ID, gain.UserID, gain.LotID, gain.Symbol, gain.AlpacaOrderID, gain.Quantity, gain.Proceeds,
+			gain.CostBasis, gain.GainLoss, gain.WashSaleQuantity, gain.DisallowedLoss, gain.HoldingPeriod,
+			gain.AcquiredAt, gain.SoldAt, gain.CreatedAt, gain.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to create realized gain: %w", err)
+		}
+	}
+
+	gainUpdate := `
+		UPDATE realized_gains
+		SET wash_sale_quantity = $2, disallowed_loss = $3, updated_at = $4
+		WHERE id = $1
+	`
+	for _, gain := range changes.UpdatedGains {
+		if _, err := tx.ExecContext(ctx, gainUpdate,
+			gain.ID, gain.WashSaleQuantity, gain.DisallowedLoss, gain.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to update realized gain: %w", err)
+		}
+	}
+
+	fill := changes.Fill
+	fillUpsert := `
+		INSERT INTO tax_lot_fills (alpaca_order_id, user_id, symbol, side, filled_qty, filled_amount, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (alpaca_order_id) DO UPDATE
+		SET filled_qty = EXCLUDED.filled_qty, filled_amount = EXCLUDED.filled_amount, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := tx.ExecContext(ctx, fillUpsert,
+		fill.AlpacaOrderID, fill.UserID, fill.Symbol, fill.Side, fill.FilledQty, fill.FilledAmount, fill.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save tax lot fill: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetSelections returns the lots chosen for a sell order
+func (r *TaxLotRepository) GetSelections(ctx context.Context, orderID uuid.UUID) ([]*entities.TaxLotSelection, error) {
+	query := `SELECT order_id, lot_id, quantity FROM tax_lot_selections WHERE order_id = $1 ORDER BY created_at`
+	var selections []*entities.TaxLotSelection
+	if err := r.db.SelectContext(ctx, &selections, query, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get tax lot selections: %w", err)
+	}
+	return selections, nil
+}
+
+// ReplaceSelections replaces the lots chosen for a sell order
+func (r *TaxLotRepository) ReplaceSelections(ctx context.Context, orderID uuid.UUID, selections []*entities.TaxLotSelection) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tax_lot_selections WHERE order_id = $1`, orderID); err != nil {
+		return fmt.Errorf("failed to clear tax lot selections: %w", err)
+	}
+	for _, selection := range selections {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO tax_lot_selections (order_id, lot_id, quantity) VALUES ($1, $2, $3)`,
+			orderID, selection.LotID, selection.Quantity); err != nil {
+			return fmt.Errorf("failed to save tax lot selection: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetHarvestingUserIDs returns users who opted in to tax-loss harvesting and still hold lots
+func (r *TaxLotRepository) GetHarvestingUserIDs(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT s.user_id
+		FROM tax_lot_settings s
+		WHERE s.harvesting_enabled
+		  AND EXISTS (SELECT 1 FROM tax_lots l WHERE l.user_id = s.user_id AND l.remaining_quantity > 0)
+		ORDER BY s.user_id
+		LIMIT $1
+	`
+	var userIDs []uuid.UUID
+	if err := r.db.SelectContext(ctx, &userIDs, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get harvesting users: %w", err)
+	}
+	return userIDs, nil
+}
+
+const taxLossHarvestColumns = `
+	id, user_id, symbol, substitute_symbol, quantity, cost_basis, market_value, harvested_loss,
+	sell_order_id, buy_order_id, status, error, wash_window_ends_at, created_at, updated_at`
+
+// CreateHarvest stores a tax-loss harvest
+func (r *TaxLotRepository) CreateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error {
+	query := `
+		INSERT INTO tax_loss_harvests (` + taxLossHarvestColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		harvest.ID, harvest.UserID, harvest.Symbol, harvest.SubstituteSymbol, harvest.Quantity, harvest.CostBasis,
+		harvest.MarketValue, harvest.HarvestedLoss, harvest.SellOrderID, harvest.BuyOrderID, harvest.Status,
+		harvest.Error, harvest.WashWindowEndsAt, harvest.CreatedAt, harvest.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create tax loss harvest: %w", err)
+	}
+	return nil
+}
+
+// UpdateHarvest saves a harvest's orders and status
+func (r *TaxLotRepository) UpdateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error {
+	query := `
+		UPDATE tax_loss_harvests
+		SET sell_order_id = $2, buy_order_id = $3, status = $4, error = $5, updated_at = $6
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		harvest.ID, harvest.SellOrderID, harvest.BuyOrderID, harvest.Status, harvest.Error, harvest.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update tax loss harvest: %w", err)
+	}
+	return nil
+}
+
+// GetActiveHarvests returns a user's harvests whose wash sale window is still open at a time
+func (r *TaxLotRepository) GetActiveHarvests(ctx context.Context, userID uuid.UUID, at time.Time) ([]*entities.TaxLossHarvest, error) {
+	query := `
+		SELECT ` + taxLossHarvestColumns + `
+		FROM tax_loss_harvests
+		WHERE user_id = $1 AND wash_window_ends_at > $2 AND status <> 'failed'
+		ORDER BY created_at DESC
+	`
+	var harvests []*entities.TaxLossHarvest
+	if err := r.db.SelectContext(ctx, &harvests, query, userID, at); err != nil {
+		return nil, fmt.Errorf("failed to get active tax loss harvests: %w", err)
+	}
+	return harvests, nil
+}
+
+// GetHarvests returns a user's harvests created in [from, to)
+func (r *TaxLotRepository) GetHarvests(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.TaxLossHarvest, error) {
+	query := `
+		SELECT ` + taxLossHarvestColumns + `
+		FROM tax_loss_harvests
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at
+	`
+	var harvests []*entities.TaxLossHarvest
+	if err := r.db.SelectContext(ctx, &harvests, query, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get tax loss harvests: %w", err)
+	}
+	return harvests, nil
+}
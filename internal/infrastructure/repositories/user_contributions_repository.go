@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/shopspring/decimal"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -80,6 +81,18 @@ func (r *UserContributionsRepository) Create(ctx context.Context, contribution *
 	return nil
 }
 
+// RecordContribution records a contribution of the given type for the activity timeline
+func (r *UserContributionsRepository) RecordContribution(ctx context.Context, userID uuid.UUID, contributionType entities.ContributionType, amount decimal.Decimal, source string) error {
+	return r.Create(ctx, &entities.UserContribution{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      contributionType,
+		Amount:    amount,
+		Source:    source,
+		CreatedAt: time.Now(),
+	})
+}
+
 // GetByID retrieves a user contribution by ID
 func (r *UserContributionsRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.UserContribution, error) {
 	ctx, span := r.tracer.Start(ctx, "repository.get_user_contribution_by_id", trace.WithAttributes(
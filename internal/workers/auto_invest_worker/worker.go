@@ -0,0 +1,85 @@
+package auto_invest_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/autoinvest"
+	"go.uber.org/zap"
+)
+
+// Config holds deferred auto-investment worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default deferred auto-investment worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  5 * time.Minute,
+		BatchSize: 500,
+	}
+}
+
+// Worker runs auto-investments deferred while the market was closed once it opens
+type Worker struct {
+	autoInvestService *autoinvest.Service
+	config            Config
+	logger            *zap.Logger
+	stopCh            chan struct{}
+}
+
+func NewWorker(
+	autoInvestService *autoinvest.Service,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		autoInvestService: autoInvestService,
+		config:            config,
+		logger:            logger,
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting deferred auto-investment worker", zap.Duration("interval", w.config.Interval))
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Deferred auto-investment worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Deferred auto-investment worker stopped")
+			return
+		case <-ticker.C:
+			w.process(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) process(ctx context.Context) {
+	if w.autoInvestService == nil {
+		return
+	}
+
+	triggered, err := w.autoInvestService.ProcessDeferredInvestments(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to process deferred auto-investments", zap.Error(err))
+		return
+	}
+	if triggered > 0 {
+		w.logger.Info("Deferred auto-investments triggered", zap.Int("triggered", triggered))
+	}
+}
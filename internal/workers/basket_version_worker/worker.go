@@ -0,0 +1,106 @@
+package basket_version_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/investing"
+	"go.uber.org/zap"
+)
+
+// Config holds basket version worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default basket version worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  5 * time.Minute,
+		BatchSize: 100,
+	}
+}
+
+// MarketHours reports whether the exchange is trading
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+}
+
+// Worker activates scheduled basket compositions and migrates holders to them
+type Worker struct {
+	catalog     *investing.BasketCatalogService
+	marketHours MarketHours
+	config      Config
+	logger      *zap.Logger
+	stopCh      chan struct{}
+}
+
+func NewWorker(
+	catalog *investing.BasketCatalogService,
+	marketHours MarketHours,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		catalog:     catalog,
+		marketHours: marketHours,
+		config:      config,
+		logger:      logger,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting basket version worker", zap.Duration("interval", w.config.Interval))
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Basket version worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Basket version worker stopped")
+			return
+		case <-ticker.C:
+			w.process(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) process(ctx context.Context) {
+	if w.catalog == nil {
+		return
+	}
+
+	activated, err := w.catalog.ActivateDueVersions(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to activate scheduled basket versions", zap.Error(err))
+	} else if activated > 0 {
+		w.logger.Info("Scheduled basket versions activated", zap.Int("activated", activated))
+	}
+
+	// Versions activate on schedule, but holders are only migrated while the market is open so
+	// their rebalancing trades fill
+	if w.marketHours != nil && !w.marketHours.IsOpen(time.Now()) {
+		return
+	}
+
+	migrated, err := w.catalog.MigrateHolders(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to migrate basket holders", zap.Error(err))
+		return
+	}
+	if migrated > 0 {
+		w.logger.Info("Basket holders migrated", zap.Int("migrated", migrated))
+	}
+}
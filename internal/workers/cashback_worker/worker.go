@@ -0,0 +1,88 @@
+package cashback_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/rewards"
+	"go.uber.org/zap"
+)
+
+// Config holds cashback payout worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default cashback payout worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  15 * time.Minute,
+		BatchSize: 500,
+	}
+}
+
+// Worker releases cleared cashback and pays it into users' stash balances
+type Worker struct {
+	rewardsService *rewards.Service
+	config         Config
+	logger         *zap.Logger
+	stopCh         chan struct{}
+}
+
+func NewWorker(
+	rewardsService *rewards.Service,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		rewardsService: rewardsService,
+		config:         config,
+		logger:         logger,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting cashback payout worker")
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Cashback payout worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Cashback payout worker stopped")
+			return
+		case <-ticker.C:
+			w.releaseCashback(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) releaseCashback(ctx context.Context) {
+	if w.rewardsService == nil {
+		return
+	}
+
+	// Keep draining while full batches come back so a backlog clears in one tick
+	for {
+		paid, err := w.rewardsService.ReleaseClearedRewards(ctx, w.config.BatchSize)
+		if err != nil {
+			w.logger.Error("Failed to release cleared cashback", zap.Error(err))
+			return
+		}
+		if paid < w.config.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package conductor_performance_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+	"go.uber.org/zap"
+)
+
+// Config holds conductor performance worker settings
+type Config struct {
+	BatchSize int
+}
+
+// DefaultConfig returns the default conductor performance worker settings
+func DefaultConfig() Config {
+	return Config{
+		BatchSize: 500,
+	}
+}
+
+// closeSettleDelay is how long after the close the worker waits for closing prices to settle
+const closeSettleDelay = 30 * time.Minute
+
+// MarketCalendar reports when the exchange closes
+type MarketCalendar interface {
+	NextClose(t time.Time) time.Time
+}
+
+// Worker recomputes conductor performance metrics and snapshots once per trading day
+type Worker struct {
+	copyTradingService *copytrading.Service
+	calendar           MarketCalendar
+	config             Config
+	logger             *zap.Logger
+	stopCh             chan struct{}
+}
+
+func NewWorker(
+	copyTradingService *copytrading.Service,
+	calendar MarketCalendar,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		copyTradingService: copyTradingService,
+		calendar:           calendar,
+		config:             config,
+		logger:             logger,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting conductor performance worker")
+
+	nextRun := w.nextMarketClose()
+	timer := time.NewTimer(time.Until(nextRun))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Conductor performance worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Conductor performance worker stopped")
+			return
+		case <-timer.C:
+			w.computePerformance(ctx)
+			nextRun = w.nextMarketClose()
+			timer.Reset(time.Until(nextRun))
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) computePerformance(ctx context.Context) {
+	if w.copyTradingService == nil {
+		return
+	}
+
+	updated, err := w.copyTradingService.ProcessConductorPerformance(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to compute conductor performance", zap.Error(err))
+		return
+	}
+	w.logger.Info("Conductor performance computed", zap.Int("conductors", updated))
+}
+
+// nextMarketClose returns the time closing prices have settled after the next close on the
+// exchange calendar, skipping weekends and holidays
+func (w *Worker) nextMarketClose() time.Time {
+	// Look back by the settle delay so a run due shortly after today's close is not skipped
+	return w.calendar.NextClose(time.Now().Add(-closeSettleDelay)).Add(closeSettleDelay)
+}
@@ -0,0 +1,85 @@
+package copy_trading_fee_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+	"go.uber.org/zap"
+)
+
+// Config holds copy trading fee worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default copy trading fee worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  time.Hour,
+		BatchSize: 1000,
+	}
+}
+
+// Worker accrues and crystallizes conductor fees on drafts and pays conductors
+type Worker struct {
+	copyTradingService *copytrading.Service
+	config             Config
+	logger             *zap.Logger
+	stopCh             chan struct{}
+}
+
+func NewWorker(
+	copyTradingService *copytrading.Service,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		copyTradingService: copyTradingService,
+		config:             config,
+		logger:             logger,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting copy trading fee worker")
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Copy trading fee worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Copy trading fee worker stopped")
+			return
+		case <-ticker.C:
+			w.processFees(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) processFees(ctx context.Context) {
+	if w.copyTradingService == nil {
+		return
+	}
+
+	crystallized, err := w.copyTradingService.ProcessFees(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to process copy trading fees", zap.Error(err))
+		return
+	}
+	if crystallized > 0 {
+		w.logger.Info("Copy trading fees crystallized", zap.Int("drafts", crystallized))
+	}
+}
@@ -0,0 +1,90 @@
+package dividend_reinvest_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/dividend"
+	"go.uber.org/zap"
+)
+
+// Config holds dividend reinvestment worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default dividend reinvestment worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  time.Hour,
+		BatchSize: 500,
+	}
+}
+
+// Worker reinvests dividends whose withholding never arrived once the wait for it runs out
+type Worker struct {
+	dividendService *dividend.Service
+	config          Config
+	logger          *zap.Logger
+	stopCh          chan struct{}
+}
+
+func NewWorker(
+	dividendService *dividend.Service,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		dividendService: dividendService,
+		config:          config,
+		logger:          logger,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting dividend reinvestment worker")
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Dividend reinvestment worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Dividend reinvestment worker stopped")
+			return
+		case <-ticker.C:
+			w.reinvestAwaiting(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) reinvestAwaiting(ctx context.Context) {
+	if w.dividendService == nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-entities.DividendWithholdingWait)
+	// Keep draining while full batches come back so a backlog clears in one tick
+	for {
+		processed, err := w.dividendService.ReinvestAwaitingDividends(ctx, cutoff, w.config.BatchSize)
+		if err != nil {
+			w.logger.Error("Failed to reinvest dividends awaiting withholding", zap.Error(err))
+			return
+		}
+		if processed < w.config.BatchSize {
+			return
+		}
+	}
+}
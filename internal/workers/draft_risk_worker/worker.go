@@ -0,0 +1,98 @@
+package draft_risk_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+	"go.uber.org/zap"
+)
+
+// Config holds draft risk worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default draft risk worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  5 * time.Minute,
+		BatchSize: 500,
+	}
+}
+
+// MarketHours reports whether the exchange is trading
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+}
+
+// Worker checks drafts' max drawdown and trailing stops against current prices
+type Worker struct {
+	copyTradingService *copytrading.Service
+	marketHours        MarketHours
+	config             Config
+	logger             *zap.Logger
+	stopCh             chan struct{}
+}
+
+func NewWorker(
+	copyTradingService *copytrading.Service,
+	marketHours MarketHours,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		copyTradingService: copyTradingService,
+		marketHours:        marketHours,
+		config:             config,
+		logger:             logger,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting draft risk worker", zap.Duration("interval", w.config.Interval))
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Draft risk worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Draft risk worker stopped")
+			return
+		case <-ticker.C:
+			w.checkDrafts(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) checkDrafts(ctx context.Context) {
+	if w.copyTradingService == nil {
+		return
+	}
+
+	// Prices are stale and protective sells would only queue while the market is closed
+	if w.marketHours != nil && !w.marketHours.IsOpen(time.Now()) {
+		return
+	}
+
+	breached, err := w.copyTradingService.ProcessDraftRisk(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to check draft risk limits", zap.Error(err))
+		return
+	}
+	if breached > 0 {
+		w.logger.Info("Draft risk limits enforced", zap.Int("breached", breached))
+	}
+}
@@ -14,10 +14,16 @@ type AccountLister interface {
 	ListAllActiveUserIDs(ctx context.Context) ([]uuid.UUID, error)
 }
 
+// MarketCalendar reports when the exchange closes
+type MarketCalendar interface {
+	NextClose(t time.Time) time.Time
+}
+
 // Worker takes daily portfolio snapshots for all users
 type Worker struct {
 	analyticsService *analytics.PortfolioAnalyticsService
 	accountLister    AccountLister
+	calendar         MarketCalendar
 	logger           *zap.Logger
 	stopCh           chan struct{}
 }
@@ -25,11 +31,13 @@ type Worker struct {
 func NewWorker(
 	analyticsService *analytics.PortfolioAnalyticsService,
 	accountLister AccountLister,
+	calendar MarketCalendar,
 	logger *zap.Logger,
 ) *Worker {
 	return &Worker{
 		analyticsService: analyticsService,
 		accountLister:    accountLister,
+		calendar:         calendar,
 		logger:           logger,
 		stopCh:           make(chan struct{}),
 	}
@@ -39,7 +47,7 @@ func NewWorker(
 func (w *Worker) Start(ctx context.Context) {
 	w.logger.Info("Starting portfolio snapshot worker")
 
-	// Calculate time until next 4 PM ET (market close)
+	// Calculate time until the next market close
 	nextRun := w.nextMarketClose()
 	timer := time.NewTimer(time.Until(nextRun))
 	defer timer.Stop()
@@ -96,25 +104,10 @@ func (w *Worker) takeSnapshots(ctx context.Context) {
 		zap.Int("success", successCount))
 }
 
+// nextMarketClose returns the next close on the exchange calendar, which is 1 PM on early closes
+// and skips weekends and holidays
 func (w *Worker) nextMarketClose() time.Time {
-	// 4 PM ET = 21:00 UTC (during EST) or 20:00 UTC (during EDT)
-	loc, _ := time.LoadLocation("America/New_York")
-	now := time.Now().In(loc)
-
-	// Set to 4 PM today
-	marketClose := time.Date(now.Year(), now.Month(), now.Day(), 16, 0, 0, 0, loc)
-
-	// If we've passed 4 PM, schedule for tomorrow
-	if now.After(marketClose) {
-		marketClose = marketClose.AddDate(0, 0, 1)
-	}
-
-	// Skip weekends
-	for marketClose.Weekday() == time.Saturday || marketClose.Weekday() == time.Sunday {
-		marketClose = marketClose.AddDate(0, 0, 1)
-	}
-
-	return marketClose
+	return w.calendar.NextClose(time.Now())
 }
 
 // TakeSnapshotNow takes a snapshot immediately (for manual trigger)
@@ -0,0 +1,85 @@
+package risk_reassessment_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/riskprofile"
+	"go.uber.org/zap"
+)
+
+// Config holds risk reassessment worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default risk reassessment worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  6 * time.Hour,
+		BatchSize: 500,
+	}
+}
+
+// Worker prompts users whose risk assessments have expired to retake the questionnaire
+type Worker struct {
+	riskProfileService *riskprofile.Service
+	config             Config
+	logger             *zap.Logger
+	stopCh             chan struct{}
+}
+
+func NewWorker(
+	riskProfileService *riskprofile.Service,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		riskProfileService: riskProfileService,
+		config:             config,
+		logger:             logger,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting risk reassessment worker", zap.Duration("interval", w.config.Interval))
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Risk reassessment worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Risk reassessment worker stopped")
+			return
+		case <-ticker.C:
+			w.sendPrompts(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) sendPrompts(ctx context.Context) {
+	if w.riskProfileService == nil {
+		return
+	}
+
+	prompted, err := w.riskProfileService.SendReassessmentPrompts(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to send risk reassessment prompts", zap.Error(err))
+		return
+	}
+	if prompted > 0 {
+		w.logger.Info("Risk reassessment prompts sent", zap.Int("prompted", prompted))
+	}
+}
@@ -9,10 +9,17 @@ import (
 	"go.uber.org/zap"
 )
 
+// MarketHours reports whether the exchange is trading
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+	IsExtendedHours(t time.Time) bool
+}
+
 // Worker processes scheduled investments and market alerts
 type Worker struct {
 	scheduledService *investing.ScheduledInvestmentService
 	marketService    *market.MarketDataService
+	marketHours      MarketHours
 	logger           *zap.Logger
 	stopCh           chan struct{}
 }
@@ -20,11 +27,13 @@ type Worker struct {
 func NewWorker(
 	scheduledService *investing.ScheduledInvestmentService,
 	marketService *market.MarketDataService,
+	marketHours MarketHours,
 	logger *zap.Logger,
 ) *Worker {
 	return &Worker{
 		scheduledService: scheduledService,
 		marketService:    marketService,
+		marketHours:      marketHours,
 		logger:           logger,
 		stopCh:           make(chan struct{}),
 	}
@@ -42,6 +51,10 @@ func (w *Worker) Start(ctx context.Context) {
 	alertTicker := time.NewTicker(30 * time.Second)
 	defer alertTicker.Stop()
 
+	// Remind users of tomorrow's runs every hour
+	reminderTicker := time.NewTicker(1 * time.Hour)
+	defer reminderTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -54,6 +67,8 @@ func (w *Worker) Start(ctx context.Context) {
 			w.processScheduledInvestments(ctx)
 		case <-alertTicker.C:
 			w.checkMarketAlerts(ctx)
+		case <-reminderTicker.C:
+			w.sendReminders(ctx)
 		}
 	}
 }
@@ -73,11 +88,31 @@ func (w *Worker) processScheduledInvestments(ctx context.Context) {
 	}
 }
 
+func (w *Worker) sendReminders(ctx context.Context) {
+	if w.scheduledService == nil {
+		return
+	}
+
+	sent, err := w.scheduledService.SendUpcomingReminders(ctx)
+	if err != nil {
+		w.logger.Error("Failed to send scheduled investment reminders", zap.Error(err))
+		return
+	}
+	if sent > 0 {
+		w.logger.Info("Sent scheduled investment reminders", zap.Int("count", sent))
+	}
+}
+
 func (w *Worker) checkMarketAlerts(ctx context.Context) {
 	if w.marketService == nil {
 		return
 	}
 
+	// Prices only move while the market is trading, including extended hours
+	if now := time.Now(); w.marketHours != nil && !w.marketHours.IsOpen(now) && !w.marketHours.IsExtendedHours(now) {
+		return
+	}
+
 	if err := w.marketService.CheckAlerts(ctx); err != nil {
 		w.logger.Error("Failed to check market alerts", zap.Error(err))
 	}
@@ -0,0 +1,98 @@
+package tax_harvest_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rail-service/rail_service/internal/domain/services/tax"
+	"go.uber.org/zap"
+)
+
+// Config holds tax-loss harvest worker settings
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultConfig returns the default tax-loss harvest worker settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  6 * time.Hour,
+		BatchSize: 500,
+	}
+}
+
+// MarketHours reports whether the exchange is trading
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+}
+
+// Worker harvests unrealized losses for users who opted in and retries failed substitute buys
+type Worker struct {
+	harvesting  *tax.HarvestingService
+	marketHours MarketHours
+	config      Config
+	logger      *zap.Logger
+	stopCh      chan struct{}
+}
+
+func NewWorker(
+	harvesting *tax.HarvestingService,
+	marketHours MarketHours,
+	config Config,
+	logger *zap.Logger,
+) *Worker {
+	return &Worker{
+		harvesting:  harvesting,
+		marketHours: marketHours,
+		config:      config,
+		logger:      logger,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start begins the worker processing loop
+func (w *Worker) Start(ctx context.Context) {
+	w.logger.Info("Starting tax harvest worker", zap.Duration("interval", w.config.Interval))
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Tax harvest worker stopped (context cancelled)")
+			return
+		case <-w.stopCh:
+			w.logger.Info("Tax harvest worker stopped")
+			return
+		case <-ticker.C:
+			w.process(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) process(ctx context.Context) {
+	if w.harvesting == nil {
+		return
+	}
+
+	// Harvest sales and substitute buys only trade in regular hours; a closed-market tick is skipped
+	if w.marketHours != nil && !w.marketHours.IsOpen(time.Now()) {
+		return
+	}
+
+	harvested, err := w.harvesting.HarvestAll(ctx, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to harvest tax losses", zap.Error(err))
+		return
+	}
+	if harvested > 0 {
+		w.logger.Info("Tax losses harvested", zap.Int("harvests", harvested))
+	}
+}
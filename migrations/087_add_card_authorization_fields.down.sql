@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_card_transactions_card_created;
+DROP INDEX IF EXISTS idx_card_transactions_decline_code;
+
+ALTER TABLE card_transactions
+    DROP COLUMN IF EXISTS risk_score,
+    DROP COLUMN IF EXISTS decline_code;
@@ -0,0 +1,13 @@
+-- Structured authorization decisions on card transactions
+ALTER TABLE card_transactions
+    ADD COLUMN IF NOT EXISTS decline_code VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS risk_score DECIMAL(5, 4);
+
+CREATE INDEX IF NOT EXISTS idx_card_transactions_decline_code ON card_transactions(decline_code)
+    WHERE decline_code IS NOT NULL;
+
+-- Supports the per-card daily limit check
+CREATE INDEX IF NOT EXISTS idx_card_transactions_card_created ON card_transactions(card_id, created_at DESC);
+
+COMMENT ON COLUMN card_transactions.decline_code IS 'Structured decline reason code from the authorization pipeline';
+COMMENT ON COLUMN card_transactions.risk_score IS 'Fraud risk score (0-1) computed at authorization time';
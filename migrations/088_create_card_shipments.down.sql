@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS card_shipments_updated_at_trigger ON card_shipments;
+DROP INDEX IF EXISTS idx_card_shipments_status;
+DROP INDEX IF EXISTS idx_card_shipments_user_id;
+DROP TABLE IF EXISTS card_shipments;
+
+ALTER TABLE cards
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS replaces_card_id,
+    DROP COLUMN IF EXISTS activated_at;
@@ -0,0 +1,33 @@
+-- Physical card lifecycle: activation, replacement and shipment tracking
+ALTER TABLE cards
+    ADD COLUMN IF NOT EXISTS activated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS replaces_card_id UUID REFERENCES cards(id),
+    ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR(50);
+
+CREATE TABLE IF NOT EXISTS card_shipments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL UNIQUE REFERENCES cards(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    street_line_1 VARCHAR(255) NOT NULL,
+    street_line_2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100),
+    postal_code VARCHAR(20) NOT NULL,
+    country VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ordered' CHECK (status IN ('ordered', 'processing', 'shipped', 'delivered', 'returned', 'failed')),
+    carrier VARCHAR(50),
+    tracking_number VARCHAR(100),
+    tracking_url TEXT,
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_shipments_user_id ON card_shipments(user_id);
+CREATE INDEX idx_card_shipments_status ON card_shipments(status);
+
+CREATE TRIGGER card_shipments_updated_at_trigger
+    BEFORE UPDATE ON card_shipments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
@@ -0,0 +1,15 @@
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment'
+));
+
+DROP TABLE IF EXISTS card_dispute_evidence;
+DROP TRIGGER IF EXISTS card_disputes_updated_at_trigger ON card_disputes;
+DROP TABLE IF EXISTS card_disputes;
@@ -0,0 +1,56 @@
+-- Card transaction disputes with provisional credit tracking
+CREATE TABLE IF NOT EXISTS card_disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id UUID NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES card_transactions(id) ON DELETE CASCADE,
+    reason VARCHAR(50) NOT NULL CHECK (reason IN ('unauthorized', 'duplicate', 'not_received', 'not_as_described', 'incorrect_amount', 'cancelled_service', 'other')),
+    description TEXT NOT NULL,
+    amount DECIMAL(20, 8) NOT NULL,
+    currency VARCHAR(10) NOT NULL DEFAULT 'USD',
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted' CHECK (status IN ('submitted', 'under_review', 'won', 'lost')),
+    provisional_credit_status VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (provisional_credit_status IN ('none', 'posted', 'finalized', 'reversed', 'reversal_failed')),
+    provisional_credit_tx_id UUID,
+    clawback_tx_id UUID,
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Only one open dispute per transaction
+CREATE UNIQUE INDEX idx_card_disputes_open_transaction ON card_disputes(transaction_id)
+    WHERE status IN ('submitted', 'under_review');
+CREATE INDEX idx_card_disputes_user_id ON card_disputes(user_id, created_at DESC);
+CREATE INDEX idx_card_disputes_status ON card_disputes(status, created_at);
+
+CREATE TRIGGER card_disputes_updated_at_trigger
+    BEFORE UPDATE ON card_disputes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
+
+CREATE TABLE IF NOT EXISTS card_dispute_evidence (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dispute_id UUID NOT NULL REFERENCES card_disputes(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('receipt', 'correspondence', 'photo', 'other')),
+    description TEXT NOT NULL,
+    url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_dispute_evidence_dispute_id ON card_dispute_evidence(dispute_id);
+
+-- Allow dispute credits in the ledger (card_payment was also missing from the original constraint)
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute'
+));
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS card_controls_updated_at_trigger ON card_controls;
+DROP TABLE IF EXISTS card_controls;
@@ -0,0 +1,24 @@
+-- Per-card spending controls
+CREATE TABLE IF NOT EXISTS card_controls (
+    card_id UUID PRIMARY KEY REFERENCES cards(id) ON DELETE CASCADE,
+    per_transaction_limit DECIMAL(20, 8) CHECK (per_transaction_limit > 0),
+    daily_limit DECIMAL(20, 8) CHECK (daily_limit > 0),
+    monthly_limit DECIMAL(20, 8) CHECK (monthly_limit > 0),
+    allowed_mccs TEXT[] NOT NULL DEFAULT '{}',
+    blocked_mccs TEXT[] NOT NULL DEFAULT '{}',
+    merchant_locked BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_merchant VARCHAR(255),
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    online_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    atm_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    international_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_controls_single_use ON card_controls(card_id) WHERE single_use;
+
+CREATE TRIGGER card_controls_updated_at_trigger
+    BEFORE UPDATE ON card_controls
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
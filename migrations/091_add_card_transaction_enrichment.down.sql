@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS card_category_rules_updated_at_trigger ON card_category_rules;
+DROP TABLE IF EXISTS card_category_rules;
+
+DROP INDEX IF EXISTS idx_card_transactions_user_category;
+DROP INDEX IF EXISTS idx_card_transactions_user_merchant;
+
+ALTER TABLE card_transactions
+    DROP COLUMN IF EXISTS is_recurring,
+    DROP COLUMN IF EXISTS category_source,
+    DROP COLUMN IF EXISTS spending_category,
+    DROP COLUMN IF EXISTS merchant_key,
+    DROP COLUMN IF EXISTS merchant_display_name;
@@ -0,0 +1,30 @@
+-- Enriched merchant and category fields on card transactions
+ALTER TABLE card_transactions
+    ADD COLUMN IF NOT EXISTS merchant_display_name VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS merchant_key VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS spending_category VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS category_source VARCHAR(16)
+        CHECK (category_source IN ('mcc', 'merchant', 'rule', 'user')),
+    ADD COLUMN IF NOT EXISTS is_recurring BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_card_transactions_user_merchant
+    ON card_transactions(user_id, merchant_key, created_at DESC)
+    WHERE merchant_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_card_transactions_user_category
+    ON card_transactions(user_id, spending_category);
+
+-- Category overrides learned from user re-categorization
+CREATE TABLE IF NOT EXISTS card_category_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    merchant_key VARCHAR(255) NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, merchant_key)
+);
+
+CREATE TRIGGER card_category_rules_updated_at_trigger
+    BEFORE UPDATE ON card_category_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
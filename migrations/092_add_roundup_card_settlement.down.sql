@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_roundup_transactions_source;
+
+ALTER TABLE roundup_transactions
+    DROP COLUMN IF EXISTS ledger_transaction_id;
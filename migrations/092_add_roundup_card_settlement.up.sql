@@ -0,0 +1,8 @@
+-- Link round-ups to the ledger transfer that moved the spare change
+ALTER TABLE roundup_transactions
+    ADD COLUMN IF NOT EXISTS ledger_transaction_id UUID REFERENCES ledger_transactions(id);
+
+-- One round-up per source transaction
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roundup_transactions_source
+    ON roundup_transactions(user_id, source_type, source_ref)
+    WHERE source_ref IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_roundup_transactions_user_created;
+
+ALTER TABLE roundup_transactions
+    DROP COLUMN IF EXISTS skip_reason;
+
+ALTER TABLE roundup_settings
+    DROP COLUMN IF EXISTS skip_below_balance,
+    DROP COLUMN IF EXISTS low_balance_floor,
+    DROP COLUMN IF EXISTS boost_ends_at,
+    DROP COLUMN IF EXISTS boost_multiplier,
+    DROP COLUMN IF EXISTS monthly_cap,
+    DROP COLUMN IF EXISTS weekly_cap;
@@ -0,0 +1,15 @@
+-- Round-up caps, boosts and balance safeguards
+ALTER TABLE roundup_settings
+    ADD COLUMN IF NOT EXISTS weekly_cap DECIMAL(10, 2) CHECK (weekly_cap >= 0),
+    ADD COLUMN IF NOT EXISTS monthly_cap DECIMAL(10, 2) CHECK (monthly_cap >= 0),
+    ADD COLUMN IF NOT EXISTS boost_multiplier DECIMAL(3, 1) CHECK (boost_multiplier >= 1.0 AND boost_multiplier <= 10.0),
+    ADD COLUMN IF NOT EXISTS boost_ends_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS low_balance_floor DECIMAL(20, 2) CHECK (low_balance_floor >= 0),
+    ADD COLUMN IF NOT EXISTS skip_below_balance DECIMAL(20, 2) CHECK (skip_below_balance >= 0);
+
+ALTER TABLE roundup_transactions
+    ADD COLUMN IF NOT EXISTS skip_reason VARCHAR(32);
+
+-- Cap evaluation sums a user's recent round-ups
+CREATE INDEX IF NOT EXISTS idx_roundup_transactions_user_created
+    ON roundup_transactions(user_id, created_at);
@@ -0,0 +1,35 @@
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute'
+));
+
+DELETE FROM ledger_accounts WHERE account_type = 'system_rewards_funding';
+
+DROP INDEX IF EXISTS idx_ledger_accounts_system_type;
+CREATE UNIQUE INDEX idx_ledger_accounts_system_type ON ledger_accounts(account_type)
+    WHERE user_id IS NULL AND account_type IN ('system_buffer_usdc', 'system_buffer_fiat', 'broker_operational');
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational'
+));
+
+DROP TRIGGER IF EXISTS cashback_rewards_updated_at_trigger ON cashback_rewards;
+DROP TABLE IF EXISTS cashback_rewards;
+DROP TRIGGER IF EXISTS reward_offers_updated_at_trigger ON reward_offers;
+DROP TABLE IF EXISTS reward_offers;
@@ -0,0 +1,94 @@
+-- Cashback rewards: merchant/category offers and per-purchase accruals paid into the stash
+CREATE TABLE IF NOT EXISTS reward_offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    merchant_key VARCHAR(100),
+    category VARCHAR(30),
+    rate DECIMAL(10, 6) NOT NULL CHECK (rate > 0 AND rate <= 0.25),
+    per_transaction_cap DECIMAL(20, 8) CHECK (per_transaction_cap > 0),
+    monthly_cap DECIMAL(20, 8) CHECK (monthly_cap > 0),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_reward_offer_target CHECK ((merchant_key IS NULL) <> (category IS NULL))
+);
+
+CREATE INDEX idx_reward_offers_active ON reward_offers(active, starts_at) WHERE active = TRUE;
+
+CREATE TRIGGER reward_offers_updated_at_trigger
+    BEFORE UPDATE ON reward_offers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
+
+CREATE TABLE IF NOT EXISTS cashback_rewards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offer_id UUID NOT NULL REFERENCES reward_offers(id),
+    offer_name VARCHAR(100) NOT NULL,
+    card_transaction_id UUID NOT NULL REFERENCES card_transactions(id) ON DELETE CASCADE,
+    merchant_name VARCHAR(255),
+    merchant_key VARCHAR(100),
+    category VARCHAR(30),
+    purchase_amount DECIMAL(20, 8) NOT NULL,
+    rate DECIMAL(10, 6) NOT NULL,
+    amount DECIMAL(20, 8) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'available', 'paid', 'reversed')),
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    reversed_at TIMESTAMP WITH TIME ZONE,
+    ledger_transaction_id UUID,
+    reversal_ledger_transaction_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A purchase earns cashback at most once
+CREATE UNIQUE INDEX idx_cashback_rewards_card_transaction ON cashback_rewards(card_transaction_id);
+CREATE INDEX idx_cashback_rewards_user_id ON cashback_rewards(user_id, created_at DESC);
+CREATE INDEX idx_cashback_rewards_offer_month ON cashback_rewards(user_id, offer_id, created_at);
+CREATE INDEX idx_cashback_rewards_clearing ON cashback_rewards(status, available_at)
+    WHERE status IN ('pending', 'available');
+
+CREATE TRIGGER cashback_rewards_updated_at_trigger
+    BEFORE UPDATE ON cashback_rewards
+    FOR EACH ROW
+    EXECUTE FUNCTION update_cards_updated_at();
+
+-- Rewards funding pool (spending/stash balances were also missing from the original constraint)
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding'
+));
+
+DROP INDEX IF EXISTS idx_ledger_accounts_system_type;
+CREATE UNIQUE INDEX idx_ledger_accounts_system_type ON ledger_accounts(account_type)
+    WHERE user_id IS NULL AND account_type IN ('system_buffer_usdc', 'system_buffer_fiat', 'broker_operational', 'system_rewards_funding');
+
+INSERT INTO ledger_accounts (id, user_id, account_type, currency, balance) VALUES
+    (uuid_generate_v4(), NULL, 'system_rewards_funding', 'USD', 0)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute',
+    'cashback'
+));
@@ -0,0 +1,40 @@
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute',
+    'cashback'
+));
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding'
+));
+
+DROP INDEX IF EXISTS idx_conductor_payouts_conductor;
+DROP INDEX IF EXISTS idx_draft_fee_statements_conductor;
+DROP INDEX IF EXISTS idx_draft_fee_statements_draft;
+
+DROP TABLE IF EXISTS conductor_payouts;
+DROP TABLE IF EXISTS draft_fee_statements;
+
+ALTER TABLE drafts DROP COLUMN IF EXISTS last_crystallized_at;
+ALTER TABLE drafts DROP COLUMN IF EXISTS last_fee_accrual_at;
+ALTER TABLE drafts DROP COLUMN IF EXISTS accrued_fees;
+ALTER TABLE drafts DROP COLUMN IF EXISTS high_water_mark;
+
+ALTER TABLE conductors DROP COLUMN IF EXISTS management_fee_rate;
@@ -0,0 +1,75 @@
+-- Conductor fee schedule: fee_rate is the performance fee; management_fee_rate is an annual AUM fee
+ALTER TABLE conductors ADD COLUMN IF NOT EXISTS management_fee_rate DECIMAL(5, 4) NOT NULL DEFAULT 0;
+
+-- Per-draft fee state
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS high_water_mark DECIMAL(20, 8) NOT NULL DEFAULT 0;
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS accrued_fees DECIMAL(20, 8) NOT NULL DEFAULT 0;
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS last_fee_accrual_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS last_crystallized_at TIMESTAMP WITH TIME ZONE;
+
+-- Existing drafts start their high-water mark at the capital they linked with
+UPDATE drafts SET high_water_mark = start_value WHERE high_water_mark = 0;
+
+-- Fee statements: one row per crystallization of a draft's fees
+CREATE TABLE IF NOT EXISTS draft_fee_statements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    draft_id UUID NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+    drafter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    conductor_id UUID NOT NULL REFERENCES conductors(id) ON DELETE CASCADE,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    draft_value DECIMAL(20, 8) NOT NULL, -- Cash plus holdings at crystallization
+    opening_high_water_mark DECIMAL(20, 8) NOT NULL,
+    closing_high_water_mark DECIMAL(20, 8) NOT NULL,
+    performance_fee_rate DECIMAL(5, 4) NOT NULL,
+    management_fee DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    performance_fee DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    total_fee DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    amount_charged DECIMAL(20, 8) NOT NULL DEFAULT 0, -- May be below total_fee when draft cash is short
+    ledger_transaction_id UUID REFERENCES ledger_transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_fee_statement_amounts CHECK (amount_charged >= 0 AND amount_charged <= total_fee)
+);
+
+-- Conductor payouts: fees moved from the conductor payable account to the conductor's balance
+CREATE TABLE IF NOT EXISTS conductor_payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conductor_id UUID NOT NULL REFERENCES conductors(id) ON DELETE CASCADE,
+    amount DECIMAL(20, 8) NOT NULL,
+    ledger_transaction_id UUID REFERENCES ledger_transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_payout_amount_positive CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_draft_fee_statements_draft ON draft_fee_statements(draft_id, period_end DESC);
+CREATE INDEX IF NOT EXISTS idx_draft_fee_statements_conductor ON draft_fee_statements(conductor_id);
+CREATE INDEX IF NOT EXISTS idx_conductor_payouts_conductor ON conductor_payouts(conductor_id, created_at DESC);
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'conductor_payable',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding'
+));
+
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute',
+    'cashback',
+    'copy_trading_fee'
+));
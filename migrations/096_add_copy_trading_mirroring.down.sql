@@ -0,0 +1,10 @@
+DELETE FROM signal_execution_logs WHERE execution_type = 'catch_up';
+ALTER TABLE signal_execution_logs DROP CONSTRAINT IF EXISTS check_execution_signal;
+ALTER TABLE signal_execution_logs DROP COLUMN IF EXISTS side;
+ALTER TABLE signal_execution_logs DROP COLUMN IF EXISTS asset_ticker;
+ALTER TABLE signal_execution_logs DROP COLUMN IF EXISTS execution_type;
+ALTER TABLE signal_execution_logs ALTER COLUMN signal_id SET NOT NULL;
+
+ALTER TABLE drafts DROP CONSTRAINT IF EXISTS check_draft_mirror_mode;
+ALTER TABLE drafts DROP COLUMN IF EXISTS track_id;
+ALTER TABLE drafts DROP COLUMN IF EXISTS mirror_mode;
@@ -0,0 +1,19 @@
+-- Drafts can mirror the conductor's portfolio (or one of their tracks) instead of only copying signals
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS mirror_mode VARCHAR(16) NOT NULL DEFAULT 'signals';
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS track_id UUID REFERENCES tracks(id) ON DELETE SET NULL;
+ALTER TABLE drafts ADD CONSTRAINT check_draft_mirror_mode CHECK (mirror_mode IN ('signals', 'portfolio'));
+
+-- Catch-up orders are logged alongside copied signals but have no signal of their own
+ALTER TABLE signal_execution_logs ALTER COLUMN signal_id DROP NOT NULL;
+ALTER TABLE signal_execution_logs ADD COLUMN IF NOT EXISTS execution_type VARCHAR(16) NOT NULL DEFAULT 'signal';
+ALTER TABLE signal_execution_logs ADD COLUMN IF NOT EXISTS asset_ticker VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE signal_execution_logs ADD COLUMN IF NOT EXISTS side VARCHAR(8) NOT NULL DEFAULT '';
+
+UPDATE signal_execution_logs l
+SET asset_ticker = s.asset_ticker, side = s.side
+FROM signals s
+WHERE s.id = l.signal_id AND l.asset_ticker = '';
+
+ALTER TABLE signal_execution_logs ADD CONSTRAINT check_execution_signal CHECK (
+    (execution_type = 'signal' AND signal_id IS NOT NULL) OR execution_type = 'catch_up'
+);
@@ -0,0 +1,4 @@
+ALTER TABLE signal_execution_logs DROP COLUMN IF EXISTS block_order_id;
+
+DROP INDEX IF EXISTS idx_copy_trading_block_orders_signal;
+DROP TABLE IF EXISTS copy_trading_block_orders;
@@ -0,0 +1,20 @@
+-- Block orders: one firm-account order per signal, allocated back to each draft by journal
+CREATE TABLE IF NOT EXISTS copy_trading_block_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    signal_id UUID NOT NULL REFERENCES signals(id) ON DELETE CASCADE,
+    asset_ticker VARCHAR(16) NOT NULL,
+    side VARCHAR(8) NOT NULL,
+    requested_quantity DECIMAL(20, 8) NOT NULL, -- Sum of the drafts' quantities
+    filled_quantity DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    average_price DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    draft_count INTEGER NOT NULL,
+    order_id VARCHAR(128), -- Firm account order reference
+    status VARCHAR(32) NOT NULL DEFAULT 'pending', -- pending, filled, partially_filled, failed
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_copy_trading_block_orders_signal ON copy_trading_block_orders(signal_id);
+
+ALTER TABLE signal_execution_logs ADD COLUMN IF NOT EXISTS block_order_id UUID REFERENCES copy_trading_block_orders(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+DELETE FROM signal_execution_logs WHERE execution_type = 'liquidation';
+ALTER TABLE signal_execution_logs DROP CONSTRAINT IF EXISTS check_execution_signal;
+ALTER TABLE signal_execution_logs ADD CONSTRAINT check_execution_signal CHECK (
+    (execution_type = 'signal' AND signal_id IS NOT NULL) OR execution_type = 'catch_up'
+);
+
+DROP INDEX IF EXISTS idx_drafts_risk_monitored;
+
+ALTER TABLE drafts DROP CONSTRAINT IF EXISTS check_draft_risk_limits;
+ALTER TABLE drafts DROP CONSTRAINT IF EXISTS check_draft_risk_breach_action;
+ALTER TABLE drafts DROP COLUMN IF EXISTS peak_value;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_breach_reason;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_breach_action;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_max_trade_value;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_max_concentration;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_trailing_stop;
+ALTER TABLE drafts DROP COLUMN IF EXISTS risk_max_drawdown;
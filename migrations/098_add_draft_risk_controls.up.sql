@@ -0,0 +1,27 @@
+-- Per-draft risk limits, enforced on each signal and by the risk monitor on price moves
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_max_drawdown DECIMAL(5, 4); -- Fall below start value that triggers the breach action
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_trailing_stop DECIMAL(5, 4); -- Fall below peak value that triggers the breach action
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_max_concentration DECIMAL(5, 4); -- Largest share of draft value in one asset
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_max_trade_value DECIMAL(20, 8); -- Largest copied buy in USD
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_breach_action VARCHAR(16) NOT NULL DEFAULT 'pause';
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS risk_breach_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS peak_value DECIMAL(20, 8) NOT NULL DEFAULT 0;
+
+UPDATE drafts SET peak_value = GREATEST(start_value, current_aum) WHERE peak_value = 0;
+
+ALTER TABLE drafts ADD CONSTRAINT check_draft_risk_breach_action CHECK (risk_breach_action IN ('pause', 'unlink'));
+ALTER TABLE drafts ADD CONSTRAINT check_draft_risk_limits CHECK (
+    (risk_max_drawdown IS NULL OR (risk_max_drawdown > 0 AND risk_max_drawdown <= 1)) AND
+    (risk_trailing_stop IS NULL OR (risk_trailing_stop > 0 AND risk_trailing_stop <= 1)) AND
+    (risk_max_concentration IS NULL OR (risk_max_concentration > 0 AND risk_max_concentration <= 1)) AND
+    (risk_max_trade_value IS NULL OR risk_max_trade_value > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drafts_risk_monitored ON drafts(id)
+    WHERE status = 'active' AND (risk_max_drawdown IS NOT NULL OR risk_trailing_stop IS NOT NULL);
+
+-- Liquidations on a risk breach are logged like catch-up orders, without a signal
+ALTER TABLE signal_execution_logs DROP CONSTRAINT IF EXISTS check_execution_signal;
+ALTER TABLE signal_execution_logs ADD CONSTRAINT check_execution_signal CHECK (
+    (execution_type = 'signal' AND signal_id IS NOT NULL) OR execution_type IN ('catch_up', 'liquidation')
+);
@@ -0,0 +1,10 @@
+ALTER TABLE signals DROP COLUMN IF EXISTS track_version;
+ALTER TABLE signals DROP COLUMN IF EXISTS track_id;
+
+DROP TABLE IF EXISTS track_versions;
+
+-- Keep only each track's current allocations
+DELETE FROM track_allocations a USING tracks t WHERE a.track_id = t.id AND a.version <> t.version;
+DROP INDEX IF EXISTS idx_track_allocations_version;
+ALTER TABLE track_allocations DROP COLUMN IF EXISTS version;
+ALTER TABLE tracks DROP COLUMN IF EXISTS version;
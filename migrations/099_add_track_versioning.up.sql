@@ -0,0 +1,23 @@
+-- Track allocations are versioned: each change adds a new set of rows instead of replacing the old
+ALTER TABLE tracks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE track_allocations ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_track_allocations_version ON track_allocations(track_id, version);
+
+-- Track Versions: History of a track's allocation changes and the signals that propagated them
+CREATE TABLE IF NOT EXISTS track_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    track_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    signal_id UUID REFERENCES signals(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_track_versions UNIQUE (track_id, version)
+);
+
+INSERT INTO track_versions (track_id, version, created_at)
+SELECT id, 1, created_at FROM tracks
+ON CONFLICT (track_id, version) DO NOTHING;
+
+-- REBALANCE signals reference the track version followers should move to
+ALTER TABLE signals ADD COLUMN IF NOT EXISTS track_id UUID REFERENCES tracks(id) ON DELETE CASCADE;
+ALTER TABLE signals ADD COLUMN IF NOT EXISTS track_version INTEGER;
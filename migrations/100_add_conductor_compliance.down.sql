@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_signals_conductor_created;
+DROP TABLE IF EXISTS signal_blackout_windows;
+DROP TABLE IF EXISTS conductor_compliance_events;
@@ -0,0 +1,37 @@
+-- Conductor Compliance Events: Conductor trades blocked from being copied or flagged for review
+CREATE TABLE IF NOT EXISTS conductor_compliance_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conductor_id UUID NOT NULL REFERENCES conductors(id) ON DELETE CASCADE,
+    signal_id UUID REFERENCES signals(id) ON DELETE SET NULL,
+    event_type VARCHAR(32) NOT NULL, -- restricted_asset, blackout, frequency_limit, front_running, suspended
+    asset_ticker VARCHAR(16) NOT NULL DEFAULT '',
+    side VARCHAR(8) NOT NULL DEFAULT '',
+    trade_value DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_compliance_event_type CHECK (
+        event_type IN ('restricted_asset', 'blackout', 'frequency_limit', 'front_running', 'suspended')
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_events_conductor ON conductor_compliance_events(conductor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_compliance_events_created ON conductor_compliance_events(created_at);
+
+-- Signal Blackout Windows: Periods when conductor trades are not copied
+CREATE TABLE IF NOT EXISTS signal_blackout_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conductor_id UUID REFERENCES conductors(id) ON DELETE CASCADE, -- NULL applies to every conductor
+    asset_ticker VARCHAR(16) NOT NULL DEFAULT '', -- Empty covers every asset
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_blackout_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blackout_windows_period ON signal_blackout_windows(ends_at, starts_at);
+
+-- Frequency limits count a conductor's recent signals
+CREATE INDEX IF NOT EXISTS idx_signals_conductor_created ON signals(conductor_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS strategy_assignments;
+DROP TABLE IF EXISTS model_portfolio_versions;
+DROP TABLE IF EXISTS model_portfolios;
@@ -0,0 +1,71 @@
+-- Model Portfolios: Strategies the auto-investment engine assigns to users
+CREATE TABLE IF NOT EXISTS model_portfolios (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_model_portfolios_status CHECK (status IN ('active', 'retired'))
+);
+
+-- Model Portfolio Versions: Allocations and selection criteria, effective from a point in time
+CREATE TABLE IF NOT EXISTS model_portfolio_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    portfolio_id UUID NOT NULL REFERENCES model_portfolios(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    criteria JSONB NOT NULL DEFAULT '{}',
+    allocations JSONB NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_model_portfolio_versions UNIQUE (portfolio_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_model_portfolio_versions_effective ON model_portfolio_versions(portfolio_id, effective_from DESC);
+
+-- Strategy Assignments: The model portfolio version used for each auto-investment
+CREATE TABLE IF NOT EXISTS strategy_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    portfolio_id UUID REFERENCES model_portfolios(id) ON DELETE SET NULL,
+    portfolio_version_id UUID REFERENCES model_portfolio_versions(id) ON DELETE SET NULL,
+    portfolio_name VARCHAR(200) NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    correlation_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(20, 8) NOT NULL,
+    signals JSONB NOT NULL DEFAULT '{}',
+    allocations JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_strategy_assignments UNIQUE (user_id, correlation_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_strategy_assignments_user ON strategy_assignments(user_id, created_at DESC);
+
+-- Seed the portfolios the engine previously hard-coded, selected by age band
+WITH seed (slug, name, description, priority, criteria, allocations) AS (
+    VALUES
+        ('global-fallback', 'Global Fallback', 'Broad market default for users without matching signals', 0,
+         '{}'::jsonb,
+         '[{"symbol":"SPY","weight":"60"},{"symbol":"QQQ","weight":"25"},{"symbol":"BND","weight":"15"}]'::jsonb),
+        ('aggressive-growth', 'Aggressive Growth', 'Higher tech and growth exposure for young investors', 10,
+         '{"min_age":18,"max_age":25}'::jsonb,
+         '[{"symbol":"QQQ","weight":"40"},{"symbol":"SPY","weight":"35"},{"symbol":"VUG","weight":"25"}]'::jsonb),
+        ('balanced-growth', 'Balanced Growth', 'Balanced between growth and stability', 10,
+         '{"min_age":26,"max_age":40}'::jsonb,
+         '[{"symbol":"SPY","weight":"50"},{"symbol":"QQQ","weight":"30"},{"symbol":"BND","weight":"20"}]'::jsonb),
+        ('conservative', 'Conservative', 'Focus on stability and income', 10,
+         '{"min_age":41}'::jsonb,
+         '[{"symbol":"SPY","weight":"40"},{"symbol":"BND","weight":"35"},{"symbol":"VYM","weight":"25"}]'::jsonb)
+), portfolios AS (
+    INSERT INTO model_portfolios (slug, name, description)
+    SELECT slug, name, description FROM seed
+    ON CONFLICT (slug) DO NOTHING
+    RETURNING id, slug
+)
+INSERT INTO model_portfolio_versions (portfolio_id, version, effective_from, priority, criteria, allocations, notes)
+SELECT p.id, 1, NOW(), s.priority, s.criteria, s.allocations, 'Initial version'
+FROM portfolios p JOIN seed s ON s.slug = p.slug;
@@ -0,0 +1,4 @@
+ALTER TABLE model_portfolio_versions DROP COLUMN IF EXISTS risk_band;
+DROP TABLE IF EXISTS risk_acknowledgments;
+DROP TABLE IF EXISTS risk_assessments;
+DROP TABLE IF EXISTS risk_questionnaires;
@@ -0,0 +1,107 @@
+-- Risk Questionnaires: Versioned risk profiling questions and score-to-band thresholds
+CREATE TABLE IF NOT EXISTS risk_questionnaires (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version INTEGER NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    questions JSONB NOT NULL,
+    bands JSONB NOT NULL,
+    reassess_after_days INTEGER NOT NULL DEFAULT 365,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_risk_questionnaires_status CHECK (status IN ('active', 'retired'))
+);
+
+-- Only one questionnaire version is answered at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_questionnaires_active ON risk_questionnaires(status) WHERE status = 'active';
+
+-- Risk Assessments: A user's scored answers; the latest one is their current risk profile
+CREATE TABLE IF NOT EXISTS risk_assessments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    questionnaire_id UUID NOT NULL REFERENCES risk_questionnaires(id),
+    questionnaire_version INTEGER NOT NULL,
+    answers JSONB NOT NULL,
+    score INTEGER NOT NULL,
+    risk_band VARCHAR(20) NOT NULL,
+    time_horizon_years INTEGER,
+    reassess_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prompted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_risk_assessments_band CHECK (risk_band IN ('conservative', 'moderate', 'balanced', 'growth', 'aggressive'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_user ON risk_assessments(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_reassess ON risk_assessments(reassess_at) WHERE prompted_at IS NULL;
+
+-- Risk Acknowledgments: Users accepting investments riskier than their assessed band
+CREATE TABLE IF NOT EXISTS risk_acknowledgments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assessment_id UUID NOT NULL REFERENCES risk_assessments(id) ON DELETE CASCADE,
+    product VARCHAR(32) NOT NULL, -- auto_invest, basket, copy_trading
+    resource_id UUID, -- Basket or conductor; NULL for standing auto-invest acknowledgments
+    product_risk_band VARCHAR(20) NOT NULL,
+    user_risk_band VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_acknowledgments_lookup ON risk_acknowledgments(user_id, assessment_id, product);
+
+-- Model portfolios carry a risk band so unsuitable ones are not assigned
+ALTER TABLE model_portfolio_versions ADD COLUMN IF NOT EXISTS risk_band VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE model_portfolio_versions v
+SET risk_band = CASE p.slug
+        WHEN 'global-fallback' THEN 'balanced'
+        WHEN 'aggressive-growth' THEN 'aggressive'
+        WHEN 'balanced-growth' THEN 'balanced'
+        WHEN 'conservative' THEN 'conservative'
+    END
+FROM model_portfolios p
+WHERE p.id = v.portfolio_id AND v.risk_band = ''
+  AND p.slug IN ('global-fallback', 'aggressive-growth', 'balanced-growth', 'conservative');
+
+-- Seed the first questionnaire: five questions scored 0-4, so totals range 0-20
+INSERT INTO risk_questionnaires (version, status, questions, bands, reassess_after_days)
+VALUES (1, 'active',
+'[
+  {"key":"horizon","text":"When do you expect to need most of the money you invest?","options":[
+    {"value":"lt_3","label":"Within 3 years","score":0,"horizon_years":2},
+    {"value":"3_5","label":"In 3 to 5 years","score":1,"horizon_years":4},
+    {"value":"5_10","label":"In 5 to 10 years","score":2,"horizon_years":7},
+    {"value":"10_20","label":"In 10 to 20 years","score":3,"horizon_years":15},
+    {"value":"gt_20","label":"More than 20 years from now","score":4,"horizon_years":25}]},
+  {"key":"drawdown_reaction","text":"If your investments fell 20% in a month, what would you do?","options":[
+    {"value":"sell_all","label":"Sell everything","score":0},
+    {"value":"sell_some","label":"Sell some","score":1},
+    {"value":"hold","label":"Do nothing","score":2},
+    {"value":"buy_some","label":"Buy a little more","score":3},
+    {"value":"buy_more","label":"Buy a lot more","score":4}]},
+  {"key":"goal","text":"What is your main goal for this money?","options":[
+    {"value":"preserve","label":"Keep it safe","score":0},
+    {"value":"income","label":"Earn steady income","score":1},
+    {"value":"balanced","label":"Balance growth and safety","score":2},
+    {"value":"growth","label":"Grow it over time","score":3},
+    {"value":"max_growth","label":"Grow it as much as possible","score":4}]},
+  {"key":"experience","text":"How much investing experience do you have?","options":[
+    {"value":"none","label":"None","score":0},
+    {"value":"beginner","label":"I have invested a little","score":1},
+    {"value":"intermediate","label":"I invest regularly","score":2},
+    {"value":"experienced","label":"I have invested through several market downturns","score":3},
+    {"value":"expert","label":"I work in or closely follow financial markets","score":4}]},
+  {"key":"income_stability","text":"How stable is your income?","options":[
+    {"value":"very_unstable","label":"Very unstable","score":0},
+    {"value":"unstable","label":"Somewhat unstable","score":1},
+    {"value":"average","label":"Average","score":2},
+    {"value":"stable","label":"Stable","score":3},
+    {"value":"very_stable","label":"Very stable","score":4}]}
+]'::jsonb,
+'[
+  {"band":"conservative","min_score":0},
+  {"band":"moderate","min_score":5},
+  {"band":"balanced","min_score":9},
+  {"band":"growth","min_score":13},
+  {"band":"aggressive","min_score":17}
+]'::jsonb,
+365)
+ON CONFLICT (version) DO NOTHING;
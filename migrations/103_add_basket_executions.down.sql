@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS basket_execution_legs;
+DROP TABLE IF EXISTS basket_executions;
@@ -0,0 +1,41 @@
+-- Basket Executions: One basket investment split into per-symbol order legs
+CREATE TABLE IF NOT EXISTS basket_executions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    basket_id UUID REFERENCES baskets(id) ON DELETE SET NULL,
+    alpaca_account_id VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    total_amount DECIMAL(36, 18) NOT NULL,
+    submitted_amount DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    released_amount DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    status VARCHAR(30) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT uq_basket_executions_idempotency UNIQUE (user_id, idempotency_key),
+    CONSTRAINT chk_basket_executions_status CHECK (status IN ('pending', 'executing', 'completed', 'partially_completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_basket_executions_user ON basket_executions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_basket_executions_open ON basket_executions(status) WHERE status IN ('pending', 'executing');
+
+-- Basket Execution Legs: The order placed for each symbol, with its retry and release state
+CREATE TABLE IF NOT EXISTS basket_execution_legs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    execution_id UUID NOT NULL REFERENCES basket_executions(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    percentage DECIMAL(10, 4) NOT NULL,
+    amount DECIMAL(36, 18) NOT NULL,
+    client_order_id VARCHAR(128) NOT NULL UNIQUE,
+    alpaca_order_id VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    released_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_basket_execution_legs_symbol UNIQUE (execution_id, symbol),
+    CONSTRAINT chk_basket_execution_legs_status CHECK (status IN ('pending', 'submitted', 'failed', 'released'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_basket_execution_legs_execution ON basket_execution_legs(execution_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS basket_holder_migrations;
+DROP TABLE IF EXISTS basket_versions;
+ALTER TABLE baskets DROP COLUMN IF EXISTS current_version;
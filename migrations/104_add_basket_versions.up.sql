@@ -0,0 +1,58 @@
+-- Seed the curated baskets that were previously hard-coded in the basket executor
+INSERT INTO baskets (id, name, description, risk_level, composition_json)
+SELECT seed.id, seed.name, seed.description, seed.risk_level, seed.composition_json::jsonb
+FROM (VALUES
+    ('3b0f6a52-1d7e-4c8a-9f21-6e5d4c3b2a01'::uuid, 'Tech Growth', 'Large-cap technology leaders', 'growth',
+     '[{"symbol":"AAPL","weight":"0.20"},{"symbol":"MSFT","weight":"0.20"},{"symbol":"GOOGL","weight":"0.15"},{"symbol":"NVDA","weight":"0.15"},{"symbol":"TSLA","weight":"0.15"},{"symbol":"META","weight":"0.15"}]'),
+    ('3b0f6a52-1d7e-4c8a-9f21-6e5d4c3b2a02'::uuid, 'Sustainability', 'Clean energy funds and companies', 'growth',
+     '[{"symbol":"ICLN","weight":"0.30"},{"symbol":"TAN","weight":"0.25"},{"symbol":"TSLA","weight":"0.20"},{"symbol":"NEE","weight":"0.15"},{"symbol":"ENPH","weight":"0.10"}]'),
+    ('3b0f6a52-1d7e-4c8a-9f21-6e5d4c3b2a03'::uuid, 'Balanced ETF', 'Broad market index funds with a bond allocation', 'balanced',
+     '[{"symbol":"SPY","weight":"0.40"},{"symbol":"QQQ","weight":"0.30"},{"symbol":"VTI","weight":"0.20"},{"symbol":"AGG","weight":"0.10"}]')
+) AS seed(id, name, description, risk_level, composition_json)
+WHERE NOT EXISTS (SELECT 1 FROM baskets b WHERE b.name = seed.name);
+
+ALTER TABLE baskets ADD COLUMN IF NOT EXISTS current_version INTEGER NOT NULL DEFAULT 1;
+
+-- Basket Versions: Every composition a basket has had or is scheduled to have
+CREATE TABLE IF NOT EXISTS basket_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    basket_id UUID NOT NULL REFERENCES baskets(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    composition JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_basket_versions_version UNIQUE (basket_id, version),
+    CONSTRAINT chk_basket_versions_status CHECK (status IN ('scheduled', 'active', 'superseded', 'cancelled'))
+);
+
+-- A basket has one active composition and at most one pending change
+CREATE UNIQUE INDEX IF NOT EXISTS idx_basket_versions_active ON basket_versions(basket_id) WHERE status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_basket_versions_scheduled ON basket_versions(basket_id) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_basket_versions_due ON basket_versions(effective_at) WHERE status = 'scheduled';
+
+-- Existing compositions become version 1
+INSERT INTO basket_versions (basket_id, version, composition, status, effective_at, activated_at, created_at)
+SELECT b.id, 1, b.composition_json, 'active', b.created_at, b.created_at, b.created_at
+FROM baskets b
+WHERE NOT EXISTS (SELECT 1 FROM basket_versions v WHERE v.basket_id = b.id);
+
+-- Basket Holder Migrations: Trades moving a holder's position to a basket's new composition
+CREATE TABLE IF NOT EXISTS basket_holder_migrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    basket_id UUID NOT NULL REFERENCES baskets(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_version INTEGER NOT NULL,
+    to_version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    trades JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT uq_basket_holder_migrations UNIQUE (basket_id, user_id, to_version),
+    CONSTRAINT chk_basket_holder_migrations_status CHECK (status IN ('pending', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_basket_holder_migrations_pending ON basket_holder_migrations(created_at) WHERE status = 'pending';
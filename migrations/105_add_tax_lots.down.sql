@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tax_lot_selections;
+DROP TABLE IF EXISTS tax_lot_fills;
+DROP TABLE IF EXISTS realized_gains;
+DROP TABLE IF EXISTS tax_lots;
+DROP TABLE IF EXISTS tax_lot_settings;
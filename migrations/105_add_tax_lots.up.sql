@@ -0,0 +1,72 @@
+-- Tax Lot Settings: The lot relief method applied to a user's sales
+CREATE TABLE IF NOT EXISTS tax_lot_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL DEFAULT 'fifo',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tax_lot_settings_method CHECK (method IN ('fifo', 'specific_id', 'hifo'))
+);
+
+-- Tax Lots: Shares bought in one fill, with their adjusted cost basis and holding period start
+CREATE TABLE IF NOT EXISTS tax_lots (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    alpaca_order_id VARCHAR(100) NOT NULL,
+    quantity DECIMAL(36, 18) NOT NULL,
+    remaining_quantity DECIMAL(36, 18) NOT NULL,
+    cost_basis DECIMAL(36, 18) NOT NULL,
+    remaining_cost_basis DECIMAL(36, 18) NOT NULL,
+    wash_sale_adjustment DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    is_replacement BOOLEAN NOT NULL DEFAULT FALSE,
+    purchased_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tax_lots_remaining CHECK (remaining_quantity >= 0 AND remaining_quantity <= quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tax_lots_open ON tax_lots(user_id, symbol, purchased_at) WHERE remaining_quantity > 0;
+
+-- Realized Gains: Shares of a lot relieved by a sale, with any wash sale disallowance
+CREATE TABLE IF NOT EXISTS realized_gains (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lot_id UUID NOT NULL REFERENCES tax_lots(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    alpaca_order_id VARCHAR(100) NOT NULL,
+    quantity DECIMAL(36, 18) NOT NULL,
+    proceeds DECIMAL(36, 18) NOT NULL,
+    cost_basis DECIMAL(36, 18) NOT NULL,
+    gain_loss DECIMAL(36, 18) NOT NULL,
+    wash_sale_quantity DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    disallowed_loss DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    holding_period VARCHAR(20) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sold_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_realized_gains_holding_period CHECK (holding_period IN ('short_term', 'long_term'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_realized_gains_user_sold ON realized_gains(user_id, sold_at);
+CREATE INDEX IF NOT EXISTS idx_realized_gains_wash_candidates ON realized_gains(user_id, symbol, sold_at) WHERE gain_loss < 0;
+
+-- Tax Lot Fills: Cumulative quantity of each order already applied to lots
+CREATE TABLE IF NOT EXISTS tax_lot_fills (
+    alpaca_order_id VARCHAR(100) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(10) NOT NULL,
+    filled_qty DECIMAL(36, 18) NOT NULL,
+    filled_amount DECIMAL(36, 18) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Tax Lot Selections: Lots chosen for a sell order under the specific-id method
+CREATE TABLE IF NOT EXISTS tax_lot_selections (
+    order_id UUID NOT NULL,
+    lot_id UUID NOT NULL REFERENCES tax_lots(id) ON DELETE CASCADE,
+    quantity DECIMAL(36, 18) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, lot_id)
+);
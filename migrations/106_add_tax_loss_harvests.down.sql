@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tax_loss_harvests;
+DROP INDEX IF EXISTS idx_tax_lot_settings_harvesting;
+ALTER TABLE tax_lot_settings DROP COLUMN IF EXISTS harvesting_enabled;
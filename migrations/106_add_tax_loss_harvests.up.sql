@@ -0,0 +1,27 @@
+-- Tax-loss harvesting is opt-in per user
+ALTER TABLE tax_lot_settings ADD COLUMN IF NOT EXISTS harvesting_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_tax_lot_settings_harvesting ON tax_lot_settings(user_id) WHERE harvesting_enabled;
+
+-- Tax Loss Harvests: Loss lots sold and swapped into a substitute, with the wash sale window they open
+CREATE TABLE IF NOT EXISTS tax_loss_harvests (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    substitute_symbol VARCHAR(20) NOT NULL,
+    quantity DECIMAL(36, 18) NOT NULL,
+    cost_basis DECIMAL(36, 18) NOT NULL,
+    market_value DECIMAL(36, 18) NOT NULL,
+    harvested_loss DECIMAL(36, 18) NOT NULL,
+    sell_order_id UUID,
+    buy_order_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT,
+    wash_window_ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tax_loss_harvests_status CHECK (status IN ('pending', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tax_loss_harvests_user ON tax_loss_harvests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tax_loss_harvests_window ON tax_loss_harvests(user_id, wash_window_ends_at) WHERE status <> 'failed';
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_scheduled_investments_reminder;
+
+ALTER TABLE scheduled_investment_executions
+    DROP COLUMN IF EXISTS ledger_transaction_id,
+    DROP COLUMN IF EXISTS scheduled_for,
+    DROP COLUMN IF EXISTS reason;
+
+UPDATE scheduled_investment_executions SET status = 'failed' WHERE status = 'insufficient_funds';
+UPDATE scheduled_investment_executions SET status = 'skipped' WHERE status = 'deferred';
+ALTER TABLE scheduled_investment_executions ALTER COLUMN status TYPE VARCHAR(16);
+
+ALTER TABLE scheduled_investments
+    DROP COLUMN IF EXISTS reminder_sent_for,
+    DROP COLUMN IF EXISTS holiday_policy,
+    DROP COLUMN IF EXISTS catch_up_policy;
@@ -0,0 +1,14 @@
+-- Catch-up and holiday policies and reminder tracking for scheduled investments
+ALTER TABLE scheduled_investments
+    ADD COLUMN IF NOT EXISTS catch_up_policy VARCHAR(16) NOT NULL DEFAULT 'run_once', -- skip, run_once, run_all
+    ADD COLUMN IF NOT EXISTS holiday_policy VARCHAR(16) NOT NULL DEFAULT 'defer',     -- defer, skip
+    ADD COLUMN IF NOT EXISTS reminder_sent_for TIMESTAMP WITH TIME ZONE;
+
+-- Record the run each execution was for, why it was skipped or deferred, and the spend balance debit
+ALTER TABLE scheduled_investment_executions
+    ALTER COLUMN status TYPE VARCHAR(32), -- success, failed, skipped, deferred, insufficient_funds
+    ADD COLUMN IF NOT EXISTS reason TEXT,
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS ledger_transaction_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_investments_reminder ON scheduled_investments(next_execution_at, reminder_sent_for) WHERE status = 'active';
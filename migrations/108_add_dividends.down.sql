@@ -0,0 +1,47 @@
+DELETE FROM user_contributions WHERE type = 'dividend';
+ALTER TABLE user_contributions DROP CONSTRAINT IF EXISTS user_contributions_type_check;
+ALTER TABLE user_contributions ADD CONSTRAINT user_contributions_type_check CHECK (type IN ('deposit', 'roundup', 'cashback', 'referral'));
+
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute',
+    'cashback',
+    'copy_trading_fee'
+));
+
+DELETE FROM ledger_accounts WHERE user_id IS NULL AND account_type = 'broker_incoming';
+
+DROP INDEX IF EXISTS idx_ledger_accounts_system_type;
+CREATE UNIQUE INDEX idx_ledger_accounts_system_type ON ledger_accounts(account_type)
+    WHERE user_id IS NULL AND account_type IN ('system_buffer_usdc', 'system_buffer_fiat', 'broker_operational', 'system_rewards_funding');
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_balance_positive;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_balance_positive CHECK (balance >= 0);
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'conductor_payable',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding'
+));
+
+DROP INDEX IF EXISTS idx_dividends_user_symbol;
+DROP INDEX IF EXISTS idx_dividends_user_pay_date;
+
+DROP TABLE IF EXISTS dividends;
+DROP TABLE IF EXISTS dividend_settings;
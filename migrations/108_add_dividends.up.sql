@@ -0,0 +1,81 @@
+-- Dividend Settings: What each user does with dividend cash
+CREATE TABLE IF NOT EXISTS dividend_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reinvest_mode VARCHAR(20) NOT NULL DEFAULT 'off',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_dividend_settings_reinvest_mode CHECK (reinvest_mode IN ('off', 'same_asset', 'strategy'))
+);
+
+-- Dividends: One row per Alpaca DIV or DIVNRA activity posted to the user's buying power
+CREATE TABLE IF NOT EXISTS dividends (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id VARCHAR(100) NOT NULL UNIQUE,
+    activity_type VARCHAR(10) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    quantity DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    per_share_amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    gross_amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    withholding_amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(20, 8) NOT NULL, -- Negative for withholding
+    pay_date DATE NOT NULL,
+    ledger_transaction_id UUID REFERENCES ledger_transactions(id),
+    reinvest_status VARCHAR(20) NOT NULL DEFAULT 'not_reinvested',
+    reinvest_amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    reinvest_orders INTEGER NOT NULL DEFAULT 0,
+    reinvest_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_dividends_activity_type CHECK (activity_type IN ('DIV', 'DIVNRA'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_dividends_user_pay_date ON dividends(user_id, pay_date DESC);
+CREATE INDEX IF NOT EXISTS idx_dividends_user_symbol ON dividends(user_id, symbol, pay_date);
+
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS chk_transaction_type;
+ALTER TABLE ledger_transactions ADD CONSTRAINT chk_transaction_type CHECK (transaction_type IN (
+    'deposit',
+    'withdrawal',
+    'investment',
+    'conversion',
+    'internal_transfer',
+    'buffer_replenishment',
+    'reversal',
+    'card_payment',
+    'card_dispute',
+    'cashback',
+    'copy_trading_fee',
+    'dividend'
+));
+
+-- Dividend cash the broker pays in is booked against a clearing account, which runs negative by
+-- the amount received, rather than drawn from the pre-funded broker_operational pool
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'conductor_payable',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding',
+    'broker_incoming'
+));
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_balance_positive;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_balance_positive CHECK (balance >= 0 OR account_type = 'broker_incoming');
+
+DROP INDEX IF EXISTS idx_ledger_accounts_system_type;
+CREATE UNIQUE INDEX idx_ledger_accounts_system_type ON ledger_accounts(account_type)
+    WHERE user_id IS NULL AND account_type IN ('system_buffer_usdc', 'system_buffer_fiat', 'broker_operational', 'system_rewards_funding', 'broker_incoming');
+
+INSERT INTO ledger_accounts (id, user_id, account_type, currency, balance) VALUES
+    (uuid_generate_v4(), NULL, 'broker_incoming', 'USD', 0)
+ON CONFLICT DO NOTHING;
+
+-- Dividends appear on the activity timeline alongside deposits, round-ups and cashback
+ALTER TABLE user_contributions DROP CONSTRAINT IF EXISTS user_contributions_type_check;
+ALTER TABLE user_contributions ADD CONSTRAINT user_contributions_type_check CHECK (type IN ('deposit', 'roundup', 'cashback', 'referral', 'dividend'));
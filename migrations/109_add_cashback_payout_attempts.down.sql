@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cashback_rewards_clearing;
+CREATE INDEX idx_cashback_rewards_clearing ON cashback_rewards(status, available_at)
+    WHERE status IN ('pending', 'available');
+
+ALTER TABLE cashback_rewards DROP COLUMN IF EXISTS payout_attempted_at;
@@ -0,0 +1,6 @@
+-- Rewards the funding pool could not cover are retried after rewards that have not been attempted
+ALTER TABLE cashback_rewards ADD COLUMN IF NOT EXISTS payout_attempted_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_cashback_rewards_clearing;
+CREATE INDEX idx_cashback_rewards_clearing ON cashback_rewards(status, payout_attempted_at NULLS FIRST, available_at)
+    WHERE status IN ('pending', 'available');
@@ -0,0 +1,3 @@
+DELETE FROM model_portfolio_versions
+WHERE portfolio_id IN (SELECT id FROM model_portfolios WHERE slug = 'capital-preservation');
+DELETE FROM model_portfolios WHERE slug = 'capital-preservation';
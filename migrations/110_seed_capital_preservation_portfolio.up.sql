@@ -0,0 +1,13 @@
+-- Seed a conservative default for the bands the broad market default exceeds, so every
+-- assessed risk band has an in-band fallback
+WITH portfolio AS (
+    INSERT INTO model_portfolios (slug, name, description)
+    VALUES ('capital-preservation', 'Capital Preservation', 'Bond-led default for users whose risk band excludes the broad market default')
+    ON CONFLICT (slug) DO NOTHING
+    RETURNING id
+)
+INSERT INTO model_portfolio_versions (portfolio_id, version, effective_from, priority, risk_band, criteria, allocations, notes)
+SELECT id, 1, NOW(), 0, 'conservative', '{"risk_bands":["conservative","moderate"]}'::jsonb,
+       '[{"symbol":"BND","weight":"60"},{"symbol":"SPY","weight":"25"},{"symbol":"VYM","weight":"15"}]'::jsonb,
+       'Initial version'
+FROM portfolio;
@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS idx_dividends_awaiting_withholding;
+
+UPDATE dividends SET reinvest_status = 'not_reinvested' WHERE reinvest_status = 'awaiting_withholding';
+
+DELETE FROM ledger_accounts WHERE account_type = 'withholding_receivable';
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_balance_positive;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_balance_positive CHECK (balance >= 0 OR account_type = 'broker_incoming');
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'conductor_payable',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding',
+    'broker_incoming'
+));
@@ -0,0 +1,24 @@
+-- Dividend tax withheld after the dividend has been spent is owed by the user; the receivable runs
+-- negative by the amount owed
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_account_type;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_account_type CHECK (account_type IN (
+    'usdc_balance',
+    'fiat_exposure',
+    'pending_investment',
+    'spending_balance',
+    'stash_balance',
+    'conductor_payable',
+    'withholding_receivable',
+    'system_buffer_usdc',
+    'system_buffer_fiat',
+    'broker_operational',
+    'system_rewards_funding',
+    'broker_incoming'
+));
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS chk_balance_positive;
+ALTER TABLE ledger_accounts ADD CONSTRAINT chk_balance_positive CHECK (balance >= 0 OR account_type IN ('broker_incoming', 'withholding_receivable'));
+
+-- Dividends held for reinvestment until their withholding is known
+CREATE INDEX IF NOT EXISTS idx_dividends_awaiting_withholding ON dividends(created_at)
+    WHERE reinvest_status = 'awaiting_withholding';
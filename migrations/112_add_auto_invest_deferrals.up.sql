@@ -0,0 +1,11 @@
+-- Auto-investments triggered while the market is closed wait here, with the funds in the stash,
+-- until the next open
+CREATE TABLE IF NOT EXISTS auto_invest_deferrals (
+    correlation_id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    stash_id UUID NOT NULL,
+    not_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auto_invest_deferrals_not_before ON auto_invest_deferrals(not_before);
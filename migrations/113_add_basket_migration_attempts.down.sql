@@ -0,0 +1 @@
+ALTER TABLE basket_holder_migrations DROP COLUMN IF EXISTS attempts;
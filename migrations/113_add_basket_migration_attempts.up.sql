@@ -0,0 +1,3 @@
+-- Holder migrations that stop partway stay pending and resume with their unplaced trades; attempts
+-- counts the passes that stopped on a failed trade
+ALTER TABLE basket_holder_migrations ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_conductors_followers_id;
@@ -0,0 +1,2 @@
+-- Keyset pagination of active conductors by (followers_count, id)
+CREATE INDEX IF NOT EXISTS idx_conductors_followers_id ON conductors(followers_count DESC, id DESC) WHERE status = 'active';
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Global rewards funding metrics exported for use in DI container
+var (
+	RewardsFundingLowAlertsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "stack",
+			Subsystem: "rewards",
+			Name:      "funding_low_alerts_total",
+			Help:      "Total number of low rewards funding pool alerts",
+		},
+		[]string{"reason"}, // below_threshold or shortfall
+	)
+)
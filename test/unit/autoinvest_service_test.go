@@ -75,7 +75,13 @@ func (m *mockOrderPlacer) PlaceMarketOrder(ctx context.Context, userID uuid.UUID
 
 // mockStrategyEngine implements autoinvest.StrategyEngine for testing
 type mockStrategyEngine struct {
-	result *strategy.StrategyResult
+	result      *strategy.StrategyResult
+	assignments []string
+}
+
+func (m *mockStrategyEngine) RecordAssignment(ctx context.Context, userID uuid.UUID, correlationID string, amount decimal.Decimal, result *strategy.StrategyResult) error {
+	m.assignments = append(m.assignments, correlationID)
+	return nil
 }
 
 func (m *mockStrategyEngine) GetStrategy(ctx context.Context, userID uuid.UUID) (*strategy.StrategyResult, error) {
@@ -183,6 +189,7 @@ func TestAutoInvestService_WithStrategyEngine(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, orderPlacer.called, "OrderPlacer should have been called")
 	assert.Len(t, orderPlacer.orders, 2, "Should have placed 2 orders for 2 allocations")
+	assert.Equal(t, []string{"test-correlation-id"}, strategyEngine.assignments, "Strategy assignment should be recorded")
 
 	// Verify allocation amounts
 	var spyOrder, qqqOrder *orderPlacerCall
@@ -200,6 +207,115 @@ func TestAutoInvestService_WithStrategyEngine(t *testing.T) {
 	assert.True(t, qqqOrder.amount.Equal(decimal.NewFromFloat(40)), "QQQ should get 40% of 100 = 40")
 }
 
+// mockSuitabilityChecker rejects strategies riskier than a fixed band
+type mockSuitabilityChecker struct {
+	band entities.RiskBand
+}
+
+func (m *mockSuitabilityChecker) CheckSuitability(ctx context.Context, userID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID, productBand entities.RiskBand, acknowledged bool) error {
+	if productBand.Exceeds(m.band) && !acknowledged {
+		return entities.ErrRiskAcknowledgmentRequired
+	}
+	return nil
+}
+
+func TestAutoInvestService_StrategyAboveRiskBandRequiresAcknowledgment(t *testing.T) {
+	ledger := newMockLedgerService(decimal.NewFromFloat(100))
+	orderPlacer := &mockOrderPlacer{}
+	svc := autoinvest.NewService(ledger, orderPlacer, autoinvest.Config{}, testLogger())
+	svc.SetStrategyEngine(&mockStrategyEngine{result: &strategy.StrategyResult{
+		StrategyName: "Global Fallback",
+		Allocations:  []strategy.Allocation{{Symbol: "SPY", Weight: decimal.NewFromInt(100)}},
+		RiskBand:     entities.RiskBandBalanced,
+	}})
+	svc.SetSuitabilityChecker(&mockSuitabilityChecker{band: entities.RiskBandConservative})
+
+	err := svc.TriggerAutoInvestment(context.Background(), autoinvest.TriggerRequest{
+		UserID:        uuid.New(),
+		StashID:       uuid.New(),
+		CorrelationID: "deposit-1",
+	})
+
+	assert.ErrorIs(t, err, entities.ErrRiskAcknowledgmentRequired)
+	assert.False(t, orderPlacer.called, "No orders should be placed without an acknowledgment")
+}
+
+// mockAutoInvestMarketHours implements autoinvest.MarketHours with a switchable open flag
+type mockAutoInvestMarketHours struct {
+	open bool
+}
+
+func (m *mockAutoInvestMarketHours) IsOpen(t time.Time) bool {
+	return m.open
+}
+
+func (m *mockAutoInvestMarketHours) NextOpen(t time.Time) time.Time {
+	return t.Add(-time.Minute)
+}
+
+// mockDeferralStore implements autoinvest.DeferralStore for testing
+type mockDeferralStore struct {
+	deferrals map[string]*entities.DeferredAutoInvestment
+}
+
+func (m *mockDeferralStore) SaveDeferral(ctx context.Context, deferral *entities.DeferredAutoInvestment) error {
+	m.deferrals[deferral.CorrelationID] = deferral
+	return nil
+}
+
+func (m *mockDeferralStore) GetDueDeferrals(ctx context.Context, now time.Time, limit int) ([]*entities.DeferredAutoInvestment, error) {
+	var due []*entities.DeferredAutoInvestment
+	for _, d := range m.deferrals {
+		if !d.NotBefore.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockDeferralStore) DeleteDeferral(ctx context.Context, correlationID string) error {
+	delete(m.deferrals, correlationID)
+	return nil
+}
+
+func TestAutoInvestService_DefersWhileMarketClosed(t *testing.T) {
+	ctx := context.Background()
+	ledger := newMockLedgerService(decimal.NewFromFloat(100))
+	orderPlacer := &mockOrderPlacer{}
+	hours := &mockAutoInvestMarketHours{}
+	deferrals := &mockDeferralStore{deferrals: make(map[string]*entities.DeferredAutoInvestment)}
+	svc := autoinvest.NewService(ledger, orderPlacer, autoinvest.Config{}, testLogger())
+	svc.SetMarketHours(hours)
+	svc.SetDeferralStore(deferrals)
+
+	userID := uuid.New()
+	err := svc.TriggerAutoInvestment(ctx, autoinvest.TriggerRequest{
+		UserID:        userID,
+		StashID:       uuid.New(),
+		CorrelationID: "deposit-1",
+	})
+
+	// The funds stay in the stash and the trigger is queued for the open
+	assert.ErrorIs(t, err, autoinvest.ErrDeferredUntilOpen)
+	assert.False(t, orderPlacer.called)
+	require.Contains(t, deferrals.deferrals, "deposit-1")
+	assert.Equal(t, userID, deferrals.deferrals["deposit-1"].UserID)
+
+	// Nothing runs until the market opens
+	triggered, err := svc.ProcessDeferredInvestments(ctx, 100)
+	require.NoError(t, err)
+	assert.Zero(t, triggered)
+	assert.False(t, orderPlacer.called)
+
+	hours.open = true
+	triggered, err = svc.ProcessDeferredInvestments(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, triggered)
+	require.Len(t, orderPlacer.orders, 1)
+	assert.Equal(t, "SPY", orderPlacer.orders[0].symbol)
+	assert.Empty(t, deferrals.deferrals)
+}
+
 // mockUserProfileProvider implements strategy.UserProfileProvider for testing
 type mockUserProfileProvider struct {
 	profile *entities.UserProfile
@@ -209,6 +325,39 @@ func (m *mockUserProfileProvider) GetByID(ctx context.Context, id uuid.UUID) (*e
 	return m.profile, nil
 }
 
+func TestAutoInvestService_InvestsIntoRebalancingTargets(t *testing.T) {
+	ctx := context.Background()
+	ledger := newMockLedgerService(decimal.NewFromFloat(100))
+	orderPlacer := &mockOrderPlacer{}
+	investor := &mockCashFlowInvestor{investable: decimal.NewFromInt(80), active: true}
+	svc := autoinvest.NewService(ledger, orderPlacer, autoinvest.Config{}, testLogger())
+	svc.SetCashFlowInvestor(investor)
+
+	err := svc.TriggerAutoInvestment(ctx, autoinvest.TriggerRequest{
+		UserID:        uuid.New(),
+		StashID:       uuid.New(),
+		CorrelationID: "deposit-1",
+	})
+	require.NoError(t, err)
+
+	// The deposit goes to the user's own targets instead of the strategy's symbols
+	require.Len(t, investor.offered, 1)
+	assert.Equal(t, "100", investor.offered[0].String())
+	assert.Equal(t, entities.CashFlowSourceDeposit, investor.sources[0])
+	assert.False(t, orderPlacer.called)
+
+	// Users without a config keep the strategy path
+	investor.active = false
+	err = svc.TriggerAutoInvestment(ctx, autoinvest.TriggerRequest{
+		UserID:        uuid.New(),
+		StashID:       uuid.New(),
+		CorrelationID: "deposit-2",
+	})
+	require.NoError(t, err)
+	assert.Len(t, investor.offered, 1)
+	assert.True(t, orderPlacer.called)
+}
+
 func TestStrategyEngine_AggressiveGrowthForYoungUser(t *testing.T) {
 	log := testLogger()
 
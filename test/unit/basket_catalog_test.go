@@ -0,0 +1,306 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/investing"
+)
+
+// mockBasketCatalogRepository keeps baskets, versions and holder migrations in memory
+type mockBasketCatalogRepository struct {
+	baskets    map[uuid.UUID]*entities.Basket
+	versions   []*entities.BasketVersion
+	migrations []*entities.BasketHolderMigration
+	holders    map[uuid.UUID][]uuid.UUID
+}
+
+func newMockBasketCatalogRepository() *mockBasketCatalogRepository {
+	return &mockBasketCatalogRepository{
+		baskets: map[uuid.UUID]*entities.Basket{},
+		holders: map[uuid.UUID][]uuid.UUID{},
+	}
+}
+
+func (m *mockBasketCatalogRepository) GetAll(ctx context.Context) ([]*entities.Basket, error) {
+	var baskets []*entities.Basket
+	for _, basket := range m.baskets {
+		baskets = append(baskets, basket)
+	}
+	return baskets, nil
+}
+
+func (m *mockBasketCatalogRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
+	basket, ok := m.baskets[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *basket
+	return &copied, nil
+}
+
+func (m *mockBasketCatalogRepository) Create(ctx context.Context, basket *entities.Basket, version *entities.BasketVersion) error {
+	m.baskets[basket.ID] = basket
+	m.versions = append(m.versions, version)
+	return nil
+}
+
+func (m *mockBasketCatalogRepository) UpdateDetails(ctx context.Context, basket *entities.Basket) error {
+	stored := m.baskets[basket.ID]
+	stored.Name, stored.Description, stored.RiskLevel = basket.Name, basket.Description, basket.RiskLevel
+	return nil
+}
+
+func (m *mockBasketCatalogRepository) CreateVersion(ctx context.Context, version *entities.BasketVersion) error {
+	version.Version = 0
+	for _, v := range m.versions {
+		if v.BasketID == version.BasketID {
+			if v.Version > version.Version {
+				version.Version = v.Version
+			}
+			if v.Status == entities.BasketVersionStatusScheduled {
+				v.Status = entities.BasketVersionStatusCancelled
+			}
+		}
+	}
+	version.Version++
+	m.versions = append(m.versions, version)
+	return nil
+}
+
+func (m *mockBasketCatalogRepository) GetVersion(ctx context.Context, basketID uuid.UUID, version int) (*entities.BasketVersion, error) {
+	for _, v := range m.versions {
+		if v.BasketID == basketID && v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockBasketCatalogRepository) GetVersions(ctx context.Context, basketID uuid.UUID) ([]*entities.BasketVersion, error) {
+	return m.versions, nil
+}
+
+func (m *mockBasketCatalogRepository) GetDueVersions(ctx context.Context, at time.Time, limit int) ([]*entities.BasketVersion, error) {
+	var due []*entities.BasketVersion
+	for _, v := range m.versions {
+		if v.Status == entities.BasketVersionStatusScheduled && !v.EffectiveAt.After(at) {
+			due = append(due, v)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockBasketCatalogRepository) ActivateVersion(ctx context.Context, version *entities.BasketVersion) (int, error) {
+	basket := m.baskets[version.BasketID]
+	for _, v := range m.versions {
+		if v.BasketID == version.BasketID && v.Status == entities.BasketVersionStatusActive {
+			v.Status = entities.BasketVersionStatusSuperseded
+		}
+	}
+	version.Status = entities.BasketVersionStatusActive
+	for _, userID := range m.holders[basket.ID] {
+		m.migrations = append(m.migrations, &entities.BasketHolderMigration{
+			ID:          uuid.New(),
+			BasketID:    basket.ID,
+			UserID:      userID,
+			FromVersion: basket.CurrentVersion,
+			ToVersion:   version.Version,
+			Status:      entities.BasketHolderMigrationStatusPending,
+		})
+	}
+	basket.Composition = version.Composition
+	basket.CurrentVersion = version.Version
+	return len(m.holders[basket.ID]), nil
+}
+
+func (m *mockBasketCatalogRepository) GetPendingHolderMigrations(ctx context.Context, limit int) ([]*entities.BasketHolderMigration, error) {
+	var pending []*entities.BasketHolderMigration
+	for _, migration := range m.migrations {
+		if migration.Status == entities.BasketHolderMigrationStatusPending {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func (m *mockBasketCatalogRepository) UpdateHolderMigration(ctx context.Context, migration *entities.BasketHolderMigration) error {
+	return nil
+}
+
+// mockAssetLookup reports every asset as tradable unless listed as untradable
+type mockAssetLookup struct {
+	untradable map[string]bool
+}
+
+func (m *mockAssetLookup) GetAsset(ctx context.Context, symbol string) (*entities.AlpacaAssetResponse, error) {
+	return &entities.AlpacaAssetResponse{
+		Symbol:       symbol,
+		Status:       entities.AlpacaAssetStatusActive,
+		Tradable:     !m.untradable[symbol],
+		Fractionable: true,
+	}, nil
+}
+
+// mockBasketPositions returns a fixed market value for every basket position
+type mockBasketPositions struct {
+	value decimal.Decimal
+}
+
+func (m *mockBasketPositions) GetByUserAndBasket(ctx context.Context, userID, basketID uuid.UUID) (*entities.Position, error) {
+	return &entities.Position{UserID: userID, BasketID: basketID, Quantity: decimal.NewFromInt(1), MarketValue: m.value}, nil
+}
+
+// mockSignedOrderPlacer records market orders; negative notionals are sells. Symbols in failures
+// are rejected that many times before they are accepted.
+type mockSignedOrderPlacer struct {
+	orders   []string
+	failures map[string]int
+}
+
+func (m *mockSignedOrderPlacer) PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error) {
+	if m.failures[symbol] > 0 {
+		m.failures[symbol]--
+		return nil, errors.New("broker unavailable")
+	}
+	m.orders = append(m.orders, symbol+" "+notional.StringFixed(2))
+	return &entities.InvestmentOrder{ID: uuid.New(), UserID: userID, Symbol: symbol}, nil
+}
+
+func basketRequest(composition map[string]string, effectiveAt *time.Time) *entities.CuratedBasketRequest {
+	req := &entities.CuratedBasketRequest{
+		Name:        "Core",
+		Description: "Core holdings",
+		RiskLevel:   entities.RiskLevelBalanced,
+		EffectiveAt: effectiveAt,
+	}
+	for _, symbol := range []string{"SPY", "QQQ", "AGG", "VTI", "NKLA"} {
+		if weight, ok := composition[symbol]; ok {
+			req.Composition = append(req.Composition, entities.BasketComponent{Symbol: symbol, Weight: decimal.RequireFromString(weight)})
+		}
+	}
+	return req
+}
+
+func TestBasketCatalog_ValidatesTradabilityAndVersionsCompositions(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockBasketCatalogRepository()
+	catalog := investing.NewBasketCatalogService(repo, zap.NewNop())
+	catalog.SetAssetLookup(&mockAssetLookup{untradable: map[string]bool{"NKLA": true}})
+	adminID := uuid.New()
+
+	_, err := catalog.CreateBasket(ctx, adminID, basketRequest(map[string]string{"SPY": "0.5", "NKLA": "0.5"}, nil))
+	assert.EqualError(t, err, "asset NKLA is not tradable")
+
+	basket, err := catalog.CreateBasket(ctx, adminID, basketRequest(map[string]string{"SPY": "0.6", "AGG": "0.4"}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1, basket.CurrentVersion)
+
+	// Executors read the active composition from the catalog
+	allocations, err := catalog.GetAllocations(ctx, basket.ID)
+	require.NoError(t, err)
+	require.Len(t, allocations, 2)
+	assert.True(t, allocations[0].Percentage.Equal(decimal.NewFromInt(60)))
+
+	// Changing only details does not add a version
+	change, err := catalog.UpdateBasket(ctx, adminID, basket.ID, basketRequest(map[string]string{"SPY": "0.6", "AGG": "0.4"}, nil))
+	require.NoError(t, err)
+	assert.Nil(t, change.Version)
+
+	// A scheduled change waits for its effective time
+	later := time.Now().Add(24 * time.Hour)
+	change, err = catalog.UpdateBasket(ctx, adminID, basket.ID, basketRequest(map[string]string{"SPY": "0.5", "QQQ": "0.5"}, &later))
+	require.NoError(t, err)
+	require.NotNil(t, change.Version)
+	assert.Equal(t, 2, change.Version.Version)
+	assert.Equal(t, entities.BasketVersionStatusScheduled, change.Version.Status)
+	assert.Equal(t, 1, repo.baskets[basket.ID].CurrentVersion)
+
+	activated, err := catalog.ActivateDueVersions(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, activated)
+
+	change.Version.EffectiveAt = time.Now().Add(-time.Minute)
+	activated, err = catalog.ActivateDueVersions(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, activated)
+	assert.Equal(t, 2, repo.baskets[basket.ID].CurrentVersion)
+}
+
+func TestBasketCatalog_MigratesHoldersToNewComposition(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockBasketCatalogRepository()
+	placer := &mockSignedOrderPlacer{}
+	catalog := investing.NewBasketCatalogService(repo, zap.NewNop())
+	catalog.SetHolderMigration(&mockBasketPositions{value: decimal.NewFromInt(1000)}, placer)
+	adminID, holderID := uuid.New(), uuid.New()
+
+	basket, err := catalog.CreateBasket(ctx, adminID, basketRequest(map[string]string{"SPY": "0.6", "AGG": "0.4"}, nil))
+	require.NoError(t, err)
+	repo.holders[basket.ID] = []uuid.UUID{holderID}
+
+	// An immediate change activates and queues the holder
+	change, err := catalog.UpdateBasket(ctx, adminID, basket.ID, basketRequest(map[string]string{"SPY": "0.5", "QQQ": "0.3", "AGG": "0.2"}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, entities.BasketVersionStatusActive, change.Version.Status)
+	require.Len(t, repo.migrations, 1)
+
+	migrated, err := catalog.MigrateHolders(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+
+	// Sells for reduced weights go first, then buys
+	assert.Equal(t, []string{"AGG -200.00", "SPY -100.00", "QQQ 300.00"}, placer.orders)
+	assert.Equal(t, entities.BasketHolderMigrationStatusCompleted, repo.migrations[0].Status)
+	assert.Len(t, repo.migrations[0].Trades, 3)
+}
+
+func TestBasketCatalog_MigrationRetriesRemainingLegsOnNextPass(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockBasketCatalogRepository()
+	placer := &mockSignedOrderPlacer{failures: map[string]int{"QQQ": 1}}
+	catalog := investing.NewBasketCatalogService(repo, zap.NewNop())
+	catalog.SetHolderMigration(&mockBasketPositions{value: decimal.NewFromInt(1000)}, placer)
+	adminID, holderID := uuid.New(), uuid.New()
+
+	basket, err := catalog.CreateBasket(ctx, adminID, basketRequest(map[string]string{"SPY": "0.6", "AGG": "0.4"}, nil))
+	require.NoError(t, err)
+	repo.holders[basket.ID] = []uuid.UUID{holderID}
+	_, err = catalog.UpdateBasket(ctx, adminID, basket.ID, basketRequest(map[string]string{"SPY": "0.5", "QQQ": "0.3", "AGG": "0.2"}, nil))
+	require.NoError(t, err)
+
+	// The sells go through but the buy fails, so the holder stays pending with the sells recorded
+	migrated, err := catalog.MigrateHolders(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, migrated)
+	assert.Equal(t, []string{"AGG -200.00", "SPY -100.00"}, placer.orders)
+	migration := repo.migrations[0]
+	assert.Equal(t, entities.BasketHolderMigrationStatusPending, migration.Status)
+	assert.Equal(t, 1, migration.Attempts)
+	require.NotNil(t, migration.Error)
+	placed := 0
+	for _, trade := range migration.Trades {
+		if trade.PlacedAt != nil {
+			placed++
+			assert.NotNil(t, trade.OrderID)
+		}
+	}
+	assert.Equal(t, 2, placed)
+
+	// The next pass places only the buy left
+	migrated, err = catalog.MigrateHolders(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+	assert.Equal(t, []string{"AGG -200.00", "SPY -100.00", "QQQ 300.00"}, placer.orders)
+	assert.Equal(t, entities.BasketHolderMigrationStatusCompleted, migration.Status)
+	assert.Nil(t, migration.Error)
+}
@@ -0,0 +1,221 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/investing"
+	"github.com/rail-service/rail_service/internal/infrastructure/adapters/alpaca"
+)
+
+// mockBasketOrderClient fails orders per symbol with queued errors, then accepts them. Symbols in
+// lostResponses have their order placed but the response replaced by the given error.
+type mockBasketOrderClient struct {
+	failures      map[string][]error
+	lostResponses map[string]error
+	placed        []*entities.AlpacaCreateOrderRequest
+	orders        []entities.AlpacaOrderResponse
+}
+
+func (m *mockBasketOrderClient) CreateOrder(ctx context.Context, accountID string, req *entities.AlpacaCreateOrderRequest) (*entities.AlpacaOrderResponse, error) {
+	if errs := m.failures[req.Symbol]; len(errs) > 0 {
+		m.failures[req.Symbol] = errs[1:]
+		return nil, errs[0]
+	}
+	for _, order := range m.orders {
+		if order.ClientOrderID == req.ClientOrderID {
+			return nil, &alpaca.ValidationError{Message: "client_order_id must be unique"}
+		}
+	}
+	m.placed = append(m.placed, req)
+	order := entities.AlpacaOrderResponse{ID: uuid.NewString(), ClientOrderID: req.ClientOrderID, Symbol: req.Symbol}
+	m.orders = append(m.orders, order)
+	if err, ok := m.lostResponses[req.Symbol]; ok {
+		delete(m.lostResponses, req.Symbol)
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (m *mockBasketOrderClient) ListOrders(ctx context.Context, accountID string, query map[string]string) ([]entities.AlpacaOrderResponse, error) {
+	return m.orders, nil
+}
+
+// mockBasketExecutionRepository keeps executions in memory
+type mockBasketExecutionRepository struct {
+	executions map[uuid.UUID]*entities.BasketExecution
+}
+
+func (m *mockBasketExecutionRepository) CreateExecution(ctx context.Context, execution *entities.BasketExecution) error {
+	m.executions[execution.ID] = execution
+	return nil
+}
+
+func (m *mockBasketExecutionRepository) GetExecution(ctx context.Context, id uuid.UUID) (*entities.BasketExecution, error) {
+	return m.executions[id], nil
+}
+
+func (m *mockBasketExecutionRepository) GetExecutionByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*entities.BasketExecution, error) {
+	for _, execution := range m.executions {
+		if execution.UserID == userID && execution.IdempotencyKey == key {
+			return execution, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockBasketExecutionRepository) UpdateExecution(ctx context.Context, execution *entities.BasketExecution) error {
+	return nil
+}
+
+func (m *mockBasketExecutionRepository) UpdateLeg(ctx context.Context, leg *entities.BasketExecutionLeg) error {
+	return nil
+}
+
+// mockFundsReleaser records released reservations
+type mockFundsReleaser struct {
+	released decimal.Decimal
+}
+
+func (m *mockFundsReleaser) ReleaseReservation(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	m.released = m.released.Add(amount)
+	return nil
+}
+
+func TestBasketExecutor_RetriesLegsAndReleasesFailedOnes(t *testing.T) {
+	ctx := context.Background()
+	client := &mockBasketOrderClient{failures: map[string][]error{
+		"AAPL": {&alpaca.ServerError{StatusCode: 503, Message: "unavailable"}},
+		"TSLA": {&alpaca.ValidationError{Message: "asset is not tradable"}},
+	}}
+	repo := &mockBasketExecutionRepository{executions: map[uuid.UUID]*entities.BasketExecution{}}
+	funds := &mockFundsReleaser{released: decimal.Zero}
+	executor := investing.NewBasketExecutor(client, zap.NewNop())
+	executor.SetExecutionRepository(repo)
+	executor.SetFundsReleaser(funds)
+	executor.SetRetryPolicy(3, time.Millisecond)
+
+	req := &investing.BasketExecutionRequest{
+		UserID:          uuid.New(),
+		AlpacaAccountID: "acct-1",
+		TotalAmount:     decimal.NewFromInt(100),
+		IdempotencyKey:  "order-1",
+		Allocations: []investing.BasketAllocation{
+			{Symbol: "AAPL", Percentage: decimal.NewFromInt(33)},
+			{Symbol: "MSFT", Percentage: decimal.NewFromInt(34)},
+			{Symbol: "TSLA", Percentage: decimal.NewFromInt(33)},
+		},
+	}
+	execution, err := executor.ExecuteBasket(ctx, req)
+	require.NoError(t, err)
+
+	// The retryable failure is retried under the same client order ID; the rejected leg is released
+	assert.Equal(t, entities.BasketExecutionStatusPartiallyCompleted, execution.Status)
+	require.Len(t, client.placed, 2)
+	legs := map[string]*entities.BasketExecutionLeg{}
+	for _, leg := range execution.Legs {
+		legs[leg.Symbol] = leg
+	}
+	assert.Equal(t, entities.BasketExecutionLegStatusSubmitted, legs["AAPL"].Status)
+	assert.Equal(t, 2, legs["AAPL"].Attempts)
+	assert.Equal(t, entities.BasketExecutionLegStatusReleased, legs["TSLA"].Status)
+	assert.Equal(t, 1, legs["TSLA"].Attempts)
+	assert.True(t, funds.released.Equal(decimal.NewFromInt(33)))
+	assert.True(t, execution.SubmittedAmount.Equal(decimal.NewFromInt(67)))
+	assert.True(t, execution.ReleasedAmount.Equal(decimal.NewFromInt(33)))
+
+	// Repeating the request returns the report without placing orders again
+	again, err := executor.ExecuteBasket(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, execution.ID, again.ID)
+	assert.Len(t, client.placed, 2)
+}
+
+func TestBasketExecutor_ResumesWithoutDuplicatingPlacedOrders(t *testing.T) {
+	ctx := context.Background()
+	client := &mockBasketOrderClient{failures: map[string][]error{}}
+	repo := &mockBasketExecutionRepository{executions: map[uuid.UUID]*entities.BasketExecution{}}
+	executor := investing.NewBasketExecutor(client, zap.NewNop())
+	executor.SetExecutionRepository(repo)
+	executor.SetFundsReleaser(&mockFundsReleaser{released: decimal.Zero})
+	executor.SetRetryPolicy(3, time.Millisecond)
+
+	req := &investing.BasketExecutionRequest{
+		UserID:          uuid.New(),
+		AlpacaAccountID: "acct-1",
+		TotalAmount:     decimal.NewFromInt(10),
+		IdempotencyKey:  "order-2",
+		Allocations: []investing.BasketAllocation{
+			{Symbol: "SPY", Percentage: decimal.NewFromInt(33)},
+			{Symbol: "QQQ", Percentage: decimal.NewFromInt(67)},
+		},
+	}
+	execution, err := executor.ExecuteBasket(ctx, req)
+	require.NoError(t, err)
+
+	// Cents lost to rounding go to the largest allocation
+	assert.Equal(t, "6.70", execution.Legs[1].Amount.StringFixed(2))
+	assert.Equal(t, "3.30", execution.Legs[0].Amount.StringFixed(2))
+
+	// An earlier attempt reached the broker but the response was lost: the lookup finds the order
+	leg := execution.Legs[0]
+	leg.Status = entities.BasketExecutionLegStatusPending
+	leg.AlpacaOrderID = nil
+	leg.Attempts = 1
+	execution.Status = entities.BasketExecutionStatusExecuting
+	execution.SubmittedAmount = execution.SubmittedAmount.Sub(leg.Amount)
+
+	resumed, err := executor.ExecuteBasket(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, entities.BasketExecutionStatusCompleted, resumed.Status)
+	assert.Equal(t, entities.BasketExecutionLegStatusSubmitted, leg.Status)
+	assert.Len(t, client.placed, 2)
+	assert.Equal(t, "basket-"+execution.ID.String()+"-SPY", leg.ClientOrderID)
+}
+
+func TestBasketExecutor_TransportErrorsNeverReleasePlacedOrders(t *testing.T) {
+	ctx := context.Background()
+	client := &mockBasketOrderClient{
+		failures: map[string][]error{
+			"VTI": {fmt.Errorf("dial tcp: connection reset by peer")},
+		},
+		lostResponses: map[string]error{
+			"BND": fmt.Errorf("context deadline exceeded (Client.Timeout exceeded while awaiting headers)"),
+		},
+	}
+	repo := &mockBasketExecutionRepository{executions: map[uuid.UUID]*entities.BasketExecution{}}
+	funds := &mockFundsReleaser{released: decimal.Zero}
+	executor := investing.NewBasketExecutor(client, zap.NewNop())
+	executor.SetExecutionRepository(repo)
+	executor.SetFundsReleaser(funds)
+	executor.SetRetryPolicy(3, time.Millisecond)
+
+	execution, err := executor.ExecuteBasket(ctx, &investing.BasketExecutionRequest{
+		UserID:          uuid.New(),
+		AlpacaAccountID: "acct-1",
+		TotalAmount:     decimal.NewFromInt(100),
+		IdempotencyKey:  "order-3",
+		Allocations: []investing.BasketAllocation{
+			{Symbol: "VTI", Percentage: decimal.NewFromInt(60)},
+			{Symbol: "BND", Percentage: decimal.NewFromInt(40)},
+		},
+	})
+	require.NoError(t, err)
+
+	// A reset connection is retried, and a timed-out order the broker accepted is found, not released
+	assert.Equal(t, entities.BasketExecutionStatusCompleted, execution.Status)
+	for _, leg := range execution.Legs {
+		assert.Equal(t, entities.BasketExecutionLegStatusSubmitted, leg.Status, leg.Symbol)
+	}
+	assert.Len(t, client.placed, 2)
+	assert.True(t, funds.released.IsZero())
+}
@@ -2,7 +2,11 @@ package unit
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -18,12 +22,22 @@ import (
 type mockCardRepository struct {
 	cards        map[string]*entities.BridgeCard
 	transactions map[string]*entities.BridgeCardTransaction
+	shipments    map[uuid.UUID]*entities.CardShipment
+	disputes     map[uuid.UUID]*entities.CardDispute
+	evidence     map[uuid.UUID][]*entities.CardDisputeEvidence
+	controls     map[uuid.UUID]*entities.CardControls
+	rules        map[string]*entities.CardCategoryRule
 }
 
 func newMockCardRepository() *mockCardRepository {
 	return &mockCardRepository{
 		cards:        make(map[string]*entities.BridgeCard),
 		transactions: make(map[string]*entities.BridgeCardTransaction),
+		shipments:    make(map[uuid.UUID]*entities.CardShipment),
+		disputes:     make(map[uuid.UUID]*entities.CardDispute),
+		evidence:     make(map[uuid.UUID][]*entities.CardDisputeEvidence),
+		controls:     make(map[uuid.UUID]*entities.CardControls),
+		rules:        make(map[string]*entities.CardCategoryRule),
 	}
 }
 
@@ -76,10 +90,20 @@ func (m *mockCardRepository) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 
 func (m *mockCardRepository) CreateTransaction(ctx context.Context, tx *entities.BridgeCardTransaction) error {
 	tx.ID = uuid.New()
+	tx.CreatedAt = time.Now().UTC()
 	m.transactions[tx.BridgeTransID] = tx
 	return nil
 }
 
+func (m *mockCardRepository) GetTransactionByID(ctx context.Context, id uuid.UUID) (*entities.BridgeCardTransaction, error) {
+	for _, tx := range m.transactions {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockCardRepository) GetTransactionByBridgeID(ctx context.Context, bridgeTransID string) (*entities.BridgeCardTransaction, error) {
 	return m.transactions[bridgeTransID], nil
 }
@@ -115,6 +139,16 @@ func (m *mockCardRepository) UpdateTransactionStatus(ctx context.Context, id uui
 	return nil
 }
 
+func (m *mockCardRepository) GetCardSpendSince(ctx context.Context, cardID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var txs []*entities.BridgeCardTransaction
+	for _, tx := range m.transactions {
+		if tx.CardID == cardID && !tx.CreatedAt.Before(since) {
+			txs = append(txs, tx)
+		}
+	}
+	return entities.CardSpend(txs, time.Now().UTC()), nil
+}
+
 func (m *mockCardRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
 	count := 0
 	for _, c := range m.cards {
@@ -125,6 +159,161 @@ func (m *mockCardRepository) CountByUserID(ctx context.Context, userID uuid.UUID
 	return count, nil
 }
 
+func (m *mockCardRepository) MarkActivated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	for _, c := range m.cards {
+		if c.ID == id {
+			c.Status = entities.CardStatusActive
+			c.ActivatedAt = &at
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockCardRepository) Cancel(ctx context.Context, id uuid.UUID, reason string) error {
+	for _, c := range m.cards {
+		if c.ID == id {
+			c.Status = entities.CardStatusCancelled
+			c.CancelReason = &reason
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockCardRepository) CreateShipment(ctx context.Context, shipment *entities.CardShipment) error {
+	m.shipments[shipment.CardID] = shipment
+	return nil
+}
+
+func (m *mockCardRepository) GetShipmentByCardID(ctx context.Context, cardID uuid.UUID) (*entities.CardShipment, error) {
+	return m.shipments[cardID], nil
+}
+
+func (m *mockCardRepository) UpdateShipment(ctx context.Context, shipment *entities.CardShipment) error {
+	m.shipments[shipment.CardID] = shipment
+	return nil
+}
+
+func (m *mockCardRepository) CreateDispute(ctx context.Context, dispute *entities.CardDispute, evidence []*entities.CardDisputeEvidence) error {
+	m.disputes[dispute.ID] = dispute
+	m.evidence[dispute.ID] = evidence
+	return nil
+}
+
+func (m *mockCardRepository) AddDisputeEvidence(ctx context.Context, evidence []*entities.CardDisputeEvidence) error {
+	for _, e := range evidence {
+		m.evidence[e.DisputeID] = append(m.evidence[e.DisputeID], e)
+	}
+	return nil
+}
+
+func (m *mockCardRepository) GetDisputeByID(ctx context.Context, id uuid.UUID) (*entities.CardDispute, error) {
+	return m.disputes[id], nil
+}
+
+func (m *mockCardRepository) GetDisputeByTransactionID(ctx context.Context, transactionID uuid.UUID) (*entities.CardDispute, error) {
+	for _, d := range m.disputes {
+		if d.TransactionID == transactionID {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCardRepository) GetDisputesByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CardDispute, error) {
+	var result []*entities.CardDispute
+	for _, d := range m.disputes {
+		if d.UserID == userID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCardRepository) GetDisputesByStatus(ctx context.Context, status entities.CardDisputeStatus, limit, offset int) ([]*entities.CardDispute, error) {
+	var result []*entities.CardDispute
+	for _, d := range m.disputes {
+		if d.Status == status {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCardRepository) GetDisputeEvidence(ctx context.Context, disputeID uuid.UUID) ([]*entities.CardDisputeEvidence, error) {
+	return m.evidence[disputeID], nil
+}
+
+func (m *mockCardRepository) UpdateDispute(ctx context.Context, dispute *entities.CardDispute) error {
+	m.disputes[dispute.ID] = dispute
+	return nil
+}
+
+func (m *mockCardRepository) GetCardControls(ctx context.Context, cardID uuid.UUID) (*entities.CardControls, error) {
+	return m.controls[cardID], nil
+}
+
+func (m *mockCardRepository) UpsertCardControls(ctx context.Context, controls *entities.CardControls) error {
+	m.controls[controls.CardID] = controls
+	return nil
+}
+
+func (m *mockCardRepository) LockCardMerchant(ctx context.Context, cardID uuid.UUID, merchant string) error {
+	if c := m.controls[cardID]; c != nil && c.MerchantLocked && c.LockedMerchant == nil {
+		c.LockedMerchant = &merchant
+	}
+	return nil
+}
+
+func (m *mockCardRepository) GetMerchantTransactionsSince(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) ([]*entities.BridgeCardTransaction, error) {
+	var result []*entities.BridgeCardTransaction
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && tx.MerchantKey != nil && *tx.MerchantKey == merchantKey && !tx.CreatedAt.Before(since) {
+			result = append(result, tx)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (m *mockCardRepository) UpdateTransactionCategory(ctx context.Context, id uuid.UUID, category entities.SpendingCategory, source entities.CategorySource) error {
+	for _, tx := range m.transactions {
+		if tx.ID == id {
+			tx.Category, tx.CategorySource = &category, &source
+		}
+	}
+	return nil
+}
+
+func (m *mockCardRepository) UpdateMerchantCategory(ctx context.Context, userID uuid.UUID, merchantKey string, category entities.SpendingCategory, source entities.CategorySource) error {
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && tx.MerchantKey != nil && *tx.MerchantKey == merchantKey {
+			c, s := category, source
+			tx.Category, tx.CategorySource = &c, &s
+		}
+	}
+	return nil
+}
+
+func (m *mockCardRepository) MarkMerchantRecurring(ctx context.Context, userID uuid.UUID, merchantKey string, since time.Time) error {
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && tx.MerchantKey != nil && *tx.MerchantKey == merchantKey && !tx.CreatedAt.Before(since) {
+			tx.IsRecurring = true
+		}
+	}
+	return nil
+}
+
+func (m *mockCardRepository) GetCategoryRule(ctx context.Context, userID uuid.UUID, merchantKey string) (*entities.CardCategoryRule, error) {
+	return m.rules[userID.String()+merchantKey], nil
+}
+
+func (m *mockCardRepository) UpsertCategoryRule(ctx context.Context, rule *entities.CardCategoryRule) error {
+	m.rules[rule.UserID.String()+rule.MerchantKey] = rule
+	return nil
+}
+
 // mockCardBalanceProvider implements card.BalanceProvider for testing
 type mockCardBalanceProvider struct {
 	balance       decimal.Decimal
@@ -138,6 +327,10 @@ func (m *mockCardBalanceProvider) GetSpendBalance(ctx context.Context, userID uu
 }
 
 func (m *mockCardBalanceProvider) DeductSpendBalance(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, reference string) error {
+	// The ledger rejects non-positive entries, so a refund must never be deducted
+	if !amount.IsPositive() {
+		return fmt.Errorf("entry amount must be positive: %s", amount)
+	}
 	m.deductCalled = true
 	m.deductAmount = amount
 	m.deductRef = reference
@@ -321,3 +514,642 @@ func TestCardService_RecordTransaction_PendingDoesNotDeduct(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, balanceProvider.deductCalled, "Balance should NOT be deducted for pending transactions")
 }
+
+func TestCardService_ProcessCardAuthorization_BlockedMerchantCategory(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(100)}
+
+	bridgeCardID := "bridge-card-123"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+
+	approved, reason, err := svc.ProcessCardAuthorization(
+		context.Background(),
+		bridgeCardID,
+		decimal.NewFromFloat(20),
+		"Lucky Casino",
+		"7995",
+	)
+
+	require.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	assert.False(t, approved)
+	assert.Equal(t, string(entities.CardDeclineMerchantBlocked), reason)
+}
+
+func TestCardService_AuthorizeTransaction_DailyLimitPersistsDecline(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(1000)}
+
+	cardID := uuid.New()
+	bridgeCardID := "bridge-card-123"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+		Currency:     "USD",
+	}
+	repo.transactions["earlier"] = &entities.BridgeCardTransaction{
+		ID:        uuid.New(),
+		CardID:    cardID,
+		Type:      "capture",
+		Amount:    decimal.NewFromFloat(80),
+		Status:    "completed",
+		CreatedAt: time.Now().UTC(),
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+	policy := card.DefaultAuthorizationPolicy()
+	policy.DailyLimit = decimal.NewFromFloat(100)
+	svc.SetAuthorizationPolicy(policy)
+
+	decision, err := svc.AuthorizeTransaction(context.Background(), &card.AuthorizationRequest{
+		BridgeCardID:  bridgeCardID,
+		BridgeTransID: "auth-1",
+		Amount:        decimal.NewFromFloat(30),
+		MerchantName:  "Grocer",
+	})
+
+	require.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	assert.False(t, decision.Approved)
+	assert.Equal(t, entities.CardDeclineDailyLimit, decision.DeclineCode)
+	assert.Equal(t, "daily_limit", decision.DeclinedBy)
+
+	tx, _ := repo.GetTransactionByBridgeID(context.Background(), "auth-1")
+	require.NotNil(t, tx)
+	assert.Equal(t, "declined", tx.Status)
+	require.NotNil(t, tx.DeclineCode)
+	assert.Equal(t, string(entities.CardDeclineDailyLimit), *tx.DeclineCode)
+}
+
+// failingRule simulates a rule whose dependency errors
+type failingRule struct{}
+
+func (failingRule) Name() string { return "failing" }
+
+func (failingRule) Evaluate(ctx context.Context, c *entities.BridgeCard, req *card.AuthorizationRequest) (*card.RuleResult, error) {
+	return nil, errors.New("dependency unavailable")
+}
+
+func TestCardService_ProcessAuthorization_PersistsWebhookDecisions(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(1000)}
+
+	bridgeCardID := "bridge-card-webhook"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+		Currency:     "USD",
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+	ctx := context.Background()
+
+	// The transaction's own currency is checked, not the card's
+	decision, err := svc.ProcessAuthorization(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, BridgeTransID: "auth-eur", Amount: decimal.NewFromFloat(20), Currency: "EUR", MerchantName: "Cafe",
+	})
+	assert.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	require.NotNil(t, decision)
+	assert.False(t, decision.Approved)
+	assert.Equal(t, entities.CardDeclineCurrencyRestricted, decision.DeclineCode)
+	tx, _ := repo.GetTransactionByBridgeID(ctx, "auth-eur")
+	require.NotNil(t, tx)
+	require.NotNil(t, tx.DeclineCode)
+	assert.Equal(t, string(entities.CardDeclineCurrencyRestricted), *tx.DeclineCode)
+	require.NotNil(t, tx.RiskScore)
+
+	// A rule that cannot be evaluated declines as a processing error
+	svc.AddAuthorizationRule(failingRule{})
+	_, err = svc.ProcessAuthorization(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, BridgeTransID: "auth-err", Amount: decimal.NewFromFloat(20), Currency: "USD", MerchantName: "Cafe",
+	})
+	assert.Error(t, err)
+	tx, _ = repo.GetTransactionByBridgeID(ctx, "auth-err")
+	require.NotNil(t, tx)
+	require.NotNil(t, tx.DeclineCode)
+	assert.Equal(t, string(entities.CardDeclineProcessingError), *tx.DeclineCode)
+}
+
+// slowRule simulates a dependency that misses the latency budget
+type slowRule struct{ delay time.Duration }
+
+func (r slowRule) Name() string { return "slow" }
+
+func (r slowRule) Evaluate(ctx context.Context, c *entities.BridgeCard, req *card.AuthorizationRequest) (*card.RuleResult, error) {
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+	}
+	return &card.RuleResult{}, nil
+}
+
+func TestCardService_AuthorizeTransaction_FallbackOnTimeout(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(100)}
+
+	bridgeCardID := "bridge-card-123"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+	policy := card.DefaultAuthorizationPolicy()
+	policy.DecisionTimeout = 20 * time.Millisecond
+	policy.StandInLimit = decimal.NewFromFloat(25)
+	svc.SetAuthorizationPolicy(policy)
+	svc.AddAuthorizationRule(slowRule{delay: 200 * time.Millisecond})
+
+	small, err := svc.AuthorizeTransaction(context.Background(), &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID,
+		Amount:       decimal.NewFromFloat(10),
+	})
+	require.NoError(t, err)
+	assert.True(t, small.Approved)
+	assert.True(t, small.Fallback)
+
+	large, err := svc.AuthorizeTransaction(context.Background(), &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID,
+		Amount:       decimal.NewFromFloat(50),
+	})
+	require.ErrorIs(t, err, card.ErrAuthorizationTimeout)
+	assert.False(t, large.Approved)
+	assert.Equal(t, entities.CardDeclineAuthorizationTimeout, large.DeclineCode)
+}
+
+// mockPasscodeVerifier implements card.PasscodeVerifier for testing
+type mockPasscodeVerifier struct {
+	passcode string
+}
+
+func (m *mockPasscodeVerifier) VerifyPasscode(ctx context.Context, userID uuid.UUID, passcode string) (string, time.Time, error) {
+	if passcode != m.passcode {
+		return "", time.Time{}, errors.New("invalid passcode")
+	}
+	return "token", time.Now().Add(time.Minute), nil
+}
+
+func TestCardService_ActivateCard_RequiresShipmentAndMatchingLast4(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+
+	userID := uuid.New()
+	cardID := uuid.New()
+	bridgeCardID := "bridge-physical-123"
+
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusPending,
+		Type:         entities.CardTypePhysical,
+		Last4:        "4242",
+	}
+	repo.shipments[cardID] = &entities.CardShipment{
+		ID:     uuid.New(),
+		CardID: cardID,
+		Status: entities.CardShipmentProcessing,
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, nil, zapLog)
+	svc.SetPasscodeVerifier(&mockPasscodeVerifier{passcode: "1234"})
+
+	req := &entities.ActivateCardRequest{Last4: "4242", Passcode: "1234"}
+	_, err := svc.ActivateCard(context.Background(), userID, cardID, req)
+	assert.ErrorIs(t, err, card.ErrCardNotShipped)
+
+	// Carrier reports the card shipped
+	err = svc.ProcessShipmentUpdate(context.Background(), bridgeCardID, &card.ShipmentUpdate{
+		Status:         entities.CardShipmentShipped,
+		Carrier:        "USPS",
+		TrackingNumber: "9400100000000000000000",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, repo.shipments[cardID].ShippedAt)
+
+	_, err = svc.ActivateCard(context.Background(), userID, cardID, &entities.ActivateCardRequest{Last4: "0000", Passcode: "1234"})
+	assert.ErrorIs(t, err, card.ErrActivationVerificationFailed)
+
+	_, err = svc.ActivateCard(context.Background(), userID, cardID, &entities.ActivateCardRequest{Last4: "4242", Passcode: "9999"})
+	assert.ErrorIs(t, err, card.ErrActivationVerificationFailed)
+
+	// Without Bridge configured activation fails instead of panicking
+	_, err = svc.ActivateCard(context.Background(), userID, cardID, req)
+	assert.EqualError(t, err, "bridge adapter not configured")
+
+	assert.Equal(t, entities.CardStatusPending, repo.cards[bridgeCardID].Status, "Card must stay pending after failed activation")
+}
+
+// mockCardLedgerService implements card.LedgerService for testing
+type mockCardLedgerService struct {
+	accounts     map[entities.AccountType]*entities.LedgerAccount
+	transactions []*entities.CreateTransactionRequest
+}
+
+func newMockCardLedgerService() *mockCardLedgerService {
+	return &mockCardLedgerService{accounts: make(map[entities.AccountType]*entities.LedgerAccount)}
+}
+
+func (m *mockCardLedgerService) account(accountType entities.AccountType) *entities.LedgerAccount {
+	if _, ok := m.accounts[accountType]; !ok {
+		m.accounts[accountType] = &entities.LedgerAccount{ID: uuid.New(), AccountType: accountType}
+	}
+	return m.accounts[accountType]
+}
+
+func (m *mockCardLedgerService) GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error) {
+	return m.account(accountType).Balance, nil
+}
+
+func (m *mockCardLedgerService) GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error) {
+	return m.account(accountType), nil
+}
+
+func (m *mockCardLedgerService) GetSystemAccount(ctx context.Context, accountType entities.AccountType) (*entities.LedgerAccount, error) {
+	return m.account(accountType), nil
+}
+
+func (m *mockCardLedgerService) CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	for _, e := range req.Entries {
+		for _, acct := range m.accounts {
+			if acct.ID != e.AccountID {
+				continue
+			}
+			if e.EntryType == entities.EntryTypeDebit {
+				acct.Balance = acct.Balance.Add(e.Amount)
+			} else {
+				acct.Balance = acct.Balance.Sub(e.Amount)
+			}
+		}
+	}
+	m.transactions = append(m.transactions, req)
+	return &entities.LedgerTransaction{ID: uuid.New()}, nil
+}
+
+func TestCardService_FreezeUnfreeze_RejectsCardsNotYetActivated(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	userID := uuid.New()
+
+	pending := &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: "bridge-physical-pending",
+		Status:       entities.CardStatusPending,
+		Type:         entities.CardTypePhysical,
+	}
+	// Bridge reported the card frozen before the user ever activated it
+	unactivated := &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: "bridge-physical-frozen",
+		Status:       entities.CardStatusFrozen,
+		Type:         entities.CardTypePhysical,
+	}
+	repo.cards[pending.BridgeCardID] = pending
+	repo.cards[unactivated.BridgeCardID] = unactivated
+
+	svc := card.NewService(repo, nil, nil, nil, nil, zapLog)
+
+	_, err := svc.UnfreezeCard(context.Background(), userID, pending.ID)
+	assert.ErrorIs(t, err, card.ErrCardNotActivated)
+	_, err = svc.FreezeCard(context.Background(), userID, pending.ID)
+	assert.ErrorIs(t, err, card.ErrCardNotActivated)
+	_, err = svc.UnfreezeCard(context.Background(), userID, unactivated.ID)
+	assert.ErrorIs(t, err, card.ErrCardNotActivated)
+
+	assert.Equal(t, entities.CardStatusPending, pending.Status)
+	assert.Equal(t, entities.CardStatusFrozen, unactivated.Status)
+}
+
+func TestCardService_Dispute_ProvisionalCreditClawedBackWhenLost(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(1000)
+
+	userID := uuid.New()
+	cardID := uuid.New()
+	txID := uuid.New()
+	repo.transactions["bridge-tx-1"] = &entities.BridgeCardTransaction{
+		ID:            txID,
+		CardID:        cardID,
+		UserID:        userID,
+		BridgeTransID: "bridge-tx-1",
+		Amount:        decimal.NewFromInt(40),
+		Currency:      "USD",
+		Status:        string(entities.CardTxStatusCompleted),
+		CreatedAt:     time.Now().Add(-48 * time.Hour),
+	}
+
+	svc := card.NewService(repo, nil, nil, nil, nil, zapLog)
+	svc.SetLedgerService(ledger)
+
+	result, err := svc.OpenDispute(context.Background(), userID, &entities.OpenCardDisputeRequest{
+		TransactionID: txID,
+		Reason:        entities.CardDisputeReasonNotReceived,
+		Description:   "Item never arrived",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CardDisputeSubmitted, result.Dispute.Status)
+	assert.Equal(t, entities.ProvisionalCreditPosted, result.Dispute.ProvisionalCreditStatus)
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.Equal(decimal.NewFromInt(40)))
+
+	_, err = svc.OpenDispute(context.Background(), userID, &entities.OpenCardDisputeRequest{
+		TransactionID: txID,
+		Reason:        entities.CardDisputeReasonNotReceived,
+		Description:   "Second attempt",
+	})
+	assert.ErrorIs(t, err, card.ErrDisputeExists)
+
+	_, err = svc.MarkDisputeUnderReview(context.Background(), result.Dispute.ID, uuid.New())
+	require.NoError(t, err)
+
+	dispute, err := svc.ResolveDispute(context.Background(), result.Dispute.ID, uuid.New(), &entities.ResolveCardDisputeRequest{
+		Outcome: entities.CardDisputeLost,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CardDisputeLost, dispute.Status)
+	assert.Equal(t, entities.ProvisionalCreditReversed, dispute.ProvisionalCreditStatus)
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.IsZero())
+	assert.True(t, ledger.account(entities.AccountTypeSystemBufferFiat).Balance.Equal(decimal.NewFromInt(1000)))
+
+	_, err = svc.ResolveDispute(context.Background(), result.Dispute.ID, uuid.New(), &entities.ResolveCardDisputeRequest{
+		Outcome: entities.CardDisputeWon,
+	})
+	assert.ErrorIs(t, err, card.ErrDisputeResolved)
+
+	// A lost dispute cannot be reopened for another provisional credit
+	_, err = svc.OpenDispute(context.Background(), userID, &entities.OpenCardDisputeRequest{
+		TransactionID: txID,
+		Reason:        entities.CardDisputeReasonNotReceived,
+		Description:   "Trying again",
+	})
+	assert.ErrorIs(t, err, card.ErrDisputeExists)
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.IsZero())
+}
+
+// mockSettlementReverser records the card settlements whose round-up and cashback are reversed
+type mockSettlementReverser struct {
+	roundupsReversed []uuid.UUID
+	cashbackReversed []uuid.UUID
+}
+
+func (m *mockSettlementReverser) ProcessCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) (*entities.RoundupTransaction, error) {
+	return nil, nil
+}
+
+func (m *mockSettlementReverser) AccrueCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) (*entities.CashbackReward, error) {
+	return nil, nil
+}
+
+func (m *mockSettlementReverser) ReverseCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) error {
+	m.roundupsReversed = append(m.roundupsReversed, tx.ID)
+	return nil
+}
+
+// mockCashbackReverser wraps mockSettlementReverser so cashback reversals are recorded separately
+type mockCashbackReverser struct{ *mockSettlementReverser }
+
+func (m mockCashbackReverser) ReverseCardSettlement(ctx context.Context, tx *entities.BridgeCardTransaction) error {
+	m.cashbackReversed = append(m.cashbackReversed, tx.ID)
+	return nil
+}
+
+func TestCardService_Dispute_WonReversesRoundupAndCashback(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(1000)
+
+	userID := uuid.New()
+	txID := uuid.New()
+	repo.transactions["bridge-tx-won"] = &entities.BridgeCardTransaction{
+		ID:            txID,
+		CardID:        uuid.New(),
+		UserID:        userID,
+		BridgeTransID: "bridge-tx-won",
+		Type:          "purchase",
+		Amount:        decimal.NewFromInt(25),
+		Currency:      "USD",
+		Status:        string(entities.CardTxStatusCompleted),
+		CreatedAt:     time.Now().Add(-24 * time.Hour),
+	}
+
+	reverser := &mockSettlementReverser{}
+	svc := card.NewService(repo, nil, nil, nil, nil, zapLog)
+	svc.SetLedgerService(ledger)
+	svc.SetRoundupProcessor(reverser)
+	svc.SetRewardsProcessor(mockCashbackReverser{reverser})
+
+	result, err := svc.OpenDispute(context.Background(), userID, &entities.OpenCardDisputeRequest{
+		TransactionID: txID,
+		Reason:        entities.CardDisputeReasonNotReceived,
+	})
+	require.NoError(t, err)
+
+	dispute, err := svc.ResolveDispute(context.Background(), result.Dispute.ID, uuid.New(), &entities.ResolveCardDisputeRequest{
+		Outcome: entities.CardDisputeWon,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ProvisionalCreditFinalized, dispute.ProvisionalCreditStatus)
+	assert.Equal(t, string(entities.CardTxStatusReversed), repo.transactions["bridge-tx-won"].Status)
+	assert.Equal(t, []uuid.UUID{txID}, reverser.roundupsReversed)
+	assert.Equal(t, []uuid.UUID{txID}, reverser.cashbackReversed)
+}
+
+func TestCardService_AuthorizeTransaction_CardControls(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(1000)}
+
+	userID := uuid.New()
+	cardID := uuid.New()
+	bridgeCardID := "bridge-card-controls"
+
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	perTx := decimal.NewFromFloat(100)
+	controls := entities.DefaultCardControls(cardID)
+	controls.PerTransactionLimit = &perTx
+	controls.MerchantLocked = true
+	controls.InternationalEnabled = false
+	repo.controls[cardID] = controls
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+	ctx := context.Background()
+
+	decision, err := svc.AuthorizeTransaction(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, Amount: decimal.NewFromFloat(150), MerchantName: "Netflix",
+	})
+	assert.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	assert.Equal(t, entities.CardDeclineTransactionLimit, decision.DeclineCode)
+
+	decision, err = svc.AuthorizeTransaction(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, Amount: decimal.NewFromFloat(20), MerchantName: "Netflix", MerchantCountry: "GB",
+	})
+	assert.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	assert.Equal(t, entities.CardDeclineInternationalBlocked, decision.DeclineCode)
+
+	// First approved merchant locks the card
+	decision, err = svc.AuthorizeTransaction(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, Amount: decimal.NewFromFloat(20), MerchantName: "  netflix ",
+	})
+	require.NoError(t, err)
+	assert.True(t, decision.Approved)
+	require.NotNil(t, controls.LockedMerchant)
+	assert.Equal(t, "NETFLIX", *controls.LockedMerchant)
+
+	decision, err = svc.AuthorizeTransaction(ctx, &card.AuthorizationRequest{
+		BridgeCardID: bridgeCardID, Amount: decimal.NewFromFloat(20), MerchantName: "Hulu",
+	})
+	assert.ErrorIs(t, err, card.ErrAuthorizationDeclined)
+	assert.Equal(t, entities.CardDeclineMerchantLocked, decision.DeclineCode)
+}
+
+func TestCardService_RecordTransaction_SingleUseCardCancelledAfterCapture(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(100)}
+
+	cardID := uuid.New()
+	bridgeCardID := "bridge-card-single-use"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	controls := entities.DefaultCardControls(cardID)
+	controls.SingleUse = true
+	repo.controls[cardID] = controls
+
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+
+	err := svc.RecordTransaction(context.Background(), bridgeCardID, "tx-single", "capture",
+		decimal.NewFromFloat(25), "Store", "5411", "completed", nil)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CardStatusCancelled, repo.cards[bridgeCardID].Status)
+}
+
+func TestCardService_RecordTransaction_EnrichesAndLearnsCategory(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCardRepository()
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(500)}
+
+	userID := uuid.New()
+	bridgeCardID := "bridge-card-enrich"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	svc := card.NewService(repo, nil, nil, nil, balanceProvider, zapLog)
+	ctx := context.Background()
+
+	// Processor prefix, store number and state code are stripped; the MCC picks the category
+	require.NoError(t, svc.RecordTransaction(ctx, bridgeCardID, "tx-coffee", "capture",
+		decimal.NewFromFloat(6.50), "SQ *BLUE BOTTLE COFFEE #123 OAKLAND CA", "5814", "completed", nil))
+	coffee := repo.transactions["tx-coffee"]
+	require.NotNil(t, coffee.MerchantDisplayName)
+	assert.Equal(t, "Blue Bottle Coffee Oakland", *coffee.MerchantDisplayName)
+	assert.Equal(t, entities.SpendingCategoryFoodAndDrink, *coffee.Category)
+	assert.False(t, coffee.IsRecurring)
+
+	// Two earlier monthly charges of a similar amount make the third one recurring
+	key := "GYM CLUB"
+	for i, daysAgo := range []int{60, 30} {
+		repo.transactions[fmt.Sprintf("tx-gym-%d", i)] = &entities.BridgeCardTransaction{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Amount:      decimal.NewFromFloat(40),
+			Status:      "completed",
+			MerchantKey: &key,
+			CreatedAt:   time.Now().UTC().AddDate(0, 0, -daysAgo),
+		}
+	}
+	require.NoError(t, svc.RecordTransaction(ctx, bridgeCardID, "tx-gym", "capture",
+		decimal.NewFromFloat(42), "GYM CLUB 0042", "7997", "completed", nil))
+	gym := repo.transactions["tx-gym"]
+	assert.Equal(t, key, *gym.MerchantKey)
+	assert.True(t, gym.IsRecurring)
+	assert.True(t, repo.transactions["tx-gym-0"].IsRecurring)
+
+	// Re-categorizing learns a rule that applies to the merchant's next charge
+	_, err := svc.RecategorizeTransaction(ctx, userID, gym.ID, &entities.RecategorizeTransactionRequest{
+		Category: entities.SpendingCategoryHealth,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CategorySourceUser, *gym.CategorySource)
+	assert.Equal(t, entities.SpendingCategoryHealth, *repo.transactions["tx-gym-1"].Category)
+
+	require.NoError(t, svc.RecordTransaction(ctx, bridgeCardID, "tx-gym-next", "capture",
+		decimal.NewFromFloat(40), "GYM CLUB 0042", "7997", "pending", nil))
+	next := repo.transactions["tx-gym-next"]
+	assert.Equal(t, entities.SpendingCategoryHealth, *next.Category)
+	assert.Equal(t, entities.CategorySourceRule, *next.CategorySource)
+
+	_, err = svc.RecategorizeTransaction(ctx, userID, gym.ID, &entities.RecategorizeTransactionRequest{Category: "snacks"})
+	assert.ErrorIs(t, err, card.ErrInvalidCategory)
+}
+
+func TestCardSpend_CountsHeldPurchasesOnce(t *testing.T) {
+	now := time.Now().UTC()
+	merchant := func(name string) *string { return &name }
+	tx := func(txType, status string, amount int64, name string, age time.Duration) *entities.BridgeCardTransaction {
+		return &entities.BridgeCardTransaction{
+			Type:         txType,
+			Status:       status,
+			Amount:       decimal.NewFromInt(amount),
+			MerchantName: merchant(name),
+			CreatedAt:    now.Add(-age),
+		}
+	}
+
+	txs := []*entities.BridgeCardTransaction{
+		// Settled in place: the authorization row itself completed
+		tx("authorization", "completed", 40, "Grocer", 3*time.Hour),
+		// Held and then captured under its own transaction: counted once
+		tx("authorization", "pending", 25, "Cafe", 2*time.Hour),
+		tx("capture", "completed", 25, "Cafe", time.Hour),
+		// A second visit still on hold
+		tx("authorization", "pending", 25, "Cafe", 30*time.Minute),
+		// Lapsed hold, refund, reversal and decline never count
+		tx("authorization", "pending", 500, "Hotel", entities.CardAuthorizationHoldWindow+time.Hour),
+		tx("refund", "completed", -15, "Grocer", 20*time.Minute),
+		tx("authorization", "reversed", 60, "Airline", time.Hour),
+		tx("authorization", "declined", 80, "Casino", time.Hour),
+	}
+
+	assert.Equal(t, "90", entities.CardSpend(txs, now).String())
+}
@@ -0,0 +1,278 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/api/handlers"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+	"github.com/rail-service/rail_service/internal/domain/services/rewards"
+	"github.com/rail-service/rail_service/internal/domain/services/roundup"
+)
+
+// newBridgeCardWebhookRouter routes Bridge webhooks to the card service as the container does
+func newBridgeCardWebhookRouter(svc *card.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	zapLog := zap.NewNop()
+	handler := handlers.NewBridgeWebhookHandler(nil, zapLog, "", true)
+	handler.SetService(handlers.NewBridgeWebhookService(nil, nil, svc, nil, zapLog))
+
+	router := gin.New()
+	router.POST("/webhooks/bridge", handler.HandleWebhook)
+	return router
+}
+
+func postBridgeWebhook(t *testing.T, router *gin.Engine, payload map[string]interface{}) map[string]interface{} {
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/bridge", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestBridgeWebhook_CardShipmentUpdatesReachCardService(t *testing.T) {
+	repo := newMockCardRepository()
+	cardID := uuid.New()
+	bridgeCardID := "bridge-physical-1"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusPending,
+		Type:         entities.CardTypePhysical,
+	}
+	repo.shipments[cardID] = &entities.CardShipment{ID: uuid.New(), CardID: cardID, Status: entities.CardShipmentOrdered}
+
+	router := newBridgeCardWebhookRouter(card.NewService(repo, nil, nil, nil, nil, zap.NewNop()))
+
+	postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":      "card_account",
+		"event_type":          "card_account.updated",
+		"event_object_id":     bridgeCardID,
+		"event_object_status": "pending",
+		"event_object": map[string]interface{}{
+			"shipping": map[string]interface{}{
+				"status":          "in_transit",
+				"carrier":         "USPS",
+				"tracking_number": "9400100000000000000000",
+			},
+		},
+	})
+
+	shipment := repo.shipments[cardID]
+	assert.Equal(t, entities.CardShipmentShipped, shipment.Status)
+	require.NotNil(t, shipment.Carrier)
+	assert.Equal(t, "USPS", *shipment.Carrier)
+	require.NotNil(t, shipment.ShippedAt)
+
+	postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":      "card_account",
+		"event_object_id":     bridgeCardID,
+		"event_object_status": "pending",
+		"event_object":        map[string]interface{}{"shipping": map[string]interface{}{"status": "delivered"}},
+	})
+	assert.Equal(t, entities.CardShipmentDelivered, repo.shipments[cardID].Status)
+
+	// A late in-transit webhook does not move the delivered card backwards
+	postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":      "card_account",
+		"event_object_id":     bridgeCardID,
+		"event_object_status": "pending",
+		"event_object":        map[string]interface{}{"shipping": map[string]interface{}{"status": "in_transit"}},
+	})
+	assert.Equal(t, entities.CardShipmentDelivered, repo.shipments[cardID].Status)
+}
+
+func TestBridgeWebhook_CardControlsApplyToLiveAuthorizations(t *testing.T) {
+	repo := newMockCardRepository()
+	cardID := uuid.New()
+	bridgeCardID := "bridge-card-controls-webhook"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           cardID,
+		UserID:       uuid.New(),
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+		Currency:     "USD",
+	}
+	controls := entities.DefaultCardControls(cardID)
+	controls.OnlineEnabled = false
+	controls.InternationalEnabled = false
+	repo.controls[cardID] = controls
+
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromFloat(500)}
+	router := newBridgeCardWebhookRouter(card.NewService(repo, nil, nil, nil, balanceProvider, zap.NewNop()))
+
+	authorize := func(transactionID string, object map[string]interface{}) *entities.BridgeCardTransaction {
+		object["card_account_id"] = bridgeCardID
+		object["amount"] = "25.00"
+		object["currency"] = "usd"
+		object["merchant_name"] = "Shop"
+		resp := postBridgeWebhook(t, router, map[string]interface{}{
+			"event_category":      "card_transaction",
+			"event_object_id":     transactionID,
+			"event_object_status": "pending",
+			"event_object":        object,
+		})
+		tx, _ := repo.GetTransactionByBridgeID(context.Background(), transactionID)
+		require.NotNil(t, tx, "authorization %s is persisted", transactionID)
+
+		// Bridge approves or declines from the response, with the pipeline's decline code
+		approved := tx.Status != string(entities.CardTxStatusDeclined)
+		assert.Equal(t, approved, resp["approved"])
+		if !approved {
+			assert.Equal(t, *tx.DeclineCode, resp["decline_code"])
+		}
+		return tx
+	}
+
+	online := authorize("auth-online", map[string]interface{}{"card_present": false, "merchant_country": "US"})
+	assert.Equal(t, string(entities.CardTxStatusDeclined), online.Status)
+	require.NotNil(t, online.DeclineCode)
+	assert.Equal(t, string(entities.CardDeclineOnlineDisabled), *online.DeclineCode)
+
+	abroad := authorize("auth-abroad", map[string]interface{}{"card_present": true, "merchant_country": "gb"})
+	require.NotNil(t, abroad.DeclineCode)
+	assert.Equal(t, string(entities.CardDeclineInternationalBlocked), *abroad.DeclineCode)
+
+	inStore := authorize("auth-in-store", map[string]interface{}{"card_present": true, "merchant_country": "US"})
+	assert.Equal(t, string(entities.CardTxStatusPending), inStore.Status)
+	assert.Nil(t, inStore.DeclineCode)
+}
+
+func TestBridgeWebhook_PostedCardTransactionSettlesAndRoundsUp(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := newMockCardRepository()
+	bridgeCardID := "bridge-card-posted"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+		Currency:     "USD",
+	}
+
+	roundupRepo := newMockRoundupRepository()
+	settings := entities.DefaultRoundupSettings(userID)
+	settings.Enabled = true
+	roundupRepo.settings[userID] = settings
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSpendingBalance).Balance = decimal.NewFromInt(100)
+	roundupSvc := roundup.NewService(roundupRepo, nil, nil, nil, zap.NewNop())
+	roundupSvc.SetLedgerService(ledger)
+
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromInt(100)}
+	cardSvc := card.NewService(repo, nil, nil, nil, balanceProvider, zap.NewNop())
+	cardSvc.SetRoundupProcessor(roundupSvc)
+	router := newBridgeCardWebhookRouter(cardSvc)
+
+	object := func() map[string]interface{} {
+		return map[string]interface{}{
+			"card_account_id": bridgeCardID,
+			"amount":          "4.25",
+			"currency":        "usd",
+			"merchant_name":   "Corner Cafe",
+			"card_present":    true,
+		}
+	}
+	resp := postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":      "card_transaction",
+		"event_object_id":     "tx-posted",
+		"event_object_status": "pending",
+		"event_object":        object(),
+	})
+	require.Equal(t, true, resp["approved"])
+	assert.False(t, balanceProvider.deductCalled)
+
+	// Bridge settles the authorization with a posted transaction event
+	postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":  "posted_card_account_transaction",
+		"event_object_id": "tx-posted",
+		"event_object":    object(),
+	})
+
+	tx, _ := repo.GetTransactionByBridgeID(ctx, "tx-posted")
+	require.NotNil(t, tx)
+	assert.Equal(t, string(entities.CardTxStatusCompleted), tx.Status)
+	assert.True(t, balanceProvider.deductCalled)
+	assert.True(t, balanceProvider.deductAmount.Equal(decimal.NewFromFloat(4.25)))
+
+	require.Len(t, roundupRepo.transactions, 1)
+	rt := roundupRepo.transactions[0]
+	assert.Equal(t, entities.RoundupStatusCollected, rt.Status)
+	assert.Equal(t, tx.ID.String(), *rt.SourceRef)
+	assert.True(t, rt.MultipliedAmount.Equal(decimal.NewFromFloat(0.75)))
+}
+
+func TestBridgeWebhook_PostedCardTransactionAccruesCashback(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := newMockCardRepository()
+	bridgeCardID := "bridge-card-posted-cashback"
+	repo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+		Currency:     "USD",
+	}
+
+	rewardsRepo := newMockRewardsRepository()
+	rewardsSvc := rewards.NewService(rewardsRepo, newMockCardLedgerService(), nil, zap.NewNop())
+	merchant := "Corner Cafe"
+	offer, err := rewardsSvc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{
+		Name:     "Cafe Cashback",
+		Merchant: &merchant,
+		Rate:     decimal.NewFromFloat(0.1),
+	})
+	require.NoError(t, err)
+
+	balanceProvider := &mockCardBalanceProvider{balance: decimal.NewFromInt(100)}
+	cardSvc := card.NewService(repo, nil, nil, nil, balanceProvider, zap.NewNop())
+	cardSvc.SetRewardsProcessor(rewardsSvc)
+	router := newBridgeCardWebhookRouter(cardSvc)
+
+	// A purchase Bridge posts without a prior authorization event still earns cashback
+	postBridgeWebhook(t, router, map[string]interface{}{
+		"event_category":  "posted_card_account_transaction",
+		"event_object_id": "tx-posted-cashback",
+		"event_object": map[string]interface{}{
+			"card_account_id": bridgeCardID,
+			"amount":          "20.00",
+			"merchant_name":   merchant,
+		},
+	})
+
+	tx, _ := repo.GetTransactionByBridgeID(ctx, "tx-posted-cashback")
+	require.NotNil(t, tx)
+	assert.Equal(t, string(entities.CardTxStatusCompleted), tx.Status)
+	assert.True(t, balanceProvider.deductCalled)
+
+	require.Len(t, rewardsRepo.rewards, 1)
+	reward := rewardsRepo.rewards[0]
+	assert.Equal(t, offer.ID, reward.OfferID)
+	assert.Equal(t, tx.ID, reward.CardTransactionID)
+	assert.Equal(t, "2", reward.Amount.String())
+	assert.Equal(t, entities.CashbackStatusPending, reward.Status)
+}
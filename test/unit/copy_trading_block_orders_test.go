@@ -0,0 +1,271 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+// mockBlockTradingAdapter fills block orders up to a fixed quantity and price and records journals
+// as the net shares and cash each user received, with journals to the firm as negative amounts
+type mockBlockTradingAdapter struct {
+	fillLimit    decimal.Decimal
+	averagePrice decimal.Decimal
+	orders       []decimal.Decimal
+	journals     map[uuid.UUID]decimal.Decimal
+	cash         map[uuid.UUID]decimal.Decimal
+	failJournal  map[uuid.UUID]bool
+	failCash     map[uuid.UUID]bool
+	calls        []string
+}
+
+func (m *mockBlockTradingAdapter) PlaceBlockOrder(ctx context.Context, symbol string, side string, quantity decimal.Decimal) (string, decimal.Decimal, decimal.Decimal, error) {
+	m.orders = append(m.orders, quantity)
+	m.calls = append(m.calls, "order")
+	return "block-1", decimal.Min(quantity, m.fillLimit), m.averagePrice, nil
+}
+
+func (m *mockBlockTradingAdapter) JournalShares(ctx context.Context, userID uuid.UUID, symbol string, side string, quantity decimal.Decimal) (string, error) {
+	m.calls = append(m.calls, "journal_"+side)
+	if m.failJournal[userID] {
+		return "", fmt.Errorf("journal rejected")
+	}
+	if side == "sell" {
+		quantity = quantity.Neg()
+	}
+	m.journals[userID] = m.journals[userID].Add(quantity)
+	return "journal-" + userID.String(), nil
+}
+
+func (m *mockBlockTradingAdapter) JournalCash(ctx context.Context, userID uuid.UUID, side string, amount decimal.Decimal) (string, error) {
+	m.calls = append(m.calls, "cash_"+side)
+	if m.failCash[userID] {
+		return "", fmt.Errorf("cash journal rejected")
+	}
+	if m.cash == nil {
+		m.cash = make(map[uuid.UUID]decimal.Decimal)
+	}
+	if side == "buy" {
+		amount = amount.Neg()
+	}
+	m.cash[userID] = m.cash[userID].Add(amount)
+	return "cash-" + userID.String(), nil
+}
+
+func TestCopyTradingBlockOrders_AggregatesSignalAndAllocatesFillsProRata(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	blocks := &mockBlockTradingAdapter{
+		fillLimit:    decimal.NewFromInt(5),
+		averagePrice: decimal.NewFromInt(101),
+		journals:     make(map[uuid.UUID]decimal.Decimal),
+	}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+	svc.SetBlockTradingAdapter(blocks)
+
+	conductorID := uuid.New()
+	var drafts []*entities.Draft
+	for _, capital := range []int64{5, 1000, 2000, 3000} {
+		d := &entities.Draft{
+			ID:               uuid.New(),
+			DrafterID:        uuid.New(),
+			ConductorID:      conductorID,
+			Status:           entities.DraftStatusActive,
+			AllocatedCapital: decimal.NewFromInt(capital),
+			CurrentAUM:       decimal.NewFromInt(capital),
+			StartValue:       decimal.NewFromInt(capital),
+			CopyRatio:        decimal.NewFromInt(1),
+		}
+		repo.drafts[d.ID] = d
+		drafts = append(drafts, d)
+	}
+
+	// The conductor buys 10 AAPL out of $10,000, so the drafts want 0.005, 1, 2 and 3 shares
+	signal := &entities.Signal{
+		ID:                   uuid.New(),
+		ConductorID:          conductorID,
+		AssetTicker:          "AAPL",
+		Side:                 "buy",
+		BaseQuantity:         decimal.NewFromInt(10),
+		ConductorAUMAtSignal: decimal.NewFromInt(10000),
+	}
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+
+	// The $0.50 draft is skipped and the other three go out as one 6-share order
+	require.Len(t, blocks.orders, 1)
+	assert.Equal(t, "6", blocks.orders[0].String())
+	require.Len(t, repo.blockOrders, 1)
+	block := repo.blockOrders[0]
+	assert.Equal(t, 3, block.DraftCount)
+	assert.Equal(t, entities.BlockOrderStatusPartiallyFilled, block.Status)
+	assert.Equal(t, "5", block.FilledQuantity.String())
+
+	// Five filled shares split 5:10:15 to eight decimal places, with the odd unit to the largest remainder
+	assert.Equal(t, "0.83333333", blocks.journals[drafts[1].DrafterID].String())
+	assert.Equal(t, "1.66666667", blocks.journals[drafts[2].DrafterID].String())
+	assert.Equal(t, "2.5", blocks.journals[drafts[3].DrafterID].String())
+	assert.True(t, blocks.journals[drafts[0].DrafterID].IsZero())
+
+	for _, log := range repo.executionLogs {
+		if log.DraftID == drafts[0].ID {
+			assert.Equal(t, entities.ExecutionStatusSkippedTooSmall, log.Status)
+			assert.Nil(t, log.BlockOrderID)
+			continue
+		}
+		assert.Equal(t, entities.ExecutionStatusPartial, log.Status)
+		assert.Equal(t, "101", log.ExecutedPrice.String())
+		assert.Equal(t, "block-1", log.OrderID)
+		require.NotNil(t, log.BlockOrderID)
+		assert.Equal(t, block.ID, *log.BlockOrderID)
+	}
+	assert.Equal(t, "2747.5", drafts[3].CurrentAUM.String())
+
+	// Each drafter funded its shares at $100 before the order and got back what the fill left unused
+	assert.Equal(t, []string{"cash_buy", "cash_buy", "cash_buy", "order"}, blocks.calls[:4])
+	assert.Equal(t, "-84.17", blocks.cash[drafts[1].DrafterID].String())
+	assert.Equal(t, "-252.5", blocks.cash[drafts[3].DrafterID].String())
+	assert.True(t, blocks.cash[drafts[0].DrafterID].IsZero())
+
+	// Reprocessing the signal places no second order
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+	assert.Len(t, blocks.orders, 1)
+}
+
+func TestCopyTradingBlockOrders_SellJournalsSharesToFirmBeforeOrdering(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	blocks := &mockBlockTradingAdapter{
+		fillLimit:    decimal.NewFromInt(3),
+		averagePrice: decimal.NewFromInt(99),
+		journals:     make(map[uuid.UUID]decimal.Decimal),
+		failJournal:  make(map[uuid.UUID]bool),
+	}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+	svc.SetBlockTradingAdapter(blocks)
+
+	conductorID := uuid.New()
+	var drafts []*entities.Draft
+	for _, capital := range []int64{1000, 2000, 3000} {
+		d := &entities.Draft{
+			ID:               uuid.New(),
+			DrafterID:        uuid.New(),
+			ConductorID:      conductorID,
+			Status:           entities.DraftStatusActive,
+			AllocatedCapital: decimal.NewFromInt(capital),
+			CurrentAUM:       decimal.NewFromInt(capital),
+			StartValue:       decimal.NewFromInt(capital),
+			CopyRatio:        decimal.NewFromInt(1),
+		}
+		repo.drafts[d.ID] = d
+		drafts = append(drafts, d)
+	}
+	blocks.failJournal[drafts[0].DrafterID] = true
+
+	// The drafts want to sell 1, 2 and 3 shares; the first drafter's shares cannot be journaled
+	signal := &entities.Signal{
+		ID:                   uuid.New(),
+		ConductorID:          conductorID,
+		AssetTicker:          "AAPL",
+		Side:                 "sell",
+		BaseQuantity:         decimal.NewFromInt(10),
+		ConductorAUMAtSignal: decimal.NewFromInt(10000),
+	}
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+
+	// Only journaled shares are sold, and the firm holds them before the order goes out
+	require.Len(t, blocks.orders, 1)
+	assert.Equal(t, "5", blocks.orders[0].String())
+	assert.Equal(t, []string{"journal_sell", "journal_sell", "journal_sell", "order", "journal_buy", "cash_sell", "journal_buy", "cash_sell"}, blocks.calls)
+
+	// Three of five shares sold, split 2:3, and the unsold shares go back to their drafters
+	assert.True(t, blocks.journals[drafts[0].DrafterID].IsZero())
+	assert.Equal(t, "-1.2", blocks.journals[drafts[1].DrafterID].String())
+	assert.Equal(t, "-1.8", blocks.journals[drafts[2].DrafterID].String())
+	assert.Equal(t, "3178.2", drafts[2].CurrentAUM.String())
+
+	// The sale proceeds are journaled from the firm to each drafter
+	assert.Equal(t, "118.8", blocks.cash[drafts[1].DrafterID].String())
+	assert.Equal(t, "178.2", blocks.cash[drafts[2].DrafterID].String())
+
+	for _, log := range repo.executionLogs {
+		if log.DraftID == drafts[0].ID {
+			assert.Equal(t, entities.ExecutionStatusFailed, log.Status)
+			assert.Nil(t, log.BlockOrderID)
+			continue
+		}
+		assert.Equal(t, entities.ExecutionStatusPartial, log.Status)
+	}
+}
+
+func TestCopyTradingBlockOrders_FailedCashJournalFailsOnlyThatLeg(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	blocks := &mockBlockTradingAdapter{
+		fillLimit:    decimal.NewFromInt(10),
+		averagePrice: decimal.NewFromInt(100),
+		journals:     make(map[uuid.UUID]decimal.Decimal),
+		failCash:     make(map[uuid.UUID]bool),
+	}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+	svc.SetBlockTradingAdapter(blocks)
+
+	conductorID := uuid.New()
+	var drafts []*entities.Draft
+	for _, capital := range []int64{1000, 2000} {
+		d := &entities.Draft{
+			ID:               uuid.New(),
+			DrafterID:        uuid.New(),
+			ConductorID:      conductorID,
+			Status:           entities.DraftStatusActive,
+			AllocatedCapital: decimal.NewFromInt(capital),
+			CurrentAUM:       decimal.NewFromInt(capital),
+			StartValue:       decimal.NewFromInt(capital),
+			CopyRatio:        decimal.NewFromInt(1),
+		}
+		repo.drafts[d.ID] = d
+		drafts = append(drafts, d)
+	}
+	blocks.failCash[drafts[0].DrafterID] = true
+
+	signal := &entities.Signal{
+		ID:                   uuid.New(),
+		ConductorID:          conductorID,
+		AssetTicker:          "AAPL",
+		Side:                 "buy",
+		BaseQuantity:         decimal.NewFromInt(10),
+		ConductorAUMAtSignal: decimal.NewFromInt(10000),
+	}
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+
+	// The unfunded drafter is left out of the block, so the firm never pays for its shares
+	require.Len(t, blocks.orders, 1)
+	assert.Equal(t, "2", blocks.orders[0].String())
+	assert.True(t, blocks.journals[drafts[0].DrafterID].IsZero())
+	assert.Equal(t, "2", blocks.journals[drafts[1].DrafterID].String())
+	assert.Equal(t, "-200", blocks.cash[drafts[1].DrafterID].String())
+	assert.Equal(t, "1000", drafts[0].CurrentAUM.String())
+
+	for _, log := range repo.executionLogs {
+		if log.DraftID == drafts[0].ID {
+			assert.Equal(t, entities.ExecutionStatusFailed, log.Status)
+			assert.Contains(t, log.ErrorMessage, "cash journal failed")
+			continue
+		}
+		assert.Equal(t, entities.ExecutionStatusSuccess, log.Status)
+	}
+}
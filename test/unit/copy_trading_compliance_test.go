@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+// mockAssetEligibilityProvider returns fixed eligibility data per symbol
+type mockAssetEligibilityProvider struct {
+	assets map[string]*entities.AssetEligibility
+}
+
+func (m *mockAssetEligibilityProvider) GetAssetEligibility(ctx context.Context, symbol string) (*entities.AssetEligibility, error) {
+	return m.assets[symbol], nil
+}
+
+func newComplianceTestService(t *testing.T) (*copytrading.Service, *mockCopyTradingRepository, *entities.Conductor) {
+	t.Helper()
+	zapLog, _ := zap.NewDevelopment()
+	repo := newMockCopyTradingRepository()
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, &mockCopyTradingAdapter{}, zapLog)
+	svc.SetAssetEligibilityProvider(&mockAssetEligibilityProvider{assets: map[string]*entities.AssetEligibility{
+		"AAPL": {Symbol: "AAPL", Tradable: true, Exchange: "NASDAQ", AverageDollarVolume: decimal.NewFromInt(5_000_000_000)},
+		"PUMP": {Symbol: "PUMP", Tradable: true, Exchange: "NASDAQ", AverageDollarVolume: decimal.NewFromInt(200_000)},
+		"OTCX": {Symbol: "OTCX", Tradable: true, Exchange: "OTC", AverageDollarVolume: decimal.NewFromInt(50_000_000)},
+	}})
+
+	conductor := &entities.Conductor{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		DisplayName: "Trader",
+		Status:      entities.ConductorStatusActive,
+		SourceAUM:   decimal.NewFromInt(100_000),
+	}
+	repo.conductors[conductor.ID] = conductor
+	return svc, repo, conductor
+}
+
+func TestCopyTradingCompliance_BlocksRestrictedTrades(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, conductor := newComplianceTestService(t)
+	qty, price := decimal.NewFromInt(10), decimal.NewFromInt(100)
+
+	// Illiquid and OTC buys are blocked, but sells of them are still copied so followers can exit
+	signal, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "PUMP", "buy", qty, price, "o1")
+	require.NoError(t, err)
+	assert.Nil(t, signal)
+	signal, err = svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "OTCX", "buy", qty, price, "o2")
+	require.NoError(t, err)
+	assert.Nil(t, signal)
+	signal, err = svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "PUMP", "sell", qty, price, "o3")
+	require.NoError(t, err)
+	assert.NotNil(t, signal)
+
+	// A blackout on AAPL blocks buys for this conductor only; sells are copied and recorded
+	_, err = svc.CreateBlackoutWindow(ctx, uuid.New(), &entities.CreateBlackoutWindowRequest{
+		ConductorID: &conductor.ID,
+		AssetTicker: "aapl",
+		StartsAt:    time.Now().Add(-time.Hour),
+		EndsAt:      time.Now().Add(time.Hour),
+		Reason:      "earnings",
+	})
+	require.NoError(t, err)
+	signal, err = svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "buy", qty, price, "o4")
+	require.NoError(t, err)
+	assert.Nil(t, signal)
+	sell, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "sell", qty, price, "o5")
+	require.NoError(t, err)
+	require.NotNil(t, sell)
+
+	require.Len(t, repo.complianceEvents, 4)
+	assert.Equal(t, entities.ComplianceEventRestrictedAsset, repo.complianceEvents[0].EventType)
+	assert.Equal(t, entities.ComplianceEventRestrictedAsset, repo.complianceEvents[1].EventType)
+	assert.Equal(t, entities.ComplianceEventBlackout, repo.complianceEvents[2].EventType)
+	for _, e := range repo.complianceEvents[:3] {
+		assert.True(t, e.Blocked)
+		assert.Nil(t, e.SignalID)
+	}
+	recorded := repo.complianceEvents[3]
+	assert.Equal(t, entities.ComplianceEventBlackout, recorded.EventType)
+	assert.False(t, recorded.Blocked)
+	assert.Equal(t, sell.ID, *recorded.SignalID)
+
+	// Only the blocked trades count against the conductor in the report
+	report, err := svc.GetComplianceReport(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, report.Conductors, 1)
+	assert.Equal(t, 3, report.Conductors[0].BlockedTrades)
+	assert.Equal(t, 2, report.Conductors[0].EventCounts[entities.ComplianceEventBlackout])
+
+	_, err = svc.CreateBlackoutWindow(ctx, uuid.New(), &entities.CreateBlackoutWindowRequest{
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(-time.Hour),
+		Reason:   "backwards",
+	})
+	assert.EqualError(t, err, "blackout must end after it starts")
+}
+
+func TestCopyTradingCompliance_LimitsSignalFrequency(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, conductor := newComplianceTestService(t)
+
+	for i := 0; i < entities.ComplianceMaxSignalsPerHour; i++ {
+		signal, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "buy", decimal.NewFromInt(1), decimal.NewFromInt(100), "o")
+		require.NoError(t, err)
+		require.NotNil(t, signal)
+	}
+
+	signal, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "buy", decimal.NewFromInt(1), decimal.NewFromInt(100), "o")
+	require.NoError(t, err)
+	assert.Nil(t, signal)
+	require.Len(t, repo.complianceEvents, 1)
+	assert.Equal(t, entities.ComplianceEventFrequencyLimit, repo.complianceEvents[0].EventType)
+
+	// Followers can still exit once the limit is reached
+	signal, err = svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "sell", decimal.NewFromInt(1), decimal.NewFromInt(100), "o")
+	require.NoError(t, err)
+	assert.NotNil(t, signal)
+	assert.Len(t, repo.complianceEvents, 1)
+}
+
+func TestCopyTradingCompliance_FlagsFrontRunningAndSuspends(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, conductor := newComplianceTestService(t)
+	notifier := &mockDrafterNotifier{}
+	svc.SetNotifier(notifier)
+
+	buy, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "buy", decimal.NewFromInt(10), decimal.NewFromInt(100), "o1")
+	require.NoError(t, err)
+	repo.executionLogs = append(repo.executionLogs, &entities.SignalExecutionLog{
+		ID:            uuid.New(),
+		DraftID:       uuid.New(),
+		SignalID:      &buy.ID,
+		ExecutionType: entities.ExecutionTypeSignal,
+		AssetTicker:   "AAPL",
+		Side:          "buy",
+		ExecutedValue: decimal.NewFromInt(5000),
+		Status:        entities.ExecutionStatusSuccess,
+		CreatedAt:     time.Now().UTC(),
+	})
+
+	// Selling into the followers' buys is copied but flagged
+	sell, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "sell", decimal.NewFromInt(10), decimal.NewFromInt(101), "o2")
+	require.NoError(t, err)
+	require.NotNil(t, sell)
+	require.Len(t, repo.complianceEvents, 1)
+	flag := repo.complianceEvents[0]
+	assert.Equal(t, entities.ComplianceEventFrontRunning, flag.EventType)
+	assert.False(t, flag.Blocked)
+	assert.Equal(t, sell.ID, *flag.SignalID)
+
+	report, err := svc.GetComplianceReport(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, report.Conductors, 1)
+	assert.True(t, report.Conductors[0].Suspicious)
+	assert.Equal(t, 1, report.Conductors[0].FrontRunningFlags)
+	assert.Equal(t, "Trader", report.Conductors[0].DisplayName)
+
+	suspended, err := svc.SuspendConductor(ctx, uuid.New(), conductor.ID, "front-running followers")
+	require.NoError(t, err)
+	assert.Equal(t, entities.ConductorStatusSuspended, suspended.Status)
+	assert.Len(t, notifier.titles, 1)
+	_, err = svc.SuspendConductor(ctx, uuid.New(), conductor.ID, "again")
+	assert.EqualError(t, err, "conductor already suspended")
+
+	// A suspended conductor's trades no longer generate signals
+	signal, err := svc.CreateSignalFromConductorTrade(ctx, conductor.UserID, "AAPL", "buy", decimal.NewFromInt(1), decimal.NewFromInt(100), "o3")
+	require.NoError(t, err)
+	assert.Nil(t, signal)
+}
@@ -0,0 +1,707 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+// mockCopyTradingRepository implements copytrading.Repository for testing
+type mockCopyTradingRepository struct {
+	conductors map[uuid.UUID]*entities.Conductor
+	drafts     map[uuid.UUID]*entities.Draft
+	holdings   map[uuid.UUID]map[string]decimal.Decimal
+	statements []*entities.DraftFeeStatement
+	payouts    []*entities.ConductorPayout
+
+	conductorHoldings map[uuid.UUID]map[string]decimal.Decimal
+	tracks            map[uuid.UUID]*entities.Track
+	allocations       map[uuid.UUID][]entities.TrackAllocation
+	trackVersions     []*entities.TrackVersion
+	executionLogs     []*entities.SignalExecutionLog
+	blockOrders       []*entities.BlockOrder
+	signals           []*entities.Signal
+	performance       []*entities.ConductorPerformanceHistory
+	complianceEvents  []*entities.ConductorComplianceEvent
+	blackouts         []*entities.SignalBlackoutWindow
+}
+
+func newMockCopyTradingRepository() *mockCopyTradingRepository {
+	return &mockCopyTradingRepository{
+		conductors: make(map[uuid.UUID]*entities.Conductor),
+		drafts:     make(map[uuid.UUID]*entities.Draft),
+		holdings:   make(map[uuid.UUID]map[string]decimal.Decimal),
+
+		conductorHoldings: make(map[uuid.UUID]map[string]decimal.Decimal),
+		tracks:            make(map[uuid.UUID]*entities.Track),
+		allocations:       make(map[uuid.UUID][]entities.TrackAllocation),
+	}
+}
+
+func (m *mockCopyTradingRepository) GetActiveConductors(ctx context.Context, limit, offset int, sortBy string) ([]*entities.Conductor, int, error) {
+	var conductors []*entities.Conductor
+	for _, c := range m.conductors {
+		if c.Status == entities.ConductorStatusActive {
+			conductors = append(conductors, c)
+		}
+	}
+	total := len(conductors)
+	if offset >= total {
+		return nil, total, nil
+	}
+	return conductors[offset:min(offset+limit, total)], total, nil
+}
+
+func (m *mockCopyTradingRepository) GetActiveConductorsAfter(ctx context.Context, after *entities.Conductor, limit int) ([]*entities.Conductor, error) {
+	// Matches ORDER BY followers_count DESC, id DESC
+	before := func(a, b *entities.Conductor) bool {
+		if a.FollowersCount != b.FollowersCount {
+			return a.FollowersCount > b.FollowersCount
+		}
+		return a.ID.String() > b.ID.String()
+	}
+	var conductors []*entities.Conductor
+	for _, c := range m.conductors {
+		if c.Status == entities.ConductorStatusActive && (after == nil || before(after, c)) {
+			conductors = append(conductors, c)
+		}
+	}
+	sort.Slice(conductors, func(i, j int) bool { return before(conductors[i], conductors[j]) })
+	return conductors[:min(limit, len(conductors))], nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorByID(ctx context.Context, id uuid.UUID) (*entities.Conductor, error) {
+	return m.conductors[id], nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorByUserID(ctx context.Context, userID uuid.UUID) (*entities.Conductor, error) {
+	for _, c := range m.conductors {
+		if c.UserID == userID {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) CreateConductor(ctx context.Context, conductor *entities.Conductor) error {
+	m.conductors[conductor.ID] = conductor
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateConductorAUM(ctx context.Context, conductorID uuid.UUID, aum decimal.Decimal) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) IncrementFollowersCount(ctx context.Context, conductorID uuid.UUID, delta int) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) CreateApplication(ctx context.Context, app *entities.ConductorApplication) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetApplicationByID(ctx context.Context, id uuid.UUID) (*entities.ConductorApplication, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetApplicationByUserID(ctx context.Context, userID uuid.UUID) (*entities.ConductorApplication, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetPendingApplications(ctx context.Context, limit, offset int) ([]*entities.ConductorApplication, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateApplicationStatus(ctx context.Context, id uuid.UUID, status entities.ConductorApplicationStatus, reviewerID *uuid.UUID, reason string) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) CreateTrack(ctx context.Context, track *entities.Track) error {
+	m.tracks[track.ID] = track
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetTrackByID(ctx context.Context, id uuid.UUID) (*entities.Track, error) {
+	return m.tracks[id], nil
+}
+
+func (m *mockCopyTradingRepository) GetTracksByConductorID(ctx context.Context, conductorID uuid.UUID) ([]*entities.Track, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetActiveTracks(ctx context.Context, limit, offset int) ([]*entities.Track, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateTrack(ctx context.Context, track *entities.Track) error {
+	m.tracks[track.ID] = track
+	return nil
+}
+
+func (m *mockCopyTradingRepository) DeleteTrack(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) CreateTrackAllocations(ctx context.Context, allocations []entities.TrackAllocation) error {
+	for _, a := range allocations {
+		m.allocations[a.TrackID] = append(m.allocations[a.TrackID], a)
+	}
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetTrackAllocations(ctx context.Context, trackID uuid.UUID) ([]entities.TrackAllocation, error) {
+	track, ok := m.tracks[trackID]
+	if !ok {
+		return m.allocations[trackID], nil
+	}
+	return m.GetTrackAllocationsByVersion(ctx, trackID, track.Version)
+}
+
+func (m *mockCopyTradingRepository) GetTrackAllocationsByVersion(ctx context.Context, trackID uuid.UUID, version int) ([]entities.TrackAllocation, error) {
+	var allocations []entities.TrackAllocation
+	for _, a := range m.allocations[trackID] {
+		if a.Version == version {
+			allocations = append(allocations, a)
+		}
+	}
+	return allocations, nil
+}
+
+func (m *mockCopyTradingRepository) CreateTrackVersion(ctx context.Context, version *entities.TrackVersion) error {
+	m.trackVersions = append(m.trackVersions, version)
+	if track, ok := m.tracks[version.TrackID]; ok {
+		track.Version = version.Version
+	}
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetTrackVersions(ctx context.Context, trackID uuid.UUID, limit int) ([]*entities.TrackVersion, error) {
+	var versions []*entities.TrackVersion
+	for i := len(m.trackVersions) - 1; i >= 0 && len(versions) < limit; i-- {
+		if m.trackVersions[i].TrackID == trackID {
+			copied := *m.trackVersions[i]
+			versions = append(versions, &copied)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockCopyTradingRepository) CreateDraft(ctx context.Context, draft *entities.Draft) error {
+	m.drafts[draft.ID] = draft
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetDraftByID(ctx context.Context, id uuid.UUID) (*entities.Draft, error) {
+	return m.drafts[id], nil
+}
+
+func (m *mockCopyTradingRepository) GetDraftsByDrafterID(ctx context.Context, drafterID uuid.UUID) ([]*entities.Draft, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetActiveDraftsByConductorID(ctx context.Context, conductorID uuid.UUID) ([]*entities.Draft, error) {
+	var drafts []*entities.Draft
+	for _, d := range m.drafts {
+		if d.ConductorID == conductorID && d.Status == entities.DraftStatusActive {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool { return drafts[i].AllocatedCapital.LessThan(drafts[j].AllocatedCapital) })
+	return drafts, nil
+}
+
+func (m *mockCopyTradingRepository) GetExistingDraft(ctx context.Context, drafterID, conductorID uuid.UUID) (*entities.Draft, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftStatus(ctx context.Context, draftID uuid.UUID, status entities.DraftStatus) error {
+	m.drafts[draftID].Status = status
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftCapital(ctx context.Context, draftID uuid.UUID, newCapital decimal.Decimal) error {
+	draft := m.drafts[draftID]
+	diff := newCapital.Sub(draft.AllocatedCapital)
+	draft.CurrentAUM = draft.CurrentAUM.Add(diff)
+	draft.HighWaterMark = draft.HighWaterMark.Add(diff)
+	draft.StartValue = draft.StartValue.Add(diff)
+	draft.PeakValue = draft.PeakValue.Add(diff)
+	draft.AllocatedCapital = newCapital
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftAUM(ctx context.Context, draftID uuid.UUID, currentAUM, profitLoss decimal.Decimal) error {
+	m.drafts[draftID].CurrentAUM = currentAUM
+	m.drafts[draftID].TotalProfitLoss = profitLoss
+	return nil
+}
+
+func (m *mockCopyTradingRepository) CreateSignal(ctx context.Context, signal *entities.Signal) error {
+	m.signals = append(m.signals, signal)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetSignalByID(ctx context.Context, id uuid.UUID) (*entities.Signal, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetPendingSignals(ctx context.Context, limit int) ([]*entities.Signal, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateSignalStatus(ctx context.Context, signalID uuid.UUID, status entities.SignalStatus, processedCount, failedCount int) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetSignalsByConductor(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.Signal, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorHoldings(ctx context.Context, conductorID uuid.UUID) (map[string]decimal.Decimal, error) {
+	return m.conductorHoldings[conductorID], nil
+}
+
+func (m *mockCopyTradingRepository) CreateExecutionLog(ctx context.Context, log *entities.SignalExecutionLog) error {
+	m.executionLogs = append(m.executionLogs, log)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetExecutionLogByIdempotencyKey(ctx context.Context, key string) (*entities.SignalExecutionLog, error) {
+	for _, log := range m.executionLogs {
+		if log.IdempotencyKey == key {
+			return log, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetExecutionLogsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.SignalExecutionLog, error) {
+	return nil, nil
+}
+
+func (m *mockCopyTradingRepository) GetDraftsForFeeProcessing(ctx context.Context, limit int) ([]*entities.Draft, error) {
+	var result []*entities.Draft
+	for _, d := range m.drafts {
+		if d.Status == entities.DraftStatusActive || d.Status == entities.DraftStatusPaused {
+			copied := *d
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCopyTradingRepository) GetDraftHoldings(ctx context.Context, draftID uuid.UUID) (map[string]decimal.Decimal, error) {
+	holdings := make(map[string]decimal.Decimal)
+	for ticker, quantity := range m.holdings[draftID] {
+		holdings[ticker] = quantity
+	}
+	for _, log := range m.executionLogs {
+		if log.DraftID != draftID || log.Status != entities.ExecutionStatusSuccess {
+			continue
+		}
+		if log.Side == "buy" {
+			holdings[log.AssetTicker] = holdings[log.AssetTicker].Add(log.ExecutedQuantity)
+		} else {
+			holdings[log.AssetTicker] = holdings[log.AssetTicker].Sub(log.ExecutedQuantity)
+		}
+	}
+	for ticker, quantity := range holdings {
+		if !quantity.IsPositive() {
+			delete(holdings, ticker)
+		}
+	}
+	return holdings, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftFeeAccrual(ctx context.Context, draftID uuid.UUID, accruedFees decimal.Decimal, accruedAt time.Time) error {
+	m.drafts[draftID].AccruedFees = accruedFees
+	m.drafts[draftID].LastFeeAccrualAt = &accruedAt
+	return nil
+}
+
+func (m *mockCopyTradingRepository) RecordFeeCrystallization(ctx context.Context, statement *entities.DraftFeeStatement) error {
+	draft := m.drafts[statement.DraftID]
+	draft.CurrentAUM = draft.CurrentAUM.Sub(statement.AmountCharged)
+	draft.TotalFeesPaid = draft.TotalFeesPaid.Add(statement.AmountCharged)
+	draft.AccruedFees = statement.TotalFee.Sub(statement.AmountCharged)
+	draft.HighWaterMark = statement.ClosingHighWater
+	periodEnd := statement.PeriodEnd
+	draft.LastFeeAccrualAt = &periodEnd
+	draft.LastCrystallized = &periodEnd
+	m.statements = append(m.statements, statement)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetFeeStatementsByDraft(ctx context.Context, draftID uuid.UUID, limit int) ([]*entities.DraftFeeStatement, error) {
+	return m.statements, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateConductorFees(ctx context.Context, conductorID uuid.UUID, feeRate, managementFeeRate decimal.Decimal) error {
+	m.conductors[conductorID].FeeRate = feeRate
+	m.conductors[conductorID].ManagementFee = managementFeeRate
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorsWithUnpaidFees(ctx context.Context) ([]*entities.Conductor, error) {
+	var result []*entities.Conductor
+	for _, c := range m.conductors {
+		earned, paidOut, _ := m.GetConductorFeeTotals(ctx, c.ID)
+		if earned.GreaterThan(paidOut) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCopyTradingRepository) GetLatestChargedFeeStatement(ctx context.Context, conductorID uuid.UUID) (*entities.DraftFeeStatement, error) {
+	var latest *entities.DraftFeeStatement
+	for _, s := range m.statements {
+		if s.ConductorID == conductorID && s.AmountCharged.IsPositive() {
+			latest = s
+		}
+	}
+	return latest, nil
+}
+
+func (m *mockCopyTradingRepository) CreateConductorPayout(ctx context.Context, payout *entities.ConductorPayout) error {
+	for _, p := range m.payouts {
+		if p.ID == payout.ID {
+			return nil
+		}
+	}
+	m.payouts = append(m.payouts, payout)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorPayouts(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorPayout, error) {
+	return m.payouts, nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorFeeTotals(ctx context.Context, conductorID uuid.UUID) (earned, paidOut decimal.Decimal, err error) {
+	for _, s := range m.statements {
+		if s.ConductorID == conductorID {
+			earned = earned.Add(s.AmountCharged)
+		}
+	}
+	for _, p := range m.payouts {
+		if p.ConductorID == conductorID {
+			paidOut = paidOut.Add(p.Amount)
+		}
+	}
+	return earned, paidOut, nil
+}
+
+func (m *mockCopyTradingRepository) CreateBlockOrder(ctx context.Context, order *entities.BlockOrder) error {
+	m.blockOrders = append(m.blockOrders, order)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateBlockOrder(ctx context.Context, order *entities.BlockOrder) error {
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetConductorSignalHistory(ctx context.Context, conductorID uuid.UUID) ([]*entities.Signal, error) {
+	var signals []*entities.Signal
+	for _, s := range m.signals {
+		if s.ConductorID == conductorID {
+			signals = append(signals, s)
+		}
+	}
+	return signals, nil
+}
+
+func (m *mockCopyTradingRepository) UpsertPerformanceSnapshot(ctx context.Context, snapshot *entities.ConductorPerformanceHistory) error {
+	for i, p := range m.performance {
+		if p.ConductorID == snapshot.ConductorID && p.SnapshotDate.Equal(snapshot.SnapshotDate) {
+			m.performance[i] = snapshot
+			return nil
+		}
+	}
+	m.performance = append(m.performance, snapshot)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetPerformanceHistory(ctx context.Context, conductorID uuid.UUID, since time.Time) ([]*entities.ConductorPerformanceHistory, error) {
+	var history []*entities.ConductorPerformanceHistory
+	for _, p := range m.performance {
+		if p.ConductorID == conductorID && !p.SnapshotDate.Before(since) {
+			history = append(history, p)
+		}
+	}
+	return history, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateConductorPerformance(ctx context.Context, conductor *entities.Conductor) error {
+	m.conductors[conductor.ID] = conductor
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftRiskSettings(ctx context.Context, draftID uuid.UUID, settings entities.DraftRiskSettings) error {
+	m.drafts[draftID].DraftRiskSettings = settings
+	return nil
+}
+
+func (m *mockCopyTradingRepository) UpdateDraftRiskState(ctx context.Context, draftID uuid.UUID, peakValue decimal.Decimal, breachReason string) error {
+	m.drafts[draftID].PeakValue = peakValue
+	m.drafts[draftID].RiskBreachReason = breachReason
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetDraftsWithStopLimits(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Draft, error) {
+	var drafts []*entities.Draft
+	for _, d := range m.drafts {
+		if d.Status == entities.DraftStatusActive && d.HasStopLimits() {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool { return drafts[i].ID.String() < drafts[j].ID.String() })
+	for len(drafts) > 0 && drafts[0].ID.String() <= afterID.String() {
+		drafts = drafts[1:]
+	}
+	return drafts[:min(limit, len(drafts))], nil
+}
+
+func (m *mockCopyTradingRepository) CreateComplianceEvent(ctx context.Context, event *entities.ConductorComplianceEvent) error {
+	m.complianceEvents = append(m.complianceEvents, event)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetComplianceEventsByConductor(ctx context.Context, conductorID uuid.UUID, limit int) ([]*entities.ConductorComplianceEvent, error) {
+	var events []*entities.ConductorComplianceEvent
+	for _, e := range m.complianceEvents {
+		if e.ConductorID == conductorID {
+			events = append(events, e)
+		}
+	}
+	return events[:min(limit, len(events))], nil
+}
+
+func (m *mockCopyTradingRepository) GetComplianceEventCounts(ctx context.Context, since time.Time) ([]*entities.ComplianceEventCount, error) {
+	type key struct {
+		conductorID uuid.UUID
+		eventType   entities.ComplianceEventType
+		blocked     bool
+	}
+	byKey := make(map[key]*entities.ComplianceEventCount)
+	var counts []*entities.ComplianceEventCount
+	for _, e := range m.complianceEvents {
+		if e.CreatedAt.Before(since) {
+			continue
+		}
+		k := key{e.ConductorID, e.EventType, e.Blocked}
+		count, ok := byKey[k]
+		if !ok {
+			count = &entities.ComplianceEventCount{ConductorID: e.ConductorID, EventType: e.EventType, Blocked: e.Blocked}
+			byKey[k] = count
+			counts = append(counts, count)
+		}
+		count.Count++
+		if e.CreatedAt.After(count.LastEventAt) {
+			count.LastEventAt = e.CreatedAt
+		}
+	}
+	return counts, nil
+}
+
+func (m *mockCopyTradingRepository) CountConductorTradeSignalsSince(ctx context.Context, conductorID uuid.UUID, since time.Time) (int, error) {
+	count := 0
+	for _, s := range m.signals {
+		if s.ConductorID == conductorID && s.SignalType != entities.SignalTypeRebalance && !s.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockCopyTradingRepository) GetFollowerBuysSince(ctx context.Context, conductorID uuid.UUID, ticker string, since time.Time) (decimal.Decimal, int, error) {
+	conductorSignals := make(map[uuid.UUID]bool)
+	for _, s := range m.signals {
+		if s.ConductorID == conductorID {
+			conductorSignals[s.ID] = true
+		}
+	}
+	value, count := decimal.Zero, 0
+	for _, l := range m.executionLogs {
+		if l.SignalID != nil && conductorSignals[*l.SignalID] && l.AssetTicker == ticker && l.Side == "buy" &&
+			l.Status == entities.ExecutionStatusSuccess && !l.CreatedAt.Before(since) {
+			value = value.Add(l.ExecutedValue)
+			count++
+		}
+	}
+	return value, count, nil
+}
+
+func (m *mockCopyTradingRepository) UpdateConductorStatus(ctx context.Context, conductorID uuid.UUID, status entities.ConductorStatus) error {
+	if c, ok := m.conductors[conductorID]; ok {
+		c.Status = status
+	}
+	return nil
+}
+
+func (m *mockCopyTradingRepository) CreateBlackoutWindow(ctx context.Context, window *entities.SignalBlackoutWindow) error {
+	m.blackouts = append(m.blackouts, window)
+	return nil
+}
+
+func (m *mockCopyTradingRepository) GetBlackoutWindows(ctx context.Context, endingAfter time.Time) ([]*entities.SignalBlackoutWindow, error) {
+	var windows []*entities.SignalBlackoutWindow
+	for _, w := range m.blackouts {
+		if w.EndsAt.After(endingAfter) {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+func (m *mockCopyTradingRepository) GetActiveBlackoutWindows(ctx context.Context, conductorID uuid.UUID, ticker string, at time.Time) ([]*entities.SignalBlackoutWindow, error) {
+	var windows []*entities.SignalBlackoutWindow
+	for _, w := range m.blackouts {
+		if !w.StartsAt.After(at) && w.EndsAt.After(at) &&
+			(w.ConductorID == nil || *w.ConductorID == conductorID) &&
+			(w.AssetTicker == "" || w.AssetTicker == ticker) {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+func (m *mockCopyTradingRepository) DeleteBlackoutWindow(ctx context.Context, id uuid.UUID) (bool, error) {
+	for i, w := range m.blackouts {
+		if w.ID == id {
+			m.blackouts = append(m.blackouts[:i], m.blackouts[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mockCopyTradingAdapter prices assets from a fixed table
+type mockCopyTradingAdapter struct {
+	prices map[string]decimal.Decimal
+}
+
+func (m *mockCopyTradingAdapter) PlaceOrder(ctx context.Context, userID uuid.UUID, symbol string, side string, quantity decimal.Decimal) (string, decimal.Decimal, error) {
+	return "order-1", m.prices[symbol], nil
+}
+
+func (m *mockCopyTradingAdapter) GetCurrentPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	return m.prices[symbol], nil
+}
+
+// mockCopyTradingBalanceProvider tracks a drafter's available balance
+type mockCopyTradingBalanceProvider struct {
+	balance decimal.Decimal
+}
+
+func (m *mockCopyTradingBalanceProvider) GetAvailableBalance(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	return m.balance, nil
+}
+
+func (m *mockCopyTradingBalanceProvider) DeductBalance(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, description string) error {
+	m.balance = m.balance.Sub(amount)
+	return nil
+}
+
+func (m *mockCopyTradingBalanceProvider) AddBalance(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, description string) error {
+	m.balance = m.balance.Add(amount)
+	return nil
+}
+
+func TestCopyTradingFees_HighWaterMarkCrystallizationAndPayout(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypePendingInvestment).Balance = decimal.NewFromInt(1000)
+	balances := &mockCopyTradingBalanceProvider{}
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(200)}}
+
+	svc := copytrading.NewService(repo, balances, adapter, zapLog)
+	svc.SetLedgerService(ledger)
+
+	conductor := &entities.Conductor{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		DisplayName: "Alpha",
+		Status:      entities.ConductorStatusActive,
+		FeeRate:     decimal.NewFromFloat(0.2),
+	}
+	repo.conductors[conductor.ID] = conductor
+
+	// $1,000 draft that bought 5 AAPL at $160 and now holds $200 cash plus 5 AAPL at $200
+	drafterID := uuid.New()
+	draft := &entities.Draft{
+		ID:               uuid.New(),
+		DrafterID:        drafterID,
+		ConductorID:      conductor.ID,
+		Status:           entities.DraftStatusActive,
+		AllocatedCapital: decimal.NewFromInt(1000),
+		CurrentAUM:       decimal.NewFromInt(200),
+		StartValue:       decimal.NewFromInt(1000),
+		HighWaterMark:    decimal.NewFromInt(1000),
+		CreatedAt:        time.Now().Add(-31 * 24 * time.Hour),
+	}
+	repo.drafts[draft.ID] = draft
+	repo.holdings[draft.ID] = map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(5)}
+
+	_, err := svc.UpdateConductorFees(ctx, conductor.UserID, &entities.UpdateConductorFeesRequest{ManagementFeeRate: decimalPtr(decimal.NewFromFloat(0.05))})
+	assert.Error(t, err, "management fee above the cap is rejected")
+
+	// 20% of the $200 gain above the high-water mark is charged, then paid out to the conductor
+	crystallized, err := svc.ProcessFees(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, crystallized)
+	require.Len(t, repo.statements, 1)
+	assert.Equal(t, "40", repo.statements[0].PerformanceFee.String())
+	assert.Equal(t, "40", repo.statements[0].AmountCharged.String())
+	assert.Equal(t, "1160", draft.HighWaterMark.String())
+	assert.Equal(t, "160", draft.CurrentAUM.String())
+	assert.Equal(t, "960", ledger.account(entities.AccountTypePendingInvestment).Balance.String())
+	assert.Equal(t, entities.TransactionTypeCopyTradingFee, ledger.transactions[0].TransactionType)
+
+	require.Len(t, repo.payouts, 1)
+	assert.Equal(t, "40", repo.payouts[0].Amount.String())
+	assert.True(t, ledger.account(entities.AccountTypeConductorPayable).Balance.IsZero())
+	assert.Equal(t, "40", ledger.account(entities.AccountTypeUSDCBalance).Balance.String())
+	// The payout is keyed by the conductor and the statement it pays through, so a retried pass
+	// over the same fees posts once
+	require.Len(t, ledger.transactions, 2)
+	assert.Equal(t, fmt.Sprintf("conductor-payout:%s:%s", conductor.ID, repo.statements[0].ID), ledger.transactions[1].IdempotencyKey)
+
+	// No new gains above the high-water mark means no performance fee
+	statement, err := svc.CrystallizeDraftFees(ctx, draft)
+	require.NoError(t, err)
+	assert.True(t, statement.TotalFee.IsZero())
+
+	// A year of 2% management fee on $1,160 exceeds the $10 of cash left, so the rest carries over
+	_, err = svc.UpdateConductorFees(ctx, conductor.UserID, &entities.UpdateConductorFeesRequest{ManagementFeeRate: decimalPtr(decimal.NewFromFloat(0.02))})
+	require.NoError(t, err)
+	yearAgo := time.Now().Add(-365 * 24 * time.Hour)
+	draft.LastCrystallized = &yearAgo
+	draft.LastFeeAccrualAt = nil
+	draft.CurrentAUM = decimal.NewFromInt(10)
+	draft.HighWaterMark = decimal.NewFromInt(1010)
+
+	statement, err = svc.CrystallizeDraftFees(ctx, draft)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.2, statement.ManagementFee.InexactFloat64(), 0.01)
+	assert.True(t, statement.PerformanceFee.IsZero())
+	assert.Equal(t, "10", statement.AmountCharged.String())
+	assert.InDelta(t, 10.2, draft.AccruedFees.InexactFloat64(), 0.01)
+	assert.True(t, draft.CurrentAUM.IsZero())
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+func TestCopyTradingMirror_CatchUpOnCreateResizeAndConductorExit(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	balances := &mockCopyTradingBalanceProvider{balance: decimal.NewFromInt(5000)}
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{
+		"AAPL": decimal.NewFromInt(100),
+		"MSFT": decimal.NewFromInt(250),
+	}}
+	svc := copytrading.NewService(repo, balances, adapter, zapLog)
+
+	// The conductor holds $1,000 of AAPL and $500 of MSFT out of $2,000 under management
+	conductor := &entities.Conductor{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		DisplayName:    "Alpha",
+		Status:         entities.ConductorStatusActive,
+		SourceAUM:      decimal.NewFromInt(2000),
+		MinDraftAmount: decimal.NewFromInt(100),
+	}
+	repo.conductors[conductor.ID] = conductor
+	repo.conductorHoldings[conductor.ID] = map[string]decimal.Decimal{
+		"AAPL": decimal.NewFromInt(10),
+		"MSFT": decimal.NewFromInt(2),
+	}
+
+	_, err := svc.CreateDraft(ctx, uuid.New(), &entities.CreateDraftRequest{
+		ConductorID:      conductor.ID,
+		AllocatedCapital: decimal.NewFromInt(1000),
+		TrackID:          &uuid.UUID{},
+	})
+	assert.EqualError(t, err, "track mirroring requires portfolio mode")
+
+	drafterID := uuid.New()
+	draft, err := svc.CreateDraft(ctx, drafterID, &entities.CreateDraftRequest{
+		ConductorID:      conductor.ID,
+		AllocatedCapital: decimal.NewFromInt(1000),
+		MirrorMode:       entities.DraftMirrorModePortfolio,
+	})
+	require.NoError(t, err)
+
+	// A new draft starts at the conductor's 50% AAPL / 25% MSFT weights
+	holdings, _ := repo.GetDraftHoldings(ctx, draft.ID)
+	assert.Equal(t, "5", holdings["AAPL"].String())
+	assert.Equal(t, "1", holdings["MSFT"].String())
+	assert.Equal(t, "250", draft.CurrentAUM.String())
+	for _, log := range repo.executionLogs {
+		assert.Equal(t, entities.ExecutionTypeCatchUp, log.ExecutionType)
+		assert.Nil(t, log.SignalID)
+	}
+
+	// Doubling the allocation doubles the positions
+	require.NoError(t, svc.ResizeDraft(ctx, drafterID, draft.ID, decimal.NewFromInt(2000)))
+	holdings, _ = repo.GetDraftHoldings(ctx, draft.ID)
+	assert.Equal(t, "10", holdings["AAPL"].String())
+	assert.Equal(t, "2", holdings["MSFT"].String())
+	assert.Equal(t, "500", draft.CurrentAUM.String())
+
+	// Once the conductor has exited AAPL the draft sells out of it; MSFT is already on target
+	repo.conductorHoldings[conductor.ID] = map[string]decimal.Decimal{"MSFT": decimal.NewFromInt(2)}
+	orders, err := svc.SyncUserDraft(ctx, drafterID, draft.ID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "sell", orders[0].Side)
+	assert.Equal(t, "AAPL", orders[0].AssetTicker)
+	holdings, _ = repo.GetDraftHoldings(ctx, draft.ID)
+	assert.NotContains(t, holdings, "AAPL")
+	assert.Equal(t, "1500", draft.CurrentAUM.String())
+
+	// A synced draft produces no further orders
+	orders, err = svc.SyncUserDraft(ctx, drafterID, draft.ID)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+}
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+func TestCopyTradingPerformance_ComputesReturnsDrawdownAndWinRateFromSignals(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"TSLA": decimal.NewFromInt(100)}}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+
+	conductor := &entities.Conductor{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Status:         entities.ConductorStatusActive,
+		SourceAUM:      decimal.NewFromInt(10000),
+		FollowersCount: 4,
+	}
+	repo.conductors[conductor.ID] = conductor
+
+	// One winning AAPL round-trip, one losing MSFT round-trip closed in two sells, and an open TSLA position
+	day1 := time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC)
+	trades := []struct {
+		ticker, side string
+		quantity     int64
+		price        int64
+	}{
+		{"AAPL", "buy", 10, 100},
+		{"AAPL", "sell", 10, 120},
+		{"MSFT", "buy", 10, 200},
+		{"MSFT", "sell", 5, 180},
+		{"MSFT", "sell", 5, 190},
+		{"TSLA", "buy", 20, 100},
+	}
+	for i, trade := range trades {
+		repo.signals = append(repo.signals, &entities.Signal{
+			ID:                   uuid.New(),
+			ConductorID:          conductor.ID,
+			AssetTicker:          trade.ticker,
+			Side:                 trade.side,
+			BaseQuantity:         decimal.NewFromInt(trade.quantity),
+			BasePrice:            decimal.NewFromInt(trade.price),
+			ConductorAUMAtSignal: decimal.NewFromInt(10000),
+			CreatedAt:            day1.Add(-time.Duration(6-i) * time.Hour),
+		})
+	}
+
+	snapshot, err := svc.ComputeConductorPerformance(ctx, conductor, day1)
+	require.NoError(t, err)
+	assert.Equal(t, "10050", snapshot.AUM.String())
+	assert.Equal(t, "0.005", snapshot.CumulativeReturn.String())
+	assert.Equal(t, 6, snapshot.TradesCount)
+	assert.Equal(t, 4, snapshot.FollowersCount)
+	assert.Equal(t, "0.5", conductor.WinRate.String())
+	assert.Equal(t, 6, conductor.TotalTrades)
+
+	// TSLA falls, then recovers past the previous peak
+	adapter.prices["TSLA"] = decimal.NewFromInt(90)
+	_, err = svc.ComputeConductorPerformance(ctx, conductor, day1.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "9850", conductor.SourceAUM.String())
+	assert.Equal(t, "-0.015", conductor.TotalReturn.String())
+	assert.Equal(t, "0.0199", conductor.MaxDrawdown.String())
+
+	adapter.prices["TSLA"] = decimal.NewFromInt(110)
+	_, err = svc.ComputeConductorPerformance(ctx, conductor, day1.AddDate(0, 0, 2))
+	require.NoError(t, err)
+
+	// Rerunning a day replaces its snapshot instead of compounding on it
+	adapter.prices["TSLA"] = decimal.NewFromInt(120)
+	_, err = svc.ComputeConductorPerformance(ctx, conductor, day1.AddDate(0, 0, 2).Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "10450", conductor.SourceAUM.String())
+	assert.Equal(t, "0.045", conductor.TotalReturn.String())
+	assert.Equal(t, "0.0199", conductor.MaxDrawdown.String())
+	assert.True(t, conductor.SharpeRatio.IsPositive())
+
+	chart, err := svc.GetConductorPerformance(ctx, conductor.ID, "all")
+	require.NoError(t, err)
+	require.Len(t, chart.Points, 3)
+	assert.Equal(t, "0.045", chart.TotalReturn.String())
+	assert.Equal(t, "0.045", chart.Points[2].CumulativeReturn.String())
+
+	_, err = svc.GetConductorPerformance(ctx, conductor.ID, "5y")
+	assert.EqualError(t, err, "invalid period")
+
+	updated, err := svc.ProcessConductorPerformance(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+}
+
+func TestCopyTradingPerformance_ProcessPagesByFollowersAndID(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zap.NewNop())
+
+	// Ties on followers_count are broken by ID, so small pages cover every conductor once
+	for _, followers := range []int{5, 3, 3, 3, 0} {
+		conductor := &entities.Conductor{
+			ID:             uuid.New(),
+			UserID:         uuid.New(),
+			Status:         entities.ConductorStatusActive,
+			SourceAUM:      decimal.NewFromInt(10_000),
+			FollowersCount: followers,
+		}
+		repo.conductors[conductor.ID] = conductor
+	}
+
+	updated, err := svc.ProcessConductorPerformance(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, updated)
+	seen := make(map[uuid.UUID]bool)
+	for _, snapshot := range repo.performance {
+		seen[snapshot.ConductorID] = true
+	}
+	assert.Len(t, seen, 5)
+}
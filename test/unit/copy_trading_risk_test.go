@@ -0,0 +1,166 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+// mockDrafterNotifier records the notifications sent to drafters
+type mockDrafterNotifier struct {
+	titles   []string
+	messages []string
+}
+
+func (m *mockDrafterNotifier) SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error {
+	m.titles = append(m.titles, title)
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func newRiskTestDraft(repo *mockCopyTradingRepository, conductorID uuid.UUID, cash, startValue int64, holdings map[string]decimal.Decimal) *entities.Draft {
+	draft := &entities.Draft{
+		ID:               uuid.New(),
+		DrafterID:        uuid.New(),
+		ConductorID:      conductorID,
+		Status:           entities.DraftStatusActive,
+		AllocatedCapital: decimal.NewFromInt(startValue),
+		CurrentAUM:       decimal.NewFromInt(cash),
+		StartValue:       decimal.NewFromInt(startValue),
+		PeakValue:        decimal.NewFromInt(startValue),
+		CopyRatio:        decimal.NewFromInt(1),
+	}
+	repo.drafts[draft.ID] = draft
+	repo.holdings[draft.ID] = holdings
+	return draft
+}
+
+func TestCopyTradingRisk_CapsBuysBySizeAndConcentration(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+
+	// $1,000 cash plus 2 AAPL, limited to $250 a trade and 25% of its $1,200 value in one asset
+	conductorID := uuid.New()
+	draft := newRiskTestDraft(repo, conductorID, 1000, 1000, map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(2)})
+	_, err := svc.UpdateDraftRisk(ctx, draft.DrafterID, draft.ID, &entities.UpdateDraftRiskRequest{
+		MaxConcentration: decimalPtr(decimal.NewFromFloat(0.25)),
+		MaxTradeValue:    decimalPtr(decimal.NewFromInt(250)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.DraftRiskActionPause, draft.BreachAction)
+
+	_, err = svc.UpdateDraftRisk(ctx, draft.DrafterID, draft.ID, &entities.UpdateDraftRiskRequest{
+		TrailingStop: decimalPtr(decimal.NewFromFloat(1.5)),
+	})
+	assert.EqualError(t, err, "invalid risk settings: trailing stop must be between 0 and 1")
+
+	// The conductor's $500 buy is cut to $250, then to the $100 left under the $300 concentration cap
+	signal := &entities.Signal{
+		ID:                   uuid.New(),
+		ConductorID:          conductorID,
+		AssetTicker:          "AAPL",
+		Side:                 "buy",
+		BaseQuantity:         decimal.NewFromInt(5),
+		ConductorAUMAtSignal: decimal.NewFromInt(1000),
+	}
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+	require.Len(t, repo.executionLogs, 1)
+	assert.Equal(t, entities.ExecutionStatusSuccess, repo.executionLogs[0].Status)
+	assert.Equal(t, "1", repo.executionLogs[0].ExecutedQuantity.String())
+
+	// With the position at its cap the next buy is skipped
+	signal.ID = uuid.New()
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+	require.Len(t, repo.executionLogs, 2)
+	assert.Equal(t, entities.ExecutionStatusSkippedRiskLimit, repo.executionLogs[1].Status)
+}
+
+func TestCopyTradingRisk_TrailingStopPausesAndNotifies(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	notifier := &mockDrafterNotifier{}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+	svc.SetNotifier(notifier)
+
+	conductor := &entities.Conductor{ID: uuid.New(), DisplayName: "Alpha", Status: entities.ConductorStatusActive}
+	repo.conductors[conductor.ID] = conductor
+	draft := newRiskTestDraft(repo, conductor.ID, 0, 1000, map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(10)})
+	_, err := svc.UpdateDraftRisk(ctx, draft.DrafterID, draft.ID, &entities.UpdateDraftRiskRequest{
+		TrailingStop: decimalPtr(decimal.NewFromFloat(0.1)),
+	})
+	require.NoError(t, err)
+
+	// A rise to $1,200 raises the peak without tripping the stop
+	adapter.prices["AAPL"] = decimal.NewFromInt(120)
+	breached, err := svc.ProcessDraftRisk(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, breached)
+	assert.Equal(t, "1200", draft.PeakValue.String())
+
+	// Falling 12.5% from the peak pauses the draft and tells the drafter why
+	adapter.prices["AAPL"] = decimal.NewFromInt(105)
+	breached, err = svc.ProcessDraftRisk(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, breached)
+	assert.Equal(t, entities.DraftStatusPaused, draft.Status)
+	assert.Contains(t, draft.RiskBreachReason, "10% trailing stop")
+	require.Len(t, notifier.messages, 1)
+	assert.Equal(t, "Copy trading draft paused", notifier.titles[0])
+	assert.Contains(t, notifier.messages[0], "We paused copying Alpha because its value fell 12.5% from its peak of $1200.00")
+
+	// Resuming restarts the trailing stop from the current value
+	require.NoError(t, svc.ResumeDraft(ctx, draft.DrafterID, draft.ID))
+	assert.Equal(t, entities.DraftStatusActive, draft.Status)
+	assert.Empty(t, draft.RiskBreachReason)
+	assert.Equal(t, "1050", draft.PeakValue.String())
+}
+
+func TestCopyTradingRisk_MaxDrawdownLiquidatesAndUnlinks(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(100)}}
+	balances := &mockCopyTradingBalanceProvider{}
+	notifier := &mockDrafterNotifier{}
+	svc := copytrading.NewService(repo, balances, adapter, zapLog)
+	svc.SetNotifier(notifier)
+
+	conductor := &entities.Conductor{ID: uuid.New(), DisplayName: "Alpha", Status: entities.ConductorStatusActive}
+	repo.conductors[conductor.ID] = conductor
+	draft := newRiskTestDraft(repo, conductor.ID, 500, 1000, map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(5)})
+	_, err := svc.UpdateDraftRisk(ctx, draft.DrafterID, draft.ID, &entities.UpdateDraftRiskRequest{
+		MaxDrawdown:  decimalPtr(decimal.NewFromFloat(0.2)),
+		BreachAction: entities.DraftRiskActionUnlink,
+	})
+	require.NoError(t, err)
+
+	// Halving AAPL leaves the draft 25% below its $1,000 start
+	adapter.prices["AAPL"] = decimal.NewFromInt(50)
+	breached, err := svc.ProcessDraftRisk(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, breached)
+
+	require.Len(t, repo.executionLogs, 1)
+	assert.Equal(t, entities.ExecutionTypeLiquidation, repo.executionLogs[0].ExecutionType)
+	assert.Equal(t, "sell", repo.executionLogs[0].Side)
+	assert.Equal(t, "5", repo.executionLogs[0].ExecutedQuantity.String())
+
+	assert.Equal(t, entities.DraftStatusUnlinked, draft.Status)
+	assert.Equal(t, "750", balances.balance.String())
+	require.Len(t, notifier.messages, 1)
+	assert.Equal(t, "Copy trading draft closed", notifier.titles[0])
+	assert.Contains(t, notifier.messages[0], "25% below its starting value, past your 20% max drawdown")
+}
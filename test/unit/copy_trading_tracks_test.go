@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/copytrading"
+)
+
+func TestCopyTradingTracks_NewVersionRebalancesFollowersByAllocationDiff(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockCopyTradingRepository()
+	adapter := &mockCopyTradingAdapter{prices: map[string]decimal.Decimal{
+		"AAPL": decimal.NewFromInt(100),
+		"MSFT": decimal.NewFromInt(100),
+		"NVDA": decimal.NewFromInt(50),
+	}}
+	svc := copytrading.NewService(repo, &mockCopyTradingBalanceProvider{}, adapter, zapLog)
+
+	conductor := &entities.Conductor{ID: uuid.New(), UserID: uuid.New(), Status: entities.ConductorStatusActive}
+	repo.conductors[conductor.ID] = conductor
+
+	track, err := svc.CreateTrack(ctx, conductor.UserID, &entities.CreateTrackRequest{
+		Name:        "Big Tech",
+		Description: "Large cap technology",
+		RiskLevel:   "medium",
+		Allocations: []entities.CreateTrackAllocationRequest{
+			{AssetTicker: "AAPL", AssetName: "Apple", TargetWeight: decimal.NewFromInt(60)},
+			{AssetTicker: "MSFT", AssetName: "Microsoft", TargetWeight: decimal.NewFromInt(40)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, track.Version)
+
+	// A $1,000 follower at the track's weights, and a signal-mode draft of the same conductor
+	follower := &entities.Draft{
+		ID:               uuid.New(),
+		DrafterID:        uuid.New(),
+		ConductorID:      conductor.ID,
+		Status:           entities.DraftStatusActive,
+		AllocatedCapital: decimal.NewFromInt(1000),
+		CurrentAUM:       decimal.Zero,
+		StartValue:       decimal.NewFromInt(1000),
+		CopyRatio:        decimal.NewFromInt(1),
+		MirrorMode:       entities.DraftMirrorModePortfolio,
+		TrackID:          &track.ID,
+	}
+	repo.drafts[follower.ID] = follower
+	repo.holdings[follower.ID] = map[string]decimal.Decimal{"AAPL": decimal.NewFromInt(6), "MSFT": decimal.NewFromInt(4)}
+	other := newRiskTestDraft(repo, conductor.ID, 1000, 1000, nil)
+
+	_, err = svc.UpdateTrack(ctx, conductor.UserID, track.ID, &entities.UpdateTrackRequest{
+		Allocations: []entities.CreateTrackAllocationRequest{
+			{AssetTicker: "AAPL", AssetName: "Apple", TargetWeight: decimal.NewFromInt(60)},
+			{AssetTicker: "MSFT", AssetName: "Microsoft", TargetWeight: decimal.NewFromInt(30)},
+		},
+	})
+	assert.EqualError(t, err, "allocations must sum to 100%, got 90%")
+
+	updated, err := svc.UpdateTrack(ctx, conductor.UserID, track.ID, &entities.UpdateTrackRequest{
+		Allocations: []entities.CreateTrackAllocationRequest{
+			{AssetTicker: "AAPL", AssetName: "Apple", TargetWeight: decimal.NewFromInt(30)},
+			{AssetTicker: "MSFT", AssetName: "Microsoft", TargetWeight: decimal.NewFromInt(50)},
+			{AssetTicker: "NVDA", AssetName: "Nvidia", TargetWeight: decimal.NewFromInt(20)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	require.Len(t, repo.signals, 1)
+	signal := repo.signals[0]
+	assert.Equal(t, entities.SignalTypeRebalance, signal.SignalType)
+	assert.Equal(t, 2, *signal.TrackVersion)
+
+	// Republishing the same weights creates no version
+	_, err = svc.UpdateTrack(ctx, conductor.UserID, track.ID, &entities.UpdateTrackRequest{
+		Allocations: []entities.CreateTrackAllocationRequest{
+			{AssetTicker: "NVDA", AssetName: "Nvidia", TargetWeight: decimal.NewFromInt(20)},
+			{AssetTicker: "AAPL", AssetName: "Apple", TargetWeight: decimal.NewFromInt(30)},
+			{AssetTicker: "MSFT", AssetName: "Microsoft", TargetWeight: decimal.NewFromInt(50)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, repo.signals, 1)
+
+	// The follower sells $300 of AAPL and buys $100 of MSFT and $200 of NVDA; the other draft is untouched
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+	orders := make(map[string]*entities.SignalExecutionLog)
+	for _, log := range repo.executionLogs {
+		require.Equal(t, follower.ID, log.DraftID)
+		orders[log.Side+" "+log.AssetTicker] = log
+	}
+	require.Len(t, orders, 3)
+	assert.Equal(t, "3", orders["sell AAPL"].ExecutedQuantity.String())
+	assert.Equal(t, "1", orders["buy MSFT"].ExecutedQuantity.String())
+	assert.Equal(t, "4", orders["buy NVDA"].ExecutedQuantity.String())
+	assert.True(t, follower.CurrentAUM.IsZero())
+	assert.Equal(t, "1000", other.CurrentAUM.String())
+
+	// Reprocessing the signal places no further orders
+	require.NoError(t, svc.ProcessSignal(ctx, signal))
+	assert.Len(t, repo.executionLogs, 3)
+
+	versions, err := svc.GetTrackVersions(ctx, track.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, signal.ID, *versions[0].SignalID)
+	require.Len(t, versions[0].Changes, 3)
+	assert.Equal(t, "AAPL", versions[0].Changes[0].AssetTicker)
+	assert.Equal(t, "60", versions[0].Changes[0].PreviousWeight.String())
+	assert.Equal(t, "30", versions[0].Changes[0].NewWeight.String())
+	assert.Len(t, versions[1].Changes, 2)
+}
@@ -0,0 +1,312 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/dividend"
+)
+
+// mockDividendRepository implements dividend.Repository for testing
+type mockDividendRepository struct {
+	settings  map[uuid.UUID]*entities.DividendSettings
+	dividends []*entities.Dividend
+}
+
+func newMockDividendRepository() *mockDividendRepository {
+	return &mockDividendRepository{settings: make(map[uuid.UUID]*entities.DividendSettings)}
+}
+
+func (m *mockDividendRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.DividendSettings, error) {
+	return m.settings[userID], nil
+}
+
+func (m *mockDividendRepository) UpsertSettings(ctx context.Context, settings *entities.DividendSettings) error {
+	m.settings[settings.UserID] = settings
+	return nil
+}
+
+func (m *mockDividendRepository) GetByActivityID(ctx context.Context, activityID string) (*entities.Dividend, error) {
+	for _, d := range m.dividends {
+		if d.ActivityID == activityID {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDividendRepository) Create(ctx context.Context, dividend *entities.Dividend) error {
+	m.dividends = append(m.dividends, dividend)
+	return nil
+}
+
+func (m *mockDividendRepository) Update(ctx context.Context, dividend *entities.Dividend) error {
+	return nil
+}
+
+func (m *mockDividendRepository) GetWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, d := range m.dividends {
+		if d.UserID == userID && d.Symbol == symbol && d.PayDate.Equal(payDate) {
+			total = total.Add(d.WithholdingAmount)
+		}
+	}
+	return total, nil
+}
+
+func (m *mockDividendRepository) GetAwaitingWithholding(ctx context.Context, userID uuid.UUID, symbol string, payDate time.Time) ([]*entities.Dividend, error) {
+	var result []*entities.Dividend
+	for _, d := range m.dividends {
+		if d.UserID == userID && d.Symbol == symbol && d.PayDate.Equal(payDate) && d.ReinvestStatus == entities.DividendReinvestStatusAwaiting {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDividendRepository) ListAwaitingWithholding(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.Dividend, error) {
+	var result []*entities.Dividend
+	for _, d := range m.dividends {
+		if d.ReinvestStatus == entities.DividendReinvestStatusAwaiting && d.CreatedAt.Before(createdBefore) && len(result) < limit {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDividendRepository) ClaimAwaiting(ctx context.Context, dividendID uuid.UUID) (bool, error) {
+	for _, d := range m.dividends {
+		if d.ID == dividendID && d.ReinvestStatus == entities.DividendReinvestStatusAwaiting {
+			d.ReinvestStatus = entities.DividendReinvestStatusNone
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDividendRepository) GetByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.Dividend, error) {
+	var result []*entities.Dividend
+	for _, d := range m.dividends {
+		if d.UserID == userID && !d.PayDate.Before(from) && d.PayDate.Before(to) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// flakyDividendLedger fails ledger posts while fail is set
+type flakyDividendLedger struct {
+	*mockCardLedgerService
+	fail bool
+}
+
+func (m *flakyDividendLedger) CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error) {
+	if m.fail {
+		return nil, errors.New("ledger unavailable")
+	}
+	return m.mockCardLedgerService.CreateTransaction(ctx, req)
+}
+
+func TestDividendService_CreditsWithholdsAndReinvestsOnce(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockDividendRepository()
+	ledger := newMockCardLedgerService()
+	contributions := &mockContributionRecorder{}
+	orders := &mockHarvestOrderPlacer{}
+
+	svc := dividend.NewService(repo, ledger, contributions, zapLog)
+	svc.SetOrderPlacer(orders)
+
+	userID := uuid.New()
+	_, err := svc.UpdateSettings(ctx, userID, "monthly")
+	assert.EqualError(t, err, "invalid reinvest mode")
+	_, err = svc.UpdateSettings(ctx, userID, entities.DividendReinvestSameAsset)
+	require.NoError(t, err)
+
+	payDate := time.Now().UTC().Format("2006-01-02")
+	activity := func(id, activityType, netAmount string) *entities.AlpacaNonTradeActivityEvent {
+		return &entities.AlpacaNonTradeActivityEvent{
+			ID:             id,
+			ActivityType:   activityType,
+			Symbol:         "ko",
+			NetAmount:      decimal.RequireFromString(netAmount),
+			Qty:            decimal.NewFromInt(20),
+			PerShareAmount: decimal.RequireFromString("0.5"),
+			Date:           payDate,
+		}
+	}
+
+	// Withholding on a nonresident's dividend is posted even with no buying power to cover it
+	withheld, err := svc.RecordDividend(ctx, userID, activity("act-1", entities.ActivityTypeDividendNRAWithholding, "-1.5"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", withheld.WithholdingAmount.String())
+	assert.Equal(t, entities.DividendReinvestStatusNone, withheld.ReinvestStatus)
+
+	// The dividend is credited gross and the amount left after withholding is reinvested
+	paid, err := svc.RecordDividend(ctx, userID, activity("act-2", entities.ActivityTypeDividend, "10"))
+	require.NoError(t, err)
+	assert.Equal(t, "KO", paid.Symbol)
+	assert.Equal(t, "10", paid.GrossAmount.String())
+	assert.Equal(t, entities.DividendReinvestStatusPlaced, paid.ReinvestStatus)
+	assert.Equal(t, "8.5", paid.ReinvestAmount.String())
+	require.Len(t, orders.orders, 1)
+	assert.Equal(t, "KO", orders.orders[0].Symbol)
+	assert.Equal(t, "8.5", orders.orders[0].Notional.String())
+
+	// Withholding posted before any buying power was owed and is settled from the dividend
+	assert.Equal(t, "8.5", ledger.account(entities.AccountTypeFiatExposure).Balance.String())
+	assert.True(t, ledger.account(entities.AccountTypeWithholdingReceivable).Balance.IsZero())
+	assert.Equal(t, "-8.5", ledger.account(entities.AccountTypeBrokerIncoming).Balance.String())
+	assert.True(t, ledger.account(entities.AccountTypeBrokerOperational).Balance.IsZero())
+	require.Len(t, ledger.transactions, 2)
+	assert.Equal(t, entities.TransactionTypeDividend, ledger.transactions[1].TransactionType)
+	assert.Equal(t, "dividend:act-2", ledger.transactions[1].IdempotencyKey)
+	require.Len(t, contributions.contributions, 1)
+	assert.Equal(t, entities.ContributionTypeDividend, contributions.contributions[0].Type)
+
+	// A redelivered webhook neither posts nor reinvests again
+	again, err := svc.RecordDividend(ctx, userID, activity("act-2", entities.ActivityTypeDividend, "10"))
+	require.NoError(t, err)
+	assert.Equal(t, paid.ID, again.ID)
+	assert.Len(t, ledger.transactions, 2)
+	assert.Len(t, orders.orders, 1)
+
+	// Dividends under the minimum stay as cash
+	small, err := svc.RecordDividend(ctx, userID, activity("act-3", entities.ActivityTypeDividend, "0.4"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.DividendReinvestStatusSkipped, small.ReinvestStatus)
+	assert.Len(t, orders.orders, 1)
+
+	report, err := svc.GetDividendReport(ctx, userID, time.Now().UTC().Year())
+	require.NoError(t, err)
+	assert.Equal(t, "10.4", report.Gross.String())
+	assert.Equal(t, "1.5", report.Withholding.String())
+	assert.Equal(t, "8.9", report.Net.String())
+	assert.Equal(t, "8.5", report.Reinvested.String())
+	require.Len(t, report.BySymbol, 1)
+	assert.Equal(t, 2, report.BySymbol[0].Payments)
+}
+
+func TestDividendService_RepostsDividendWhoseLedgerPostFailed(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockDividendRepository()
+	ledger := &flakyDividendLedger{mockCardLedgerService: newMockCardLedgerService(), fail: true}
+	orders := &mockHarvestOrderPlacer{}
+
+	svc := dividend.NewService(repo, ledger, &mockContributionRecorder{}, zapLog)
+	svc.SetOrderPlacer(orders)
+
+	userID := uuid.New()
+	_, err := svc.UpdateSettings(ctx, userID, entities.DividendReinvestSameAsset)
+	require.NoError(t, err)
+
+	activity := &entities.AlpacaNonTradeActivityEvent{
+		ID:             "act-1",
+		ActivityType:   entities.ActivityTypeDividend,
+		Symbol:         "KO",
+		NetAmount:      decimal.NewFromInt(10),
+		Qty:            decimal.NewFromInt(20),
+		PerShareAmount: decimal.RequireFromString("0.5"),
+		Date:           time.Now().UTC().Format("2006-01-02"),
+	}
+
+	// The dividend is recorded but never credited or reinvested
+	_, err = svc.RecordDividend(ctx, userID, activity)
+	require.Error(t, err)
+	require.Len(t, repo.dividends, 1)
+	assert.Nil(t, repo.dividends[0].LedgerTransactionID)
+	assert.Empty(t, orders.orders)
+
+	// The redelivered webhook posts it and holds it for withholding
+	ledger.fail = false
+	paid, err := svc.RecordDividend(ctx, userID, activity)
+	require.NoError(t, err)
+	assert.Equal(t, repo.dividends[0].ID, paid.ID)
+	assert.NotNil(t, paid.LedgerTransactionID)
+	assert.Equal(t, "10", ledger.account(entities.AccountTypeFiatExposure).Balance.String())
+	require.Len(t, ledger.transactions, 1)
+	assert.Equal(t, "dividend:act-1", ledger.transactions[0].IdempotencyKey)
+	assert.Equal(t, entities.DividendReinvestStatusAwaiting, paid.ReinvestStatus)
+	assert.Empty(t, orders.orders)
+
+	// No withholding arrives, so it is reinvested in full once the wait runs out
+	processed, err := svc.ReinvestAwaitingDividends(ctx, time.Now().Add(time.Minute), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	require.Len(t, orders.orders, 1)
+	assert.Equal(t, "10", orders.orders[0].Notional.String())
+	assert.Equal(t, entities.DividendReinvestStatusPlaced, paid.ReinvestStatus)
+
+	// Once credited, further redeliveries are ignored
+	_, err = svc.RecordDividend(ctx, userID, activity)
+	require.NoError(t, err)
+	assert.Len(t, ledger.transactions, 1)
+	assert.Len(t, orders.orders, 1)
+}
+
+func TestDividendService_DividendBeforeWithholdingReinvestsNetAndBooksShortfall(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockDividendRepository()
+	ledger := newMockCardLedgerService()
+	orders := &mockHarvestOrderPlacer{}
+
+	svc := dividend.NewService(repo, ledger, &mockContributionRecorder{}, zapLog)
+	svc.SetOrderPlacer(orders)
+
+	userID := uuid.New()
+	_, err := svc.UpdateSettings(ctx, userID, entities.DividendReinvestSameAsset)
+	require.NoError(t, err)
+
+	payDate := time.Now().UTC().Format("2006-01-02")
+	activity := func(id, activityType, symbol, netAmount string) *entities.AlpacaNonTradeActivityEvent {
+		return &entities.AlpacaNonTradeActivityEvent{
+			ID:           id,
+			ActivityType: activityType,
+			Symbol:       symbol,
+			NetAmount:    decimal.RequireFromString(netAmount),
+			Date:         payDate,
+		}
+	}
+
+	// The dividend arrives first and waits for its withholding
+	paid, err := svc.RecordDividend(ctx, userID, activity("act-1", entities.ActivityTypeDividend, "KO", "10"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.DividendReinvestStatusAwaiting, paid.ReinvestStatus)
+	assert.Empty(t, orders.orders)
+
+	// The withholding releases it, reinvested net of the tax
+	_, err = svc.RecordDividend(ctx, userID, activity("act-2", entities.ActivityTypeDividendNRAWithholding, "KO", "-1.5"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.DividendReinvestStatusPlaced, paid.ReinvestStatus)
+	assert.Equal(t, "8.5", paid.ReinvestAmount.String())
+	require.Len(t, orders.orders, 1)
+	assert.Equal(t, "8.5", orders.orders[0].Notional.String())
+	assert.Equal(t, "8.5", ledger.account(entities.AccountTypeFiatExposure).Balance.String())
+
+	// The sweep leaves a dividend already reinvested alone
+	processed, err := svc.ReinvestAwaitingDividends(ctx, time.Now().Add(time.Minute), 100)
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+	assert.Len(t, orders.orders, 1)
+
+	// Withholding the user's buying power cannot cover is still posted, with the rest owed
+	ledger.account(entities.AccountTypeFiatExposure).Balance = decimal.NewFromInt(1)
+	_, err = svc.RecordDividend(ctx, userID, activity("act-3", entities.ActivityTypeDividendNRAWithholding, "PEP", "-3"))
+	require.NoError(t, err)
+	assert.True(t, ledger.account(entities.AccountTypeFiatExposure).Balance.IsZero())
+	assert.Equal(t, "-2", ledger.account(entities.AccountTypeWithholdingReceivable).Balance.String())
+	require.Len(t, ledger.transactions, 3)
+	assert.Len(t, ledger.transactions[2].Entries, 3)
+}
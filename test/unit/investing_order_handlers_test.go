@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/api/handlers"
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/investing"
+	"github.com/rail-service/rail_service/internal/infrastructure/adapters/alpaca"
+)
+
+// mockInvestingBasketRepository serves a single basket
+type mockInvestingBasketRepository struct {
+	basket *entities.Basket
+}
+
+func (m *mockInvestingBasketRepository) GetAll(ctx context.Context) ([]*entities.Basket, error) {
+	return []*entities.Basket{m.basket}, nil
+}
+
+func (m *mockInvestingBasketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
+	if m.basket.ID != id {
+		return nil, nil
+	}
+	return m.basket, nil
+}
+
+// mockInvestingOrderRepository keeps orders in memory
+type mockInvestingOrderRepository struct {
+	orders map[uuid.UUID]*entities.Order
+}
+
+func (m *mockInvestingOrderRepository) Create(ctx context.Context, order *entities.Order) error {
+	if _, ok := m.orders[order.ID]; ok {
+		return fmt.Errorf("duplicate order %s", order.ID)
+	}
+	stored := *order
+	m.orders[order.ID] = &stored
+	return nil
+}
+
+func (m *mockInvestingOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	if order, ok := m.orders[id]; ok {
+		copied := *order
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockInvestingOrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int, status *entities.OrderStatus) ([]*entities.Order, error) {
+	return nil, nil
+}
+
+func (m *mockInvestingOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.OrderStatus, brokerageRef *string) error {
+	m.orders[id].Status = status
+	m.orders[id].BrokerageRef = brokerageRef
+	return nil
+}
+
+// mockBuyingPower reserves and releases a single user's buying power, and doubles as the basket
+// executor's funds releaser so both sides use the same balance
+type mockBuyingPower struct {
+	buyingPower decimal.Decimal
+}
+
+func (m *mockBuyingPower) Get(ctx context.Context, userID uuid.UUID) (*entities.Balance, error) {
+	return &entities.Balance{UserID: userID, BuyingPower: m.buyingPower, Currency: "USD"}, nil
+}
+
+func (m *mockBuyingPower) DeductBuyingPower(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	if m.buyingPower.LessThan(amount) {
+		return fmt.Errorf("insufficient buying power")
+	}
+	m.buyingPower = m.buyingPower.Sub(amount)
+	return nil
+}
+
+func (m *mockBuyingPower) AddBuyingPower(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	m.buyingPower = m.buyingPower.Add(amount)
+	return nil
+}
+
+func (m *mockBuyingPower) ReleaseReservation(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	return m.AddBuyingPower(ctx, userID, amount)
+}
+
+// mockBrokerageAccounts gives every user the same brokerage account
+type mockBrokerageAccounts struct{}
+
+func (m *mockBrokerageAccounts) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.AlpacaAccount, error) {
+	return &entities.AlpacaAccount{UserID: userID, AlpacaAccountID: "acct-1"}, nil
+}
+
+func TestInvestingHandlers_CreateOrder_ExecutesBasketIdempotently(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	basket := &entities.Basket{
+		ID:   uuid.New(),
+		Name: "Tech",
+		Composition: []entities.BasketComponent{
+			{Symbol: "AAPL", Weight: decimal.RequireFromString("0.6")},
+			{Symbol: "TSLA", Weight: decimal.RequireFromString("0.4")},
+		},
+	}
+	orders := &mockInvestingOrderRepository{orders: make(map[uuid.UUID]*entities.Order)}
+	balances := &mockBuyingPower{buyingPower: decimal.NewFromInt(1000)}
+	client := &mockBasketOrderClient{failures: map[string][]error{
+		"TSLA": {&alpaca.ValidationError{Message: "asset is not tradable"}},
+	}}
+
+	baskets := &mockInvestingBasketRepository{basket: basket}
+	executor := investing.NewBasketExecutor(client, zap.NewNop())
+	executor.SetBasketRepository(baskets)
+	executor.SetExecutionRepository(&mockBasketExecutionRepository{executions: map[uuid.UUID]*entities.BasketExecution{}})
+	executor.SetFundsReleaser(balances)
+	executor.SetRetryPolicy(1, time.Millisecond)
+
+	svc := investing.NewService(baskets, orders, nil, balances, nil, nil, nil, nil, nil, testLogger())
+	svc.SetBasketExecutor(executor, &mockBrokerageAccounts{})
+	h := handlers.NewInvestingHandlers(svc, testLogger())
+
+	router := gin.New()
+	router.POST("/api/v1/investing/orders", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		h.CreateOrder(c)
+	})
+	postOrder := func() (*httptest.ResponseRecorder, entities.Order) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"basketId":       basket.ID,
+			"side":           "buy",
+			"amount":         "100",
+			"idempotencyKey": "checkout-1",
+		})
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/investing/orders", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var order entities.Order
+		_ = json.Unmarshal(w.Body.Bytes(), &order)
+		return w, order
+	}
+
+	w, order := postOrder()
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, entities.OrderStatusPending, order.Status)
+	require.NotNil(t, order.BrokerageRef)
+
+	// Only AAPL was placed; TSLA's $40 share of the reservation went back to buying power
+	require.Len(t, client.placed, 1)
+	assert.Equal(t, "AAPL", client.placed[0].Symbol)
+	assert.Equal(t, "60", client.placed[0].Notional.String())
+	assert.Equal(t, "940", balances.buyingPower.String())
+
+	// A retried request returns the same order without reserving or ordering again
+	w, replay := postOrder()
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, order.ID, replay.ID)
+	assert.Equal(t, *order.BrokerageRef, *replay.BrokerageRef)
+	assert.Len(t, client.placed, 1)
+	assert.Len(t, orders.orders, 1)
+	assert.Equal(t, "940", balances.buyingPower.String())
+}
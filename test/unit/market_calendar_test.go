@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/market"
+)
+
+// July 2024 around Independence Day, as returned by Alpaca's calendar
+var july2024Calendar = []entities.AlpacaCalendarDay{
+	{Date: "2024-07-01", Open: "09:30", Close: "16:00", SessionOpen: "0400", SessionClose: "2000"},
+	{Date: "2024-07-02", Open: "09:30", Close: "16:00", SessionOpen: "0400", SessionClose: "2000"},
+	{Date: "2024-07-03", Open: "09:30", Close: "13:00", SessionOpen: "0400", SessionClose: "1700"},
+	{Date: "2024-07-05", Open: "09:30", Close: "16:00", SessionOpen: "0400", SessionClose: "2000"},
+	{Date: "2024-07-08", Open: "09:30", Close: "16:00", SessionOpen: "0400", SessionClose: "2000"},
+}
+
+func TestMarketCalendar_SessionsFromFixture(t *testing.T) {
+	calendar := market.NewCalendarService(market.NewFixtureCalendarSource(july2024Calendar), zap.NewNop())
+	et := calendar.Location()
+
+	// Early close on the 3rd: open at noon, after-hours at 2 PM, closed at 6 PM
+	assert.True(t, calendar.IsEarlyClose(time.Date(2024, 7, 3, 12, 0, 0, 0, et)))
+	assert.True(t, calendar.IsOpen(time.Date(2024, 7, 3, 12, 0, 0, 0, et)))
+	assert.False(t, calendar.IsOpen(time.Date(2024, 7, 3, 14, 0, 0, 0, et)))
+	assert.True(t, calendar.IsExtendedHours(time.Date(2024, 7, 3, 14, 0, 0, 0, et)))
+	assert.False(t, calendar.IsExtendedHours(time.Date(2024, 7, 3, 18, 0, 0, 0, et)))
+
+	// After the early close the next open skips the holiday
+	assert.False(t, calendar.IsTradingDay(time.Date(2024, 7, 4, 12, 0, 0, 0, et)))
+	assert.Equal(t, time.Date(2024, 7, 5, 9, 30, 0, 0, et), calendar.NextOpen(time.Date(2024, 7, 3, 14, 0, 0, 0, et)))
+	assert.Equal(t, time.Date(2024, 7, 3, 13, 0, 0, 0, et), calendar.NextClose(time.Date(2024, 7, 3, 10, 0, 0, 0, et)))
+	assert.Equal(t, time.Date(2024, 7, 5, 16, 0, 0, 0, et), calendar.NextClose(time.Date(2024, 7, 3, 13, 0, 0, 0, et)))
+
+	// Trading-day arithmetic skips the holiday and the weekend
+	assert.Equal(t, time.Date(2024, 7, 8, 0, 0, 0, 0, et), calendar.AddTradingDays(time.Date(2024, 7, 3, 10, 0, 0, 0, et), 2))
+	assert.Equal(t, time.Date(2024, 7, 3, 0, 0, 0, 0, et), calendar.PreviousTradingDay(time.Date(2024, 7, 5, 10, 0, 0, 0, et)))
+	assert.Equal(t, 3, calendar.TradingDaysBetween(time.Date(2024, 7, 2, 0, 0, 0, 0, et), time.Date(2024, 7, 8, 0, 0, 0, 0, et)))
+}
+
+func TestMarketCalendar_ExchangeRulesMatchFixture(t *testing.T) {
+	fixture := market.NewFixtureCalendarSource(july2024Calendar)
+	rules := market.NewExchangeCalendar()
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, rules.Location())
+	to := time.Date(2024, 7, 8, 0, 0, 0, 0, rules.Location())
+
+	expected, err := fixture.Sessions(context.Background(), from, to)
+	require.NoError(t, err)
+	actual, err := rules.Sessions(context.Background(), from, to)
+	require.NoError(t, err)
+
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.True(t, expected[i].Open.Equal(actual[i].Open), "open on %s", expected[i].Date)
+		assert.True(t, expected[i].Close.Equal(actual[i].Close), "close on %s", expected[i].Date)
+		assert.True(t, expected[i].ExtendedClose.Equal(actual[i].ExtendedClose), "extended close on %s", expected[i].Date)
+		assert.Equal(t, expected[i].EarlyClose, actual[i].EarlyClose)
+	}
+
+	name, holiday := rules.Holiday(time.Date(2024, 7, 4, 12, 0, 0, 0, rules.Location()))
+	assert.True(t, holiday)
+	assert.Equal(t, "Independence Day", name)
+}
@@ -0,0 +1,509 @@
+package unit
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+	"github.com/rail-service/rail_service/internal/domain/services/rewards"
+)
+
+// mockRewardsRepository implements rewards.Repository for testing
+type mockRewardsRepository struct {
+	offers  map[uuid.UUID]*entities.RewardOffer
+	rewards []*entities.CashbackReward
+}
+
+func newMockRewardsRepository() *mockRewardsRepository {
+	return &mockRewardsRepository{offers: make(map[uuid.UUID]*entities.RewardOffer)}
+}
+
+func (m *mockRewardsRepository) CreateOffer(ctx context.Context, offer *entities.RewardOffer) error {
+	m.offers[offer.ID] = offer
+	return nil
+}
+
+func (m *mockRewardsRepository) UpdateOffer(ctx context.Context, offer *entities.RewardOffer) error {
+	m.offers[offer.ID] = offer
+	return nil
+}
+
+func (m *mockRewardsRepository) GetOffer(ctx context.Context, id uuid.UUID) (*entities.RewardOffer, error) {
+	return m.offers[id], nil
+}
+
+func (m *mockRewardsRepository) ListOffers(ctx context.Context, activeOnly bool) ([]*entities.RewardOffer, error) {
+	var result []*entities.RewardOffer
+	for _, offer := range m.offers {
+		if !activeOnly || offer.Active {
+			result = append(result, offer)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRewardsRepository) CreateReward(ctx context.Context, reward *entities.CashbackReward) error {
+	m.rewards = append(m.rewards, reward)
+	return nil
+}
+
+func (m *mockRewardsRepository) GetRewardByCardTransaction(ctx context.Context, cardTransactionID uuid.UUID) (*entities.CashbackReward, error) {
+	for _, r := range m.rewards {
+		if r.CardTransactionID == cardTransactionID {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRewardsRepository) FindRefundableReward(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.CashbackReward, error) {
+	for i := len(m.rewards) - 1; i >= 0; i-- {
+		r := m.rewards[i]
+		if r.UserID == userID && r.MerchantKey != nil && *r.MerchantKey == merchantKey &&
+			r.PurchaseAmount.GreaterThanOrEqual(amount) && r.Status != entities.CashbackStatusReversed {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRewardsRepository) UpdateRewardStatus(ctx context.Context, id uuid.UUID, status entities.CashbackStatus, ledgerTxID *uuid.UUID) error {
+	for _, r := range m.rewards {
+		if r.ID != id {
+			continue
+		}
+		r.Status = status
+		switch status {
+		case entities.CashbackStatusPaid:
+			r.LedgerTxID = ledgerTxID
+		case entities.CashbackStatusReversed:
+			r.ReversalLedgerTxID = ledgerTxID
+		}
+	}
+	return nil
+}
+
+func (m *mockRewardsRepository) ReduceReward(ctx context.Context, id uuid.UUID, purchaseAmount, amount decimal.Decimal) error {
+	for _, r := range m.rewards {
+		if r.ID == id {
+			r.PurchaseAmount = purchaseAmount
+			r.Amount = amount
+		}
+	}
+	return nil
+}
+
+func (m *mockRewardsRepository) SumOfferRewardsSince(ctx context.Context, userID, offerID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, r := range m.rewards {
+		if r.UserID == userID && r.OfferID == offerID && !r.CreatedAt.Before(since) && r.Status != entities.CashbackStatusReversed {
+			total = total.Add(r.Amount)
+		}
+	}
+	return total, nil
+}
+
+func (m *mockRewardsRepository) GetRewardsReadyForPayout(ctx context.Context, now time.Time, limit int) ([]*entities.CashbackReward, error) {
+	var result []*entities.CashbackReward
+	for _, r := range m.rewards {
+		if (r.Status == entities.CashbackStatusPending && !r.AvailableAt.After(now)) || r.Status == entities.CashbackStatusAvailable {
+			result = append(result, r)
+		}
+	}
+	// Unattempted rewards first, then held rewards from the longest held, each by available_at
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].PayoutAttemptedAt, result[j].PayoutAttemptedAt
+		switch {
+		case a == nil && b != nil:
+			return true
+		case a != nil && b == nil:
+			return false
+		case a != nil && !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return result[i].AvailableAt.Before(result[j].AvailableAt)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *mockRewardsRepository) MarkPayoutAttempted(ctx context.Context, id uuid.UUID, attemptedAt time.Time) error {
+	for _, r := range m.rewards {
+		if r.ID == id {
+			r.PayoutAttemptedAt = &attemptedAt
+		}
+	}
+	return nil
+}
+
+func (m *mockRewardsRepository) GetUserRewards(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CashbackReward, error) {
+	return m.rewards, nil
+}
+
+func (m *mockRewardsRepository) GetUserSummary(ctx context.Context, userID uuid.UUID) (*entities.CashbackSummary, error) {
+	return &entities.CashbackSummary{}, nil
+}
+
+// mockContributionRecorder records contributions for testing
+type mockContributionRecorder struct {
+	contributions []entities.UserContribution
+}
+
+func (m *mockContributionRecorder) RecordContribution(ctx context.Context, userID uuid.UUID, contributionType entities.ContributionType, amount decimal.Decimal, source string) error {
+	m.contributions = append(m.contributions, entities.UserContribution{UserID: userID, Type: contributionType, Amount: amount, Source: source})
+	return nil
+}
+
+func TestRewardsService_CashbackAccruesClearsPaysAndReversesOnRefund(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockRewardsRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(100)
+	contributions := &mockContributionRecorder{}
+
+	svc := rewards.NewService(repo, ledger, contributions, zapLog)
+	svc.SetClearingPeriod(time.Hour)
+
+	// The pool is topped up from the treasury before any cashback can be paid
+	_, err := svc.FundPool(ctx, decimal.NewFromInt(100), "treasury-transfer-1")
+	require.NoError(t, err)
+	assert.True(t, ledger.account(entities.AccountTypeSystemBufferFiat).Balance.IsZero())
+
+	merchant := " starbucks "
+	perTxCap := decimal.NewFromInt(2)
+	monthlyCap := decimal.NewFromInt(3)
+	merchantOffer, err := svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{
+		Name:              "Coffee Club",
+		Merchant:          &merchant,
+		Rate:              decimal.NewFromFloat(0.05),
+		PerTransactionCap: &perTxCap,
+		MonthlyCap:        &monthlyCap,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "STARBUCKS", *merchantOffer.MerchantKey)
+
+	food := entities.SpendingCategoryFoodAndDrink
+	_, err = svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{
+		Name:     "Dining",
+		Category: &food,
+		Rate:     decimal.NewFromFloat(0.01),
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{Name: "Too generous", Category: &food, Rate: decimal.NewFromFloat(0.5)})
+	assert.ErrorIs(t, err, rewards.ErrInvalidOffer)
+
+	userID := uuid.New()
+	merchantKey := "STARBUCKS"
+	purchase := func(amount int64) *entities.BridgeCardTransaction {
+		return &entities.BridgeCardTransaction{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Type:        "purchase",
+			Amount:      decimal.NewFromInt(amount),
+			MerchantKey: &merchantKey,
+			Category:    &food,
+			Status:      string(entities.CardTxStatusCompleted),
+		}
+	}
+
+	// 5% of $60 is $3, capped at $2 per purchase, which beats 1% dining cashback
+	first := purchase(60)
+	reward, err := svc.AccrueCardSettlement(ctx, first)
+	require.NoError(t, err)
+	require.NotNil(t, reward)
+	assert.Equal(t, merchantOffer.ID, reward.OfferID)
+	assert.Equal(t, "2", reward.Amount.String())
+	assert.Equal(t, entities.CashbackStatusPending, reward.Status)
+
+	again, err := svc.AccrueCardSettlement(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, reward.ID, again.ID, "a purchase earns cashback once")
+
+	// Only $1 of the $3 monthly cap remains, so the dining offer's $0.50 loses to it
+	second, err := svc.AccrueCardSettlement(ctx, purchase(50))
+	require.NoError(t, err)
+	assert.Equal(t, "1", second.Amount.String())
+
+	// Nothing is paid until the clearing period has passed
+	paid, err := svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, paid)
+
+	reward.AvailableAt = time.Now().Add(-time.Minute)
+	paid, err = svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, paid)
+	assert.Equal(t, entities.CashbackStatusPaid, reward.Status)
+	assert.Equal(t, "2", ledger.account(entities.AccountTypeStashBalance).Balance.String())
+	assert.Equal(t, "98", ledger.account(entities.AccountTypeSystemRewardsFunding).Balance.String())
+	require.Len(t, contributions.contributions, 1)
+	assert.Equal(t, entities.ContributionTypeCashback, contributions.contributions[0].Type)
+	assert.Equal(t, entities.TransactionTypeCashback, ledger.transactions[1].TransactionType)
+
+	// A refund for the paid purchase reclaims the cashback from the stash
+	refund := purchase(60)
+	refund.Type = "refund"
+	require.NoError(t, svc.ReverseCardSettlement(ctx, refund))
+	assert.Equal(t, entities.CashbackStatusReversed, reward.Status)
+	assert.NotNil(t, reward.ReversalLedgerTxID)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+	assert.Equal(t, "100", ledger.account(entities.AccountTypeSystemRewardsFunding).Balance.String())
+
+	// Reversing a pending reward needs no ledger movement
+	txCount := len(ledger.transactions)
+	reversed := purchase(50)
+	reversed.ID = second.CardTransactionID
+	reversed.Status = string(entities.CardTxStatusReversed)
+	require.NoError(t, svc.ReverseCardSettlement(ctx, reversed))
+	assert.Equal(t, entities.CashbackStatusReversed, second.Status)
+	assert.Len(t, ledger.transactions, txCount)
+}
+
+func TestRewardsService_PartialRefundReversesShareOfCashback(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockRewardsRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(100)
+
+	svc := rewards.NewService(repo, ledger, nil, zapLog)
+	svc.SetClearingPeriod(0)
+	_, err := svc.FundPool(ctx, decimal.NewFromInt(100), "treasury-transfer-1")
+	require.NoError(t, err)
+
+	merchant := "Grocer"
+	_, err = svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{Name: "Groceries", Merchant: &merchant, Rate: decimal.NewFromFloat(0.05)})
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	merchantKey := "GROCER"
+	cardTx := func(txType string, amount float64) *entities.BridgeCardTransaction {
+		return &entities.BridgeCardTransaction{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Type:        txType,
+			Amount:      decimal.NewFromFloat(amount),
+			MerchantKey: &merchantKey,
+			Status:      string(entities.CardTxStatusCompleted),
+		}
+	}
+
+	// 5% of $80 is $4, paid out straight away
+	reward, err := svc.AccrueCardSettlement(ctx, cardTx("capture", 80))
+	require.NoError(t, err)
+	paid, err := svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, paid)
+	assert.Equal(t, "4", ledger.account(entities.AccountTypeStashBalance).Balance.String())
+
+	// Refunding $20 of the $80 reclaims a quarter of the cashback
+	require.NoError(t, svc.ReverseCardSettlement(ctx, cardTx("refund", -20)))
+	assert.Equal(t, entities.CashbackStatusPaid, reward.Status)
+	assert.Equal(t, "3", reward.Amount.String())
+	assert.Equal(t, "60", reward.PurchaseAmount.String())
+	assert.Equal(t, "3", ledger.account(entities.AccountTypeStashBalance).Balance.String())
+
+	// A refund a cent short of the rest is still matched, and reverses the reward once no cashback is left
+	require.NoError(t, svc.ReverseCardSettlement(ctx, cardTx("refund", -59.99)))
+	assert.Equal(t, entities.CashbackStatusReversed, reward.Status)
+	assert.Equal(t, "3", reward.Amount.String())
+	assert.NotNil(t, reward.ReversalLedgerTxID)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+
+	// A refund larger than any rewarded purchase matches nothing
+	txCount := len(ledger.transactions)
+	require.NoError(t, svc.ReverseCardSettlement(ctx, cardTx("refund", -100)))
+	assert.Len(t, ledger.transactions, txCount)
+}
+
+func TestRewardsService_CardRefundReversesCashbackThroughRecordTransaction(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(100)
+	ledger.account(entities.AccountTypeSpendingBalance).Balance = decimal.NewFromInt(100)
+
+	repo := newMockRewardsRepository()
+	svc := rewards.NewService(repo, ledger, nil, zapLog)
+	svc.SetClearingPeriod(0)
+	_, err := svc.FundPool(ctx, decimal.NewFromInt(50), "treasury-transfer-1")
+	require.NoError(t, err)
+	merchant := "Grocer"
+	_, err = svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{Name: "Groceries", Merchant: &merchant, Rate: decimal.NewFromFloat(0.05)})
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	cardRepo := newMockCardRepository()
+	bridgeCardID := "bridge-card-cashback-refund"
+	cardRepo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	cardSvc := card.NewService(cardRepo, nil, nil, nil, &mockCardBalanceProvider{balance: decimal.NewFromInt(100)}, zapLog)
+	cardSvc.SetLedgerService(ledger)
+	cardSvc.SetRewardsProcessor(svc)
+
+	// 5% of $80 is $4, paid into the stash once cleared
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-groceries", "capture",
+		decimal.NewFromInt(80), merchant, "5411", "completed", nil))
+	require.Len(t, repo.rewards, 1)
+	reward := repo.rewards[0]
+	paid, err := svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, paid)
+	assert.Equal(t, "4", ledger.account(entities.AccountTypeStashBalance).Balance.String())
+
+	// Bridge posts the partial refund as a completed transaction with a negative amount
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-groceries-refund", "capture",
+		decimal.NewFromInt(-20), merchant, "5411", "completed", nil))
+	refund, _ := cardRepo.GetTransactionByBridgeID(ctx, "tx-groceries-refund")
+	require.NotNil(t, refund)
+	assert.Equal(t, "refund", refund.Type)
+	assert.Len(t, repo.rewards, 1, "a refund never earns cashback")
+	assert.Equal(t, "3", reward.Amount.String())
+	assert.Equal(t, "3", ledger.account(entities.AccountTypeStashBalance).Balance.String())
+	assert.Equal(t, "120", ledger.account(entities.AccountTypeSpendingBalance).Balance.String(), "the refund is credited to the spending balance")
+
+	// Refunding the rest reverses the remaining cashback
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-groceries-refund-2", "refund",
+		decimal.NewFromInt(-60), merchant, "5411", "completed", nil))
+	assert.Equal(t, entities.CashbackStatusReversed, reward.Status)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+}
+
+// mockFundingAlerter records low rewards funding alerts for testing
+type mockFundingAlerter struct {
+	shortfalls []decimal.Decimal
+}
+
+func (m *mockFundingAlerter) AlertLowRewardsFunding(ctx context.Context, balance, threshold, shortfall decimal.Decimal) error {
+	m.shortfalls = append(m.shortfalls, shortfall)
+	return nil
+}
+
+func TestRewardsService_UnfundedPoolHoldsPayoutsAndAlerts(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockRewardsRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(50)
+	alerter := &mockFundingAlerter{}
+
+	svc := rewards.NewService(repo, ledger, nil, zapLog)
+	svc.SetClearingPeriod(0)
+	svc.SetFundingAlerter(alerter, decimal.NewFromInt(10))
+
+	food := entities.SpendingCategoryFoodAndDrink
+	_, err := svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{
+		Name:     "Dining",
+		Category: &food,
+		Rate:     decimal.NewFromFloat(0.05),
+	})
+	require.NoError(t, err)
+
+	reward, err := svc.AccrueCardSettlement(ctx, &entities.BridgeCardTransaction{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		Type:     "purchase",
+		Amount:   decimal.NewFromInt(40),
+		Category: &food,
+		Status:   string(entities.CardTxStatusCompleted),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, reward)
+	reward.AvailableAt = time.Now().Add(-time.Minute)
+
+	// The pool starts empty, so the cleared reward is held and the shortfall is alerted
+	paid, err := svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, paid)
+	assert.Equal(t, entities.CashbackStatusAvailable, reward.Status)
+	assert.Empty(t, ledger.transactions)
+	assert.True(t, ledger.account(entities.AccountTypeSystemRewardsFunding).Balance.IsZero())
+	require.Len(t, alerter.shortfalls, 1)
+	assert.Equal(t, "2", alerter.shortfalls[0].String())
+
+	_, err = svc.FundPool(ctx, decimal.Zero, "treasury-transfer-0")
+	assert.ErrorIs(t, err, rewards.ErrInvalidFundingAmount)
+
+	// Once funded, the held reward pays out on the next run
+	_, err = svc.FundPool(ctx, decimal.NewFromInt(50), "treasury-transfer-1")
+	require.NoError(t, err)
+	paid, err = svc.ReleaseClearedRewards(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, paid)
+	assert.Equal(t, entities.CashbackStatusPaid, reward.Status)
+	assert.Equal(t, "48", ledger.account(entities.AccountTypeSystemRewardsFunding).Balance.String())
+	assert.Len(t, alerter.shortfalls, 1)
+}
+
+func TestRewardsService_HeldRewardsDoNotStallSmallerPayouts(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	repo := newMockRewardsRepository()
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSystemBufferFiat).Balance = decimal.NewFromInt(5)
+
+	svc := rewards.NewService(repo, ledger, nil, zapLog)
+	svc.SetClearingPeriod(0)
+	_, err := svc.FundPool(ctx, decimal.NewFromInt(5), "treasury-transfer-1")
+	require.NoError(t, err)
+
+	food := entities.SpendingCategoryFoodAndDrink
+	_, err = svc.CreateOffer(ctx, &entities.CreateRewardOfferRequest{Name: "Dining", Category: &food, Rate: decimal.NewFromFloat(0.1)})
+	require.NoError(t, err)
+
+	accrue := func(amount int64, clearedAgo time.Duration) *entities.CashbackReward {
+		reward, err := svc.AccrueCardSettlement(ctx, &entities.BridgeCardTransaction{
+			ID:       uuid.New(),
+			UserID:   uuid.New(),
+			Type:     "capture",
+			Amount:   decimal.NewFromInt(amount),
+			Category: &food,
+			Status:   string(entities.CardTxStatusCompleted),
+		})
+		require.NoError(t, err)
+		reward.AvailableAt = time.Now().Add(-clearedAgo)
+		return reward
+	}
+
+	// The oldest reward is larger than the whole pool
+	large := accrue(100, 3*time.Hour)
+	small := accrue(20, 2*time.Hour)
+	smaller := accrue(10, time.Hour)
+
+	paid, err := svc.ReleaseClearedRewards(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, paid)
+	assert.Equal(t, entities.CashbackStatusAvailable, large.Status)
+	assert.NotNil(t, large.PayoutAttemptedAt)
+
+	// Later batches reach the rewards the pool can cover instead of re-reading the held one
+	paid, err = svc.ReleaseClearedRewards(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, paid)
+	assert.Equal(t, entities.CashbackStatusPaid, small.Status)
+
+	paid, err = svc.ReleaseClearedRewards(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, paid)
+	assert.Equal(t, entities.CashbackStatusPaid, smaller.Status)
+	assert.Equal(t, "2", ledger.account(entities.AccountTypeSystemRewardsFunding).Balance.String())
+	assert.Equal(t, entities.CashbackStatusAvailable, large.Status)
+}
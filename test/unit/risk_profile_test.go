@@ -0,0 +1,230 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/riskprofile"
+)
+
+// mockRiskProfileRepository stores questionnaires, assessments and acknowledgments in memory
+type mockRiskProfileRepository struct {
+	questionnaires []*entities.RiskQuestionnaire
+	assessments    []*entities.RiskAssessment
+	acks           []*entities.RiskAcknowledgment
+}
+
+func (m *mockRiskProfileRepository) CreateQuestionnaire(ctx context.Context, questionnaire *entities.RiskQuestionnaire) error {
+	for _, q := range m.questionnaires {
+		q.Status = entities.RiskQuestionnaireStatusRetired
+	}
+	m.questionnaires = append([]*entities.RiskQuestionnaire{questionnaire}, m.questionnaires...)
+	return nil
+}
+
+func (m *mockRiskProfileRepository) GetActiveQuestionnaire(ctx context.Context) (*entities.RiskQuestionnaire, error) {
+	for _, q := range m.questionnaires {
+		if q.Status == entities.RiskQuestionnaireStatusActive {
+			return q, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRiskProfileRepository) GetQuestionnaires(ctx context.Context) ([]*entities.RiskQuestionnaire, error) {
+	return m.questionnaires, nil
+}
+
+func (m *mockRiskProfileRepository) CreateAssessment(ctx context.Context, assessment *entities.RiskAssessment) error {
+	m.assessments = append(m.assessments, assessment)
+	return nil
+}
+
+func (m *mockRiskProfileRepository) GetLatestAssessment(ctx context.Context, userID uuid.UUID) (*entities.RiskAssessment, error) {
+	for i := len(m.assessments) - 1; i >= 0; i-- {
+		if m.assessments[i].UserID == userID {
+			return m.assessments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRiskProfileRepository) GetAssessmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.RiskAssessment, error) {
+	return m.assessments, nil
+}
+
+func (m *mockRiskProfileRepository) GetAssessmentsDueForReassessment(ctx context.Context, at time.Time, limit int) ([]*entities.RiskAssessment, error) {
+	var due []*entities.RiskAssessment
+	for _, a := range m.assessments {
+		if !a.ReassessAt.After(at) && a.PromptedAt == nil {
+			due = append(due, a)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockRiskProfileRepository) MarkReassessmentPrompted(ctx context.Context, assessmentID uuid.UUID) error {
+	now := time.Now()
+	for _, a := range m.assessments {
+		if a.ID == assessmentID {
+			a.PromptedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockRiskProfileRepository) CreateAcknowledgment(ctx context.Context, ack *entities.RiskAcknowledgment) error {
+	m.acks = append(m.acks, ack)
+	return nil
+}
+
+func (m *mockRiskProfileRepository) GetAcknowledgments(ctx context.Context, userID, assessmentID uuid.UUID, product entities.RiskProduct, resourceID *uuid.UUID) ([]*entities.RiskAcknowledgment, error) {
+	var acks []*entities.RiskAcknowledgment
+	for _, ack := range m.acks {
+		sameResource := (ack.ResourceID == nil && resourceID == nil) ||
+			(ack.ResourceID != nil && resourceID != nil && *ack.ResourceID == *resourceID)
+		if ack.UserID == userID && ack.AssessmentID == assessmentID && ack.Product == product && sameResource {
+			acks = append(acks, ack)
+		}
+	}
+	return acks, nil
+}
+
+// mockAuditLogger captures audit log entries
+type mockAuditLogger struct {
+	actions []entities.AuditAction
+}
+
+func (m *mockAuditLogger) Log(ctx context.Context, userID uuid.UUID, action entities.AuditAction, resource string, resourceID *uuid.UUID, metadata map[string]interface{}) error {
+	m.actions = append(m.actions, action)
+	return nil
+}
+
+func testRiskQuestionnaireRequest() *entities.CreateRiskQuestionnaireRequest {
+	short, long := 2, 25
+	return &entities.CreateRiskQuestionnaireRequest{
+		Questions: []entities.RiskQuestion{
+			{Key: "horizon", Text: "When will you need the money?", Options: []entities.RiskQuestionOption{
+				{Value: "soon", Label: "Soon", Score: 0, HorizonYears: &short},
+				{Value: "later", Label: "Much later", Score: 4, HorizonYears: &long},
+			}},
+			{Key: "drawdown_reaction", Text: "Your investments fall 20%. What do you do?", Options: []entities.RiskQuestionOption{
+				{Value: "sell", Label: "Sell", Score: 0},
+				{Value: "hold", Label: "Hold", Score: 2},
+				{Value: "buy", Label: "Buy more", Score: 4},
+			}},
+		},
+		Bands: []entities.RiskBandThreshold{
+			{Band: entities.RiskBandGrowth, MinScore: 6},
+			{Band: entities.RiskBandConservative, MinScore: 0},
+			{Band: entities.RiskBandBalanced, MinScore: 3},
+		},
+	}
+}
+
+func TestRiskProfileService_ScoresAnswersIntoBandsAndFlagsReassessment(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockRiskProfileRepository{}
+	audit := &mockAuditLogger{}
+	service := riskprofile.NewService(repo, zap.NewNop())
+	service.SetAuditLogger(audit)
+	adminID, userID := uuid.New(), uuid.New()
+
+	_, err := service.PublishQuestionnaire(ctx, adminID, testRiskQuestionnaireRequest())
+	require.NoError(t, err)
+
+	_, err = service.SubmitAssessment(ctx, userID, &entities.SubmitRiskAssessmentRequest{Answers: map[string]string{"horizon": "later"}})
+	assert.EqualError(t, err, "missing answer for question drawdown_reaction")
+	_, err = service.SubmitAssessment(ctx, userID, &entities.SubmitRiskAssessmentRequest{Answers: map[string]string{"horizon": "later", "drawdown_reaction": "panic"}})
+	assert.EqualError(t, err, `invalid answer "panic" for question drawdown_reaction`)
+
+	assessment, err := service.SubmitAssessment(ctx, userID, &entities.SubmitRiskAssessmentRequest{
+		Answers: map[string]string{"horizon": "later", "drawdown_reaction": "hold"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6, assessment.Score)
+	assert.Equal(t, entities.RiskBandGrowth, assessment.RiskBand)
+	assert.Equal(t, 25, *assessment.TimeHorizonYears)
+	assert.Equal(t, 1, assessment.QuestionnaireVersion)
+	assert.Equal(t, []entities.AuditAction{entities.AuditActionRiskAssessment}, audit.actions)
+
+	// The strategy engine sees the band and horizon
+	risk, err := service.GetRiskProfile(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "growth", risk.RiskBand)
+	assert.Equal(t, 25, *risk.TimeHorizonYears)
+
+	profile, err := service.GetProfile(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, profile.ReassessmentDue)
+
+	// A new questionnaire version asks existing users to reassess
+	_, err = service.PublishQuestionnaire(ctx, adminID, testRiskQuestionnaireRequest())
+	require.NoError(t, err)
+	profile, err = service.GetProfile(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, profile.ReassessmentDue)
+	assert.Equal(t, "questionnaire_updated", profile.ReassessmentReason)
+
+	// Expired assessments are prompted once
+	notifier := &mockDrafterNotifier{}
+	service.SetNotifier(notifier)
+	assessment.ReassessAt = time.Now().Add(-time.Hour)
+	prompted, err := service.SendReassessmentPrompts(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, prompted)
+	prompted, err = service.SendReassessmentPrompts(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, prompted)
+}
+
+func TestRiskProfileService_RequiresAcknowledgmentAboveRiskBand(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockRiskProfileRepository{}
+	audit := &mockAuditLogger{}
+	service := riskprofile.NewService(repo, zap.NewNop())
+	service.SetAuditLogger(audit)
+	userID, basketID := uuid.New(), uuid.New()
+
+	// Users without an assessment are not restricted
+	require.NoError(t, service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basketID, entities.RiskBandGrowth, false))
+
+	_, err := service.PublishQuestionnaire(ctx, uuid.New(), testRiskQuestionnaireRequest())
+	require.NoError(t, err)
+	_, err = service.SubmitAssessment(ctx, userID, &entities.SubmitRiskAssessmentRequest{
+		Answers: map[string]string{"horizon": "soon", "drawdown_reaction": "buy"},
+	})
+	require.NoError(t, err)
+	audit.actions = nil
+
+	// Within the user's balanced band
+	require.NoError(t, service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basketID, entities.RiskBandConservative, false))
+
+	// Above it, an acknowledgment is needed and then recorded in the audit log
+	err = service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basketID, entities.RiskBandGrowth, false)
+	assert.ErrorIs(t, err, entities.ErrRiskAcknowledgmentRequired)
+	require.NoError(t, service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basketID, entities.RiskBandGrowth, true))
+	require.Len(t, repo.acks, 1)
+	assert.Equal(t, entities.RiskBandBalanced, repo.acks[0].UserRiskBand)
+	assert.Equal(t, []entities.AuditAction{entities.AuditActionRiskAcknowledge}, audit.actions)
+
+	// The acknowledgment covers later buys of the same basket, but not other baskets
+	require.NoError(t, service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &basketID, entities.RiskBandGrowth, false))
+	otherBasket := uuid.New()
+	err = service.CheckSuitability(ctx, userID, entities.RiskProductBasket, &otherBasket, entities.RiskBandGrowth, false)
+	assert.ErrorIs(t, err, entities.ErrRiskAcknowledgmentRequired)
+
+	// A standing acknowledgment lets auto-investment use strategies up to the acknowledged band
+	_, err = service.AcknowledgeRisk(ctx, userID, &entities.AcknowledgeRiskRequest{Product: entities.RiskProductAutoInvest, RiskBand: entities.RiskBandGrowth})
+	require.NoError(t, err)
+	require.NoError(t, service.CheckSuitability(ctx, userID, entities.RiskProductAutoInvest, nil, entities.RiskBandGrowth, false))
+	err = service.CheckSuitability(ctx, userID, entities.RiskProductAutoInvest, nil, entities.RiskBandAggressive, false)
+	assert.ErrorIs(t, err, entities.ErrRiskAcknowledgmentRequired)
+}
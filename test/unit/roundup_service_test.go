@@ -0,0 +1,384 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/card"
+	"github.com/rail-service/rail_service/internal/domain/services/roundup"
+)
+
+// mockRoundupRepository implements roundup.Repository for testing
+type mockRoundupRepository struct {
+	settings     map[uuid.UUID]*entities.RoundupSettings
+	transactions []*entities.RoundupTransaction
+	accumulators map[uuid.UUID]*entities.RoundupAccumulator
+	cardRepo     *mockCardRepository // Card purchases matched by FindRefundableRoundup
+	readyUsers   []uuid.UUID
+}
+
+func newMockRoundupRepository() *mockRoundupRepository {
+	return &mockRoundupRepository{
+		settings:     make(map[uuid.UUID]*entities.RoundupSettings),
+		accumulators: make(map[uuid.UUID]*entities.RoundupAccumulator),
+	}
+}
+
+func (m *mockRoundupRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.RoundupSettings, error) {
+	return m.settings[userID], nil
+}
+
+func (m *mockRoundupRepository) UpsertSettings(ctx context.Context, settings *entities.RoundupSettings) error {
+	m.settings[settings.UserID] = settings
+	return nil
+}
+
+func (m *mockRoundupRepository) CreateTransaction(ctx context.Context, tx *entities.RoundupTransaction) error {
+	m.transactions = append(m.transactions, tx)
+	return nil
+}
+
+func (m *mockRoundupRepository) UpdateTransactionStatus(ctx context.Context, id uuid.UUID, status entities.RoundupStatus, orderID *uuid.UUID) error {
+	for _, tx := range m.transactions {
+		if tx.ID == id {
+			tx.Status = status
+		}
+	}
+	return nil
+}
+
+func (m *mockRoundupRepository) GetPendingTransactions(ctx context.Context, userID uuid.UUID) ([]*entities.RoundupTransaction, error) {
+	var result []*entities.RoundupTransaction
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && tx.Status == entities.RoundupStatusPending {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRoundupRepository) GetTransactionBySourceRef(ctx context.Context, userID uuid.UUID, sourceType entities.RoundupSourceType, sourceRef string) (*entities.RoundupTransaction, error) {
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && tx.SourceType == sourceType && tx.SourceRef != nil && *tx.SourceRef == sourceRef {
+			return tx, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRoundupRepository) FindRefundableRoundup(ctx context.Context, userID uuid.UUID, merchantKey string, amount decimal.Decimal) (*entities.RoundupTransaction, error) {
+	if m.cardRepo == nil {
+		return nil, nil
+	}
+	for i := len(m.transactions) - 1; i >= 0; i-- {
+		tx := m.transactions[i]
+		if tx.UserID != userID || tx.SourceType != entities.RoundupSourceCard || tx.SourceRef == nil ||
+			tx.Status == entities.RoundupStatusReversed || tx.OriginalAmount.LessThan(amount) {
+			continue
+		}
+		for _, cardTx := range m.cardRepo.transactions {
+			if cardTx.ID.String() == *tx.SourceRef && cardTx.MerchantKey != nil && *cardTx.MerchantKey == merchantKey {
+				return tx, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRoundupRepository) ReduceTransaction(ctx context.Context, id uuid.UUID, originalAmount, multipliedAmount decimal.Decimal) error {
+	for _, tx := range m.transactions {
+		if tx.ID == id {
+			tx.OriginalAmount = originalAmount
+			tx.MultipliedAmount = multipliedAmount
+		}
+	}
+	return nil
+}
+
+func (m *mockRoundupRepository) GetTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.RoundupTransaction, error) {
+	return m.transactions, nil
+}
+
+func (m *mockRoundupRepository) CountTransactions(ctx context.Context, userID uuid.UUID) (int, error) {
+	return len(m.transactions), nil
+}
+
+func (m *mockRoundupRepository) SumRoundupsSince(ctx context.Context, userID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && !tx.CreatedAt.Before(since) &&
+			(tx.Status == entities.RoundupStatusPending || tx.Status == entities.RoundupStatusCollected || tx.Status == entities.RoundupStatusInvested) {
+			total = total.Add(tx.MultipliedAmount)
+		}
+	}
+	return total, nil
+}
+
+func (m *mockRoundupRepository) GetAccumulator(ctx context.Context, userID uuid.UUID) (*entities.RoundupAccumulator, error) {
+	if acc, ok := m.accumulators[userID]; ok {
+		return acc, nil
+	}
+	return &entities.RoundupAccumulator{UserID: userID}, nil
+}
+
+func (m *mockRoundupRepository) UpsertAccumulator(ctx context.Context, acc *entities.RoundupAccumulator) error {
+	m.accumulators[acc.UserID] = acc
+	return nil
+}
+
+func (m *mockRoundupRepository) GetUsersReadyForAutoInvest(ctx context.Context) ([]uuid.UUID, error) {
+	return m.readyUsers, nil
+}
+
+// mockCashFlowInvestor invests up to a fixed amount of each cash flow, failing once if err is set
+type mockCashFlowInvestor struct {
+	investable decimal.Decimal
+	err        error
+	offered    []decimal.Decimal
+	sources    []entities.CashFlowSource
+	active     bool
+}
+
+func (m *mockCashFlowInvestor) HasActiveConfig(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return m.active, nil
+}
+
+func (m *mockCashFlowInvestor) InvestCashFlow(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, source entities.CashFlowSource) ([]*entities.InvestmentOrder, decimal.Decimal, error) {
+	m.offered = append(m.offered, amount)
+	m.sources = append(m.sources, source)
+	if m.err != nil {
+		err := m.err
+		m.err = nil
+		return nil, decimal.Zero, err
+	}
+	invested := decimal.Min(amount, m.investable)
+	return []*entities.InvestmentOrder{{ID: uuid.New(), UserID: userID, Symbol: "VTI", Notional: &invested}}, invested, nil
+}
+
+func TestRoundupService_CardSettlementSweepsSpareChangeAndReversesOnRefund(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	roundupRepo := newMockRoundupRepository()
+	settings := entities.DefaultRoundupSettings(userID)
+	settings.Enabled = true
+	roundupRepo.settings[userID] = settings
+
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSpendingBalance).Balance = decimal.NewFromInt(100)
+
+	roundupSvc := roundup.NewService(roundupRepo, nil, nil, nil, zapLog)
+	roundupSvc.SetLedgerService(ledger)
+
+	cardRepo := newMockCardRepository()
+	bridgeCardID := "bridge-card-roundup"
+	cardRepo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	roundupRepo.cardRepo = cardRepo
+	cardSvc := card.NewService(cardRepo, nil, nil, nil, &mockCardBalanceProvider{balance: decimal.NewFromInt(100)}, zapLog)
+	cardSvc.SetLedgerService(ledger)
+	cardSvc.SetRoundupProcessor(roundupSvc)
+
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-coffee", "capture",
+		decimal.NewFromFloat(4.25), "Corner Cafe", "5814", "completed", nil))
+
+	require.Len(t, roundupRepo.transactions, 1)
+	rt := roundupRepo.transactions[0]
+	assert.Equal(t, entities.RoundupStatusCollected, rt.Status)
+	assert.Equal(t, entities.RoundupSourceCard, rt.SourceType)
+	assert.True(t, rt.MultipliedAmount.Equal(decimal.NewFromFloat(0.75)))
+	assert.NotNil(t, rt.LedgerTxID)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.Equal(decimal.NewFromFloat(0.75)))
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.Equal(decimal.NewFromFloat(99.25)))
+
+	// The same settlement is never rounded up twice
+	_, err := roundupSvc.ProcessCardSettlement(ctx, cardRepo.transactions["tx-coffee"])
+	require.NoError(t, err)
+	assert.Len(t, roundupRepo.transactions, 1)
+	assert.Len(t, ledger.transactions, 1)
+
+	// A refund returns the spare change to the spending balance
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-coffee", "capture",
+		decimal.NewFromFloat(4.25), "Corner Cafe", "5814", string(entities.CardTxStatusReversed), nil))
+	assert.Equal(t, entities.RoundupStatusReversed, rt.Status)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+	assert.True(t, roundupRepo.accumulators[userID].TotalCollected.IsZero())
+
+	// A refund arriving as its own transaction reverses the original purchase's round-up
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-lunch", "capture",
+		decimal.NewFromFloat(12.40), "Corner Cafe", "5814", "completed", nil))
+	require.Len(t, roundupRepo.transactions, 2)
+	lunch := roundupRepo.transactions[1]
+	assert.Equal(t, entities.RoundupStatusCollected, lunch.Status)
+
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-lunch-refund", "refund",
+		decimal.NewFromFloat(-12.40), "Corner Cafe", "5814", "completed", nil))
+	assert.Len(t, roundupRepo.transactions, 2)
+	assert.Equal(t, entities.RoundupStatusReversed, lunch.Status)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.Equal(decimal.NewFromFloat(112.40)),
+		"the refund and its round-up are credited back to the spending balance")
+	assert.True(t, roundupRepo.accumulators[userID].TotalCollected.IsZero())
+}
+
+func TestRoundupService_PartialRefundReversesShareOfRoundup(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	roundupRepo := newMockRoundupRepository()
+	settings := entities.DefaultRoundupSettings(userID)
+	settings.Enabled = true
+	roundupRepo.settings[userID] = settings
+
+	ledger := newMockCardLedgerService()
+	ledger.account(entities.AccountTypeSpendingBalance).Balance = decimal.NewFromInt(100)
+	roundupSvc := roundup.NewService(roundupRepo, nil, nil, nil, zapLog)
+	roundupSvc.SetLedgerService(ledger)
+
+	cardRepo := newMockCardRepository()
+	bridgeCardID := "bridge-card-partial-refund"
+	cardRepo.cards[bridgeCardID] = &entities.BridgeCard{
+		ID:           uuid.New(),
+		UserID:       userID,
+		BridgeCardID: bridgeCardID,
+		Status:       entities.CardStatusActive,
+		Type:         entities.CardTypeVirtual,
+	}
+	roundupRepo.cardRepo = cardRepo
+	cardSvc := card.NewService(cardRepo, nil, nil, nil, &mockCardBalanceProvider{balance: decimal.NewFromInt(100)}, zapLog)
+	cardSvc.SetLedgerService(ledger)
+	cardSvc.SetRoundupProcessor(roundupSvc)
+
+	// $12.40 rounds up by $0.60
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-shoes", "capture",
+		decimal.NewFromFloat(12.40), "Shoe Store", "5661", "completed", nil))
+	require.Len(t, roundupRepo.transactions, 1)
+	rt := roundupRepo.transactions[0]
+	assert.True(t, rt.MultipliedAmount.Equal(decimal.NewFromFloat(0.60)))
+
+	// Refunding a quarter of the purchase returns a quarter of the round-up
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-shoes-refund", "refund",
+		decimal.NewFromFloat(-3.10), "Shoe Store", "5661", "completed", nil))
+	assert.Equal(t, entities.RoundupStatusCollected, rt.Status)
+	assert.True(t, rt.OriginalAmount.Equal(decimal.NewFromFloat(9.30)))
+	assert.True(t, rt.MultipliedAmount.Equal(decimal.NewFromFloat(0.45)))
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.Equal(decimal.NewFromFloat(0.45)))
+	assert.True(t, ledger.account(entities.AccountTypeSpendingBalance).Balance.Equal(decimal.NewFromFloat(102.65)),
+		"the $3.10 refund and $0.15 of round-up are credited back")
+	assert.True(t, roundupRepo.accumulators[userID].TotalCollected.Equal(decimal.NewFromFloat(0.45)))
+
+	// Refunding the rest reverses what is left
+	require.NoError(t, cardSvc.RecordTransaction(ctx, bridgeCardID, "tx-shoes-refund-2", "refund",
+		decimal.NewFromFloat(-9.30), "Shoe Store", "5661", "completed", nil))
+	assert.Equal(t, entities.RoundupStatusReversed, rt.Status)
+	assert.True(t, ledger.account(entities.AccountTypeStashBalance).Balance.IsZero())
+	assert.True(t, roundupRepo.accumulators[userID].TotalCollected.IsZero())
+}
+
+func TestRoundupSettings_EvaluateRoundup_BoostsCapsAndSafeguards(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	boostEnds := now.Add(7 * 24 * time.Hour)
+	dec := func(v float64) *decimal.Decimal { d := decimal.NewFromFloat(v); return &d }
+
+	settings := entities.DefaultRoundupSettings(uuid.New())
+	settings.Multiplier = decimal.NewFromInt(2)
+	settings.BoostMultiplier = dec(2)
+	settings.BoostEndsAt = &boostEnds
+	settings.WeeklyCap = dec(5)
+	settings.LowBalanceFloor = dec(50)
+	settings.SkipBelowBalance = dec(10)
+
+	// $3.60 purchase: 0.40 spare change, 2x base with a 2x boost
+	eval := settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(200), Now: now})
+	assert.True(t, eval.BoostApplied)
+	assert.True(t, eval.EffectiveMultiplier.Equal(decimal.NewFromInt(4)))
+	assert.True(t, eval.MultipliedAmount.Equal(decimal.NewFromFloat(1.60)))
+
+	// Boost has ended
+	eval = settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(200), Now: boostEnds})
+	assert.False(t, eval.BoostApplied)
+	assert.True(t, eval.MultipliedAmount.Equal(decimal.NewFromFloat(0.80)))
+
+	// Under the low balance floor multipliers drop to 1x
+	eval = settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(40), Now: now})
+	assert.True(t, eval.ScaledDown)
+	assert.True(t, eval.MultipliedAmount.Equal(decimal.NewFromFloat(0.40)))
+
+	// The weekly cap trims the round-up to what is left
+	eval = settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(200), WeekToDate: decimal.NewFromFloat(4.50), Now: now})
+	assert.Equal(t, "weekly_cap", eval.CappedBy)
+	assert.True(t, eval.MultipliedAmount.Equal(decimal.NewFromFloat(0.50)))
+
+	eval = settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(200), WeekToDate: decimal.NewFromInt(5), Now: now})
+	assert.True(t, eval.Skipped)
+	assert.Equal(t, entities.RoundupSkipCapReached, eval.SkipReason)
+
+	// Round-ups that would leave less than the safeguard are skipped
+	eval = settings.EvaluateRoundup(entities.RoundupRuleInput{Amount: decimal.NewFromFloat(3.60), SpendBalance: dec(10.20), Now: now})
+	assert.True(t, eval.Skipped)
+	assert.Equal(t, entities.RoundupSkipLowBalance, eval.SkipReason)
+	assert.True(t, eval.MultipliedAmount.IsZero())
+}
+
+func TestRoundupService_AutoInvestKeepsUninvestedRoundupsPending(t *testing.T) {
+	zapLog, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	repo := newMockRoundupRepository()
+	repo.readyUsers = []uuid.UUID{userID}
+	settings := entities.DefaultRoundupSettings(userID)
+	settings.Enabled = true
+	settings.AutoInvestEnabled = true
+	settings.Threshold = decimal.NewFromInt(2)
+	repo.settings[userID] = settings
+
+	pending := decimal.Zero
+	for _, amount := range []string{"0.75", "0.60", "0.90"} {
+		tx := &entities.RoundupTransaction{
+			ID:               uuid.New(),
+			UserID:           userID,
+			MultipliedAmount: decimal.RequireFromString(amount),
+			SourceType:       entities.RoundupSourceCard,
+			Status:           entities.RoundupStatusPending,
+		}
+		repo.transactions = append(repo.transactions, tx)
+		pending = pending.Add(tx.MultipliedAmount)
+	}
+	repo.accumulators[userID] = &entities.RoundupAccumulator{UserID: userID, PendingAmount: pending}
+
+	// A failed investment leaves everything pending for the next run
+	investor := &mockCashFlowInvestor{investable: decimal.RequireFromString("1.50"), err: fmt.Errorf("broker unavailable")}
+	svc := roundup.NewService(repo, nil, nil, nil, zapLog)
+	svc.SetCashFlowInvestor(investor)
+	require.NoError(t, svc.ProcessAutoInvestBatch(ctx))
+	acc := repo.accumulators[userID]
+	assert.Equal(t, "2.25", acc.PendingAmount.String())
+	assert.True(t, acc.TotalInvested.IsZero())
+
+	// Whole-share rounding invests $1.50 of $2.25: only round-ups it fully covers are invested
+	require.NoError(t, svc.ProcessAutoInvestBatch(ctx))
+	assert.Equal(t, "1.5", acc.TotalInvested.String())
+	assert.Equal(t, "0.75", acc.PendingAmount.String())
+	assert.Equal(t, entities.RoundupStatusInvested, repo.transactions[0].Status)
+	assert.Equal(t, entities.RoundupStatusInvested, repo.transactions[1].Status)
+	assert.Equal(t, entities.RoundupStatusPending, repo.transactions[2].Status)
+	require.Len(t, investor.offered, 2)
+	assert.Equal(t, "2.25", investor.offered[1].String())
+}
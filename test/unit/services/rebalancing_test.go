@@ -210,3 +210,99 @@ func TestRebalancingService_CheckDrift(t *testing.T) {
 	assert.False(t, needsRebalance) // Already at 100% AAPL
 	assert.True(t, maxDrift.IsZero() || maxDrift.LessThanOrEqual(decimal.NewFromInt(5)))
 }
+
+type mockRebalancingAssetLookup struct {
+	wholeShareSymbols map[string]bool
+}
+
+func (m *mockRebalancingAssetLookup) GetAsset(ctx context.Context, symbol string) (*entities.AlpacaAssetResponse, error) {
+	return &entities.AlpacaAssetResponse{Symbol: symbol, Tradable: true, Fractionable: !m.wholeShareSymbols[symbol]}, nil
+}
+
+func TestRebalancingService_CashFlowFillsUnderweightBeforeSelling(t *testing.T) {
+	userID := uuid.New()
+	configID := uuid.New()
+	configRepo := &mockRebalancingConfigRepo{configs: []*entities.RebalancingConfig{{
+		ID:     configID,
+		UserID: userID,
+		TargetAllocations: map[string]decimal.Decimal{
+			"AAPL":  decimal.NewFromInt(50),
+			"GOOGL": decimal.NewFromInt(50),
+		},
+		ThresholdPct: decimal.NewFromInt(5),
+		Status:       entities.ScheduleStatusActive,
+	}}}
+	positionRepo := &mockRebalancingPositionProvider{
+		positions: []*entities.InvestmentPosition{
+			{UserID: userID, Symbol: "AAPL", MarketValue: decimal.NewFromInt(6000), CostBasis: decimal.NewFromInt(4000)}, // 60%
+			{UserID: userID, Symbol: "GOOGL", MarketValue: decimal.NewFromInt(4000), CostBasis: decimal.NewFromInt(4000)},
+		},
+	}
+	orderPlacer := &mockOrderPlacer{}
+	svc := investing.NewRebalancingService(configRepo, positionRepo, &mockQuoteProvider{}, orderPlacer, zap.NewNop())
+
+	// $2,000 of new cash closes the gap without selling the appreciated AAPL
+	plan, err := svc.GenerateRebalancingPlanWithCashFlow(context.Background(), userID, configID, decimal.NewFromInt(2000))
+	require.NoError(t, err)
+	require.Len(t, plan.Trades, 1)
+	assert.Equal(t, "GOOGL", plan.Trades[0].Symbol)
+	assert.Equal(t, "buy", plan.Trades[0].Side)
+	assert.Equal(t, "2000.00", plan.Trades[0].CashFlowAmount.StringFixed(2))
+	assert.True(t, plan.TotalSellAmount.IsZero())
+	assert.Equal(t, "50.00", plan.ProjectedAllocations["AAPL"].StringFixed(2))
+	assert.Equal(t, "50.00", plan.ProjectedAllocations["GOOGL"].StringFixed(2))
+
+	// Round-ups and dividends are invested the same way, buys only
+	orders, invested, err := svc.InvestCashFlow(context.Background(), userID, decimal.NewFromInt(50), entities.CashFlowSourceRoundup)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "GOOGL", orders[0].Symbol)
+	assert.Equal(t, "50", invested.String())
+}
+
+func TestRebalancingService_TrimsGainsToBandEdgeInWholeShares(t *testing.T) {
+	userID := uuid.New()
+	configID := uuid.New()
+	configRepo := &mockRebalancingConfigRepo{configs: []*entities.RebalancingConfig{{
+		ID:     configID,
+		UserID: userID,
+		TargetAllocations: map[string]decimal.Decimal{
+			"AAPL":  decimal.NewFromInt(50),
+			"GOOGL": decimal.NewFromInt(50),
+		},
+		ThresholdPct: decimal.NewFromInt(5),
+		Status:       entities.ScheduleStatusActive,
+	}}}
+	positionRepo := &mockRebalancingPositionProvider{
+		positions: []*entities.InvestmentPosition{
+			{UserID: userID, Symbol: "AAPL", Qty: decimal.NewFromInt(50), MarketValue: decimal.NewFromInt(7000), CostBasis: decimal.NewFromInt(5000)}, // 70%
+			{UserID: userID, Symbol: "GOOGL", MarketValue: decimal.NewFromInt(3000), CostBasis: decimal.NewFromInt(3000)},
+		},
+	}
+	quoteProvider := &mockQuoteProvider{
+		quotes: map[string]*entities.MarketQuote{
+			"AAPL":  {Symbol: "AAPL", Price: decimal.NewFromInt(140)},
+			"GOOGL": {Symbol: "GOOGL", Price: decimal.NewFromInt(140)},
+		},
+	}
+	svc := investing.NewRebalancingService(configRepo, positionRepo, quoteProvider, &mockOrderPlacer{}, zap.NewNop())
+	svc.SetAssetLookup(&mockRebalancingAssetLookup{wholeShareSymbols: map[string]bool{"GOOGL": true}})
+
+	plan, err := svc.GenerateRebalancingPlan(context.Background(), userID, configID)
+	require.NoError(t, err)
+	require.Len(t, plan.Trades, 2)
+
+	// AAPL sits on a gain, so it is trimmed to 55% rather than its 50% target
+	sell := plan.Trades[0]
+	assert.Equal(t, "sell", sell.Side)
+	assert.Equal(t, "1500.00", sell.Amount.StringFixed(2))
+	assert.Equal(t, "428.57", sell.EstimatedGain.StringFixed(2))
+
+	// GOOGL is not fractionable: 10 whole shares, the remainder stays as cash
+	buy := plan.Trades[1]
+	assert.Equal(t, "buy", buy.Side)
+	assert.True(t, buy.WholeShares)
+	assert.True(t, buy.EstimatedQty.Equal(decimal.NewFromInt(10)))
+	assert.Equal(t, "1400.00", buy.Amount.StringFixed(2))
+	assert.Equal(t, "100.00", plan.UninvestedCash.StringFixed(2))
+}
@@ -91,6 +91,17 @@ func (m *mockScheduledInvestmentRepo) GetExecutions(ctx context.Context, schedul
 	return result, nil
 }
 
+func (m *mockScheduledInvestmentRepo) GetDueForReminder(ctx context.Context, before time.Time) ([]*entities.ScheduledInvestment, error) {
+	var result []*entities.ScheduledInvestment
+	for _, si := range m.investments {
+		reminded := si.ReminderSentFor != nil && si.ReminderSentFor.Equal(si.NextExecutionAt)
+		if si.Status == "active" && si.NextExecutionAt.Before(before) && !reminded {
+			result = append(result, si)
+		}
+	}
+	return result, nil
+}
+
 type mockOrderPlacer struct {
 	orders []*entities.InvestmentOrder
 }
@@ -183,3 +194,134 @@ func TestScheduledInvestmentService_GetUserScheduledInvestments(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, investments, 2)
 }
+
+// mockScheduleCalendar reports every day as a full session unless closed
+type mockScheduleCalendar struct {
+	closed bool
+}
+
+func (m *mockScheduleCalendar) IsTradingDay(t time.Time) bool { return !m.closed }
+
+func (m *mockScheduleCalendar) IsEarlyClose(t time.Time) bool { return false }
+
+func (m *mockScheduleCalendar) NextTradingDay(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+
+// mockFundingLedger holds a single spending balance and records transfers out of it
+type mockFundingLedger struct {
+	balance      decimal.Decimal
+	transactions []*entities.CreateTransactionRequest
+	reversed     []uuid.UUID
+}
+
+func (m *mockFundingLedger) GetAccountBalance(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (decimal.Decimal, error) {
+	return m.balance, nil
+}
+
+func (m *mockFundingLedger) GetOrCreateUserAccount(ctx context.Context, userID uuid.UUID, accountType entities.AccountType) (*entities.LedgerAccount, error) {
+	return &entities.LedgerAccount{ID: uuid.New(), AccountType: accountType}, nil
+}
+
+func (m *mockFundingLedger) CreateTransaction(ctx context.Context, req *entities.CreateTransactionRequest) (*entities.LedgerTransaction, error) {
+	m.transactions = append(m.transactions, req)
+	m.balance = m.balance.Sub(req.Entries[0].Amount)
+	return &entities.LedgerTransaction{ID: uuid.New()}, nil
+}
+
+func (m *mockFundingLedger) ReverseTransaction(ctx context.Context, originalTxID uuid.UUID, reason string) error {
+	m.reversed = append(m.reversed, originalTxID)
+	return nil
+}
+
+type mockScheduleNotifier struct {
+	titles []string
+}
+
+func (m *mockScheduleNotifier) SendGenericNotification(ctx context.Context, userID uuid.UUID, title, message string) error {
+	m.titles = append(m.titles, title)
+	return nil
+}
+
+func newDueSchedule(daysLate int, catchUpPolicy string) *entities.ScheduledInvestment {
+	symbol := "VTI"
+	today := time.Now().UTC()
+	return &entities.ScheduledInvestment{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		Symbol:          &symbol,
+		Amount:          decimal.NewFromInt(100),
+		Frequency:       entities.FrequencyDaily,
+		NextExecutionAt: time.Date(today.Year(), today.Month(), today.Day()-daysLate, 14, 0, 0, 0, time.UTC),
+		Status:          entities.ScheduleStatusActive,
+		CatchUpPolicy:   catchUpPolicy,
+		HolidayPolicy:   entities.HolidayPolicyDefer,
+	}
+}
+
+func TestScheduledInvestmentService_DefersRunOnMarketHoliday(t *testing.T) {
+	si := newDueSchedule(1, entities.CatchUpPolicyRunOnce)
+	repo := &mockScheduledInvestmentRepo{investments: []*entities.ScheduledInvestment{si}}
+	orderPlacer := &mockOrderPlacer{}
+	ledger := &mockFundingLedger{balance: decimal.NewFromInt(500)}
+
+	svc := investing.NewScheduledInvestmentService(repo, orderPlacer, &mockBasketOrderPlacer{}, zap.NewNop())
+	svc.SetFundingLedger(ledger)
+	svc.SetTradingCalendar(&mockScheduleCalendar{closed: true})
+
+	require.NoError(t, svc.ProcessDueInvestments(context.Background()))
+
+	assert.Empty(t, orderPlacer.orders)
+	assert.Empty(t, ledger.transactions)
+	require.Len(t, repo.executions, 1)
+	assert.Equal(t, entities.ScheduledRunDeferred, repo.executions[0].Status)
+	assert.True(t, si.NextExecutionAt.After(time.Now()))
+}
+
+func TestScheduledInvestmentService_InsufficientSpendingBalance(t *testing.T) {
+	si := newDueSchedule(0, entities.CatchUpPolicyRunOnce)
+	si.NextExecutionAt = time.Now().Add(-time.Minute)
+	repo := &mockScheduledInvestmentRepo{investments: []*entities.ScheduledInvestment{si}}
+	orderPlacer := &mockOrderPlacer{}
+	ledger := &mockFundingLedger{balance: decimal.NewFromInt(40)}
+	notifier := &mockScheduleNotifier{}
+
+	svc := investing.NewScheduledInvestmentService(repo, orderPlacer, &mockBasketOrderPlacer{}, zap.NewNop())
+	svc.SetFundingLedger(ledger)
+	svc.SetNotifier(notifier)
+	svc.SetTradingCalendar(&mockScheduleCalendar{})
+
+	require.NoError(t, svc.ProcessDueInvestments(context.Background()))
+
+	assert.Empty(t, orderPlacer.orders)
+	assert.Empty(t, ledger.transactions)
+	require.Len(t, repo.executions, 1)
+	assert.Equal(t, entities.ScheduledRunInsufficientFunds, repo.executions[0].Status)
+	assert.Len(t, notifier.titles, 1)
+	assert.Equal(t, 0, si.ExecutionCount)
+}
+
+func TestScheduledInvestmentService_CatchUpRunsOnceForMissedRuns(t *testing.T) {
+	si := newDueSchedule(3, entities.CatchUpPolicyRunOnce)
+	repo := &mockScheduledInvestmentRepo{investments: []*entities.ScheduledInvestment{si}}
+	orderPlacer := &mockOrderPlacer{}
+	ledger := &mockFundingLedger{balance: decimal.NewFromInt(1000)}
+
+	svc := investing.NewScheduledInvestmentService(repo, orderPlacer, &mockBasketOrderPlacer{}, zap.NewNop())
+	svc.SetFundingLedger(ledger)
+	svc.SetTradingCalendar(&mockScheduleCalendar{})
+
+	require.NoError(t, svc.ProcessDueInvestments(context.Background()))
+
+	// Only the latest missed run is placed and funded
+	require.Len(t, orderPlacer.orders, 1)
+	require.Len(t, ledger.transactions, 1)
+	assert.Equal(t, "900", ledger.balance.String())
+	require.GreaterOrEqual(t, len(repo.executions), 3)
+	last := repo.executions[len(repo.executions)-1]
+	assert.Equal(t, entities.ScheduledRunSuccess, last.Status)
+	assert.NotNil(t, last.LedgerTransactionID)
+	for _, exec := range repo.executions[:len(repo.executions)-1] {
+		assert.Equal(t, entities.ScheduledRunSkipped, exec.Status)
+	}
+	assert.Equal(t, 1, si.ExecutionCount)
+	assert.True(t, si.NextExecutionAt.After(time.Now()))
+}
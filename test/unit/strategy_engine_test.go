@@ -0,0 +1,236 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/strategy"
+)
+
+// mockStrategyRepository stores model portfolios in memory
+type mockStrategyRepository struct {
+	portfolios  map[uuid.UUID]*entities.ModelPortfolio
+	versions    []*entities.ModelPortfolioVersion
+	assignments []*entities.StrategyAssignment
+}
+
+func newMockStrategyRepository() *mockStrategyRepository {
+	return &mockStrategyRepository{portfolios: make(map[uuid.UUID]*entities.ModelPortfolio)}
+}
+
+func (m *mockStrategyRepository) CreateModelPortfolio(ctx context.Context, portfolio *entities.ModelPortfolio, version *entities.ModelPortfolioVersion) error {
+	m.portfolios[portfolio.ID] = portfolio
+	m.versions = append(m.versions, version)
+	return nil
+}
+
+func (m *mockStrategyRepository) GetModelPortfolios(ctx context.Context) ([]*entities.ModelPortfolio, error) {
+	var portfolios []*entities.ModelPortfolio
+	for _, p := range m.portfolios {
+		portfolios = append(portfolios, p)
+	}
+	return portfolios, nil
+}
+
+func (m *mockStrategyRepository) GetModelPortfolioByID(ctx context.Context, id uuid.UUID) (*entities.ModelPortfolio, error) {
+	return m.portfolios[id], nil
+}
+
+func (m *mockStrategyRepository) UpdateModelPortfolioStatus(ctx context.Context, id uuid.UUID, status entities.ModelPortfolioStatus) error {
+	m.portfolios[id].Status = status
+	return nil
+}
+
+func (m *mockStrategyRepository) CreateModelPortfolioVersion(ctx context.Context, version *entities.ModelPortfolioVersion) error {
+	m.versions = append(m.versions, version)
+	return nil
+}
+
+func (m *mockStrategyRepository) GetModelPortfolioVersions(ctx context.Context, portfolioID uuid.UUID) ([]*entities.ModelPortfolioVersion, error) {
+	var versions []*entities.ModelPortfolioVersion
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if m.versions[i].PortfolioID == portfolioID {
+			versions = append(versions, m.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockStrategyRepository) GetEffectiveModelPortfolioVersions(ctx context.Context, at time.Time) ([]*entities.ModelPortfolioVersion, error) {
+	effective := make(map[uuid.UUID]*entities.ModelPortfolioVersion)
+	for _, v := range m.versions {
+		if m.portfolios[v.PortfolioID].Status != entities.ModelPortfolioStatusActive || v.EffectiveFrom.After(at) {
+			continue
+		}
+		if current, ok := effective[v.PortfolioID]; !ok || v.EffectiveFrom.After(current.EffectiveFrom) {
+			effective[v.PortfolioID] = v
+		}
+	}
+	var versions []*entities.ModelPortfolioVersion
+	for _, v := range effective {
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func (m *mockStrategyRepository) CreateStrategyAssignment(ctx context.Context, assignment *entities.StrategyAssignment) error {
+	m.assignments = append(m.assignments, assignment)
+	return nil
+}
+
+func (m *mockStrategyRepository) GetStrategyAssignmentsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.StrategyAssignment, error) {
+	return m.assignments, nil
+}
+
+// mockRiskProfileProvider returns a fixed risk assessment
+type mockRiskProfileProvider struct {
+	profile *strategy.RiskProfile
+}
+
+func (m *mockRiskProfileProvider) GetRiskProfile(ctx context.Context, userID uuid.UUID) (*strategy.RiskProfile, error) {
+	return m.profile, nil
+}
+
+func TestStrategyEngine_SelectsStoredPortfolioVersionAndRecordsAssignment(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockStrategyRepository()
+	dob := time.Now().AddDate(-30, -1, 0)
+	engine := strategy.NewEngine(&mockUserProfileProvider{profile: &entities.UserProfile{ID: uuid.New(), DateOfBirth: &dob}}, testLogger())
+	engine.SetRepository(repo)
+	adminID := uuid.New()
+
+	_, err := engine.CreateModelPortfolio(ctx, adminID, &entities.CreateModelPortfolioRequest{
+		Slug: "core",
+		Name: "Core",
+		Version: entities.CreateModelPortfolioVersionRequest{
+			Allocations: []entities.ModelPortfolioAllocation{{Symbol: "vti", Weight: decimal.NewFromInt(100)}},
+		},
+	})
+	require.NoError(t, err)
+
+	horizon := 20
+	growth, err := engine.CreateModelPortfolio(ctx, adminID, &entities.CreateModelPortfolioRequest{
+		Slug: "long-horizon-growth",
+		Name: "Long Horizon Growth",
+		Version: entities.CreateModelPortfolioVersionRequest{
+			Priority: 10,
+			Criteria: entities.ModelPortfolioCriteria{RiskBands: []string{"aggressive"}, MinHorizonYears: &horizon},
+			Allocations: []entities.ModelPortfolioAllocation{
+				{Symbol: "QQQ", Weight: decimal.NewFromInt(70)},
+				{Symbol: "VUG", Weight: decimal.NewFromInt(30)},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Without a risk assessment only the unconditional portfolio matches
+	result, err := engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Core", result.StrategyName)
+	assert.Equal(t, "VTI", result.Allocations[0].Symbol)
+
+	// An aggressive, long-horizon investor gets the higher-priority growth portfolio
+	engine.SetRiskProfileProvider(&mockRiskProfileProvider{profile: &strategy.RiskProfile{RiskBand: "aggressive", TimeHorizonYears: &horizon}})
+	result, err = engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Long Horizon Growth", result.StrategyName)
+	assert.Equal(t, 1, result.Version)
+
+	// A scheduled version is not used before its effective date
+	future := time.Now().Add(24 * time.Hour)
+	_, err = engine.PublishModelPortfolioVersion(ctx, adminID, growth.ID, &entities.CreateModelPortfolioVersionRequest{
+		EffectiveFrom: &future,
+		Priority:      10,
+		Criteria:      growth.CurrentVersion.Criteria,
+		Allocations:   []entities.ModelPortfolioAllocation{{Symbol: "QQQ", Weight: decimal.NewFromInt(100)}},
+	})
+	require.NoError(t, err)
+	result, err = engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Version)
+
+	userID := uuid.New()
+	require.NoError(t, engine.RecordAssignment(ctx, userID, "deposit-1", decimal.NewFromInt(250), result))
+	require.Len(t, repo.assignments, 1)
+	assignment := repo.assignments[0]
+	assert.Equal(t, growth.ID, *assignment.PortfolioID)
+	assert.Equal(t, *result.PortfolioVersionID, *assignment.PortfolioVersionID)
+	assert.Equal(t, "aggressive", assignment.Signals.RiskBand)
+	assert.Equal(t, 30, *assignment.Signals.Age)
+	assert.Len(t, assignment.Allocations, 2)
+}
+
+func TestStrategyEngine_RejectsInvalidModelPortfolio(t *testing.T) {
+	engine := strategy.NewEngine(nil, testLogger())
+	engine.SetRepository(newMockStrategyRepository())
+
+	_, err := engine.CreateModelPortfolio(context.Background(), uuid.New(), &entities.CreateModelPortfolioRequest{
+		Slug: "bad",
+		Name: "Bad",
+		Version: entities.CreateModelPortfolioVersionRequest{
+			Allocations: []entities.ModelPortfolioAllocation{
+				{Symbol: "SPY", Weight: decimal.NewFromInt(60)},
+				{Symbol: "BND", Weight: decimal.NewFromInt(30)},
+			},
+		},
+	})
+	assert.EqualError(t, err, "allocations must sum to 100%, got 90%")
+}
+
+func TestStrategyEngine_FallsBackWithinRiskBand(t *testing.T) {
+	ctx := context.Background()
+	dob := time.Now().AddDate(-30, -1, 0)
+	engine := strategy.NewEngine(&mockUserProfileProvider{profile: &entities.UserProfile{ID: uuid.New(), DateOfBirth: &dob}}, testLogger())
+	engine.SetRiskProfileProvider(&mockRiskProfileProvider{profile: &strategy.RiskProfile{RiskBand: "moderate"}})
+
+	// The balanced global default exceeds a moderate band, so the conservative default seeded
+	// for low bands is used
+	result, err := engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Capital Preservation", result.StrategyName)
+	assert.Equal(t, entities.RiskBandConservative, result.RiskBand)
+
+	// Among stored portfolios the highest-ranked one within the band wins even if its criteria miss
+	repo := newMockStrategyRepository()
+	engine.SetRepository(repo)
+	minAge := 60
+	for _, req := range []*entities.CreateModelPortfolioRequest{
+		{Slug: "growth", Name: "Growth", Version: entities.CreateModelPortfolioVersionRequest{
+			Priority: 20, RiskBand: entities.RiskBandGrowth,
+			Allocations: []entities.ModelPortfolioAllocation{{Symbol: "QQQ", Weight: decimal.NewFromInt(100)}},
+		}},
+		{Slug: "income", Name: "Income", Version: entities.CreateModelPortfolioVersionRequest{
+			Priority: 5, RiskBand: entities.RiskBandModerate, Criteria: entities.ModelPortfolioCriteria{MinAge: &minAge},
+			Allocations: []entities.ModelPortfolioAllocation{{Symbol: "VYM", Weight: decimal.NewFromInt(100)}},
+		}},
+		{Slug: "bonds", Name: "Bonds", Version: entities.CreateModelPortfolioVersionRequest{
+			Priority: 1, RiskBand: entities.RiskBandConservative, Criteria: entities.ModelPortfolioCriteria{MinAge: &minAge},
+			Allocations: []entities.ModelPortfolioAllocation{{Symbol: "BND", Weight: decimal.NewFromInt(100)}},
+		}},
+	} {
+		_, err := engine.CreateModelPortfolio(ctx, uuid.New(), req)
+		require.NoError(t, err)
+	}
+	result, err = engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Income", result.StrategyName)
+
+	// With nothing inside a conservative band the default is returned for the suitability check
+	engine.SetRiskProfileProvider(&mockRiskProfileProvider{profile: &strategy.RiskProfile{RiskBand: "conservative"}})
+	for _, v := range repo.versions {
+		if v.PortfolioName == "Bonds" {
+			require.NoError(t, repo.UpdateModelPortfolioStatus(ctx, v.PortfolioID, entities.ModelPortfolioStatusRetired))
+		}
+	}
+	result, err = engine.GetStrategy(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Global Fallback", result.StrategyName)
+	assert.Equal(t, entities.RiskBandBalanced, result.RiskBand)
+}
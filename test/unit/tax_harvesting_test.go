@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/tax"
+)
+
+func (m *mockTaxLotRepository) GetHarvestingUserIDs(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	for userID, settings := range m.settings {
+		if settings.HarvestingEnabled {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (m *mockTaxLotRepository) CreateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error {
+	m.harvests = append(m.harvests, harvest)
+	return nil
+}
+
+func (m *mockTaxLotRepository) UpdateHarvest(ctx context.Context, harvest *entities.TaxLossHarvest) error {
+	return nil
+}
+
+func (m *mockTaxLotRepository) GetActiveHarvests(ctx context.Context, userID uuid.UUID, at time.Time) ([]*entities.TaxLossHarvest, error) {
+	var harvests []*entities.TaxLossHarvest
+	for _, harvest := range m.harvests {
+		if harvest.UserID == userID && harvest.WashWindowEndsAt.After(at) && harvest.Status != entities.TaxLossHarvestStatusFailed {
+			harvests = append(harvests, harvest)
+		}
+	}
+	return harvests, nil
+}
+
+func (m *mockTaxLotRepository) GetHarvests(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.TaxLossHarvest, error) {
+	var harvests []*entities.TaxLossHarvest
+	for _, harvest := range m.harvests {
+		if harvest.UserID == userID && !harvest.CreatedAt.Before(from) && harvest.CreatedAt.Before(to) {
+			harvests = append(harvests, harvest)
+		}
+	}
+	return harvests, nil
+}
+
+// mockHarvestQuotes returns fixed prices
+type mockHarvestQuotes map[string]decimal.Decimal
+
+func (m mockHarvestQuotes) GetQuotes(ctx context.Context, symbols []string) (map[string]*entities.MarketQuote, error) {
+	quotes := make(map[string]*entities.MarketQuote)
+	for _, symbol := range symbols {
+		if price, ok := m[symbol]; ok {
+			quotes[symbol] = &entities.MarketQuote{Symbol: symbol, Price: price}
+		}
+	}
+	return quotes, nil
+}
+
+// mockHarvestOrderPlacer records the sells and buys a harvest places
+type mockHarvestOrderPlacer struct {
+	orders  []*entities.InvestmentOrder
+	sellErr error
+}
+
+func (m *mockHarvestOrderPlacer) PlaceSellOrder(ctx context.Context, orderID, userID uuid.UUID, symbol string, quantity decimal.Decimal) (*entities.InvestmentOrder, error) {
+	if m.sellErr != nil {
+		return nil, m.sellErr
+	}
+	order := &entities.InvestmentOrder{ID: orderID, UserID: userID, Symbol: symbol, Side: entities.AlpacaOrderSideSell, Qty: &quantity}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func (m *mockHarvestOrderPlacer) PlaceMarketOrder(ctx context.Context, userID uuid.UUID, symbol string, notional decimal.Decimal) (*entities.InvestmentOrder, error) {
+	order := &entities.InvestmentOrder{ID: uuid.New(), UserID: userID, Symbol: symbol, Side: entities.AlpacaOrderSideBuy, Notional: &notional}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func newHarvestLot(userID uuid.UUID, symbol string, qty, price int64, purchasedAt time.Time) *entities.TaxLot {
+	cost := decimal.NewFromInt(qty * price)
+	return &entities.TaxLot{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		Symbol:             symbol,
+		Quantity:           decimal.NewFromInt(qty),
+		RemainingQuantity:  decimal.NewFromInt(qty),
+		CostBasis:          cost,
+		RemainingCostBasis: cost,
+		PurchasedAt:        purchasedAt,
+		AcquiredAt:         purchasedAt,
+	}
+}
+
+func TestTaxLossHarvesting_SellsLossLotsIntoSubstituteAndGuardsWashWindow(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockTaxLotRepository()
+	taxService := tax.NewService(repo, zap.NewNop())
+	placer := &mockHarvestOrderPlacer{}
+	quotes := mockHarvestQuotes{"SPY": decimal.NewFromInt(450)}
+	harvesting := tax.NewHarvestingService(repo, taxService, quotes, placer, tax.DefaultHarvestingConfig(), zap.NewNop())
+	userID := uuid.New()
+
+	enabled := true
+	_, err := taxService.UpdateSettings(ctx, userID, &entities.UpdateTaxLotSettingsRequest{HarvestingEnabled: &enabled})
+	require.NoError(t, err)
+	lot := newHarvestLot(userID, "SPY", 10, 500, time.Now().AddDate(0, -2, 0))
+	repo.lots = append(repo.lots, lot)
+
+	harvests, err := harvesting.HarvestUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, harvests, 1)
+	assert.Equal(t, "500.00", harvests[0].HarvestedLoss.StringFixed(2))
+	assert.Equal(t, entities.TaxLossHarvestStatusCompleted, harvests[0].Status)
+
+	// The sale is pinned to the loss lot and the proceeds buy the substitute
+	require.Len(t, placer.orders, 2)
+	sell, buy := placer.orders[0], placer.orders[1]
+	assert.Equal(t, "SPY", sell.Symbol)
+	assert.True(t, sell.Qty.Equal(decimal.NewFromInt(10)))
+	require.Len(t, repo.selections[sell.ID], 1)
+	assert.Equal(t, lot.ID, repo.selections[sell.ID][0].LotID)
+	assert.Equal(t, "SCHX", buy.Symbol)
+	assert.Equal(t, "4500.00", buy.Notional.StringFixed(2))
+
+	// Buys of the harvested symbol go to the substitute until the window closes
+	substitute, err := taxService.SubstituteFor(ctx, userID, "spy")
+	require.NoError(t, err)
+	assert.Equal(t, "SCHX", substitute)
+	substitute, err = taxService.SubstituteFor(ctx, userID, "QQQ")
+	require.NoError(t, err)
+	assert.Empty(t, substitute)
+
+	// A second run does not harvest the pair again inside the window
+	harvests, err = harvesting.HarvestUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, harvests)
+	assert.Len(t, placer.orders, 2)
+
+	report, err := harvesting.GetHarvestReport(ctx, userID, time.Now().Year())
+	require.NoError(t, err)
+	assert.Equal(t, "500.00", report.HarvestedLoss.StringFixed(2))
+}
+
+func TestTaxLossHarvesting_SkipsSymbolBoughtWithinWashWindow(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockTaxLotRepository()
+	taxService := tax.NewService(repo, zap.NewNop())
+	placer := &mockHarvestOrderPlacer{}
+	quotes := mockHarvestQuotes{"SPY": decimal.NewFromInt(450)}
+	harvesting := tax.NewHarvestingService(repo, taxService, quotes, placer, tax.DefaultHarvestingConfig(), zap.NewNop())
+	userID := uuid.New()
+
+	enabled := true
+	_, err := taxService.UpdateSettings(ctx, userID, &entities.UpdateTaxLotSettingsRequest{HarvestingEnabled: &enabled})
+	require.NoError(t, err)
+	repo.lots = append(repo.lots,
+		newHarvestLot(userID, "SPY", 10, 500, time.Now().AddDate(0, -2, 0)),
+		// A round-up bought shares ten days ago; selling the loss lot now would be a wash sale
+		newHarvestLot(userID, "SPY", 1, 440, time.Now().AddDate(0, 0, -10)),
+	)
+
+	harvests, err := harvesting.HarvestUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, harvests)
+	assert.Empty(t, placer.orders)
+}
+
+func TestTaxLossHarvesting_SelectsLotsBeforeSelling(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockTaxLotRepository()
+	taxService := tax.NewService(repo, zap.NewNop())
+	placer := &mockHarvestOrderPlacer{sellErr: errors.New("market closed")}
+	quotes := mockHarvestQuotes{"SPY": decimal.NewFromInt(450)}
+	harvesting := tax.NewHarvestingService(repo, taxService, quotes, placer, tax.DefaultHarvestingConfig(), zap.NewNop())
+	userID := uuid.New()
+
+	enabled := true
+	_, err := taxService.UpdateSettings(ctx, userID, &entities.UpdateTaxLotSettingsRequest{HarvestingEnabled: &enabled})
+	require.NoError(t, err)
+	lot := newHarvestLot(userID, "SPY", 10, 500, time.Now().AddDate(0, -2, 0))
+	repo.lots = append(repo.lots, lot)
+
+	// The lots are pinned before the sell is attempted, so a failed sell leaves only the selection
+	_, err = harvesting.HarvestUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, repo.selections, 1)
+	for _, selections := range repo.selections {
+		require.Len(t, selections, 1)
+		assert.Equal(t, lot.ID, selections[0].LotID)
+	}
+	assert.Empty(t, placer.orders)
+	require.Len(t, repo.harvests, 1)
+	assert.Equal(t, entities.TaxLossHarvestStatusFailed, repo.harvests[0].Status)
+	assert.Nil(t, repo.harvests[0].SellOrderID)
+}
@@ -0,0 +1,209 @@
+package unit
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rail-service/rail_service/internal/domain/entities"
+	"github.com/rail-service/rail_service/internal/domain/services/tax"
+)
+
+// mockTaxLotRepository keeps lots, gains, fills and harvests in memory
+type mockTaxLotRepository struct {
+	settings   map[uuid.UUID]*entities.TaxLotSettings
+	fills      map[string]*entities.TaxLotFill
+	lots       []*entities.TaxLot
+	gains      []*entities.RealizedGain
+	selections map[uuid.UUID][]*entities.TaxLotSelection
+	harvests   []*entities.TaxLossHarvest
+}
+
+func newMockTaxLotRepository() *mockTaxLotRepository {
+	return &mockTaxLotRepository{
+		settings:   map[uuid.UUID]*entities.TaxLotSettings{},
+		fills:      map[string]*entities.TaxLotFill{},
+		selections: map[uuid.UUID][]*entities.TaxLotSelection{},
+	}
+}
+
+func (m *mockTaxLotRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*entities.TaxLotSettings, error) {
+	return m.settings[userID], nil
+}
+
+func (m *mockTaxLotRepository) UpsertSettings(ctx context.Context, settings *entities.TaxLotSettings) error {
+	m.settings[settings.UserID] = settings
+	return nil
+}
+
+func (m *mockTaxLotRepository) GetFill(ctx context.Context, alpacaOrderID string) (*entities.TaxLotFill, error) {
+	if fill, ok := m.fills[alpacaOrderID]; ok {
+		copied := *fill
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockTaxLotRepository) GetLot(ctx context.Context, id uuid.UUID) (*entities.TaxLot, error) {
+	for _, lot := range m.lots {
+		if lot.ID == id {
+			return lot, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockTaxLotRepository) GetOpenLots(ctx context.Context, userID uuid.UUID, symbol string) ([]*entities.TaxLot, error) {
+	var lots []*entities.TaxLot
+	for _, lot := range m.lots {
+		if lot.UserID == userID && lot.Symbol == symbol && lot.RemainingQuantity.IsPositive() {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool { return lots[i].PurchasedAt.Before(lots[j].PurchasedAt) })
+	return lots, nil
+}
+
+func (m *mockTaxLotRepository) GetOpenLotsByUser(ctx context.Context, userID uuid.UUID) ([]*entities.TaxLot, error) {
+	var lots []*entities.TaxLot
+	for _, lot := range m.lots {
+		if lot.UserID == userID && lot.RemainingQuantity.IsPositive() {
+			lots = append(lots, lot)
+		}
+	}
+	return lots, nil
+}
+
+func (m *mockTaxLotRepository) GetWashSaleCandidates(ctx context.Context, userID uuid.UUID, symbol string, since time.Time) ([]*entities.RealizedGain, error) {
+	var gains []*entities.RealizedGain
+	for _, gain := range m.gains {
+		if gain.UserID == userID && gain.Symbol == symbol && !gain.SoldAt.Before(since) && gain.UnmatchedWashQuantity().IsPositive() {
+			gains = append(gains, gain)
+		}
+	}
+	return gains, nil
+}
+
+func (m *mockTaxLotRepository) GetRealizedGains(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entities.RealizedGain, error) {
+	var gains []*entities.RealizedGain
+	for _, gain := range m.gains {
+		if gain.UserID == userID && !gain.SoldAt.Before(from) && gain.SoldAt.Before(to) {
+			gains = append(gains, gain)
+		}
+	}
+	return gains, nil
+}
+
+func (m *mockTaxLotRepository) ApplyFill(ctx context.Context, changes *entities.TaxLotFillChanges) error {
+	m.lots = append(m.lots, changes.CreatedLots...)
+	m.gains = append(m.gains, changes.CreatedGains...)
+	m.fills[changes.Fill.AlpacaOrderID] = changes.Fill
+	return nil
+}
+
+func (m *mockTaxLotRepository) GetSelections(ctx context.Context, orderID uuid.UUID) ([]*entities.TaxLotSelection, error) {
+	return m.selections[orderID], nil
+}
+
+func (m *mockTaxLotRepository) ReplaceSelections(ctx context.Context, orderID uuid.UUID, selections []*entities.TaxLotSelection) error {
+	m.selections[orderID] = selections
+	return nil
+}
+
+func recordTaxFill(t *testing.T, svc *tax.Service, userID uuid.UUID, orderID string, side entities.AlpacaOrderSide, qty, price int64, at time.Time) *entities.InvestmentOrder {
+	t.Helper()
+	order := &entities.InvestmentOrder{ID: uuid.New(), UserID: userID, Symbol: "AAPL", Side: side}
+	require.NoError(t, svc.RecordFill(context.Background(), order, &entities.AlpacaOrderFillEvent{
+		OrderID:        orderID,
+		Symbol:         "AAPL",
+		Side:           string(side),
+		FilledQty:      decimal.NewFromInt(qty),
+		FilledAvgPrice: decimal.NewFromInt(price),
+		Status:         string(entities.AlpacaOrderStatusFilled),
+		FilledAt:       at,
+	}))
+	return order
+}
+
+func TestTaxLots_RelievesLotsByMethodAndIgnoresReplayedFills(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockTaxLotRepository()
+	svc := tax.NewService(repo, zap.NewNop())
+	userID := uuid.New()
+	start := time.Date(2023, time.March, 1, 15, 0, 0, 0, time.UTC)
+
+	recordTaxFill(t, svc, userID, "buy-1", entities.AlpacaOrderSideBuy, 10, 100, start)
+	recordTaxFill(t, svc, userID, "buy-2", entities.AlpacaOrderSideBuy, 10, 150, start.AddDate(1, 3, 0))
+	// The same cumulative fill delivered twice opens one lot
+	recordTaxFill(t, svc, userID, "buy-2", entities.AlpacaOrderSideBuy, 10, 150, start.AddDate(1, 3, 0))
+	require.Len(t, repo.lots, 2)
+
+	// FIFO relieves the oldest lot, held for more than a year
+	recordTaxFill(t, svc, userID, "sell-1", entities.AlpacaOrderSideSell, 4, 160, start.AddDate(1, 6, 0))
+	require.Len(t, repo.gains, 1)
+	assert.Equal(t, repo.lots[0].ID, repo.gains[0].LotID)
+	assert.Equal(t, entities.HoldingPeriodLongTerm, repo.gains[0].HoldingPeriod)
+	assert.Equal(t, "240.00", repo.gains[0].GainLoss.StringFixed(2))
+
+	// HIFO relieves the most expensive lot, held for less than a year
+	_, err := svc.SetMethod(ctx, userID, entities.TaxLotMethodHIFO)
+	require.NoError(t, err)
+	recordTaxFill(t, svc, userID, "sell-2", entities.AlpacaOrderSideSell, 4, 160, start.AddDate(1, 6, 0))
+	require.Len(t, repo.gains, 2)
+	assert.Equal(t, repo.lots[1].ID, repo.gains[1].LotID)
+	assert.Equal(t, entities.HoldingPeriodShortTerm, repo.gains[1].HoldingPeriod)
+	assert.Equal(t, "40.00", repo.gains[1].GainLoss.StringFixed(2))
+
+	report, err := svc.GenerateRealizedGainsReport(ctx, userID, 2024)
+	require.NoError(t, err)
+	assert.Equal(t, "240.00", report.LongTermGainLoss.StringFixed(2))
+	assert.Equal(t, "40.00", report.ShortTermGainLoss.StringFixed(2))
+	assert.Equal(t, "280.00", report.TotalGains.StringFixed(2))
+
+	csv, err := svc.ExportRealizedGainsReport(report, entities.TaxReportFormatCSV)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "AAPL,4,2023-03-01,2024-09-01,640.00,400.00,0.00,240.00,long_term", lines[1])
+}
+
+func TestTaxLots_WashSaleDisallowsLossIntoReplacementLot(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockTaxLotRepository()
+	svc := tax.NewService(repo, zap.NewNop())
+	userID := uuid.New()
+	bought := time.Date(2024, time.January, 10, 15, 0, 0, 0, time.UTC)
+	sold := bought.AddDate(0, 2, 0)
+
+	recordTaxFill(t, svc, userID, "buy-1", entities.AlpacaOrderSideBuy, 10, 100, bought)
+	recordTaxFill(t, svc, userID, "sell-1", entities.AlpacaOrderSideSell, 10, 80, sold)
+	require.Len(t, repo.gains, 1)
+	assert.Equal(t, "-200.00", repo.gains[0].GainLoss.StringFixed(2))
+
+	// Buying 4 shares back within 30 days disallows the loss on 4 of the 10 sold shares
+	recordTaxFill(t, svc, userID, "buy-2", entities.AlpacaOrderSideBuy, 4, 85, sold.AddDate(0, 0, 10))
+	loss := repo.gains[0]
+	assert.Equal(t, "80.00", loss.DisallowedLoss.StringFixed(2))
+	assert.True(t, loss.WashSaleQuantity.Equal(decimal.NewFromInt(4)))
+
+	replacement := repo.lots[1]
+	assert.True(t, replacement.IsReplacement)
+	assert.Equal(t, "420.00", replacement.CostBasis.StringFixed(2))
+	assert.Equal(t, "80.00", replacement.WashSaleAdjustment.StringFixed(2))
+	// The sold shares' 2 month holding period carries over to the replacement
+	assert.True(t, replacement.AcquiredAt.Equal(replacement.PurchasedAt.Add(-sold.Sub(bought))))
+
+	report, err := svc.GenerateRealizedGainsReport(ctx, userID, 2024)
+	require.NoError(t, err)
+	assert.Equal(t, "-120.00", report.ShortTermGainLoss.StringFixed(2))
+	assert.Equal(t, "120.00", report.TotalLosses.StringFixed(2))
+	assert.Equal(t, "80.00", report.WashSaleDisallowed.StringFixed(2))
+}